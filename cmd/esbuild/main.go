@@ -34,13 +34,14 @@ var helpText = func(colors logger.Colors) string {
   --bundle              Bundle all dependencies into the output files
   --define:K=V          Substitute K with V while parsing
   --external:M          Exclude module M from the bundle (can use * wildcards)
-  --format=...          Output format (iife | cjs | esm, no default when not
-                        bundling, otherwise default is iife when platform
-                        is browser and cjs when platform is node)
+  --format=...          Output format (iife | cjs | esm | amd | umd | system,
+                        no default when not bundling, otherwise default is
+                        iife when platform is browser and cjs when platform
+                        is node)
   --loader:X=L          Use loader L to load file extension X, where L is
                         one of: base64 | binary | copy | css | dataurl |
-                        empty | file | global-css | js | json | jsx |
-                        local-css | text | ts | tsx
+                        empty | file | global-css | html | js | json |
+                        jsx | local-css | text | ts | tsx
   --minify              Minify the output (sets all --minify-* flags)
   --outdir=...          The output directory (for multiple entry points)
   --outfile=...         The output file (for one entry point)
@@ -49,7 +50,7 @@ var helpText = func(colors logger.Colors) string {
                         default browser)
   --serve=...           Start a local HTTP server on this host:port for outputs
   --sourcemap           Emit a source map
-  --splitting           Enable code splitting (currently only for esm)
+  --splitting           Enable code splitting
   --target=...          Environment target (e.g. es2017, chrome58, firefox57,
                         safari11, edge16, node10, ie9, opera45, default esnext)
   --watch               Watch mode: rebuild on file system changes (stops when
@@ -58,25 +59,38 @@ var helpText = func(colors logger.Colors) string {
 ` + colors.Bold + `Advanced options:` + colors.Reset + `
   --allow-overwrite         Allow output files to overwrite input files
   --analyze                 Print a report about the contents of the bundle
-                            (use "--analyze=verbose" for a detailed report)
+                            (use "--analyze=verbose" for a detailed report or
+                            "--analyze=why:F" to explain why file F is included)
   --asset-names=...         Path template to use for "file" loader files
                             (default "[name]-[hash]")
   --banner:T=...            Text to be prepended to each output file of type T
                             where T is one of: css | js
+  --cache-dir=...           Persist parsed files in this directory to speed up
+                            later builds (shared between esbuild processes)
   --certfile=...            Certificate for serving HTTPS (see also "--keyfile")
   --charset=utf8            Do not escape UTF-8 code points
   --chunk-names=...         Path template to use for code splitting chunks
                             (default "[name]-[hash]")
   --color=...               Force use of color terminal escapes (true | false)
+  --compare-metafiles A B   Print how the bundle changed between metafiles A
+                            and B (use "--json" for machine-readable output)
+  --declaration             Generate a ".d.ts" file next to each TypeScript
+                            output file (requires isolatedDeclarations syntax)
+  --dedupe                  Bundle only one copy of each package when the
+                            same version is installed in multiple places
   --drop:...                Remove certain constructs (console | debugger)
   --drop-labels=...         Remove labeled statements with these label names
   --entry-names=...         Path template to use for entry point output paths
                             (default "[dir]/[name]", can also use "[hash]")
   --footer:T=...            Text to be appended to each output file of type T
                             where T is one of: css | js
-  --global-name=...         The name of the global for the IIFE format
+  --global-name=...         The name of the global for the IIFE and UMD formats
+  --hmr                     Replace changed modules in the page instead of
+                            reloading it (use with "--serve" and "--watch")
   --ignore-annotations      Enable this to work with packages that have
                             incorrect tree-shaking annotations
+  --import-map=...          Use this import map file (or inline JSON) to
+                            remap imports before they are resolved
   --inject:F                Import the file F into all input files and
                             automatically replace matching globals with imports
   --jsx-dev                 Use React's automatic runtime in development mode
@@ -97,20 +111,30 @@ var helpText = func(colors logger.Colors) string {
                             error | silent, default info)
   --log-limit=...           Maximum message count or 0 to disable (default 6)
   --log-override:X=Y        Use log level Y for log messages with identifier X
+  --loose-for-of            Assume for-of loops only iterate over arrays when
+                            lowering them for old targets
   --main-fields=...         Override the main file order in package.json
                             (default "browser,module,main" when platform is
                             browser and "main,module" when platform is node)
   --mangle-cache=...        Save "mangle props" decisions to a JSON file
   --mangle-props=...        Rename all properties matching a regular expression
   --mangle-quoted=...       Enable renaming of quoted properties (true | false)
+  --manual-chunk:N=P        Put files matching the path pattern P in the code
+                            splitting chunk named N (e.g. "vendor=node_modules/*")
   --metafile=...            Write metadata about the build to a JSON file
                             (see also: ` + colors.Underline + `https://esbuild.github.io/analyze/` + colors.Reset + `)
+  --metafile-why            Record why each file was included in the metafile
+                            (the import chain and the exports that are used)
+  --min-chunk-size=...      Merge shared code splitting chunks smaller than
+                            this many bytes into other chunks when possible
   --minify-whitespace       Remove whitespace in output files
   --minify-identifiers      Shorten identifiers in output files
   --minify-syntax           Use equivalent but shorter syntax in output files
   --out-extension:.js=.mjs  Use a custom output extension instead of ".js"
   --outbase=...             The base path used to determine entry point output
                             paths (for multiple entry points)
+  --preserve-modules        Generate one output file per input module instead
+                            of bundling modules together (requires --outdir)
   --preserve-symlinks       Disable symlink resolution for module lookup
   --public-path=...         Set the base URL for the "file" loader
   --pure:N                  Mark the name N as a pure function for tree shaking
@@ -125,9 +149,17 @@ var helpText = func(colors logger.Colors) string {
   --sourcemap=inline        Emit the source map with an inline data URL
   --sources-content=false   Omit "sourcesContent" in generated source maps
   --supported:F=...         Consider syntax F to be supported (true | false)
+  --tree-shake-commonjs     Convert statically-analyzable CommonJS modules to
+                            ESM so their unused exports can be removed
   --tree-shaking=...        Force tree shaking on or off (false | true)
   --tsconfig=...            Use this tsconfig.json file instead of other ones
+  --umd-global:M=N          Read the external module M from the global variable
+                            N in the global fallback of the UMD format
+  --vendor-dir=...          Directory for "node_modules" files when using
+                            --preserve-modules (default "vendor")
   --version                 Print the current version (` + esbuildVersion + `) and exit
+  --watch-mode=...          How watch mode detects changes (native | poll,
+                            default is native where supported)
 
 ` + colors.Bold + `Examples:` + colors.Reset + `
   ` + colors.Dim + `# Produces dist/entry_point.js and dist/entry_point.js.map` + colors.Reset + `
@@ -148,6 +180,9 @@ var helpText = func(colors logger.Colors) string {
   ` + colors.Dim + `# Start a local HTTP server for everything in "www"` + colors.Reset + `
   esbuild app.ts --bundle --servedir=www --outdir=www/js
 
+  ` + colors.Dim + `# Compare the metafiles from two builds to find size changes` + colors.Reset + `
+  esbuild --compare-metafiles before.json after.json
+
 `
 }
 
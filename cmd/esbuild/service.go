@@ -334,7 +334,19 @@ func (service *serviceType) handleIncomingPacket(bytes []byte) {
 				go func() {
 					defer service.keepAliveWaitGroup.Done()
 					defer build.disposeWaitGroup.Done()
-					if err := ctx.Watch(api.WatchOptions{}); err != nil {
+					var options api.WatchOptions
+					if value, ok := request["mode"]; ok {
+						switch value.(string) {
+						case "native":
+							options.Mode = api.WatchModeNative
+						case "poll":
+							options.Mode = api.WatchModePoll
+						default:
+							service.sendPacket(encodeErrorPacket(p.id, fmt.Errorf("Invalid watch mode: %q", value)))
+							return
+						}
+					}
+					if err := ctx.Watch(options); err != nil {
 						service.sendPacket(encodeErrorPacket(p.id, err))
 					} else {
 						service.sendPacket(encodePacket(packet{
@@ -785,6 +797,10 @@ func resolveKindToString(kind api.ResolveKind) string {
 		return "dynamic-import"
 	case api.ResolveJSRequireResolve:
 		return "require-resolve"
+	case api.ResolveJSNewWorker:
+		return "new-worker"
+	case api.ResolveJSNewURL:
+		return "new-url"
 
 	// CSS
 	case api.ResolveCSSImportRule:
@@ -794,6 +810,10 @@ func resolveKindToString(kind api.ResolveKind) string {
 	case api.ResolveCSSURLToken:
 		return "url-token"
 
+	// HTML
+	case api.ResolveHTMLAttribute:
+		return "html-attribute"
+
 	default:
 		panic("Internal error")
 	}
@@ -813,6 +833,10 @@ func stringToResolveKind(kind string) (api.ResolveKind, bool) {
 		return api.ResolveJSDynamicImport, true
 	case "require-resolve":
 		return api.ResolveJSRequireResolve, true
+	case "new-worker":
+		return api.ResolveJSNewWorker, true
+	case "new-url":
+		return api.ResolveJSNewURL, true
 
 	// CSS
 	case "import-rule":
@@ -821,6 +845,10 @@ func stringToResolveKind(kind string) (api.ResolveKind, bool) {
 		return api.ResolveCSSComposesFrom, true
 	case "url-token":
 		return api.ResolveCSSURLToken, true
+
+	// HTML
+	case "html-attribute":
+		return api.ResolveHTMLAttribute, true
 	}
 
 	return api.ResolveNone, false
@@ -1236,6 +1264,9 @@ func (service *serviceType) handleAnalyzeMetafileRequest(id uint32, request map[
 	if value, ok := request["verbose"].(bool); ok {
 		options.Verbose = value
 	}
+	if value, ok := request["why"].(string); ok {
+		options.Why = value
+	}
 
 	result := api.AnalyzeMetafile(metafile, options)
 
@@ -37,6 +37,15 @@ const (
 
 	// A CSS "url(...)" token
 	ImportURL
+
+	// A URL in an HTML attribute such as "<script src>" or "<img src>"
+	ImportHTMLAttribute
+
+	// A "new Worker(new URL('path', import.meta.url))" expression
+	ImportWorker
+
+	// A "new URL('path', import.meta.url)" expression
+	ImportNewURL
 )
 
 func (kind ImportKind) StringForMetafile() string {
@@ -55,6 +64,12 @@ func (kind ImportKind) StringForMetafile() string {
 		return "composes-from"
 	case ImportURL:
 		return "url-token"
+	case ImportHTMLAttribute:
+		return "html-attribute"
+	case ImportWorker:
+		return "new-worker"
+	case ImportNewURL:
+		return "new-url"
 	case ImportEntryPoint:
 		return "entry-point"
 	default:
@@ -142,6 +157,9 @@ const (
 	// Unique keys are randomly-generated strings that are used to replace paths
 	// in the source code after it's printed. These must not ever be split apart.
 	ContainsUniqueKey
+
+	// If true, this worker was created with "{ type: 'module' }"
+	IsModuleWorker
 )
 
 func (flags ImportRecordFlags) Has(flag ImportRecordFlags) bool {
@@ -29,6 +29,9 @@ import (
 	"github.com/evanw/esbuild/internal/fs"
 	"github.com/evanw/esbuild/internal/graph"
 	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/html_ast"
+	"github.com/evanw/esbuild/internal/html_parser"
+	"github.com/evanw/esbuild/internal/html_printer"
 	"github.com/evanw/esbuild/internal/js_ast"
 	"github.com/evanw/esbuild/internal/js_lexer"
 	"github.com/evanw/esbuild/internal/js_parser"
@@ -76,6 +79,14 @@ type Bundle struct {
 	files       []scannerFile
 	entryPoints []graph.EntryPoint
 	options     config.Options
+
+	// Files referenced using "new Worker(new URL(...))" are linked separately
+	// from the other entry points. This maps each source index to whether it
+	// was created with "{ type: 'module' }" or not.
+	workers map[uint32]bool
+
+	// This is the "duplicatePackages" object in the metafile, if present
+	duplicatePackagesJSON string
 }
 
 type parseArgs struct {
@@ -96,6 +107,10 @@ type parseArgs struct {
 	importPathRange logger.Range
 	sourceIndex     uint32
 	skipResolve     bool
+
+	// Files referenced with "new URL()" use the "file" loader if there is no
+	// loader configured for them
+	isAsset bool
 }
 
 type parseResult struct {
@@ -173,6 +188,9 @@ func parseFile(args parseArgs) {
 	// The special "default" loader determines the loader from the file path
 	if loader == config.LoaderDefault {
 		loader = loaderFromFileExtension(args.options.ExtensionToLoader, base+ext)
+		if loader == config.LoaderNone && args.isAsset {
+			loader = config.LoaderFile
+		}
 	}
 
 	if loader == config.LoaderEmpty {
@@ -243,6 +261,11 @@ func parseFile(args parseArgs) {
 		result.file.inputFile.Repr = &graph.CSSRepr{AST: ast}
 		result.ok = true
 
+	case config.LoaderHTML:
+		ast := html_parser.Parse(source)
+		result.file.inputFile.Repr = &graph.HTMLRepr{AST: ast}
+		result.ok = true
+
 	case config.LoaderJSON, config.LoaderWithTypeJSON:
 		expr, ok := args.caches.JSONCache.Parse(args.log, source, js_parser.JSONOptions{
 			UnsupportedJSFeatures: args.options.UnsupportedJSFeatures,
@@ -1355,7 +1378,11 @@ func ScanBundle(
 		return Bundle{options: options}
 	}
 
-	files := s.processScannedFiles(entryPointMeta)
+	dedupedSourceIndices, duplicatePackagesJSON := s.dedupePackages()
+	files := s.processScannedFiles(entryPointMeta, dedupedSourceIndices)
+	entryPointMeta = s.addEntryPointsFromHTML(files, entryPointMeta)
+	entryPointMeta = s.addEntryPointsForPreservedModules(files, entryPointMeta)
+	workers := s.findWorkers(files)
 
 	if options.CancelFlag.DidCancel() {
 		return Bundle{options: options}
@@ -1368,6 +1395,9 @@ func ScanBundle(
 		entryPoints:     entryPointMeta,
 		uniqueKeyPrefix: uniqueKeyPrefix,
 		options:         s.options,
+		workers:         workers,
+
+		duplicatePackagesJSON: duplicatePackagesJSON,
 	}
 }
 
@@ -1377,6 +1407,7 @@ const (
 	inputKindNormal inputKind = iota
 	inputKindEntryPoint
 	inputKindStdin
+	inputKindAsset
 )
 
 // This returns the source index of the resulting file
@@ -1440,6 +1471,12 @@ func (s *scanner) maybeParseFile(
 		optionsClone.ModuleTypeData.Type = js_ast.ModuleUnknown
 	}
 
+	// Don't convert the CommonJS exports of entry points to ESM since whoever
+	// loads the output file can observe the exports object
+	if kind == inputKindEntryPoint || kind == inputKindStdin {
+		optionsClone.TreeShakeCommonJS = false
+	}
+
 	// Enable bundling for injected files so we always do tree shaking. We
 	// never want to include unnecessary code from injected files since they
 	// are essentially bundled. However, if we do this we should skip the
@@ -1490,6 +1527,7 @@ func (s *scanner) maybeParseFile(
 		inject:          inject,
 		skipResolve:     skipResolve,
 		uniqueKeyPrefix: s.uniqueKeyPrefix,
+		isAsset:         kind == inputKindAsset,
 	})
 
 	return visited.sourceIndex
@@ -2033,8 +2071,12 @@ func (s *scanner) scanAllDependencies() {
 				path := resolveResult.PathPair.Primary
 				if !resolveResult.PathPair.IsExternal {
 					// Handle a path within the bundle
+					kind := inputKindNormal
+					if record.Kind == ast.ImportNewURL {
+						kind = inputKindAsset
+					}
 					sourceIndex := s.maybeParseFile(*resolveResult, resolver.PrettyPath(s.fs, path),
-						&result.file.inputFile.Source, record.Range, with, inputKindNormal, nil)
+						&result.file.inputFile.Source, record.Range, with, kind, nil)
 					record.SourceIndex = ast.MakeIndex32(sourceIndex)
 				} else {
 					// Allow this import statement to be removed if something marked it as "sideEffects: false"
@@ -2173,7 +2215,134 @@ func (s *scanner) generateResultForGlobResolve(
 	}
 }
 
-func (s *scanner) processScannedFiles(entryPointMeta []graph.EntryPoint) []scannerFile {
+// When the same version of a package is installed in multiple "node_modules"
+// directories, each copy is resolved to a different path and ends up in the
+// bundle separately. This is both wasteful and can break packages that rely
+// on module-level state (e.g. React contexts). With the "dedupe" option, we
+// redirect imports of files in every copy to the same file in a single copy.
+// The copy closest to the file system root is picked (typically the hoisted
+// one) so that the choice is deterministic.
+//
+// This returns a map from each redirected source index to its replacement,
+// along with the metafile JSON for packages included with multiple versions.
+func (s *scanner) dedupePackages() (map[uint32]uint32, string) {
+	if !s.options.DedupePackages || s.options.Mode != config.ModeBundle {
+		return nil, ""
+	}
+
+	s.timer.Begin("Dedupe packages")
+	defer s.timer.End("Dedupe packages")
+
+	type nameAndVersion struct {
+		name    string
+		version string
+	}
+
+	// Group the scanned files by package and then by package directory
+	dirsByPackage := make(map[nameAndVersion]map[string][]uint32)
+	for sourceIndex, result := range s.results {
+		if !result.ok {
+			continue
+		}
+		keyPath := result.file.inputFile.Source.KeyPath
+		if keyPath.Namespace != "file" || keyPath.IsDisabled() {
+			continue
+		}
+		if pkg, ok := s.res.NodeModulesPackageForPath(keyPath.Text); ok {
+			key := nameAndVersion{name: pkg.Name, version: pkg.Version}
+			dirs := dirsByPackage[key]
+			if dirs == nil {
+				dirs = make(map[string][]uint32)
+				dirsByPackage[key] = dirs
+			}
+			dirs[pkg.AbsDir] = append(dirs[pkg.AbsDir], uint32(sourceIndex))
+		}
+	}
+
+	redirects := make(map[uint32]uint32)
+	versionsByName := make(map[string]map[string]string)
+	for key, dirs := range dirsByPackage {
+		sortedDirs := make([]string, 0, len(dirs))
+		for dir := range dirs {
+			sortedDirs = append(sortedDirs, dir)
+		}
+		sort.Slice(sortedDirs, func(i int, j int) bool {
+			a, b := sortedDirs[i], sortedDirs[j]
+			if depthA, depthB := strings.Count(a, "/")+strings.Count(a, "\\"), strings.Count(b, "/")+strings.Count(b, "\\"); depthA != depthB {
+				return depthA < depthB
+			}
+			return a < b
+		})
+		canonicalDir := sortedDirs[0]
+
+		// Redirect files in other copies to the same file in the canonical copy.
+		// This only works if that file was also scanned. If not, then the file
+		// in the other copy is left alone.
+		for _, dir := range sortedDirs[1:] {
+			for _, sourceIndex := range dirs[dir] {
+				keyPath := s.results[sourceIndex].file.inputFile.Source.KeyPath
+				if rel, ok := s.fs.Rel(dir, keyPath.Text); ok {
+					keyPath.Text = canonicalFileSystemPathForWindows(s.fs.Join(canonicalDir, rel))
+					if visited, ok := s.visited[keyPath]; ok {
+						redirects[sourceIndex] = visited.sourceIndex
+					}
+				}
+			}
+		}
+
+		versions := versionsByName[key.name]
+		if versions == nil {
+			versions = make(map[string]string)
+			versionsByName[key.name] = versions
+		}
+		versions[key.version] = resolver.PrettyPath(s.fs, logger.Path{Text: canonicalDir, Namespace: "file"})
+	}
+
+	// Report packages that are included with more than one version, since
+	// those can't be deduplicated
+	var names []string
+	for name, versions := range versionsByName {
+		if len(versions) > 1 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	sb := strings.Builder{}
+	for i, name := range names {
+		versions := versionsByName[name]
+		sortedVersions := make([]string, 0, len(versions))
+		for version := range versions {
+			sortedVersions = append(sortedVersions, version)
+		}
+		sort.Strings(sortedVersions)
+
+		notes := make([]logger.MsgData, 0, len(sortedVersions))
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(fmt.Sprintf("\n    %s: {", helpers.QuoteForJSON(name, s.options.ASCIIOnly)))
+		for j, version := range sortedVersions {
+			notes = append(notes, logger.MsgData{Text: fmt.Sprintf("Version %q is in %q", version, versions[version])})
+			if j > 0 {
+				sb.WriteString(",")
+			}
+			sb.WriteString(fmt.Sprintf("\n      %s: %s",
+				helpers.QuoteForJSON(version, s.options.ASCIIOnly),
+				helpers.QuoteForJSON(versions[version], s.options.ASCIIOnly)))
+		}
+		sb.WriteString("\n    }")
+		s.log.AddIDWithNotes(logger.MsgID_Bundler_DuplicatePackage, logger.Warning, nil, logger.Range{},
+			fmt.Sprintf("Multiple versions of the package %q were found", name), notes)
+	}
+
+	var duplicatePackagesJSON string
+	if s.options.NeedsMetafile && len(names) > 0 {
+		duplicatePackagesJSON = fmt.Sprintf("{%s\n  }", sb.String())
+	}
+	return redirects, duplicatePackagesJSON
+}
+
+func (s *scanner) processScannedFiles(entryPointMeta []graph.EntryPoint, dedupedSourceIndices map[uint32]uint32) []scannerFile {
 	s.timer.Begin("Process scanned files")
 	defer s.timer.End("Process scanned files")
 
@@ -2308,6 +2477,11 @@ func (s *scanner) processScannedFiles(entryPointMeta []graph.EntryPoint) []scann
 					}
 				}
 
+				// Use the same copy of a package everywhere if "dedupe" is enabled
+				if otherSourceIndex, ok := dedupedSourceIndices[record.SourceIndex.GetIndex()]; ok {
+					record.SourceIndex = ast.MakeIndex32(otherSourceIndex)
+				}
+
 				// Generate metadata about each import
 				otherResult := &s.results[record.SourceIndex.GetIndex()]
 				otherFile := &otherResult.file
@@ -2341,7 +2515,52 @@ func (s *scanner) processScannedFiles(entryPointMeta []graph.EntryPoint) []scann
 							{Text: "You need to either reconfigure esbuild to ensure that the loader for this file is \"json\" or you need to remove this import assertion."}})
 				}
 
+				// HTML files can only be entry points since there's nothing that the
+				// importing file could do with them
+				if _, ok := otherFile.inputFile.Repr.(*graph.HTMLRepr); ok {
+					s.log.AddErrorWithNotes(&tracker, record.Range,
+						fmt.Sprintf("Cannot import %q", otherFile.inputFile.Source.PrettyPath),
+						[]logger.MsgData{{Text: fmt.Sprintf(
+							"HTML files can only be used as entry points, and %q is an HTML file (it was loaded with the %q loader).",
+							otherFile.inputFile.Source.PrettyPath, config.LoaderToString[otherFile.inputFile.Loader])}})
+					continue
+				}
+
 				switch record.Kind {
+				case ast.ImportHTMLAttribute:
+					s.validateHTMLAttribute(result.file.inputFile.Repr.(*graph.HTMLRepr).AST.URLs[importRecordIndex], record, &tracker, &otherFile.inputFile)
+
+				case ast.ImportNewURL:
+					// Using a file with "new URL()" requires a loader that generates a URL
+					var loaderHasURL bool
+					switch otherRepr := otherFile.inputFile.Repr.(type) {
+					case *graph.JSRepr:
+						loaderHasURL = otherRepr.AST.URLForCSS != ""
+					case *graph.CopyRepr:
+						loaderHasURL = true
+					}
+					if !loaderHasURL {
+						s.log.AddErrorWithNotes(&tracker, record.Range,
+							fmt.Sprintf("Cannot use %q as a URL", otherFile.inputFile.Source.PrettyPath),
+							[]logger.MsgData{{Text: fmt.Sprintf(
+								"You can't use \"new URL()\" to reference the file %q because it was loaded with the %q loader, which doesn't provide a URL to embed in the resulting JavaScript.",
+								otherFile.inputFile.Source.PrettyPath, config.LoaderToString[otherFile.inputFile.Loader])}})
+						record.SourceIndex = ast.Index32{}
+						continue
+					}
+
+				case ast.ImportWorker:
+					// Workers are bundled as separate entry points, so they must be code
+					if otherRepr, ok := otherFile.inputFile.Repr.(*graph.JSRepr); !ok || otherRepr.AST.HasLazyExport {
+						s.log.AddErrorWithNotes(&tracker, record.Range,
+							fmt.Sprintf("Cannot use %q as a worker", otherFile.inputFile.Source.PrettyPath),
+							[]logger.MsgData{{Text: fmt.Sprintf(
+								"Only JavaScript and TypeScript files can be used as workers, and %q was loaded with the %q loader.",
+								otherFile.inputFile.Source.PrettyPath, config.LoaderToString[otherFile.inputFile.Loader])}})
+						record.SourceIndex = ast.Index32{}
+						continue
+					}
+
 				case ast.ImportComposesFrom:
 					// Using a JavaScript file with CSS "composes" is not allowed
 					if _, ok := otherFile.inputFile.Repr.(*graph.JSRepr); ok && otherFile.inputFile.Loader != config.LoaderEmpty {
@@ -2607,6 +2826,216 @@ func (s *scanner) processScannedFiles(entryPointMeta []graph.EntryPoint) []scann
 	return files
 }
 
+func (s *scanner) validateHTMLAttribute(url html_ast.URL, record *ast.ImportRecord, tracker *logger.LineColumnTracker, otherFile *graph.InputFile) {
+	switch url.Kind {
+	case html_ast.URLScript:
+		// Using a CSS file or a copied file with "<script src>" is not allowed
+		switch otherFile.Repr.(type) {
+		case *graph.CSSRepr, *graph.CopyRepr:
+			s.log.AddErrorWithNotes(tracker, record.Range,
+				fmt.Sprintf("Cannot use %q as a script", otherFile.Source.PrettyPath),
+				[]logger.MsgData{{Text: fmt.Sprintf(
+					"A \"<script>\" tag can only be used to reference a JavaScript file and %q is not a JavaScript file (it was loaded with the %q loader).",
+					otherFile.Source.PrettyPath, config.LoaderToString[otherFile.Loader])}})
+		}
+
+	case html_ast.URLStylesheet:
+		// Using anything other than a CSS file with "<link rel=stylesheet>" is not allowed
+		if _, ok := otherFile.Repr.(*graph.CSSRepr); !ok {
+			s.log.AddErrorWithNotes(tracker, record.Range,
+				fmt.Sprintf("Cannot use %q as a stylesheet", otherFile.Source.PrettyPath),
+				[]logger.MsgData{{Text: fmt.Sprintf(
+					"A \"<link rel=stylesheet>\" tag can only be used to reference a CSS file and %q is not a CSS file (it was loaded with the %q loader).",
+					otherFile.Source.PrettyPath, config.LoaderToString[otherFile.Loader])}})
+		}
+
+	case html_ast.URLAsset:
+		// Using a JavaScript or CSS file as an asset is not allowed
+		switch otherRepr := otherFile.Repr.(type) {
+		case *graph.CSSRepr:
+			s.log.AddErrorWithNotes(tracker, record.Range,
+				fmt.Sprintf("Cannot use %q as a URL", otherFile.Source.PrettyPath),
+				[]logger.MsgData{{Text: fmt.Sprintf(
+					"You can only use \"<link rel=stylesheet>\" to reference a CSS file from HTML, and %q is a CSS file (it was loaded with the %q loader).",
+					otherFile.Source.PrettyPath, config.LoaderToString[otherFile.Loader])}})
+
+		case *graph.JSRepr:
+			if otherRepr.AST.URLForCSS == "" {
+				s.log.AddErrorWithNotes(tracker, record.Range,
+					fmt.Sprintf("Cannot use %q as a URL", otherFile.Source.PrettyPath),
+					[]logger.MsgData{{Text: fmt.Sprintf(
+						"You can't reference the file %q from an HTML attribute because it was loaded with the %q loader, which doesn't provide a URL to embed in the resulting HTML.",
+						otherFile.Source.PrettyPath, config.LoaderToString[otherFile.Loader])}})
+			}
+		}
+	}
+}
+
+// Scripts and stylesheets that are referenced by HTML entry points are
+// bundled as additional entry points. Their output paths are derived from
+// their input paths in the same way as for all other entry points.
+func (s *scanner) addEntryPointsFromHTML(files []scannerFile, entryPointMeta []graph.EntryPoint) []graph.EntryPoint {
+	if s.options.Mode != config.ModeBundle {
+		return entryPointMeta
+	}
+
+	isEntryPoint := make(map[uint32]bool, len(entryPointMeta))
+	for _, entryPoint := range entryPointMeta {
+		isEntryPoint[entryPoint.SourceIndex] = true
+	}
+
+	for _, entryPoint := range entryPointMeta {
+		file := &files[entryPoint.SourceIndex].inputFile
+		repr, ok := file.Repr.(*graph.HTMLRepr)
+		if !ok {
+			continue
+		}
+
+		// The HTML file needs to be written alongside the files it references
+		if s.options.AbsOutputFile != "" || s.options.WriteToStdout {
+			s.log.AddError(nil, logger.Range{},
+				fmt.Sprintf("Must use \"outdir\" when bundling the HTML file %q", file.Source.PrettyPath))
+			continue
+		}
+
+		for i, url := range repr.AST.URLs {
+			if url.Kind == html_ast.URLScript || url.Kind == html_ast.URLStylesheet {
+				if record := &repr.AST.ImportRecords[i]; record.SourceIndex.IsValid() && !isEntryPoint[record.SourceIndex.GetIndex()] {
+					isEntryPoint[record.SourceIndex.GetIndex()] = true
+					entryPointMeta = append(entryPointMeta, graph.EntryPoint{SourceIndex: record.SourceIndex.GetIndex()})
+				}
+			}
+		}
+	}
+
+	return entryPointMeta
+}
+
+// With "preserveModules", every JavaScript module reachable from an entry point
+// is turned into an entry point of its own. Code splitting then generates one
+// output file per module with imports between them rewritten to point to the
+// other output files. Files inside "node_modules" are moved into the vendor
+// directory because they typically aren't inside "outbase" and because "npm
+// publish" omits directories named "node_modules".
+func (s *scanner) addEntryPointsForPreservedModules(files []scannerFile, entryPointMeta []graph.EntryPoint) []graph.EntryPoint {
+	if !s.options.PreserveModules || s.options.Mode != config.ModeBundle {
+		return entryPointMeta
+	}
+
+	isEntryPoint := make(map[uint32]bool, len(entryPointMeta))
+	for _, entryPoint := range entryPointMeta {
+		isEntryPoint[entryPoint.SourceIndex] = true
+	}
+
+	visited := make(map[uint32]bool)
+	var visit func(sourceIndex uint32)
+	visit = func(sourceIndex uint32) {
+		if visited[sourceIndex] {
+			return
+		}
+		visited[sourceIndex] = true
+		repr, ok := files[sourceIndex].inputFile.Repr.(*graph.JSRepr)
+		if !ok {
+			return
+		}
+		for _, record := range repr.AST.ImportRecords {
+			if !record.SourceIndex.IsValid() || (record.Kind != ast.ImportStmt &&
+				record.Kind != ast.ImportRequire && record.Kind != ast.ImportDynamic) {
+				continue
+			}
+			otherSourceIndex := record.SourceIndex.GetIndex()
+			otherFile := &files[otherSourceIndex].inputFile
+
+			// Skip the JavaScript stubs for CSS files. The CSS is included in the
+			// CSS output file for the JavaScript module that imports it instead.
+			if otherRepr, ok := otherFile.Repr.(*graph.JSRepr); !ok || otherRepr.CSSSourceIndex.IsValid() {
+				continue
+			}
+
+			if !isEntryPoint[otherSourceIndex] {
+				isEntryPoint[otherSourceIndex] = true
+				entryPointMeta = append(entryPointMeta, graph.EntryPoint{
+					OutputPath:  s.preservedModuleOutputPath(otherFile),
+					SourceIndex: otherSourceIndex,
+				})
+			}
+			visit(otherSourceIndex)
+		}
+	}
+
+	for i, n := 0, len(entryPointMeta); i < n; i++ {
+		visit(entryPointMeta[i].SourceIndex)
+	}
+
+	return entryPointMeta
+}
+
+// Returns the output path for a file inside "node_modules" relative to
+// "outbase" without the file extension, or an empty string to derive the
+// output path from the input path as usual.
+func (s *scanner) preservedModuleOutputPath(inputFile *graph.InputFile) string {
+	keyPath := inputFile.Source.KeyPath
+	if keyPath.Namespace != "file" || !helpers.IsInsideNodeModules(keyPath.Text) {
+		return ""
+	}
+
+	// Use the path inside the innermost "node_modules" directory so that the
+	// package name is the first path component
+	path := strings.ReplaceAll(keyPath.Text, "\\", "/")
+	path = path[strings.LastIndex(path, "/node_modules/")+len("/node_modules/"):]
+	if ext := s.fs.Ext(path); ext != "" {
+		path = path[:len(path)-len(ext)]
+	}
+	return s.options.VendorDir + "/" + path
+}
+
+// Files referenced using "new Worker(new URL(...))" are bundled separately,
+// one per worker, since each worker runs in its own global scope. Module
+// workers are bundled in the "esm" format while classic workers are bundled
+// in the "iife" format since classic workers can't use "import" statements.
+func (s *scanner) findWorkers(files []scannerFile) map[uint32]bool {
+	if s.options.Mode != config.ModeBundle {
+		return nil
+	}
+
+	var workers map[uint32]bool
+	for sourceIndex := range files {
+		file := &files[sourceIndex].inputFile
+		repr, ok := file.Repr.(*graph.JSRepr)
+		if !ok {
+			continue
+		}
+
+		for _, record := range repr.AST.ImportRecords {
+			if record.Kind != ast.ImportWorker || !record.SourceIndex.IsValid() {
+				continue
+			}
+
+			// Worker output files need to be written alongside the code that uses them
+			tracker := logger.MakeLineColumnTracker(&file.Source)
+			if s.options.AbsOutputFile != "" || s.options.WriteToStdout {
+				s.log.AddError(&tracker, record.Range, "Must use \"outdir\" when bundling workers")
+				continue
+			}
+
+			isModule := record.Flags.Has(ast.IsModuleWorker)
+			otherSourceIndex := record.SourceIndex.GetIndex()
+			if workers == nil {
+				workers = make(map[uint32]bool)
+			}
+			if wasModule, ok := workers[otherSourceIndex]; !ok {
+				workers[otherSourceIndex] = isModule
+			} else if wasModule != isModule {
+				s.log.AddError(&tracker, record.Range,
+					fmt.Sprintf("Cannot use %q as both a module worker and a classic worker",
+						files[otherSourceIndex].inputFile.Source.PrettyPath))
+			}
+		}
+	}
+
+	return workers
+}
+
 func (s *scanner) validateTLA(sourceIndex uint32) tlaCheck {
 	result := &s.results[sourceIndex]
 
@@ -2707,6 +3136,7 @@ func DefaultExtensionToLoaderMap() map[string]config.Loader {
 		".tsx":        config.LoaderTSX,
 		".css":        config.LoaderCSS,
 		".module.css": config.LoaderLocalCSS,
+		".html":       config.LoaderHTML,
 		".json":       config.LoaderJSON,
 		".txt":        config.LoaderText,
 	}
@@ -2814,25 +3244,85 @@ func (b *Bundle) Compile(log logger.Log, timer *helpers.Timer, mangleCache map[s
 		files[i] = file.inputFile
 	}
 
+	// Workers are linked separately, but their files are still part of the build
+	workerEntryPoints := b.sortedWorkerEntryPoints(log, files)
+
 	// Get the base path from the options or choose the lowest common ancestor of all entry points
-	allReachableFiles := findReachableFiles(files, b.entryPoints)
+	allReachableFiles := findReachableFiles(files, append(append([]graph.EntryPoint{}, b.entryPoints...), workerEntryPoints...))
+
+	// HTML entry points aren't linked. Instead, they are rewritten after linking
+	// to reference the output files for everything else.
+	linkedEntryPoints := b.entryPoints
+	linkedReachableFiles := allReachableFiles
+	var htmlEntryPoints []graph.EntryPoint
+	for _, entryPoint := range b.entryPoints {
+		if _, ok := files[entryPoint.SourceIndex].Repr.(*graph.HTMLRepr); ok {
+			htmlEntryPoints = append(htmlEntryPoints, entryPoint)
+		}
+	}
+	if htmlEntryPoints != nil {
+		linkedEntryPoints = nil
+		for _, entryPoint := range b.entryPoints {
+			if _, ok := files[entryPoint.SourceIndex].Repr.(*graph.HTMLRepr); !ok {
+				linkedEntryPoints = append(linkedEntryPoints, entryPoint)
+			}
+		}
+		linkedReachableFiles = findReachableFiles(files, linkedEntryPoints)
+	}
 
 	// Compute source map data in parallel with linking
 	timer.Begin("Spawn source map tasks")
 	dataForSourceMaps := b.computeDataForSourceMapsInParallel(&options, allReachableFiles)
 	timer.End("Spawn source map tasks")
 
+	// Link each worker before the code that creates it, since that code needs
+	// to know the final output path of the worker
+	var workerResultGroups [][]graph.OutputFile
+	if workerEntryPoints != nil {
+		options.WorkerOutputPaths = make(map[uint32]string, len(workerEntryPoints))
+		for _, entryPoint := range workerEntryPoints {
+			entryPoints := []graph.EntryPoint{entryPoint}
+
+			// Module workers can use "import" statements but classic workers can't.
+			// Worker output files are named like chunks since they are only ever
+			// referenced by other generated code, never by the user directly.
+			workerOptions := options
+			if b.workers[entryPoint.SourceIndex] {
+				workerOptions.OutputFormat = config.FormatESModule
+			} else {
+				workerOptions.OutputFormat = config.FormatIIFE
+			}
+			workerOptions.CodeSplitting = false
+			workerOptions.GlobalName = nil
+			workerOptions.EntryPathTemplate = options.ChunkPathTemplate
+
+			group := link(&workerOptions, timer, log, b.fs, b.res, files, entryPoints,
+				b.uniqueKeyPrefix, findReachableFiles(files, entryPoints), dataForSourceMaps)
+			for _, outputFile := range group {
+				if !outputFile.IsCSS && outputFile.EntryPointSourceIndex.IsValid() && outputFile.EntryPointSourceIndex.GetIndex() == entryPoint.SourceIndex {
+					if relPath, ok := b.fs.Rel(options.AbsOutputDir, outputFile.AbsPath); ok {
+						// Make sure to always use forward slashes, even on Windows
+						options.WorkerOutputPaths[entryPoint.SourceIndex] = strings.ReplaceAll(relPath, "\\", "/")
+					}
+				}
+			}
+			workerResultGroups = append(workerResultGroups, group)
+		}
+	}
+
 	var resultGroups [][]graph.OutputFile
-	if options.CodeSplitting || len(b.entryPoints) == 1 {
+	if len(linkedEntryPoints) == 0 {
+		// There's nothing to link if the only entry points are HTML files
+	} else if options.CodeSplitting || len(linkedEntryPoints) == 1 {
 		// If code splitting is enabled or if there's only one entry point, link all entry points together
 		resultGroups = [][]graph.OutputFile{link(&options, timer, log, b.fs, b.res,
-			files, b.entryPoints, b.uniqueKeyPrefix, allReachableFiles, dataForSourceMaps)}
+			files, linkedEntryPoints, b.uniqueKeyPrefix, linkedReachableFiles, dataForSourceMaps)}
 	} else {
 		// Otherwise, link each entry point with the runtime file separately
 		waitGroup := sync.WaitGroup{}
-		resultGroups = make([][]graph.OutputFile, len(b.entryPoints))
-		serializer := helpers.MakeSerializer(len(b.entryPoints))
-		for i, entryPoint := range b.entryPoints {
+		resultGroups = make([][]graph.OutputFile, len(linkedEntryPoints))
+		serializer := helpers.MakeSerializer(len(linkedEntryPoints))
+		for i, entryPoint := range linkedEntryPoints {
 			waitGroup.Add(1)
 			go func(i int, entryPoint graph.EntryPoint) {
 				entryPoints := []graph.EntryPoint{entryPoint}
@@ -2864,6 +3354,16 @@ func (b *Bundle) Compile(log logger.Log, timer *helpers.Timer, mangleCache map[s
 	for _, group := range resultGroups {
 		outputFiles = append(outputFiles, group...)
 	}
+	for _, group := range workerResultGroups {
+		outputFiles = append(outputFiles, group...)
+	}
+
+	// Rewrite HTML entry points now that the final output paths are known
+	if htmlEntryPoints != nil {
+		timer.Begin("Generate HTML output files")
+		outputFiles = append(outputFiles, b.generateHTMLOutputFiles(log, &options, htmlEntryPoints, outputFiles)...)
+		timer.End("Generate HTML output files")
+	}
 
 	// Also generate the metadata file if necessary
 	var metafileJSON string
@@ -2941,6 +3441,243 @@ func (b *Bundle) Compile(log logger.Log, timer *helpers.Timer, mangleCache map[s
 	return outputFiles, metafileJSON
 }
 
+// Workers are returned in dependency order so that each worker is linked
+// before any other worker that creates it. Workers that create themselves,
+// either directly or indirectly, are not supported because the output path of
+// each worker is derived from a hash of its contents.
+func (b *Bundle) sortedWorkerEntryPoints(log logger.Log, files []graph.InputFile) []graph.EntryPoint {
+	if len(b.workers) == 0 {
+		return nil
+	}
+
+	// Visit workers in source index order for determinism
+	sourceIndices := make([]uint32, 0, len(b.workers))
+	for sourceIndex := range b.workers {
+		sourceIndices = append(sourceIndices, sourceIndex)
+	}
+	sort.Slice(sourceIndices, func(i int, j int) bool {
+		return sourceIndices[i] < sourceIndices[j]
+	})
+
+	const (
+		notVisited uint8 = iota
+		visiting
+		visited
+	)
+	state := make(map[uint32]uint8, len(b.workers))
+	var order []graph.EntryPoint
+	var visit func(uint32)
+
+	visit = func(sourceIndex uint32) {
+		switch state[sourceIndex] {
+		case visiting:
+			log.AddError(nil, logger.Range{}, fmt.Sprintf("Cannot bundle the worker %q because it creates itself",
+				files[sourceIndex].Source.PrettyPath))
+			return
+		case visited:
+			return
+		}
+		state[sourceIndex] = visiting
+
+		// Link any workers created by the code in this worker first
+		for _, otherSourceIndex := range findReachableFiles(files, []graph.EntryPoint{{SourceIndex: sourceIndex}}) {
+			if repr, ok := files[otherSourceIndex].Repr.(*graph.JSRepr); ok {
+				for _, record := range repr.AST.ImportRecords {
+					if record.Kind == ast.ImportWorker && record.SourceIndex.IsValid() {
+						visit(record.SourceIndex.GetIndex())
+					}
+				}
+			}
+		}
+
+		state[sourceIndex] = visited
+		order = append(order, graph.EntryPoint{SourceIndex: sourceIndex})
+	}
+
+	for _, sourceIndex := range sourceIndices {
+		visit(sourceIndex)
+	}
+	return order
+}
+
+// HTML entry points are generated after linking since they reference the
+// final output paths of other entry points, which aren't known until then.
+// Each HTML file is copied through as-is except that URLs in attributes are
+// replaced with the paths to the corresponding output files.
+func (b *Bundle) generateHTMLOutputFiles(
+	log logger.Log,
+	options *config.Options,
+	htmlEntryPoints []graph.EntryPoint,
+	linkedOutputFiles []graph.OutputFile,
+) (outputFiles []graph.OutputFile) {
+	// Find the output files for each linked entry point
+	type entryPointOutput struct {
+		jsAbsPath  string
+		cssAbsPath string
+	}
+	entryPointOutputs := make(map[uint32]entryPointOutput)
+	for _, outputFile := range linkedOutputFiles {
+		if outputFile.EntryPointSourceIndex.IsValid() {
+			sourceIndex := outputFile.EntryPointSourceIndex.GetIndex()
+			output := entryPointOutputs[sourceIndex]
+			if outputFile.IsCSS {
+				output.cssAbsPath = outputFile.AbsPath
+			} else {
+				output.jsAbsPath = outputFile.AbsPath
+			}
+			entryPointOutputs[sourceIndex] = output
+		}
+	}
+
+	for _, entryPoint := range htmlEntryPoints {
+		file := &b.files[entryPoint.SourceIndex].inputFile
+		repr := file.Repr.(*graph.HTMLRepr)
+
+		// The relative paths in the HTML file depend on its output directory.
+		// But the output path may contain a hash of the final contents, which
+		// depends on the relative paths. Break the cycle by computing the
+		// directory using a placeholder hash. Relative paths are the same for
+		// any hash since the hash can't appear in any of the other paths.
+		dir, base := PathRelativeToOutbase(file, options, b.fs, false /* avoidIndex */, entryPoint.OutputPath)
+		_, _, ext := logger.PlatformIndependentPathDirBaseExt(file.Source.KeyPath.Text)
+		templateExt := strings.TrimPrefix(ext, ".")
+		template := config.SubstituteTemplate(options.EntryPathTemplate, config.PathPlaceholders{
+			Dir:  &dir,
+			Name: &base,
+			Ext:  &templateExt,
+		})
+		placeholderRelPath := config.TemplateToString(config.SubstituteTemplate(template, config.PathPlaceholders{
+			Hash: &b.uniqueKeyPrefix,
+		})) + ext
+		absDir := b.fs.Dir(b.fs.Join(options.AbsOutputDir, placeholderRelPath))
+
+		urlForAbsPath := func(absPath string) string {
+			if options.PublicPath != "" {
+				relPath, _ := b.fs.Rel(options.AbsOutputDir, absPath)
+				return helpers.JoinWithPublicPath(options.PublicPath, strings.ReplaceAll(relPath, "\\", "/"))
+			}
+			relPath, ok := b.fs.Rel(absDir, absPath)
+			if !ok {
+				log.AddError(nil, logger.Range{},
+					fmt.Sprintf("Cannot traverse from directory %q to file %q", absDir, absPath))
+				return ""
+			}
+			relPath = strings.ReplaceAll(relPath, "\\", "/")
+			if !strings.HasPrefix(relPath, "../") {
+				relPath = "./" + relPath
+			}
+			return relPath
+		}
+
+		// Substitute the URL for each import record
+		urls := make([]string, len(repr.AST.URLs))
+		var stylesheetsBefore []string
+		var importedAbsPaths []string
+		var additionalFiles []graph.OutputFile
+		for i, url := range repr.AST.URLs {
+			record := &repr.AST.ImportRecords[i]
+			var absPath string
+			var suffix string
+
+			switch url.Kind {
+			case html_ast.URLScript, html_ast.URLStylesheet:
+				if !record.SourceIndex.IsValid() {
+					continue
+				}
+				output := entryPointOutputs[record.SourceIndex.GetIndex()]
+				if url.Kind == html_ast.URLScript {
+					absPath = output.jsAbsPath
+
+					// Also reference any CSS that was imported by the JavaScript code
+					if output.cssAbsPath != "" {
+						if stylesheetsBefore == nil {
+							stylesheetsBefore = make([]string, len(repr.AST.URLs))
+						}
+						stylesheetsBefore[i] = urlForAbsPath(output.cssAbsPath)
+						importedAbsPaths = append(importedAbsPaths, output.cssAbsPath)
+					}
+				} else {
+					absPath = output.cssAbsPath
+				}
+
+			case html_ast.URLAsset:
+				var other *graph.InputFile
+				if record.CopySourceIndex.IsValid() {
+					other = &b.files[record.CopySourceIndex.GetIndex()].inputFile
+				} else if record.SourceIndex.IsValid() {
+					other = &b.files[record.SourceIndex.GetIndex()].inputFile
+				} else {
+					continue
+				}
+
+				// Files from the "file" and "copy" loaders are written to the output
+				// directory while other loaders (e.g. "dataurl") embed the file
+				if other.UniqueKeyForAdditionalFile != "" {
+					absPath = other.AdditionalFiles[0].AbsPath
+					suffix = other.Source.KeyPath.IgnoredSuffix
+					additionalFiles = append(additionalFiles, other.AdditionalFiles...)
+				} else if otherRepr, ok := other.Repr.(*graph.JSRepr); ok {
+					urls[i] = otherRepr.AST.URLForCSS
+				}
+			}
+
+			if absPath != "" {
+				urls[i] = urlForAbsPath(absPath) + suffix
+				importedAbsPaths = append(importedAbsPaths, absPath)
+			}
+		}
+
+		contents := html_printer.Print(repr.AST, file.Source, html_printer.Options{
+			URLs:              urls,
+			StylesheetsBefore: stylesheetsBefore,
+		})
+
+		// Now that the contents are known, compute the final output path
+		var hash string
+		if config.HasPlaceholder(template, config.HashPlaceholder) {
+			h := xxhash.New()
+			h.Write(contents)
+			hash = HashForFileName(h.Sum(nil))
+		}
+		relPath := config.TemplateToString(config.SubstituteTemplate(template, config.PathPlaceholders{
+			Hash: &hash,
+		})) + ext
+
+		// Optionally add metadata about the file
+		var jsonMetadataChunk string
+		if options.NeedsMetafile {
+			sb := strings.Builder{}
+			sb.WriteString("{\n      \"imports\": [")
+			for i, absPath := range importedAbsPaths {
+				if i > 0 {
+					sb.WriteByte(',')
+				}
+				sb.WriteString(fmt.Sprintf("\n        {\n          \"path\": %s,\n          \"kind\": %s\n        }",
+					helpers.QuoteForJSON(resolver.PrettyPath(b.fs, logger.Path{Text: absPath, Namespace: "file"}), options.ASCIIOnly),
+					helpers.QuoteForJSON(ast.ImportHTMLAttribute.StringForMetafile(), options.ASCIIOnly)))
+			}
+			if len(importedAbsPaths) > 0 {
+				sb.WriteString("\n      ")
+			}
+			sb.WriteString(fmt.Sprintf("],\n      \"exports\": [],\n      \"entryPoint\": %s,\n      \"inputs\": {\n        %s: {\n          \"bytesInOutput\": %d\n        }\n      },\n      \"bytes\": %d\n    }",
+				helpers.QuoteForJSON(file.Source.PrettyPath, options.ASCIIOnly),
+				helpers.QuoteForJSON(file.Source.PrettyPath, options.ASCIIOnly),
+				len(contents),
+				len(contents)))
+			jsonMetadataChunk = sb.String()
+		}
+
+		outputFiles = append(outputFiles, additionalFiles...)
+		outputFiles = append(outputFiles, graph.OutputFile{
+			AbsPath:           b.fs.Join(options.AbsOutputDir, relPath),
+			Contents:          contents,
+			JSONMetadataChunk: jsonMetadataChunk,
+		})
+	}
+
+	return
+}
+
 // Find all files reachable from all entry points. This order should be
 // deterministic given that the entry point order is deterministic, since the
 // returned order is the postorder of the graph traversal and import record
@@ -2960,6 +3697,10 @@ func findReachableFiles(files []graph.InputFile, entryPoints []graph.EntryPoint)
 			}
 			if recordsPtr := file.Repr.ImportRecords(); recordsPtr != nil {
 				for _, record := range *recordsPtr {
+					if record.Kind == ast.ImportWorker {
+						// Workers are linked separately from the code that creates them
+						continue
+					}
 					if record.SourceIndex.IsValid() {
 						visit(record.SourceIndex.GetIndex())
 					} else if record.CopySourceIndex.IsValid() {
@@ -3102,7 +3843,15 @@ func (b *Bundle) generateMetadataJSON(results []graph.OutputFile, allReachableFi
 		}
 	}
 
-	sb.WriteString("\n  }\n}\n")
+	sb.WriteString("\n  }")
+
+	// Write packages with multiple versions
+	if b.duplicatePackagesJSON != "" {
+		sb.WriteString(",\n  \"duplicatePackages\": ")
+		sb.WriteString(b.duplicatePackagesJSON)
+	}
+
+	sb.WriteString("\n}\n")
 	return sb.String()
 }
 
@@ -4628,3 +4628,320 @@ func TestDCEOfSymbolInstances(t *testing.T) {
 		},
 	})
 }
+
+func TestTreeShakeCommonJS(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { used, missing } from './dot'
+				import { b } from './object'
+				import def, { foo } from './typescript'
+				console.log(used(), missing, b, def, foo())
+			`,
+			"/dot.js": `
+				function used() { return 'used' }
+				exports.used = used
+				exports.unused = function () { return 'unused' }
+				module.exports.alsoUnused = 'unused'
+			`,
+			"/object.js": `
+				const a = require('./a')
+				module.exports = {
+					a,
+					b: 'b',
+					'c-d': function () {},
+				}
+			`,
+			"/a.js": `
+				module.exports = 'a'
+			`,
+			"/typescript.js": `
+				"use strict";
+				Object.defineProperty(exports, "__esModule", { value: true });
+				exports.bar = exports.foo = void 0;
+				const foo = () => 'foo';
+				exports.foo = foo;
+				function bar() { return 'bar' }
+				exports.bar = bar;
+				exports.default = 'default';
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:              config.ModeBundle,
+			AbsOutputFile:     "/out.js",
+			TreeShakeCommonJS: true,
+		},
+		expectedCompileLog: `entry.js: WARNING: Import "missing" will always be undefined because there is no matching export in "dot.js"
+`,
+	})
+}
+
+func TestTreeShakeCommonJSDefaultImport(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import lib from './lib'
+				import * as ns from './lib'
+				console.log(lib, ns)
+			`,
+			"/lib.js": `
+				exports.a = 1
+				exports.b = 2
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:              config.ModeBundle,
+			AbsOutputFile:     "/out.js",
+			TreeShakeCommonJS: true,
+		},
+	})
+}
+
+func TestTreeShakeCommonJSFallback(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import * as a from './read-exports'
+				import * as b from './mixed'
+				import * as c from './escape'
+				import * as d from './return'
+				import * as e from './default-without-marker'
+				import * as f from './reassign'
+				console.log(a, b, c, d, e, f)
+			`,
+			"/read-exports.js": `
+				exports.foo = 1
+				exports.bar = function () { return exports.foo }
+			`,
+			"/mixed.js": `
+				module.exports = { foo: 1, bar: 2 }
+				module.exports.baz = 3
+			`,
+			"/escape.js": `
+				exports.foo = 1
+				Object.assign(exports, { bar: 2 })
+			`,
+			"/return.js": `
+				exports.foo = 1
+				if (Math.random() < 0.5) return
+				exports.bar = 2
+			`,
+			"/default-without-marker.js": `
+				exports.default = 1
+				exports.foo = 2
+			`,
+			"/reassign.js": `
+				exports.foo = 1
+				exports.foo = 2
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:              config.ModeBundle,
+			AbsOutputFile:     "/out.js",
+			TreeShakeCommonJS: true,
+		},
+	})
+}
+
+func TestTreeShakeCommonJSEntryPoint(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				exports.foo = require('./lib').foo
+			`,
+			"/lib.js": `
+				exports.foo = 1
+				exports.bar = 2
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:              config.ModeBundle,
+			OutputFormat:      config.FormatCommonJS,
+			AbsOutputFile:     "/out.js",
+			TreeShakeCommonJS: true,
+		},
+	})
+}
+
+func TestFoldImportedConstants(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { FEATURE_X, MODE, CONFIG, VERBOSE } from './flags'
+				import { debugTools } from './debug'
+				if (FEATURE_X) import('./heavy')
+				if (MODE !== 'production') debugTools()
+				if (CONFIG.debug) {
+					debugTools()
+				} else {
+					console.log('release', CONFIG.level)
+				}
+				export function log(x) {
+					return VERBOSE ? debugTools(x) : CONFIG['level'] > 1 && x
+				}
+				console.log(FEATURE_X || 'fallback', MODE === 'production' && !FEATURE_X)
+			`,
+			"/flags.js": `
+				export const FEATURE_X = false
+				export const MODE = 'production'
+				export const CONFIG = Object.freeze({ debug: false, level: 2 })
+				export const VERBOSE = null
+			`,
+			"/debug.js": `
+				export function debugTools() { console.log('debug tools') }
+			`,
+			"/heavy.js": `
+				import './heavy-dependency'
+				console.log('heavy')
+			`,
+			"/heavy-dependency.js": `
+				console.log('heavy dependency')
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputFile: "/out.js",
+		},
+	})
+}
+
+func TestFoldImportedConstantsSplitting(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { DEBUG } from './flags'
+				if (DEBUG) import('./heavy')
+				if (!DEBUG) import('./light')
+			`,
+			"/other.js": `
+				import { DEBUG } from './flags'
+				if (DEBUG) import('./nested')
+			`,
+			"/flags.js": `
+				export const DEBUG = false
+			`,
+			"/heavy.js": `
+				import('./nested')
+				console.log('heavy')
+			`,
+			"/nested.js": `
+				console.log('nested')
+			`,
+			"/light.js": `
+				console.log('light')
+			`,
+		},
+		entryPaths: []string{"/entry.js", "/other.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			CodeSplitting: true,
+			AbsOutputDir:  "/out",
+			NeedsMetafile: true,
+		},
+	})
+}
+
+func TestFoldImportedConstantsMinifySyntax(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { FEATURE_X } from './flags'
+				import { heavy } from './heavy'
+				if (FEATURE_X) heavy()
+				if (!FEATURE_X) console.log('light')
+			`,
+			"/flags.js": `
+				export const FEATURE_X = false
+			`,
+			"/heavy.js": `
+				export function heavy() { console.log('heavy') }
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputFile: "/out.js",
+			MinifySyntax:  true,
+		},
+	})
+}
+
+func TestFoldImportedConstantsNotFoldable(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { mutable, object, frozen, notFrozen, computed } from './flags'
+				import { used } from './used'
+				if (mutable) used('let')
+				if (object) used('object')
+				if (frozen.missing) used('missing property')
+				if (frozen?.debug) used('optional chain')
+				if (notFrozen.debug) used('not frozen')
+				if (computed) used('not a primitive')
+				function hoisting() {
+					if (frozen.debug) { var hoisted = 1 }
+					return hoisted
+				}
+				console.log(hoisting())
+			`,
+			"/flags.js": `
+				export let mutable = false
+				export const object = {}
+				export const frozen = Object.freeze({ debug: false })
+				export const notFrozen = { debug: false }
+				export const computed = 1 + 1 ? 0 : 1
+			`,
+			"/used.js": `
+				export function used(x) { console.log(x) }
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputFile: "/out.js",
+		},
+	})
+}
+
+func TestFoldImportedConstantsImportCycle(t *testing.T) {
+	dce_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import './a'
+				import './wrapped'
+			`,
+			"/a.js": `
+				import './b'
+				export const ON = true
+			`,
+			"/b.js": `
+				import { ON } from './a'
+				if (ON) console.log('on')
+				else console.log('off')
+			`,
+			"/wrapped.js": `
+				import { DEBUG } from './cjs'
+				if (DEBUG) console.log('debug')
+			`,
+			"/cjs.js": `
+				export const DEBUG = false
+				require('./wrapped')
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputFile: "/out.js",
+		},
+	})
+}
@@ -240,6 +240,189 @@ func TestExportFormsIIFE(t *testing.T) {
 	})
 }
 
+func TestExportFormsAMD(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import ext from 'ext'
+				import {foo} from 'other-ext'
+				try { require('optional-ext') } catch {}
+				export default 123
+				export let l = ext + foo
+				export * from './a'
+			`,
+			"/a.js": "export const abc = undefined",
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatAMD,
+			AbsOutputFile: "/out.js",
+			ExternalSettings: config.ExternalSettings{
+				PreResolve: config.ExternalMatchers{Exact: map[string]bool{
+					"ext":          true,
+					"other-ext":    true,
+					"optional-ext": true,
+				}},
+			},
+		},
+	})
+}
+
+func TestExportFormsUMD(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import ext from 'ext'
+				export default 123
+				export let l = ext
+				export * from './a'
+			`,
+			"/a.js": "export const abc = undefined",
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatUMD,
+			GlobalName:    []string{"ns", "global-name"},
+			AbsOutputFile: "/out.js",
+			ExternalSettings: config.ExternalSettings{
+				PreResolve: config.ExternalMatchers{Exact: map[string]bool{
+					"ext": true,
+				}},
+			},
+			UMDGlobals: map[string][]string{
+				"ext": {"lib", "ext-global"},
+			},
+		},
+	})
+}
+
+func TestExportFormsUMDMissingGlobal(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import a from 'a'
+				import b from 'b'
+				try { require('optional') } catch {}
+				console.log(a, b)
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatUMD,
+			AbsOutputFile: "/out.js",
+			ExternalSettings: config.ExternalSettings{
+				PreResolve: config.ExternalMatchers{Exact: map[string]bool{
+					"a":        true,
+					"b":        true,
+					"optional": true,
+				}},
+			},
+			UMDGlobals: map[string][]string{
+				"a": {"A"},
+			},
+		},
+		expectedCompileLog: `entry.js: ERROR: No global variable is configured for the external module "b"
+NOTE: The "umd" format reads external modules from global variables when there is no module loader. You can use "--umd-global:b=Name" to specify the global variable to use.
+`,
+	})
+}
+
+func TestExportFormsUMDCommonJS(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				module.exports = { foo: 123 }
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:             config.ModeBundle,
+			OutputFormat:     config.FormatUMD,
+			MinifyWhitespace: true,
+			AbsOutputFile:    "/out.js",
+		},
+	})
+}
+
+func TestExportFormsSystemJS(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import ext, {foo as bar} from 'ext'
+				import * as ns from 'ns-ext'
+				import 'side-effect-ext'
+				export {bar as reexported}
+				export * from 'star-ext'
+				export default 123
+				export let l = 234
+				export function Fn(exports) {
+					l = exports
+					l += 1
+					l++
+					return l--
+				}
+				export let [d1, d2] = [1, 2]
+				export function swap() { [d1, d2] = [d2, d1] }
+				export * from './a'
+				console.log(ext, ns, import.meta.url, import('dynamic-ext'))
+			`,
+			"/a.js": "export const abc = undefined",
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatSystemJS,
+			AbsOutputFile: "/out.js",
+			ExternalSettings: config.ExternalSettings{
+				PreResolve: config.ExternalMatchers{Exact: map[string]bool{
+					"ext":             true,
+					"ns-ext":          true,
+					"side-effect-ext": true,
+					"star-ext":        true,
+					"dynamic-ext":     true,
+				}},
+			},
+		},
+	})
+}
+
+func TestExportFormsSystemJSCommonJS(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				module.exports = { foo: 123 }
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:             config.ModeBundle,
+			OutputFormat:     config.FormatSystemJS,
+			MinifyWhitespace: true,
+			AbsOutputFile:    "/out.js",
+		},
+	})
+}
+
+func TestExportFormsSystemJSTopLevelAwait(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				export const value = await import('./data')
+			`,
+			"/data.js": "export default 123",
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatSystemJS,
+			AbsOutputFile: "/out.js",
+		},
+	})
+}
+
 func TestExportFormsWithMinifyIdentifiersAndNoBundle(t *testing.T) {
 	default_suite.expectBundled(t, bundled{
 		files: map[string]string{
@@ -7804,6 +7987,158 @@ func TestPackageAliasMatchLongest(t *testing.T) {
 	})
 }
 
+func TestImportMap(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/src/entry.js": `
+				import "react"
+				import "lodash/get"
+				import "lodash/fp/set"
+				import "./old.js"
+				import "cdn-pkg"
+				import "cdn/some/file.js"
+				import "file-url"
+				import "not-mapped"
+			`,
+			"/Users/user/project/import-map.json": `{
+				"imports": {
+					"react": "./vendor/react.js",
+					"lodash/": "./vendor/lodash/",
+					"lodash/fp/": "./vendor/lodash-fp/",
+					"./src/old.js": "./src/new.js",
+					"cdn-pkg": "https://example.com/cdn-pkg.js",
+					"cdn/": "https://example.com/cdn/",
+					"file-url": "file:///Users/user/project/vendor/file-url.js"
+				}
+			}`,
+			"/Users/user/project/vendor/react.js":                  `console.log('react')`,
+			"/Users/user/project/vendor/lodash/get.js":             `console.log('lodash/get')`,
+			"/Users/user/project/vendor/lodash-fp/set.js":          `console.log('lodash/fp/set')`,
+			"/Users/user/project/vendor/file-url.js":               `console.log('file-url')`,
+			"/Users/user/project/src/old.js":                       `test failure`,
+			"/Users/user/project/src/new.js":                       `console.log('new')`,
+			"/Users/user/project/node_modules/react/index.js":      `test failure`,
+			"/Users/user/project/node_modules/not-mapped/index.js": `console.log('not-mapped')`,
+		},
+		entryPaths: []string{"/Users/user/project/src/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+			ImportMapPath: "/Users/user/project/import-map.json",
+		},
+	})
+}
+
+func TestImportMapScopes(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/entry.js": `
+				import "pkg"
+				import "./legacy/index.js"
+				import "./legacy/nested/index.js"
+			`,
+			"/Users/user/project/legacy/index.js":             `import "pkg"`,
+			"/Users/user/project/legacy/nested/index.js":      `import "pkg"; import "other"`,
+			"/Users/user/project/pkg-v2.js":                   `console.log('v2')`,
+			"/Users/user/project/pkg-v1.js":                   `console.log('v1')`,
+			"/Users/user/project/pkg-v0.js":                   `console.log('v0')`,
+			"/Users/user/project/node_modules/other/index.js": `console.log('other')`,
+		},
+		entryPaths:    []string{"/Users/user/project/entry.js"},
+		absWorkingDir: "/Users/user/project",
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+			ImportMapRaw: `{
+				"imports": { "pkg": "./pkg-v2.js" },
+				"scopes": {
+					"./legacy/": { "pkg": "./pkg-v1.js" },
+					"./legacy/nested/": { "pkg": "./pkg-v0.js" }
+				}
+			}`,
+		},
+	})
+}
+
+func TestImportMapBlocked(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import "blocked"
+				import "blocked-dir/foo"
+			`,
+			"/node_modules/blocked/index.js":         `test failure`,
+			"/node_modules/blocked-dir/foo/index.js": `test failure`,
+		},
+		entryPaths:    []string{"/entry.js"},
+		absWorkingDir: "/",
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+			ImportMapRaw:  `{ "imports": { "blocked": null, "blocked-dir/": null } }`,
+		},
+		expectedScanLog: `entry.js: ERROR: Could not resolve "blocked"
+NOTE: The import "blocked" is mapped to null in the import map.
+NOTE: You can mark the path "blocked" as external to exclude it from the bundle, which will remove this error and leave the unresolved path in the bundle.
+entry.js: ERROR: Could not resolve "blocked-dir/foo"
+NOTE: The import "blocked-dir/foo" is mapped to null in the import map.
+NOTE: You can mark the path "blocked-dir/foo" as external to exclude it from the bundle, which will remove this error and leave the unresolved path in the bundle.
+`,
+	})
+}
+
+func TestImportMapInvalid(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import "a"
+				import "b"
+			`,
+			"/import-map.json": `{
+				"imports": {
+					"": "./empty.js",
+					"a": "not-relative.js",
+					"b": 123,
+					"c/": "./no-trailing-slash"
+				},
+				"scopes": {
+					"bare-scope/": {}
+				}
+			}`,
+			"/node_modules/a/index.js": `console.log('a')`,
+			"/node_modules/b/index.js": `console.log('b')`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+			ImportMapPath: "/import-map.json",
+		},
+		expectedScanLog: `import-map.json: WARNING: Invalid empty specifier in import map
+import-map.json: WARNING: Invalid address "not-relative.js" for "a" (addresses must be URLs or start with "/", "./", or "../")
+import-map.json: WARNING: The mapping for "b" must be a string or null
+import-map.json: WARNING: The address "./no-trailing-slash" for "c/" must end in "/" because the specifier ends in "/"
+import-map.json: WARNING: Invalid scope "bare-scope/" (scopes must be URLs or start with "/", "./", or "../")
+`,
+	})
+}
+
+func TestImportMapMissingFile(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `console.log(1)`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+			ImportMapPath: "/import-map.json",
+		},
+		expectedScanLog: `ERROR: Cannot find import map file "import-map.json"
+`,
+	})
+}
+
 func TestErrorsForAssertTypeJSON(t *testing.T) {
 	default_suite.expectBundled(t, bundled{
 		files: map[string]string{
@@ -8135,6 +8470,41 @@ func TestMetafileImportWithTypeJSON(t *testing.T) {
 	})
 }
 
+func TestMetafileWhy(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/project/entry.js": `
+				import { helper } from './lib/index.js'
+				import * as ns from './ns.js'
+				import './style.css'
+				console.log(helper(), ns)
+				export let entry = 1
+			`,
+			"/project/lib/index.js": `
+				export { helper, unused } from './helper.js'
+			`,
+			"/project/lib/helper.js": `
+				export function helper() { return 1 }
+				export function unused() { return 2 }
+			`,
+			"/project/ns.js": `
+				export let a = 1
+				export let b = 2
+			`,
+			"/project/style.css": `
+				a { color: red }
+			`,
+		},
+		entryPaths: []string{"/project/entry.js"},
+		options: config.Options{
+			Mode:             config.ModeBundle,
+			AbsOutputDir:     "/out",
+			NeedsMetafile:    true,
+			NeedsMetafileWhy: true,
+		},
+	})
+}
+
 func TestCommentPreservation(t *testing.T) {
 	default_suite.expectBundled(t, bundled{
 		files: map[string]string{
@@ -8811,3 +9181,192 @@ func TestObjectLiteralProtoSetterEdgeCasesMinifySyntax(t *testing.T) {
 		},
 	})
 }
+
+func TestHotModuleReplacement(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import { count, inc } from './counter'
+				import label from './label'
+				import * as cjs from './cjs'
+				inc()
+				console.log(count, label, cjs)
+			`,
+			"/counter.js": `
+				export let count = 0
+				export function inc() { count++ }
+				export function unused() {}
+				import.meta.hot.dispose(data => { data.count = count })
+				import.meta.hot.accept()
+			`,
+			"/label.js": `
+				export default 'label'
+			`,
+			"/cjs.js": `
+				exports.foo = 123
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:                 config.ModeBundle,
+			OutputFormat:         config.FormatESModule,
+			AbsOutputFile:        "/out.js",
+			HotModuleReplacement: true,
+		},
+	})
+}
+
+func TestHotModuleReplacementEntryPointExportsIIFE(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				export * from './other'
+				export let value = import.meta.hot.data.value
+			`,
+			"/other.js": `
+				export let other = 1
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:                 config.ModeBundle,
+			OutputFormat:         config.FormatIIFE,
+			GlobalName:           []string{"globalName"},
+			AbsOutputFile:        "/out.js",
+			HotModuleReplacement: true,
+		},
+	})
+}
+
+func TestNewWorker(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/entry.js": `
+				let module = new Worker(new URL('./module.ts', import.meta.url), { type: 'module' })
+				let classic = new Worker(new URL('./classic.js', import.meta.url))
+				let shared = new SharedWorker(new URL('./module.ts', import.meta.url), { type: 'module', name: 'shared' })
+
+				// These are not workers
+				new Worker('./classic.js')
+				new Worker(new URL('classic.js', import.meta.url))
+				new Worker(new URL('./classic.js', location.href))
+				function foo(Worker) { new Worker(new URL('./image.png', import.meta.url)) }
+			`,
+			"/src/module.ts": `
+				import { handle } from './shared'
+				let nested = new Worker(new URL('./classic.js', import.meta.url))
+				self.onmessage = (e: MessageEvent) => handle(e.data, nested)
+			`,
+			"/src/classic.js": `
+				import { handle } from './shared'
+				self.onmessage = e => handle(e.data)
+			`,
+			"/src/shared.js": `
+				export function handle(data, worker) { console.log(data, worker) }
+			`,
+			"/src/image.png": `image`,
+		},
+		entryPaths: []string{"/src/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			NeedsMetafile: true,
+		},
+	})
+}
+
+func TestNewWorkerIIFE(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js":  `new Worker(new URL('./worker.js', import.meta.url))`,
+			"/worker.js": `console.log('worker')`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatIIFE,
+			AbsOutputDir: "/out",
+		},
+	})
+}
+
+func TestNewWorkerCommonJSDefineImportMetaURL(t *testing.T) {
+	defines := config.ProcessDefines(map[string]config.DefineData{
+		"import.meta.url": {
+			DefineExpr: &config.DefineExpr{Parts: []string{"__worker_base"}},
+		},
+	})
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js":  `new Worker(new URL('./worker.js', import.meta.url))`,
+			"/worker.js": `console.log('worker')`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatCommonJS,
+			AbsOutputDir: "/out",
+			Defines:      &defines,
+		},
+	})
+}
+
+func TestNewWorkerErrors(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				new Worker(new URL('./style.css', import.meta.url))
+				new Worker(new URL('./both.js', import.meta.url))
+				new Worker(new URL('./both.js', import.meta.url), { type: 'module' })
+			`,
+			"/style.css": `a { color: red }`,
+			"/both.js":   `console.log('both')`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatESModule,
+			AbsOutputDir: "/out",
+		},
+		expectedScanLog: `entry.js: ERROR: Cannot use "style.css" as a worker
+NOTE: Only JavaScript and TypeScript files can be used as workers, and "style.css" was loaded with the "css" loader.
+entry.js: ERROR: Cannot use "both.js" as both a module worker and a classic worker
+`,
+	})
+}
+
+func TestNewWorkerCycle(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `new Worker(new URL('./a.js', import.meta.url))`,
+			"/a.js":     `new Worker(new URL('./b.js', import.meta.url))`,
+			"/b.js":     `new Worker(new URL('./a.js', import.meta.url))`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatESModule,
+			AbsOutputDir: "/out",
+		},
+		expectedCompileLog: `ERROR: Cannot bundle the worker "a.js" because it creates itself
+`,
+	})
+}
+
+func TestNewWorkerOutfile(t *testing.T) {
+	default_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js":  `new Worker(new URL('./worker.js', import.meta.url))`,
+			"/worker.js": `console.log('worker')`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputFile: "/out.js",
+		},
+		expectedScanLog: `entry.js: ERROR: Must use "outdir" when bundling workers
+`,
+	})
+}
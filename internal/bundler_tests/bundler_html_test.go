@@ -0,0 +1,220 @@
+package bundler_tests
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/internal/config"
+)
+
+var html_suite = suite{
+	name: "html",
+}
+
+func TestHTMLEntryPoint(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/index.html": `
+				<!DOCTYPE html>
+				<html>
+				<head>
+					<link rel="stylesheet" href="style.css">
+					<link rel=icon href=images/favicon.png>
+					<link rel="canonical" href="page.html">
+					<script type="module" src="./main.js"></script>
+				</head>
+				<body>
+					<img src="images/logo.png" srcset="images/logo.png 1x, images/logo@2x.png 2x">
+					<img src="https://example.com/remote.png">
+					<img src="/absolute.png">
+					<img src="data:image/png;base64,AAAA">
+					<a href="other.html">Not bundled</a>
+				</body>
+				</html>
+			`,
+			"/src/style.css":          `body { background: url(images/logo.png) }`,
+			"/src/main.js":            `console.log('main')`,
+			"/src/images/logo.png":    `logo`,
+			"/src/images/logo@2x.png": `logo@2x`,
+			"/src/images/favicon.png": `favicon`,
+		},
+		entryPaths: []string{"/src/index.html"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			AbsOutputDir: "/out",
+			EntryPathTemplate: []config.PathTemplate{
+				// "[dir]/[name]-[hash]"
+				{Data: "./", Placeholder: config.DirPlaceholder},
+				{Data: "/", Placeholder: config.NamePlaceholder},
+				{Data: "-", Placeholder: config.HashPlaceholder},
+			},
+			ExtensionToLoader: map[string]config.Loader{
+				".html": config.LoaderHTML,
+				".js":   config.LoaderJS,
+				".css":  config.LoaderCSS,
+				".png":  config.LoaderFile,
+			},
+			NeedsMetafile: true,
+		},
+	})
+}
+
+func TestHTMLScriptImportsCSS(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `
+				<html>
+				<head><title>Uses <script src="fake.js"></script></title></head>
+				<body>
+					<!-- <script src="commented-out.js"></script> -->
+					<script src=entry.js></script>
+				</body>
+				</html>
+			`,
+			"/entry.js":  `import './entry.css'; console.log('entry')`,
+			"/entry.css": `body { color: red }`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			AbsOutputDir: "/out",
+		},
+	})
+}
+
+func TestHTMLPublicPath(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `
+				<script type="module" src="./entry.js"></script>
+				<img src='image.png?query#hash'>
+			`,
+			"/entry.js":  `console.log('entry')`,
+			"/image.png": `image`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			AbsOutputDir: "/out",
+			PublicPath:   "https://example.com/static/",
+			ExtensionToLoader: map[string]config.Loader{
+				".html": config.LoaderHTML,
+				".js":   config.LoaderJS,
+				".png":  config.LoaderFile,
+			},
+		},
+	})
+}
+
+func TestHTMLDataURLAndCopy(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `
+				<video src="movie.mp4" poster="poster.svg"></video>
+				<audio src=sound&amp;effects.mp3></audio>
+			`,
+			"/movie.mp4":         `movie`,
+			"/poster.svg":        `<svg></svg>`,
+			"/sound&effects.mp3": `sound`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			AbsOutputDir: "/out",
+			ExtensionToLoader: map[string]config.Loader{
+				".html": config.LoaderHTML,
+				".mp4":  config.LoaderCopy,
+				".svg":  config.LoaderDataURL,
+				".mp3":  config.LoaderFile,
+			},
+		},
+	})
+}
+
+func TestHTMLMultiplePagesWithCodeSplitting(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.html":    `<script type="module" src="a.js"></script>`,
+			"/b.html":    `<script type="module" src="b.js"></script>`,
+			"/a.js":      `import { shared } from './shared.js'; shared('a')`,
+			"/b.js":      `import { shared } from './shared.js'; shared('b')`,
+			"/shared.js": `export function shared(x) { console.log(x) }`,
+		},
+		entryPaths: []string{"/a.html", "/b.html"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputDir:  "/out",
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+		},
+	})
+}
+
+func TestHTMLInvalidReferences(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `
+				<script src="style.css"></script>
+				<link rel="stylesheet" href="entry.js">
+				<img src="entry.js">
+				<img src="style.css">
+				<img src="missing.png">
+				<img src="data.txt">
+			`,
+			"/entry.js":  `import './index.html'`,
+			"/style.css": `a { color: red }`,
+			"/data.txt":  `text`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			AbsOutputDir: "/out",
+			ExtensionToLoader: map[string]config.Loader{
+				".html": config.LoaderHTML,
+				".js":   config.LoaderJS,
+				".css":  config.LoaderCSS,
+				".txt":  config.LoaderText,
+			},
+		},
+		expectedScanLog: `entry.js: ERROR: Cannot import "index.html"
+NOTE: HTML files can only be used as entry points, and "index.html" is an HTML file (it was loaded with the "html" loader).
+index.html: ERROR: Cannot use "style.css" as a script
+NOTE: A "<script>" tag can only be used to reference a JavaScript file and "style.css" is not a JavaScript file (it was loaded with the "css" loader).
+index.html: ERROR: Cannot use "entry.js" as a stylesheet
+NOTE: A "<link rel=stylesheet>" tag can only be used to reference a CSS file and "entry.js" is not a CSS file (it was loaded with the "js" loader).
+index.html: ERROR: Cannot use "entry.js" as a URL
+NOTE: You can't reference the file "entry.js" from an HTML attribute because it was loaded with the "js" loader, which doesn't provide a URL to embed in the resulting HTML.
+index.html: ERROR: Cannot use "style.css" as a URL
+NOTE: You can only use "<link rel=stylesheet>" to reference a CSS file from HTML, and "style.css" is a CSS file (it was loaded with the "css" loader).
+index.html: ERROR: Could not resolve "missing.png"
+NOTE: You can mark the path "missing.png" as external to exclude it from the bundle, which will remove this error and leave the unresolved path in the bundle.
+`,
+	})
+}
+
+func TestHTMLWithOutputFile(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `<script src="entry.js"></script>`,
+			"/entry.js":   `console.log('entry')`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.html",
+		},
+		expectedScanLog: `ERROR: Must use "outdir" when bundling the HTML file "index.html"
+`,
+	})
+}
+
+func TestHTMLWithoutBundling(t *testing.T) {
+	html_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/index.html": `<script src="entry.js"></script><img src="image.png">`,
+		},
+		entryPaths: []string{"/index.html"},
+		options: config.Options{
+			AbsOutputDir: "/out",
+		},
+	})
+}
@@ -1675,3 +1675,124 @@ func TestLoaderJSONPrototypeES5(t *testing.T) {
 		},
 	})
 }
+
+func TestLoaderNewURL(t *testing.T) {
+	loader_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/entry.js": `
+				console.log(
+					new URL('./logo.svg', import.meta.url),
+					new URL('../shared/icon.png', import.meta.url),
+					new URL('./data.txt', import.meta.url),
+					new URL('./copy.bin', import.meta.url),
+
+					// These are not asset references
+					new URL('https://example.com/logo.svg', import.meta.url),
+					new URL('logo.svg', import.meta.url),
+					new URL('./logo.svg', location.href),
+					new URL('./logo.svg'),
+				)
+			`,
+			"/src/logo.svg":      `<svg></svg>`,
+			"/shared/icon.png":   `icon`,
+			"/src/data.txt":      `data`,
+			"/src/copy.bin":      `copy`,
+			"/src/unused.svg":    `unused`,
+			"/shared/unused.png": `unused`,
+		},
+		entryPaths: []string{"/src/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			NeedsMetafile: true,
+			AssetPathTemplate: []config.PathTemplate{
+				// "assets/[name]-[hash]"
+				{Data: "./assets/", Placeholder: config.NamePlaceholder},
+				{Data: "-", Placeholder: config.HashPlaceholder},
+			},
+			ExtensionToLoader: map[string]config.Loader{
+				".js":  config.LoaderJS,
+				".txt": config.LoaderDataURL,
+				".bin": config.LoaderCopy,
+			},
+		},
+	})
+}
+
+func TestLoaderNewURLPublicPath(t *testing.T) {
+	loader_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/entry.js": `console.log(new URL('./logo.svg', import.meta.url))`,
+			"/src/logo.svg": `<svg></svg>`,
+		},
+		entryPaths: []string{"/src/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatESModule,
+			AbsOutputDir: "/out",
+			PublicPath:   "https://example.com/static/",
+		},
+	})
+}
+
+func TestLoaderNewURLCommonJS(t *testing.T) {
+	loader_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/entry.js": `
+				console.log(
+					new URL('./logo.svg', import.meta.url),
+					new URL('./copy.bin', import.meta.url),
+				)
+			`,
+			"/src/logo.svg": `<svg></svg>`,
+			"/src/copy.bin": `copy`,
+		},
+		entryPaths: []string{"/src/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatCommonJS,
+			AbsOutputDir: "/out",
+			ExtensionToLoader: map[string]config.Loader{
+				".js":  config.LoaderJS,
+				".svg": config.LoaderFile,
+				".bin": config.LoaderCopy,
+			},
+		},
+	})
+}
+
+func TestLoaderNewURLErrors(t *testing.T) {
+	loader_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				console.log(
+					new URL('./code.js', import.meta.url),
+					new URL('./style.css', import.meta.url),
+					new URL('./data.json', import.meta.url),
+				)
+			`,
+			"/code.js":   `console.log('code')`,
+			"/style.css": `a { color: red }`,
+			"/data.json": `{ "data": true }`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:         config.ModeBundle,
+			OutputFormat: config.FormatESModule,
+			AbsOutputDir: "/out",
+			ExtensionToLoader: map[string]config.Loader{
+				".js":   config.LoaderJS,
+				".css":  config.LoaderCSS,
+				".json": config.LoaderJSON,
+			},
+		},
+		expectedScanLog: `entry.js: ERROR: Cannot use "code.js" as a URL
+NOTE: You can't use "new URL()" to reference the file "code.js" because it was loaded with the "js" loader, which doesn't provide a URL to embed in the resulting JavaScript.
+entry.js: ERROR: Cannot use "style.css" as a URL
+NOTE: You can't use "new URL()" to reference the file "style.css" because it was loaded with the "css" loader, which doesn't provide a URL to embed in the resulting JavaScript.
+entry.js: ERROR: Cannot use "data.json" as a URL
+NOTE: You can't use "new URL()" to reference the file "data.json" because it was loaded with the "json" loader, which doesn't provide a URL to embed in the resulting JavaScript.
+`,
+	})
+}
@@ -909,14 +909,6 @@ func TestLowerAsyncES5(t *testing.T) {
 			UnsupportedJSFeatures: es(5),
 			AbsOutputFile:         "/out.js",
 		},
-		expectedScanLog: `arrow-1.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-arrow-2.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-export-def-1.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-export-def-2.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-fn-expr.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-fn-stmt.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-obj-method.js: ERROR: Transforming async functions to the configured target environment is not supported yet
-`,
 	})
 }
 
@@ -3162,3 +3154,64 @@ func TestLowerAsyncGeneratorNoAwait(t *testing.T) {
 		},
 	})
 }
+
+func TestLowerLetAndConstRenaming(t *testing.T) {
+	lower_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				let x = 1
+				{
+					let x = 2
+					{
+						const x = 3
+						console.log(x)
+					}
+					console.log(x)
+				}
+				function foo(x) {
+					{
+						let x = 4
+						if (x) {
+							let x = 5
+							console.log(x)
+						}
+						console.log(x)
+					}
+					return x
+				}
+				console.log(x, foo)
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:                  config.ModeBundle,
+			AbsOutputFile:         "/out.js",
+			UnsupportedJSFeatures: compat.ConstAndLet,
+		},
+	})
+}
+
+func TestLowerLetLoopClosureBundle(t *testing.T) {
+	lower_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				const fns = []
+				for (let i = 0; i < 3; i++) fns.push(() => i)
+				for (let i = 0; i < 3; i++) { fns.push(() => i); i++ }
+				for (const k in { a: 1, b: 2 }) fns.push(() => k)
+				let n = 0
+				while (n < 2) {
+					let j = n++
+					fns.push(() => j)
+				}
+				console.log(fns.map(fn => fn()))
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:                  config.ModeBundle,
+			AbsOutputFile:         "/out.js",
+			UnsupportedJSFeatures: compat.ConstAndLet | compat.Arrow,
+		},
+	})
+}
@@ -2754,6 +2754,107 @@ NOTE: You can mark the path "xyz/src/foo.js" as external to exclude it from the
 	})
 }
 
+func TestPackageJsonImportSelfFromNestedPackageJson(t *testing.T) {
+	packagejson_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/test/index.test.js": `
+				import xyz from "xyz"
+				console.log(xyz)
+			`,
+			"/Users/user/project/test/package.json": `
+				{
+					"type": "module"
+				}
+			`,
+			"/Users/user/project/src/index.js": `
+				export default 'index'
+			`,
+			"/Users/user/project/package.json": `
+				{
+					"name": "xyz",
+					"exports": "./src/index.js"
+				}
+			`,
+		},
+		entryPaths: []string{"/Users/user/project/test/index.test.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/Users/user/project/out.js",
+		},
+		expectedScanLog: `Users/user/project/test/index.test.js: ERROR: Could not resolve "xyz"
+NOTE: You can mark the path "xyz" as external to exclude it from the bundle, which will remove this error and leave the unresolved path in the bundle.
+`,
+	})
+}
+
+// A nested "package.json" file stops the search for a self-reference even if
+// it has no "name" field, so the import resolves to the installed package
+func TestPackageJsonImportSelfBlockedByNestedPackageJson(t *testing.T) {
+	packagejson_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/test/index.test.js": `
+				import xyz from "xyz"
+				console.log(xyz)
+			`,
+			"/Users/user/project/test/package.json": `
+				{
+					"type": "module"
+				}
+			`,
+			"/Users/user/project/test/node_modules/xyz/index.js": `
+				export default 'installed'
+			`,
+			"/Users/user/project/src/index.js": `
+				export default 'self'
+			`,
+			"/Users/user/project/package.json": `
+				{
+					"name": "xyz",
+					"exports": "./src/index.js"
+				}
+			`,
+		},
+		entryPaths: []string{"/Users/user/project/test/index.test.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/Users/user/project/out.js",
+		},
+	})
+}
+
+func TestPackageJsonImportSelfFromNestedNamedPackage(t *testing.T) {
+	packagejson_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/examples/demo/index.js": `
+				import xyz from "xyz"
+				console.log(xyz)
+			`,
+			"/Users/user/project/examples/demo/package.json": `
+				{
+					"name": "demo"
+				}
+			`,
+			"/Users/user/project/examples/demo/node_modules/xyz/index.js": `
+				export default 'installed'
+			`,
+			"/Users/user/project/src/index.js": `
+				export default 'self'
+			`,
+			"/Users/user/project/package.json": `
+				{
+					"name": "xyz",
+					"exports": "./src/index.js"
+				}
+			`,
+		},
+		entryPaths: []string{"/Users/user/project/examples/demo/index.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/Users/user/project/out.js",
+		},
+	})
+}
+
 func TestCommonJSVariableInESMTypeModule(t *testing.T) {
 	packagejson_suite.expectBundled(t, bundled{
 		files: map[string]string{
@@ -2908,3 +3009,58 @@ func TestPackageJsonSubpathImportNodeBuiltinIssue3485(t *testing.T) {
 		},
 	})
 }
+
+func TestPackageJsonDedupe(t *testing.T) {
+	packagejson_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/Users/user/project/src/entry.js": `
+				import { createContext } from "react"
+				import util from "react/esm/util"
+				import "lib-a"
+				import "lib-b"
+				console.log(createContext, util)
+			`,
+			"/Users/user/project/node_modules/react/package.json":     `{ "name": "react", "version": "18.2.0" }`,
+			"/Users/user/project/node_modules/react/index.js":         `export let createContext = 'react@18.2.0 (hoisted)'`,
+			"/Users/user/project/node_modules/react/extra.js":         `export default 'extra (hoisted)'`,
+			"/Users/user/project/node_modules/react/esm/package.json": `{ "type": "module" }`,
+			"/Users/user/project/node_modules/react/esm/util.js":      `export default 'util (hoisted)'`,
+
+			"/Users/user/project/node_modules/lib-a/package.json": `{ "name": "lib-a", "version": "1.0.0" }`,
+			"/Users/user/project/node_modules/lib-a/index.js": `
+				import { createContext } from "react"
+				import extra from "react/extra"
+				import util from "react/esm/util"
+				console.log('lib-a', createContext, extra, util)
+			`,
+			"/Users/user/project/node_modules/lib-a/node_modules/react/package.json":     `{ "name": "react", "version": "18.2.0" }`,
+			"/Users/user/project/node_modules/lib-a/node_modules/react/index.js":         `export let createContext = 'react@18.2.0 (nested)'`,
+			"/Users/user/project/node_modules/lib-a/node_modules/react/extra.js":         `export default 'extra (nested)'`,
+			"/Users/user/project/node_modules/lib-a/node_modules/react/esm/package.json": `{ "type": "module" }`,
+			"/Users/user/project/node_modules/lib-a/node_modules/react/esm/util.js":      `export default 'util (nested)'`,
+
+			"/Users/user/project/node_modules/lib-b/package.json": `{ "name": "lib-b", "version": "1.0.0" }`,
+			"/Users/user/project/node_modules/lib-b/index.js": `
+				import { createContext } from "react"
+				import util from "react/esm/util"
+				console.log('lib-b', createContext, util)
+			`,
+			"/Users/user/project/node_modules/lib-b/node_modules/react/package.json":     `{ "name": "react", "version": "17.0.2" }`,
+			"/Users/user/project/node_modules/lib-b/node_modules/react/index.js":         `export let createContext = 'react@17.0.2'`,
+			"/Users/user/project/node_modules/lib-b/node_modules/react/esm/package.json": `{ "type": "module" }`,
+			"/Users/user/project/node_modules/lib-b/node_modules/react/esm/util.js":      `export default 'util (17.0.2)'`,
+		},
+		entryPaths:    []string{"/Users/user/project/src/entry.js"},
+		absWorkingDir: "/Users/user/project",
+		options: config.Options{
+			Mode:           config.ModeBundle,
+			AbsOutputFile:  "/Users/user/project/out.js",
+			DedupePackages: true,
+			NeedsMetafile:  true,
+		},
+		expectedScanLog: `WARNING: Multiple versions of the package "react" were found
+NOTE: Version "17.0.2" is in "node_modules/lib-b/node_modules/react"
+NOTE: Version "18.2.0" is in "node_modules/react"
+`,
+	})
+}
@@ -1,9 +1,11 @@
 package bundler_tests
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/evanw/esbuild/internal/config"
+	"github.com/evanw/esbuild/internal/logger"
 )
 
 var splitting_suite = suite{
@@ -138,6 +140,188 @@ func TestSplittingDynamicAndNotDynamicCommonJSIntoES6(t *testing.T) {
 	})
 }
 
+func TestSplittingSharedES6IntoCommonJS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {foo, setFoo} from "./shared.js"
+				setFoo(1)
+				console.log(foo)
+			`,
+			"/b.js": `
+				import {foo} from "./shared.js"
+				console.log({foo})
+				export {foo}
+			`,
+			"/shared.js": `
+				export let foo = 123
+				export function setFoo(value) { foo = value }
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatCommonJS,
+			AbsOutputDir:  "/out",
+		},
+	})
+}
+
+func TestSplittingDynamicES6IntoCommonJS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.js": `
+				import {bar as a} from "./foo.js"
+				import("./foo.js").then(({bar: b}) => console.log(a, b))
+			`,
+			"/foo.js": `
+				export let bar = 123
+			`,
+		},
+		entryPaths: []string{"/entry.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatCommonJS,
+			AbsOutputDir:  "/out",
+		},
+	})
+}
+
+func TestSplittingSharedES6IntoIIFE(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {foo, setFoo} from "./shared.js"
+				setFoo(1)
+				console.log(foo)
+			`,
+			"/b.js": `
+				import {foo} from "./shared.js"
+				console.log(foo)
+			`,
+			"/shared.js": `
+				export let foo = 123
+				export function setFoo(value) { foo = value }
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatIIFE,
+			AbsOutputDir:  "/out",
+		},
+	})
+}
+
+func TestSplittingDynamicES6IntoIIFE(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {shared} from "./shared.js"
+				import("./b.js").then(({b}) => console.log(shared, b))
+			`,
+			"/b.js": `
+				import {shared} from "./shared.js"
+				export let b = shared + 1
+			`,
+			"/shared.js": `
+				export let shared = 123
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatIIFE,
+			GlobalName:    []string{"globalName"},
+			AbsOutputDir:  "/out",
+		},
+	})
+}
+
+func TestSplittingSharedES6IntoSystemJS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {foo, setFoo} from "./shared.js"
+				setFoo(1)
+				console.log(foo)
+			`,
+			"/b.js": `
+				import {foo} from "./shared.js"
+				console.log(foo)
+			`,
+			"/shared.js": `
+				export let foo = 123
+				export function setFoo(value) { foo = value }
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatSystemJS,
+			AbsOutputDir:  "/out",
+		},
+	})
+}
+
+func TestSplittingDynamicES6IntoSystemJS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {shared} from "./shared.js"
+				import("./b.js").then(({b}) => console.log(shared, b))
+			`,
+			"/b.js": `
+				import {shared} from "./shared.js"
+				export let b = shared + 1
+			`,
+			"/shared.js": `
+				export let shared = 123
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatSystemJS,
+			AbsOutputDir:  "/out",
+			NeedsMetafile: true,
+		},
+	})
+}
+
+func TestSplittingIIFEMinify(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import {foo} from "./shared.js"
+				console.log(foo(), import("./b.js"))
+			`,
+			"/b.js": `
+				import {foo} from "./shared.js"
+				console.log(foo())
+			`,
+			"/shared.js": `
+				export function foo() { return this }
+			`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:              config.ModeBundle,
+			CodeSplitting:     true,
+			OutputFormat:      config.FormatIIFE,
+			MinifyWhitespace:  true,
+			MinifyIdentifiers: true,
+			AbsOutputDir:      "/out",
+		},
+	})
+}
+
 func TestSplittingAssignToLocal(t *testing.T) {
 	splitting_suite.expectBundled(t, bundled{
 		files: map[string]string{
@@ -597,3 +781,300 @@ func TestEdgeCaseIssue2793WithoutSplitting(t *testing.T) {
 		},
 	})
 }
+
+func TestSplittingManualChunks(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import { render } from "react-dom"
+				import { createElement } from "react"
+				import { helper } from "./helper.js"
+				render(createElement(helper("a")))
+			`,
+			"/b.js": `
+				import { createElement } from "react"
+				import { helper } from "./helper.js"
+				console.log(createElement(helper("b")))
+			`,
+			"/helper.js":                       `export function helper(x) { return x }`,
+			"/node_modules/react/index.js":     `export function createElement(x) { return { x } }`,
+			"/node_modules/react-dom/index.js": `export function render(x) { document.body.append(x) }`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			ManualChunks: []config.ManualChunk{
+				{Name: "vendor", Patterns: []config.WildcardPattern{{Prefix: "node_modules/react"}}},
+			},
+		},
+	})
+}
+
+func TestSplittingManualChunksSideEffects(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import "polyfill-a"
+				import "polyfill-shared"
+				import { util } from "util"
+				console.log(util("a"))
+			`,
+			"/b.js": `
+				import "polyfill-b"
+				import "polyfill-shared"
+				import { util } from "util"
+				console.log(util("b"))
+			`,
+			"/c.js": `
+				import "polyfill-b"
+				console.log("c")
+			`,
+			"/node_modules/polyfill-a/index.js":      `globalThis.a = true`,
+			"/node_modules/polyfill-b/index.js":      `globalThis.b = true`,
+			"/node_modules/polyfill-shared/index.js": `globalThis.shared = true`,
+			"/node_modules/util/index.js":            `export function util(x) { return x }`,
+		},
+		entryPaths: []string{"/a.js", "/b.js", "/c.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			ManualChunks: []config.ManualChunk{
+				{Name: "vendor", Patterns: []config.WildcardPattern{{Prefix: "node_modules/"}}},
+			},
+		},
+	})
+}
+
+func TestSplittingManualChunksCallback(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import { one } from "./lib/one.js"
+				import { two } from "./lib/two.js"
+				console.log(one, two)
+			`,
+			"/b.js": `
+				import { one } from "./lib/one.js"
+				console.log(one, import("./c.js"))
+			`,
+			"/c.js": `
+				import { two } from "./lib/two.js"
+				console.log(two)
+			`,
+			"/lib/one.js": `export let one = 1`,
+			"/lib/two.js": `export let two = 2`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			ManualChunkCallback: func(path logger.Path) string {
+				if strings.Contains(path.Text, "lib") {
+					return "lib"
+				}
+				return ""
+			},
+			// The callback takes precedence over the patterns
+			ManualChunks: []config.ManualChunk{
+				{Name: "unused", Patterns: []config.WildcardPattern{{Prefix: "lib/"}}},
+			},
+		},
+	})
+}
+
+func TestSplittingManualChunksInvalidName(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js":      `import "./shared.js"`,
+			"/b.js":      `import "./shared.js"`,
+			"/shared.js": `console.log("shared")`,
+		},
+		entryPaths: []string{"/a.js", "/b.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			ManualChunkCallback: func(path logger.Path) string {
+				return "../escape"
+			},
+		},
+		expectedCompileLog: `ERROR: Invalid manual chunk name "../escape" for "shared.js"
+`,
+	})
+}
+
+func TestSplittingMinChunkSize(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import { ab } from "./ab.js"
+				import { abc } from "./abc.js"
+				console.log(ab, abc)
+			`,
+			"/b.js": `
+				import { ab } from "./ab.js"
+				import { bc } from "./bc.js"
+				import { abc } from "./abc.js"
+				console.log(ab, bc, abc)
+			`,
+			"/c.js": `
+				import { bc } from "./bc.js"
+				import { abc } from "./abc.js"
+				console.log(bc, abc)
+			`,
+			"/ab.js":  `export let ab = "ab"`,
+			"/bc.js":  `export let bc = "bc"`,
+			"/abc.js": `export let abc = "abc"`,
+		},
+		entryPaths: []string{"/a.js", "/b.js", "/c.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			MinChunkSize:  1000,
+		},
+	})
+}
+
+func TestSplittingMinChunkSizeSideEffects(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/a.js": `
+				import { ab } from "./ab.js"
+				import "./abc.js"
+				console.log(ab)
+			`,
+			"/b.js": `
+				import { ab } from "./ab.js"
+				import "./abc.js"
+				console.log(ab)
+			`,
+			"/c.js": `
+				import "./abc.js"
+			`,
+			"/ab.js":  `export let ab = "ab"; console.log("side effect")`,
+			"/abc.js": `export function abc() {}`,
+		},
+		entryPaths: []string{"/a.js", "/b.js", "/c.js"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			CodeSplitting: true,
+			OutputFormat:  config.FormatESModule,
+			AbsOutputDir:  "/out",
+			MinChunkSize:  1000,
+		},
+	})
+}
+
+func TestPreserveModules(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/index.js": `
+				import { add } from './utils/math'
+				import { helper } from 'pkg'
+				import './side-effect'
+				export { add }
+				export const run = () => helper(add(1, 2))
+				export const lazy = () => import('./lazy')
+			`,
+			"/src/utils/math.js": `
+				export function add(a, b) { return a + b }
+				export function mul(a, b) { return a * b }
+				function unused() { return 'unused' }
+			`,
+			"/src/side-effect.js": `
+				console.log('side effect')
+			`,
+			"/src/lazy.js": `
+				import { mul } from './utils/math'
+				export default mul(2, 3)
+			`,
+			"/node_modules/pkg/package.json": `{ "main": "lib/index.js" }`,
+			"/node_modules/pkg/lib/index.js": `
+				export { helper } from './helper'
+			`,
+			"/node_modules/pkg/lib/helper.js": `
+				export const helper = x => x * 10
+			`,
+		},
+		entryPaths: []string{"/src/index.js"},
+		options: config.Options{
+			Mode:            config.ModeBundle,
+			CodeSplitting:   true,
+			PreserveModules: true,
+			VendorDir:       "vendor",
+			OutputFormat:    config.FormatESModule,
+			AbsOutputDir:    "/out",
+		},
+	})
+}
+
+func TestPreserveModulesCommonJS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/index.js": `
+				import cjs from './cjs'
+				import { z } from './esm'
+				export const value = cjs.x + require('./other').y + z
+			`,
+			"/src/esm.js": `
+				export const z = 3
+			`,
+			"/src/cjs.js": `
+				exports.x = 1
+			`,
+			"/src/other.js": `
+				module.exports = { y: 2 }
+			`,
+		},
+		entryPaths: []string{"/src/index.js"},
+		options: config.Options{
+			Mode:            config.ModeBundle,
+			CodeSplitting:   true,
+			PreserveModules: true,
+			VendorDir:       "vendor",
+			OutputFormat:    config.FormatESModule,
+			AbsOutputDir:    "/out",
+		},
+	})
+}
+
+func TestPreserveModulesVendorDirAndCSS(t *testing.T) {
+	splitting_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/src/index.js": `
+				import { a } from '@scope/a'
+				import './style.css'
+				console.log(a)
+			`,
+			"/src/style.css": `
+				body { color: red }
+			`,
+			"/node_modules/@scope/a/index.js": `
+				import { b } from 'b'
+				export const a = b + 1
+			`,
+			"/node_modules/@scope/a/node_modules/b/index.js": `
+				export const b = 1
+			`,
+		},
+		entryPaths: []string{"/src/index.js"},
+		options: config.Options{
+			Mode:            config.ModeBundle,
+			CodeSplitting:   true,
+			PreserveModules: true,
+			VendorDir:       "deps/vendor",
+			OutputFormat:    config.FormatCommonJS,
+			AbsOutputDir:    "/out",
+		},
+	})
+}
@@ -154,6 +154,7 @@ func (s *suite) __expectBundledImpl(t *testing.T, args bundled, fsKind fs.MockKi
 			args.options.AbsOutputBase = unix2win(args.options.AbsOutputBase)
 			args.options.AbsOutputDir = unix2win(args.options.AbsOutputDir)
 			args.options.TSConfigPath = unix2win(args.options.TSConfigPath)
+			args.options.ImportMapPath = unix2win(args.options.ImportMapPath)
 		}
 
 		// Run the bundler
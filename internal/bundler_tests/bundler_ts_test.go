@@ -1202,6 +1202,42 @@ func TestTSExperimentalDecoratorsKeepNames(t *testing.T) {
 	})
 }
 
+func TestTSExperimentalDecoratorsEmitDecoratorMetadata(t *testing.T) {
+	ts_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.ts": `
+				import { Service } from './service'
+				import type { Options } from './options'
+				class Local {}
+				type Id = string
+				@dec
+				export class Foo {
+					constructor(service: Service, options: Options, id: Id) {}
+					@dec local: Local
+					@dec method(@dec x: string, y: number[]): Promise<void> {}
+				}
+			`,
+			"/service.ts": `
+				export class Service {}
+			`,
+			"/options.ts": `
+				export interface Options {}
+			`,
+			"/tsconfig.json": `{
+				"compilerOptions": {
+					"experimentalDecorators": true,
+					"emitDecoratorMetadata": true
+				}
+			}`,
+		},
+		entryPaths: []string{"/entry.ts"},
+		options: config.Options{
+			Mode:          config.ModeBundle,
+			AbsOutputFile: "/out.js",
+		},
+	})
+}
+
 // See: https://github.com/evanw/esbuild/issues/2147
 func TestTSExperimentalDecoratorScopeIssue2147(t *testing.T) {
 	ts_suite.expectBundled(t, bundled{
@@ -2982,3 +3018,63 @@ func TestTSImportInNodeModulesNameCollisionWithCSS(t *testing.T) {
 		},
 	})
 }
+
+func TestTSDeclarationNoBundle(t *testing.T) {
+	ts_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.ts": `
+				import { Options } from './types'
+				import { unused } from './unused'
+				export function run(options: Options, retries = 3): Promise<void> {
+					return Promise.resolve()
+				}
+				export class Runner {
+					constructor(private options: Options) {}
+					start(): void {}
+				}
+			`,
+			"/types.ts": `
+				export interface Options { verbose?: boolean }
+				export const defaults: Options = {}
+			`,
+			"/module.mts": `
+				export const version = '1.0.0'
+			`,
+			"/script.js": `
+				export const ignored = 1
+			`,
+		},
+		entryPaths: []string{"/entry.ts", "/types.ts", "/module.mts", "/script.js"},
+		options: config.Options{
+			Mode:              config.ModePassThrough,
+			AbsOutputDir:      "/out",
+			TSDeclaration:     true,
+			OutputExtensionJS: ".mjs",
+		},
+	})
+}
+
+func TestTSDeclarationNoBundleIsolatedDeclarationsErrors(t *testing.T) {
+	ts_suite.expectBundled(t, bundled{
+		files: map[string]string{
+			"/entry.ts": `
+				export function inferred() { return 1 }
+				export const value = compute()
+				export class Foo {
+					x = compute()
+				}
+				const notExported = compute()
+			`,
+		},
+		entryPaths: []string{"/entry.ts"},
+		options: config.Options{
+			Mode:          config.ModePassThrough,
+			AbsOutputFile: "/out.js",
+			TSDeclaration: true,
+		},
+		expectedScanLog: `entry.ts: ERROR: Function must have an explicit return type annotation with --isolatedDeclarations
+entry.ts: ERROR: Variable must have an explicit type annotation with --isolatedDeclarations
+entry.ts: ERROR: Property must have an explicit type annotation with --isolatedDeclarations
+`,
+	})
+}
@@ -60,6 +60,14 @@ func MakeCacheSet() *CacheSet {
 	}
 }
 
+// This enables the optional persistent cache, which is shared with other
+// esbuild processes. See "DiskCache" for details.
+func (c *CacheSet) SetDiskCache(disk *DiskCache) {
+	c.CSSCache.disk = disk
+	c.JSONCache.disk = disk
+	c.JSCache.disk = disk
+}
+
 type SourceIndexCache struct {
 	globEntries     map[uint64]uint32
 	entries         map[sourceIndexKey]uint32
@@ -28,6 +28,7 @@ import (
 
 type CSSCache struct {
 	entries map[logger.Path]*cssCacheEntry
+	disk    *DiskCache
 	mutex   sync.Mutex
 }
 
@@ -54,10 +55,20 @@ func (c *CSSCache) Parse(log logger.Log, source logger.Source, options css_parse
 		return entry.ast
 	}
 
-	// Cache miss
-	tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
-	ast := css_parser.Parse(tempLog, source, options)
-	msgs := tempLog.Done()
+	// Cache miss (check the persistent cache before parsing)
+	var ast css_ast.AST
+	var msgs []logger.Msg
+	name := c.disk.cssEntryName(source, &options, log.Overrides)
+	if data, ok := c.disk.loadCSS(name, source.Index); ok {
+		ast, msgs = data.AST, data.Msgs
+	} else {
+		tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
+		ast = css_parser.Parse(tempLog, source, options)
+		msgs = tempLog.Done()
+		if name != "" {
+			c.disk.storeCSS(name, source.Index, &cssCacheData{AST: ast, Msgs: msgs})
+		}
+	}
 	for _, msg := range msgs {
 		log.AddMsg(msg)
 	}
@@ -82,6 +93,7 @@ func (c *CSSCache) Parse(log logger.Log, source logger.Source, options css_parse
 
 type JSONCache struct {
 	entries map[logger.Path]*jsonCacheEntry
+	disk    *DiskCache
 	mutex   sync.Mutex
 }
 
@@ -109,10 +121,21 @@ func (c *JSONCache) Parse(log logger.Log, source logger.Source, options js_parse
 		return entry.expr, entry.ok
 	}
 
-	// Cache miss
-	tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
-	expr, ok := js_parser.ParseJSON(tempLog, source, options)
-	msgs := tempLog.Done()
+	// Cache miss (check the persistent cache before parsing)
+	var expr js_ast.Expr
+	var ok bool
+	var msgs []logger.Msg
+	name := c.disk.jsonEntryName(source, options, log.Overrides)
+	if data, hit := c.disk.loadJSON(name, source.Index); hit {
+		expr, ok, msgs = data.Expr, data.OK, data.Msgs
+	} else {
+		tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
+		expr, ok = js_parser.ParseJSON(tempLog, source, options)
+		msgs = tempLog.Done()
+		if name != "" {
+			c.disk.storeJSON(name, source.Index, &jsonCacheData{Expr: expr, OK: ok, Msgs: msgs})
+		}
+	}
 	for _, msg := range msgs {
 		log.AddMsg(msg)
 	}
@@ -138,6 +161,7 @@ func (c *JSONCache) Parse(log logger.Log, source logger.Source, options js_parse
 
 type JSCache struct {
 	entries map[logger.Path]*jsCacheEntry
+	disk    *DiskCache
 	mutex   sync.Mutex
 }
 
@@ -165,10 +189,21 @@ func (c *JSCache) Parse(log logger.Log, source logger.Source, options js_parser.
 		return entry.ast, entry.ok
 	}
 
-	// Cache miss
-	tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
-	ast, ok := js_parser.Parse(tempLog, source, options)
-	msgs := tempLog.Done()
+	// Cache miss (check the persistent cache before parsing)
+	var ast js_ast.AST
+	var ok bool
+	var msgs []logger.Msg
+	name := c.disk.jsEntryName(source, &options, log.Overrides)
+	if data, hit := c.disk.loadJS(name, source.Index); hit {
+		ast, ok, msgs = data.AST, data.OK, data.Msgs
+	} else {
+		tempLog := logger.NewDeferLog(logger.DeferLogAll, log.Overrides)
+		ast, ok = js_parser.Parse(tempLog, source, options)
+		msgs = tempLog.Done()
+		if name != "" {
+			c.disk.storeJS(name, source.Index, &jsCacheData{AST: ast, OK: ok, Msgs: msgs})
+		}
+	}
 	for _, msg := range msgs {
 		log.AddMsg(msg)
 	}
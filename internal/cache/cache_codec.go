@@ -0,0 +1,366 @@
+package cache
+
+// This is a binary serializer for parsed ASTs. It's used by the persistent
+// cache to store ASTs on the file system so that they can be loaded again by
+// a different esbuild process.
+//
+// Go's "encoding/gob" package isn't used because it can't represent the AST:
+// it doesn't support empty structs (e.g. "js_ast.EThis"), it doesn't preserve
+// pointer identity, and it loops forever on cyclic data such as the parent
+// pointers in the scope tree. A reflection-based serializer also isn't used
+// because it turned out to be slower than just parsing the file again.
+//
+// Instead, the code to encode and decode each type is automatically generated
+// by "cache_codec_test.go" into "cache_codec_table.go". That test fails if
+// the generated code is out of date. Run "UPDATE_SNAPSHOTS=1 go test
+// ./internal/cache" to regenerate it after changing any type in the AST.
+//
+// Pointer identity is preserved: each pointer is written either as a new
+// object or as a reference to an object that was already written. This means
+// shared and cyclic pointers survive a round trip.
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+type encoder struct {
+	pointers map[interface{}]uint32
+	err      error
+	buf      []byte
+}
+
+func (e *encoder) fail(value interface{}) {
+	if e.err == nil {
+		e.err = fmt.Errorf("Cannot serialize values of type %T", value)
+	}
+}
+
+func (e *encoder) writeBool(value bool) {
+	if value {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) writeUvarint(value uint64) {
+	var bytes [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, bytes[:binary.PutUvarint(bytes[:], value)]...)
+}
+
+func (e *encoder) writeVarint(value int64) {
+	var bytes [binary.MaxVarintLen64]byte
+	e.buf = append(e.buf, bytes[:binary.PutVarint(bytes[:], value)]...)
+}
+
+func (e *encoder) writeFloat64(value float64) {
+	var bytes [8]byte
+	binary.LittleEndian.PutUint64(bytes[:], math.Float64bits(value))
+	e.buf = append(e.buf, bytes[:]...)
+}
+
+func (e *encoder) writeString(value string) {
+	e.writeUvarint(uint64(len(value)))
+	e.buf = append(e.buf, value...)
+}
+
+// Nil and empty slices and maps are distinguished since some code checks for
+// nil. This writes 0 for nil and the length plus one otherwise.
+func (e *encoder) writeLen(n int, isNil bool) {
+	if isNil {
+		e.writeUvarint(0)
+	} else {
+		e.writeUvarint(uint64(n) + 1)
+	}
+}
+
+// This writes the header for a pointer and returns true if the caller must
+// write the contents of the pointed-to object
+func (e *encoder) writePointer(ptr interface{}, isNil bool) bool {
+	if isNil {
+		e.writeUvarint(0)
+		return false
+	}
+	if id, ok := e.pointers[ptr]; ok {
+		e.writeUvarint(uint64(id) + 2)
+		return false
+	}
+	e.pointers[ptr] = uint32(len(e.pointers))
+	e.writeUvarint(1)
+	return true
+}
+
+var errCorruptCacheEntry = errors.New("Corrupt cache entry")
+
+type decoder struct {
+	buf            []byte
+	str            string
+	pointers       []interface{}
+	pos            int
+	oldSourceIndex uint32
+	newSourceIndex uint32
+}
+
+func (d *decoder) readBool() bool {
+	if d.pos >= len(d.buf) {
+		panic(errCorruptCacheEntry)
+	}
+	c := d.buf[d.pos]
+	d.pos++
+	return c != 0
+}
+
+func (d *decoder) readUvarint() uint64 {
+	// Fast path for small values, which are by far the most common
+	if d.pos < len(d.buf) {
+		if c := d.buf[d.pos]; c < 0x80 {
+			d.pos++
+			return uint64(c)
+		}
+	}
+	value, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		panic(errCorruptCacheEntry)
+	}
+	d.pos += n
+	return value
+}
+
+func (d *decoder) readVarint() int64 {
+	value, n := binary.Varint(d.buf[d.pos:])
+	if n <= 0 {
+		panic(errCorruptCacheEntry)
+	}
+	d.pos += n
+	return value
+}
+
+func (d *decoder) readFloat64() float64 {
+	if len(d.buf)-d.pos < 8 {
+		panic(errCorruptCacheEntry)
+	}
+	value := math.Float64frombits(binary.LittleEndian.Uint64(d.buf[d.pos:]))
+	d.pos += 8
+	return value
+}
+
+// Strings are substrings of a single copy of the input to avoid allocating a
+// new string each time (the parser does something similar with the source)
+func (d *decoder) readString() string {
+	n := d.readUvarint()
+	if n > uint64(len(d.buf)-d.pos) {
+		panic(errCorruptCacheEntry)
+	}
+	text := d.str[d.pos : d.pos+int(n)]
+	d.pos += int(n)
+	return text
+}
+
+// This returns the length of a slice or map, or -1 for nil. Lengths are
+// validated against the remaining input to avoid allocating huge amounts of
+// memory when the input is corrupt.
+func (d *decoder) readLen() int {
+	n := d.readUvarint()
+	if n > uint64(len(d.buf)-d.pos)+1 {
+		panic(errCorruptCacheEntry)
+	}
+	return int(n) - 1
+}
+
+// This returns a previously-decoded pointer if the pointer is a reference. If
+// "isNew" is true, the caller must decode a new object and pass it to
+// "addPointer" before decoding its contents.
+func (d *decoder) readPointer() (ptr interface{}, isNew bool) {
+	switch tag := d.readUvarint(); tag {
+	case 0:
+		return nil, false
+	case 1:
+		return nil, true
+	default:
+		if tag-2 >= uint64(len(d.pointers)) {
+			panic(errCorruptCacheEntry)
+		}
+		return d.pointers[tag-2], false
+	}
+}
+
+func (d *decoder) addPointer(ptr interface{}) {
+	d.pointers = append(d.pointers, ptr)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// These types have unexported fields or need a more compact representation, so
+// the generated code calls these hand-written functions instead.
+
+func encode_ast_Ref(e *encoder, v *ast.Ref) {
+	e.writeUvarint(uint64(v.SourceIndex))
+	e.writeUvarint(uint64(v.InnerIndex))
+}
+
+func decode_ast_Ref(d *decoder, v *ast.Ref) {
+	v.SourceIndex = uint32(d.readUvarint())
+	v.InnerIndex = uint32(d.readUvarint())
+
+	// Symbols are identified by the source index of the file they were
+	// declared in, but source indices are assigned in a different order in
+	// every build. Symbols from the file being loaded are remapped to the
+	// source index that the file has in the current build.
+	if v.SourceIndex == d.oldSourceIndex {
+		v.SourceIndex = d.newSourceIndex
+	}
+}
+
+func encode_ast_Index32(e *encoder, v *ast.Index32) {
+	if v.IsValid() {
+		e.writeUvarint(uint64(v.GetIndex()) + 1)
+	} else {
+		e.writeUvarint(0)
+	}
+}
+
+func decode_ast_Index32(d *decoder, v *ast.Index32) {
+	if index := d.readUvarint(); index != 0 {
+		*v = ast.MakeIndex32(uint32(index - 1))
+	} else {
+		*v = ast.Index32{}
+	}
+}
+
+func encode_logger_ImportAttributes(e *encoder, v *logger.ImportAttributes) {
+	attrs := v.Decode()
+	e.writeUvarint(uint64(len(attrs)))
+	for _, attr := range attrs {
+		e.writeString(attr.Key)
+		e.writeString(attr.Value)
+	}
+}
+
+func decode_logger_ImportAttributes(d *decoder, v *logger.ImportAttributes) {
+	n := d.readLen() + 1
+	if n == 0 {
+		*v = logger.ImportAttributes{}
+		return
+	}
+	attrs := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		key := d.readString()
+		attrs[key] = d.readString()
+	}
+	*v = logger.EncodeImportAttributes(attrs)
+}
+
+// JavaScript strings are stored as UTF-16, so they are very common. They are
+// written as raw little-endian data since that's much faster than a varint
+// for each code unit.
+func encode_Slice_uint16(e *encoder, v *[]uint16) {
+	e.writeLen(len(*v), *v == nil)
+	for _, c := range *v {
+		e.buf = append(e.buf, byte(c), byte(c>>8))
+	}
+}
+
+func decode_Slice_uint16(d *decoder, v *[]uint16) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	if n > (len(d.buf)-d.pos)/2 {
+		panic(errCorruptCacheEntry)
+	}
+	x := make([]uint16, n)
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint16(d.buf[d.pos:])
+		d.pos += 2
+	}
+	*v = x
+}
+
+// Arbitrary values can only be stored if they are nil
+func encode_Any(e *encoder, v *interface{}) {
+	if *v != nil {
+		e.fail(*v)
+	}
+	e.writeUvarint(0)
+}
+
+func decode_Any(d *decoder, v *interface{}) {
+	if d.readUvarint() != 0 {
+		panic(errCorruptCacheEntry)
+	}
+	*v = nil
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+func newEncoder() *encoder {
+	return &encoder{pointers: make(map[interface{}]uint32)}
+}
+
+func newDecoder(buf []byte, oldSourceIndex uint32, newSourceIndex uint32) *decoder {
+	return &decoder{
+		buf:            buf,
+		str:            string(buf),
+		oldSourceIndex: oldSourceIndex,
+		newSourceIndex: newSourceIndex,
+	}
+}
+
+// This turns a panic due to corrupt input into an error
+func (d *decoder) finish(err *error) {
+	if r := recover(); r != nil {
+		if r != errCorruptCacheEntry {
+			panic(r)
+		}
+		*err = errCorruptCacheEntry
+	} else if d.pos != len(d.buf) {
+		*err = errCorruptCacheEntry
+	}
+}
+
+func encodeJSCacheData(value *jsCacheData) ([]byte, error) {
+	e := newEncoder()
+	encode_cache_jsCacheData(e, value)
+	return e.buf, e.err
+}
+
+// Any symbol references that use "oldSourceIndex" are changed to use
+// "newSourceIndex" instead
+func decodeJSCacheData(buf []byte, oldSourceIndex uint32, newSourceIndex uint32) (value jsCacheData, err error) {
+	d := newDecoder(buf, oldSourceIndex, newSourceIndex)
+	defer d.finish(&err)
+	decode_cache_jsCacheData(d, &value)
+	return
+}
+
+func encodeCSSCacheData(value *cssCacheData) ([]byte, error) {
+	e := newEncoder()
+	encode_cache_cssCacheData(e, value)
+	return e.buf, e.err
+}
+
+func decodeCSSCacheData(buf []byte, oldSourceIndex uint32, newSourceIndex uint32) (value cssCacheData, err error) {
+	d := newDecoder(buf, oldSourceIndex, newSourceIndex)
+	defer d.finish(&err)
+	decode_cache_cssCacheData(d, &value)
+	return
+}
+
+func encodeJSONCacheData(value *jsonCacheData) ([]byte, error) {
+	e := newEncoder()
+	encode_cache_jsonCacheData(e, value)
+	return e.buf, e.err
+}
+
+func decodeJSONCacheData(buf []byte, oldSourceIndex uint32, newSourceIndex uint32) (value jsonCacheData, err error) {
+	d := newDecoder(buf, oldSourceIndex, newSourceIndex)
+	defer d.finish(&err)
+	decode_cache_jsonCacheData(d, &value)
+	return
+}
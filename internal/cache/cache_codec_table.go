@@ -0,0 +1,6784 @@
+// This file was automatically generated by "cache_codec_test.go". Do not
+// edit it by hand. Run "UPDATE_SNAPSHOTS=1 go test ./internal/cache" to
+// update it.
+
+package cache
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/css_ast"
+	"github.com/evanw/esbuild/internal/css_lexer"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+const codecSchemaHash = "396f936dfe49439906c4a8bf09b3878e358706b628adc0e8989b8a03163ca7e7"
+
+func encode_Map_ast_Ref_Map_string_js_ast_SymbolUse(e *encoder, v *map[ast.Ref]map[string]js_ast.SymbolUse) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_Map_string_js_ast_SymbolUse(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_Map_string_js_ast_SymbolUse(d *decoder, v *map[ast.Ref]map[string]js_ast.SymbolUse) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]map[string]js_ast.SymbolUse, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value map[string]js_ast.SymbolUse
+		decode_ast_Ref(d, &key)
+		decode_Map_string_js_ast_SymbolUse(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_Map_string_js_ast_TSEnumValue(e *encoder, v *map[ast.Ref]map[string]js_ast.TSEnumValue) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_Map_string_js_ast_TSEnumValue(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_Map_string_js_ast_TSEnumValue(d *decoder, v *map[ast.Ref]map[string]js_ast.TSEnumValue) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]map[string]js_ast.TSEnumValue, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value map[string]js_ast.TSEnumValue
+		decode_ast_Ref(d, &key)
+		decode_Map_string_js_ast_TSEnumValue(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_Ptr_css_ast_Composes(e *encoder, v *map[ast.Ref]*css_ast.Composes) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_Ptr_css_ast_Composes(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_Ptr_css_ast_Composes(d *decoder, v *map[ast.Ref]*css_ast.Composes) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]*css_ast.Composes, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value *css_ast.Composes
+		decode_ast_Ref(d, &key)
+		decode_Ptr_css_ast_Composes(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_Slice_uint32(e *encoder, v *map[ast.Ref][]uint32) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_Slice_uint32(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_Slice_uint32(d *decoder, v *map[ast.Ref][]uint32) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref][]uint32, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value []uint32
+		decode_ast_Ref(d, &key)
+		decode_Slice_uint32(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_js_ast_ConstValue(e *encoder, v *map[ast.Ref]js_ast.ConstValue) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_js_ast_ConstValue(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_js_ast_ConstValue(d *decoder, v *map[ast.Ref]js_ast.ConstValue) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]js_ast.ConstValue, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value js_ast.ConstValue
+		decode_ast_Ref(d, &key)
+		decode_js_ast_ConstValue(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_js_ast_FoldableConst(e *encoder, v *map[ast.Ref]js_ast.FoldableConst) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_js_ast_FoldableConst(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_js_ast_FoldableConst(d *decoder, v *map[ast.Ref]js_ast.FoldableConst) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]js_ast.FoldableConst, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value js_ast.FoldableConst
+		decode_ast_Ref(d, &key)
+		decode_js_ast_FoldableConst(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_js_ast_NamedImport(e *encoder, v *map[ast.Ref]js_ast.NamedImport) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_js_ast_NamedImport(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_js_ast_NamedImport(d *decoder, v *map[ast.Ref]js_ast.NamedImport) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]js_ast.NamedImport, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value js_ast.NamedImport
+		decode_ast_Ref(d, &key)
+		decode_js_ast_NamedImport(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_js_ast_SymbolCallUse(e *encoder, v *map[ast.Ref]js_ast.SymbolCallUse) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_js_ast_SymbolCallUse(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_js_ast_SymbolCallUse(d *decoder, v *map[ast.Ref]js_ast.SymbolCallUse) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]js_ast.SymbolCallUse, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value js_ast.SymbolCallUse
+		decode_ast_Ref(d, &key)
+		decode_js_ast_SymbolCallUse(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_ast_Ref_js_ast_SymbolUse(e *encoder, v *map[ast.Ref]js_ast.SymbolUse) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_ast_Ref(e, &key)
+		encode_js_ast_SymbolUse(e, &value)
+	}
+}
+
+func decode_Map_ast_Ref_js_ast_SymbolUse(d *decoder, v *map[ast.Ref]js_ast.SymbolUse) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[ast.Ref]js_ast.SymbolUse, n)
+	for i := 0; i < n; i++ {
+		var key ast.Ref
+		var value js_ast.SymbolUse
+		decode_ast_Ref(d, &key)
+		decode_js_ast_SymbolUse(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_logger_Loc_Slice_string(e *encoder, v *map[logger.Loc][]string) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		encode_logger_Loc(e, &key)
+		encode_Slice_string(e, &value)
+	}
+}
+
+func decode_Map_logger_Loc_Slice_string(d *decoder, v *map[logger.Loc][]string) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[logger.Loc][]string, n)
+	for i := 0; i < n; i++ {
+		var key logger.Loc
+		var value []string
+		decode_logger_Loc(d, &key)
+		decode_Slice_string(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_ast_LocRef(e *encoder, v *map[string]ast.LocRef) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_ast_LocRef(e, &value)
+	}
+}
+
+func decode_Map_string_ast_LocRef(d *decoder, v *map[string]ast.LocRef) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]ast.LocRef, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value ast.LocRef
+		key = d.readString()
+		decode_ast_LocRef(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_ast_Ref(e *encoder, v *map[string]ast.Ref) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_ast_Ref(e, &value)
+	}
+}
+
+func decode_Map_string_ast_Ref(d *decoder, v *map[string]ast.Ref) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]ast.Ref, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value ast.Ref
+		key = d.readString()
+		decode_ast_Ref(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_bool(e *encoder, v *map[string]bool) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		e.writeBool(bool(value))
+	}
+}
+
+func decode_Map_string_bool(d *decoder, v *map[string]bool) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value bool
+		key = d.readString()
+		value = d.readBool()
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_js_ast_ConstValue(e *encoder, v *map[string]js_ast.ConstValue) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_ConstValue(e, &value)
+	}
+}
+
+func decode_Map_string_js_ast_ConstValue(d *decoder, v *map[string]js_ast.ConstValue) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]js_ast.ConstValue, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.ConstValue
+		key = d.readString()
+		decode_js_ast_ConstValue(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_js_ast_NamedExport(e *encoder, v *map[string]js_ast.NamedExport) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_NamedExport(e, &value)
+	}
+}
+
+func decode_Map_string_js_ast_NamedExport(d *decoder, v *map[string]js_ast.NamedExport) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]js_ast.NamedExport, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.NamedExport
+		key = d.readString()
+		decode_js_ast_NamedExport(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_js_ast_ScopeMember(e *encoder, v *map[string]js_ast.ScopeMember) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_ScopeMember(e, &value)
+	}
+}
+
+func decode_Map_string_js_ast_ScopeMember(d *decoder, v *map[string]js_ast.ScopeMember) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]js_ast.ScopeMember, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.ScopeMember
+		key = d.readString()
+		decode_js_ast_ScopeMember(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_js_ast_SymbolUse(e *encoder, v *map[string]js_ast.SymbolUse) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_SymbolUse(e, &value)
+	}
+}
+
+func decode_Map_string_js_ast_SymbolUse(d *decoder, v *map[string]js_ast.SymbolUse) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]js_ast.SymbolUse, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.SymbolUse
+		key = d.readString()
+		decode_js_ast_SymbolUse(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_js_ast_TSEnumValue(e *encoder, v *map[string]js_ast.TSEnumValue) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_TSEnumValue(e, &value)
+	}
+}
+
+func decode_Map_string_js_ast_TSEnumValue(d *decoder, v *map[string]js_ast.TSEnumValue) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]js_ast.TSEnumValue, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.TSEnumValue
+		key = d.readString()
+		decode_js_ast_TSEnumValue(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Map_string_logger_Loc(e *encoder, v *map[string]logger.Loc) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_logger_Loc(e, &value)
+	}
+}
+
+func decode_Map_string_logger_Loc(d *decoder, v *map[string]logger.Loc) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(map[string]logger.Loc, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value logger.Loc
+		key = d.readString()
+		decode_logger_Loc(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_Ptr_Slice_css_ast_Token(e *encoder, v **[]css_ast.Token) {
+	if e.writePointer(*v, *v == nil) {
+		encode_Slice_css_ast_Token(e, &(**v))
+	}
+}
+
+func decode_Ptr_Slice_css_ast_Token(d *decoder, v **[]css_ast.Token) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new([]css_ast.Token)
+		d.addPointer(x)
+		decode_Slice_css_ast_Token(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*[]css_ast.Token); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_Slice_js_ast_ClauseItem(e *encoder, v **[]js_ast.ClauseItem) {
+	if e.writePointer(*v, *v == nil) {
+		encode_Slice_js_ast_ClauseItem(e, &(**v))
+	}
+}
+
+func decode_Ptr_Slice_js_ast_ClauseItem(d *decoder, v **[]js_ast.ClauseItem) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new([]js_ast.ClauseItem)
+		d.addPointer(x)
+		decode_Slice_js_ast_ClauseItem(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*[]js_ast.ClauseItem); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_ast_CharFreq(e *encoder, v **ast.CharFreq) {
+	if e.writePointer(*v, *v == nil) {
+		encode_ast_CharFreq(e, &(**v))
+	}
+}
+
+func decode_Ptr_ast_CharFreq(d *decoder, v **ast.CharFreq) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(ast.CharFreq)
+		d.addPointer(x)
+		decode_ast_CharFreq(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*ast.CharFreq); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_ast_GlobPattern(e *encoder, v **ast.GlobPattern) {
+	if e.writePointer(*v, *v == nil) {
+		encode_ast_GlobPattern(e, &(**v))
+	}
+}
+
+func decode_Ptr_ast_GlobPattern(d *decoder, v **ast.GlobPattern) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(ast.GlobPattern)
+		d.addPointer(x)
+		decode_ast_GlobPattern(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*ast.GlobPattern); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_ast_ImportAssertOrWith(e *encoder, v **ast.ImportAssertOrWith) {
+	if e.writePointer(*v, *v == nil) {
+		encode_ast_ImportAssertOrWith(e, &(**v))
+	}
+}
+
+func decode_Ptr_ast_ImportAssertOrWith(d *decoder, v **ast.ImportAssertOrWith) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(ast.ImportAssertOrWith)
+		d.addPointer(x)
+		decode_ast_ImportAssertOrWith(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*ast.ImportAssertOrWith); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_ast_LocRef(e *encoder, v **ast.LocRef) {
+	if e.writePointer(*v, *v == nil) {
+		encode_ast_LocRef(e, &(**v))
+	}
+}
+
+func decode_Ptr_ast_LocRef(d *decoder, v **ast.LocRef) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(ast.LocRef)
+		d.addPointer(x)
+		decode_ast_LocRef(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*ast.LocRef); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_ast_NamespaceAlias(e *encoder, v **ast.NamespaceAlias) {
+	if e.writePointer(*v, *v == nil) {
+		encode_ast_NamespaceAlias(e, &(**v))
+	}
+}
+
+func decode_Ptr_ast_NamespaceAlias(d *decoder, v **ast.NamespaceAlias) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(ast.NamespaceAlias)
+		d.addPointer(x)
+		decode_ast_NamespaceAlias(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*ast.NamespaceAlias); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_Composes(e *encoder, v **css_ast.Composes) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_Composes(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_Composes(d *decoder, v **css_ast.Composes) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.Composes)
+		d.addPointer(x)
+		decode_css_ast_Composes(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.Composes); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_ImportConditions(e *encoder, v **css_ast.ImportConditions) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_ImportConditions(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_ImportConditions(d *decoder, v **css_ast.ImportConditions) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.ImportConditions)
+		d.addPointer(x)
+		decode_css_ast_ImportConditions(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.ImportConditions); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_NameToken(e *encoder, v **css_ast.NameToken) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_NameToken(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_NameToken(d *decoder, v **css_ast.NameToken) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.NameToken)
+		d.addPointer(x)
+		decode_css_ast_NameToken(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.NameToken); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_NamespacedName(e *encoder, v **css_ast.NamespacedName) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_NamespacedName(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_NamespacedName(d *decoder, v **css_ast.NamespacedName) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.NamespacedName)
+		d.addPointer(x)
+		decode_css_ast_NamespacedName(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.NamespacedName); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RAtCharset(e *encoder, v **css_ast.RAtCharset) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RAtCharset(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RAtCharset(d *decoder, v **css_ast.RAtCharset) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RAtCharset)
+		d.addPointer(x)
+		decode_css_ast_RAtCharset(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RAtCharset); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RAtImport(e *encoder, v **css_ast.RAtImport) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RAtImport(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RAtImport(d *decoder, v **css_ast.RAtImport) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RAtImport)
+		d.addPointer(x)
+		decode_css_ast_RAtImport(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RAtImport); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RAtKeyframes(e *encoder, v **css_ast.RAtKeyframes) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RAtKeyframes(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RAtKeyframes(d *decoder, v **css_ast.RAtKeyframes) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RAtKeyframes)
+		d.addPointer(x)
+		decode_css_ast_RAtKeyframes(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RAtKeyframes); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RAtLayer(e *encoder, v **css_ast.RAtLayer) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RAtLayer(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RAtLayer(d *decoder, v **css_ast.RAtLayer) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RAtLayer)
+		d.addPointer(x)
+		decode_css_ast_RAtLayer(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RAtLayer); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RBadDeclaration(e *encoder, v **css_ast.RBadDeclaration) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RBadDeclaration(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RBadDeclaration(d *decoder, v **css_ast.RBadDeclaration) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RBadDeclaration)
+		d.addPointer(x)
+		decode_css_ast_RBadDeclaration(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RBadDeclaration); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RComment(e *encoder, v **css_ast.RComment) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RComment(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RComment(d *decoder, v **css_ast.RComment) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RComment)
+		d.addPointer(x)
+		decode_css_ast_RComment(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RComment); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RDeclaration(e *encoder, v **css_ast.RDeclaration) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RDeclaration(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RDeclaration(d *decoder, v **css_ast.RDeclaration) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RDeclaration)
+		d.addPointer(x)
+		decode_css_ast_RDeclaration(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RDeclaration); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RKnownAt(e *encoder, v **css_ast.RKnownAt) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RKnownAt(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RKnownAt(d *decoder, v **css_ast.RKnownAt) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RKnownAt)
+		d.addPointer(x)
+		decode_css_ast_RKnownAt(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RKnownAt); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RQualified(e *encoder, v **css_ast.RQualified) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RQualified(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RQualified(d *decoder, v **css_ast.RQualified) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RQualified)
+		d.addPointer(x)
+		decode_css_ast_RQualified(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RQualified); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RSelector(e *encoder, v **css_ast.RSelector) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RSelector(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RSelector(d *decoder, v **css_ast.RSelector) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RSelector)
+		d.addPointer(x)
+		decode_css_ast_RSelector(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RSelector); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_RUnknownAt(e *encoder, v **css_ast.RUnknownAt) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_RUnknownAt(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_RUnknownAt(d *decoder, v **css_ast.RUnknownAt) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.RUnknownAt)
+		d.addPointer(x)
+		decode_css_ast_RUnknownAt(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.RUnknownAt); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_SSAttribute(e *encoder, v **css_ast.SSAttribute) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_SSAttribute(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_SSAttribute(d *decoder, v **css_ast.SSAttribute) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.SSAttribute)
+		d.addPointer(x)
+		decode_css_ast_SSAttribute(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.SSAttribute); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_SSClass(e *encoder, v **css_ast.SSClass) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_SSClass(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_SSClass(d *decoder, v **css_ast.SSClass) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.SSClass)
+		d.addPointer(x)
+		decode_css_ast_SSClass(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.SSClass); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_SSHash(e *encoder, v **css_ast.SSHash) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_SSHash(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_SSHash(d *decoder, v **css_ast.SSHash) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.SSHash)
+		d.addPointer(x)
+		decode_css_ast_SSHash(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.SSHash); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_SSPseudoClass(e *encoder, v **css_ast.SSPseudoClass) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_SSPseudoClass(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_SSPseudoClass(d *decoder, v **css_ast.SSPseudoClass) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.SSPseudoClass)
+		d.addPointer(x)
+		decode_css_ast_SSPseudoClass(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.SSPseudoClass); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_css_ast_SSPseudoClassWithSelectorList(e *encoder, v **css_ast.SSPseudoClassWithSelectorList) {
+	if e.writePointer(*v, *v == nil) {
+		encode_css_ast_SSPseudoClassWithSelectorList(e, &(**v))
+	}
+}
+
+func decode_Ptr_css_ast_SSPseudoClassWithSelectorList(d *decoder, v **css_ast.SSPseudoClassWithSelectorList) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(css_ast.SSPseudoClassWithSelectorList)
+		d.addPointer(x)
+		decode_css_ast_SSPseudoClassWithSelectorList(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*css_ast.SSPseudoClassWithSelectorList); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_BArray(e *encoder, v **js_ast.BArray) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_BArray(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_BArray(d *decoder, v **js_ast.BArray) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.BArray)
+		d.addPointer(x)
+		decode_js_ast_BArray(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.BArray); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_BIdentifier(e *encoder, v **js_ast.BIdentifier) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_BIdentifier(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_BIdentifier(d *decoder, v **js_ast.BIdentifier) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.BIdentifier)
+		d.addPointer(x)
+		decode_js_ast_BIdentifier(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.BIdentifier); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_BMissing(e *encoder, v **js_ast.BMissing) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_BMissing(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_BMissing(d *decoder, v **js_ast.BMissing) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.BMissing)
+		d.addPointer(x)
+		decode_js_ast_BMissing(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.BMissing); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_BObject(e *encoder, v **js_ast.BObject) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_BObject(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_BObject(d *decoder, v **js_ast.BObject) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.BObject)
+		d.addPointer(x)
+		decode_js_ast_BObject(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.BObject); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_Catch(e *encoder, v **js_ast.Catch) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_Catch(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_Catch(d *decoder, v **js_ast.Catch) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.Catch)
+		d.addPointer(x)
+		decode_js_ast_Catch(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.Catch); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ClassStaticBlock(e *encoder, v **js_ast.ClassStaticBlock) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ClassStaticBlock(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ClassStaticBlock(d *decoder, v **js_ast.ClassStaticBlock) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ClassStaticBlock)
+		d.addPointer(x)
+		decode_js_ast_ClassStaticBlock(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ClassStaticBlock); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EAnnotation(e *encoder, v **js_ast.EAnnotation) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EAnnotation(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EAnnotation(d *decoder, v **js_ast.EAnnotation) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EAnnotation)
+		d.addPointer(x)
+		decode_js_ast_EAnnotation(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EAnnotation); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EArray(e *encoder, v **js_ast.EArray) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EArray(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EArray(d *decoder, v **js_ast.EArray) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EArray)
+		d.addPointer(x)
+		decode_js_ast_EArray(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EArray); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EArrow(e *encoder, v **js_ast.EArrow) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EArrow(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EArrow(d *decoder, v **js_ast.EArrow) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EArrow)
+		d.addPointer(x)
+		decode_js_ast_EArrow(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EArrow); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EAwait(e *encoder, v **js_ast.EAwait) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EAwait(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EAwait(d *decoder, v **js_ast.EAwait) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EAwait)
+		d.addPointer(x)
+		decode_js_ast_EAwait(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EAwait); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EBigInt(e *encoder, v **js_ast.EBigInt) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EBigInt(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EBigInt(d *decoder, v **js_ast.EBigInt) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EBigInt)
+		d.addPointer(x)
+		decode_js_ast_EBigInt(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EBigInt); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EBinary(e *encoder, v **js_ast.EBinary) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EBinary(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EBinary(d *decoder, v **js_ast.EBinary) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EBinary)
+		d.addPointer(x)
+		decode_js_ast_EBinary(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EBinary); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EBoolean(e *encoder, v **js_ast.EBoolean) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EBoolean(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EBoolean(d *decoder, v **js_ast.EBoolean) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EBoolean)
+		d.addPointer(x)
+		decode_js_ast_EBoolean(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EBoolean); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ECall(e *encoder, v **js_ast.ECall) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ECall(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ECall(d *decoder, v **js_ast.ECall) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ECall)
+		d.addPointer(x)
+		decode_js_ast_ECall(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ECall); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EClass(e *encoder, v **js_ast.EClass) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EClass(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EClass(d *decoder, v **js_ast.EClass) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EClass)
+		d.addPointer(x)
+		decode_js_ast_EClass(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EClass); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EDot(e *encoder, v **js_ast.EDot) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EDot(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EDot(d *decoder, v **js_ast.EDot) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EDot)
+		d.addPointer(x)
+		decode_js_ast_EDot(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EDot); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EFunction(e *encoder, v **js_ast.EFunction) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EFunction(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EFunction(d *decoder, v **js_ast.EFunction) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EFunction)
+		d.addPointer(x)
+		decode_js_ast_EFunction(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EFunction); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EIdentifier(e *encoder, v **js_ast.EIdentifier) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EIdentifier(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EIdentifier(d *decoder, v **js_ast.EIdentifier) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EIdentifier)
+		d.addPointer(x)
+		decode_js_ast_EIdentifier(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EIdentifier); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EIf(e *encoder, v **js_ast.EIf) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EIf(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EIf(d *decoder, v **js_ast.EIf) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EIf)
+		d.addPointer(x)
+		decode_js_ast_EIf(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EIf); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EImportCall(e *encoder, v **js_ast.EImportCall) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EImportCall(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EImportCall(d *decoder, v **js_ast.EImportCall) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EImportCall)
+		d.addPointer(x)
+		decode_js_ast_EImportCall(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EImportCall); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EImportIdentifier(e *encoder, v **js_ast.EImportIdentifier) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EImportIdentifier(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EImportIdentifier(d *decoder, v **js_ast.EImportIdentifier) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EImportIdentifier)
+		d.addPointer(x)
+		decode_js_ast_EImportIdentifier(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EImportIdentifier); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EImportMeta(e *encoder, v **js_ast.EImportMeta) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EImportMeta(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EImportMeta(d *decoder, v **js_ast.EImportMeta) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EImportMeta)
+		d.addPointer(x)
+		decode_js_ast_EImportMeta(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EImportMeta); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EImportPathString(e *encoder, v **js_ast.EImportPathString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EImportPathString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EImportPathString(d *decoder, v **js_ast.EImportPathString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EImportPathString)
+		d.addPointer(x)
+		decode_js_ast_EImportPathString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EImportPathString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EImportString(e *encoder, v **js_ast.EImportString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EImportString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EImportString(d *decoder, v **js_ast.EImportString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EImportString)
+		d.addPointer(x)
+		decode_js_ast_EImportString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EImportString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EIndex(e *encoder, v **js_ast.EIndex) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EIndex(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EIndex(d *decoder, v **js_ast.EIndex) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EIndex)
+		d.addPointer(x)
+		decode_js_ast_EIndex(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EIndex); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EInlinedEnum(e *encoder, v **js_ast.EInlinedEnum) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EInlinedEnum(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EInlinedEnum(d *decoder, v **js_ast.EInlinedEnum) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EInlinedEnum)
+		d.addPointer(x)
+		decode_js_ast_EInlinedEnum(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EInlinedEnum); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EJSXElement(e *encoder, v **js_ast.EJSXElement) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EJSXElement(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EJSXElement(d *decoder, v **js_ast.EJSXElement) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EJSXElement)
+		d.addPointer(x)
+		decode_js_ast_EJSXElement(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EJSXElement); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EJSXText(e *encoder, v **js_ast.EJSXText) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EJSXText(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EJSXText(d *decoder, v **js_ast.EJSXText) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EJSXText)
+		d.addPointer(x)
+		decode_js_ast_EJSXText(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EJSXText); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EMissing(e *encoder, v **js_ast.EMissing) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EMissing(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EMissing(d *decoder, v **js_ast.EMissing) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EMissing)
+		d.addPointer(x)
+		decode_js_ast_EMissing(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EMissing); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ENameOfSymbol(e *encoder, v **js_ast.ENameOfSymbol) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ENameOfSymbol(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ENameOfSymbol(d *decoder, v **js_ast.ENameOfSymbol) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ENameOfSymbol)
+		d.addPointer(x)
+		decode_js_ast_ENameOfSymbol(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ENameOfSymbol); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ENew(e *encoder, v **js_ast.ENew) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ENew(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ENew(d *decoder, v **js_ast.ENew) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ENew)
+		d.addPointer(x)
+		decode_js_ast_ENew(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ENew); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ENewTarget(e *encoder, v **js_ast.ENewTarget) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ENewTarget(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ENewTarget(d *decoder, v **js_ast.ENewTarget) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ENewTarget)
+		d.addPointer(x)
+		decode_js_ast_ENewTarget(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ENewTarget); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ENull(e *encoder, v **js_ast.ENull) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ENull(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ENull(d *decoder, v **js_ast.ENull) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ENull)
+		d.addPointer(x)
+		decode_js_ast_ENull(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ENull); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ENumber(e *encoder, v **js_ast.ENumber) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ENumber(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ENumber(d *decoder, v **js_ast.ENumber) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ENumber)
+		d.addPointer(x)
+		decode_js_ast_ENumber(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ENumber); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EObject(e *encoder, v **js_ast.EObject) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EObject(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EObject(d *decoder, v **js_ast.EObject) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EObject)
+		d.addPointer(x)
+		decode_js_ast_EObject(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EObject); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EPrivateIdentifier(e *encoder, v **js_ast.EPrivateIdentifier) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EPrivateIdentifier(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EPrivateIdentifier(d *decoder, v **js_ast.EPrivateIdentifier) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EPrivateIdentifier)
+		d.addPointer(x)
+		decode_js_ast_EPrivateIdentifier(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EPrivateIdentifier); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ERegExp(e *encoder, v **js_ast.ERegExp) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ERegExp(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ERegExp(d *decoder, v **js_ast.ERegExp) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ERegExp)
+		d.addPointer(x)
+		decode_js_ast_ERegExp(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ERegExp); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ERequireResolveString(e *encoder, v **js_ast.ERequireResolveString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ERequireResolveString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ERequireResolveString(d *decoder, v **js_ast.ERequireResolveString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ERequireResolveString)
+		d.addPointer(x)
+		decode_js_ast_ERequireResolveString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ERequireResolveString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ERequireString(e *encoder, v **js_ast.ERequireString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ERequireString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ERequireString(d *decoder, v **js_ast.ERequireString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ERequireString)
+		d.addPointer(x)
+		decode_js_ast_ERequireString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ERequireString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ESpread(e *encoder, v **js_ast.ESpread) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ESpread(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ESpread(d *decoder, v **js_ast.ESpread) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ESpread)
+		d.addPointer(x)
+		decode_js_ast_ESpread(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ESpread); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EString(e *encoder, v **js_ast.EString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EString(d *decoder, v **js_ast.EString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EString)
+		d.addPointer(x)
+		decode_js_ast_EString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ESuper(e *encoder, v **js_ast.ESuper) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ESuper(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ESuper(d *decoder, v **js_ast.ESuper) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ESuper)
+		d.addPointer(x)
+		decode_js_ast_ESuper(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ESuper); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ETemplate(e *encoder, v **js_ast.ETemplate) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ETemplate(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ETemplate(d *decoder, v **js_ast.ETemplate) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ETemplate)
+		d.addPointer(x)
+		decode_js_ast_ETemplate(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ETemplate); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EThis(e *encoder, v **js_ast.EThis) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EThis(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EThis(d *decoder, v **js_ast.EThis) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EThis)
+		d.addPointer(x)
+		decode_js_ast_EThis(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EThis); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EUnary(e *encoder, v **js_ast.EUnary) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EUnary(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EUnary(d *decoder, v **js_ast.EUnary) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EUnary)
+		d.addPointer(x)
+		decode_js_ast_EUnary(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EUnary); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EUndefined(e *encoder, v **js_ast.EUndefined) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EUndefined(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EUndefined(d *decoder, v **js_ast.EUndefined) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EUndefined)
+		d.addPointer(x)
+		decode_js_ast_EUndefined(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EUndefined); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_EYield(e *encoder, v **js_ast.EYield) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_EYield(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_EYield(d *decoder, v **js_ast.EYield) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.EYield)
+		d.addPointer(x)
+		decode_js_ast_EYield(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.EYield); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_ExportStarAlias(e *encoder, v **js_ast.ExportStarAlias) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_ExportStarAlias(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_ExportStarAlias(d *decoder, v **js_ast.ExportStarAlias) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.ExportStarAlias)
+		d.addPointer(x)
+		decode_js_ast_ExportStarAlias(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.ExportStarAlias); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_Finally(e *encoder, v **js_ast.Finally) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_Finally(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_Finally(d *decoder, v **js_ast.Finally) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.Finally)
+		d.addPointer(x)
+		decode_js_ast_Finally(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.Finally); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SBlock(e *encoder, v **js_ast.SBlock) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SBlock(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SBlock(d *decoder, v **js_ast.SBlock) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SBlock)
+		d.addPointer(x)
+		decode_js_ast_SBlock(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SBlock); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SBreak(e *encoder, v **js_ast.SBreak) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SBreak(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SBreak(d *decoder, v **js_ast.SBreak) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SBreak)
+		d.addPointer(x)
+		decode_js_ast_SBreak(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SBreak); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SClass(e *encoder, v **js_ast.SClass) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SClass(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SClass(d *decoder, v **js_ast.SClass) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SClass)
+		d.addPointer(x)
+		decode_js_ast_SClass(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SClass); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SComment(e *encoder, v **js_ast.SComment) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SComment(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SComment(d *decoder, v **js_ast.SComment) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SComment)
+		d.addPointer(x)
+		decode_js_ast_SComment(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SComment); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SContinue(e *encoder, v **js_ast.SContinue) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SContinue(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SContinue(d *decoder, v **js_ast.SContinue) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SContinue)
+		d.addPointer(x)
+		decode_js_ast_SContinue(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SContinue); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SDebugger(e *encoder, v **js_ast.SDebugger) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SDebugger(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SDebugger(d *decoder, v **js_ast.SDebugger) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SDebugger)
+		d.addPointer(x)
+		decode_js_ast_SDebugger(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SDebugger); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SDirective(e *encoder, v **js_ast.SDirective) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SDirective(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SDirective(d *decoder, v **js_ast.SDirective) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SDirective)
+		d.addPointer(x)
+		decode_js_ast_SDirective(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SDirective); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SDoWhile(e *encoder, v **js_ast.SDoWhile) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SDoWhile(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SDoWhile(d *decoder, v **js_ast.SDoWhile) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SDoWhile)
+		d.addPointer(x)
+		decode_js_ast_SDoWhile(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SDoWhile); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SEmpty(e *encoder, v **js_ast.SEmpty) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SEmpty(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SEmpty(d *decoder, v **js_ast.SEmpty) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SEmpty)
+		d.addPointer(x)
+		decode_js_ast_SEmpty(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SEmpty); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SEnum(e *encoder, v **js_ast.SEnum) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SEnum(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SEnum(d *decoder, v **js_ast.SEnum) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SEnum)
+		d.addPointer(x)
+		decode_js_ast_SEnum(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SEnum); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExportClause(e *encoder, v **js_ast.SExportClause) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExportClause(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExportClause(d *decoder, v **js_ast.SExportClause) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExportClause)
+		d.addPointer(x)
+		decode_js_ast_SExportClause(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExportClause); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExportDefault(e *encoder, v **js_ast.SExportDefault) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExportDefault(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExportDefault(d *decoder, v **js_ast.SExportDefault) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExportDefault)
+		d.addPointer(x)
+		decode_js_ast_SExportDefault(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExportDefault); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExportEquals(e *encoder, v **js_ast.SExportEquals) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExportEquals(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExportEquals(d *decoder, v **js_ast.SExportEquals) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExportEquals)
+		d.addPointer(x)
+		decode_js_ast_SExportEquals(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExportEquals); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExportFrom(e *encoder, v **js_ast.SExportFrom) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExportFrom(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExportFrom(d *decoder, v **js_ast.SExportFrom) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExportFrom)
+		d.addPointer(x)
+		decode_js_ast_SExportFrom(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExportFrom); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExportStar(e *encoder, v **js_ast.SExportStar) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExportStar(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExportStar(d *decoder, v **js_ast.SExportStar) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExportStar)
+		d.addPointer(x)
+		decode_js_ast_SExportStar(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExportStar); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SExpr(e *encoder, v **js_ast.SExpr) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SExpr(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SExpr(d *decoder, v **js_ast.SExpr) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SExpr)
+		d.addPointer(x)
+		decode_js_ast_SExpr(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SExpr); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SFor(e *encoder, v **js_ast.SFor) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SFor(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SFor(d *decoder, v **js_ast.SFor) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SFor)
+		d.addPointer(x)
+		decode_js_ast_SFor(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SFor); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SForIn(e *encoder, v **js_ast.SForIn) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SForIn(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SForIn(d *decoder, v **js_ast.SForIn) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SForIn)
+		d.addPointer(x)
+		decode_js_ast_SForIn(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SForIn); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SForOf(e *encoder, v **js_ast.SForOf) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SForOf(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SForOf(d *decoder, v **js_ast.SForOf) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SForOf)
+		d.addPointer(x)
+		decode_js_ast_SForOf(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SForOf); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SFunction(e *encoder, v **js_ast.SFunction) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SFunction(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SFunction(d *decoder, v **js_ast.SFunction) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SFunction)
+		d.addPointer(x)
+		decode_js_ast_SFunction(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SFunction); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SIf(e *encoder, v **js_ast.SIf) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SIf(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SIf(d *decoder, v **js_ast.SIf) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SIf)
+		d.addPointer(x)
+		decode_js_ast_SIf(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SIf); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SImport(e *encoder, v **js_ast.SImport) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SImport(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SImport(d *decoder, v **js_ast.SImport) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SImport)
+		d.addPointer(x)
+		decode_js_ast_SImport(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SImport); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SLabel(e *encoder, v **js_ast.SLabel) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SLabel(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SLabel(d *decoder, v **js_ast.SLabel) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SLabel)
+		d.addPointer(x)
+		decode_js_ast_SLabel(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SLabel); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SLazyExport(e *encoder, v **js_ast.SLazyExport) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SLazyExport(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SLazyExport(d *decoder, v **js_ast.SLazyExport) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SLazyExport)
+		d.addPointer(x)
+		decode_js_ast_SLazyExport(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SLazyExport); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SLocal(e *encoder, v **js_ast.SLocal) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SLocal(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SLocal(d *decoder, v **js_ast.SLocal) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SLocal)
+		d.addPointer(x)
+		decode_js_ast_SLocal(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SLocal); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SNamespace(e *encoder, v **js_ast.SNamespace) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SNamespace(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SNamespace(d *decoder, v **js_ast.SNamespace) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SNamespace)
+		d.addPointer(x)
+		decode_js_ast_SNamespace(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SNamespace); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SReturn(e *encoder, v **js_ast.SReturn) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SReturn(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SReturn(d *decoder, v **js_ast.SReturn) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SReturn)
+		d.addPointer(x)
+		decode_js_ast_SReturn(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SReturn); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SSwitch(e *encoder, v **js_ast.SSwitch) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SSwitch(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SSwitch(d *decoder, v **js_ast.SSwitch) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SSwitch)
+		d.addPointer(x)
+		decode_js_ast_SSwitch(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SSwitch); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SThrow(e *encoder, v **js_ast.SThrow) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SThrow(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SThrow(d *decoder, v **js_ast.SThrow) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SThrow)
+		d.addPointer(x)
+		decode_js_ast_SThrow(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SThrow); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_STry(e *encoder, v **js_ast.STry) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_STry(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_STry(d *decoder, v **js_ast.STry) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.STry)
+		d.addPointer(x)
+		decode_js_ast_STry(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.STry); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_STypeScript(e *encoder, v **js_ast.STypeScript) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_STypeScript(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_STypeScript(d *decoder, v **js_ast.STypeScript) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.STypeScript)
+		d.addPointer(x)
+		decode_js_ast_STypeScript(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.STypeScript); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SWhile(e *encoder, v **js_ast.SWhile) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SWhile(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SWhile(d *decoder, v **js_ast.SWhile) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SWhile)
+		d.addPointer(x)
+		decode_js_ast_SWhile(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SWhile); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_SWith(e *encoder, v **js_ast.SWith) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_SWith(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_SWith(d *decoder, v **js_ast.SWith) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.SWith)
+		d.addPointer(x)
+		decode_js_ast_SWith(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.SWith); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_Scope(e *encoder, v **js_ast.Scope) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_Scope(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_Scope(d *decoder, v **js_ast.Scope) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.Scope)
+		d.addPointer(x)
+		decode_js_ast_Scope(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.Scope); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSDecoratorMetadata(e *encoder, v **js_ast.TSDecoratorMetadata) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSDecoratorMetadata(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSDecoratorMetadata(d *decoder, v **js_ast.TSDecoratorMetadata) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSDecoratorMetadata)
+		d.addPointer(x)
+		decode_js_ast_TSDecoratorMetadata(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSDecoratorMetadata); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSNamespaceMemberEnumNumber(e *encoder, v **js_ast.TSNamespaceMemberEnumNumber) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSNamespaceMemberEnumNumber(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSNamespaceMemberEnumNumber(d *decoder, v **js_ast.TSNamespaceMemberEnumNumber) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSNamespaceMemberEnumNumber)
+		d.addPointer(x)
+		decode_js_ast_TSNamespaceMemberEnumNumber(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSNamespaceMemberEnumNumber); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSNamespaceMemberEnumString(e *encoder, v **js_ast.TSNamespaceMemberEnumString) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSNamespaceMemberEnumString(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSNamespaceMemberEnumString(d *decoder, v **js_ast.TSNamespaceMemberEnumString) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSNamespaceMemberEnumString)
+		d.addPointer(x)
+		decode_js_ast_TSNamespaceMemberEnumString(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSNamespaceMemberEnumString); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSNamespaceMemberNamespace(e *encoder, v **js_ast.TSNamespaceMemberNamespace) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSNamespaceMemberNamespace(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSNamespaceMemberNamespace(d *decoder, v **js_ast.TSNamespaceMemberNamespace) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSNamespaceMemberNamespace)
+		d.addPointer(x)
+		decode_js_ast_TSNamespaceMemberNamespace(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSNamespaceMemberNamespace); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSNamespaceMemberProperty(e *encoder, v **js_ast.TSNamespaceMemberProperty) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSNamespaceMemberProperty(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSNamespaceMemberProperty(d *decoder, v **js_ast.TSNamespaceMemberProperty) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSNamespaceMemberProperty)
+		d.addPointer(x)
+		decode_js_ast_TSNamespaceMemberProperty(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSNamespaceMemberProperty); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_js_ast_TSNamespaceScope(e *encoder, v **js_ast.TSNamespaceScope) {
+	if e.writePointer(*v, *v == nil) {
+		encode_js_ast_TSNamespaceScope(e, &(**v))
+	}
+}
+
+func decode_Ptr_js_ast_TSNamespaceScope(d *decoder, v **js_ast.TSNamespaceScope) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(js_ast.TSNamespaceScope)
+		d.addPointer(x)
+		decode_js_ast_TSNamespaceScope(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*js_ast.TSNamespaceScope); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_logger_Loc(e *encoder, v **logger.Loc) {
+	if e.writePointer(*v, *v == nil) {
+		encode_logger_Loc(e, &(**v))
+	}
+}
+
+func decode_Ptr_logger_Loc(d *decoder, v **logger.Loc) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(logger.Loc)
+		d.addPointer(x)
+		decode_logger_Loc(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*logger.Loc); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_logger_MsgLocation(e *encoder, v **logger.MsgLocation) {
+	if e.writePointer(*v, *v == nil) {
+		encode_logger_MsgLocation(e, &(**v))
+	}
+}
+
+func decode_Ptr_logger_MsgLocation(d *decoder, v **logger.MsgLocation) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(logger.MsgLocation)
+		d.addPointer(x)
+		decode_logger_MsgLocation(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*logger.MsgLocation); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Ptr_logger_Source(e *encoder, v **logger.Source) {
+	if e.writePointer(*v, *v == nil) {
+		encode_logger_Source(e, &(**v))
+	}
+}
+
+func decode_Ptr_logger_Source(d *decoder, v **logger.Source) {
+	ptr, isNew := d.readPointer()
+	if isNew {
+		x := new(logger.Source)
+		d.addPointer(x)
+		decode_logger_Source(d, &(*x))
+		*v = x
+	} else if ptr == nil {
+		*v = nil
+	} else if x, ok := ptr.(*logger.Source); ok {
+		*v = x
+	} else {
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_Slice_Ptr_js_ast_Scope(e *encoder, v *[]*js_ast.Scope) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_Ptr_js_ast_Scope(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_Ptr_js_ast_Scope(d *decoder, v *[]*js_ast.Scope) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]*js_ast.Scope, n)
+	for i := range x {
+		decode_Ptr_js_ast_Scope(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_Slice_string(e *encoder, v *[][]string) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_Slice_string(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_Slice_string(d *decoder, v *[][]string) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([][]string, n)
+	for i := range x {
+		decode_Slice_string(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_ast_AssertOrWithEntry(e *encoder, v *[]ast.AssertOrWithEntry) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_ast_AssertOrWithEntry(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_ast_AssertOrWithEntry(d *decoder, v *[]ast.AssertOrWithEntry) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]ast.AssertOrWithEntry, n)
+	for i := range x {
+		decode_ast_AssertOrWithEntry(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_ast_ImportRecord(e *encoder, v *[]ast.ImportRecord) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_ast_ImportRecord(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_ast_ImportRecord(d *decoder, v *[]ast.ImportRecord) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]ast.ImportRecord, n)
+	for i := range x {
+		decode_ast_ImportRecord(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_ast_LocRef(e *encoder, v *[]ast.LocRef) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_ast_LocRef(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_ast_LocRef(d *decoder, v *[]ast.LocRef) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]ast.LocRef, n)
+	for i := range x {
+		decode_ast_LocRef(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_ast_Ref(e *encoder, v *[]ast.Ref) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_ast_Ref(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_ast_Ref(d *decoder, v *[]ast.Ref) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]ast.Ref, n)
+	for i := range x {
+		decode_ast_Ref(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_ast_Symbol(e *encoder, v *[]ast.Symbol) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_ast_Symbol(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_ast_Symbol(d *decoder, v *[]ast.Symbol) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]ast.Symbol, n)
+	for i := range x {
+		decode_ast_Symbol(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_ComplexSelector(e *encoder, v *[]css_ast.ComplexSelector) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_ComplexSelector(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_ComplexSelector(d *decoder, v *[]css_ast.ComplexSelector) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.ComplexSelector, n)
+	for i := range x {
+		decode_css_ast_ComplexSelector(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_CompoundSelector(e *encoder, v *[]css_ast.CompoundSelector) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_CompoundSelector(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_CompoundSelector(d *decoder, v *[]css_ast.CompoundSelector) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.CompoundSelector, n)
+	for i := range x {
+		decode_css_ast_CompoundSelector(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_ImportedComposesName(e *encoder, v *[]css_ast.ImportedComposesName) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_ImportedComposesName(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_ImportedComposesName(d *decoder, v *[]css_ast.ImportedComposesName) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.ImportedComposesName, n)
+	for i := range x {
+		decode_css_ast_ImportedComposesName(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_KeyframeBlock(e *encoder, v *[]css_ast.KeyframeBlock) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_KeyframeBlock(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_KeyframeBlock(d *decoder, v *[]css_ast.KeyframeBlock) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.KeyframeBlock, n)
+	for i := range x {
+		decode_css_ast_KeyframeBlock(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_Rule(e *encoder, v *[]css_ast.Rule) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_Rule(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_Rule(d *decoder, v *[]css_ast.Rule) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.Rule, n)
+	for i := range x {
+		decode_css_ast_Rule(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_SubclassSelector(e *encoder, v *[]css_ast.SubclassSelector) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_SubclassSelector(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_SubclassSelector(d *decoder, v *[]css_ast.SubclassSelector) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.SubclassSelector, n)
+	for i := range x {
+		decode_css_ast_SubclassSelector(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_css_ast_Token(e *encoder, v *[]css_ast.Token) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_css_ast_Token(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_css_ast_Token(d *decoder, v *[]css_ast.Token) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]css_ast.Token, n)
+	for i := range x {
+		decode_css_ast_Token(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_helpers_GlobPart(e *encoder, v *[]helpers.GlobPart) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_helpers_GlobPart(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_helpers_GlobPart(d *decoder, v *[]helpers.GlobPart) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]helpers.GlobPart, n)
+	for i := range x {
+		decode_helpers_GlobPart(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Arg(e *encoder, v *[]js_ast.Arg) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Arg(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Arg(d *decoder, v *[]js_ast.Arg) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Arg, n)
+	for i := range x {
+		decode_js_ast_Arg(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_ArrayBinding(e *encoder, v *[]js_ast.ArrayBinding) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_ArrayBinding(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_ArrayBinding(d *decoder, v *[]js_ast.ArrayBinding) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.ArrayBinding, n)
+	for i := range x {
+		decode_js_ast_ArrayBinding(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Case(e *encoder, v *[]js_ast.Case) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Case(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Case(d *decoder, v *[]js_ast.Case) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Case, n)
+	for i := range x {
+		decode_js_ast_Case(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_ClauseItem(e *encoder, v *[]js_ast.ClauseItem) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_ClauseItem(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_ClauseItem(d *decoder, v *[]js_ast.ClauseItem) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.ClauseItem, n)
+	for i := range x {
+		decode_js_ast_ClauseItem(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Decl(e *encoder, v *[]js_ast.Decl) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Decl(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Decl(d *decoder, v *[]js_ast.Decl) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Decl, n)
+	for i := range x {
+		decode_js_ast_Decl(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_DeclaredSymbol(e *encoder, v *[]js_ast.DeclaredSymbol) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_DeclaredSymbol(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_DeclaredSymbol(d *decoder, v *[]js_ast.DeclaredSymbol) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.DeclaredSymbol, n)
+	for i := range x {
+		decode_js_ast_DeclaredSymbol(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Decorator(e *encoder, v *[]js_ast.Decorator) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Decorator(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Decorator(d *decoder, v *[]js_ast.Decorator) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Decorator, n)
+	for i := range x {
+		decode_js_ast_Decorator(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Dependency(e *encoder, v *[]js_ast.Dependency) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Dependency(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Dependency(d *decoder, v *[]js_ast.Dependency) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Dependency, n)
+	for i := range x {
+		decode_js_ast_Dependency(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_EnumValue(e *encoder, v *[]js_ast.EnumValue) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_EnumValue(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_EnumValue(d *decoder, v *[]js_ast.EnumValue) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.EnumValue, n)
+	for i := range x {
+		decode_js_ast_EnumValue(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Expr(e *encoder, v *[]js_ast.Expr) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Expr(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Expr(d *decoder, v *[]js_ast.Expr) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Expr, n)
+	for i := range x {
+		decode_js_ast_Expr(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Part(e *encoder, v *[]js_ast.Part) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Part(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Part(d *decoder, v *[]js_ast.Part) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Part, n)
+	for i := range x {
+		decode_js_ast_Part(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Property(e *encoder, v *[]js_ast.Property) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Property(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Property(d *decoder, v *[]js_ast.Property) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Property, n)
+	for i := range x {
+		decode_js_ast_Property(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_PropertyBinding(e *encoder, v *[]js_ast.PropertyBinding) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_PropertyBinding(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_PropertyBinding(d *decoder, v *[]js_ast.PropertyBinding) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.PropertyBinding, n)
+	for i := range x {
+		decode_js_ast_PropertyBinding(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_ScopeMember(e *encoder, v *[]js_ast.ScopeMember) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_ScopeMember(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_ScopeMember(d *decoder, v *[]js_ast.ScopeMember) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.ScopeMember, n)
+	for i := range x {
+		decode_js_ast_ScopeMember(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_Stmt(e *encoder, v *[]js_ast.Stmt) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_Stmt(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_Stmt(d *decoder, v *[]js_ast.Stmt) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.Stmt, n)
+	for i := range x {
+		decode_js_ast_Stmt(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_TSMetadataType(e *encoder, v *[]js_ast.TSMetadataType) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_TSMetadataType(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_TSMetadataType(d *decoder, v *[]js_ast.TSMetadataType) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.TSMetadataType, n)
+	for i := range x {
+		decode_js_ast_TSMetadataType(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_js_ast_TemplatePart(e *encoder, v *[]js_ast.TemplatePart) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_js_ast_TemplatePart(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_js_ast_TemplatePart(d *decoder, v *[]js_ast.TemplatePart) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]js_ast.TemplatePart, n)
+	for i := range x {
+		decode_js_ast_TemplatePart(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_logger_Msg(e *encoder, v *[]logger.Msg) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_logger_Msg(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_logger_Msg(d *decoder, v *[]logger.Msg) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]logger.Msg, n)
+	for i := range x {
+		decode_logger_Msg(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_logger_MsgData(e *encoder, v *[]logger.MsgData) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		encode_logger_MsgData(e, &(*v)[i])
+	}
+}
+
+func decode_Slice_logger_MsgData(d *decoder, v *[]logger.MsgData) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]logger.MsgData, n)
+	for i := range x {
+		decode_logger_MsgData(d, &x[i])
+	}
+	*v = x
+}
+
+func encode_Slice_string(e *encoder, v *[]string) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		e.writeString(string((*v)[i]))
+	}
+}
+
+func decode_Slice_string(d *decoder, v *[]string) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]string, n)
+	for i := range x {
+		x[i] = d.readString()
+	}
+	*v = x
+}
+
+func encode_Slice_uint32(e *encoder, v *[]uint32) {
+	e.writeLen(len(*v), *v == nil)
+	for i := range *v {
+		e.writeUvarint(uint64((*v)[i]))
+	}
+}
+
+func decode_Slice_uint32(d *decoder, v *[]uint32) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make([]uint32, n)
+	for i := range x {
+		x[i] = uint32(d.readUvarint())
+	}
+	*v = x
+}
+
+func encode_ast_AssertOrWithEntry(e *encoder, v *ast.AssertOrWithEntry) {
+	encode_Slice_uint16(e, &v.Key)
+	encode_Slice_uint16(e, &v.Value)
+	encode_logger_Loc(e, &v.KeyLoc)
+	encode_logger_Loc(e, &v.ValueLoc)
+	e.writeBool(bool(v.PreferQuotedKey))
+}
+
+func decode_ast_AssertOrWithEntry(d *decoder, v *ast.AssertOrWithEntry) {
+	decode_Slice_uint16(d, &v.Key)
+	decode_Slice_uint16(d, &v.Value)
+	decode_logger_Loc(d, &v.KeyLoc)
+	decode_logger_Loc(d, &v.ValueLoc)
+	v.PreferQuotedKey = d.readBool()
+}
+
+func encode_ast_CharFreq(e *encoder, v *ast.CharFreq) {
+	for i := range *v {
+		e.writeVarint(int64((*v)[i]))
+	}
+}
+
+func decode_ast_CharFreq(d *decoder, v *ast.CharFreq) {
+	for i := range *v {
+		(*v)[i] = int32(d.readVarint())
+	}
+}
+
+func encode_ast_GlobPattern(e *encoder, v *ast.GlobPattern) {
+	encode_Slice_helpers_GlobPart(e, &v.Parts)
+	e.writeString(string(v.ExportAlias))
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_ast_GlobPattern(d *decoder, v *ast.GlobPattern) {
+	decode_Slice_helpers_GlobPart(d, &v.Parts)
+	v.ExportAlias = d.readString()
+	v.Kind = ast.ImportKind(d.readUvarint())
+}
+
+func encode_ast_ImportAssertOrWith(e *encoder, v *ast.ImportAssertOrWith) {
+	encode_Slice_ast_AssertOrWithEntry(e, &v.Entries)
+	encode_logger_Loc(e, &v.KeywordLoc)
+	encode_logger_Loc(e, &v.InnerOpenBraceLoc)
+	encode_logger_Loc(e, &v.InnerCloseBraceLoc)
+	encode_logger_Loc(e, &v.OuterOpenBraceLoc)
+	encode_logger_Loc(e, &v.OuterCloseBraceLoc)
+	e.writeUvarint(uint64(v.Keyword))
+}
+
+func decode_ast_ImportAssertOrWith(d *decoder, v *ast.ImportAssertOrWith) {
+	decode_Slice_ast_AssertOrWithEntry(d, &v.Entries)
+	decode_logger_Loc(d, &v.KeywordLoc)
+	decode_logger_Loc(d, &v.InnerOpenBraceLoc)
+	decode_logger_Loc(d, &v.InnerCloseBraceLoc)
+	decode_logger_Loc(d, &v.OuterOpenBraceLoc)
+	decode_logger_Loc(d, &v.OuterCloseBraceLoc)
+	v.Keyword = ast.AssertOrWithKeyword(d.readUvarint())
+}
+
+func encode_ast_ImportRecord(e *encoder, v *ast.ImportRecord) {
+	encode_Ptr_ast_ImportAssertOrWith(e, &v.AssertOrWith)
+	encode_Ptr_ast_GlobPattern(e, &v.GlobPattern)
+	encode_logger_Path(e, &v.Path)
+	encode_logger_Range(e, &v.Range)
+	encode_logger_Loc(e, &v.ErrorHandlerLoc)
+	encode_ast_Index32(e, &v.SourceIndex)
+	encode_ast_Index32(e, &v.CopySourceIndex)
+	e.writeUvarint(uint64(v.Flags))
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_ast_ImportRecord(d *decoder, v *ast.ImportRecord) {
+	decode_Ptr_ast_ImportAssertOrWith(d, &v.AssertOrWith)
+	decode_Ptr_ast_GlobPattern(d, &v.GlobPattern)
+	decode_logger_Path(d, &v.Path)
+	decode_logger_Range(d, &v.Range)
+	decode_logger_Loc(d, &v.ErrorHandlerLoc)
+	decode_ast_Index32(d, &v.SourceIndex)
+	decode_ast_Index32(d, &v.CopySourceIndex)
+	v.Flags = ast.ImportRecordFlags(d.readUvarint())
+	v.Kind = ast.ImportKind(d.readUvarint())
+}
+
+func encode_ast_LocRef(e *encoder, v *ast.LocRef) {
+	encode_logger_Loc(e, &v.Loc)
+	encode_ast_Ref(e, &v.Ref)
+}
+
+func decode_ast_LocRef(d *decoder, v *ast.LocRef) {
+	decode_logger_Loc(d, &v.Loc)
+	decode_ast_Ref(d, &v.Ref)
+}
+
+func encode_ast_NamespaceAlias(e *encoder, v *ast.NamespaceAlias) {
+	e.writeString(string(v.Alias))
+	encode_ast_Ref(e, &v.NamespaceRef)
+}
+
+func decode_ast_NamespaceAlias(d *decoder, v *ast.NamespaceAlias) {
+	v.Alias = d.readString()
+	decode_ast_Ref(d, &v.NamespaceRef)
+}
+
+func encode_ast_SlotCounts(e *encoder, v *ast.SlotCounts) {
+	for i := range *v {
+		e.writeUvarint(uint64((*v)[i]))
+	}
+}
+
+func decode_ast_SlotCounts(d *decoder, v *ast.SlotCounts) {
+	for i := range *v {
+		(*v)[i] = uint32(d.readUvarint())
+	}
+}
+
+func encode_ast_Symbol(e *encoder, v *ast.Symbol) {
+	encode_Ptr_ast_NamespaceAlias(e, &v.NamespaceAlias)
+	e.writeString(string(v.OriginalName))
+	encode_ast_Ref(e, &v.Link)
+	e.writeUvarint(uint64(v.UseCountEstimate))
+	encode_ast_Index32(e, &v.ChunkIndex)
+	encode_ast_Index32(e, &v.NestedScopeSlot)
+	e.writeUvarint(uint64(v.Flags))
+	e.writeUvarint(uint64(v.Kind))
+	e.writeUvarint(uint64(v.ImportItemStatus))
+}
+
+func decode_ast_Symbol(d *decoder, v *ast.Symbol) {
+	decode_Ptr_ast_NamespaceAlias(d, &v.NamespaceAlias)
+	v.OriginalName = d.readString()
+	decode_ast_Ref(d, &v.Link)
+	v.UseCountEstimate = uint32(d.readUvarint())
+	decode_ast_Index32(d, &v.ChunkIndex)
+	decode_ast_Index32(d, &v.NestedScopeSlot)
+	v.Flags = ast.SymbolFlags(d.readUvarint())
+	v.Kind = ast.SymbolKind(d.readUvarint())
+	v.ImportItemStatus = ast.ImportItemStatus(d.readUvarint())
+}
+
+func encode_cache_cssCacheData(e *encoder, v *cssCacheData) {
+	encode_css_ast_AST(e, &v.AST)
+	encode_Slice_logger_Msg(e, &v.Msgs)
+}
+
+func decode_cache_cssCacheData(d *decoder, v *cssCacheData) {
+	decode_css_ast_AST(d, &v.AST)
+	decode_Slice_logger_Msg(d, &v.Msgs)
+}
+
+func encode_cache_jsCacheData(e *encoder, v *jsCacheData) {
+	encode_js_ast_AST(e, &v.AST)
+	encode_Slice_logger_Msg(e, &v.Msgs)
+	e.writeBool(bool(v.OK))
+}
+
+func decode_cache_jsCacheData(d *decoder, v *jsCacheData) {
+	decode_js_ast_AST(d, &v.AST)
+	decode_Slice_logger_Msg(d, &v.Msgs)
+	v.OK = d.readBool()
+}
+
+func encode_cache_jsonCacheData(e *encoder, v *jsonCacheData) {
+	encode_js_ast_Expr(e, &v.Expr)
+	encode_Slice_logger_Msg(e, &v.Msgs)
+	e.writeBool(bool(v.OK))
+}
+
+func decode_cache_jsonCacheData(d *decoder, v *jsonCacheData) {
+	decode_js_ast_Expr(d, &v.Expr)
+	decode_Slice_logger_Msg(d, &v.Msgs)
+	v.OK = d.readBool()
+}
+
+func encode_css_ast_AST(e *encoder, v *css_ast.AST) {
+	encode_Slice_ast_Symbol(e, &v.Symbols)
+	encode_Ptr_ast_CharFreq(e, &v.CharFreq)
+	encode_Slice_ast_ImportRecord(e, &v.ImportRecords)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Span(e, &v.SourceMapComment)
+	e.writeVarint(int64(v.ApproximateLineCount))
+	encode_Slice_ast_LocRef(e, &v.LocalSymbols)
+	encode_Map_string_ast_LocRef(e, &v.LocalScope)
+	encode_Map_string_ast_LocRef(e, &v.GlobalScope)
+	encode_Map_ast_Ref_Ptr_css_ast_Composes(e, &v.Composes)
+	encode_Slice_Slice_string(e, &v.LayersPreImport)
+	encode_Slice_Slice_string(e, &v.LayersPostImport)
+}
+
+func decode_css_ast_AST(d *decoder, v *css_ast.AST) {
+	decode_Slice_ast_Symbol(d, &v.Symbols)
+	decode_Ptr_ast_CharFreq(d, &v.CharFreq)
+	decode_Slice_ast_ImportRecord(d, &v.ImportRecords)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Span(d, &v.SourceMapComment)
+	v.ApproximateLineCount = int32(d.readVarint())
+	decode_Slice_ast_LocRef(d, &v.LocalSymbols)
+	decode_Map_string_ast_LocRef(d, &v.LocalScope)
+	decode_Map_string_ast_LocRef(d, &v.GlobalScope)
+	decode_Map_ast_Ref_Ptr_css_ast_Composes(d, &v.Composes)
+	decode_Slice_Slice_string(d, &v.LayersPreImport)
+	decode_Slice_Slice_string(d, &v.LayersPostImport)
+}
+
+func encode_css_ast_Combinator(e *encoder, v *css_ast.Combinator) {
+	encode_logger_Loc(e, &v.Loc)
+	e.writeUvarint(uint64(v.Byte))
+}
+
+func decode_css_ast_Combinator(d *decoder, v *css_ast.Combinator) {
+	decode_logger_Loc(d, &v.Loc)
+	v.Byte = uint8(d.readUvarint())
+}
+
+func encode_css_ast_ComplexSelector(e *encoder, v *css_ast.ComplexSelector) {
+	encode_Slice_css_ast_CompoundSelector(e, &v.Selectors)
+}
+
+func decode_css_ast_ComplexSelector(d *decoder, v *css_ast.ComplexSelector) {
+	decode_Slice_css_ast_CompoundSelector(d, &v.Selectors)
+}
+
+func encode_css_ast_Composes(e *encoder, v *css_ast.Composes) {
+	encode_Slice_ast_LocRef(e, &v.Names)
+	encode_Slice_css_ast_ImportedComposesName(e, &v.ImportedNames)
+	encode_Map_string_logger_Loc(e, &v.Properties)
+}
+
+func decode_css_ast_Composes(d *decoder, v *css_ast.Composes) {
+	decode_Slice_ast_LocRef(d, &v.Names)
+	decode_Slice_css_ast_ImportedComposesName(d, &v.ImportedNames)
+	decode_Map_string_logger_Loc(d, &v.Properties)
+}
+
+func encode_css_ast_CompoundSelector(e *encoder, v *css_ast.CompoundSelector) {
+	encode_Ptr_css_ast_NamespacedName(e, &v.TypeSelector)
+	encode_Slice_css_ast_SubclassSelector(e, &v.SubclassSelectors)
+	encode_ast_Index32(e, &v.NestingSelectorLoc)
+	encode_css_ast_Combinator(e, &v.Combinator)
+	e.writeBool(bool(v.WasEmptyFromLocalOrGlobal))
+}
+
+func decode_css_ast_CompoundSelector(d *decoder, v *css_ast.CompoundSelector) {
+	decode_Ptr_css_ast_NamespacedName(d, &v.TypeSelector)
+	decode_Slice_css_ast_SubclassSelector(d, &v.SubclassSelectors)
+	decode_ast_Index32(d, &v.NestingSelectorLoc)
+	decode_css_ast_Combinator(d, &v.Combinator)
+	v.WasEmptyFromLocalOrGlobal = d.readBool()
+}
+
+func encode_css_ast_ImportConditions(e *encoder, v *css_ast.ImportConditions) {
+	encode_Slice_css_ast_Token(e, &v.Media)
+	encode_Slice_css_ast_Token(e, &v.Layers)
+	encode_Slice_css_ast_Token(e, &v.Supports)
+}
+
+func decode_css_ast_ImportConditions(d *decoder, v *css_ast.ImportConditions) {
+	decode_Slice_css_ast_Token(d, &v.Media)
+	decode_Slice_css_ast_Token(d, &v.Layers)
+	decode_Slice_css_ast_Token(d, &v.Supports)
+}
+
+func encode_css_ast_ImportedComposesName(e *encoder, v *css_ast.ImportedComposesName) {
+	e.writeString(string(v.Alias))
+	encode_logger_Loc(e, &v.AliasLoc)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+}
+
+func decode_css_ast_ImportedComposesName(d *decoder, v *css_ast.ImportedComposesName) {
+	v.Alias = d.readString()
+	decode_logger_Loc(d, &v.AliasLoc)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+}
+
+func encode_css_ast_KeyframeBlock(e *encoder, v *css_ast.KeyframeBlock) {
+	encode_Slice_string(e, &v.Selectors)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Loc(e, &v.Loc)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_KeyframeBlock(d *decoder, v *css_ast.KeyframeBlock) {
+	decode_Slice_string(d, &v.Selectors)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Loc(d, &v.Loc)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_NameToken(e *encoder, v *css_ast.NameToken) {
+	e.writeString(string(v.Text))
+	encode_logger_Range(e, &v.Range)
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_css_ast_NameToken(d *decoder, v *css_ast.NameToken) {
+	v.Text = d.readString()
+	decode_logger_Range(d, &v.Range)
+	v.Kind = css_lexer.T(d.readUvarint())
+}
+
+func encode_css_ast_NamespacedName(e *encoder, v *css_ast.NamespacedName) {
+	encode_Ptr_css_ast_NameToken(e, &v.NamespacePrefix)
+	encode_css_ast_NameToken(e, &v.Name)
+}
+
+func decode_css_ast_NamespacedName(d *decoder, v *css_ast.NamespacedName) {
+	decode_Ptr_css_ast_NameToken(d, &v.NamespacePrefix)
+	decode_css_ast_NameToken(d, &v.Name)
+}
+
+func encode_css_ast_NthIndex(e *encoder, v *css_ast.NthIndex) {
+	e.writeString(string(v.A))
+	e.writeString(string(v.B))
+}
+
+func decode_css_ast_NthIndex(d *decoder, v *css_ast.NthIndex) {
+	v.A = d.readString()
+	v.B = d.readString()
+}
+
+func encode_css_ast_R(e *encoder, v *css_ast.R) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *css_ast.RAtCharset:
+		e.writeUvarint(1)
+		encode_Ptr_css_ast_RAtCharset(e, &x)
+	case *css_ast.RAtImport:
+		e.writeUvarint(2)
+		encode_Ptr_css_ast_RAtImport(e, &x)
+	case *css_ast.RAtKeyframes:
+		e.writeUvarint(3)
+		encode_Ptr_css_ast_RAtKeyframes(e, &x)
+	case *css_ast.RAtLayer:
+		e.writeUvarint(4)
+		encode_Ptr_css_ast_RAtLayer(e, &x)
+	case *css_ast.RBadDeclaration:
+		e.writeUvarint(5)
+		encode_Ptr_css_ast_RBadDeclaration(e, &x)
+	case *css_ast.RComment:
+		e.writeUvarint(6)
+		encode_Ptr_css_ast_RComment(e, &x)
+	case *css_ast.RDeclaration:
+		e.writeUvarint(7)
+		encode_Ptr_css_ast_RDeclaration(e, &x)
+	case *css_ast.RKnownAt:
+		e.writeUvarint(8)
+		encode_Ptr_css_ast_RKnownAt(e, &x)
+	case *css_ast.RQualified:
+		e.writeUvarint(9)
+		encode_Ptr_css_ast_RQualified(e, &x)
+	case *css_ast.RSelector:
+		e.writeUvarint(10)
+		encode_Ptr_css_ast_RSelector(e, &x)
+	case *css_ast.RUnknownAt:
+		e.writeUvarint(11)
+		encode_Ptr_css_ast_RUnknownAt(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_css_ast_R(d *decoder, v *css_ast.R) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *css_ast.RAtCharset
+		decode_Ptr_css_ast_RAtCharset(d, &x)
+		*v = x
+	case 2:
+		var x *css_ast.RAtImport
+		decode_Ptr_css_ast_RAtImport(d, &x)
+		*v = x
+	case 3:
+		var x *css_ast.RAtKeyframes
+		decode_Ptr_css_ast_RAtKeyframes(d, &x)
+		*v = x
+	case 4:
+		var x *css_ast.RAtLayer
+		decode_Ptr_css_ast_RAtLayer(d, &x)
+		*v = x
+	case 5:
+		var x *css_ast.RBadDeclaration
+		decode_Ptr_css_ast_RBadDeclaration(d, &x)
+		*v = x
+	case 6:
+		var x *css_ast.RComment
+		decode_Ptr_css_ast_RComment(d, &x)
+		*v = x
+	case 7:
+		var x *css_ast.RDeclaration
+		decode_Ptr_css_ast_RDeclaration(d, &x)
+		*v = x
+	case 8:
+		var x *css_ast.RKnownAt
+		decode_Ptr_css_ast_RKnownAt(d, &x)
+		*v = x
+	case 9:
+		var x *css_ast.RQualified
+		decode_Ptr_css_ast_RQualified(d, &x)
+		*v = x
+	case 10:
+		var x *css_ast.RSelector
+		decode_Ptr_css_ast_RSelector(d, &x)
+		*v = x
+	case 11:
+		var x *css_ast.RUnknownAt
+		decode_Ptr_css_ast_RUnknownAt(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_css_ast_RAtCharset(e *encoder, v *css_ast.RAtCharset) {
+	e.writeString(string(v.Encoding))
+}
+
+func decode_css_ast_RAtCharset(d *decoder, v *css_ast.RAtCharset) {
+	v.Encoding = d.readString()
+}
+
+func encode_css_ast_RAtImport(e *encoder, v *css_ast.RAtImport) {
+	encode_Ptr_css_ast_ImportConditions(e, &v.ImportConditions)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+}
+
+func decode_css_ast_RAtImport(d *decoder, v *css_ast.RAtImport) {
+	decode_Ptr_css_ast_ImportConditions(d, &v.ImportConditions)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+}
+
+func encode_css_ast_RAtKeyframes(e *encoder, v *css_ast.RAtKeyframes) {
+	e.writeString(string(v.AtToken))
+	encode_ast_LocRef(e, &v.Name)
+	encode_Slice_css_ast_KeyframeBlock(e, &v.Blocks)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_RAtKeyframes(d *decoder, v *css_ast.RAtKeyframes) {
+	v.AtToken = d.readString()
+	decode_ast_LocRef(d, &v.Name)
+	decode_Slice_css_ast_KeyframeBlock(d, &v.Blocks)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_RAtLayer(e *encoder, v *css_ast.RAtLayer) {
+	encode_Slice_Slice_string(e, &v.Names)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_RAtLayer(d *decoder, v *css_ast.RAtLayer) {
+	decode_Slice_Slice_string(d, &v.Names)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_RBadDeclaration(e *encoder, v *css_ast.RBadDeclaration) {
+	encode_Slice_css_ast_Token(e, &v.Tokens)
+}
+
+func decode_css_ast_RBadDeclaration(d *decoder, v *css_ast.RBadDeclaration) {
+	decode_Slice_css_ast_Token(d, &v.Tokens)
+}
+
+func encode_css_ast_RComment(e *encoder, v *css_ast.RComment) {
+	e.writeString(string(v.Text))
+}
+
+func decode_css_ast_RComment(d *decoder, v *css_ast.RComment) {
+	v.Text = d.readString()
+}
+
+func encode_css_ast_RDeclaration(e *encoder, v *css_ast.RDeclaration) {
+	e.writeString(string(v.KeyText))
+	encode_Slice_css_ast_Token(e, &v.Value)
+	encode_logger_Range(e, &v.KeyRange)
+	e.writeUvarint(uint64(v.Key))
+	e.writeBool(bool(v.Important))
+}
+
+func decode_css_ast_RDeclaration(d *decoder, v *css_ast.RDeclaration) {
+	v.KeyText = d.readString()
+	decode_Slice_css_ast_Token(d, &v.Value)
+	decode_logger_Range(d, &v.KeyRange)
+	v.Key = css_ast.D(d.readUvarint())
+	v.Important = d.readBool()
+}
+
+func encode_css_ast_RKnownAt(e *encoder, v *css_ast.RKnownAt) {
+	e.writeString(string(v.AtToken))
+	encode_Slice_css_ast_Token(e, &v.Prelude)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_RKnownAt(d *decoder, v *css_ast.RKnownAt) {
+	v.AtToken = d.readString()
+	decode_Slice_css_ast_Token(d, &v.Prelude)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_RQualified(e *encoder, v *css_ast.RQualified) {
+	encode_Slice_css_ast_Token(e, &v.Prelude)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_RQualified(d *decoder, v *css_ast.RQualified) {
+	decode_Slice_css_ast_Token(d, &v.Prelude)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_RSelector(e *encoder, v *css_ast.RSelector) {
+	encode_Slice_css_ast_ComplexSelector(e, &v.Selectors)
+	encode_Slice_css_ast_Rule(e, &v.Rules)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_css_ast_RSelector(d *decoder, v *css_ast.RSelector) {
+	decode_Slice_css_ast_ComplexSelector(d, &v.Selectors)
+	decode_Slice_css_ast_Rule(d, &v.Rules)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_css_ast_RUnknownAt(e *encoder, v *css_ast.RUnknownAt) {
+	e.writeString(string(v.AtToken))
+	encode_Slice_css_ast_Token(e, &v.Prelude)
+	encode_Slice_css_ast_Token(e, &v.Block)
+}
+
+func decode_css_ast_RUnknownAt(d *decoder, v *css_ast.RUnknownAt) {
+	v.AtToken = d.readString()
+	decode_Slice_css_ast_Token(d, &v.Prelude)
+	decode_Slice_css_ast_Token(d, &v.Block)
+}
+
+func encode_css_ast_Rule(e *encoder, v *css_ast.Rule) {
+	encode_css_ast_R(e, &v.Data)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_css_ast_Rule(d *decoder, v *css_ast.Rule) {
+	decode_css_ast_R(d, &v.Data)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_css_ast_SS(e *encoder, v *css_ast.SS) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *css_ast.SSAttribute:
+		e.writeUvarint(1)
+		encode_Ptr_css_ast_SSAttribute(e, &x)
+	case *css_ast.SSClass:
+		e.writeUvarint(2)
+		encode_Ptr_css_ast_SSClass(e, &x)
+	case *css_ast.SSHash:
+		e.writeUvarint(3)
+		encode_Ptr_css_ast_SSHash(e, &x)
+	case *css_ast.SSPseudoClass:
+		e.writeUvarint(4)
+		encode_Ptr_css_ast_SSPseudoClass(e, &x)
+	case *css_ast.SSPseudoClassWithSelectorList:
+		e.writeUvarint(5)
+		encode_Ptr_css_ast_SSPseudoClassWithSelectorList(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_css_ast_SS(d *decoder, v *css_ast.SS) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *css_ast.SSAttribute
+		decode_Ptr_css_ast_SSAttribute(d, &x)
+		*v = x
+	case 2:
+		var x *css_ast.SSClass
+		decode_Ptr_css_ast_SSClass(d, &x)
+		*v = x
+	case 3:
+		var x *css_ast.SSHash
+		decode_Ptr_css_ast_SSHash(d, &x)
+		*v = x
+	case 4:
+		var x *css_ast.SSPseudoClass
+		decode_Ptr_css_ast_SSPseudoClass(d, &x)
+		*v = x
+	case 5:
+		var x *css_ast.SSPseudoClassWithSelectorList
+		decode_Ptr_css_ast_SSPseudoClassWithSelectorList(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_css_ast_SSAttribute(e *encoder, v *css_ast.SSAttribute) {
+	e.writeString(string(v.MatcherOp))
+	e.writeString(string(v.MatcherValue))
+	encode_css_ast_NamespacedName(e, &v.NamespacedName)
+	e.writeUvarint(uint64(v.MatcherModifier))
+}
+
+func decode_css_ast_SSAttribute(d *decoder, v *css_ast.SSAttribute) {
+	v.MatcherOp = d.readString()
+	v.MatcherValue = d.readString()
+	decode_css_ast_NamespacedName(d, &v.NamespacedName)
+	v.MatcherModifier = uint8(d.readUvarint())
+}
+
+func encode_css_ast_SSClass(e *encoder, v *css_ast.SSClass) {
+	encode_ast_LocRef(e, &v.Name)
+}
+
+func decode_css_ast_SSClass(d *decoder, v *css_ast.SSClass) {
+	decode_ast_LocRef(d, &v.Name)
+}
+
+func encode_css_ast_SSHash(e *encoder, v *css_ast.SSHash) {
+	encode_ast_LocRef(e, &v.Name)
+}
+
+func decode_css_ast_SSHash(d *decoder, v *css_ast.SSHash) {
+	decode_ast_LocRef(d, &v.Name)
+}
+
+func encode_css_ast_SSPseudoClass(e *encoder, v *css_ast.SSPseudoClass) {
+	e.writeString(string(v.Name))
+	encode_Slice_css_ast_Token(e, &v.Args)
+	e.writeBool(bool(v.IsElement))
+}
+
+func decode_css_ast_SSPseudoClass(d *decoder, v *css_ast.SSPseudoClass) {
+	v.Name = d.readString()
+	decode_Slice_css_ast_Token(d, &v.Args)
+	v.IsElement = d.readBool()
+}
+
+func encode_css_ast_SSPseudoClassWithSelectorList(e *encoder, v *css_ast.SSPseudoClassWithSelectorList) {
+	encode_Slice_css_ast_ComplexSelector(e, &v.Selectors)
+	encode_css_ast_NthIndex(e, &v.Index)
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_css_ast_SSPseudoClassWithSelectorList(d *decoder, v *css_ast.SSPseudoClassWithSelectorList) {
+	decode_Slice_css_ast_ComplexSelector(d, &v.Selectors)
+	decode_css_ast_NthIndex(d, &v.Index)
+	v.Kind = css_ast.PseudoClassKind(d.readUvarint())
+}
+
+func encode_css_ast_SubclassSelector(e *encoder, v *css_ast.SubclassSelector) {
+	encode_css_ast_SS(e, &v.Data)
+	encode_logger_Range(e, &v.Range)
+}
+
+func decode_css_ast_SubclassSelector(d *decoder, v *css_ast.SubclassSelector) {
+	decode_css_ast_SS(d, &v.Data)
+	decode_logger_Range(d, &v.Range)
+}
+
+func encode_css_ast_Token(e *encoder, v *css_ast.Token) {
+	encode_Ptr_Slice_css_ast_Token(e, &v.Children)
+	e.writeString(string(v.Text))
+	encode_logger_Loc(e, &v.Loc)
+	e.writeUvarint(uint64(v.PayloadIndex))
+	e.writeUvarint(uint64(v.UnitOffset))
+	e.writeUvarint(uint64(v.Kind))
+	e.writeUvarint(uint64(v.Whitespace))
+}
+
+func decode_css_ast_Token(d *decoder, v *css_ast.Token) {
+	decode_Ptr_Slice_css_ast_Token(d, &v.Children)
+	v.Text = d.readString()
+	decode_logger_Loc(d, &v.Loc)
+	v.PayloadIndex = uint32(d.readUvarint())
+	v.UnitOffset = uint16(d.readUvarint())
+	v.Kind = css_lexer.T(d.readUvarint())
+	v.Whitespace = css_ast.WhitespaceFlags(d.readUvarint())
+}
+
+func encode_helpers_GlobPart(e *encoder, v *helpers.GlobPart) {
+	e.writeString(string(v.Prefix))
+	e.writeUvarint(uint64(v.Wildcard))
+}
+
+func decode_helpers_GlobPart(d *decoder, v *helpers.GlobPart) {
+	v.Prefix = d.readString()
+	v.Wildcard = helpers.GlobWildcard(d.readUvarint())
+}
+
+func encode_js_ast_AST(e *encoder, v *js_ast.AST) {
+	encode_js_ast_ModuleTypeData(e, &v.ModuleTypeData)
+	encode_Slice_js_ast_Part(e, &v.Parts)
+	encode_Slice_ast_Symbol(e, &v.Symbols)
+	encode_Map_logger_Loc_Slice_string(e, &v.ExprComments)
+	encode_Ptr_js_ast_Scope(e, &v.ModuleScope)
+	encode_Ptr_ast_CharFreq(e, &v.CharFreq)
+	encode_js_ast_Expr(e, &v.ManifestForYarnPnP)
+	e.writeString(string(v.Hashbang))
+	encode_Slice_string(e, &v.Directives)
+	e.writeString(string(v.URLForCSS))
+	e.writeString(string(v.TSDeclaration))
+	encode_Map_ast_Ref_Slice_uint32(e, &v.TopLevelSymbolToPartsFromParser)
+	encode_Map_ast_Ref_Map_string_js_ast_TSEnumValue(e, &v.TSEnums)
+	encode_Map_ast_Ref_js_ast_ConstValue(e, &v.ConstValues)
+	encode_Map_ast_Ref_js_ast_FoldableConst(e, &v.FoldableConsts)
+	encode_Map_string_ast_Ref(e, &v.MangledProps)
+	encode_Map_string_bool(e, &v.ReservedProps)
+	encode_Slice_ast_ImportRecord(e, &v.ImportRecords)
+	encode_Map_ast_Ref_js_ast_NamedImport(e, &v.NamedImports)
+	encode_Map_string_js_ast_NamedExport(e, &v.NamedExports)
+	encode_Slice_uint32(e, &v.ExportStarImportRecords)
+	encode_logger_Span(e, &v.SourceMapComment)
+	encode_logger_Range(e, &v.ExportKeyword)
+	encode_logger_Range(e, &v.TopLevelAwaitKeyword)
+	encode_logger_Range(e, &v.LiveTopLevelAwaitKeyword)
+	encode_ast_Ref(e, &v.ExportsRef)
+	encode_ast_Ref(e, &v.ModuleRef)
+	encode_ast_Ref(e, &v.WrapperRef)
+	e.writeVarint(int64(v.ApproximateLineCount))
+	encode_ast_SlotCounts(e, &v.NestedScopeSlotCounts)
+	e.writeBool(bool(v.HasLazyExport))
+	e.writeBool(bool(v.UsesExportsRef))
+	e.writeBool(bool(v.UsesModuleRef))
+	e.writeUvarint(uint64(v.ExportsKind))
+	e.writeBool(bool(v.ConvertedFromCommonJS))
+}
+
+func decode_js_ast_AST(d *decoder, v *js_ast.AST) {
+	decode_js_ast_ModuleTypeData(d, &v.ModuleTypeData)
+	decode_Slice_js_ast_Part(d, &v.Parts)
+	decode_Slice_ast_Symbol(d, &v.Symbols)
+	decode_Map_logger_Loc_Slice_string(d, &v.ExprComments)
+	decode_Ptr_js_ast_Scope(d, &v.ModuleScope)
+	decode_Ptr_ast_CharFreq(d, &v.CharFreq)
+	decode_js_ast_Expr(d, &v.ManifestForYarnPnP)
+	v.Hashbang = d.readString()
+	decode_Slice_string(d, &v.Directives)
+	v.URLForCSS = d.readString()
+	v.TSDeclaration = d.readString()
+	decode_Map_ast_Ref_Slice_uint32(d, &v.TopLevelSymbolToPartsFromParser)
+	decode_Map_ast_Ref_Map_string_js_ast_TSEnumValue(d, &v.TSEnums)
+	decode_Map_ast_Ref_js_ast_ConstValue(d, &v.ConstValues)
+	decode_Map_ast_Ref_js_ast_FoldableConst(d, &v.FoldableConsts)
+	decode_Map_string_ast_Ref(d, &v.MangledProps)
+	decode_Map_string_bool(d, &v.ReservedProps)
+	decode_Slice_ast_ImportRecord(d, &v.ImportRecords)
+	decode_Map_ast_Ref_js_ast_NamedImport(d, &v.NamedImports)
+	decode_Map_string_js_ast_NamedExport(d, &v.NamedExports)
+	decode_Slice_uint32(d, &v.ExportStarImportRecords)
+	decode_logger_Span(d, &v.SourceMapComment)
+	decode_logger_Range(d, &v.ExportKeyword)
+	decode_logger_Range(d, &v.TopLevelAwaitKeyword)
+	decode_logger_Range(d, &v.LiveTopLevelAwaitKeyword)
+	decode_ast_Ref(d, &v.ExportsRef)
+	decode_ast_Ref(d, &v.ModuleRef)
+	decode_ast_Ref(d, &v.WrapperRef)
+	v.ApproximateLineCount = int32(d.readVarint())
+	decode_ast_SlotCounts(d, &v.NestedScopeSlotCounts)
+	v.HasLazyExport = d.readBool()
+	v.UsesExportsRef = d.readBool()
+	v.UsesModuleRef = d.readBool()
+	v.ExportsKind = js_ast.ExportsKind(d.readUvarint())
+	v.ConvertedFromCommonJS = d.readBool()
+}
+
+func encode_js_ast_Arg(e *encoder, v *js_ast.Arg) {
+	encode_js_ast_Binding(e, &v.Binding)
+	encode_js_ast_Expr(e, &v.DefaultOrNil)
+	encode_Slice_js_ast_Decorator(e, &v.Decorators)
+	e.writeBool(bool(v.IsTypeScriptCtorField))
+}
+
+func decode_js_ast_Arg(d *decoder, v *js_ast.Arg) {
+	decode_js_ast_Binding(d, &v.Binding)
+	decode_js_ast_Expr(d, &v.DefaultOrNil)
+	decode_Slice_js_ast_Decorator(d, &v.Decorators)
+	v.IsTypeScriptCtorField = d.readBool()
+}
+
+func encode_js_ast_ArrayBinding(e *encoder, v *js_ast.ArrayBinding) {
+	encode_js_ast_Binding(e, &v.Binding)
+	encode_js_ast_Expr(e, &v.DefaultValueOrNil)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_ArrayBinding(d *decoder, v *js_ast.ArrayBinding) {
+	decode_js_ast_Binding(d, &v.Binding)
+	decode_js_ast_Expr(d, &v.DefaultValueOrNil)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_B(e *encoder, v *js_ast.B) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *js_ast.BArray:
+		e.writeUvarint(1)
+		encode_Ptr_js_ast_BArray(e, &x)
+	case *js_ast.BIdentifier:
+		e.writeUvarint(2)
+		encode_Ptr_js_ast_BIdentifier(e, &x)
+	case *js_ast.BMissing:
+		e.writeUvarint(3)
+		encode_Ptr_js_ast_BMissing(e, &x)
+	case *js_ast.BObject:
+		e.writeUvarint(4)
+		encode_Ptr_js_ast_BObject(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_js_ast_B(d *decoder, v *js_ast.B) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *js_ast.BArray
+		decode_Ptr_js_ast_BArray(d, &x)
+		*v = x
+	case 2:
+		var x *js_ast.BIdentifier
+		decode_Ptr_js_ast_BIdentifier(d, &x)
+		*v = x
+	case 3:
+		var x *js_ast.BMissing
+		decode_Ptr_js_ast_BMissing(d, &x)
+		*v = x
+	case 4:
+		var x *js_ast.BObject
+		decode_Ptr_js_ast_BObject(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_js_ast_BArray(e *encoder, v *js_ast.BArray) {
+	encode_Slice_js_ast_ArrayBinding(e, &v.Items)
+	encode_logger_Loc(e, &v.CloseBracketLoc)
+	e.writeBool(bool(v.HasSpread))
+	e.writeBool(bool(v.IsSingleLine))
+}
+
+func decode_js_ast_BArray(d *decoder, v *js_ast.BArray) {
+	decode_Slice_js_ast_ArrayBinding(d, &v.Items)
+	decode_logger_Loc(d, &v.CloseBracketLoc)
+	v.HasSpread = d.readBool()
+	v.IsSingleLine = d.readBool()
+}
+
+func encode_js_ast_BIdentifier(e *encoder, v *js_ast.BIdentifier) {
+	encode_ast_Ref(e, &v.Ref)
+}
+
+func decode_js_ast_BIdentifier(d *decoder, v *js_ast.BIdentifier) {
+	decode_ast_Ref(d, &v.Ref)
+}
+
+func encode_js_ast_BMissing(e *encoder, v *js_ast.BMissing) {
+}
+
+func decode_js_ast_BMissing(d *decoder, v *js_ast.BMissing) {
+}
+
+func encode_js_ast_BObject(e *encoder, v *js_ast.BObject) {
+	encode_Slice_js_ast_PropertyBinding(e, &v.Properties)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+	e.writeBool(bool(v.IsSingleLine))
+}
+
+func decode_js_ast_BObject(d *decoder, v *js_ast.BObject) {
+	decode_Slice_js_ast_PropertyBinding(d, &v.Properties)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+	v.IsSingleLine = d.readBool()
+}
+
+func encode_js_ast_Binding(e *encoder, v *js_ast.Binding) {
+	encode_js_ast_B(e, &v.Data)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_Binding(d *decoder, v *js_ast.Binding) {
+	decode_js_ast_B(d, &v.Data)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Case(e *encoder, v *js_ast.Case) {
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+	encode_Slice_js_ast_Stmt(e, &v.Body)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_Case(d *decoder, v *js_ast.Case) {
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+	decode_Slice_js_ast_Stmt(d, &v.Body)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Catch(e *encoder, v *js_ast.Catch) {
+	encode_js_ast_Binding(e, &v.BindingOrNil)
+	encode_js_ast_SBlock(e, &v.Block)
+	encode_logger_Loc(e, &v.Loc)
+	encode_logger_Loc(e, &v.BlockLoc)
+}
+
+func decode_js_ast_Catch(d *decoder, v *js_ast.Catch) {
+	decode_js_ast_Binding(d, &v.BindingOrNil)
+	decode_js_ast_SBlock(d, &v.Block)
+	decode_logger_Loc(d, &v.Loc)
+	decode_logger_Loc(d, &v.BlockLoc)
+}
+
+func encode_js_ast_Class(e *encoder, v *js_ast.Class) {
+	encode_Slice_js_ast_Decorator(e, &v.Decorators)
+	encode_Ptr_ast_LocRef(e, &v.Name)
+	encode_js_ast_Expr(e, &v.ExtendsOrNil)
+	encode_Slice_js_ast_Property(e, &v.Properties)
+	encode_logger_Range(e, &v.ClassKeyword)
+	encode_logger_Loc(e, &v.BodyLoc)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+	e.writeBool(bool(v.UseDefineForClassFields))
+}
+
+func decode_js_ast_Class(d *decoder, v *js_ast.Class) {
+	decode_Slice_js_ast_Decorator(d, &v.Decorators)
+	decode_Ptr_ast_LocRef(d, &v.Name)
+	decode_js_ast_Expr(d, &v.ExtendsOrNil)
+	decode_Slice_js_ast_Property(d, &v.Properties)
+	decode_logger_Range(d, &v.ClassKeyword)
+	decode_logger_Loc(d, &v.BodyLoc)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+	v.UseDefineForClassFields = d.readBool()
+}
+
+func encode_js_ast_ClassStaticBlock(e *encoder, v *js_ast.ClassStaticBlock) {
+	encode_js_ast_SBlock(e, &v.Block)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_ClassStaticBlock(d *decoder, v *js_ast.ClassStaticBlock) {
+	decode_js_ast_SBlock(d, &v.Block)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_ClauseItem(e *encoder, v *js_ast.ClauseItem) {
+	e.writeString(string(v.Alias))
+	e.writeString(string(v.OriginalName))
+	encode_logger_Loc(e, &v.AliasLoc)
+	encode_ast_LocRef(e, &v.Name)
+}
+
+func decode_js_ast_ClauseItem(d *decoder, v *js_ast.ClauseItem) {
+	v.Alias = d.readString()
+	v.OriginalName = d.readString()
+	decode_logger_Loc(d, &v.AliasLoc)
+	decode_ast_LocRef(d, &v.Name)
+}
+
+func encode_js_ast_ConstValue(e *encoder, v *js_ast.ConstValue) {
+	encode_Slice_uint16(e, &v.String)
+	e.writeFloat64(float64(v.Number))
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_js_ast_ConstValue(d *decoder, v *js_ast.ConstValue) {
+	decode_Slice_uint16(d, &v.String)
+	v.Number = d.readFloat64()
+	v.Kind = js_ast.ConstValueKind(d.readUvarint())
+}
+
+func encode_js_ast_Decl(e *encoder, v *js_ast.Decl) {
+	encode_js_ast_Binding(e, &v.Binding)
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+}
+
+func decode_js_ast_Decl(d *decoder, v *js_ast.Decl) {
+	decode_js_ast_Binding(d, &v.Binding)
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+}
+
+func encode_js_ast_DeclaredSymbol(e *encoder, v *js_ast.DeclaredSymbol) {
+	encode_ast_Ref(e, &v.Ref)
+	e.writeBool(bool(v.IsTopLevel))
+}
+
+func decode_js_ast_DeclaredSymbol(d *decoder, v *js_ast.DeclaredSymbol) {
+	decode_ast_Ref(d, &v.Ref)
+	v.IsTopLevel = d.readBool()
+}
+
+func encode_js_ast_Decorator(e *encoder, v *js_ast.Decorator) {
+	encode_js_ast_Expr(e, &v.Value)
+	encode_logger_Loc(e, &v.AtLoc)
+	e.writeBool(bool(v.OmitNewlineAfter))
+}
+
+func decode_js_ast_Decorator(d *decoder, v *js_ast.Decorator) {
+	decode_js_ast_Expr(d, &v.Value)
+	decode_logger_Loc(d, &v.AtLoc)
+	v.OmitNewlineAfter = d.readBool()
+}
+
+func encode_js_ast_Dependency(e *encoder, v *js_ast.Dependency) {
+	e.writeUvarint(uint64(v.SourceIndex))
+	e.writeUvarint(uint64(v.PartIndex))
+}
+
+func decode_js_ast_Dependency(d *decoder, v *js_ast.Dependency) {
+	v.SourceIndex = uint32(d.readUvarint())
+	v.PartIndex = uint32(d.readUvarint())
+}
+
+func encode_js_ast_E(e *encoder, v *js_ast.E) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *js_ast.EAnnotation:
+		e.writeUvarint(1)
+		encode_Ptr_js_ast_EAnnotation(e, &x)
+	case *js_ast.EArray:
+		e.writeUvarint(2)
+		encode_Ptr_js_ast_EArray(e, &x)
+	case *js_ast.EArrow:
+		e.writeUvarint(3)
+		encode_Ptr_js_ast_EArrow(e, &x)
+	case *js_ast.EAwait:
+		e.writeUvarint(4)
+		encode_Ptr_js_ast_EAwait(e, &x)
+	case *js_ast.EBigInt:
+		e.writeUvarint(5)
+		encode_Ptr_js_ast_EBigInt(e, &x)
+	case *js_ast.EBinary:
+		e.writeUvarint(6)
+		encode_Ptr_js_ast_EBinary(e, &x)
+	case *js_ast.EBoolean:
+		e.writeUvarint(7)
+		encode_Ptr_js_ast_EBoolean(e, &x)
+	case *js_ast.ECall:
+		e.writeUvarint(8)
+		encode_Ptr_js_ast_ECall(e, &x)
+	case *js_ast.EClass:
+		e.writeUvarint(9)
+		encode_Ptr_js_ast_EClass(e, &x)
+	case *js_ast.EDot:
+		e.writeUvarint(10)
+		encode_Ptr_js_ast_EDot(e, &x)
+	case *js_ast.EFunction:
+		e.writeUvarint(11)
+		encode_Ptr_js_ast_EFunction(e, &x)
+	case *js_ast.EIdentifier:
+		e.writeUvarint(12)
+		encode_Ptr_js_ast_EIdentifier(e, &x)
+	case *js_ast.EIf:
+		e.writeUvarint(13)
+		encode_Ptr_js_ast_EIf(e, &x)
+	case *js_ast.EImportCall:
+		e.writeUvarint(14)
+		encode_Ptr_js_ast_EImportCall(e, &x)
+	case *js_ast.EImportIdentifier:
+		e.writeUvarint(15)
+		encode_Ptr_js_ast_EImportIdentifier(e, &x)
+	case *js_ast.EImportMeta:
+		e.writeUvarint(16)
+		encode_Ptr_js_ast_EImportMeta(e, &x)
+	case *js_ast.EImportPathString:
+		e.writeUvarint(17)
+		encode_Ptr_js_ast_EImportPathString(e, &x)
+	case *js_ast.EImportString:
+		e.writeUvarint(18)
+		encode_Ptr_js_ast_EImportString(e, &x)
+	case *js_ast.EIndex:
+		e.writeUvarint(19)
+		encode_Ptr_js_ast_EIndex(e, &x)
+	case *js_ast.EInlinedEnum:
+		e.writeUvarint(20)
+		encode_Ptr_js_ast_EInlinedEnum(e, &x)
+	case *js_ast.EJSXElement:
+		e.writeUvarint(21)
+		encode_Ptr_js_ast_EJSXElement(e, &x)
+	case *js_ast.EJSXText:
+		e.writeUvarint(22)
+		encode_Ptr_js_ast_EJSXText(e, &x)
+	case *js_ast.EMissing:
+		e.writeUvarint(23)
+		encode_Ptr_js_ast_EMissing(e, &x)
+	case *js_ast.ENameOfSymbol:
+		e.writeUvarint(24)
+		encode_Ptr_js_ast_ENameOfSymbol(e, &x)
+	case *js_ast.ENew:
+		e.writeUvarint(25)
+		encode_Ptr_js_ast_ENew(e, &x)
+	case *js_ast.ENewTarget:
+		e.writeUvarint(26)
+		encode_Ptr_js_ast_ENewTarget(e, &x)
+	case *js_ast.ENull:
+		e.writeUvarint(27)
+		encode_Ptr_js_ast_ENull(e, &x)
+	case *js_ast.ENumber:
+		e.writeUvarint(28)
+		encode_Ptr_js_ast_ENumber(e, &x)
+	case *js_ast.EObject:
+		e.writeUvarint(29)
+		encode_Ptr_js_ast_EObject(e, &x)
+	case *js_ast.EPrivateIdentifier:
+		e.writeUvarint(30)
+		encode_Ptr_js_ast_EPrivateIdentifier(e, &x)
+	case *js_ast.ERegExp:
+		e.writeUvarint(31)
+		encode_Ptr_js_ast_ERegExp(e, &x)
+	case *js_ast.ERequireResolveString:
+		e.writeUvarint(32)
+		encode_Ptr_js_ast_ERequireResolveString(e, &x)
+	case *js_ast.ERequireString:
+		e.writeUvarint(33)
+		encode_Ptr_js_ast_ERequireString(e, &x)
+	case *js_ast.ESpread:
+		e.writeUvarint(34)
+		encode_Ptr_js_ast_ESpread(e, &x)
+	case *js_ast.EString:
+		e.writeUvarint(35)
+		encode_Ptr_js_ast_EString(e, &x)
+	case *js_ast.ESuper:
+		e.writeUvarint(36)
+		encode_Ptr_js_ast_ESuper(e, &x)
+	case *js_ast.ETemplate:
+		e.writeUvarint(37)
+		encode_Ptr_js_ast_ETemplate(e, &x)
+	case *js_ast.EThis:
+		e.writeUvarint(38)
+		encode_Ptr_js_ast_EThis(e, &x)
+	case *js_ast.EUnary:
+		e.writeUvarint(39)
+		encode_Ptr_js_ast_EUnary(e, &x)
+	case *js_ast.EUndefined:
+		e.writeUvarint(40)
+		encode_Ptr_js_ast_EUndefined(e, &x)
+	case *js_ast.EYield:
+		e.writeUvarint(41)
+		encode_Ptr_js_ast_EYield(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_js_ast_E(d *decoder, v *js_ast.E) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *js_ast.EAnnotation
+		decode_Ptr_js_ast_EAnnotation(d, &x)
+		*v = x
+	case 2:
+		var x *js_ast.EArray
+		decode_Ptr_js_ast_EArray(d, &x)
+		*v = x
+	case 3:
+		var x *js_ast.EArrow
+		decode_Ptr_js_ast_EArrow(d, &x)
+		*v = x
+	case 4:
+		var x *js_ast.EAwait
+		decode_Ptr_js_ast_EAwait(d, &x)
+		*v = x
+	case 5:
+		var x *js_ast.EBigInt
+		decode_Ptr_js_ast_EBigInt(d, &x)
+		*v = x
+	case 6:
+		var x *js_ast.EBinary
+		decode_Ptr_js_ast_EBinary(d, &x)
+		*v = x
+	case 7:
+		var x *js_ast.EBoolean
+		decode_Ptr_js_ast_EBoolean(d, &x)
+		*v = x
+	case 8:
+		var x *js_ast.ECall
+		decode_Ptr_js_ast_ECall(d, &x)
+		*v = x
+	case 9:
+		var x *js_ast.EClass
+		decode_Ptr_js_ast_EClass(d, &x)
+		*v = x
+	case 10:
+		var x *js_ast.EDot
+		decode_Ptr_js_ast_EDot(d, &x)
+		*v = x
+	case 11:
+		var x *js_ast.EFunction
+		decode_Ptr_js_ast_EFunction(d, &x)
+		*v = x
+	case 12:
+		var x *js_ast.EIdentifier
+		decode_Ptr_js_ast_EIdentifier(d, &x)
+		*v = x
+	case 13:
+		var x *js_ast.EIf
+		decode_Ptr_js_ast_EIf(d, &x)
+		*v = x
+	case 14:
+		var x *js_ast.EImportCall
+		decode_Ptr_js_ast_EImportCall(d, &x)
+		*v = x
+	case 15:
+		var x *js_ast.EImportIdentifier
+		decode_Ptr_js_ast_EImportIdentifier(d, &x)
+		*v = x
+	case 16:
+		var x *js_ast.EImportMeta
+		decode_Ptr_js_ast_EImportMeta(d, &x)
+		*v = x
+	case 17:
+		var x *js_ast.EImportPathString
+		decode_Ptr_js_ast_EImportPathString(d, &x)
+		*v = x
+	case 18:
+		var x *js_ast.EImportString
+		decode_Ptr_js_ast_EImportString(d, &x)
+		*v = x
+	case 19:
+		var x *js_ast.EIndex
+		decode_Ptr_js_ast_EIndex(d, &x)
+		*v = x
+	case 20:
+		var x *js_ast.EInlinedEnum
+		decode_Ptr_js_ast_EInlinedEnum(d, &x)
+		*v = x
+	case 21:
+		var x *js_ast.EJSXElement
+		decode_Ptr_js_ast_EJSXElement(d, &x)
+		*v = x
+	case 22:
+		var x *js_ast.EJSXText
+		decode_Ptr_js_ast_EJSXText(d, &x)
+		*v = x
+	case 23:
+		var x *js_ast.EMissing
+		decode_Ptr_js_ast_EMissing(d, &x)
+		*v = x
+	case 24:
+		var x *js_ast.ENameOfSymbol
+		decode_Ptr_js_ast_ENameOfSymbol(d, &x)
+		*v = x
+	case 25:
+		var x *js_ast.ENew
+		decode_Ptr_js_ast_ENew(d, &x)
+		*v = x
+	case 26:
+		var x *js_ast.ENewTarget
+		decode_Ptr_js_ast_ENewTarget(d, &x)
+		*v = x
+	case 27:
+		var x *js_ast.ENull
+		decode_Ptr_js_ast_ENull(d, &x)
+		*v = x
+	case 28:
+		var x *js_ast.ENumber
+		decode_Ptr_js_ast_ENumber(d, &x)
+		*v = x
+	case 29:
+		var x *js_ast.EObject
+		decode_Ptr_js_ast_EObject(d, &x)
+		*v = x
+	case 30:
+		var x *js_ast.EPrivateIdentifier
+		decode_Ptr_js_ast_EPrivateIdentifier(d, &x)
+		*v = x
+	case 31:
+		var x *js_ast.ERegExp
+		decode_Ptr_js_ast_ERegExp(d, &x)
+		*v = x
+	case 32:
+		var x *js_ast.ERequireResolveString
+		decode_Ptr_js_ast_ERequireResolveString(d, &x)
+		*v = x
+	case 33:
+		var x *js_ast.ERequireString
+		decode_Ptr_js_ast_ERequireString(d, &x)
+		*v = x
+	case 34:
+		var x *js_ast.ESpread
+		decode_Ptr_js_ast_ESpread(d, &x)
+		*v = x
+	case 35:
+		var x *js_ast.EString
+		decode_Ptr_js_ast_EString(d, &x)
+		*v = x
+	case 36:
+		var x *js_ast.ESuper
+		decode_Ptr_js_ast_ESuper(d, &x)
+		*v = x
+	case 37:
+		var x *js_ast.ETemplate
+		decode_Ptr_js_ast_ETemplate(d, &x)
+		*v = x
+	case 38:
+		var x *js_ast.EThis
+		decode_Ptr_js_ast_EThis(d, &x)
+		*v = x
+	case 39:
+		var x *js_ast.EUnary
+		decode_Ptr_js_ast_EUnary(d, &x)
+		*v = x
+	case 40:
+		var x *js_ast.EUndefined
+		decode_Ptr_js_ast_EUndefined(d, &x)
+		*v = x
+	case 41:
+		var x *js_ast.EYield
+		decode_Ptr_js_ast_EYield(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_js_ast_EAnnotation(e *encoder, v *js_ast.EAnnotation) {
+	encode_js_ast_Expr(e, &v.Value)
+	e.writeUvarint(uint64(v.Flags))
+}
+
+func decode_js_ast_EAnnotation(d *decoder, v *js_ast.EAnnotation) {
+	decode_js_ast_Expr(d, &v.Value)
+	v.Flags = js_ast.AnnotationFlags(d.readUvarint())
+}
+
+func encode_js_ast_EArray(e *encoder, v *js_ast.EArray) {
+	encode_Slice_js_ast_Expr(e, &v.Items)
+	encode_logger_Loc(e, &v.CommaAfterSpread)
+	encode_logger_Loc(e, &v.CloseBracketLoc)
+	e.writeBool(bool(v.IsSingleLine))
+	e.writeBool(bool(v.IsParenthesized))
+}
+
+func decode_js_ast_EArray(d *decoder, v *js_ast.EArray) {
+	decode_Slice_js_ast_Expr(d, &v.Items)
+	decode_logger_Loc(d, &v.CommaAfterSpread)
+	decode_logger_Loc(d, &v.CloseBracketLoc)
+	v.IsSingleLine = d.readBool()
+	v.IsParenthesized = d.readBool()
+}
+
+func encode_js_ast_EArrow(e *encoder, v *js_ast.EArrow) {
+	encode_Slice_js_ast_Arg(e, &v.Args)
+	encode_js_ast_FnBody(e, &v.Body)
+	e.writeBool(bool(v.IsAsync))
+	e.writeBool(bool(v.HasRestArg))
+	e.writeBool(bool(v.PreferExpr))
+	e.writeBool(bool(v.HasNoSideEffectsComment))
+}
+
+func decode_js_ast_EArrow(d *decoder, v *js_ast.EArrow) {
+	decode_Slice_js_ast_Arg(d, &v.Args)
+	decode_js_ast_FnBody(d, &v.Body)
+	v.IsAsync = d.readBool()
+	v.HasRestArg = d.readBool()
+	v.PreferExpr = d.readBool()
+	v.HasNoSideEffectsComment = d.readBool()
+}
+
+func encode_js_ast_EAwait(e *encoder, v *js_ast.EAwait) {
+	encode_js_ast_Expr(e, &v.Value)
+}
+
+func decode_js_ast_EAwait(d *decoder, v *js_ast.EAwait) {
+	decode_js_ast_Expr(d, &v.Value)
+}
+
+func encode_js_ast_EBigInt(e *encoder, v *js_ast.EBigInt) {
+	e.writeString(string(v.Value))
+}
+
+func decode_js_ast_EBigInt(d *decoder, v *js_ast.EBigInt) {
+	v.Value = d.readString()
+}
+
+func encode_js_ast_EBinary(e *encoder, v *js_ast.EBinary) {
+	encode_js_ast_Expr(e, &v.Left)
+	encode_js_ast_Expr(e, &v.Right)
+	e.writeUvarint(uint64(v.Op))
+}
+
+func decode_js_ast_EBinary(d *decoder, v *js_ast.EBinary) {
+	decode_js_ast_Expr(d, &v.Left)
+	decode_js_ast_Expr(d, &v.Right)
+	v.Op = js_ast.OpCode(d.readUvarint())
+}
+
+func encode_js_ast_EBoolean(e *encoder, v *js_ast.EBoolean) {
+	e.writeBool(bool(v.Value))
+}
+
+func decode_js_ast_EBoolean(d *decoder, v *js_ast.EBoolean) {
+	v.Value = d.readBool()
+}
+
+func encode_js_ast_ECall(e *encoder, v *js_ast.ECall) {
+	encode_js_ast_Expr(e, &v.Target)
+	encode_Slice_js_ast_Expr(e, &v.Args)
+	encode_logger_Loc(e, &v.CloseParenLoc)
+	e.writeUvarint(uint64(v.OptionalChain))
+	e.writeUvarint(uint64(v.Kind))
+	e.writeBool(bool(v.IsMultiLine))
+	e.writeBool(bool(v.CanBeUnwrappedIfUnused))
+}
+
+func decode_js_ast_ECall(d *decoder, v *js_ast.ECall) {
+	decode_js_ast_Expr(d, &v.Target)
+	decode_Slice_js_ast_Expr(d, &v.Args)
+	decode_logger_Loc(d, &v.CloseParenLoc)
+	v.OptionalChain = js_ast.OptionalChain(d.readUvarint())
+	v.Kind = js_ast.CallKind(d.readUvarint())
+	v.IsMultiLine = d.readBool()
+	v.CanBeUnwrappedIfUnused = d.readBool()
+}
+
+func encode_js_ast_EClass(e *encoder, v *js_ast.EClass) {
+	encode_js_ast_Class(e, &v.Class)
+}
+
+func decode_js_ast_EClass(d *decoder, v *js_ast.EClass) {
+	decode_js_ast_Class(d, &v.Class)
+}
+
+func encode_js_ast_EDot(e *encoder, v *js_ast.EDot) {
+	encode_js_ast_Expr(e, &v.Target)
+	e.writeString(string(v.Name))
+	encode_logger_Loc(e, &v.NameLoc)
+	e.writeUvarint(uint64(v.OptionalChain))
+	e.writeBool(bool(v.CanBeRemovedIfUnused))
+	e.writeBool(bool(v.CallCanBeUnwrappedIfUnused))
+	e.writeBool(bool(v.IsSymbolInstance))
+}
+
+func decode_js_ast_EDot(d *decoder, v *js_ast.EDot) {
+	decode_js_ast_Expr(d, &v.Target)
+	v.Name = d.readString()
+	decode_logger_Loc(d, &v.NameLoc)
+	v.OptionalChain = js_ast.OptionalChain(d.readUvarint())
+	v.CanBeRemovedIfUnused = d.readBool()
+	v.CallCanBeUnwrappedIfUnused = d.readBool()
+	v.IsSymbolInstance = d.readBool()
+}
+
+func encode_js_ast_EFunction(e *encoder, v *js_ast.EFunction) {
+	encode_js_ast_Fn(e, &v.Fn)
+}
+
+func decode_js_ast_EFunction(d *decoder, v *js_ast.EFunction) {
+	decode_js_ast_Fn(d, &v.Fn)
+}
+
+func encode_js_ast_EIdentifier(e *encoder, v *js_ast.EIdentifier) {
+	encode_ast_Ref(e, &v.Ref)
+	e.writeBool(bool(v.MustKeepDueToWithStmt))
+	e.writeBool(bool(v.CanBeRemovedIfUnused))
+	e.writeBool(bool(v.CallCanBeUnwrappedIfUnused))
+}
+
+func decode_js_ast_EIdentifier(d *decoder, v *js_ast.EIdentifier) {
+	decode_ast_Ref(d, &v.Ref)
+	v.MustKeepDueToWithStmt = d.readBool()
+	v.CanBeRemovedIfUnused = d.readBool()
+	v.CallCanBeUnwrappedIfUnused = d.readBool()
+}
+
+func encode_js_ast_EIf(e *encoder, v *js_ast.EIf) {
+	encode_js_ast_Expr(e, &v.Test)
+	encode_js_ast_Expr(e, &v.Yes)
+	encode_js_ast_Expr(e, &v.No)
+}
+
+func decode_js_ast_EIf(d *decoder, v *js_ast.EIf) {
+	decode_js_ast_Expr(d, &v.Test)
+	decode_js_ast_Expr(d, &v.Yes)
+	decode_js_ast_Expr(d, &v.No)
+}
+
+func encode_js_ast_EImportCall(e *encoder, v *js_ast.EImportCall) {
+	encode_js_ast_Expr(e, &v.Expr)
+	encode_js_ast_Expr(e, &v.OptionsOrNil)
+	encode_logger_Loc(e, &v.CloseParenLoc)
+}
+
+func decode_js_ast_EImportCall(d *decoder, v *js_ast.EImportCall) {
+	decode_js_ast_Expr(d, &v.Expr)
+	decode_js_ast_Expr(d, &v.OptionsOrNil)
+	decode_logger_Loc(d, &v.CloseParenLoc)
+}
+
+func encode_js_ast_EImportIdentifier(e *encoder, v *js_ast.EImportIdentifier) {
+	encode_ast_Ref(e, &v.Ref)
+	e.writeBool(bool(v.PreferQuotedKey))
+	e.writeBool(bool(v.WasOriginallyIdentifier))
+}
+
+func decode_js_ast_EImportIdentifier(d *decoder, v *js_ast.EImportIdentifier) {
+	decode_ast_Ref(d, &v.Ref)
+	v.PreferQuotedKey = d.readBool()
+	v.WasOriginallyIdentifier = d.readBool()
+}
+
+func encode_js_ast_EImportMeta(e *encoder, v *js_ast.EImportMeta) {
+	e.writeVarint(int64(v.RangeLen))
+}
+
+func decode_js_ast_EImportMeta(d *decoder, v *js_ast.EImportMeta) {
+	v.RangeLen = int32(d.readVarint())
+}
+
+func encode_js_ast_EImportPathString(e *encoder, v *js_ast.EImportPathString) {
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+}
+
+func decode_js_ast_EImportPathString(d *decoder, v *js_ast.EImportPathString) {
+	v.ImportRecordIndex = uint32(d.readUvarint())
+}
+
+func encode_js_ast_EImportString(e *encoder, v *js_ast.EImportString) {
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	encode_logger_Loc(e, &v.CloseParenLoc)
+}
+
+func decode_js_ast_EImportString(d *decoder, v *js_ast.EImportString) {
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	decode_logger_Loc(d, &v.CloseParenLoc)
+}
+
+func encode_js_ast_EIndex(e *encoder, v *js_ast.EIndex) {
+	encode_js_ast_Expr(e, &v.Target)
+	encode_js_ast_Expr(e, &v.Index)
+	encode_logger_Loc(e, &v.CloseBracketLoc)
+	e.writeUvarint(uint64(v.OptionalChain))
+	e.writeBool(bool(v.CanBeRemovedIfUnused))
+	e.writeBool(bool(v.CallCanBeUnwrappedIfUnused))
+	e.writeBool(bool(v.IsSymbolInstance))
+}
+
+func decode_js_ast_EIndex(d *decoder, v *js_ast.EIndex) {
+	decode_js_ast_Expr(d, &v.Target)
+	decode_js_ast_Expr(d, &v.Index)
+	decode_logger_Loc(d, &v.CloseBracketLoc)
+	v.OptionalChain = js_ast.OptionalChain(d.readUvarint())
+	v.CanBeRemovedIfUnused = d.readBool()
+	v.CallCanBeUnwrappedIfUnused = d.readBool()
+	v.IsSymbolInstance = d.readBool()
+}
+
+func encode_js_ast_EInlinedEnum(e *encoder, v *js_ast.EInlinedEnum) {
+	encode_js_ast_Expr(e, &v.Value)
+	e.writeString(string(v.Comment))
+}
+
+func decode_js_ast_EInlinedEnum(d *decoder, v *js_ast.EInlinedEnum) {
+	decode_js_ast_Expr(d, &v.Value)
+	v.Comment = d.readString()
+}
+
+func encode_js_ast_EJSXElement(e *encoder, v *js_ast.EJSXElement) {
+	encode_js_ast_Expr(e, &v.TagOrNil)
+	encode_Slice_js_ast_Property(e, &v.Properties)
+	encode_Slice_js_ast_Expr(e, &v.NullableChildren)
+	encode_logger_Loc(e, &v.CloseLoc)
+	e.writeBool(bool(v.IsTagSingleLine))
+}
+
+func decode_js_ast_EJSXElement(d *decoder, v *js_ast.EJSXElement) {
+	decode_js_ast_Expr(d, &v.TagOrNil)
+	decode_Slice_js_ast_Property(d, &v.Properties)
+	decode_Slice_js_ast_Expr(d, &v.NullableChildren)
+	decode_logger_Loc(d, &v.CloseLoc)
+	v.IsTagSingleLine = d.readBool()
+}
+
+func encode_js_ast_EJSXText(e *encoder, v *js_ast.EJSXText) {
+	e.writeString(string(v.Raw))
+}
+
+func decode_js_ast_EJSXText(d *decoder, v *js_ast.EJSXText) {
+	v.Raw = d.readString()
+}
+
+func encode_js_ast_EMissing(e *encoder, v *js_ast.EMissing) {
+}
+
+func decode_js_ast_EMissing(d *decoder, v *js_ast.EMissing) {
+}
+
+func encode_js_ast_ENameOfSymbol(e *encoder, v *js_ast.ENameOfSymbol) {
+	encode_ast_Ref(e, &v.Ref)
+	e.writeBool(bool(v.HasPropertyKeyComment))
+}
+
+func decode_js_ast_ENameOfSymbol(d *decoder, v *js_ast.ENameOfSymbol) {
+	decode_ast_Ref(d, &v.Ref)
+	v.HasPropertyKeyComment = d.readBool()
+}
+
+func encode_js_ast_ENew(e *encoder, v *js_ast.ENew) {
+	encode_js_ast_Expr(e, &v.Target)
+	encode_Slice_js_ast_Expr(e, &v.Args)
+	encode_logger_Loc(e, &v.CloseParenLoc)
+	e.writeBool(bool(v.IsMultiLine))
+	e.writeBool(bool(v.CanBeUnwrappedIfUnused))
+}
+
+func decode_js_ast_ENew(d *decoder, v *js_ast.ENew) {
+	decode_js_ast_Expr(d, &v.Target)
+	decode_Slice_js_ast_Expr(d, &v.Args)
+	decode_logger_Loc(d, &v.CloseParenLoc)
+	v.IsMultiLine = d.readBool()
+	v.CanBeUnwrappedIfUnused = d.readBool()
+}
+
+func encode_js_ast_ENewTarget(e *encoder, v *js_ast.ENewTarget) {
+	encode_logger_Range(e, &v.Range)
+}
+
+func decode_js_ast_ENewTarget(d *decoder, v *js_ast.ENewTarget) {
+	decode_logger_Range(d, &v.Range)
+}
+
+func encode_js_ast_ENull(e *encoder, v *js_ast.ENull) {
+}
+
+func decode_js_ast_ENull(d *decoder, v *js_ast.ENull) {
+}
+
+func encode_js_ast_ENumber(e *encoder, v *js_ast.ENumber) {
+	e.writeFloat64(float64(v.Value))
+}
+
+func decode_js_ast_ENumber(d *decoder, v *js_ast.ENumber) {
+	v.Value = d.readFloat64()
+}
+
+func encode_js_ast_EObject(e *encoder, v *js_ast.EObject) {
+	encode_Slice_js_ast_Property(e, &v.Properties)
+	encode_logger_Loc(e, &v.CommaAfterSpread)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+	e.writeBool(bool(v.IsSingleLine))
+	e.writeBool(bool(v.IsParenthesized))
+}
+
+func decode_js_ast_EObject(d *decoder, v *js_ast.EObject) {
+	decode_Slice_js_ast_Property(d, &v.Properties)
+	decode_logger_Loc(d, &v.CommaAfterSpread)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+	v.IsSingleLine = d.readBool()
+	v.IsParenthesized = d.readBool()
+}
+
+func encode_js_ast_EPrivateIdentifier(e *encoder, v *js_ast.EPrivateIdentifier) {
+	encode_ast_Ref(e, &v.Ref)
+}
+
+func decode_js_ast_EPrivateIdentifier(d *decoder, v *js_ast.EPrivateIdentifier) {
+	decode_ast_Ref(d, &v.Ref)
+}
+
+func encode_js_ast_ERegExp(e *encoder, v *js_ast.ERegExp) {
+	e.writeString(string(v.Value))
+}
+
+func decode_js_ast_ERegExp(d *decoder, v *js_ast.ERegExp) {
+	v.Value = d.readString()
+}
+
+func encode_js_ast_ERequireResolveString(e *encoder, v *js_ast.ERequireResolveString) {
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	encode_logger_Loc(e, &v.CloseParenLoc)
+}
+
+func decode_js_ast_ERequireResolveString(d *decoder, v *js_ast.ERequireResolveString) {
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	decode_logger_Loc(d, &v.CloseParenLoc)
+}
+
+func encode_js_ast_ERequireString(e *encoder, v *js_ast.ERequireString) {
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	encode_logger_Loc(e, &v.CloseParenLoc)
+}
+
+func decode_js_ast_ERequireString(d *decoder, v *js_ast.ERequireString) {
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	decode_logger_Loc(d, &v.CloseParenLoc)
+}
+
+func encode_js_ast_ESpread(e *encoder, v *js_ast.ESpread) {
+	encode_js_ast_Expr(e, &v.Value)
+}
+
+func decode_js_ast_ESpread(d *decoder, v *js_ast.ESpread) {
+	decode_js_ast_Expr(d, &v.Value)
+}
+
+func encode_js_ast_EString(e *encoder, v *js_ast.EString) {
+	encode_Slice_uint16(e, &v.Value)
+	encode_logger_Loc(e, &v.LegacyOctalLoc)
+	e.writeBool(bool(v.PreferTemplate))
+	e.writeBool(bool(v.HasPropertyKeyComment))
+	e.writeBool(bool(v.ContainsUniqueKey))
+}
+
+func decode_js_ast_EString(d *decoder, v *js_ast.EString) {
+	decode_Slice_uint16(d, &v.Value)
+	decode_logger_Loc(d, &v.LegacyOctalLoc)
+	v.PreferTemplate = d.readBool()
+	v.HasPropertyKeyComment = d.readBool()
+	v.ContainsUniqueKey = d.readBool()
+}
+
+func encode_js_ast_ESuper(e *encoder, v *js_ast.ESuper) {
+}
+
+func decode_js_ast_ESuper(d *decoder, v *js_ast.ESuper) {
+}
+
+func encode_js_ast_ETemplate(e *encoder, v *js_ast.ETemplate) {
+	encode_js_ast_Expr(e, &v.TagOrNil)
+	e.writeString(string(v.HeadRaw))
+	encode_Slice_uint16(e, &v.HeadCooked)
+	encode_Slice_js_ast_TemplatePart(e, &v.Parts)
+	encode_logger_Loc(e, &v.HeadLoc)
+	encode_logger_Loc(e, &v.LegacyOctalLoc)
+	e.writeBool(bool(v.CanBeUnwrappedIfUnused))
+	e.writeBool(bool(v.TagWasOriginallyPropertyAccess))
+}
+
+func decode_js_ast_ETemplate(d *decoder, v *js_ast.ETemplate) {
+	decode_js_ast_Expr(d, &v.TagOrNil)
+	v.HeadRaw = d.readString()
+	decode_Slice_uint16(d, &v.HeadCooked)
+	decode_Slice_js_ast_TemplatePart(d, &v.Parts)
+	decode_logger_Loc(d, &v.HeadLoc)
+	decode_logger_Loc(d, &v.LegacyOctalLoc)
+	v.CanBeUnwrappedIfUnused = d.readBool()
+	v.TagWasOriginallyPropertyAccess = d.readBool()
+}
+
+func encode_js_ast_EThis(e *encoder, v *js_ast.EThis) {
+}
+
+func decode_js_ast_EThis(d *decoder, v *js_ast.EThis) {
+}
+
+func encode_js_ast_EUnary(e *encoder, v *js_ast.EUnary) {
+	encode_js_ast_Expr(e, &v.Value)
+	e.writeUvarint(uint64(v.Op))
+	e.writeBool(bool(v.WasOriginallyTypeofIdentifier))
+	e.writeBool(bool(v.WasOriginallyDeleteOfIdentifierOrPropertyAccess))
+}
+
+func decode_js_ast_EUnary(d *decoder, v *js_ast.EUnary) {
+	decode_js_ast_Expr(d, &v.Value)
+	v.Op = js_ast.OpCode(d.readUvarint())
+	v.WasOriginallyTypeofIdentifier = d.readBool()
+	v.WasOriginallyDeleteOfIdentifierOrPropertyAccess = d.readBool()
+}
+
+func encode_js_ast_EUndefined(e *encoder, v *js_ast.EUndefined) {
+}
+
+func decode_js_ast_EUndefined(d *decoder, v *js_ast.EUndefined) {
+}
+
+func encode_js_ast_EYield(e *encoder, v *js_ast.EYield) {
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+	e.writeBool(bool(v.IsStar))
+}
+
+func decode_js_ast_EYield(d *decoder, v *js_ast.EYield) {
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+	v.IsStar = d.readBool()
+}
+
+func encode_js_ast_EnumValue(e *encoder, v *js_ast.EnumValue) {
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+	encode_Slice_uint16(e, &v.Name)
+	encode_ast_Ref(e, &v.Ref)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_EnumValue(d *decoder, v *js_ast.EnumValue) {
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+	decode_Slice_uint16(d, &v.Name)
+	decode_ast_Ref(d, &v.Ref)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_ExportStarAlias(e *encoder, v *js_ast.ExportStarAlias) {
+	e.writeString(string(v.OriginalName))
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_ExportStarAlias(d *decoder, v *js_ast.ExportStarAlias) {
+	v.OriginalName = d.readString()
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Expr(e *encoder, v *js_ast.Expr) {
+	encode_js_ast_E(e, &v.Data)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_Expr(d *decoder, v *js_ast.Expr) {
+	decode_js_ast_E(d, &v.Data)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Finally(e *encoder, v *js_ast.Finally) {
+	encode_js_ast_SBlock(e, &v.Block)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_Finally(d *decoder, v *js_ast.Finally) {
+	decode_js_ast_SBlock(d, &v.Block)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Fn(e *encoder, v *js_ast.Fn) {
+	encode_Ptr_ast_LocRef(e, &v.Name)
+	encode_Slice_js_ast_Arg(e, &v.Args)
+	encode_js_ast_FnBody(e, &v.Body)
+	encode_ast_Ref(e, &v.ArgumentsRef)
+	encode_logger_Loc(e, &v.OpenParenLoc)
+	e.writeBool(bool(v.IsAsync))
+	e.writeBool(bool(v.IsGenerator))
+	e.writeBool(bool(v.HasRestArg))
+	e.writeBool(bool(v.HasIfScope))
+	e.writeBool(bool(v.HasNoSideEffectsComment))
+	e.writeBool(bool(v.IsUniqueFormalParameters))
+}
+
+func decode_js_ast_Fn(d *decoder, v *js_ast.Fn) {
+	decode_Ptr_ast_LocRef(d, &v.Name)
+	decode_Slice_js_ast_Arg(d, &v.Args)
+	decode_js_ast_FnBody(d, &v.Body)
+	decode_ast_Ref(d, &v.ArgumentsRef)
+	decode_logger_Loc(d, &v.OpenParenLoc)
+	v.IsAsync = d.readBool()
+	v.IsGenerator = d.readBool()
+	v.HasRestArg = d.readBool()
+	v.HasIfScope = d.readBool()
+	v.HasNoSideEffectsComment = d.readBool()
+	v.IsUniqueFormalParameters = d.readBool()
+}
+
+func encode_js_ast_FnBody(e *encoder, v *js_ast.FnBody) {
+	encode_js_ast_SBlock(e, &v.Block)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_FnBody(d *decoder, v *js_ast.FnBody) {
+	decode_js_ast_SBlock(d, &v.Block)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_FoldableConst(e *encoder, v *js_ast.FoldableConst) {
+	encode_Map_string_js_ast_ConstValue(e, &v.Properties)
+	encode_js_ast_ConstValue(e, &v.Value)
+}
+
+func decode_js_ast_FoldableConst(d *decoder, v *js_ast.FoldableConst) {
+	decode_Map_string_js_ast_ConstValue(d, &v.Properties)
+	decode_js_ast_ConstValue(d, &v.Value)
+}
+
+func encode_js_ast_ModuleTypeData(e *encoder, v *js_ast.ModuleTypeData) {
+	encode_Ptr_logger_Source(e, &v.Source)
+	encode_logger_Range(e, &v.Range)
+	e.writeUvarint(uint64(v.Type))
+}
+
+func decode_js_ast_ModuleTypeData(d *decoder, v *js_ast.ModuleTypeData) {
+	decode_Ptr_logger_Source(d, &v.Source)
+	decode_logger_Range(d, &v.Range)
+	v.Type = js_ast.ModuleType(d.readUvarint())
+}
+
+func encode_js_ast_NamedExport(e *encoder, v *js_ast.NamedExport) {
+	encode_ast_Ref(e, &v.Ref)
+	encode_logger_Loc(e, &v.AliasLoc)
+}
+
+func decode_js_ast_NamedExport(d *decoder, v *js_ast.NamedExport) {
+	decode_ast_Ref(d, &v.Ref)
+	decode_logger_Loc(d, &v.AliasLoc)
+}
+
+func encode_js_ast_NamedImport(e *encoder, v *js_ast.NamedImport) {
+	e.writeString(string(v.Alias))
+	encode_Slice_uint32(e, &v.LocalPartsWithUses)
+	encode_logger_Loc(e, &v.AliasLoc)
+	encode_ast_Ref(e, &v.NamespaceRef)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	e.writeBool(bool(v.AliasIsStar))
+	e.writeBool(bool(v.IsExported))
+}
+
+func decode_js_ast_NamedImport(d *decoder, v *js_ast.NamedImport) {
+	v.Alias = d.readString()
+	decode_Slice_uint32(d, &v.LocalPartsWithUses)
+	decode_logger_Loc(d, &v.AliasLoc)
+	decode_ast_Ref(d, &v.NamespaceRef)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	v.AliasIsStar = d.readBool()
+	v.IsExported = d.readBool()
+}
+
+func encode_js_ast_Part(e *encoder, v *js_ast.Part) {
+	encode_Slice_js_ast_Stmt(e, &v.Stmts)
+	encode_Slice_Ptr_js_ast_Scope(e, &v.Scopes)
+	encode_Slice_uint32(e, &v.ImportRecordIndices)
+	encode_Slice_js_ast_DeclaredSymbol(e, &v.DeclaredSymbols)
+	encode_Map_ast_Ref_js_ast_SymbolUse(e, &v.SymbolUses)
+	encode_Map_ast_Ref_js_ast_SymbolCallUse(e, &v.SymbolCallUses)
+	encode_Map_ast_Ref_Map_string_js_ast_SymbolUse(e, &v.ImportSymbolPropertyUses)
+	encode_Slice_js_ast_Dependency(e, &v.Dependencies)
+	e.writeBool(bool(v.CanBeRemovedIfUnused))
+	e.writeBool(bool(v.ForceTreeShaking))
+	e.writeBool(bool(v.IsLive))
+}
+
+func decode_js_ast_Part(d *decoder, v *js_ast.Part) {
+	decode_Slice_js_ast_Stmt(d, &v.Stmts)
+	decode_Slice_Ptr_js_ast_Scope(d, &v.Scopes)
+	decode_Slice_uint32(d, &v.ImportRecordIndices)
+	decode_Slice_js_ast_DeclaredSymbol(d, &v.DeclaredSymbols)
+	decode_Map_ast_Ref_js_ast_SymbolUse(d, &v.SymbolUses)
+	decode_Map_ast_Ref_js_ast_SymbolCallUse(d, &v.SymbolCallUses)
+	decode_Map_ast_Ref_Map_string_js_ast_SymbolUse(d, &v.ImportSymbolPropertyUses)
+	decode_Slice_js_ast_Dependency(d, &v.Dependencies)
+	v.CanBeRemovedIfUnused = d.readBool()
+	v.ForceTreeShaking = d.readBool()
+	v.IsLive = d.readBool()
+}
+
+func encode_js_ast_Property(e *encoder, v *js_ast.Property) {
+	encode_Ptr_js_ast_ClassStaticBlock(e, &v.ClassStaticBlock)
+	encode_js_ast_Expr(e, &v.Key)
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+	encode_js_ast_Expr(e, &v.InitializerOrNil)
+	encode_Slice_js_ast_Decorator(e, &v.Decorators)
+	encode_Ptr_js_ast_TSDecoratorMetadata(e, &v.TSDecoratorMetadata)
+	encode_logger_Loc(e, &v.Loc)
+	encode_logger_Loc(e, &v.CloseBracketLoc)
+	e.writeUvarint(uint64(v.Kind))
+	e.writeUvarint(uint64(v.Flags))
+}
+
+func decode_js_ast_Property(d *decoder, v *js_ast.Property) {
+	decode_Ptr_js_ast_ClassStaticBlock(d, &v.ClassStaticBlock)
+	decode_js_ast_Expr(d, &v.Key)
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+	decode_js_ast_Expr(d, &v.InitializerOrNil)
+	decode_Slice_js_ast_Decorator(d, &v.Decorators)
+	decode_Ptr_js_ast_TSDecoratorMetadata(d, &v.TSDecoratorMetadata)
+	decode_logger_Loc(d, &v.Loc)
+	decode_logger_Loc(d, &v.CloseBracketLoc)
+	v.Kind = js_ast.PropertyKind(d.readUvarint())
+	v.Flags = js_ast.PropertyFlags(d.readUvarint())
+}
+
+func encode_js_ast_PropertyBinding(e *encoder, v *js_ast.PropertyBinding) {
+	encode_js_ast_Expr(e, &v.Key)
+	encode_js_ast_Binding(e, &v.Value)
+	encode_js_ast_Expr(e, &v.DefaultValueOrNil)
+	encode_logger_Loc(e, &v.Loc)
+	encode_logger_Loc(e, &v.CloseBracketLoc)
+	e.writeBool(bool(v.IsComputed))
+	e.writeBool(bool(v.IsSpread))
+	e.writeBool(bool(v.PreferQuotedKey))
+}
+
+func decode_js_ast_PropertyBinding(d *decoder, v *js_ast.PropertyBinding) {
+	decode_js_ast_Expr(d, &v.Key)
+	decode_js_ast_Binding(d, &v.Value)
+	decode_js_ast_Expr(d, &v.DefaultValueOrNil)
+	decode_logger_Loc(d, &v.Loc)
+	decode_logger_Loc(d, &v.CloseBracketLoc)
+	v.IsComputed = d.readBool()
+	v.IsSpread = d.readBool()
+	v.PreferQuotedKey = d.readBool()
+}
+
+func encode_js_ast_S(e *encoder, v *js_ast.S) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *js_ast.SBlock:
+		e.writeUvarint(1)
+		encode_Ptr_js_ast_SBlock(e, &x)
+	case *js_ast.SBreak:
+		e.writeUvarint(2)
+		encode_Ptr_js_ast_SBreak(e, &x)
+	case *js_ast.SClass:
+		e.writeUvarint(3)
+		encode_Ptr_js_ast_SClass(e, &x)
+	case *js_ast.SComment:
+		e.writeUvarint(4)
+		encode_Ptr_js_ast_SComment(e, &x)
+	case *js_ast.SContinue:
+		e.writeUvarint(5)
+		encode_Ptr_js_ast_SContinue(e, &x)
+	case *js_ast.SDebugger:
+		e.writeUvarint(6)
+		encode_Ptr_js_ast_SDebugger(e, &x)
+	case *js_ast.SDirective:
+		e.writeUvarint(7)
+		encode_Ptr_js_ast_SDirective(e, &x)
+	case *js_ast.SDoWhile:
+		e.writeUvarint(8)
+		encode_Ptr_js_ast_SDoWhile(e, &x)
+	case *js_ast.SEmpty:
+		e.writeUvarint(9)
+		encode_Ptr_js_ast_SEmpty(e, &x)
+	case *js_ast.SEnum:
+		e.writeUvarint(10)
+		encode_Ptr_js_ast_SEnum(e, &x)
+	case *js_ast.SExportClause:
+		e.writeUvarint(11)
+		encode_Ptr_js_ast_SExportClause(e, &x)
+	case *js_ast.SExportDefault:
+		e.writeUvarint(12)
+		encode_Ptr_js_ast_SExportDefault(e, &x)
+	case *js_ast.SExportEquals:
+		e.writeUvarint(13)
+		encode_Ptr_js_ast_SExportEquals(e, &x)
+	case *js_ast.SExportFrom:
+		e.writeUvarint(14)
+		encode_Ptr_js_ast_SExportFrom(e, &x)
+	case *js_ast.SExportStar:
+		e.writeUvarint(15)
+		encode_Ptr_js_ast_SExportStar(e, &x)
+	case *js_ast.SExpr:
+		e.writeUvarint(16)
+		encode_Ptr_js_ast_SExpr(e, &x)
+	case *js_ast.SFor:
+		e.writeUvarint(17)
+		encode_Ptr_js_ast_SFor(e, &x)
+	case *js_ast.SForIn:
+		e.writeUvarint(18)
+		encode_Ptr_js_ast_SForIn(e, &x)
+	case *js_ast.SForOf:
+		e.writeUvarint(19)
+		encode_Ptr_js_ast_SForOf(e, &x)
+	case *js_ast.SFunction:
+		e.writeUvarint(20)
+		encode_Ptr_js_ast_SFunction(e, &x)
+	case *js_ast.SIf:
+		e.writeUvarint(21)
+		encode_Ptr_js_ast_SIf(e, &x)
+	case *js_ast.SImport:
+		e.writeUvarint(22)
+		encode_Ptr_js_ast_SImport(e, &x)
+	case *js_ast.SLabel:
+		e.writeUvarint(23)
+		encode_Ptr_js_ast_SLabel(e, &x)
+	case *js_ast.SLazyExport:
+		e.writeUvarint(24)
+		encode_Ptr_js_ast_SLazyExport(e, &x)
+	case *js_ast.SLocal:
+		e.writeUvarint(25)
+		encode_Ptr_js_ast_SLocal(e, &x)
+	case *js_ast.SNamespace:
+		e.writeUvarint(26)
+		encode_Ptr_js_ast_SNamespace(e, &x)
+	case *js_ast.SReturn:
+		e.writeUvarint(27)
+		encode_Ptr_js_ast_SReturn(e, &x)
+	case *js_ast.SSwitch:
+		e.writeUvarint(28)
+		encode_Ptr_js_ast_SSwitch(e, &x)
+	case *js_ast.SThrow:
+		e.writeUvarint(29)
+		encode_Ptr_js_ast_SThrow(e, &x)
+	case *js_ast.STry:
+		e.writeUvarint(30)
+		encode_Ptr_js_ast_STry(e, &x)
+	case *js_ast.STypeScript:
+		e.writeUvarint(31)
+		encode_Ptr_js_ast_STypeScript(e, &x)
+	case *js_ast.SWhile:
+		e.writeUvarint(32)
+		encode_Ptr_js_ast_SWhile(e, &x)
+	case *js_ast.SWith:
+		e.writeUvarint(33)
+		encode_Ptr_js_ast_SWith(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_js_ast_S(d *decoder, v *js_ast.S) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *js_ast.SBlock
+		decode_Ptr_js_ast_SBlock(d, &x)
+		*v = x
+	case 2:
+		var x *js_ast.SBreak
+		decode_Ptr_js_ast_SBreak(d, &x)
+		*v = x
+	case 3:
+		var x *js_ast.SClass
+		decode_Ptr_js_ast_SClass(d, &x)
+		*v = x
+	case 4:
+		var x *js_ast.SComment
+		decode_Ptr_js_ast_SComment(d, &x)
+		*v = x
+	case 5:
+		var x *js_ast.SContinue
+		decode_Ptr_js_ast_SContinue(d, &x)
+		*v = x
+	case 6:
+		var x *js_ast.SDebugger
+		decode_Ptr_js_ast_SDebugger(d, &x)
+		*v = x
+	case 7:
+		var x *js_ast.SDirective
+		decode_Ptr_js_ast_SDirective(d, &x)
+		*v = x
+	case 8:
+		var x *js_ast.SDoWhile
+		decode_Ptr_js_ast_SDoWhile(d, &x)
+		*v = x
+	case 9:
+		var x *js_ast.SEmpty
+		decode_Ptr_js_ast_SEmpty(d, &x)
+		*v = x
+	case 10:
+		var x *js_ast.SEnum
+		decode_Ptr_js_ast_SEnum(d, &x)
+		*v = x
+	case 11:
+		var x *js_ast.SExportClause
+		decode_Ptr_js_ast_SExportClause(d, &x)
+		*v = x
+	case 12:
+		var x *js_ast.SExportDefault
+		decode_Ptr_js_ast_SExportDefault(d, &x)
+		*v = x
+	case 13:
+		var x *js_ast.SExportEquals
+		decode_Ptr_js_ast_SExportEquals(d, &x)
+		*v = x
+	case 14:
+		var x *js_ast.SExportFrom
+		decode_Ptr_js_ast_SExportFrom(d, &x)
+		*v = x
+	case 15:
+		var x *js_ast.SExportStar
+		decode_Ptr_js_ast_SExportStar(d, &x)
+		*v = x
+	case 16:
+		var x *js_ast.SExpr
+		decode_Ptr_js_ast_SExpr(d, &x)
+		*v = x
+	case 17:
+		var x *js_ast.SFor
+		decode_Ptr_js_ast_SFor(d, &x)
+		*v = x
+	case 18:
+		var x *js_ast.SForIn
+		decode_Ptr_js_ast_SForIn(d, &x)
+		*v = x
+	case 19:
+		var x *js_ast.SForOf
+		decode_Ptr_js_ast_SForOf(d, &x)
+		*v = x
+	case 20:
+		var x *js_ast.SFunction
+		decode_Ptr_js_ast_SFunction(d, &x)
+		*v = x
+	case 21:
+		var x *js_ast.SIf
+		decode_Ptr_js_ast_SIf(d, &x)
+		*v = x
+	case 22:
+		var x *js_ast.SImport
+		decode_Ptr_js_ast_SImport(d, &x)
+		*v = x
+	case 23:
+		var x *js_ast.SLabel
+		decode_Ptr_js_ast_SLabel(d, &x)
+		*v = x
+	case 24:
+		var x *js_ast.SLazyExport
+		decode_Ptr_js_ast_SLazyExport(d, &x)
+		*v = x
+	case 25:
+		var x *js_ast.SLocal
+		decode_Ptr_js_ast_SLocal(d, &x)
+		*v = x
+	case 26:
+		var x *js_ast.SNamespace
+		decode_Ptr_js_ast_SNamespace(d, &x)
+		*v = x
+	case 27:
+		var x *js_ast.SReturn
+		decode_Ptr_js_ast_SReturn(d, &x)
+		*v = x
+	case 28:
+		var x *js_ast.SSwitch
+		decode_Ptr_js_ast_SSwitch(d, &x)
+		*v = x
+	case 29:
+		var x *js_ast.SThrow
+		decode_Ptr_js_ast_SThrow(d, &x)
+		*v = x
+	case 30:
+		var x *js_ast.STry
+		decode_Ptr_js_ast_STry(d, &x)
+		*v = x
+	case 31:
+		var x *js_ast.STypeScript
+		decode_Ptr_js_ast_STypeScript(d, &x)
+		*v = x
+	case 32:
+		var x *js_ast.SWhile
+		decode_Ptr_js_ast_SWhile(d, &x)
+		*v = x
+	case 33:
+		var x *js_ast.SWith
+		decode_Ptr_js_ast_SWith(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_js_ast_SBlock(e *encoder, v *js_ast.SBlock) {
+	encode_Slice_js_ast_Stmt(e, &v.Stmts)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_js_ast_SBlock(d *decoder, v *js_ast.SBlock) {
+	decode_Slice_js_ast_Stmt(d, &v.Stmts)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_js_ast_SBreak(e *encoder, v *js_ast.SBreak) {
+	encode_Ptr_ast_LocRef(e, &v.Label)
+}
+
+func decode_js_ast_SBreak(d *decoder, v *js_ast.SBreak) {
+	decode_Ptr_ast_LocRef(d, &v.Label)
+}
+
+func encode_js_ast_SClass(e *encoder, v *js_ast.SClass) {
+	encode_js_ast_Class(e, &v.Class)
+	e.writeBool(bool(v.IsExport))
+}
+
+func decode_js_ast_SClass(d *decoder, v *js_ast.SClass) {
+	decode_js_ast_Class(d, &v.Class)
+	v.IsExport = d.readBool()
+}
+
+func encode_js_ast_SComment(e *encoder, v *js_ast.SComment) {
+	e.writeString(string(v.Text))
+	e.writeBool(bool(v.IsLegalComment))
+}
+
+func decode_js_ast_SComment(d *decoder, v *js_ast.SComment) {
+	v.Text = d.readString()
+	v.IsLegalComment = d.readBool()
+}
+
+func encode_js_ast_SContinue(e *encoder, v *js_ast.SContinue) {
+	encode_Ptr_ast_LocRef(e, &v.Label)
+}
+
+func decode_js_ast_SContinue(d *decoder, v *js_ast.SContinue) {
+	decode_Ptr_ast_LocRef(d, &v.Label)
+}
+
+func encode_js_ast_SDebugger(e *encoder, v *js_ast.SDebugger) {
+}
+
+func decode_js_ast_SDebugger(d *decoder, v *js_ast.SDebugger) {
+}
+
+func encode_js_ast_SDirective(e *encoder, v *js_ast.SDirective) {
+	encode_Slice_uint16(e, &v.Value)
+	encode_logger_Loc(e, &v.LegacyOctalLoc)
+}
+
+func decode_js_ast_SDirective(d *decoder, v *js_ast.SDirective) {
+	decode_Slice_uint16(d, &v.Value)
+	decode_logger_Loc(d, &v.LegacyOctalLoc)
+}
+
+func encode_js_ast_SDoWhile(e *encoder, v *js_ast.SDoWhile) {
+	encode_js_ast_Stmt(e, &v.Body)
+	encode_js_ast_Expr(e, &v.Test)
+}
+
+func decode_js_ast_SDoWhile(d *decoder, v *js_ast.SDoWhile) {
+	decode_js_ast_Stmt(d, &v.Body)
+	decode_js_ast_Expr(d, &v.Test)
+}
+
+func encode_js_ast_SEmpty(e *encoder, v *js_ast.SEmpty) {
+}
+
+func decode_js_ast_SEmpty(d *decoder, v *js_ast.SEmpty) {
+}
+
+func encode_js_ast_SEnum(e *encoder, v *js_ast.SEnum) {
+	encode_Slice_js_ast_EnumValue(e, &v.Values)
+	encode_ast_LocRef(e, &v.Name)
+	encode_ast_Ref(e, &v.Arg)
+	e.writeBool(bool(v.IsExport))
+}
+
+func decode_js_ast_SEnum(d *decoder, v *js_ast.SEnum) {
+	decode_Slice_js_ast_EnumValue(d, &v.Values)
+	decode_ast_LocRef(d, &v.Name)
+	decode_ast_Ref(d, &v.Arg)
+	v.IsExport = d.readBool()
+}
+
+func encode_js_ast_SExportClause(e *encoder, v *js_ast.SExportClause) {
+	encode_Slice_js_ast_ClauseItem(e, &v.Items)
+	e.writeBool(bool(v.IsSingleLine))
+}
+
+func decode_js_ast_SExportClause(d *decoder, v *js_ast.SExportClause) {
+	decode_Slice_js_ast_ClauseItem(d, &v.Items)
+	v.IsSingleLine = d.readBool()
+}
+
+func encode_js_ast_SExportDefault(e *encoder, v *js_ast.SExportDefault) {
+	encode_js_ast_Stmt(e, &v.Value)
+	encode_ast_LocRef(e, &v.DefaultName)
+}
+
+func decode_js_ast_SExportDefault(d *decoder, v *js_ast.SExportDefault) {
+	decode_js_ast_Stmt(d, &v.Value)
+	decode_ast_LocRef(d, &v.DefaultName)
+}
+
+func encode_js_ast_SExportEquals(e *encoder, v *js_ast.SExportEquals) {
+	encode_js_ast_Expr(e, &v.Value)
+}
+
+func decode_js_ast_SExportEquals(d *decoder, v *js_ast.SExportEquals) {
+	decode_js_ast_Expr(d, &v.Value)
+}
+
+func encode_js_ast_SExportFrom(e *encoder, v *js_ast.SExportFrom) {
+	encode_Slice_js_ast_ClauseItem(e, &v.Items)
+	encode_ast_Ref(e, &v.NamespaceRef)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	e.writeBool(bool(v.IsSingleLine))
+}
+
+func decode_js_ast_SExportFrom(d *decoder, v *js_ast.SExportFrom) {
+	decode_Slice_js_ast_ClauseItem(d, &v.Items)
+	decode_ast_Ref(d, &v.NamespaceRef)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	v.IsSingleLine = d.readBool()
+}
+
+func encode_js_ast_SExportStar(e *encoder, v *js_ast.SExportStar) {
+	encode_Ptr_js_ast_ExportStarAlias(e, &v.Alias)
+	encode_ast_Ref(e, &v.NamespaceRef)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+}
+
+func decode_js_ast_SExportStar(d *decoder, v *js_ast.SExportStar) {
+	decode_Ptr_js_ast_ExportStarAlias(d, &v.Alias)
+	decode_ast_Ref(d, &v.NamespaceRef)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+}
+
+func encode_js_ast_SExpr(e *encoder, v *js_ast.SExpr) {
+	encode_js_ast_Expr(e, &v.Value)
+	e.writeBool(bool(v.IsFromClassOrFnThatCanBeRemovedIfUnused))
+}
+
+func decode_js_ast_SExpr(d *decoder, v *js_ast.SExpr) {
+	decode_js_ast_Expr(d, &v.Value)
+	v.IsFromClassOrFnThatCanBeRemovedIfUnused = d.readBool()
+}
+
+func encode_js_ast_SFor(e *encoder, v *js_ast.SFor) {
+	encode_js_ast_Stmt(e, &v.InitOrNil)
+	encode_js_ast_Expr(e, &v.TestOrNil)
+	encode_js_ast_Expr(e, &v.UpdateOrNil)
+	encode_js_ast_Stmt(e, &v.Body)
+}
+
+func decode_js_ast_SFor(d *decoder, v *js_ast.SFor) {
+	decode_js_ast_Stmt(d, &v.InitOrNil)
+	decode_js_ast_Expr(d, &v.TestOrNil)
+	decode_js_ast_Expr(d, &v.UpdateOrNil)
+	decode_js_ast_Stmt(d, &v.Body)
+}
+
+func encode_js_ast_SForIn(e *encoder, v *js_ast.SForIn) {
+	encode_js_ast_Stmt(e, &v.Init)
+	encode_js_ast_Expr(e, &v.Value)
+	encode_js_ast_Stmt(e, &v.Body)
+}
+
+func decode_js_ast_SForIn(d *decoder, v *js_ast.SForIn) {
+	decode_js_ast_Stmt(d, &v.Init)
+	decode_js_ast_Expr(d, &v.Value)
+	decode_js_ast_Stmt(d, &v.Body)
+}
+
+func encode_js_ast_SForOf(e *encoder, v *js_ast.SForOf) {
+	encode_js_ast_Stmt(e, &v.Init)
+	encode_js_ast_Expr(e, &v.Value)
+	encode_js_ast_Stmt(e, &v.Body)
+	encode_logger_Range(e, &v.Await)
+}
+
+func decode_js_ast_SForOf(d *decoder, v *js_ast.SForOf) {
+	decode_js_ast_Stmt(d, &v.Init)
+	decode_js_ast_Expr(d, &v.Value)
+	decode_js_ast_Stmt(d, &v.Body)
+	decode_logger_Range(d, &v.Await)
+}
+
+func encode_js_ast_SFunction(e *encoder, v *js_ast.SFunction) {
+	encode_js_ast_Fn(e, &v.Fn)
+	e.writeBool(bool(v.IsExport))
+}
+
+func decode_js_ast_SFunction(d *decoder, v *js_ast.SFunction) {
+	decode_js_ast_Fn(d, &v.Fn)
+	v.IsExport = d.readBool()
+}
+
+func encode_js_ast_SIf(e *encoder, v *js_ast.SIf) {
+	encode_js_ast_Expr(e, &v.Test)
+	encode_js_ast_Stmt(e, &v.Yes)
+	encode_js_ast_Stmt(e, &v.NoOrNil)
+}
+
+func decode_js_ast_SIf(d *decoder, v *js_ast.SIf) {
+	decode_js_ast_Expr(d, &v.Test)
+	decode_js_ast_Stmt(d, &v.Yes)
+	decode_js_ast_Stmt(d, &v.NoOrNil)
+}
+
+func encode_js_ast_SImport(e *encoder, v *js_ast.SImport) {
+	encode_Ptr_ast_LocRef(e, &v.DefaultName)
+	encode_Ptr_Slice_js_ast_ClauseItem(e, &v.Items)
+	encode_Ptr_logger_Loc(e, &v.StarNameLoc)
+	encode_ast_Ref(e, &v.NamespaceRef)
+	e.writeUvarint(uint64(v.ImportRecordIndex))
+	e.writeBool(bool(v.IsSingleLine))
+}
+
+func decode_js_ast_SImport(d *decoder, v *js_ast.SImport) {
+	decode_Ptr_ast_LocRef(d, &v.DefaultName)
+	decode_Ptr_Slice_js_ast_ClauseItem(d, &v.Items)
+	decode_Ptr_logger_Loc(d, &v.StarNameLoc)
+	decode_ast_Ref(d, &v.NamespaceRef)
+	v.ImportRecordIndex = uint32(d.readUvarint())
+	v.IsSingleLine = d.readBool()
+}
+
+func encode_js_ast_SLabel(e *encoder, v *js_ast.SLabel) {
+	encode_js_ast_Stmt(e, &v.Stmt)
+	encode_ast_LocRef(e, &v.Name)
+}
+
+func decode_js_ast_SLabel(d *decoder, v *js_ast.SLabel) {
+	decode_js_ast_Stmt(d, &v.Stmt)
+	decode_ast_LocRef(d, &v.Name)
+}
+
+func encode_js_ast_SLazyExport(e *encoder, v *js_ast.SLazyExport) {
+	encode_js_ast_Expr(e, &v.Value)
+}
+
+func decode_js_ast_SLazyExport(d *decoder, v *js_ast.SLazyExport) {
+	decode_js_ast_Expr(d, &v.Value)
+}
+
+func encode_js_ast_SLocal(e *encoder, v *js_ast.SLocal) {
+	encode_Slice_js_ast_Decl(e, &v.Decls)
+	e.writeUvarint(uint64(v.Kind))
+	e.writeBool(bool(v.IsExport))
+	e.writeBool(bool(v.WasTSImportEquals))
+}
+
+func decode_js_ast_SLocal(d *decoder, v *js_ast.SLocal) {
+	decode_Slice_js_ast_Decl(d, &v.Decls)
+	v.Kind = js_ast.LocalKind(d.readUvarint())
+	v.IsExport = d.readBool()
+	v.WasTSImportEquals = d.readBool()
+}
+
+func encode_js_ast_SNamespace(e *encoder, v *js_ast.SNamespace) {
+	encode_Slice_js_ast_Stmt(e, &v.Stmts)
+	encode_ast_LocRef(e, &v.Name)
+	encode_ast_Ref(e, &v.Arg)
+	e.writeBool(bool(v.IsExport))
+}
+
+func decode_js_ast_SNamespace(d *decoder, v *js_ast.SNamespace) {
+	decode_Slice_js_ast_Stmt(d, &v.Stmts)
+	decode_ast_LocRef(d, &v.Name)
+	decode_ast_Ref(d, &v.Arg)
+	v.IsExport = d.readBool()
+}
+
+func encode_js_ast_SReturn(e *encoder, v *js_ast.SReturn) {
+	encode_js_ast_Expr(e, &v.ValueOrNil)
+}
+
+func decode_js_ast_SReturn(d *decoder, v *js_ast.SReturn) {
+	decode_js_ast_Expr(d, &v.ValueOrNil)
+}
+
+func encode_js_ast_SSwitch(e *encoder, v *js_ast.SSwitch) {
+	encode_js_ast_Expr(e, &v.Test)
+	encode_Slice_js_ast_Case(e, &v.Cases)
+	encode_logger_Loc(e, &v.BodyLoc)
+	encode_logger_Loc(e, &v.CloseBraceLoc)
+}
+
+func decode_js_ast_SSwitch(d *decoder, v *js_ast.SSwitch) {
+	decode_js_ast_Expr(d, &v.Test)
+	decode_Slice_js_ast_Case(d, &v.Cases)
+	decode_logger_Loc(d, &v.BodyLoc)
+	decode_logger_Loc(d, &v.CloseBraceLoc)
+}
+
+func encode_js_ast_SThrow(e *encoder, v *js_ast.SThrow) {
+	encode_js_ast_Expr(e, &v.Value)
+}
+
+func decode_js_ast_SThrow(d *decoder, v *js_ast.SThrow) {
+	decode_js_ast_Expr(d, &v.Value)
+}
+
+func encode_js_ast_STry(e *encoder, v *js_ast.STry) {
+	encode_Ptr_js_ast_Catch(e, &v.Catch)
+	encode_Ptr_js_ast_Finally(e, &v.Finally)
+	encode_js_ast_SBlock(e, &v.Block)
+	encode_logger_Loc(e, &v.BlockLoc)
+}
+
+func decode_js_ast_STry(d *decoder, v *js_ast.STry) {
+	decode_Ptr_js_ast_Catch(d, &v.Catch)
+	decode_Ptr_js_ast_Finally(d, &v.Finally)
+	decode_js_ast_SBlock(d, &v.Block)
+	decode_logger_Loc(d, &v.BlockLoc)
+}
+
+func encode_js_ast_STypeScript(e *encoder, v *js_ast.STypeScript) {
+	e.writeBool(bool(v.WasDeclareClass))
+}
+
+func decode_js_ast_STypeScript(d *decoder, v *js_ast.STypeScript) {
+	v.WasDeclareClass = d.readBool()
+}
+
+func encode_js_ast_SWhile(e *encoder, v *js_ast.SWhile) {
+	encode_js_ast_Expr(e, &v.Test)
+	encode_js_ast_Stmt(e, &v.Body)
+}
+
+func decode_js_ast_SWhile(d *decoder, v *js_ast.SWhile) {
+	decode_js_ast_Expr(d, &v.Test)
+	decode_js_ast_Stmt(d, &v.Body)
+}
+
+func encode_js_ast_SWith(e *encoder, v *js_ast.SWith) {
+	encode_js_ast_Expr(e, &v.Value)
+	encode_js_ast_Stmt(e, &v.Body)
+	encode_logger_Loc(e, &v.BodyLoc)
+}
+
+func decode_js_ast_SWith(d *decoder, v *js_ast.SWith) {
+	decode_js_ast_Expr(d, &v.Value)
+	decode_js_ast_Stmt(d, &v.Body)
+	decode_logger_Loc(d, &v.BodyLoc)
+}
+
+func encode_js_ast_Scope(e *encoder, v *js_ast.Scope) {
+	encode_Ptr_js_ast_TSNamespaceScope(e, &v.TSNamespace)
+	encode_Ptr_js_ast_Scope(e, &v.Parent)
+	encode_Slice_Ptr_js_ast_Scope(e, &v.Children)
+	encode_Map_string_js_ast_ScopeMember(e, &v.Members)
+	encode_Slice_js_ast_ScopeMember(e, &v.Replaced)
+	encode_Slice_ast_Ref(e, &v.Generated)
+	encode_logger_Loc(e, &v.UseStrictLoc)
+	encode_ast_LocRef(e, &v.Label)
+	e.writeBool(bool(v.LabelStmtIsLoop))
+	e.writeBool(bool(v.ContainsDirectEval))
+	e.writeBool(bool(v.ForbidArguments))
+	e.writeBool(bool(v.IsAfterConstLocalPrefix))
+	e.writeUvarint(uint64(v.StrictMode))
+	e.writeUvarint(uint64(v.Kind))
+}
+
+func decode_js_ast_Scope(d *decoder, v *js_ast.Scope) {
+	decode_Ptr_js_ast_TSNamespaceScope(d, &v.TSNamespace)
+	decode_Ptr_js_ast_Scope(d, &v.Parent)
+	decode_Slice_Ptr_js_ast_Scope(d, &v.Children)
+	decode_Map_string_js_ast_ScopeMember(d, &v.Members)
+	decode_Slice_js_ast_ScopeMember(d, &v.Replaced)
+	decode_Slice_ast_Ref(d, &v.Generated)
+	decode_logger_Loc(d, &v.UseStrictLoc)
+	decode_ast_LocRef(d, &v.Label)
+	v.LabelStmtIsLoop = d.readBool()
+	v.ContainsDirectEval = d.readBool()
+	v.ForbidArguments = d.readBool()
+	v.IsAfterConstLocalPrefix = d.readBool()
+	v.StrictMode = js_ast.StrictModeKind(d.readUvarint())
+	v.Kind = js_ast.ScopeKind(d.readUvarint())
+}
+
+func encode_js_ast_ScopeMember(e *encoder, v *js_ast.ScopeMember) {
+	encode_ast_Ref(e, &v.Ref)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_ScopeMember(d *decoder, v *js_ast.ScopeMember) {
+	decode_ast_Ref(d, &v.Ref)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_Stmt(e *encoder, v *js_ast.Stmt) {
+	encode_js_ast_S(e, &v.Data)
+	encode_logger_Loc(e, &v.Loc)
+}
+
+func decode_js_ast_Stmt(d *decoder, v *js_ast.Stmt) {
+	decode_js_ast_S(d, &v.Data)
+	decode_logger_Loc(d, &v.Loc)
+}
+
+func encode_js_ast_SymbolCallUse(e *encoder, v *js_ast.SymbolCallUse) {
+	e.writeUvarint(uint64(v.CallCountEstimate))
+	e.writeUvarint(uint64(v.SingleArgNonSpreadCallCountEstimate))
+}
+
+func decode_js_ast_SymbolCallUse(d *decoder, v *js_ast.SymbolCallUse) {
+	v.CallCountEstimate = uint32(d.readUvarint())
+	v.SingleArgNonSpreadCallCountEstimate = uint32(d.readUvarint())
+}
+
+func encode_js_ast_SymbolUse(e *encoder, v *js_ast.SymbolUse) {
+	e.writeUvarint(uint64(v.CountEstimate))
+}
+
+func decode_js_ast_SymbolUse(d *decoder, v *js_ast.SymbolUse) {
+	v.CountEstimate = uint32(d.readUvarint())
+}
+
+func encode_js_ast_TSDecoratorMetadata(e *encoder, v *js_ast.TSDecoratorMetadata) {
+	encode_Slice_js_ast_TSMetadataType(e, &v.ParamTypes)
+	encode_js_ast_TSMetadataType(e, &v.Type)
+}
+
+func decode_js_ast_TSDecoratorMetadata(d *decoder, v *js_ast.TSDecoratorMetadata) {
+	decode_Slice_js_ast_TSMetadataType(d, &v.ParamTypes)
+	decode_js_ast_TSMetadataType(d, &v.Type)
+}
+
+func encode_js_ast_TSEnumValue(e *encoder, v *js_ast.TSEnumValue) {
+	encode_Slice_uint16(e, &v.String)
+	e.writeFloat64(float64(v.Number))
+}
+
+func decode_js_ast_TSEnumValue(d *decoder, v *js_ast.TSEnumValue) {
+	decode_Slice_uint16(d, &v.String)
+	v.Number = d.readFloat64()
+}
+
+func encode_js_ast_TSMetadataType(e *encoder, v *js_ast.TSMetadataType) {
+	encode_js_ast_Expr(e, &v.EntityOrNil)
+	e.writeString(string(v.Name))
+}
+
+func decode_js_ast_TSMetadataType(d *decoder, v *js_ast.TSMetadataType) {
+	decode_js_ast_Expr(d, &v.EntityOrNil)
+	v.Name = d.readString()
+}
+
+func encode_js_ast_TSNamespaceMember(e *encoder, v *js_ast.TSNamespaceMember) {
+	encode_js_ast_TSNamespaceMemberData(e, &v.Data)
+	encode_logger_Loc(e, &v.Loc)
+	e.writeBool(bool(v.IsEnumValue))
+}
+
+func decode_js_ast_TSNamespaceMember(d *decoder, v *js_ast.TSNamespaceMember) {
+	decode_js_ast_TSNamespaceMemberData(d, &v.Data)
+	decode_logger_Loc(d, &v.Loc)
+	v.IsEnumValue = d.readBool()
+}
+
+func encode_js_ast_TSNamespaceMemberData(e *encoder, v *js_ast.TSNamespaceMemberData) {
+	switch x := (*v).(type) {
+	case nil:
+		e.writeUvarint(0)
+	case *js_ast.TSNamespaceMemberEnumNumber:
+		e.writeUvarint(1)
+		encode_Ptr_js_ast_TSNamespaceMemberEnumNumber(e, &x)
+	case *js_ast.TSNamespaceMemberEnumString:
+		e.writeUvarint(2)
+		encode_Ptr_js_ast_TSNamespaceMemberEnumString(e, &x)
+	case *js_ast.TSNamespaceMemberNamespace:
+		e.writeUvarint(3)
+		encode_Ptr_js_ast_TSNamespaceMemberNamespace(e, &x)
+	case *js_ast.TSNamespaceMemberProperty:
+		e.writeUvarint(4)
+		encode_Ptr_js_ast_TSNamespaceMemberProperty(e, &x)
+	default:
+		e.fail(x)
+	}
+}
+
+func decode_js_ast_TSNamespaceMemberData(d *decoder, v *js_ast.TSNamespaceMemberData) {
+	switch d.readUvarint() {
+	case 0:
+		*v = nil
+	case 1:
+		var x *js_ast.TSNamespaceMemberEnumNumber
+		decode_Ptr_js_ast_TSNamespaceMemberEnumNumber(d, &x)
+		*v = x
+	case 2:
+		var x *js_ast.TSNamespaceMemberEnumString
+		decode_Ptr_js_ast_TSNamespaceMemberEnumString(d, &x)
+		*v = x
+	case 3:
+		var x *js_ast.TSNamespaceMemberNamespace
+		decode_Ptr_js_ast_TSNamespaceMemberNamespace(d, &x)
+		*v = x
+	case 4:
+		var x *js_ast.TSNamespaceMemberProperty
+		decode_Ptr_js_ast_TSNamespaceMemberProperty(d, &x)
+		*v = x
+	default:
+		panic(errCorruptCacheEntry)
+	}
+}
+
+func encode_js_ast_TSNamespaceMemberEnumNumber(e *encoder, v *js_ast.TSNamespaceMemberEnumNumber) {
+	e.writeFloat64(float64(v.Value))
+}
+
+func decode_js_ast_TSNamespaceMemberEnumNumber(d *decoder, v *js_ast.TSNamespaceMemberEnumNumber) {
+	v.Value = d.readFloat64()
+}
+
+func encode_js_ast_TSNamespaceMemberEnumString(e *encoder, v *js_ast.TSNamespaceMemberEnumString) {
+	encode_Slice_uint16(e, &v.Value)
+}
+
+func decode_js_ast_TSNamespaceMemberEnumString(d *decoder, v *js_ast.TSNamespaceMemberEnumString) {
+	decode_Slice_uint16(d, &v.Value)
+}
+
+func encode_js_ast_TSNamespaceMemberNamespace(e *encoder, v *js_ast.TSNamespaceMemberNamespace) {
+	encode_js_ast_TSNamespaceMembers(e, &v.ExportedMembers)
+}
+
+func decode_js_ast_TSNamespaceMemberNamespace(d *decoder, v *js_ast.TSNamespaceMemberNamespace) {
+	decode_js_ast_TSNamespaceMembers(d, &v.ExportedMembers)
+}
+
+func encode_js_ast_TSNamespaceMemberProperty(e *encoder, v *js_ast.TSNamespaceMemberProperty) {
+}
+
+func decode_js_ast_TSNamespaceMemberProperty(d *decoder, v *js_ast.TSNamespaceMemberProperty) {
+}
+
+func encode_js_ast_TSNamespaceMembers(e *encoder, v *js_ast.TSNamespaceMembers) {
+	e.writeLen(len(*v), *v == nil)
+	for key, value := range *v {
+		e.writeString(string(key))
+		encode_js_ast_TSNamespaceMember(e, &value)
+	}
+}
+
+func decode_js_ast_TSNamespaceMembers(d *decoder, v *js_ast.TSNamespaceMembers) {
+	n := d.readLen()
+	if n < 0 {
+		*v = nil
+		return
+	}
+	x := make(js_ast.TSNamespaceMembers, n)
+	for i := 0; i < n; i++ {
+		var key string
+		var value js_ast.TSNamespaceMember
+		key = d.readString()
+		decode_js_ast_TSNamespaceMember(d, &value)
+		x[key] = value
+	}
+	*v = x
+}
+
+func encode_js_ast_TSNamespaceScope(e *encoder, v *js_ast.TSNamespaceScope) {
+	encode_js_ast_TSNamespaceMembers(e, &v.ExportedMembers)
+	encode_Map_string_ast_Ref(e, &v.LazilyGeneratedProperyAccesses)
+	encode_ast_Ref(e, &v.ArgRef)
+	e.writeBool(bool(v.IsEnumScope))
+}
+
+func decode_js_ast_TSNamespaceScope(d *decoder, v *js_ast.TSNamespaceScope) {
+	decode_js_ast_TSNamespaceMembers(d, &v.ExportedMembers)
+	decode_Map_string_ast_Ref(d, &v.LazilyGeneratedProperyAccesses)
+	decode_ast_Ref(d, &v.ArgRef)
+	v.IsEnumScope = d.readBool()
+}
+
+func encode_js_ast_TemplatePart(e *encoder, v *js_ast.TemplatePart) {
+	encode_js_ast_Expr(e, &v.Value)
+	e.writeString(string(v.TailRaw))
+	encode_Slice_uint16(e, &v.TailCooked)
+	encode_logger_Loc(e, &v.TailLoc)
+}
+
+func decode_js_ast_TemplatePart(d *decoder, v *js_ast.TemplatePart) {
+	decode_js_ast_Expr(d, &v.Value)
+	v.TailRaw = d.readString()
+	decode_Slice_uint16(d, &v.TailCooked)
+	decode_logger_Loc(d, &v.TailLoc)
+}
+
+func encode_logger_Loc(e *encoder, v *logger.Loc) {
+	e.writeVarint(int64(v.Start))
+}
+
+func decode_logger_Loc(d *decoder, v *logger.Loc) {
+	v.Start = int32(d.readVarint())
+}
+
+func encode_logger_Msg(e *encoder, v *logger.Msg) {
+	encode_Slice_logger_MsgData(e, &v.Notes)
+	e.writeString(string(v.PluginName))
+	encode_logger_MsgData(e, &v.Data)
+	e.writeUvarint(uint64(v.Kind))
+	e.writeUvarint(uint64(v.ID))
+}
+
+func decode_logger_Msg(d *decoder, v *logger.Msg) {
+	decode_Slice_logger_MsgData(d, &v.Notes)
+	v.PluginName = d.readString()
+	decode_logger_MsgData(d, &v.Data)
+	v.Kind = logger.MsgKind(d.readUvarint())
+	v.ID = uint8(d.readUvarint())
+}
+
+func encode_logger_MsgData(e *encoder, v *logger.MsgData) {
+	encode_Any(e, &v.UserDetail)
+	encode_Ptr_logger_MsgLocation(e, &v.Location)
+	e.writeString(string(v.Text))
+	e.writeBool(bool(v.DisableMaximumWidth))
+}
+
+func decode_logger_MsgData(d *decoder, v *logger.MsgData) {
+	decode_Any(d, &v.UserDetail)
+	decode_Ptr_logger_MsgLocation(d, &v.Location)
+	v.Text = d.readString()
+	v.DisableMaximumWidth = d.readBool()
+}
+
+func encode_logger_MsgLocation(e *encoder, v *logger.MsgLocation) {
+	e.writeString(string(v.File))
+	e.writeString(string(v.Namespace))
+	e.writeString(string(v.LineText))
+	e.writeString(string(v.Suggestion))
+	e.writeVarint(int64(v.Line))
+	e.writeVarint(int64(v.Column))
+	e.writeVarint(int64(v.Length))
+}
+
+func decode_logger_MsgLocation(d *decoder, v *logger.MsgLocation) {
+	v.File = d.readString()
+	v.Namespace = d.readString()
+	v.LineText = d.readString()
+	v.Suggestion = d.readString()
+	v.Line = int(d.readVarint())
+	v.Column = int(d.readVarint())
+	v.Length = int(d.readVarint())
+}
+
+func encode_logger_Path(e *encoder, v *logger.Path) {
+	e.writeString(string(v.Text))
+	e.writeString(string(v.Namespace))
+	e.writeString(string(v.IgnoredSuffix))
+	encode_logger_ImportAttributes(e, &v.ImportAttributes)
+	e.writeUvarint(uint64(v.Flags))
+}
+
+func decode_logger_Path(d *decoder, v *logger.Path) {
+	v.Text = d.readString()
+	v.Namespace = d.readString()
+	v.IgnoredSuffix = d.readString()
+	decode_logger_ImportAttributes(d, &v.ImportAttributes)
+	v.Flags = logger.PathFlags(d.readUvarint())
+}
+
+func encode_logger_Range(e *encoder, v *logger.Range) {
+	encode_logger_Loc(e, &v.Loc)
+	e.writeVarint(int64(v.Len))
+}
+
+func decode_logger_Range(d *decoder, v *logger.Range) {
+	decode_logger_Loc(d, &v.Loc)
+	v.Len = int32(d.readVarint())
+}
+
+func encode_logger_Source(e *encoder, v *logger.Source) {
+	e.writeString(string(v.PrettyPath))
+	e.writeString(string(v.IdentifierName))
+	e.writeString(string(v.Contents))
+	encode_logger_Path(e, &v.KeyPath)
+	e.writeUvarint(uint64(v.Index))
+}
+
+func decode_logger_Source(d *decoder, v *logger.Source) {
+	v.PrettyPath = d.readString()
+	v.IdentifierName = d.readString()
+	v.Contents = d.readString()
+	decode_logger_Path(d, &v.KeyPath)
+	v.Index = uint32(d.readUvarint())
+}
+
+func encode_logger_Span(e *encoder, v *logger.Span) {
+	e.writeString(string(v.Text))
+	encode_logger_Range(e, &v.Range)
+}
+
+func decode_logger_Span(d *decoder, v *logger.Span) {
+	v.Text = d.readString()
+	decode_logger_Range(d, &v.Range)
+}
@@ -0,0 +1,428 @@
+package cache
+
+// This test generates the code in "cache_codec_table.go" using reflection and
+// fails if the checked-in code is out of date. Run "UPDATE_SNAPSHOTS=1 go test
+// ./internal/cache" to update it.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/css_ast"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// These are the types that are stored in the cache at the top level
+var codecRootTypes = []interface{}{
+	jsCacheData{},
+	cssCacheData{},
+	jsonCacheData{},
+}
+
+// Every concrete type that can be stored in an interface somewhere in the AST
+// must be listed here. The order matters since indices into this list are
+// part of the serialized format.
+var codecInterfaceTypes = []interface{}{
+	// js_ast.B
+	&js_ast.BArray{},
+	&js_ast.BIdentifier{},
+	&js_ast.BMissing{},
+	&js_ast.BObject{},
+
+	// js_ast.E
+	&js_ast.EAnnotation{},
+	&js_ast.EArray{},
+	&js_ast.EArrow{},
+	&js_ast.EAwait{},
+	&js_ast.EBigInt{},
+	&js_ast.EBinary{},
+	&js_ast.EBoolean{},
+	&js_ast.ECall{},
+	&js_ast.EClass{},
+	&js_ast.EDot{},
+	&js_ast.EFunction{},
+	&js_ast.EIdentifier{},
+	&js_ast.EIf{},
+	&js_ast.EImportCall{},
+	&js_ast.EImportIdentifier{},
+	&js_ast.EImportMeta{},
+	&js_ast.EImportPathString{},
+	&js_ast.EImportString{},
+	&js_ast.EIndex{},
+	&js_ast.EInlinedEnum{},
+	&js_ast.EJSXElement{},
+	&js_ast.EJSXText{},
+	&js_ast.EMissing{},
+	&js_ast.ENameOfSymbol{},
+	&js_ast.ENew{},
+	&js_ast.ENewTarget{},
+	&js_ast.ENull{},
+	&js_ast.ENumber{},
+	&js_ast.EObject{},
+	&js_ast.EPrivateIdentifier{},
+	&js_ast.ERegExp{},
+	&js_ast.ERequireResolveString{},
+	&js_ast.ERequireString{},
+	&js_ast.ESpread{},
+	&js_ast.EString{},
+	&js_ast.ESuper{},
+	&js_ast.ETemplate{},
+	&js_ast.EThis{},
+	&js_ast.EUnary{},
+	&js_ast.EUndefined{},
+	&js_ast.EYield{},
+
+	// js_ast.S
+	&js_ast.SBlock{},
+	&js_ast.SBreak{},
+	&js_ast.SClass{},
+	&js_ast.SComment{},
+	&js_ast.SContinue{},
+	&js_ast.SDebugger{},
+	&js_ast.SDirective{},
+	&js_ast.SDoWhile{},
+	&js_ast.SEmpty{},
+	&js_ast.SEnum{},
+	&js_ast.SExportClause{},
+	&js_ast.SExportDefault{},
+	&js_ast.SExportEquals{},
+	&js_ast.SExportFrom{},
+	&js_ast.SExportStar{},
+	&js_ast.SExpr{},
+	&js_ast.SFor{},
+	&js_ast.SForIn{},
+	&js_ast.SForOf{},
+	&js_ast.SFunction{},
+	&js_ast.SIf{},
+	&js_ast.SImport{},
+	&js_ast.SLabel{},
+	&js_ast.SLazyExport{},
+	&js_ast.SLocal{},
+	&js_ast.SNamespace{},
+	&js_ast.SReturn{},
+	&js_ast.SSwitch{},
+	&js_ast.SThrow{},
+	&js_ast.STry{},
+	&js_ast.STypeScript{},
+	&js_ast.SWhile{},
+	&js_ast.SWith{},
+
+	// js_ast.TSNamespaceMemberData
+	&js_ast.TSNamespaceMemberEnumNumber{},
+	&js_ast.TSNamespaceMemberEnumString{},
+	&js_ast.TSNamespaceMemberNamespace{},
+	&js_ast.TSNamespaceMemberProperty{},
+
+	// css_ast.R
+	&css_ast.RAtCharset{},
+	&css_ast.RAtImport{},
+	&css_ast.RAtKeyframes{},
+	&css_ast.RAtLayer{},
+	&css_ast.RBadDeclaration{},
+	&css_ast.RComment{},
+	&css_ast.RDeclaration{},
+	&css_ast.RKnownAt{},
+	&css_ast.RQualified{},
+	&css_ast.RSelector{},
+	&css_ast.RUnknownAt{},
+
+	// css_ast.SS
+	&css_ast.SSAttribute{},
+	&css_ast.SSClass{},
+	&css_ast.SSHash{},
+	&css_ast.SSPseudoClass{},
+	&css_ast.SSPseudoClassWithSelectorList{},
+}
+
+// These types have unexported fields and are handled by hand-written code
+var codecHandWrittenTypes = map[reflect.Type]bool{
+	reflect.TypeOf(ast.Ref{}):                  true,
+	reflect.TypeOf(ast.Index32{}):              true,
+	reflect.TypeOf(logger.ImportAttributes{}):  true,
+	reflect.TypeOf((*interface{})(nil)).Elem(): true,
+	reflect.TypeOf([]uint16{}):                 true,
+}
+
+type codecGenerator struct {
+	visited map[reflect.Type]bool
+	imports map[string]string
+	funcs   map[string]string
+	schema  []string
+	errors  []string
+}
+
+var thisPackagePath = reflect.TypeOf(jsCacheData{}).PkgPath()
+
+func (g *codecGenerator) typeExpr(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" || t.PkgPath() == thisPackagePath {
+			return t.Name()
+		}
+		name := path.Base(t.PkgPath())
+		g.imports[t.PkgPath()] = name
+		return name + "." + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + g.typeExpr(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeExpr(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeExpr(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("map[%s]%s", g.typeExpr(t.Key()), g.typeExpr(t.Elem()))
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}"
+		}
+	}
+	g.errors = append(g.errors, fmt.Sprintf("Unsupported type %s", t.String()))
+	return "?"
+}
+
+func (g *codecGenerator) funcName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		return path.Base(t.PkgPath()) + "_" + t.Name()
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "Ptr_" + g.funcName(t.Elem())
+	case reflect.Slice:
+		return "Slice_" + g.funcName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("Array%d_%s", t.Len(), g.funcName(t.Elem()))
+	case reflect.Map:
+		return fmt.Sprintf("Map_%s_%s", g.funcName(t.Key()), g.funcName(t.Elem()))
+	case reflect.Interface:
+		return "Any"
+	}
+	return "?"
+}
+
+func isBasicKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// This returns a statement that encodes the addressable expression "x"
+func (g *codecGenerator) encodeStmt(t reflect.Type, x string) string {
+	g.visit(t)
+	switch t.Kind() {
+	case reflect.Bool:
+		return fmt.Sprintf("e.writeBool(bool(%s))", x)
+	case reflect.String:
+		return fmt.Sprintf("e.writeString(string(%s))", x)
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("e.writeFloat64(float64(%s))", x)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprintf("e.writeVarint(int64(%s))", x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("e.writeUvarint(uint64(%s))", x)
+	}
+	return fmt.Sprintf("encode_%s(e, &%s)", g.funcName(t), x)
+}
+
+// This returns a statement that decodes into the addressable expression "x"
+func (g *codecGenerator) decodeStmt(t reflect.Type, x string) string {
+	g.visit(t)
+	if isBasicKind(t.Kind()) {
+		var read string
+		var basic string
+		switch t.Kind() {
+		case reflect.Bool:
+			read, basic = "d.readBool()", "bool"
+		case reflect.String:
+			read, basic = "d.readString()", "string"
+		case reflect.Float32, reflect.Float64:
+			read, basic = "d.readFloat64()", "float64"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			read, basic = "d.readVarint()", "int64"
+		default:
+			read, basic = "d.readUvarint()", "uint64"
+		}
+		if expr := g.typeExpr(t); expr != basic {
+			read = fmt.Sprintf("%s(%s)", expr, read)
+		}
+		return fmt.Sprintf("%s = %s", x, read)
+	}
+	return fmt.Sprintf("decode_%s(d, &%s)", g.funcName(t), x)
+}
+
+func (g *codecGenerator) visit(t reflect.Type) {
+	if g.visited[t] {
+		return
+	}
+	g.visited[t] = true
+	g.schema = append(g.schema, t.String())
+	if isBasicKind(t.Kind()) || codecHandWrittenTypes[t] {
+		return
+	}
+
+	name := g.funcName(t)
+	expr := g.typeExpr(t)
+	enc := strings.Builder{}
+	dec := strings.Builder{}
+	fmt.Fprintf(&enc, "func encode_%s(e *encoder, v *%s) {\n", name, expr)
+	fmt.Fprintf(&dec, "func decode_%s(d *decoder, v *%s) {\n", name, expr)
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				g.errors = append(g.errors, fmt.Sprintf("Cannot serialize unexported field %s in type %s", field.Name, t.String()))
+				continue
+			}
+			g.schema = append(g.schema, fmt.Sprintf("  %s %s", field.Name, field.Type.String()))
+			fmt.Fprintf(&enc, "%s\n", g.encodeStmt(field.Type, "v."+field.Name))
+			fmt.Fprintf(&dec, "%s\n", g.decodeStmt(field.Type, "v."+field.Name))
+		}
+
+	case reflect.Ptr:
+		elem := g.typeExpr(t.Elem())
+		fmt.Fprintf(&enc, "if e.writePointer(*v, *v == nil) {\n%s\n}\n", g.encodeStmt(t.Elem(), "(**v)"))
+		fmt.Fprintf(&dec, "ptr, isNew := d.readPointer()\n")
+		fmt.Fprintf(&dec, "if isNew {\nx := new(%s)\nd.addPointer(x)\n%s\n*v = x\n", elem, g.decodeStmt(t.Elem(), "(*x)"))
+		fmt.Fprintf(&dec, "} else if ptr == nil {\n*v = nil\n")
+		fmt.Fprintf(&dec, "} else if x, ok := ptr.(%s); ok {\n*v = x\n", expr)
+		fmt.Fprintf(&dec, "} else {\npanic(errCorruptCacheEntry)\n}\n")
+
+	case reflect.Slice:
+		fmt.Fprintf(&enc, "e.writeLen(len(*v), *v == nil)\nfor i := range *v {\n%s\n}\n", g.encodeStmt(t.Elem(), "(*v)[i]"))
+		fmt.Fprintf(&dec, "n := d.readLen()\nif n < 0 {\n*v = nil\nreturn\n}\n")
+		fmt.Fprintf(&dec, "x := make(%s, n)\nfor i := range x {\n%s\n}\n*v = x\n", expr, g.decodeStmt(t.Elem(), "x[i]"))
+
+	case reflect.Array:
+		fmt.Fprintf(&enc, "for i := range *v {\n%s\n}\n", g.encodeStmt(t.Elem(), "(*v)[i]"))
+		fmt.Fprintf(&dec, "for i := range *v {\n%s\n}\n", g.decodeStmt(t.Elem(), "(*v)[i]"))
+
+	case reflect.Map:
+		fmt.Fprintf(&enc, "e.writeLen(len(*v), *v == nil)\nfor key, value := range *v {\n%s\n%s\n}\n",
+			g.encodeStmt(t.Key(), "key"), g.encodeStmt(t.Elem(), "value"))
+		fmt.Fprintf(&dec, "n := d.readLen()\nif n < 0 {\n*v = nil\nreturn\n}\n")
+		fmt.Fprintf(&dec, "x := make(%s, n)\nfor i := 0; i < n; i++ {\nvar key %s\nvar value %s\n%s\n%s\nx[key] = value\n}\n*v = x\n",
+			expr, g.typeExpr(t.Key()), g.typeExpr(t.Elem()), g.decodeStmt(t.Key(), "key"), g.decodeStmt(t.Elem(), "value"))
+
+	case reflect.Interface:
+		fmt.Fprintf(&enc, "switch x := (*v).(type) {\ncase nil:\ne.writeUvarint(0)\n")
+		fmt.Fprintf(&dec, "switch d.readUvarint() {\ncase 0:\n*v = nil\n")
+		tag := 0
+		for _, it := range codecInterfaceTypes {
+			concrete := reflect.TypeOf(it)
+			if !concrete.Implements(t) {
+				continue
+			}
+			tag++
+			g.schema = append(g.schema, fmt.Sprintf("  %d %s", tag, concrete.String()))
+			concreteExpr := g.typeExpr(concrete)
+			fmt.Fprintf(&enc, "case %s:\ne.writeUvarint(%d)\n%s\n", concreteExpr, tag, g.encodeStmt(concrete, "x"))
+			fmt.Fprintf(&dec, "case %d:\nvar x %s\n%s\n*v = x\n", tag, concreteExpr, g.decodeStmt(concrete, "x"))
+		}
+		if tag == 0 {
+			g.errors = append(g.errors, fmt.Sprintf("No concrete types are registered for interface %s", t.String()))
+		}
+		fmt.Fprintf(&enc, "default:\ne.fail(x)\n}\n")
+		fmt.Fprintf(&dec, "default:\npanic(errCorruptCacheEntry)\n}\n")
+
+	default:
+		g.errors = append(g.errors, fmt.Sprintf("Unsupported type %s", t.String()))
+	}
+
+	enc.WriteString("}\n")
+	dec.WriteString("}\n")
+	g.funcs[name] = enc.String() + "\n" + dec.String()
+}
+
+func generateCodec() (string, []string) {
+	g := codecGenerator{
+		visited: make(map[reflect.Type]bool),
+		imports: make(map[string]string),
+		funcs:   make(map[string]string),
+	}
+	for _, it := range codecRootTypes {
+		g.visit(reflect.TypeOf(it))
+	}
+
+	// Every registered type must be reachable from one of the root types
+	for _, it := range codecInterfaceTypes {
+		if t := reflect.TypeOf(it); !g.visited[t] {
+			g.errors = append(g.errors, fmt.Sprintf("Type %s is registered but never used", t.String()))
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString("// This file was automatically generated by \"cache_codec_test.go\". Do not\n")
+	sb.WriteString("// edit it by hand. Run \"UPDATE_SNAPSHOTS=1 go test ./internal/cache\" to\n")
+	sb.WriteString("// update it.\n\npackage cache\n\nimport (\n")
+	paths := make([]string, 0, len(g.imports))
+	for importPath := range g.imports {
+		paths = append(paths, importPath)
+	}
+	sort.Strings(paths)
+	for _, importPath := range paths {
+		fmt.Fprintf(&sb, "%q\n", importPath)
+	}
+	sb.WriteString(")\n\n")
+
+	// This changes whenever the shape of any of these types changes
+	hash := sha256.Sum256([]byte(strings.Join(g.schema, "\n")))
+	fmt.Fprintf(&sb, "const codecSchemaHash = %q\n\n", hex.EncodeToString(hash[:]))
+
+	names := make([]string, 0, len(g.funcs))
+	for name := range g.funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(g.funcs[name])
+		sb.WriteString("\n")
+	}
+
+	source, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		g.errors = append(g.errors, err.Error())
+		return sb.String(), g.errors
+	}
+	return string(source), g.errors
+}
+
+func TestCodecIsUpToDate(t *testing.T) {
+	source, errors := generateCodec()
+	for _, err := range errors {
+		t.Error(err)
+	}
+	if len(errors) > 0 {
+		return
+	}
+
+	const file = "cache_codec_table.go"
+	if _, ok := os.LookupEnv("UPDATE_SNAPSHOTS"); ok {
+		if err := ioutil.WriteFile(file, []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	existing, err := ioutil.ReadFile(file)
+	if err != nil || string(existing) != source {
+		t.Fatalf("The file %q is out of date. Run \"UPDATE_SNAPSHOTS=1 go test ./internal/cache\" to update it.", file)
+	}
+}
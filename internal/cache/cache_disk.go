@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/evanw/esbuild/internal/css_ast"
+	"github.com/evanw/esbuild/internal/css_parser"
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_parser"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// This is an optional persistent cache that stores parsed ASTs in a directory
+// on the file system. Unlike the other caches, it's shared between esbuild
+// processes. The idea is to avoid re-parsing the same files over and over
+// again when esbuild is run many times on the same code (e.g. in CI).
+//
+// Entries are keyed by everything that the parser's output depends on:
+//
+//   - The version of esbuild itself (approximated by a hash of the running
+//     executable) and the shape of the AST data structures
+//
+//   - The contents and path of the file being parsed
+//
+//   - The parser options, which are converted into a string instead of being
+//     compared for equality like the in-memory caches do
+//
+//   - The log overrides, since they control which log messages are generated
+//
+// The key is hashed to form the name of the cache entry, so an entry is never
+// overwritten with different data. Stale entries are never used, but they
+// are also never deleted. It's up to the user to clear the cache directory if
+// it gets too big.
+//
+// All errors are ignored. A cache entry that can't be read or written is
+// treated as a cache miss.
+type DiskCache struct {
+	fs  fs.FS
+	dir string
+}
+
+// Bump this if the file format changes in a way that isn't captured by the
+// schema fingerprint
+const diskCacheFormatVersion = 1
+
+var diskCacheMagic = []byte("esbuild-cache\x00")
+
+type jsCacheData struct {
+	AST  js_ast.AST
+	Msgs []logger.Msg
+	OK   bool
+}
+
+type cssCacheData struct {
+	AST  css_ast.AST
+	Msgs []logger.Msg
+}
+
+type jsonCacheData struct {
+	Expr js_ast.Expr
+	Msgs []logger.Msg
+	OK   bool
+}
+
+func MakeDiskCache(fs fs.FS, dir string) *DiskCache {
+	return &DiskCache{fs: fs, dir: dir}
+}
+
+// This returns the name of the cache entry for the given parser input
+func (c *DiskCache) entryName(kind string, optionsKey string, source logger.Source, overrides map[logger.MsgID]logger.LogLevel) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "format=%d version=%s schema=%s kind=%s\x00", diskCacheFormatVersion,
+		esbuildFingerprint(), codecSchemaHash, kind)
+
+	// Note: "source.Index" is deliberately not part of the key since it's not
+	// stable across builds. Symbol references are remapped when loading instead.
+	fmt.Fprintf(hash, "path=%q,%q,%q,%d,%v pretty=%q identifier=%q\x00",
+		source.KeyPath.Text, source.KeyPath.Namespace, source.KeyPath.IgnoredSuffix, source.KeyPath.Flags,
+		source.KeyPath.ImportAttributes.Decode(), source.PrettyPath, source.IdentifierName)
+	if len(overrides) > 0 {
+		keys := make([]int, 0, len(overrides))
+		for id := range overrides {
+			keys = append(keys, int(id))
+		}
+		sort.Ints(keys)
+		for _, id := range keys {
+			fmt.Fprintf(hash, "override=%d,%d\x00", id, overrides[logger.MsgID(id)])
+		}
+	}
+	io.WriteString(hash, optionsKey)
+	hash.Write([]byte{0})
+	io.WriteString(hash, source.Contents)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func (c *DiskCache) entryPath(name string) string {
+	// Spread the entries out over subdirectories to keep directories small
+	return c.fs.Join(c.dir, name[:2], name[2:])
+}
+
+// This returns the serialized data for the entry along with the source index
+// that the file had when the entry was written
+func (c *DiskCache) load(name string) (payload []byte, oldSourceIndex uint32, ok bool) {
+	if c == nil || name == "" {
+		return nil, 0, false
+	}
+	fs.BeforeFileOpen()
+	buffer, err := ioutil.ReadFile(c.entryPath(name))
+	fs.AfterFileClose()
+	if err != nil || len(buffer) < len(diskCacheMagic)+4 || string(buffer[:len(diskCacheMagic)]) != string(diskCacheMagic) {
+		return nil, 0, false
+	}
+	buffer = buffer[len(diskCacheMagic):]
+	return buffer[4:], binary.LittleEndian.Uint32(buffer[:4]), true
+}
+
+func (c *DiskCache) store(name string, sourceIndex uint32, payload []byte) {
+	if c == nil || name == "" {
+		return
+	}
+	buffer := make([]byte, 0, len(diskCacheMagic)+4+len(payload))
+	buffer = append(buffer, diskCacheMagic...)
+	var index [4]byte
+	binary.LittleEndian.PutUint32(index[:], sourceIndex)
+	buffer = append(buffer, index[:]...)
+	buffer = append(buffer, payload...)
+
+	// Write to a temporary file and then rename it into place. That way other
+	// processes reading from the cache concurrently never observe a partially-
+	// written entry.
+	path := c.entryPath(name)
+	dir := c.fs.Dir(path)
+	if err := fs.MkdirAll(c.fs, dir, 0755); err != nil {
+		return
+	}
+	fs.BeforeFileOpen()
+	defer fs.AfterFileClose()
+	file, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(buffer)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+}
+
+func (c *DiskCache) loadJS(name string, sourceIndex uint32) (jsCacheData, bool) {
+	if payload, oldSourceIndex, ok := c.load(name); ok {
+		if data, err := decodeJSCacheData(payload, oldSourceIndex, sourceIndex); err == nil {
+			return data, true
+		}
+	}
+	return jsCacheData{}, false
+}
+
+func (c *DiskCache) storeJS(name string, sourceIndex uint32, data *jsCacheData) {
+	if payload, err := encodeJSCacheData(data); err == nil {
+		c.store(name, sourceIndex, payload)
+	}
+}
+
+func (c *DiskCache) loadCSS(name string, sourceIndex uint32) (cssCacheData, bool) {
+	if payload, oldSourceIndex, ok := c.load(name); ok {
+		if data, err := decodeCSSCacheData(payload, oldSourceIndex, sourceIndex); err == nil {
+			return data, true
+		}
+	}
+	return cssCacheData{}, false
+}
+
+func (c *DiskCache) storeCSS(name string, sourceIndex uint32, data *cssCacheData) {
+	if payload, err := encodeCSSCacheData(data); err == nil {
+		c.store(name, sourceIndex, payload)
+	}
+}
+
+func (c *DiskCache) loadJSON(name string, sourceIndex uint32) (jsonCacheData, bool) {
+	if payload, oldSourceIndex, ok := c.load(name); ok {
+		if data, err := decodeJSONCacheData(payload, oldSourceIndex, sourceIndex); err == nil {
+			return data, true
+		}
+	}
+	return jsonCacheData{}, false
+}
+
+func (c *DiskCache) storeJSON(name string, sourceIndex uint32, data *jsonCacheData) {
+	if payload, err := encodeJSONCacheData(data); err == nil {
+		c.store(name, sourceIndex, payload)
+	}
+}
+
+// These return the name of the cache entry for the given parser input, or the
+// empty string if the persistent cache is disabled or the input can't be cached
+
+func (c *DiskCache) jsEntryName(source logger.Source, options *js_parser.Options, overrides map[logger.MsgID]logger.LogLevel) string {
+	if c == nil {
+		return ""
+	}
+	optionsKey, ok := options.PersistentCacheKey()
+	if !ok {
+		return ""
+	}
+	return c.entryName("js", optionsKey, source, overrides)
+}
+
+func (c *DiskCache) cssEntryName(source logger.Source, options *css_parser.Options, overrides map[logger.MsgID]logger.LogLevel) string {
+	if c == nil {
+		return ""
+	}
+	return c.entryName("css", options.PersistentCacheKey(), source, overrides)
+}
+
+func (c *DiskCache) jsonEntryName(source logger.Source, options js_parser.JSONOptions, overrides map[logger.MsgID]logger.LogLevel) string {
+	if c == nil {
+		return ""
+	}
+	return c.entryName("json", fmt.Sprintf("%#v", options), source, overrides)
+}
+
+var esbuildFingerprintOnce sync.Once
+var esbuildFingerprintValue string
+
+// Parser behavior can change between esbuild versions without changing the
+// shape of the AST, so cache entries must also be invalidated whenever
+// esbuild itself changes. The version number isn't available here, so the
+// contents of the running executable are hashed instead. This is done once
+// per process. The module version from the build info is used as a fallback
+// when the executable can't be read (e.g. when running as WebAssembly).
+func esbuildFingerprint() string {
+	esbuildFingerprintOnce.Do(func() {
+		if path, err := os.Executable(); err == nil {
+			if file, err := os.Open(path); err == nil {
+				hash := sha256.New()
+				_, err := io.Copy(hash, file)
+				file.Close()
+				if err == nil {
+					esbuildFingerprintValue = "exe:" + hex.EncodeToString(hash.Sum(nil))
+					return
+				}
+			}
+		}
+
+		sb := strings.Builder{}
+		sb.WriteString("build:")
+		if info, ok := debug.ReadBuildInfo(); ok {
+			fmt.Fprintf(&sb, "%s@%s", info.Main.Path, info.Main.Version)
+			for _, dep := range info.Deps {
+				if dep.Path == "github.com/evanw/esbuild" {
+					fmt.Fprintf(&sb, ",%s@%s", dep.Path, dep.Version)
+				}
+			}
+		}
+		esbuildFingerprintValue = sb.String()
+	})
+	return esbuildFingerprintValue
+}
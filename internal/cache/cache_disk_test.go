@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/evanw/esbuild/internal/config"
+	"github.com/evanw/esbuild/internal/css_parser"
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/js_parser"
+	"github.com/evanw/esbuild/internal/logger"
+	"github.com/evanw/esbuild/internal/test"
+)
+
+func makeTestDiskCache(t *testing.T) (*DiskCache, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "esbuild-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	realFS, err := fs.RealFS(fs.RealFSOptions{AbsWorkingDir: dir, DoNotCache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return MakeDiskCache(realFS, dir), func() { os.RemoveAll(dir) }
+}
+
+func testSource(index uint32, path string, contents string) logger.Source {
+	return logger.Source{
+		Index:          index,
+		KeyPath:        logger.Path{Text: path, Namespace: "file"},
+		PrettyPath:     path,
+		IdentifierName: "entry",
+		Contents:       contents,
+	}
+}
+
+func expectDiskCacheRoundTripJS(t *testing.T, loader config.Loader, contents string) {
+	t.Helper()
+	disk, cleanup := makeTestDiskCache(t)
+	defer cleanup()
+
+	options := config.Options{Mode: config.ModeBundle}
+	switch loader {
+	case config.LoaderTS:
+		options.TS.Parse = true
+	case config.LoaderJSX:
+		options.JSX.Parse = true
+	}
+	parserOptions := js_parser.OptionsFromConfig(&options)
+
+	// The first parse populates the persistent cache
+	first := MakeCacheSet()
+	first.SetDiskCache(disk)
+	log := logger.NewDeferLog(logger.DeferLogAll, nil)
+	first.JSCache.Parse(log, testSource(5, "/entry.js", contents), parserOptions)
+	expectedMsgs := log.Done()
+
+	// A different process would likely assign a different source index
+	source := testSource(7, "/entry.js", contents)
+	name := disk.jsEntryName(source, &parserOptions, nil)
+	if name == "" {
+		t.Fatal("Expected the file to be cacheable")
+	}
+	data, ok := disk.loadJS(name, source.Index)
+	if !ok {
+		t.Fatal("Expected a persistent cache hit")
+	}
+
+	// The loaded AST must match what the parser would have produced
+	log = logger.NewDeferLog(logger.DeferLogAll, nil)
+	expected, expectedOK := js_parser.Parse(log, source, parserOptions)
+	log.Done()
+	test.AssertEqual(t, data.OK, expectedOK)
+	test.AssertEqual(t, len(data.Msgs), len(expectedMsgs))
+	if !reflect.DeepEqual(data.AST, expected) {
+		t.Fatal("The AST loaded from the persistent cache is different")
+	}
+}
+
+func TestDiskCacheJS(t *testing.T) {
+	expectDiskCacheRoundTripJS(t, config.LoaderJS, "")
+	expectDiskCacheRoundTripJS(t, config.LoaderJS, `
+		import def, { a as b, c } from './foo'
+		import * as ns from './bar' with { type: 'json' }
+		export * from './baz'
+		export { b, ns as default }
+		export let [x, ...y] = [1, 2, 3], { z = 4, ...w } = {}
+		function* gen() { yield* [1n, 2.5, 'str', `+"`tpl${x}`"+`, /re/g] }
+		async function fn(a = this, { b } = {}) { await import('./lazy'); for await (const q of a) ; }
+		class Foo extends def { #p = 1; static { this.q = new.target } get [c]() { return super.x?.y ?? this.#p } }
+		label: for (var i in w) { if (i) continue label; else break label }
+		try { throw new Error } catch { debugger } finally { with (x) ; }
+		switch (typeof x) { case 'a': delete x.y; default: void 0 }
+		module.exports = require('./cjs'), exports.foo = () => ({ ...y, [x]: null, undefined })
+	`)
+	expectDiskCacheRoundTripJS(t, config.LoaderJS, "let x = ; // This is a syntax error")
+	expectDiskCacheRoundTripJS(t, config.LoaderJSX, "export default <div a='b' {...c}>text{1}<></></div>")
+	expectDiskCacheRoundTripJS(t, config.LoaderTS, `
+		enum E { A, B = 'b' }
+		namespace ns.inner { export const x = E.A }
+		declare module 'foo' { export let y: number }
+		export class C<T> { constructor(private readonly p: T) {} }
+	`)
+}
+
+func TestDiskCacheCSS(t *testing.T) {
+	disk, cleanup := makeTestDiskCache(t)
+	defer cleanup()
+
+	contents := `
+		@charset "UTF-8";
+		@import url("foo.css") layer(base) supports(display: grid);
+		@layer a, b;
+		@keyframes spin { from { rotate: 0 } to { rotate: 1turn } }
+		@media (min-width: 100px) { a#id.cls[href^="x" i]:hover:not(.x) > b::before { color: red !important } }
+		.parent { &:focus { color: #fff } }
+		/* comment */ @unknown foo { bar }
+	`
+	options := css_parser.OptionsFromConfig(config.LoaderLocalCSS, &config.Options{})
+	first := MakeCacheSet()
+	first.SetDiskCache(disk)
+	first.CSSCache.Parse(logger.NewDeferLog(logger.DeferLogAll, nil), testSource(5, "/entry.css", contents), options)
+
+	source := testSource(7, "/entry.css", contents)
+	data, ok := disk.loadCSS(disk.cssEntryName(source, &options, nil), source.Index)
+	if !ok {
+		t.Fatal("Expected a persistent cache hit")
+	}
+	expected := css_parser.Parse(logger.NewDeferLog(logger.DeferLogAll, nil), source, options)
+	if !reflect.DeepEqual(data.AST, expected) {
+		t.Fatal("The AST loaded from the persistent cache is different")
+	}
+}
+
+func TestDiskCacheInvalidation(t *testing.T) {
+	disk, cleanup := makeTestDiskCache(t)
+	defer cleanup()
+
+	options := js_parser.OptionsFromConfig(&config.Options{})
+	source := testSource(5, "/entry.js", "let x = 1")
+	caches := MakeCacheSet()
+	caches.SetDiskCache(disk)
+	caches.JSCache.Parse(logger.NewDeferLog(logger.DeferLogAll, nil), source, options)
+	name := disk.jsEntryName(source, &options, nil)
+
+	// Changing the contents must change the key
+	changed := source
+	changed.Contents = "let x = 2"
+	if disk.jsEntryName(changed, &options, nil) == name {
+		t.Fatal("Expected a different key for different contents")
+	}
+
+	// Changing the parser options must change the key
+	otherOptions := js_parser.OptionsFromConfig(&config.Options{MinifySyntax: true})
+	if disk.jsEntryName(source, &otherOptions, nil) == name {
+		t.Fatal("Expected a different key for different options")
+	}
+
+	// Changing the log overrides must change the key
+	overrides := map[logger.MsgID]logger.LogLevel{logger.MsgID_JS_DuplicateCase: logger.LevelError}
+	if disk.jsEntryName(source, &options, overrides) == name {
+		t.Fatal("Expected a different key for different log overrides")
+	}
+
+	// Corrupt entries must be ignored
+	if err := ioutil.WriteFile(disk.entryPath(name), []byte("esbuild-cache\x00\x05\x00\x00\x00garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := disk.loadJS(name, source.Index); ok {
+		t.Fatal("Expected a corrupt entry to be a cache miss")
+	}
+}
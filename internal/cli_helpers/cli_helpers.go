@@ -41,6 +41,8 @@ func ParseLoader(text string) (api.Loader, *ErrorWithNote) {
 		return api.LoaderFile, nil
 	case "global-css":
 		return api.LoaderGlobalCSS, nil
+	case "html":
+		return api.LoaderHTML, nil
 	case "js":
 		return api.LoaderJS, nil
 	case "json":
@@ -58,7 +60,7 @@ func ParseLoader(text string) (api.Loader, *ErrorWithNote) {
 	default:
 		return api.LoaderNone, MakeErrorWithNote(
 			fmt.Sprintf("Invalid loader value: %q", text),
-			"Valid values are \"base64\", \"binary\", \"copy\", \"css\", \"dataurl\", \"empty\", \"file\", \"global-css\", \"js\", \"json\", \"jsx\", \"local-css\", \"text\", \"ts\", or \"tsx\".",
+			"Valid values are \"base64\", \"binary\", \"copy\", \"css\", \"dataurl\", \"empty\", \"file\", \"global-css\", \"html\", \"js\", \"json\", \"jsx\", \"local-css\", \"text\", \"ts\", or \"tsx\".",
 		)
 	}
 }
@@ -106,6 +106,7 @@ func (tsConfig *TSConfigJSX) ApplyTo(jsxOptions *JSXOptions) {
 // Note: This can currently only contain primitive values. It's compared
 // for equality using a structural equality comparison by the JS parser.
 type TSConfig struct {
+	EmitDecoratorMetadata   MaybeBool
 	ExperimentalDecorators  MaybeBool
 	ImportsNotUsedAsValues  TSImportsNotUsedAsValues
 	PreserveValueImports    MaybeBool
@@ -116,6 +117,9 @@ type TSConfig struct {
 
 // This is used for "extends" in "tsconfig.json"
 func (derived *TSConfig) ApplyExtendedConfig(base TSConfig) {
+	if base.EmitDecoratorMetadata != Unspecified {
+		derived.EmitDecoratorMetadata = base.EmitDecoratorMetadata
+	}
 	if base.ExperimentalDecorators != Unspecified {
 		derived.ExperimentalDecorators = base.ExperimentalDecorators
 	}
@@ -194,6 +198,7 @@ const (
 	LoaderEmpty
 	LoaderFile
 	LoaderGlobalCSS
+	LoaderHTML
 	LoaderJS
 	LoaderJSON
 	LoaderWithTypeJSON // Has a "with { type: 'json' }" attribute
@@ -216,6 +221,7 @@ var LoaderToString = []string{
 	"empty",
 	"file",
 	"global-css",
+	"html",
 	"js",
 	"json",
 	"json",
@@ -293,10 +299,59 @@ const (
 	//   export {...};
 	//
 	FormatESModule
+
+	// The AMD format looks like this:
+	//
+	//   define(["require", ...externals], function(require) {
+	//     ... bundled code ...
+	//     return exports;
+	//   });
+	//
+	FormatAMD
+
+	// The UMD format looks like this:
+	//
+	//   (function(root, factory) {
+	//     if (typeof define === "function" && define.amd) define([...], factory);
+	//     else if (typeof module === "object" && module.exports) module.exports = factory(require);
+	//     else root.globalName = factory();
+	//   })(typeof self !== "undefined" ? self : this, function(require) {
+	//     ... bundled code ...
+	//     return exports;
+	//   });
+	//
+	FormatUMD
+
+	// The SystemJS format looks like this:
+	//
+	//   System.register([...dependencies], function(exports, module) {
+	//     var ...imports;
+	//     return {
+	//       setters: [...],
+	//       execute: function() {
+	//         ... bundled code ...
+	//         exports({...});
+	//       }
+	//     };
+	//   });
+	//
+	FormatSystemJS
 )
 
 func (f Format) KeepESMImportExportSyntax() bool {
-	return f == FormatPreserve || f == FormatESModule
+	return f == FormatPreserve || f == FormatESModule || f == FormatSystemJS
+}
+
+// The AMD and UMD formats put the bundled code in a factory function that
+// returns the exports, so they are generated almost exactly like an IIFE
+func (f Format) IsIIFELike() bool {
+	return f == FormatIIFE || f == FormatAMD || f == FormatUMD
+}
+
+// The SystemJS format is generated as an ES module and then the top-level
+// import and export statements are converted into a "System.register" call
+func (f Format) IsESModuleLike() bool {
+	return f == FormatESModule || f == FormatSystemJS
 }
 
 func (f Format) String() string {
@@ -307,6 +362,12 @@ func (f Format) String() string {
 		return "cjs"
 	case FormatESModule:
 		return "esm"
+	case FormatAMD:
+		return "amd"
+	case FormatUMD:
+		return "umd"
+	case FormatSystemJS:
+		return "system"
 	}
 	return ""
 }
@@ -332,6 +393,39 @@ func (matchers ExternalMatchers) HasMatchers() bool {
 	return len(matchers.Exact) > 0 || len(matchers.Patterns) > 0
 }
 
+// This assigns all files matching any of the patterns to a single named chunk
+// when code splitting. Patterns are matched against the file's path relative
+// to the current working directory (i.e. the path in the metafile).
+type ManualChunk struct {
+	Name     string
+	Paths    map[string]bool
+	Patterns []WildcardPattern
+}
+
+func (chunk ManualChunk) Matches(path string) bool {
+	if chunk.Paths[path] {
+		return true
+	}
+	for _, pattern := range chunk.Patterns {
+		if pattern.Matches(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// Manual chunk names are substituted for the "[name]" placeholder in the
+// chunk path template, so they can't be used to escape the output directory
+func IsValidManualChunkName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, "/\\")
+}
+
+func (pattern WildcardPattern) Matches(path string) bool {
+	return len(path) >= len(pattern.Prefix)+len(pattern.Suffix) &&
+		strings.HasPrefix(path, pattern.Prefix) &&
+		strings.HasSuffix(path, pattern.Suffix)
+}
+
 type ExternalSettings struct {
 	PreResolve  ExternalMatchers
 	PostResolve ExternalMatchers
@@ -411,6 +505,7 @@ type Options struct {
 	ExternalSettings ExternalSettings
 	ExternalPackages bool
 	PackageAliases   map[string]string
+	UMDGlobals       map[string][]string
 
 	AbsOutputFile      string
 	AbsOutputDir       string
@@ -420,8 +515,14 @@ type Options struct {
 	GlobalName         []string
 	TSConfigPath       string
 	TSConfigRaw        string
+	ImportMapPath      string
+	ImportMapRaw       string
 	ExtensionToLoader  map[string]Loader
 
+	// If non-empty, parsed ASTs are persisted in this directory and are shared
+	// with other esbuild processes
+	AbsCacheDir string
+
 	PublicPath      string
 	InjectPaths     []string
 	InjectedDefines []InjectedDefine
@@ -436,6 +537,23 @@ type Options struct {
 	ChunkPathTemplate []PathTemplate
 	AssetPathTemplate []PathTemplate
 
+	// This maps the source index of each file that was bundled as a worker to
+	// the path of its output file relative to the output directory. It's filled
+	// in by the bundler after each worker has been linked.
+	WorkerOutputPaths map[uint32]string
+
+	// When code splitting, these are used to group files into named chunks
+	// instead of grouping them by which entry points can reach them. The
+	// callback (if present) takes precedence over the patterns. Manual chunks
+	// are checked in order and the first match wins.
+	ManualChunks        []ManualChunk
+	ManualChunkCallback func(path logger.Path) string
+
+	// When code splitting, automatically-generated shared chunks smaller than
+	// this many bytes of input are merged into other shared chunks if doing so
+	// doesn't cause any additional side effects to run
+	MinChunkSize int
+
 	Plugins    []Plugin
 	SourceRoot string
 	Stdin      *StdinInfo
@@ -454,15 +572,32 @@ type Options struct {
 	TS                TSOptions
 	Mode              Mode
 	PreserveSymlinks  bool
+	DedupePackages    bool
+	TreeShakeCommonJS bool
 	MinifyWhitespace  bool
 	MinifyIdentifiers bool
 	MinifySyntax      bool
 	ProfilerNames     bool
 	CodeSplitting     bool
+	PreserveModules   bool
 	WatchMode         bool
 	AllowOverwrite    bool
 	LegalComments     LegalComments
 
+	// With "PreserveModules", output files for modules inside "node_modules"
+	// are placed in this directory (relative to the output directory) instead
+	VendorDir string
+
+	// If true, every module is wrapped and registered with a global registry
+	// so that individual modules can be replaced at run time
+	HotModuleReplacement bool
+
+	// If true, a ".d.ts" file is generated next to the output file for each
+	// TypeScript entry point. This is done without type checking (i.e. like
+	// TypeScript's "isolatedDeclarations" setting) so declarations that need
+	// type inference are errors.
+	TSDeclaration bool
+
 	// If true, make sure to generate a single file that can be written to stdout
 	WriteToStdout bool
 
@@ -470,6 +605,7 @@ type Options struct {
 	OmitJSXRuntimeForTests bool
 	ASCIIOnly              bool
 	KeepNames              bool
+	LooseForOf             bool
 	IgnoreDCEAnnotations   bool
 	TreeShaking            bool
 	DropDebugger           bool
@@ -477,6 +613,7 @@ type Options struct {
 	Platform               Platform
 	OutputFormat           Format
 	NeedsMetafile          bool
+	NeedsMetafileWhy       bool
 	SourceMap              SourceMap
 	ExcludeSourcesContent  bool
 }
@@ -662,7 +799,8 @@ func SubstituteTemplate(template []PathTemplate, placeholders PathPlaceholders)
 }
 
 func ShouldCallRuntimeRequire(mode Mode, outputFormat Format) bool {
-	return mode == ModeBundle && outputFormat != FormatCommonJS
+	// The AMD and UMD formats pass the real "require" function to the factory
+	return mode == ModeBundle && outputFormat != FormatCommonJS && outputFormat != FormatAMD && outputFormat != FormatUMD
 }
 
 type InjectedDefine struct {
@@ -119,6 +119,13 @@ func (a *Options) Equal(b *Options) bool {
 	return true
 }
 
+// This is used as part of a persistent build cache key. Unlike "Equal", the
+// result must uniquely identify these options across different processes.
+func (options *Options) PersistentCacheKey() string {
+	// Note: The "fmt" package prints maps with their keys in sorted order
+	return fmt.Sprintf("%#v %v", options.optionsThatSupportStructuralEquality, options.cssPrefixData)
+}
+
 func Parse(log logger.Log, source logger.Source, options Options) css_ast.AST {
 	result := css_lexer.Tokenize(log, source, css_lexer.Options{
 		RecordAllComments: options.minifyIdentifiers,
@@ -108,6 +108,9 @@ type LinkerGraph struct {
 	// This is for cross-module inlining of detected inlinable constants
 	ConstValues map[ast.Ref]js_ast.ConstValue
 
+	// This is for cross-module folding of conditions that test constants
+	FoldableConsts map[ast.Ref]js_ast.FoldableConst
+
 	// We should avoid traversing all files in the bundle, because the linker
 	// should be able to run a linking operation on a large bundle where only
 	// a few files are needed (e.g. an incremental compilation scenario). This
@@ -272,6 +275,7 @@ func CloneLinkerGraph(
 	// Do a final quick pass over all files
 	var tsEnums map[ast.Ref]map[string]js_ast.TSEnumValue
 	var constValues map[ast.Ref]js_ast.ConstValue
+	var foldableConsts map[ast.Ref]js_ast.FoldableConst
 	bitCount := uint(len(entryPoints))
 	for _, sourceIndex := range reachableFiles {
 		file := &files[sourceIndex]
@@ -300,12 +304,23 @@ func CloneLinkerGraph(
 				constValues[ref] = value
 			}
 		}
+
+		// Also merge foldable constants into one big map as well
+		if repr, ok := file.InputFile.Repr.(*JSRepr); ok && repr.AST.FoldableConsts != nil {
+			if foldableConsts == nil {
+				foldableConsts = make(map[ast.Ref]js_ast.FoldableConst)
+			}
+			for ref, value := range repr.AST.FoldableConsts {
+				foldableConsts[ref] = value
+			}
+		}
 	}
 
 	return LinkerGraph{
 		Symbols:             symbols,
 		TSEnums:             tsEnums,
 		ConstValues:         constValues,
+		FoldableConsts:      foldableConsts,
 		entryPoints:         entryPoints,
 		Files:               files,
 		ReachableFiles:      reachableFiles,
@@ -318,6 +333,73 @@ func (g *LinkerGraph) EntryPoints() []EntryPoint {
 	return g.entryPoints
 }
 
+// Dynamic imports are turned into entry points before linking, but the linker
+// may remove "import()" expressions later on (e.g. when folding a condition
+// that tests a constant imported from another file). This removes the entry
+// points for files that are no longer dynamically imported from anywhere so
+// that no chunk is generated for them. It must be called before tree shaking
+// because the entry bits are reallocated.
+func (g *LinkerGraph) RemoveUnusedDynamicImportEntryPoints() {
+	hasDynamicImportEntryPoints := false
+	for _, entryPoint := range g.entryPoints {
+		if g.Files[entryPoint.SourceIndex].entryPointKind == entryPointDynamicImport {
+			hasDynamicImportEntryPoints = true
+			break
+		}
+	}
+	if !hasDynamicImportEntryPoints {
+		return
+	}
+
+	// Find all files that are still dynamically imported. Imports in files that
+	// can only be reached through removed imports don't count.
+	visited := make(map[uint32]bool)
+	isDynamicallyImported := make(map[uint32]bool)
+	var visit func(sourceIndex uint32)
+	visit = func(sourceIndex uint32) {
+		if visited[sourceIndex] {
+			return
+		}
+		visited[sourceIndex] = true
+		if repr, ok := g.Files[sourceIndex].InputFile.Repr.(*JSRepr); ok {
+			for _, record := range repr.AST.ImportRecords {
+				if !record.SourceIndex.IsValid() || record.Flags.Has(ast.IsUnused) {
+					continue
+				}
+				otherSourceIndex := record.SourceIndex.GetIndex()
+				if record.Kind == ast.ImportDynamic {
+					isDynamicallyImported[otherSourceIndex] = true
+				}
+				visit(otherSourceIndex)
+			}
+		}
+	}
+	for _, entryPoint := range g.entryPoints {
+		if g.Files[entryPoint.SourceIndex].entryPointKind == entryPointUserSpecified {
+			visit(entryPoint.SourceIndex)
+		}
+	}
+
+	entryPoints := g.entryPoints[:0]
+	for _, entryPoint := range g.entryPoints {
+		if file := &g.Files[entryPoint.SourceIndex]; file.entryPointKind == entryPointDynamicImport && !isDynamicallyImported[entryPoint.SourceIndex] {
+			file.entryPointKind = entryPointNone
+			continue
+		}
+		entryPoints = append(entryPoints, entryPoint)
+	}
+	if len(entryPoints) == len(g.entryPoints) {
+		return
+	}
+	g.entryPoints = entryPoints
+
+	// Reallocate the entry bit sets now that the number of entry points changed
+	bitCount := uint(len(entryPoints))
+	for _, sourceIndex := range g.ReachableFiles {
+		g.Files[sourceIndex].EntryBits = helpers.NewBitSet(bitCount)
+	}
+}
+
 func (g *LinkerGraph) AddPartToFile(sourceIndex uint32, part js_ast.Part) uint32 {
 	// Invariant: this map is never null
 	if part.SymbolUses == nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/evanw/esbuild/internal/ast"
 	"github.com/evanw/esbuild/internal/config"
 	"github.com/evanw/esbuild/internal/css_ast"
+	"github.com/evanw/esbuild/internal/html_ast"
 	"github.com/evanw/esbuild/internal/js_ast"
 	"github.com/evanw/esbuild/internal/logger"
 	"github.com/evanw/esbuild/internal/resolver"
@@ -40,6 +41,17 @@ type OutputFile struct {
 	// fully assembled later.
 	JSONMetadataChunk string
 
+	// If hot module replacement is enabled, this maps the path of each module
+	// in this file to a hash of that module's generated code
+	HMRModuleHashes map[string]string
+
+	// If this is the output file for an entry point chunk, this is the source
+	// index of that entry point. HTML entry points use this to find the output
+	// files for the scripts and stylesheets that they reference. A JavaScript
+	// entry point that imports CSS has two of these (one for each language).
+	EntryPointSourceIndex ast.Index32
+	IsCSS                 bool
+
 	AbsPath      string
 	Contents     []byte
 	IsExecutable bool
@@ -117,6 +129,14 @@ func (repr *CSSRepr) ImportRecords() *[]ast.ImportRecord {
 	return &repr.AST.ImportRecords
 }
 
+type HTMLRepr struct {
+	AST html_ast.AST
+}
+
+func (repr *HTMLRepr) ImportRecords() *[]ast.ImportRecord {
+	return &repr.AST.ImportRecords
+}
+
 type CopyRepr struct {
 	// The URL that replaces the contents of any import record paths for this file
 	URLForCode string
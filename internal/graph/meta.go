@@ -147,6 +147,12 @@ type JSReprMeta struct {
 	// the "exports" variable.
 	ForceIncludeExportsForEntryPoint bool
 
+	// If true, this was an ESM file that was converted to CommonJS so that it
+	// can be replaced at run time by hot module replacement. Its exports are
+	// always generated and are marked with "__esModule" so that importers can
+	// still tell that it used to be an ES module.
+	IsHMRModuleFromESM bool
+
 	// This is set when we need to pull in the "__export" symbol in to the part
 	// at "nsExportPartIndex". This can't be done in "createExportsForFile"
 	// because of concurrent map hazards. Instead, it must be done later.
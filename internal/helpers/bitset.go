@@ -1,6 +1,9 @@
 package helpers
 
-import "bytes"
+import (
+	"bytes"
+	"math/bits"
+)
 
 type BitSet struct {
 	entries []byte
@@ -25,3 +28,19 @@ func (bs BitSet) Equals(other BitSet) bool {
 func (bs BitSet) String() string {
 	return string(bs.entries)
 }
+
+func (bs BitSet) Union(other BitSet) BitSet {
+	result := BitSet{make([]byte, len(bs.entries))}
+	for i, b := range bs.entries {
+		result.entries[i] = b | other.entries[i]
+	}
+	return result
+}
+
+// Returns the number of bits that are set in this set but not in the other set
+func (bs BitSet) CountBitsNotIn(other BitSet) (count int) {
+	for i, b := range bs.entries {
+		count += bits.OnesCount8(b &^ other.entries[i])
+	}
+	return
+}
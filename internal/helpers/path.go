@@ -20,3 +20,32 @@ func IsInsideNodeModules(path string) bool {
 		path = dir
 	}
 }
+
+func JoinWithPublicPath(publicPath string, relPath string) string {
+	if strings.HasPrefix(relPath, "./") {
+		relPath = relPath[2:]
+
+		// Strip any amount of further no-op slashes (i.e. ".///././/x/y" => "x/y")
+		for {
+			if strings.HasPrefix(relPath, "/") {
+				relPath = relPath[1:]
+			} else if strings.HasPrefix(relPath, "./") {
+				relPath = relPath[2:]
+			} else {
+				break
+			}
+		}
+	}
+
+	// Use a relative path if there is no public path
+	if publicPath == "" {
+		publicPath = "."
+	}
+
+	// Join with a slash
+	slash := "/"
+	if strings.HasSuffix(publicPath, "/") {
+		slash = ""
+	}
+	return publicPath + slash + relPath
+}
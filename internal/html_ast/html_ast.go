@@ -0,0 +1,57 @@
+package html_ast
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// HTML files aren't parsed into a tree. The bundler only needs to know which
+// attributes contain URLs so that it can bundle the files they reference and
+// then substitute the final output paths back into the original text. So the
+// AST is just a list of ranges in the original file. Everything else is
+// copied through verbatim, which means the output is always byte-for-byte
+// identical to the input except for the rewritten URLs.
+
+type AST struct {
+	ImportRecords []ast.ImportRecord
+
+	// There is exactly one URL for each import record, in the same order. This
+	// means the import record index for a URL is the same as its index here.
+	URLs []URL
+}
+
+type URLKind uint8
+
+const (
+	// A URL that is used as-is by the browser, such as "<img src>". The file it
+	// references must be loaded with a loader that generates a URL such as the
+	// "file" or "copy" loaders.
+	URLAsset URLKind = iota
+
+	// A "<script src>" attribute. The file it references is bundled as an
+	// additional entry point.
+	URLScript
+
+	// A "<link rel=stylesheet href>" attribute. The file it references is
+	// bundled as an additional entry point.
+	URLStylesheet
+)
+
+type URL struct {
+	// This is the range of the attribute value not including the quotes. For
+	// "srcset" attributes, this is the range of a single URL in the list.
+	Range logger.Range
+
+	// This is the location of the "<" character that starts the tag containing
+	// this URL. It's used to insert additional tags before this one.
+	TagLoc logger.Loc
+
+	Kind URLKind
+
+	// This is the quote character around the attribute value, or 0 if the
+	// attribute value is unquoted. It's needed to escape the new value.
+	Quote byte
+
+	// This is true for "<script type=module>" tags
+	IsModule bool
+}
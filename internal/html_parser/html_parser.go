@@ -0,0 +1,407 @@
+package html_parser
+
+// This is not a full HTML parser. It only tokenizes enough of the HTML syntax
+// to reliably find the attributes that reference other files. That means it
+// needs to understand tags, attributes, comments, and the elements whose
+// contents are raw text (e.g. "<script>" and "<style>"), but it doesn't need
+// to construct a DOM tree or to handle implied and misnested tags.
+//
+// The tokenizer follows the HTML specification where it matters: tag and
+// attribute names are case-insensitive, attribute values can be quoted with
+// either quote character or unquoted, and character references in attribute
+// values are decoded. Malformed input never causes an error since browsers
+// never report errors for HTML either.
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/html_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+type parser struct {
+	text          string
+	importRecords []ast.ImportRecord
+	urls          []html_ast.URL
+}
+
+type attribute struct {
+	name       string
+	value      string
+	valueRange logger.Range
+	quote      byte
+}
+
+func Parse(source logger.Source) html_ast.AST {
+	p := parser{text: source.Contents}
+	p.parse()
+	return html_ast.AST{
+		ImportRecords: p.importRecords,
+		URLs:          p.urls,
+	}
+}
+
+func (p *parser) parse() {
+	text := p.text
+	i := 0
+
+	for i < len(text) {
+		lt := strings.IndexByte(text[i:], '<')
+		if lt == -1 {
+			break
+		}
+		i += lt
+
+		switch {
+		case strings.HasPrefix(text[i:], "<!--"):
+			// Skip over comments
+			if end := strings.Index(text[i+4:], "-->"); end != -1 {
+				i += 4 + end + 3
+			} else {
+				i = len(text)
+			}
+
+		case strings.HasPrefix(text[i:], "<!") || strings.HasPrefix(text[i:], "<?") || strings.HasPrefix(text[i:], "</"):
+			// Skip over doctypes, processing instructions, and end tags
+			if end := strings.IndexByte(text[i:], '>'); end != -1 {
+				i += end + 1
+			} else {
+				i = len(text)
+			}
+
+		case i+1 < len(text) && isASCIILetter(text[i+1]):
+			tagLoc := logger.Loc{Start: int32(i)}
+			name, attributes, end := p.parseStartTag(i + 1)
+			i = end
+			p.visitTag(tagLoc, name, attributes)
+
+			// The contents of these elements are raw text and can't contain tags
+			switch name {
+			case "script", "style", "textarea", "title", "xmp", "iframe", "noembed", "noframes", "plaintext":
+				i = skipRawText(text, i, name)
+			}
+
+		default:
+			i++
+		}
+	}
+}
+
+func (p *parser) parseStartTag(i int) (name string, attributes []attribute, end int) {
+	text := p.text
+
+	// Parse the tag name
+	start := i
+	for i < len(text) && !isWhitespace(text[i]) && text[i] != '/' && text[i] != '>' {
+		i++
+	}
+	name = strings.ToLower(text[start:i])
+
+	// Parse the attributes
+	for {
+		for i < len(text) && (isWhitespace(text[i]) || text[i] == '/') {
+			i++
+		}
+		if i >= len(text) {
+			return name, attributes, i
+		}
+		if text[i] == '>' {
+			return name, attributes, i + 1
+		}
+
+		// Parse the attribute name. Note that the first character of an
+		// attribute name is allowed to be "=".
+		nameStart := i
+		i++
+		for i < len(text) && !isWhitespace(text[i]) && text[i] != '/' && text[i] != '>' && text[i] != '=' {
+			i++
+		}
+		attr := attribute{name: strings.ToLower(text[nameStart:i])}
+
+		// Parse the optional attribute value
+		j := i
+		for j < len(text) && isWhitespace(text[j]) {
+			j++
+		}
+		if j < len(text) && text[j] == '=' {
+			i = j + 1
+			for i < len(text) && isWhitespace(text[i]) {
+				i++
+			}
+			if i < len(text) && (text[i] == '"' || text[i] == '\'') {
+				attr.quote = text[i]
+				valueStart := i + 1
+				valueEnd := strings.IndexByte(text[valueStart:], attr.quote)
+				if valueEnd == -1 {
+					valueEnd = len(text)
+					i = len(text)
+				} else {
+					valueEnd += valueStart
+					i = valueEnd + 1
+				}
+				attr.valueRange = logger.Range{Loc: logger.Loc{Start: int32(valueStart)}, Len: int32(valueEnd - valueStart)}
+			} else {
+				valueStart := i
+				for i < len(text) && !isWhitespace(text[i]) && text[i] != '>' {
+					i++
+				}
+				attr.valueRange = logger.Range{Loc: logger.Loc{Start: int32(valueStart)}, Len: int32(i - valueStart)}
+			}
+			attr.value = decodeCharacterReferences(text[attr.valueRange.Loc.Start:attr.valueRange.End()])
+		}
+		attributes = append(attributes, attr)
+	}
+}
+
+// This returns the index after the end tag of the raw text element
+func skipRawText(text string, i int, name string) int {
+	for {
+		end := strings.Index(text[i:], "</")
+		if end == -1 {
+			return len(text)
+		}
+		i += end + 2
+		if len(text)-i >= len(name) && strings.EqualFold(text[i:i+len(name)], name) {
+			if after := i + len(name); after == len(text) || isWhitespace(text[after]) || text[after] == '/' || text[after] == '>' {
+				return i - 2
+			}
+		}
+	}
+}
+
+func (p *parser) visitTag(tagLoc logger.Loc, name string, attributes []attribute) {
+	switch name {
+	case "script":
+		isModule := false
+		if attr, ok := findAttribute(attributes, "type"); ok {
+			switch strings.ToLower(strings.TrimSpace(attr.value)) {
+			case "module":
+				isModule = true
+			case "", "text/javascript", "application/javascript":
+			default:
+				// Ignore things like "<script type=text/template>"
+				return
+			}
+		}
+		if attr, ok := findAttribute(attributes, "src"); ok {
+			p.addURL(tagLoc, attr, html_ast.URLScript, isModule)
+		}
+
+	case "link":
+		rel, ok := findAttribute(attributes, "rel")
+		if !ok {
+			return
+		}
+		kind := html_ast.URLKind(0)
+		isKnown := false
+		for _, token := range strings.Fields(strings.ToLower(rel.value)) {
+			switch token {
+			case "stylesheet":
+				kind = html_ast.URLStylesheet
+				isKnown = true
+			case "icon", "apple-touch-icon", "apple-touch-icon-precomposed", "mask-icon", "manifest":
+				if !isKnown {
+					kind = html_ast.URLAsset
+					isKnown = true
+				}
+			}
+		}
+		if attr, ok := findAttribute(attributes, "href"); ok && isKnown {
+			p.addURL(tagLoc, attr, kind, false)
+		}
+
+	case "img", "source":
+		if attr, ok := findAttribute(attributes, "src"); ok {
+			p.addURL(tagLoc, attr, html_ast.URLAsset, false)
+		}
+		if attr, ok := findAttribute(attributes, "srcset"); ok {
+			p.addSrcSet(tagLoc, attr)
+		}
+
+	case "video":
+		if attr, ok := findAttribute(attributes, "src"); ok {
+			p.addURL(tagLoc, attr, html_ast.URLAsset, false)
+		}
+		if attr, ok := findAttribute(attributes, "poster"); ok {
+			p.addURL(tagLoc, attr, html_ast.URLAsset, false)
+		}
+
+	case "audio", "track":
+		if attr, ok := findAttribute(attributes, "src"); ok {
+			p.addURL(tagLoc, attr, html_ast.URLAsset, false)
+		}
+	}
+}
+
+func (p *parser) addURL(tagLoc logger.Loc, attr attribute, kind html_ast.URLKind, isModule bool) {
+	// Leading and trailing whitespace is ignored in URL attributes
+	raw := p.text[attr.valueRange.Loc.Start:attr.valueRange.End()]
+	start := attr.valueRange.Loc.Start + int32(len(raw)-len(strings.TrimLeft(raw, whitespace)))
+	end := attr.valueRange.End() - int32(len(raw)-len(strings.TrimRight(raw, whitespace)))
+	if start > end {
+		start = end
+	}
+	p.addURLForRange(tagLoc, logger.Range{Loc: logger.Loc{Start: start}, Len: end - start}, attr.quote, kind, isModule)
+}
+
+// A "srcset" attribute is a comma-separated list of image candidates. Each
+// candidate is a URL followed by an optional descriptor such as "2x":
+//
+//	<img srcset="image-1x.png, image-2x.png 2x">
+func (p *parser) addSrcSet(tagLoc logger.Loc, attr attribute) {
+	text := p.text
+	i := int(attr.valueRange.Loc.Start)
+	end := int(attr.valueRange.End())
+
+	for i < end {
+		// Skip leading whitespace and commas
+		for i < end && (isWhitespace(text[i]) || text[i] == ',') {
+			i++
+		}
+		if i >= end {
+			break
+		}
+
+		// The URL is everything up to the next whitespace character. Any commas
+		// at the end of the URL separate it from the next candidate.
+		urlStart := i
+		for i < end && !isWhitespace(text[i]) {
+			i++
+		}
+		urlEnd := i
+		for urlEnd > urlStart && text[urlEnd-1] == ',' {
+			urlEnd--
+		}
+		p.addURLForRange(tagLoc, logger.Range{Loc: logger.Loc{Start: int32(urlStart)}, Len: int32(urlEnd - urlStart)}, attr.quote, html_ast.URLAsset, false)
+
+		// Skip over the descriptor if the URL wasn't terminated by a comma
+		if urlEnd == i {
+			for i < end && text[i] != ',' {
+				i++
+			}
+		}
+	}
+}
+
+func (p *parser) addURLForRange(tagLoc logger.Loc, r logger.Range, quote byte, kind html_ast.URLKind, isModule bool) {
+	path := decodeCharacterReferences(p.text[r.Loc.Start:r.End()])
+	if !isBundlablePath(path) {
+		return
+	}
+	p.importRecords = append(p.importRecords, ast.ImportRecord{
+		Kind:  ast.ImportHTMLAttribute,
+		Path:  logger.Path{Text: path},
+		Range: r,
+	})
+	p.urls = append(p.urls, html_ast.URL{
+		Range:    r,
+		TagLoc:   tagLoc,
+		Kind:     kind,
+		Quote:    quote,
+		IsModule: isModule,
+	})
+}
+
+// Only relative paths are bundled. Absolute paths are relative to the root of
+// the web server instead of to the file system, and URLs with a scheme (e.g.
+// "https:" or "data:") don't refer to files at all.
+func isBundlablePath(path string) bool {
+	if path == "" || path[0] == '/' || path[0] == '#' || path[0] == '?' {
+		return false
+	}
+
+	// Check for a scheme, which is an ASCII letter followed by letters, digits,
+	// "+", "-", or "." and then a ":"
+	if isASCIILetter(path[0]) {
+		for i := 1; i < len(path); i++ {
+			c := path[i]
+			if c == ':' {
+				return false
+			}
+			if !isASCIILetter(c) && (c < '0' || c > '9') && c != '+' && c != '-' && c != '.' {
+				break
+			}
+		}
+	}
+	return true
+}
+
+func findAttribute(attributes []attribute, name string) (attribute, bool) {
+	// Duplicate attributes are ignored, so the first one wins
+	for _, attr := range attributes {
+		if attr.name == name {
+			return attr, true
+		}
+	}
+	return attribute{}, false
+}
+
+var namedCharacterReferences = map[string]string{
+	"amp":  "&",
+	"apos": "'",
+	"gt":   ">",
+	"lt":   "<",
+	"nbsp": "\u00A0",
+	"quot": "\"",
+}
+
+// Only numeric character references and the most common named character
+// references are decoded. Other named character references are very unlikely
+// to appear in file paths and are left alone.
+func decodeCharacterReferences(text string) string {
+	amp := strings.IndexByte(text, '&')
+	if amp == -1 {
+		return text
+	}
+
+	sb := strings.Builder{}
+	for amp != -1 {
+		sb.WriteString(text[:amp])
+		text = text[amp:]
+		semicolon := strings.IndexByte(text, ';')
+		decoded := false
+
+		if semicolon > 1 {
+			name := text[1:semicolon]
+			if name[0] == '#' {
+				var value uint64
+				var err error
+				if len(name) > 1 && (name[1] == 'x' || name[1] == 'X') {
+					value, err = strconv.ParseUint(name[2:], 16, 32)
+				} else {
+					value, err = strconv.ParseUint(name[1:], 10, 32)
+				}
+				if err == nil && value > 0 && value <= utf8.MaxRune {
+					sb.WriteRune(rune(value))
+					decoded = true
+				}
+			} else if value, ok := namedCharacterReferences[name]; ok {
+				sb.WriteString(value)
+				decoded = true
+			}
+		}
+
+		if decoded {
+			text = text[semicolon+1:]
+		} else {
+			sb.WriteByte('&')
+			text = text[1:]
+		}
+		amp = strings.IndexByte(text, '&')
+	}
+	sb.WriteString(text)
+	return sb.String()
+}
+
+const whitespace = " \t\n\f\r"
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
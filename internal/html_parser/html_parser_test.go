@@ -0,0 +1,108 @@
+package html_parser
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/internal/html_ast"
+	"github.com/evanw/esbuild/internal/html_printer"
+	"github.com/evanw/esbuild/internal/test"
+)
+
+// This replaces each URL with a description of what was parsed
+func expectPrinted(t *testing.T, contents string, expected string) {
+	t.Helper()
+	t.Run(contents, func(t *testing.T) {
+		t.Helper()
+		tree := Parse(test.SourceForTest(contents))
+		urls := make([]string, len(tree.URLs))
+		for i, url := range tree.URLs {
+			var kind string
+			switch url.Kind {
+			case html_ast.URLAsset:
+				kind = "asset"
+			case html_ast.URLScript:
+				kind = "script"
+				if url.IsModule {
+					kind = "module"
+				}
+			case html_ast.URLStylesheet:
+				kind = "stylesheet"
+			}
+			urls[i] = "[" + kind + ":" + tree.ImportRecords[i].Path.Text + "]"
+		}
+		result := html_printer.Print(tree, test.SourceForTest(contents), html_printer.Options{URLs: urls})
+		test.AssertEqualWithDiff(t, string(result), expected)
+	})
+}
+
+func TestTags(t *testing.T) {
+	expectPrinted(t, `<script src="a.js"></script>`, `<script src="[script:a.js]"></script>`)
+	expectPrinted(t, `<SCRIPT SRC="a.js"></SCRIPT>`, `<SCRIPT SRC="[script:a.js]"></SCRIPT>`)
+	expectPrinted(t, `<script type="module" src="a.js"></script>`, `<script type="module" src="[module:a.js]"></script>`)
+	expectPrinted(t, `<script type=MODULE src=a.js></script>`, `<script type=MODULE src=[module:a.js]></script>`)
+	expectPrinted(t, `<script type="text/javascript" src="a.js"></script>`, `<script type="text/javascript" src="[script:a.js]"></script>`)
+	expectPrinted(t, `<script type="text/template" src="a.js"></script>`, `<script type="text/template" src="a.js"></script>`)
+	expectPrinted(t, `<link rel="stylesheet" href="a.css">`, `<link rel="stylesheet" href="[stylesheet:a.css]">`)
+	expectPrinted(t, `<link href="a.css" rel="Stylesheet">`, `<link href="[stylesheet:a.css]" rel="Stylesheet">`)
+	expectPrinted(t, `<link rel="shortcut icon" href="a.ico">`, `<link rel="shortcut icon" href="[asset:a.ico]">`)
+	expectPrinted(t, `<link rel="manifest" href="a.json">`, `<link rel="manifest" href="[asset:a.json]">`)
+	expectPrinted(t, `<link rel="preconnect" href="a.com">`, `<link rel="preconnect" href="a.com">`)
+	expectPrinted(t, `<link href="a.css">`, `<link href="a.css">`)
+	expectPrinted(t, `<img src="a.png">`, `<img src="[asset:a.png]">`)
+	expectPrinted(t, `<img src="a.png"/>`, `<img src="[asset:a.png]"/>`)
+	expectPrinted(t, `<source src="a.webm">`, `<source src="[asset:a.webm]">`)
+	expectPrinted(t, `<video src="a.mp4" poster="a.png">`, `<video src="[asset:a.mp4]" poster="[asset:a.png]">`)
+	expectPrinted(t, `<audio src="a.mp3">`, `<audio src="[asset:a.mp3]">`)
+	expectPrinted(t, `<track src="a.vtt">`, `<track src="[asset:a.vtt]">`)
+	expectPrinted(t, `<a href="a.html">`, `<a href="a.html">`)
+	expectPrinted(t, `<img data-src="a.png">`, `<img data-src="a.png">`)
+}
+
+func TestAttributes(t *testing.T) {
+	expectPrinted(t, `<img src='a.png'>`, `<img src='[asset:a.png]'>`)
+	expectPrinted(t, `<img src=a.png>`, `<img src=[asset:a.png]>`)
+	expectPrinted(t, `<img src = "a.png" >`, `<img src = "[asset:a.png]" >`)
+	expectPrinted(t, `<img src="  a.png  ">`, `<img src="  [asset:a.png]  ">`)
+	expectPrinted(t, `<img alt="src=b.png" src="a.png">`, `<img alt="src=b.png" src="[asset:a.png]">`)
+	expectPrinted(t, `<img src="a.png" src="b.png">`, `<img src="[asset:a.png]" src="b.png">`)
+	expectPrinted(t, `<img src="a&amp;b.png">`, `<img src="[asset:a&amp;b.png]">`)
+	expectPrinted(t, `<img src="a&#38;&#x26;&unknown;b.png">`, `<img src="[asset:a&amp;&amp;&amp;unknown;b.png]">`)
+	expectPrinted(t, `<img src='a"b.png'>`, `<img src='[asset:a"b.png]'>`)
+	expectPrinted(t, `<img src="a'b.png">`, `<img src="[asset:a'b.png]">`)
+	expectPrinted(t, `<img src=a&quot;b.png>`, `<img src=[asset:a%22b.png]>`)
+	expectPrinted(t, `<img src="a.png`, `<img src="[asset:a.png]`)
+}
+
+func TestSrcSet(t *testing.T) {
+	expectPrinted(t, `<img srcset="a.png">`, `<img srcset="[asset:a.png]">`)
+	expectPrinted(t, `<img srcset="a.png 1x, b.png 2x">`, `<img srcset="[asset:a.png] 1x, [asset:b.png] 2x">`)
+	expectPrinted(t, `<img srcset="a.png,b.png 2x">`, `<img srcset="[asset:a.png,b.png] 2x">`)
+	expectPrinted(t, `<img srcset="a.png,, b.png 2x">`, `<img srcset="[asset:a.png],, [asset:b.png] 2x">`)
+	expectPrinted(t, `<img srcset=" a.png 100w , b.png 200w ">`, `<img srcset=" [asset:a.png] 100w , [asset:b.png] 200w ">`)
+	expectPrinted(t, `<source srcset="a.webp" type="image/webp">`, `<source srcset="[asset:a.webp]" type="image/webp">`)
+}
+
+func TestIgnoredURLs(t *testing.T) {
+	expectPrinted(t, `<img src="">`, `<img src="">`)
+	expectPrinted(t, `<img src="/a.png">`, `<img src="/a.png">`)
+	expectPrinted(t, `<img src="//example.com/a.png">`, `<img src="//example.com/a.png">`)
+	expectPrinted(t, `<img src="https://example.com/a.png">`, `<img src="https://example.com/a.png">`)
+	expectPrinted(t, `<img src="data:image/png;base64,">`, `<img src="data:image/png;base64,">`)
+	expectPrinted(t, `<img src="#a">`, `<img src="#a">`)
+	expectPrinted(t, `<img src="?a">`, `<img src="?a">`)
+	expectPrinted(t, `<img src="a-b.c:d.png">`, `<img src="a-b.c:d.png">`)
+	expectPrinted(t, `<img src="a/b:c.png">`, `<img src="[asset:a/b:c.png]">`)
+}
+
+func TestRawText(t *testing.T) {
+	expectPrinted(t, `<!-- <img src="a.png"> --><img src="b.png">`, `<!-- <img src="a.png"> --><img src="[asset:b.png]">`)
+	expectPrinted(t, `<!-- <img src="a.png">`, `<!-- <img src="a.png">`)
+	expectPrinted(t, `<!DOCTYPE html><img src="a.png">`, `<!DOCTYPE html><img src="[asset:a.png]">`)
+	expectPrinted(t, `<script>"<img src='a.png'>"</script><img src="b.png">`, `<script>"<img src='a.png'>"</script><img src="[asset:b.png]">`)
+	expectPrinted(t, `<script>"</scripts><img src='a.png'>"</SCRIPT ><img src="b.png">`, `<script>"</scripts><img src='a.png'>"</SCRIPT ><img src="[asset:b.png]">`)
+	expectPrinted(t, `<style>a { content: "<img src='a.png'>" }</style>`, `<style>a { content: "<img src='a.png'>" }</style>`)
+	expectPrinted(t, `<textarea><img src="a.png"></textarea>`, `<textarea><img src="a.png"></textarea>`)
+	expectPrinted(t, `<title><img src="a.png"></title>`, `<title><img src="a.png"></title>`)
+	expectPrinted(t, `a < b <img src="a.png">`, `a < b <img src="[asset:a.png]">`)
+	expectPrinted(t, `</img src="a.png">`, `</img src="a.png">`)
+}
@@ -0,0 +1,89 @@
+package html_printer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/html_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+type Options struct {
+	// This is the new URL for each import record. An empty string means the
+	// original text for that URL is kept as-is.
+	URLs []string
+
+	// This is the URL of an additional stylesheet for each import record. If
+	// present, a "<link>" tag for it is inserted right before the tag containing
+	// that import record's URL. This is used for the CSS that is generated when
+	// the JavaScript referenced by a "<script>" tag imports CSS. It may be nil.
+	StylesheetsBefore []string
+}
+
+type edit struct {
+	text  string
+	start int32
+	end   int32
+}
+
+// The printer copies the original file through verbatim and only substitutes
+// the URLs that were changed. This avoids reformatting the user's HTML.
+func Print(tree html_ast.AST, source logger.Source, options Options) []byte {
+	var edits []edit
+
+	for i, url := range tree.URLs {
+		if options.StylesheetsBefore != nil && options.StylesheetsBefore[i] != "" {
+			tag := "<link rel=\"stylesheet\" href=\"" + escapeAttributeValue(options.StylesheetsBefore[i], '"') + "\">"
+			edits = append(edits, edit{text: tag, start: url.TagLoc.Start, end: url.TagLoc.Start})
+		}
+		if newURL := options.URLs[i]; newURL != "" {
+			edits = append(edits, edit{text: escapeAttributeValue(newURL, url.Quote), start: url.Range.Loc.Start, end: url.Range.End()})
+		}
+	}
+
+	// Insertions before a tag come before any URLs inside that tag
+	sort.SliceStable(edits, func(i int, j int) bool {
+		return edits[i].start < edits[j].start
+	})
+
+	text := source.Contents
+	sb := strings.Builder{}
+	sb.Grow(len(text))
+	offset := int32(0)
+	for _, e := range edits {
+		sb.WriteString(text[offset:e.start])
+		sb.WriteString(e.text)
+		offset = e.end
+	}
+	sb.WriteString(text[offset:])
+	return []byte(sb.String())
+}
+
+func escapeAttributeValue(value string, quote byte) string {
+	sb := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '&':
+			sb.WriteString("&amp;")
+		case c == quote:
+			if c == '"' {
+				sb.WriteString("&quot;")
+			} else {
+				sb.WriteString("&#39;")
+			}
+
+		// Unquoted attribute values can't contain these characters. They are all
+		// valid in URLs when percent-encoded.
+		case quote == 0 && (c == ' ' || c == '\t' || c == '\n' || c == '\f' || c == '\r' ||
+			c == '"' || c == '\'' || c == '<' || c == '>' || c == '`' || c == '='):
+			sb.WriteByte('%')
+			sb.WriteByte("0123456789ABCDEF"[c>>4])
+			sb.WriteByte("0123456789ABCDEF"[c&15])
+
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
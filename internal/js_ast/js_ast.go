@@ -247,6 +247,26 @@ type Decorator struct {
 	OmitNewlineAfter bool
 }
 
+// This is the type information that TypeScript's "emitDecoratorMetadata"
+// setting makes available at run-time. It's derived from type annotations
+// using syntax alone, so type references are kept as unresolved names.
+type TSDecoratorMetadata struct {
+	ParamTypes []TSMetadataType
+
+	// This is the type annotation for fields and the return type annotation
+	// for methods and getters
+	Type TSMetadataType
+}
+
+type TSMetadataType struct {
+	// This is set for type references such as "Foo" or "Foo.Bar"
+	EntityOrNil Expr
+
+	// Otherwise this is the name of a global constructor such as "String", or
+	// empty if the type should be represented as "void 0"
+	Name string
+}
+
 type PropertyKind uint8
 
 const (
@@ -299,6 +319,10 @@ type Property struct {
 
 	Decorators []Decorator
 
+	// This is only present for TypeScript class elements when the
+	// "emitDecoratorMetadata" setting is enabled
+	TSDecoratorMetadata *TSDecoratorMetadata
+
 	Loc             logger.Loc
 	CloseBracketLoc logger.Loc
 	Kind            PropertyKind
@@ -466,6 +490,7 @@ func (*EIf) isExpr()                   {}
 func (*ERequireString) isExpr()        {}
 func (*ERequireResolveString) isExpr() {}
 func (*EImportString) isExpr()         {}
+func (*EImportPathString) isExpr()     {}
 func (*EImportCall) isExpr()           {}
 
 type EArray struct {
@@ -891,6 +916,12 @@ type EImportString struct {
 	CloseParenLoc     logger.Loc
 }
 
+// This is a string containing the path of an import record. It's used for the
+// "new URL('path', import.meta.url)" inside "new Worker(...)" expressions.
+type EImportPathString struct {
+	ImportRecordIndex uint32
+}
+
 type EImportCall struct {
 	Expr          Expr
 	OptionsOrNil  Expr
@@ -1521,6 +1552,11 @@ type AST struct {
 	Directives []string
 	URLForCSS  string
 
+	// This is the contents of the ".d.ts" file for this TypeScript file when
+	// declaration files are enabled. It's generated in the parser because it
+	// needs the type annotations that the parser otherwise throws away.
+	TSDeclaration string
+
 	// Note: If you're in the linker, do not use this map directly. This map is
 	// filled in by the parser and is considered immutable. For performance reasons,
 	// the linker doesn't mutate this map (cloning a map is slow in Go). Instead the
@@ -1536,6 +1572,10 @@ type AST struct {
 	// to enable cross-module inlining of these constants.
 	ConstValues map[ast.Ref]ConstValue
 
+	// This contains the values of top-level constants that conditions in other
+	// files can be folded with. Unlike "ConstValues", these are never inlined.
+	FoldableConsts map[ast.Ref]FoldableConst
+
 	// Properties in here are represented as symbols instead of strings, which
 	// allows them to be renamed to smaller names.
 	MangledProps map[string]ast.Ref
@@ -1578,6 +1618,12 @@ type AST struct {
 	UsesExportsRef bool
 	UsesModuleRef  bool
 	ExportsKind    ExportsKind
+
+	// If true, this file was written using CommonJS but its exports were
+	// statically analyzable, so they were converted to ESM exports. Importing
+	// a missing export from this file is not an error since it wouldn't be an
+	// error if the file was still CommonJS.
+	ConvertedFromCommonJS bool
 }
 
 type TSEnumValue struct {
@@ -1594,13 +1640,26 @@ const (
 	ConstValueTrue
 	ConstValueFalse
 	ConstValueNumber
+	ConstValueString // Only used by "FoldableConsts"
 )
 
 type ConstValue struct {
-	Number float64 // Use this for "ConstValueNumber"
+	String []uint16 // Use this for "ConstValueString"
+	Number float64  // Use this for "ConstValueNumber"
 	Kind   ConstValueKind
 }
 
+// This is the value of a top-level "const" variable that is either a
+// primitive or an "Object.freeze()" call on an object literal containing
+// only primitives. The linker uses these to remove branches that test
+// constants imported from other files before tree shaking.
+type FoldableConst struct {
+	// This is nil if the constant isn't a frozen object literal
+	Properties map[string]ConstValue
+
+	Value ConstValue
+}
+
 func ExprToConstValue(expr Expr) ConstValue {
 	switch v := expr.Data.(type) {
 	case *ENull:
@@ -1652,6 +1711,9 @@ func ConstValueToExpr(loc logger.Loc, value ConstValue) Expr {
 
 	case ConstValueNumber:
 		return Expr{Loc: loc, Data: &ENumber{Value: value.Number}}
+
+	case ConstValueString:
+		return Expr{Loc: loc, Data: &EString{Value: value.String}}
 	}
 
 	panic("Internal error: invalid constant value")
@@ -263,6 +263,7 @@ type Lexer struct {
 	tracker     logger.LineColumnTracker
 
 	encodedStringLiteralStart int
+	prevTokenEnd              int
 
 	Number                          float64
 	current                         int
@@ -371,6 +372,12 @@ func (lexer *Lexer) Range() logger.Range {
 	return logger.Range{Loc: logger.Loc{Start: int32(lexer.start)}, Len: int32(lexer.end - lexer.start)}
 }
 
+// This is the end of the token before the current token. It excludes any
+// whitespace and comments between the two tokens.
+func (lexer *Lexer) PrevTokenEnd() int32 {
+	return int32(lexer.prevTokenEnd)
+}
+
 func (lexer *Lexer) Raw() string {
 	return lexer.source.Contents[lexer.start:lexer.end]
 }
@@ -1003,6 +1010,7 @@ func (lexer *Lexer) NextInsideJSXElement() {
 }
 
 func (lexer *Lexer) Next() {
+	lexer.prevTokenEnd = lexer.end
 	lexer.HasNewlineBefore = lexer.end == 0
 	lexer.HasCommentBefore = 0
 	lexer.PrevTokenWasAwaitKeyword = false
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
 	"unicode/utf8"
 
 	"github.com/evanw/esbuild/internal/ast"
@@ -57,6 +58,7 @@ type parser struct {
 	symbolCallUses             map[ast.Ref]js_ast.SymbolCallUse
 	declaredSymbols            []js_ast.DeclaredSymbol
 	globPatternImports         []globPatternImport
+	tsDeclaration              *tsDeclarationData
 	runtimeImports             map[string]ast.LocRef
 	duplicateCaseChecker       duplicateCaseChecker
 	unrepresentableIdentifiers map[string]bool
@@ -67,6 +69,10 @@ type parser struct {
 	// For strict mode handling
 	hoistedRefForSloppyModeBlockFn map[ast.Ref]ast.Ref
 
+	// When "let" and "const" are lowered, this tracks the block-scoped symbols
+	// that are referenced from inside a nested function (see "lowerLetLoop")
+	letsCapturedByClosures map[ast.Ref]bool
+
 	// For lowering private methods
 	privateGetters map[ast.Ref]ast.Ref
 	privateSetters map[ast.Ref]ast.Ref
@@ -94,10 +100,17 @@ type parser struct {
 	localTypeNames             map[string]bool
 	tsEnums                    map[ast.Ref]map[string]js_ast.TSEnumValue
 	constValues                map[ast.Ref]js_ast.ConstValue
+	foldableConsts             map[ast.Ref]js_ast.FoldableConst
 	propMethodValue            js_ast.E
 	propDerivedCtorValue       js_ast.E
 	propMethodDecoratorScope   *js_ast.Scope
 
+	// TypeScript's "emitDecoratorMetadata" setting serializes references to
+	// interfaces, type aliases, and type-only imports as "Object" and references
+	// to enums as the type of their values. These remember enough to do the same.
+	tsMetadataObjectNames map[string]bool
+	tsEnumMetadataTypes   map[ast.Ref]string
+
 	// This is the reference to the generated function argument for the namespace,
 	// which is different than the reference to the namespace itself:
 	//
@@ -230,6 +243,15 @@ type parser struct {
 	regExpRef     ast.Ref
 	superCtorRef  ast.Ref
 
+	// This is the replacement for "this" inside the constructor of a derived
+	// class that is being lowered to an ES5 constructor function
+	derivedClassThisRef ast.Ref
+
+	// Lowered default and rest arguments become statements at the start of the
+	// function body. This is the number of these statements for each function
+	// body so that class constructors can insert field initializers after them.
+	loweredArgsPrologueLen map[*js_ast.SBlock]int
+
 	// Imports from "react/jsx-runtime" and "react", respectively.
 	// (Or whatever was specified in the "importSource" option)
 	jsxRuntimeImports map[string]ast.LocRef
@@ -389,6 +411,10 @@ type parser struct {
 
 	// If this is true, then all top-level statements are wrapped in a try/catch
 	willWrapModuleInTryCatchForUsing bool
+
+	// These are for the "treeShakeCommonJS" option
+	commonJSObjectExports             []commonJSObjectExport
+	commonJSExportsWereConvertedToESM bool
 }
 
 type globPatternImport struct {
@@ -477,6 +503,7 @@ type optionsThatSupportStructuralEquality struct {
 	outputFormat           config.Format
 	asciiOnly              bool
 	keepNames              bool
+	looseForOf             bool
 	minifySyntax           bool
 	minifyIdentifiers      bool
 	minifyWhitespace       bool
@@ -484,8 +511,11 @@ type optionsThatSupportStructuralEquality struct {
 	omitJSXRuntimeForTests bool
 	ignoreDCEAnnotations   bool
 	treeShaking            bool
+	treeShakeCommonJS      bool
 	dropDebugger           bool
 	mangleQuoted           bool
+	hotModuleReplacement   bool
+	tsDeclaration          bool
 
 	// This is an internal-only option used for the implementation of Yarn PnP
 	decodeHydrateRuntimeStateYarnPnP bool
@@ -521,6 +551,7 @@ func OptionsFromConfig(options *config.Options) Options {
 			moduleTypeData:                    options.ModuleTypeData,
 			asciiOnly:                         options.ASCIIOnly,
 			keepNames:                         options.KeepNames,
+			looseForOf:                        options.LooseForOf,
 			minifySyntax:                      options.MinifySyntax,
 			minifyIdentifiers:                 options.MinifyIdentifiers,
 			minifyWhitespace:                  options.MinifyWhitespace,
@@ -528,8 +559,11 @@ func OptionsFromConfig(options *config.Options) Options {
 			omitJSXRuntimeForTests:            options.OmitJSXRuntimeForTests,
 			ignoreDCEAnnotations:              options.IgnoreDCEAnnotations,
 			treeShaking:                       options.TreeShaking,
+			treeShakeCommonJS:                 options.TreeShakeCommonJS,
 			dropDebugger:                      options.DropDebugger,
 			mangleQuoted:                      options.MangleQuoted,
+			hotModuleReplacement:              options.HotModuleReplacement,
+			tsDeclaration:                     options.TSDeclaration,
 		},
 	}
 }
@@ -611,6 +645,146 @@ func jsxExprsEqual(a config.DefineExpr, b config.DefineExpr) bool {
 	return true
 }
 
+// This is used as part of a persistent build cache key. Unlike "Equal", the
+// result must uniquely identify these options across different processes, so
+// it can't rely on pointer identity. The second return value is false if the
+// options contain something that can't be represented, in which case the
+// persistent cache must not be used.
+func (options *Options) PersistentCacheKey() (string, bool) {
+	sb := strings.Builder{}
+	o := &options.optionsThatSupportStructuralEquality
+	fmt.Fprintf(&sb, "target=%q unsupported=%d,%d,%d ts=%#v mode=%d platform=%d format=%d",
+		o.originalTargetEnv, o.unsupportedJSFeatures, o.unsupportedJSFeatureOverrides, o.unsupportedJSFeatureOverridesMask,
+		o.ts, o.mode, o.platform, o.outputFormat)
+	fmt.Fprintf(&sb, " flags=%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t,%t",
+		o.asciiOnly, o.keepNames, o.looseForOf, o.minifySyntax, o.minifyIdentifiers, o.minifyWhitespace, o.omitRuntimeForTests,
+		o.omitJSXRuntimeForTests, o.ignoreDCEAnnotations, o.treeShaking, o.treeShakeCommonJS, o.dropDebugger, o.mangleQuoted,
+		o.hotModuleReplacement, o.tsDeclaration, o.decodeHydrateRuntimeStateYarnPnP)
+
+	// The module type can come from a "package.json" file, which is referenced
+	// by log messages
+	fmt.Fprintf(&sb, " moduleType=%d,%#v", o.moduleTypeData.Type, o.moduleTypeData.Range)
+	if o.moduleTypeData.Source != nil {
+		writeSourceForPersistentCacheKey(&sb, o.moduleTypeData.Source)
+	}
+
+	if options.tsAlwaysStrict != nil {
+		fmt.Fprintf(&sb, " alwaysStrict=%t,%q,%#v", options.tsAlwaysStrict.Value, options.tsAlwaysStrict.Name, options.tsAlwaysStrict.Range)
+		writeSourceForPersistentCacheKey(&sb, &options.tsAlwaysStrict.Source)
+	}
+	if options.mangleProps != nil {
+		fmt.Fprintf(&sb, " mangleProps=%q", options.mangleProps.String())
+	}
+	if options.reserveProps != nil {
+		fmt.Fprintf(&sb, " reserveProps=%q", options.reserveProps.String())
+	}
+	fmt.Fprintf(&sb, " dropLabels=%q", options.dropLabels)
+
+	for _, file := range options.injectedFiles {
+		fmt.Fprintf(&sb, " inject=%q,%d,%t,%#v", file.DefineName, file.Source.Index, file.IsCopyLoader, file.Exports)
+		writeSourceForPersistentCacheKey(&sb, &file.Source)
+	}
+
+	jsx := &options.jsx
+	fmt.Fprintf(&sb, " jsx=%t,%t,%t,%q,%t,%t", jsx.Parse, jsx.Preserve, jsx.AutomaticRuntime, jsx.ImportSource, jsx.Development, jsx.SideEffects)
+	if !writeDefineExprForPersistentCacheKey(&sb, &jsx.Factory) || !writeDefineExprForPersistentCacheKey(&sb, &jsx.Fragment) {
+		return "", false
+	}
+
+	if options.defines != nil {
+		sb.WriteString(" defines=")
+		key, ok := persistentCacheKeyForDefines(options.defines)
+		if !ok {
+			return "", false
+		}
+		sb.WriteString(key)
+	}
+
+	return sb.String(), true
+}
+
+func writeSourceForPersistentCacheKey(sb *strings.Builder, source *logger.Source) {
+	fmt.Fprintf(sb, " source=%#v,%q,%q,%q", source.KeyPath, source.PrettyPath, source.IdentifierName, source.Contents)
+}
+
+func writeDefineExprForPersistentCacheKey(sb *strings.Builder, define *config.DefineExpr) bool {
+	fmt.Fprintf(sb, " define=%q", define.Parts)
+	if define.InjectedDefineIndex.IsValid() {
+		fmt.Fprintf(sb, ",%d", define.InjectedDefineIndex.GetIndex())
+	}
+	switch e := define.Constant.(type) {
+	case nil:
+	case *js_ast.ENull:
+		sb.WriteString(",null")
+	case *js_ast.EUndefined:
+		sb.WriteString(",undefined")
+	case *js_ast.EBoolean:
+		fmt.Fprintf(sb, ",%t", e.Value)
+	case *js_ast.ENumber:
+		fmt.Fprintf(sb, ",%v", math.Float64bits(e.Value))
+	case *js_ast.EString:
+		fmt.Fprintf(sb, ",%q", helpers.UTF16ToString(e.Value))
+	case *js_ast.EBigInt:
+		fmt.Fprintf(sb, ",%sn", e.Value)
+	default:
+		return false
+	}
+	return true
+}
+
+// The defines are the same object for every file in a build, and turning them
+// into a string is expensive (there are a lot of known globals). So the most
+// recent result is remembered.
+var persistentCacheKeyForDefinesMutex sync.Mutex
+var persistentCacheKeyForDefinesLastInput *config.ProcessedDefines
+var persistentCacheKeyForDefinesLastOutput string
+var persistentCacheKeyForDefinesLastOK bool
+
+func persistentCacheKeyForDefines(defines *config.ProcessedDefines) (string, bool) {
+	persistentCacheKeyForDefinesMutex.Lock()
+	defer persistentCacheKeyForDefinesMutex.Unlock()
+	if persistentCacheKeyForDefinesLastInput == defines {
+		return persistentCacheKeyForDefinesLastOutput, persistentCacheKeyForDefinesLastOK
+	}
+
+	sb := strings.Builder{}
+	ok := true
+	writeDefine := func(data config.DefineData) {
+		fmt.Fprintf(&sb, ",%d", data.Flags)
+		if data.DefineExpr != nil && !writeDefineExprForPersistentCacheKey(&sb, data.DefineExpr) {
+			ok = false
+		}
+	}
+
+	keys := make([]string, 0, len(defines.IdentifierDefines))
+	for key := range defines.IdentifierDefines {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&sb, " id=%q", key)
+		writeDefine(defines.IdentifierDefines[key])
+	}
+
+	keys = keys[:0]
+	for key := range defines.DotDefines {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&sb, " dotKey=%q", key)
+		for _, define := range defines.DotDefines[key] {
+			fmt.Fprintf(&sb, " dot=%q", define.Parts)
+			writeDefine(define.Data)
+		}
+	}
+
+	persistentCacheKeyForDefinesLastInput = defines
+	persistentCacheKeyForDefinesLastOutput = sb.String()
+	persistentCacheKeyForDefinesLastOK = ok
+	return persistentCacheKeyForDefinesLastOutput, ok
+}
+
 type tempRef struct {
 	valueOrNil js_ast.Expr
 	ref        ast.Ref
@@ -644,6 +818,7 @@ const (
 type fnOrArrowDataParse struct {
 	arrowArgErrors      *deferredArrowArgErrors
 	decoratorScope      *js_ast.Scope
+	decoratorMetadata   *js_ast.TSDecoratorMetadata
 	asyncRange          logger.Range
 	needsAsyncLoc       logger.Loc
 	await               awaitOrYield
@@ -678,6 +853,11 @@ type fnOrArrowDataVisit struct {
 	isDerivedClassCtor             bool
 	isOutsideFnOrArrow             bool
 	shouldLowerSuperPropertyAccess bool
+
+	// These are used to detect "await" and "yield" expressions inside loop
+	// bodies that may need to be moved into a function (see "lowerLetLoop")
+	awaitOrYieldCount uint32
+	awaitOrYieldRange logger.Range
 }
 
 // This is function-specific information used during visiting. It is saved and
@@ -716,6 +896,25 @@ type fnOnlyDataVisit struct {
 	// will have to reference a captured variable instead of the real variable.
 	isInsideAsyncArrowFn bool
 
+	// If we're inside a generator function and generator functions are not
+	// supported, then the body will be moved into a nested function for the
+	// state machine. That means references to "arguments" will also have to
+	// reference a captured variable instead of the real variable.
+	isInsideLoweredGeneratorFn bool
+
+	// If classes are not supported, the constructor of a derived class becomes
+	// a function that calls the base class constructor manually. The object
+	// returned from that call may not be the same as "this", so references to
+	// "this" in the constructor and in instance field initializers (which are
+	// moved into the constructor) are replaced with this symbol instead.
+	derivedClassThisRef *ast.Ref
+
+	// These are used to detect uses of "this" and "arguments" inside loop
+	// bodies that may need to be moved into a function (see "lowerLetLoop")
+	thisUseCount      uint32
+	argumentsUseCount uint32
+	argumentsUseLoc   logger.Loc
+
 	// If false, disallow "new.target" expressions. We disallow all "new.target"
 	// expressions at the top-level of the file (i.e. not inside a function or
 	// a class field). Technically since CommonJS files are wrapped in a function
@@ -1042,6 +1241,11 @@ func (p *parser) selectLocalKind(kind js_ast.LocalKind) js_ast.LocalKind {
 		return js_ast.LocalVar
 	}
 
+	// Generated code must not use "let" and "const" if they aren't supported
+	if (kind == js_ast.LocalLet || kind == js_ast.LocalConst) && p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) {
+		return js_ast.LocalVar
+	}
+
 	// Optimization: use "let" instead of "const" because it's shorter. This is
 	// only done when bundling because assigning to "const" is only an error when
 	// bundling.
@@ -1817,7 +2021,6 @@ type deferredErrors struct {
 	// These are errors for expressions
 	invalidExprDefaultValue  logger.Range
 	invalidExprAfterQuestion logger.Range
-	arraySpreadFeature       logger.Range
 
 	// These errors are for arrow functions
 	invalidParens []logger.Range
@@ -1830,9 +2033,6 @@ func (from *deferredErrors) mergeInto(to *deferredErrors) {
 	if from.invalidExprAfterQuestion.Len > 0 {
 		to.invalidExprAfterQuestion = from.invalidExprAfterQuestion
 	}
-	if from.arraySpreadFeature.Len > 0 {
-		to.arraySpreadFeature = from.arraySpreadFeature
-	}
 	if len(from.invalidParens) > 0 {
 		if len(to.invalidParens) > 0 {
 			to.invalidParens = append(to.invalidParens, from.invalidParens...)
@@ -1851,10 +2051,6 @@ func (p *parser) logExprErrors(errors *deferredErrors) {
 		r := errors.invalidExprAfterQuestion
 		p.log.AddError(&p.tracker, r, fmt.Sprintf("Unexpected %q", p.source.Contents[r.Loc.Start:r.Loc.Start+r.Len]))
 	}
-
-	if errors.arraySpreadFeature.Len > 0 {
-		p.markSyntaxFeature(compat.ArraySpread, errors.arraySpreadFeature)
-	}
 }
 
 func (p *parser) logDeferredArrowArgErrors(errors *deferredErrors) {
@@ -2033,7 +2229,6 @@ type propertyOpts struct {
 	decoratorContext decoratorContextFlags
 
 	asyncRange     logger.Range
-	generatorRange logger.Range
 	tsDeclareRange logger.Range
 	classKeyword   logger.Range
 	isAsync        bool
@@ -2042,6 +2237,9 @@ type propertyOpts struct {
 	// Class-related options
 	isStatic        bool
 	isTSAbstract    bool
+	isTSPrivate     bool
+	isTSProtected   bool
+	isTSReadonly    bool
 	isClass         bool
 	classHasExtends bool
 }
@@ -2083,7 +2281,6 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 
 	case js_lexer.TOpenBracket:
 		flags |= js_ast.PropertyIsComputed
-		p.markSyntaxFeature(compat.ObjectExtensions, p.lexer.Range())
 		p.lexer.Next()
 		wasIdentifier := p.lexer.Token == js_lexer.TIdentifier
 		expr := p.parseExpr(js_ast.LComma)
@@ -2117,7 +2314,6 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 			p.lexer.Unexpected()
 		}
 		opts.isGenerator = true
-		opts.generatorRange = p.lexer.Range()
 		p.lexer.Next()
 		return p.parseProperty(startLoc, js_ast.PropertyNormal, opts, errors)
 
@@ -2250,6 +2446,14 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 				case "private", "protected", "public", "readonly", "override":
 					// Skip over TypeScript keywords
 					if opts.isClass && p.options.ts.Parse && raw == name.String {
+						switch name.String {
+						case "private":
+							opts.isTSPrivate = true
+						case "protected":
+							opts.isTSProtected = true
+						case "readonly":
+							opts.isTSReadonly = true
+						}
 						return p.parseProperty(startLoc, kind, opts, nil)
 					}
 				}
@@ -2322,8 +2526,22 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 		}
 	}
 
+	// TypeScript declaration files need the source ranges of class members
+	var tsDecl *tsDeclProp
+	if p.tsDeclaration != nil && opts.isClass {
+		tsDecl = &tsDeclProp{
+			key:         logger.Range{Loc: keyRange.Loc, Len: p.lexer.PrevTokenEnd() - keyRange.Loc.Start},
+			isPrivate:   opts.isTSPrivate,
+			isProtected: opts.isTSProtected,
+			isReadonly:  opts.isTSReadonly,
+			isStatic:    opts.isStatic,
+		}
+	}
+
 	hasTypeParameters := false
 	hasDefiniteAssignmentAssertionOperator := false
+	emitDecoratorMetadata := opts.isClass && p.options.ts.Parse && p.options.ts.Config.ExperimentalDecorators == config.True &&
+		p.options.ts.Config.EmitDecoratorMetadata == config.True
 
 	if p.options.ts.Parse {
 		if opts.isClass {
@@ -2331,6 +2549,9 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 				// "class X { foo?: number }"
 				// "class X { foo?(): number }"
 				p.lexer.Next()
+				if tsDecl != nil {
+					tsDecl.isOptional = true
+				}
 			} else if p.lexer.Token == js_lexer.TExclamation && !p.lexer.HasNewlineBefore && !opts.isAsync &&
 				!opts.isGenerator && (kind == js_ast.PropertyNormal || kind == js_ast.PropertyAutoAccessor) {
 				// "class X { foo!: number }"
@@ -2350,6 +2571,7 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 	if kind == js_ast.PropertyAutoAccessor || (opts.isClass && kind == js_ast.PropertyNormal && !opts.isAsync && !opts.isGenerator &&
 		!hasTypeParameters && (p.lexer.Token != js_lexer.TOpenParen || hasDefiniteAssignmentAssertionOperator)) {
 		var initializerOrNil js_ast.Expr
+		var decoratorMetadata *js_ast.TSDecoratorMetadata
 
 		// Forbid the names "constructor" and "prototype" in some cases
 		if !flags.Has(js_ast.PropertyIsComputed) {
@@ -2360,10 +2582,22 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 		}
 
 		// Skip over types
-		if p.options.ts.Parse && p.lexer.Token == js_lexer.TColon {
+		typeLoc := p.lexer.Loc()
+		if emitDecoratorMetadata && len(opts.decorators) > 0 {
+			decoratorMetadata = &js_ast.TSDecoratorMetadata{Type: js_ast.TSMetadataType{Name: "Object"}}
+			if p.lexer.Token == js_lexer.TColon {
+				p.lexer.Next()
+				typeLoc = p.lexer.Loc()
+				decoratorMetadata.Type = p.skipTypeScriptTypeForDecoratorMetadata(0, false)
+			}
+		} else if p.options.ts.Parse && p.lexer.Token == js_lexer.TColon {
 			p.lexer.Next()
+			typeLoc = p.lexer.Loc()
 			p.skipTypeScriptType(js_ast.LLowest)
 		}
+		if tsDecl != nil && typeLoc != p.lexer.Loc() {
+			tsDecl.typeRange = p.rangeToPrevTokenEnd(typeLoc)
+		}
 
 		if p.lexer.Token == js_lexer.TEquals {
 			p.lexer.Next()
@@ -2378,6 +2612,10 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 
 			p.fnOrArrowDataParse.isThisDisallowed = oldIsThisDisallowed
 			p.fnOrArrowDataParse.allowSuperProperty = oldAllowSuperProperty
+
+			if tsDecl != nil {
+				tsDecl.initializerEnd = p.lexer.PrevTokenEnd()
+			}
 		}
 
 		// Special-case private identifiers
@@ -2410,14 +2648,18 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 		if opts.isStatic {
 			flags |= js_ast.PropertyIsStatic
 		}
+		if tsDecl != nil {
+			p.tsDeclaration.props[startLoc] = *tsDecl
+		}
 		return js_ast.Property{
-			Decorators:       opts.decorators,
-			Loc:              startLoc,
-			Kind:             kind,
-			Flags:            flags,
-			Key:              key,
-			InitializerOrNil: initializerOrNil,
-			CloseBracketLoc:  closeBracketLoc,
+			Decorators:          opts.decorators,
+			TSDecoratorMetadata: decoratorMetadata,
+			Loc:                 startLoc,
+			Kind:                kind,
+			Flags:               flags,
+			Key:                 key,
+			InitializerOrNil:    initializerOrNil,
+			CloseBracketLoc:     closeBracketLoc,
 		}, true
 	}
 
@@ -2437,18 +2679,6 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 			hasError = true
 		}
 
-		if opts.isAsync && p.markAsyncFn(opts.asyncRange, opts.isGenerator) {
-			hasError = true
-		}
-
-		if !hasError && opts.isGenerator && p.markSyntaxFeature(compat.Generator, opts.generatorRange) {
-			hasError = true
-		}
-
-		if !hasError && p.lexer.Token == js_lexer.TOpenParen && kind != js_ast.PropertyGet && kind != js_ast.PropertySet && p.markSyntaxFeature(compat.ObjectExtensions, p.lexer.Range()) {
-			hasError = true
-		}
-
 		loc := p.lexer.Loc()
 		scopeIndex := p.pushScopeForParsePass(js_ast.ScopeFunctionArgs, loc)
 		isConstructor := false
@@ -2484,6 +2714,17 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 			yield = allowExpr
 		}
 
+		// The type of a missing return type annotation depends on the method
+		var decoratorMetadata *js_ast.TSDecoratorMetadata
+		if emitDecoratorMetadata {
+			decoratorMetadata = &js_ast.TSDecoratorMetadata{}
+			if kind == js_ast.PropertyGet {
+				decoratorMetadata.Type.Name = "Object"
+			} else if opts.isAsync {
+				decoratorMetadata.Type.Name = "Promise"
+			}
+		}
+
 		fn, hadBody := p.parseFn(nil, opts.classKeyword, opts.decoratorContext, fnOrArrowDataParse{
 			needsAsyncLoc:      key.Loc,
 			asyncRange:         opts.asyncRange,
@@ -2492,6 +2733,7 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 			allowSuperCall:     opts.classHasExtends && isConstructor,
 			allowSuperProperty: true,
 			decoratorScope:     opts.decoratorScope,
+			decoratorMetadata:  decoratorMetadata,
 			isConstructor:      isConstructor,
 
 			// Only allow omitting the body if we're parsing TypeScript class
@@ -2499,6 +2741,10 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 		})
 
 		// "class Foo { foo(): void; foo(): void {} }"
+		if tsDecl != nil {
+			tsDecl.isOverload = !hadBody && !opts.isTSAbstract
+			p.tsDeclaration.props[startLoc] = *tsDecl
+		}
 		if !hadBody {
 			// Skip this property entirely
 			p.popAndDiscardScope(scopeIndex)
@@ -2509,6 +2755,25 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 		fn.IsUniqueFormalParameters = true
 		value := js_ast.Expr{Loc: loc, Data: &js_ast.EFunction{Fn: fn}}
 
+		// Only keep type information for decorated methods. The constructor's
+		// parameter types are used for the class decorators.
+		if decoratorMetadata != nil {
+			hasDecorators := len(opts.decorators) > 0 || isConstructor
+			for _, arg := range fn.Args {
+				if len(arg.Decorators) > 0 {
+					hasDecorators = true
+				}
+			}
+			if !hasDecorators {
+				decoratorMetadata = nil
+			} else if kind == js_ast.PropertySet {
+				decoratorMetadata.Type = js_ast.TSMetadataType{Name: "Object"}
+				if len(decoratorMetadata.ParamTypes) > 0 {
+					decoratorMetadata.Type = decoratorMetadata.ParamTypes[0]
+				}
+			}
+		}
+
 		// Enforce argument rules for accessors
 		switch kind {
 		case js_ast.PropertyGet:
@@ -2571,13 +2836,14 @@ func (p *parser) parseProperty(startLoc logger.Loc, kind js_ast.PropertyKind, op
 			flags |= js_ast.PropertyIsStatic
 		}
 		return js_ast.Property{
-			Decorators:      opts.decorators,
-			Loc:             startLoc,
-			Kind:            kind,
-			Flags:           flags | js_ast.PropertyIsMethod,
-			Key:             key,
-			ValueOrNil:      value,
-			CloseBracketLoc: closeBracketLoc,
+			Decorators:          opts.decorators,
+			TSDecoratorMetadata: decoratorMetadata,
+			Loc:                 startLoc,
+			Kind:                kind,
+			Flags:               flags | js_ast.PropertyIsMethod,
+			Key:                 key,
+			ValueOrNil:          value,
+			CloseBracketLoc:     closeBracketLoc,
 		}, true
 	}
 
@@ -2889,7 +3155,6 @@ func (p *parser) parseAsyncPrefixExpr(asyncRange logger.Range, level js_ast.L, f
 				}
 
 				if isArrowFn {
-					p.markAsyncFn(asyncRange, false)
 					ref := p.storeNameInRef(p.lexer.Identifier)
 					arg := js_ast.Arg{Binding: js_ast.Binding{Loc: p.lexer.Loc(), Data: &js_ast.BIdentifier{Ref: ref}}}
 					p.lexer.Next()
@@ -2936,14 +3201,7 @@ func (p *parser) parseAsyncPrefixExpr(asyncRange logger.Range, level js_ast.L, f
 func (p *parser) parseFnExpr(loc logger.Loc, isAsync bool, asyncRange logger.Range) js_ast.Expr {
 	p.lexer.Next()
 	isGenerator := p.lexer.Token == js_lexer.TAsterisk
-	hasError := false
-	if isAsync {
-		hasError = p.markAsyncFn(asyncRange, isGenerator)
-	}
 	if isGenerator {
-		if !hasError {
-			p.markSyntaxFeature(compat.Generator, p.lexer.Range())
-		}
 		p.lexer.Next()
 	}
 	var name *ast.LocRef
@@ -3019,6 +3277,17 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 	oldFnOrArrowData := p.fnOrArrowDataParse
 	p.fnOrArrowDataParse.arrowArgErrors = &arrowArgErrors
 
+	// TypeScript declaration files need the source ranges of the signature
+	var tsDecl *tsDeclFn
+	if p.tsDeclaration != nil {
+		tsDecl = &tsDeclFn{}
+		openParen := p.lexer.PrevTokenEnd() - 1
+		if r := p.tsDeclaration.lastTypeParameters; r.Len > 0 && r.End() <= openParen &&
+			strings.TrimSpace(p.source.Contents[r.End():openParen]) == "" {
+			tsDecl.typeParameters = r
+		}
+	}
+
 	// Scan over the comma-separated arguments or expressions
 	for p.lexer.Token != js_lexer.TCloseParen {
 		itemLoc := p.lexer.Loc()
@@ -3026,7 +3295,12 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 
 		if isSpread {
 			spreadRange = p.lexer.Range()
-			p.markSyntaxFeature(compat.RestArgument, spreadRange)
+
+			// Lowering rest arguments uses "arguments", which means arrow functions
+			// with rest arguments must be converted into normal functions
+			if !p.options.unsupportedJSFeatures.Has(compat.Arrow) {
+				p.markSyntaxFeature(compat.RestArgument, spreadRange)
+			}
 			p.lexer.Next()
 		}
 
@@ -3035,6 +3309,16 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 		// in one but not in the other are deferred.
 		p.latestArrowArgLoc = p.lexer.Loc()
 		item := p.parseExprOrBindings(js_ast.LComma, &errors)
+		declArg := tsDeclArg{start: itemLoc.Start, isRest: isSpread}
+		if tsDecl != nil {
+			declArg.binding = p.rangeToPrevTokenEnd(p.latestArrowArgLoc)
+
+			// "(a = 1) => {}"
+			if assign, ok := item.Data.(*js_ast.EBinary); ok && assign.Op == js_ast.BinOpAssign {
+				declArg.binding.Len = p.source.RangeOfOperatorBefore(assign.Right.Loc, "=").Loc.Start - declArg.binding.Loc.Start
+				declArg.defaultOrNil = assign.Right
+			}
+		}
 
 		if isSpread {
 			item = js_ast.Expr{Loc: itemLoc, Data: &js_ast.ESpread{Value: item}}
@@ -3044,13 +3328,23 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 		if p.options.ts.Parse && p.lexer.Token == js_lexer.TColon {
 			typeColonRange = p.lexer.Range()
 			p.lexer.Next()
+			typeLoc := p.lexer.Loc()
 			p.skipTypeScriptType(js_ast.LLowest)
+			if tsDecl != nil {
+				declArg.typeRange = p.rangeToPrevTokenEnd(typeLoc)
+			}
 		}
 
 		// There may be a "=" after the type (but not after an "as" cast)
 		if p.options.ts.Parse && p.lexer.Token == js_lexer.TEquals && p.lexer.Loc() != p.forbidSuffixAfterAsLoc {
 			p.lexer.Next()
-			item = js_ast.Assign(item, p.parseExpr(js_ast.LComma))
+			value := p.parseExpr(js_ast.LComma)
+			item = js_ast.Assign(item, value)
+			declArg.defaultOrNil = value
+		}
+
+		if tsDecl != nil {
+			tsDecl.args = append(tsDecl.args, declArg)
 		}
 
 		items = append(items, item)
@@ -3087,10 +3381,6 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 		var invalidLog invalidLog
 		args := []js_ast.Arg{}
 
-		if isAsync {
-			p.markAsyncFn(opts.asyncRange, false)
-		}
-
 		// First, try converting the expressions to bindings
 		for _, item := range items {
 			isSpread := false
@@ -3108,8 +3398,14 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 		// attempt to convert the expressions to bindings first before deciding
 		// whether this is an arrow function, and only pick an arrow function if
 		// there were no conversion errors.
+		returnTypeLoc := p.lexer.Range().End()
+		hasReturnType := p.lexer.Token == js_lexer.TColon
 		if p.lexer.Token == js_lexer.TEqualsGreaterThan || (len(invalidLog.invalidTokens) == 0 &&
 			p.trySkipTypeScriptArrowReturnTypeWithBacktracking()) || opts.forceArrowFn {
+			if tsDecl != nil && hasReturnType && p.lexer.Token == js_lexer.TEqualsGreaterThan {
+				tsDecl.returnType = p.rangeToPrevTokenEnd(logger.Loc{Start: returnTypeLoc})
+			}
+
 			if commaAfterSpread.Start != 0 {
 				p.log.AddError(&p.tracker, logger.Range{Loc: commaAfterSpread, Len: 1}, "Unexpected \",\" after rest pattern")
 			}
@@ -3125,11 +3421,6 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 				panic(js_lexer.LexerPanic{})
 			}
 
-			// Also report syntax features used in bindings
-			for _, entry := range invalidLog.syntaxFeatures {
-				p.markSyntaxFeature(entry.feature, entry.token)
-			}
-
 			await := allowIdent
 			if isAsync {
 				await = allowExpr
@@ -3142,6 +3433,9 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 			arrow.IsAsync = isAsync
 			arrow.HasRestArg = spreadRange.Len > 0
 			p.popScope()
+			if tsDecl != nil {
+				p.tsDeclaration.arrows[loc] = *tsDecl
+			}
 			return js_ast.Expr{Loc: loc, Data: arrow}
 		}
 	}
@@ -3186,13 +3480,7 @@ func (p *parser) parseParenExpr(loc logger.Loc, level js_ast.L, opts parenExprOp
 }
 
 type invalidLog struct {
-	invalidTokens  []logger.Range
-	syntaxFeatures []syntaxFeature
-}
-
-type syntaxFeature struct {
-	feature compat.JSFeature
-	token   logger.Range
+	invalidTokens []logger.Range
 }
 
 func (p *parser) convertExprToBindingAndInitializer(
@@ -3208,11 +3496,6 @@ func (p *parser) convertExprToBindingAndInitializer(
 		equalsRange := p.source.RangeOfOperatorBefore(initializerOrNil.Loc, "=")
 		if isSpread {
 			p.log.AddError(&p.tracker, equalsRange, "A rest argument cannot have a default initializer")
-		} else {
-			invalidLog.syntaxFeatures = append(invalidLog.syntaxFeatures, syntaxFeature{
-				feature: compat.DefaultArgument,
-				token:   equalsRange,
-			})
 		}
 	}
 	return binding, initializerOrNil, invalidLog
@@ -3234,15 +3517,13 @@ func (p *parser) convertExprToBinding(expr js_ast.Expr, invalidLog invalidLog) (
 		if e.CommaAfterSpread.Start != 0 {
 			invalidLog.invalidTokens = append(invalidLog.invalidTokens, logger.Range{Loc: e.CommaAfterSpread, Len: 1})
 		}
-		invalidLog.syntaxFeatures = append(invalidLog.syntaxFeatures,
-			syntaxFeature{feature: compat.Destructuring, token: p.source.RangeOfOperatorAfter(expr.Loc, "[")})
 		items := []js_ast.ArrayBinding{}
 		isSpread := false
 		for _, item := range e.Items {
 			if i, ok := item.Data.(*js_ast.ESpread); ok {
 				isSpread = true
 				item = i.Value
-				if _, ok := item.Data.(*js_ast.EIdentifier); !ok {
+				if _, ok := item.Data.(*js_ast.EIdentifier); !ok && !p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
 					p.markSyntaxFeature(compat.NestedRestBinding, p.source.RangeOfOperatorAfter(item.Loc, "["))
 				}
 			}
@@ -3265,8 +3546,6 @@ func (p *parser) convertExprToBinding(expr js_ast.Expr, invalidLog invalidLog) (
 		if e.CommaAfterSpread.Start != 0 {
 			invalidLog.invalidTokens = append(invalidLog.invalidTokens, logger.Range{Loc: e.CommaAfterSpread, Len: 1})
 		}
-		invalidLog.syntaxFeatures = append(invalidLog.syntaxFeatures,
-			syntaxFeature{feature: compat.Destructuring, token: p.source.RangeOfOperatorAfter(expr.Loc, "{")})
 		properties := []js_ast.PropertyBinding{}
 		for _, property := range e.Properties {
 			if property.Flags.Has(js_ast.PropertyIsMethod) || property.Kind == js_ast.PropertyGet || property.Kind == js_ast.PropertySet {
@@ -3676,11 +3955,6 @@ func (p *parser) parsePrefix(level js_ast.L, errors *deferredErrors, flags exprF
 				items = append(items, js_ast.Expr{Loc: p.lexer.Loc(), Data: js_ast.EMissingShared})
 
 			case js_lexer.TDotDotDot:
-				if errors != nil {
-					errors.arraySpreadFeature = p.lexer.Range()
-				} else {
-					p.markSyntaxFeature(compat.ArraySpread, p.lexer.Range())
-				}
 				dotsLoc := p.saveExprCommentsHere()
 				p.lexer.Next()
 				item := p.parseExprOrBindings(js_ast.LComma, &selfErrors)
@@ -4827,7 +5101,6 @@ func (p *parser) parseExprOrLetOrUsingStmt(opts parseStmtOpts) (js_ast.Expr, js_
 			if opts.lexicalDecl != lexicalDeclAllowAll {
 				p.forbidLexicalDecl(tokenRange.Loc)
 			}
-			p.markSyntaxFeature(compat.ConstAndLet, tokenRange)
 			decls := p.parseAndDeclareDecls(ast.SymbolOther, opts)
 			return js_ast.Expr{}, js_ast.Stmt{Loc: tokenRange.Loc, Data: &js_ast.SLocal{
 				Kind:     js_ast.LocalLet,
@@ -4907,7 +5180,6 @@ func (p *parser) parseCallArgs() (args []js_ast.Expr, closeParenLoc logger.Loc,
 		loc := p.lexer.Loc()
 		isSpread := p.lexer.Token == js_lexer.TDotDotDot
 		if isSpread {
-			p.markSyntaxFeature(compat.RestArgument, p.lexer.Range())
 			p.lexer.Next()
 		}
 		arg := p.parseExpr(js_ast.LComma)
@@ -5375,6 +5647,12 @@ func (p *parser) parseAndDeclareDecls(kind ast.SymbolKind, opts parseStmtOpts) [
 		local := p.parseBinding(parseBindingOpts{isUsingStmt: opts.isUsingStmt})
 		p.declareBinding(kind, local, opts)
 
+		// TypeScript declaration files need the source ranges of declarations
+		var tsDecl tsDeclDecl
+		if p.tsDeclaration != nil {
+			tsDecl.binding = p.rangeToPrevTokenEnd(local.Loc)
+		}
+
 		// Skip over types
 		if p.options.ts.Parse {
 			// "let foo!"
@@ -5386,13 +5664,20 @@ func (p *parser) parseAndDeclareDecls(kind ast.SymbolKind, opts parseStmtOpts) [
 			// "let foo: number"
 			if isDefiniteAssignmentAssertion || p.lexer.Token == js_lexer.TColon {
 				p.lexer.Expect(js_lexer.TColon)
+				typeLoc := p.lexer.Loc()
 				p.skipTypeScriptType(js_ast.LLowest)
+				if p.tsDeclaration != nil {
+					tsDecl.typeRange = p.rangeToPrevTokenEnd(typeLoc)
+				}
 			}
 		}
 
 		if p.lexer.Token == js_lexer.TEquals {
 			p.lexer.Next()
 			valueOrNil = p.parseExpr(js_ast.LComma)
+			if p.tsDeclaration != nil {
+				tsDecl.valueEnd = p.lexer.PrevTokenEnd()
+			}
 
 			// Rollup (the tool that invented the "@__NO_SIDE_EFFECTS__" comment) only
 			// applies this to the first declaration, and only when it's a "const".
@@ -5426,6 +5711,9 @@ func (p *parser) parseAndDeclareDecls(kind ast.SymbolKind, opts parseStmtOpts) [
 		}
 
 		decls = append(decls, js_ast.Decl{Binding: local, ValueOrNil: valueOrNil})
+		if p.tsDeclaration != nil {
+			p.tsDeclaration.decls[local.Loc] = tsDecl
+		}
 
 		if p.lexer.Token != js_lexer.TComma {
 			break
@@ -5564,16 +5852,18 @@ func (p *parser) parseImportClause() ([]js_ast.ClauseItem, bool) {
 				// "import { type xx as yy } from 'mod'"
 				// "import { type if as yy } from 'mod'"
 				// "import { type 'xx' as yy } from 'mod'"
-				p.parseClauseAlias("import")
+				localName := p.parseClauseAlias("import").String
 				p.lexer.Next()
 
 				if p.lexer.IsContextualKeyword("as") {
 					p.lexer.Next()
+					localName = p.lexer.Identifier.String
 					p.lexer.Expect(js_lexer.TIdentifier)
 				} else if !isIdentifier {
 					// An import where the name is a keyword must have an alias
 					p.lexer.ExpectedString("\"as\"")
 				}
+				p.tsMetadataObjectNames[localName] = true
 			}
 		} else {
 			if p.lexer.IsContextualKeyword("as") {
@@ -5783,7 +6073,6 @@ func (p *parser) parseBinding(opts parseBindingOpts) js_ast.Binding {
 		if opts.isUsingStmt {
 			break
 		}
-		p.markSyntaxFeature(compat.Destructuring, p.lexer.Range())
 		p.lexer.Next()
 		isSingleLine := !p.lexer.HasNewlineBefore
 		items := []js_ast.ArrayBinding{}
@@ -5807,8 +6096,9 @@ func (p *parser) parseBinding(opts parseBindingOpts) js_ast.Binding {
 					p.lexer.Next()
 					hasSpread = true
 
-					// This was a bug in the ES2015 spec that was fixed in ES2016
-					if p.lexer.Token != js_lexer.TIdentifier {
+					// This was a bug in the ES2015 spec that was fixed in ES2016. It's
+					// not a problem if all binding patterns are being lowered anyway.
+					if p.lexer.Token != js_lexer.TIdentifier && !p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
 						p.markSyntaxFeature(compat.NestedRestBinding, p.lexer.Range())
 					}
 				}
@@ -5865,7 +6155,6 @@ func (p *parser) parseBinding(opts parseBindingOpts) js_ast.Binding {
 		if opts.isUsingStmt {
 			break
 		}
-		p.markSyntaxFeature(compat.Destructuring, p.lexer.Range())
 		p.lexer.Next()
 		isSingleLine := !p.lexer.HasNewlineBefore
 		properties := []js_ast.PropertyBinding{}
@@ -5927,6 +6216,16 @@ func (p *parser) parseFn(
 	fn.IsGenerator = data.yield == allowExpr
 	fn.ArgumentsRef = ast.InvalidRef
 	fn.OpenParenLoc = p.lexer.Loc()
+
+	// TypeScript declaration files need the source ranges of the signature
+	var tsDecl *tsDeclFn
+	if p.tsDeclaration != nil {
+		tsDecl = &tsDeclFn{}
+		if r := p.tsDeclaration.lastTypeParameters; r.Len > 0 && r.End() == p.lexer.PrevTokenEnd() {
+			tsDecl.typeParameters = r
+		}
+	}
+
 	p.lexer.Expect(js_lexer.TOpenParen)
 
 	// Await and yield are not allowed in function arguments
@@ -5952,11 +6251,15 @@ func (p *parser) parseFn(
 	for p.lexer.Token != js_lexer.TCloseParen {
 		// Skip over "this" type annotations
 		if p.options.ts.Parse && p.lexer.Token == js_lexer.TThis {
+			thisLoc := p.lexer.Loc()
 			p.lexer.Next()
 			if p.lexer.Token == js_lexer.TColon {
 				p.lexer.Next()
 				p.skipTypeScriptType(js_ast.LLowest)
 			}
+			if tsDecl != nil {
+				tsDecl.thisArg = p.rangeToPrevTokenEnd(thisLoc)
+			}
 			if p.lexer.Token != js_lexer.TComma {
 				break
 			}
@@ -6022,8 +6325,8 @@ func (p *parser) parseFn(
 			p.fnOrArrowDataParse.needsAsyncLoc = oldNeedsAsyncLoc
 		}
 
+		argLoc := p.lexer.Loc()
 		if !fn.HasRestArg && p.lexer.Token == js_lexer.TDotDotDot {
-			p.markSyntaxFeature(compat.RestArgument, p.lexer.Range())
 			p.lexer.Next()
 			fn.HasRestArg = true
 		}
@@ -6032,6 +6335,7 @@ func (p *parser) parseFn(
 		isIdentifier := p.lexer.Token == js_lexer.TIdentifier
 		text := p.lexer.Identifier.String
 		arg := p.parseBinding(parseBindingOpts{})
+		var declArg tsDeclArg
 
 		if p.options.ts.Parse {
 			// Skip over TypeScript accessibility modifiers, which turn this argument
@@ -6054,16 +6358,28 @@ func (p *parser) parseFn(
 					arg = p.parseBinding(parseBindingOpts{})
 				}
 			}
+			declArg.binding = p.rangeToPrevTokenEnd(arg.Loc)
 
 			// "function foo(a?) {}"
 			if p.lexer.Token == js_lexer.TQuestion {
 				p.lexer.Next()
+				declArg.isOptional = true
 			}
 
 			// "function foo(a: any) {}"
+			argType := js_ast.TSMetadataType{Name: "Object"}
 			if p.lexer.Token == js_lexer.TColon {
 				p.lexer.Next()
-				p.skipTypeScriptType(js_ast.LLowest)
+				typeLoc := p.lexer.Loc()
+				if data.decoratorMetadata != nil {
+					argType = p.skipTypeScriptTypeForDecoratorMetadata(0, fn.HasRestArg)
+				} else {
+					p.skipTypeScriptType(js_ast.LLowest)
+				}
+				declArg.typeRange = p.rangeToPrevTokenEnd(typeLoc)
+			}
+			if data.decoratorMetadata != nil {
+				data.decoratorMetadata.ParamTypes = append(data.decoratorMetadata.ParamTypes, argType)
 			}
 		}
 
@@ -6071,7 +6387,6 @@ func (p *parser) parseFn(
 
 		var defaultValueOrNil js_ast.Expr
 		if !fn.HasRestArg && p.lexer.Token == js_lexer.TEquals {
-			p.markSyntaxFeature(compat.DefaultArgument, p.lexer.Range())
 			p.lexer.Next()
 			defaultValueOrNil = p.parseExpr(js_ast.LComma)
 		}
@@ -6085,6 +6400,13 @@ func (p *parser) parseFn(
 			IsTypeScriptCtorField: isTypeScriptCtorField,
 		})
 
+		if tsDecl != nil {
+			declArg.start = argLoc.Start
+			declArg.isRest = fn.HasRestArg
+			declArg.defaultOrNil = defaultValueOrNil
+			tsDecl.args = append(tsDecl.args, declArg)
+		}
+
 		if p.lexer.Token != js_lexer.TComma {
 			break
 		}
@@ -6116,7 +6438,18 @@ func (p *parser) parseFn(
 	// "function foo(): any {}"
 	if p.options.ts.Parse && p.lexer.Token == js_lexer.TColon {
 		p.lexer.Next()
-		p.skipTypeScriptReturnType()
+		typeLoc := p.lexer.Loc()
+		if data.decoratorMetadata != nil {
+			data.decoratorMetadata.Type = p.skipTypeScriptTypeForDecoratorMetadata(isReturnTypeFlag, false)
+		} else {
+			p.skipTypeScriptReturnType()
+		}
+		if tsDecl != nil {
+			tsDecl.returnType = p.rangeToPrevTokenEnd(typeLoc)
+		}
+	}
+	if tsDecl != nil {
+		p.tsDeclaration.fns[fn.OpenParenLoc] = *tsDecl
 	}
 
 	// "function foo(): any;"
@@ -6162,7 +6495,6 @@ func (p *parser) parseClassStmt(loc logger.Loc, opts parseStmtOpts) js_ast.Stmt
 	var name *ast.LocRef
 	classKeyword := p.lexer.Range()
 	if p.lexer.Token == js_lexer.TClass {
-		p.markSyntaxFeature(compat.Class, classKeyword)
 		p.lexer.Next()
 	} else {
 		p.lexer.Expected(js_lexer.TClass)
@@ -6212,7 +6544,6 @@ func (p *parser) parseClassStmt(loc logger.Loc, opts parseStmtOpts) js_ast.Stmt
 
 func (p *parser) parseClassExpr(decorators []js_ast.Decorator) js_ast.Expr {
 	classKeyword := p.lexer.Range()
-	p.markSyntaxFeature(compat.Class, classKeyword)
 	p.lexer.Expect(js_lexer.TClass)
 	var name *ast.LocRef
 
@@ -6304,6 +6635,10 @@ func (p *parser) parseClass(classKeyword logger.Range, name *ast.LocRef, classOp
 	}
 	hasConstructor := false
 
+	// TypeScript declaration files need the source ranges of class members,
+	// including TypeScript-only class members
+	var tsDeclMembers []tsDeclMember
+
 	for p.lexer.Token != js_lexer.TCloseBrace {
 		if p.lexer.Token == js_lexer.TSemicolon {
 			p.lexer.Next()
@@ -6316,7 +6651,21 @@ func (p *parser) parseClass(classKeyword logger.Range, name *ast.LocRef, classOp
 		opts.decorators = p.parseDecorators(p.currentScope, classKeyword, opts.decoratorContext)
 
 		// This property may turn out to be a type in TypeScript, which should be ignored
-		if property, ok := p.parseProperty(p.saveExprCommentsHere(), js_ast.PropertyNormal, opts, nil); ok {
+		propertyLoc := p.lexer.Loc()
+		property, ok := p.parseProperty(p.saveExprCommentsHere(), js_ast.PropertyNormal, opts, nil)
+		if p.tsDeclaration != nil {
+			member := tsDeclMember{
+				start:         firstDecoratorLoc.Start,
+				propertyLoc:   propertyLoc,
+				end:           p.lexer.PrevTokenEnd(),
+				propertyIndex: -1,
+			}
+			if ok {
+				member.propertyIndex = int32(len(properties))
+			}
+			tsDeclMembers = append(tsDeclMembers, member)
+		}
+		if ok {
 			properties = append(properties, property)
 
 			// Forbid decorators on class constructors
@@ -6351,6 +6700,9 @@ func (p *parser) parseClass(classKeyword logger.Range, name *ast.LocRef, classOp
 
 	closeBraceLoc := p.saveExprCommentsHere()
 	p.lexer.Expect(js_lexer.TCloseBrace)
+	if p.tsDeclaration != nil {
+		p.tsDeclaration.classes[bodyLoc] = tsDeclMembers
+	}
 	return js_ast.Class{
 		ClassKeyword:  classKeyword,
 		Decorators:    classOpts.decorators,
@@ -6493,14 +6845,7 @@ func (p *parser) parsePath() (logger.Range, string, *ast.ImportAssertOrWith, ast
 // This assumes the "function" token has already been parsed
 func (p *parser) parseFnStmt(loc logger.Loc, opts parseStmtOpts, isAsync bool, asyncRange logger.Range) js_ast.Stmt {
 	isGenerator := p.lexer.Token == js_lexer.TAsterisk
-	hasError := false
-	if isAsync {
-		hasError = p.markAsyncFn(asyncRange, isGenerator)
-	}
 	if isGenerator {
-		if !hasError {
-			p.markSyntaxFeature(compat.Generator, p.lexer.Range())
-		}
 		p.lexer.Next()
 	}
 
@@ -6633,21 +6978,13 @@ func (p *parser) parseDecorators(decoratorScope *js_ast.Scope, classKeyword logg
 				} else if (context & decoratorBeforeClassExpr) != 0 {
 					p.log.AddError(&p.tracker, p.lexer.Range(), "TypeScript experimental decorators cannot be used in expression position")
 				}
-			} else {
-				if (context&decoratorInFnArgs) != 0 && p.options.ts.Config.ExperimentalDecorators != config.True {
-					p.log.AddErrorWithNotes(&p.tracker, p.lexer.Range(), "Parameter decorators only work when experimental decorators are enabled", []logger.MsgData{{
-						Text: "You can enable experimental decorators by adding \"experimentalDecorators\": true to your \"tsconfig.json\" file.",
-					}})
-				} else {
-					p.markSyntaxFeature(compat.Decorators, p.lexer.Range())
-				}
-			}
-		} else {
-			if (context & decoratorInFnArgs) != 0 {
-				p.log.AddError(&p.tracker, p.lexer.Range(), "Parameter decorators are not allowed in JavaScript")
-			} else {
-				p.markSyntaxFeature(compat.Decorators, p.lexer.Range())
+			} else if (context & decoratorInFnArgs) != 0 {
+				p.log.AddErrorWithNotes(&p.tracker, p.lexer.Range(), "Parameter decorators only work when experimental decorators are enabled", []logger.MsgData{{
+					Text: "You can enable experimental decorators by adding \"experimentalDecorators\": true to your \"tsconfig.json\" file.",
+				}})
 			}
+		} else if (context & decoratorInFnArgs) != 0 {
+			p.log.AddError(&p.tracker, p.lexer.Range(), "Parameter decorators are not allowed in JavaScript")
 		}
 	}
 
@@ -7239,7 +7576,6 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 		if opts.lexicalDecl != lexicalDeclAllowAll {
 			p.forbidLexicalDecl(loc)
 		}
-		p.markSyntaxFeature(compat.ConstAndLet, p.lexer.Range())
 		p.lexer.Next()
 
 		if p.options.ts.Parse && p.lexer.Token == js_lexer.TEnum {
@@ -7457,17 +7793,8 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 			if p.fnOrArrowDataParse.await != allowExpr {
 				p.log.AddError(&p.tracker, awaitRange, "Cannot use \"await\" outside an async function")
 				awaitRange = logger.Range{}
-			} else {
-				didGenerateError := false
-				if p.fnOrArrowDataParse.isTopLevel {
-					p.topLevelAwaitKeyword = awaitRange
-				}
-				if !didGenerateError && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait) && p.options.unsupportedJSFeatures.Has(compat.Generator) {
-					// If for-await loops aren't supported, then we only support lowering
-					// if either async/await or generators is supported. Otherwise we
-					// cannot lower for-await loops.
-					p.markSyntaxFeature(compat.ForAwait, awaitRange)
-				}
+			} else if p.fnOrArrowDataParse.isTopLevel {
+				p.topLevelAwaitKeyword = awaitRange
 			}
 			p.lexer.Next()
 		}
@@ -7496,7 +7823,6 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 			initOrNil = js_ast.Stmt{Loc: initLoc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: decls}}
 
 		case js_lexer.TConst:
-			p.markSyntaxFeature(compat.ConstAndLet, p.lexer.Range())
 			p.lexer.Next()
 			decls = p.parseAndDeclareDecls(ast.SymbolConst, parseStmtOpts{})
 			initOrNil = js_ast.Stmt{Loc: initLoc, Data: &js_ast.SLocal{Kind: js_ast.LocalConst, Decls: decls}}
@@ -7535,7 +7861,6 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 				}
 			}
 			p.forbidInitializers(decls, "of", false)
-			p.markSyntaxFeature(compat.ForOf, p.lexer.Range())
 			p.lexer.Next()
 			value := p.parseExpr(js_ast.LComma)
 			p.lexer.Expect(js_lexer.TCloseParen)
@@ -7682,6 +8007,7 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 							break syntaxBeforePath
 						} else {
 							// "import type foo from 'bar';"
+							p.tsMetadataObjectNames[nameSubstring.String] = true
 							p.lexer.ExpectContextualKeyword("from")
 							p.parsePath()
 							p.lexer.ExpectOrInsertSemicolon()
@@ -7692,6 +8018,7 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 						// "import type * as foo from 'bar';"
 						p.lexer.Next()
 						p.lexer.ExpectContextualKeyword("as")
+						p.tsMetadataObjectNames[p.lexer.Identifier.String] = true
 						p.lexer.Expect(js_lexer.TIdentifier)
 						p.lexer.ExpectContextualKeyword("from")
 						p.parsePath()
@@ -7700,7 +8027,10 @@ func (p *parser) parseStmt(opts parseStmtOpts) js_ast.Stmt {
 
 					case js_lexer.TOpenBrace:
 						// "import type {foo} from 'bar';"
-						p.parseImportClause()
+						items, _ := p.parseImportClause()
+						for _, item := range items {
+							p.tsMetadataObjectNames[p.loadNameFromRef(item.Name.Ref)] = true
+						}
 						p.lexer.ExpectContextualKeyword("from")
 						p.parsePath()
 						p.lexer.ExpectOrInsertSemicolon()
@@ -8106,6 +8436,13 @@ func (p *parser) parseStmtsUpTo(end js_lexer.T, opts parseStmtOpts) []js_ast.Stm
 	opts.lexicalDecl = lexicalDeclAllowAll
 	isDirectivePrologue := opts.allowDirectivePrologue
 
+	// TypeScript declaration files need the source ranges of top-level and
+	// namespace-level statements, including TypeScript-only statements
+	var tsDeclStmts *[]tsDeclStmt
+	if p.tsDeclaration != nil && (opts.isModuleScope || opts.isNamespaceScope) {
+		tsDeclStmts = p.newTSDeclStmtList(opts.isModuleScope)
+	}
+
 	for {
 		// Preserve some statement-level comments
 		comments := p.lexer.LegalCommentsBeforeToken
@@ -8125,7 +8462,15 @@ func (p *parser) parseStmtsUpTo(end js_lexer.T, opts parseStmtOpts) []js_ast.Stm
 			break
 		}
 
+		stmtLoc := p.lexer.Loc()
 		stmt := p.parseStmt(opts)
+		if tsDeclStmts != nil {
+			*tsDeclStmts = append(*tsDeclStmts, tsDeclStmt{
+				stmt:  stmt,
+				start: stmtLoc.Start,
+				end:   p.lexer.PrevTokenEnd(),
+			})
+		}
 
 		// Skip TypeScript types entirely
 		if p.options.ts.Parse {
@@ -8279,6 +8624,7 @@ func (p *parser) findSymbol(loc logger.Loc, name string) findSymbolResult {
 	var ref ast.Ref
 	var declareLoc logger.Loc
 	isInsideWithScope := false
+	isInsideClosure := false
 	didForbidArguments := false
 	s := p.currentScope
 
@@ -8302,6 +8648,11 @@ func (p *parser) findSymbol(loc logger.Loc, name string) findSymbolResult {
 			break
 		}
 
+		// Track if we're inside a nested function or class body
+		if s.Kind.StopsHoisting() || s.Kind == js_ast.ScopeClassBody {
+			isInsideClosure = true
+		}
+
 		// Is the symbol a member of this scope's TypeScript namespace?
 		if tsNamespace := s.TSNamespace; tsNamespace != nil {
 			if member, ok := tsNamespace.ExportedMembers[name]; ok && tsNamespace.IsEnumScope == member.IsEnumValue {
@@ -8346,6 +8697,15 @@ func (p *parser) findSymbol(loc logger.Loc, name string) findSymbolResult {
 		p.symbols[ref.InnerIndex].Flags |= ast.MustNotBeRenamed
 	}
 
+	// If "let" and "const" are being lowered, then loops need to know which
+	// block-scoped variables are captured by a closure (see "lowerLetLoop")
+	if isInsideClosure && p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) && isBlockScopedSymbolKind(p.symbols[ref.InnerIndex].Kind) {
+		if p.letsCapturedByClosures == nil {
+			p.letsCapturedByClosures = make(map[ast.Ref]bool)
+		}
+		p.letsCapturedByClosures[ref] = true
+	}
+
 	// Track how many times we've referenced this symbol
 	p.recordUsage(ref)
 	return findSymbolResult{ref, declareLoc, isInsideWithScope}
@@ -9557,11 +9917,17 @@ func (p *parser) substituteSingleUseSymbolInExpr(
 	return expr, substituteFailure
 }
 
-func (p *parser) visitLoopBody(stmt js_ast.Stmt) js_ast.Stmt {
+func (p *parser) visitLoopBody(stmt js_ast.Stmt, loop *letLoop) js_ast.Stmt {
 	oldIsInsideLoop := p.fnOrArrowDataVisit.isInsideLoop
 	p.fnOrArrowDataVisit.isInsideLoop = true
 	p.loopBody = stmt.Data
+	if loop != nil {
+		p.beforeLetLoopBody(loop)
+	}
 	stmt = p.visitSingleStmt(stmt, stmtsLoopBody)
+	if loop != nil {
+		p.afterLetLoopBody(loop)
+	}
 	p.fnOrArrowDataVisit.isInsideLoop = oldIsInsideLoop
 	return stmt
 }
@@ -9627,7 +9993,7 @@ func (p *parser) visitForLoopInit(stmt js_ast.Stmt, isInOrOf bool) js_ast.Stmt {
 				d.ValueOrNil = p.visitExpr(d.ValueOrNil)
 			}
 		}
-		s.Decls = p.lowerObjectRestInDecls(s.Decls)
+		s.Decls = p.lowerDestructuringInDecls(s.Decls)
 		s.Kind = p.selectLocalKind(s.Kind)
 
 	default:
@@ -9644,6 +10010,17 @@ func (p *parser) recordDeclaredSymbol(ref ast.Ref) {
 	})
 }
 
+// This is for temporary variables that are declared with "var" by generated
+// code (i.e. that were generated with "tempRefNoDeclare"). Top-level ones
+// must be recorded so that they are renamed to avoid collisions.
+func (p *parser) recordDeclaredTempRef(ref ast.Ref) {
+	scope := p.currentScope
+	for !scope.Kind.StopsHoisting() {
+		scope = scope.Parent
+	}
+	p.declaredSymbols = append(p.declaredSymbols, js_ast.DeclaredSymbol{Ref: ref, IsTopLevel: scope == p.moduleScope})
+}
+
 type bindingOpts struct {
 	duplicateArgCheck map[string]logger.Range
 }
@@ -10261,6 +10638,7 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 		}
 
 		s.Stmt = p.visitSingleStmt(s.Stmt, stmtsNormal)
+		isLoop := p.currentScope.LabelStmtIsLoop
 		p.popScope()
 
 		// Drop this entire statement if requested
@@ -10269,6 +10647,28 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			return stmts
 		}
 
+		if isLoop {
+			// Lowering "let" and "const" in a loop may generate statements before the
+			// loop. Move them outside of the label so that it still labels the loop.
+			if block, ok := s.Stmt.Data.(*js_ast.SBlock); ok && len(block.Stmts) > 1 {
+				switch last := block.Stmts[len(block.Stmts)-1]; last.Data.(type) {
+				case *js_ast.SFor, *js_ast.SForIn, *js_ast.SForOf, *js_ast.SWhile, *js_ast.SDoWhile, *js_ast.STry:
+					stmts = append(stmts, block.Stmts[:len(block.Stmts)-1]...)
+					s.Stmt = last
+				}
+			}
+
+			// Lowering "for-of" and "for await" loops wraps the loop in a "try"
+			// statement. Move the label inside so that it still labels the loop.
+			if try, ok := s.Stmt.Data.(*js_ast.STry); ok && len(try.Block.Stmts) == 1 {
+				if _, ok := try.Block.Stmts[0].Data.(*js_ast.SFor); ok {
+					s.Stmt = try.Block.Stmts[0]
+					try.Block.Stmts[0] = stmt
+					return append(stmts, js_ast.Stmt{Loc: stmt.Loc, Data: try})
+				}
+			}
+		}
+
 		if p.options.minifySyntax {
 			// Optimize "x: break x" which some people apparently write by hand
 			if child, ok := s.Stmt.Data.(*js_ast.SBreak); ok && child.Label != nil && child.Label.Ref == s.Name.Ref {
@@ -10349,6 +10749,25 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 						}
 					}
 				}
+
+				// Remember top-level constants so that the linker can fold conditions
+				// in other files that import them
+				if p.options.mode == config.ModeBundle && s.Kind == js_ast.LocalConst && p.currentScope == p.moduleScope {
+					if id, ok := d.Binding.Data.(*js_ast.BIdentifier); ok {
+						if value, ok := p.exprToFoldableConst(d.ValueOrNil); ok {
+							if p.foldableConsts == nil {
+								p.foldableConsts = make(map[ast.Ref]js_ast.FoldableConst)
+							}
+							p.foldableConsts[id.Ref] = value
+
+							// Freezing a new object literal has no side effects, so let this
+							// declaration be tree-shaken if it's only used for folding
+							if call, ok := d.ValueOrNil.Data.(*js_ast.ECall); ok {
+								call.CanBeUnwrappedIfUnused = true
+							}
+						}
+					}
+				}
 			}
 
 			// Attempt to continue the const local prefix
@@ -10398,7 +10817,7 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			return stmts
 		}
 
-		s.Decls = p.lowerObjectRestInDecls(s.Decls)
+		s.Decls = p.lowerDestructuringInDecls(s.Decls)
 
 		// Optimization: Avoid unnecessary "using" machinery by changing ones
 		// initialized to "null" or "undefined" into a normal variable. Note that
@@ -10413,6 +10832,17 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			}
 		}
 
+		// A "let" declaration without an initializer resets the variable to
+		// undefined each time it's evaluated but a "var" declaration doesn't, so
+		// add an explicit initializer inside loops if "let" becomes "var"
+		if s.Kind == js_ast.LocalLet && p.fnOrArrowDataVisit.isInsideLoop && p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) {
+			for i := range s.Decls {
+				if d := &s.Decls[i]; d.ValueOrNil.Data == nil {
+					d.ValueOrNil = js_ast.Expr{Loc: d.Binding.Loc, Data: js_ast.EUndefinedShared}
+				}
+			}
+		}
+
 		s.Kind = p.selectLocalKind(s.Kind)
 
 		// Potentially relocate "var" declarations to the top level
@@ -10474,6 +10904,15 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			}
 		}
 
+		// A derived class constructor that has been lowered to ES5 must return the
+		// object that was returned from the base class constructor
+		if ref := p.fnOnlyDataVisit.derivedClassThisRef; ref != nil && p.fnOrArrowDataVisit.isDerivedClassCtor {
+			if _, ok := s.ValueOrNil.Data.(*js_ast.EUndefined); ok || s.ValueOrNil.Data == nil {
+				p.recordUsage(*ref)
+				s.ValueOrNil = js_ast.Expr{Loc: stmt.Loc, Data: &js_ast.EIdentifier{Ref: *ref}}
+			}
+		}
+
 	case *js_ast.SBlock:
 		p.pushScopeForVisitPass(js_ast.ScopeBlock, stmt.Loc)
 
@@ -10506,8 +10945,10 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 		p.popScope()
 
 	case *js_ast.SWhile:
+		loop := p.beginLetLoop(false)
 		s.Test = p.visitExpr(s.Test)
-		s.Body = p.visitLoopBody(s.Body)
+		s.Body = p.visitLoopBody(s.Body, loop)
+		stmts = p.lowerLetLoop(stmt.Loc, loop, js_ast.Stmt{}, &s.Body, stmts)
 
 		if p.options.minifySyntax {
 			s.Test = p.astHelpers.SimplifyBooleanExpr(s.Test)
@@ -10525,8 +10966,10 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 		}
 
 	case *js_ast.SDoWhile:
-		s.Body = p.visitLoopBody(s.Body)
+		loop := p.beginLetLoop(false)
+		s.Body = p.visitLoopBody(s.Body, loop)
 		s.Test = p.visitExpr(s.Test)
+		stmts = p.lowerLetLoop(stmt.Loc, loop, js_ast.Stmt{}, &s.Body, stmts)
 
 		if p.options.minifySyntax {
 			s.Test = p.astHelpers.SimplifyBooleanExpr(s.Test)
@@ -10577,6 +11020,7 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 		}
 
 	case *js_ast.SFor:
+		loop := p.beginLetLoop(true)
 		p.pushScopeForVisitPass(js_ast.ScopeBlock, stmt.Loc)
 		if s.InitOrNil.Data != nil {
 			p.visitForLoopInit(s.InitOrNil, false)
@@ -10598,7 +11042,8 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 		if s.UpdateOrNil.Data != nil {
 			s.UpdateOrNil = p.visitExpr(s.UpdateOrNil)
 		}
-		s.Body = p.visitLoopBody(s.Body)
+		s.Body = p.visitLoopBody(s.Body, loop)
+		initBeforeRelocation := s.InitOrNil
 
 		// Potentially relocate "var" declarations to the top level. Note that this
 		// must be done inside the scope of the for loop or they won't be relocated.
@@ -10616,15 +11061,18 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 
 		p.popScope()
 
+		stmts = p.lowerLetLoop(stmt.Loc, loop, initBeforeRelocation, &s.Body, stmts)
+
 		if p.options.minifySyntax {
 			mangleFor(s)
 		}
 
 	case *js_ast.SForIn:
+		loop := p.beginLetLoop(false)
 		p.pushScopeForVisitPass(js_ast.ScopeBlock, stmt.Loc)
 		p.visitForLoopInit(s.Init, true)
 		s.Value = p.visitExpr(s.Value)
-		s.Body = p.visitLoopBody(s.Body)
+		s.Body = p.visitLoopBody(s.Body, loop)
 
 		// Check for a variable initializer
 		if local, ok := s.Init.Data.(*js_ast.SLocal); ok && local.Kind == js_ast.LocalVar && len(local.Decls) == 1 {
@@ -10643,6 +11091,7 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 
 		// Potentially relocate "var" declarations to the top level. Note that this
 		// must be done inside the scope of the for loop or they won't be relocated.
+		initBeforeRelocation := s.Init
 		if init, ok := s.Init.Data.(*js_ast.SLocal); ok && init.Kind == js_ast.LocalVar {
 			if replacement, ok := p.maybeRelocateVarsToTopLevel(init.Decls, relocateVarsForInOrForOf); ok {
 				s.Init = replacement
@@ -10651,7 +11100,8 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 
 		p.popScope()
 
-		p.lowerObjectRestInForLoopInit(s.Init, &s.Body)
+		p.lowerDestructuringInForLoopInit(s.Init, &s.Body)
+		stmts = p.lowerLetLoop(stmt.Loc, loop, initBeforeRelocation, &s.Body, stmts)
 
 	case *js_ast.SForOf:
 		// Silently remove unsupported top-level "await" in dead code branches
@@ -10664,13 +11114,15 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			}
 		}
 
+		loop := p.beginLetLoop(false)
 		p.pushScopeForVisitPass(js_ast.ScopeBlock, stmt.Loc)
 		p.visitForLoopInit(s.Init, true)
 		s.Value = p.visitExpr(s.Value)
-		s.Body = p.visitLoopBody(s.Body)
+		s.Body = p.visitLoopBody(s.Body, loop)
 
 		// Potentially relocate "var" declarations to the top level. Note that this
 		// must be done inside the scope of the for loop or they won't be relocated.
+		initBeforeRelocation := s.Init
 		if init, ok := s.Init.Data.(*js_ast.SLocal); ok && init.Kind == js_ast.LocalVar {
 			if replacement, ok := p.maybeRelocateVarsToTopLevel(init.Decls, relocateVarsForInOrForOf); ok {
 				s.Init = replacement
@@ -10702,7 +11154,8 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 
 		p.popScope()
 
-		p.lowerObjectRestInForLoopInit(s.Init, &s.Body)
+		p.lowerDestructuringInForLoopInit(s.Init, &s.Body)
+		stmts = p.lowerLetLoop(stmt.Loc, loop, initBeforeRelocation, &s.Body, stmts)
 
 		// Lower "for await" if it's unsupported if it's in a lowered async generator
 		if s.Await.Len > 0 && (p.options.unsupportedJSFeatures.Has(compat.ForAwait) ||
@@ -10710,6 +11163,11 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			return p.lowerForAwaitLoop(stmt.Loc, s, stmts)
 		}
 
+		// Lower "for-of" if it's unsupported
+		if s.Await.Len == 0 && p.options.unsupportedJSFeatures.Has(compat.ForOf) {
+			return p.lowerForOfLoop(stmt.Loc, s, stmts)
+		}
+
 	case *js_ast.STry:
 		p.pushScopeForVisitPass(js_ast.ScopeBlock, stmt.Loc)
 		if p.fnOrArrowDataVisit.tryBodyCount == 0 {
@@ -10734,7 +11192,7 @@ func (p *parser) visitAndAppendStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_
 			s.Catch.Block.Stmts = p.visitStmts(s.Catch.Block.Stmts, stmtsNormal)
 			p.popScope()
 
-			p.lowerObjectRestInCatchBinding(s.Catch)
+			p.lowerDestructuringInCatchBinding(s.Catch)
 			p.popScope()
 		}
 
@@ -11390,9 +11848,23 @@ func (p *parser) visitDecorators(decorators []js_ast.Decorator, decoratorScope *
 }
 
 type visitClassResult struct {
-	bodyScope         *js_ast.Scope
-	innerClassNameRef ast.Ref
-	superCtorRef      ast.Ref
+	bodyScope           *js_ast.Scope
+	innerClassNameRef   ast.Ref
+	superCtorRef        ast.Ref
+	derivedClassThisRef ast.Ref
+
+	// When lowering a non-derived class to ES5, instance field initializers are
+	// moved into the constructor and arrow functions inside them capture "this"
+	// using this symbol. It must be declared in the constructor instead of in
+	// the function that contains the class. This is nil if it's unused.
+	instanceFieldThisCaptureRef *ast.Ref
+
+	// If JavaScript class decorators are being lowered, references to the class
+	// name inside the class body use this symbol instead of "innerClassNameRef".
+	// Class decorators can replace the class with another value, and these
+	// references must see the replacement. But "this" in static initializers
+	// must still be the original class, and that uses "innerClassNameRef".
+	decoratedClassRef ast.Ref
 
 	// If true, the class was determined to be safe to remove if the class is
 	// never used (i.e. the class definition is side-effect free). This is
@@ -11450,6 +11922,18 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 		}
 	}
 
+	// JavaScript decorators can replace private methods and observe private
+	// fields, neither of which is possible with native private names. So all
+	// private members must be lowered when lowering JavaScript decorators.
+	if classLoweringInfo.lowerStandardDecorators {
+		for _, prop := range class.Properties {
+			if private, ok := prop.Key.Data.(*js_ast.EPrivateIdentifier); ok {
+				p.symbols[private.Ref.InnerIndex].Flags |= ast.PrivateSymbolMustBeLowered
+				recomputeClassLoweringInfo = true
+			}
+		}
+	}
+
 	// Conservatively lower all private names that have been used in a private
 	// brand check anywhere in the file. See the comment on this map for details.
 	if p.lowerAllOfThesePrivateNames != nil {
@@ -11489,6 +11973,18 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 	oldSuperCtorRef := p.superCtorRef
 	p.superCtorRef = result.superCtorRef
 
+	// Create the "_this" symbol if necessary. This will cause us to replace all
+	// "this" expressions inside the constructor with this symbol, which will
+	// then be assigned the object returned from the base class constructor.
+	result.derivedClassThisRef = ast.InvalidRef
+	if p.options.unsupportedJSFeatures.Has(compat.Class) && class.ExtendsOrNil.Data != nil {
+		result.derivedClassThisRef = p.newSymbol(ast.SymbolOther, "_this")
+		p.currentScope.Generated = append(p.currentScope.Generated, result.derivedClassThisRef)
+		p.recordDeclaredSymbol(result.derivedClassThisRef)
+	}
+	oldDerivedClassThisRef := p.derivedClassThisRef
+	p.derivedClassThisRef = result.derivedClassThisRef
+
 	// Insert an immutable inner name that spans the whole class to match
 	// JavaScript's semantics specifically the "CreateImmutableBinding" here:
 	// https://262.ecma-international.org/6.0/#sec-runtime-semantics-classdefinitionevaluation
@@ -11498,10 +11994,18 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 	// original value of the name, not the re-assigned value. Use "const" for
 	// this symbol to match JavaScript run-time semantics. You are not allowed
 	// to assign to this symbol (it throws a TypeError).
+	classNameRef := ast.InvalidRef
+	result.decoratedClassRef = ast.InvalidRef
 	if class.Name != nil {
 		name := p.symbols[class.Name.Ref.InnerIndex].OriginalName
 		result.innerClassNameRef = p.newSymbol(ast.SymbolConst, "_"+name)
-		p.currentScope.Members[name] = js_ast.ScopeMember{Loc: class.Name.Loc, Ref: result.innerClassNameRef}
+		classNameRef = result.innerClassNameRef
+		if classLoweringInfo.lowerStandardDecorators && len(class.Decorators) > 0 {
+			result.decoratedClassRef = p.newSymbol(ast.SymbolConst, name)
+			p.recordDeclaredSymbol(result.decoratedClassRef)
+			classNameRef = result.decoratedClassRef
+		}
+		p.currentScope.Members[name] = js_ast.ScopeMember{Loc: class.Name.Loc, Ref: classNameRef}
 	} else {
 		name := "_this"
 		if defaultNameRef != ast.InvalidRef {
@@ -11562,7 +12066,7 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 		} else {
 			// It's forbidden to reference the class name in a computed key
 			if property.Flags.Has(js_ast.PropertyIsComputed) && class.Name != nil {
-				p.symbols[result.innerClassNameRef.InnerIndex].Kind = ast.SymbolClassInComputedPropertyKey
+				p.symbols[classNameRef.InnerIndex].Kind = ast.SymbolClassInComputedPropertyKey
 			}
 
 			key, _ := p.visitExprInOut(property.Key, exprIn{
@@ -11572,7 +12076,7 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 
 			// Re-allow using the class name after visiting a computed key
 			if property.Flags.Has(js_ast.PropertyIsComputed) && class.Name != nil {
-				p.symbols[result.innerClassNameRef.InnerIndex].Kind = ast.SymbolConst
+				p.symbols[classNameRef.InnerIndex].Kind = ast.SymbolConst
 			}
 
 			if p.options.minifySyntax {
@@ -11624,6 +12128,11 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 		oldShouldLowerSuperPropertyAccess := p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess
 		p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess = false
 		p.fnOnlyDataVisit.shouldReplaceThisWithInnerClassNameRef = false
+
+		// There is no "super" keyword once the class has been lowered to ES5
+		if p.options.unsupportedJSFeatures.Has(compat.Class) {
+			p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess = true
+		}
 		p.fnOnlyDataVisit.isThisNested = true
 		p.fnOnlyDataVisit.isNewTargetAllowed = true
 		p.fnOnlyDataVisit.isInStaticClassContext = property.Flags.Has(js_ast.PropertyIsStatic)
@@ -11680,6 +12189,14 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 				p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess = true
 			}
 
+			// Instance field initializers will be moved into the constructor
+			isMovedToCtor := !property.Flags.Has(js_ast.PropertyIsStatic) && p.options.unsupportedJSFeatures.Has(compat.Class)
+			if !property.Flags.Has(js_ast.PropertyIsStatic) && result.derivedClassThisRef != ast.InvalidRef {
+				p.fnOnlyDataVisit.derivedClassThisRef = &result.derivedClassThisRef
+			} else if isMovedToCtor {
+				p.fnOnlyDataVisit.thisCaptureRef = result.instanceFieldThisCaptureRef
+			}
+
 			// Propagate the name to keep from the field into the initializer
 			if p.options.keepNames && nameToKeep != "" {
 				p.nameToKeep = nameToKeep
@@ -11687,6 +12204,9 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 			}
 
 			property.InitializerOrNil = p.visitExpr(property.InitializerOrNil)
+			if isMovedToCtor && result.derivedClassThisRef == ast.InvalidRef {
+				result.instanceFieldThisCaptureRef = p.fnOnlyDataVisit.thisCaptureRef
+			}
 		}
 
 		// Restore "this" so it will take the inherited value in property keys
@@ -11738,10 +12258,13 @@ func (p *parser) visitClass(nameScopeLoc logger.Loc, class *js_ast.Class, defaul
 
 	p.enclosingClassKeyword = oldEnclosingClassKeyword
 	p.superCtorRef = oldSuperCtorRef
+	p.derivedClassThisRef = oldDerivedClassThisRef
 	p.popScope()
 
-	if p.symbols[result.innerClassNameRef.InnerIndex].UseCountEstimate == 0 {
-		// Don't generate a shadowing name if one isn't needed
+	if p.symbols[result.innerClassNameRef.InnerIndex].UseCountEstimate == 0 &&
+		!(classLoweringInfo.lowerStandardDecorators && len(class.Decorators) > 0) {
+		// Don't generate a shadowing name if one isn't needed. Lowered class
+		// decorators always need one because they are passed the original class.
 		result.innerClassNameRef = ast.InvalidRef
 	} else if class.Name == nil {
 		// If there was originally no class name but something inside needed one
@@ -12508,6 +13031,13 @@ func (p *parser) valueForThis(
 		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: *p.fnOnlyDataVisit.innerClassNameRef}}, true
 	}
 
+	// Substitute "this" if we're inside a derived class constructor that's
+	// being lowered to ES5
+	if ref := p.fnOnlyDataVisit.derivedClassThisRef; ref != nil {
+		p.recordUsage(*ref)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: *ref}}, true
+	}
+
 	// Is this a top-level use of "this"?
 	if !p.fnOnlyDataVisit.isThisNested {
 		// Substitute user-specified defines
@@ -12776,7 +13306,7 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 	// it doesn't affect these mitigations by ensuring that the mitigations are not
 	// applied in those cases (e.g. by adding an additional conditional check).
 	switch e := expr.Data.(type) {
-	case *js_ast.ENull, *js_ast.ESuper, *js_ast.EBoolean, *js_ast.EBigInt, *js_ast.EUndefined, *js_ast.EJSXText:
+	case *js_ast.ENull, *js_ast.ESuper, *js_ast.EBoolean, *js_ast.EBigInt, *js_ast.EUndefined, *js_ast.EJSXText, *js_ast.EImportPathString:
 
 	case *js_ast.ENameOfSymbol:
 		e.Ref = p.symbolForMangledProp(p.loadNameFromRef(e.Ref))
@@ -12847,6 +13377,10 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EIdentifier{Ref: p.captureThis()}}, exprOut{}
 		}
 
+		// Track uses of "this" in case the enclosing loop body must be moved into
+		// a function when lowering "let" and "const"
+		p.fnOnlyDataVisit.thisUseCount++
+
 	case *js_ast.EImportMeta:
 		isDeleteTarget := e == p.deleteTarget
 		isCallTarget := e == p.callTarget
@@ -12937,8 +13471,10 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 				// Make this an error when bundling because we may need to convert this
 				// "const" into a "var" during bundling. Also make this an error when
 				// the constant is inlined because we will otherwise generate code with
-				// a syntax error.
+				// a syntax error. Lowering "const" to "var" would also silently remove
+				// the run-time error, so that's an error too.
 				if _, isInlinedConstant := p.constValues[result.ref]; isInlinedConstant || p.options.mode == config.ModeBundle ||
+					p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) ||
 					(p.currentScope.Parent == nil && p.willWrapModuleInTryCatchForUsing) {
 					p.log.AddErrorWithNotes(&p.tracker, r,
 						fmt.Sprintf("Cannot assign to %q because it is a constant", name), notes)
@@ -13482,6 +14018,15 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 		isCallTarget := e == p.callTarget
 		isTemplateTag := e == p.templateTag
 
+		// Substitute "import.meta.hot" with this module's hot module replacement
+		// object. Each module is registered using its path as the key.
+		if _, ok := e.Target.Data.(*js_ast.EImportMeta); ok && e.Name == "hot" &&
+			p.options.hotModuleReplacement && in.assignTarget == js_ast.AssignTargetNone && !isDeleteTarget {
+			return p.callRuntime(expr.Loc, "__hmrHot", []js_ast.Expr{
+				{Loc: expr.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(p.source.PrettyPath)}},
+			}), exprOut{}
+		}
+
 		// Check both user-specified defines and known globals
 		if defines, ok := p.options.defines.DotDefines[e.Name]; ok {
 			for _, define := range defines {
@@ -13992,6 +14537,8 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 
 		p.awaitTarget = e.Value.Data
 		e.Value = p.visitExpr(e.Value)
+		p.fnOrArrowDataVisit.awaitOrYieldCount++
+		p.fnOrArrowDataVisit.awaitOrYieldRange = logger.Range{Loc: expr.Loc, Len: 5}
 
 		// "await" expressions turn into "yield" expressions when lowering
 		return p.maybeLowerAwait(expr.Loc, e), exprOut{}
@@ -14000,6 +14547,8 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 		if e.ValueOrNil.Data != nil {
 			e.ValueOrNil = p.visitExpr(e.ValueOrNil)
 		}
+		p.fnOrArrowDataVisit.awaitOrYieldCount++
+		p.fnOrArrowDataVisit.awaitOrYieldRange = logger.Range{Loc: expr.Loc, Len: 5}
 
 		// "yield* x" turns into "yield* __yieldStar(x)" when lowering async generator functions
 		if e.IsStar && p.options.unsupportedJSFeatures.Has(compat.AsyncGenerator) && p.fnOrArrowDataVisit.isGenerator {
@@ -14011,7 +14560,6 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			if e.CommaAfterSpread.Start != 0 {
 				p.log.AddError(&p.tracker, logger.Range{Loc: e.CommaAfterSpread, Len: 1}, "Unexpected \",\" after rest pattern")
 			}
-			p.markSyntaxFeature(compat.Destructuring, logger.Range{Loc: expr.Loc, Len: 1})
 		}
 		hasSpread := false
 		for i, item := range e.Items {
@@ -14047,12 +14595,20 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			e.Items = js_ast.InlineSpreadsOfArrayLiterals(e.Items)
 		}
 
+		// Lower array spread if it's unsupported
+		if hasSpread && in.assignTarget == js_ast.AssignTargetNone && p.options.unsupportedJSFeatures.Has(compat.ArraySpread) {
+			for _, item := range e.Items {
+				if _, ok := item.Data.(*js_ast.ESpread); ok {
+					return p.lowerArraySpread(expr.Loc, e.Items, e.IsSingleLine), exprOut{}
+				}
+			}
+		}
+
 	case *js_ast.EObject:
 		if in.assignTarget != js_ast.AssignTargetNone {
 			if e.CommaAfterSpread.Start != 0 {
 				p.log.AddError(&p.tracker, logger.Range{Loc: e.CommaAfterSpread, Len: 1}, "Unexpected \",\" after rest pattern")
 			}
-			p.markSyntaxFeature(compat.Destructuring, logger.Range{Loc: expr.Loc, Len: 1})
 		}
 
 		hasSpread := false
@@ -14121,19 +14677,26 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			if property.ValueOrNil.Data != nil {
 				oldIsInStaticClassContext := p.fnOnlyDataVisit.isInStaticClassContext
 				oldInnerClassNameRef := p.fnOnlyDataVisit.innerClassNameRef
-
-				// If this is an async method and async methods are unsupported,
-				// generate a temporary variable in case this async method contains a
-				// "super" property reference. If that happens, the "super" expression
-				// must be lowered which will need a reference to this object literal.
-				if property.Flags.Has(js_ast.PropertyIsMethod) && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait) {
-					if fn, ok := property.ValueOrNil.Data.(*js_ast.EFunction); ok && fn.Fn.IsAsync {
+				oldShouldLowerSuperPropertyAccess := p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess
+
+				// If this is an async method and async methods are unsupported (or a
+				// generator method and generator methods are unsupported, or methods
+				// are unsupported entirely), generate a temporary variable in case
+				// this method contains a "super" property reference. If that happens,
+				// the "super" expression must be lowered which will need a reference
+				// to this object literal.
+				if property.Flags.Has(js_ast.PropertyIsMethod) {
+					if fn, ok := property.ValueOrNil.Data.(*js_ast.EFunction); ok && ((fn.Fn.IsAsync && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait)) ||
+						p.isGeneratorLowered(fn.Fn.IsAsync, fn.Fn.IsGenerator) || p.options.unsupportedJSFeatures.Has(compat.ObjectExtensions)) {
 						if innerClassNameRef == ast.InvalidRef {
 							innerClassNameRef = p.generateTempRef(tempRefNeedsDeclareMayBeCapturedInsideLoop, "")
 						}
 						p.propMethodValue = property.ValueOrNil.Data
 						p.fnOnlyDataVisit.isInStaticClassContext = true
 						p.fnOnlyDataVisit.innerClassNameRef = &innerClassNameRef
+						if p.options.unsupportedJSFeatures.Has(compat.ObjectExtensions) {
+							p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess = true
+						}
 					}
 				}
 
@@ -14141,6 +14704,7 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 
 				p.fnOnlyDataVisit.innerClassNameRef = oldInnerClassNameRef
 				p.fnOnlyDataVisit.isInStaticClassContext = oldIsInStaticClassContext
+				p.fnOrArrowDataVisit.shouldLowerSuperPropertyAccess = oldShouldLowerSuperPropertyAccess
 			}
 
 			if property.InitializerOrNil.Data != nil {
@@ -14846,7 +15410,7 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			if target, loc, private := p.extractPrivateIndex(e.Target); private != nil {
 				// "foo.#bar(123)" => "__privateGet(_a = foo, #bar).call(_a, 123)"
 				targetFunc, targetWrapFunc := p.captureValueWithPossibleSideEffects(target.Loc, 2, target, valueCouldBeMutated)
-				return targetWrapFunc(js_ast.Expr{Loc: target.Loc, Data: &js_ast.ECall{
+				return targetWrapFunc(p.maybeLowerCallSpread(target.Loc, &js_ast.ECall{
 					Target: js_ast.Expr{Loc: target.Loc, Data: &js_ast.EDot{
 						Target:  p.lowerPrivateGet(targetFunc(), loc, private),
 						Name:    "call",
@@ -14855,8 +15419,15 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 					Args:                   append([]js_ast.Expr{targetFunc()}, e.Args...),
 					CanBeUnwrappedIfUnused: e.CanBeUnwrappedIfUnused,
 					Kind:                   js_ast.TargetWasOriginallyPropertyAccess,
-				}}), exprOut{}
+				})), exprOut{}
 			}
+
+			// Lower spread arguments if they're unsupported. Calls inside an optional
+			// chain are lowered along with the rest of the chain instead.
+			if p.shouldLowerCallSpread(e.Args) {
+				return p.lowerCallSpread(expr.Loc, e), exprOut{}
+			}
+
 			p.maybeLowerSuperPropertyGetInsideCall(e)
 		}
 
@@ -14953,6 +15524,8 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 	case *js_ast.ENew:
 		hasSpread := false
 
+		urlWithoutBase := p.maybeRecordNewURL(e)
+
 		e.Target = p.visitExpr(e.Target)
 		p.warnAboutImportNamespaceCall(e.Target, exprKindNew)
 
@@ -14964,6 +15537,11 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 			e.Args[i] = arg
 		}
 
+		// Substitute the base URL after visiting so that the runtime import isn't visited
+		if urlWithoutBase != nil {
+			urlWithoutBase.Args[1] = p.importFromRuntime(urlWithoutBase.Args[1].Loc, "__scriptURL")
+		}
+
 		// "new foo(1, ...[2, 3], 4)" => "new foo(1, 2, 3, 4)"
 		if p.options.minifySyntax && hasSpread {
 			e.Args = js_ast.InlineSpreadsOfArrayLiterals(e.Args)
@@ -14971,6 +15549,11 @@ func (p *parser) visitExprInOut(expr js_ast.Expr, in exprIn) (js_ast.Expr, exprO
 
 		p.maybeMarkKnownGlobalConstructorAsPure(e)
 
+		// Lower spread arguments if they're unsupported
+		if p.shouldLowerCallSpread(e.Args) {
+			return p.lowerNewSpread(expr.Loc, e), exprOut{}
+		}
+
 	case *js_ast.EArrow:
 		// Check for a propagated name to keep from the parent context
 		var nameToKeep string
@@ -16058,6 +16641,108 @@ func (p *parser) warnAboutImportNamespaceCall(target js_ast.Expr, kind importNam
 	}
 }
 
+// This checks for "new URL('./path', import.meta.url)" where the path is a
+// relative path. This must be called before the arguments are visited so that
+// the global "URL" identifier can be checked.
+func (p *parser) isNewURLWithImportMetaURL(e *js_ast.ENew) (string, bool) {
+	if len(e.Args) != 2 || !p.isDotOrIndexDefineMatch(e.Target, []string{"URL"}) {
+		return "", false
+	}
+	str, ok := e.Args[0].Data.(*js_ast.EString)
+	if !ok {
+		return "", false
+	}
+	if dot, ok := e.Args[1].Data.(*js_ast.EDot); !ok || dot.Name != "url" {
+		return "", false
+	} else if _, ok := dot.Target.Data.(*js_ast.EImportMeta); !ok {
+		return "", false
+	}
+	text := helpers.UTF16ToString(str.Value)
+	if !strings.HasPrefix(text, "./") && !strings.HasPrefix(text, "../") {
+		return "", false
+	}
+	return text, true
+}
+
+// When bundling, "new URL('./file.png', import.meta.url)" references the file
+// as an asset and "new Worker(new URL('./worker.js', import.meta.url))" causes
+// the worker file to be bundled as a separate entry point. The path string is
+// replaced with an import record so that the linker can substitute the final
+// output path.
+//
+// If "import.meta.url" will be empty in the output format, the base URL is
+// replaced with a placeholder and the "new URL()" expression is returned. The
+// caller must then substitute the base URL after the arguments are visited.
+func (p *parser) maybeRecordNewURL(e *js_ast.ENew) *js_ast.ENew {
+	if p.options.mode != config.ModeBundle || p.isControlFlowDead {
+		return nil
+	}
+
+	// Check for "new URL('./file.png', import.meta.url)"
+	if text, ok := p.isNewURLWithImportMetaURL(e); ok {
+		importRecordIndex := p.addImportRecord(ast.ImportNewURL, p.source.RangeOfString(e.Args[0].Loc), text, nil, 0)
+		p.importRecordsForCurrentPart = append(p.importRecordsForCurrentPart, importRecordIndex)
+		e.Args[0].Data = &js_ast.EImportPathString{ImportRecordIndex: importRecordIndex}
+		return p.maybeReplaceImportMetaURLBase(e)
+	}
+
+	// Check for "new Worker(new URL('./worker.js', import.meta.url))"
+	if len(e.Args) < 1 || len(e.Args) > 2 ||
+		(!p.isDotOrIndexDefineMatch(e.Target, []string{"Worker"}) && !p.isDotOrIndexDefineMatch(e.Target, []string{"SharedWorker"})) {
+		return nil
+	}
+	url, ok := e.Args[0].Data.(*js_ast.ENew)
+	if !ok {
+		return nil
+	}
+	text, ok := p.isNewURLWithImportMetaURL(url)
+	if !ok {
+		return nil
+	}
+
+	// The optional second argument determines whether this is a module worker
+	var flags ast.ImportRecordFlags
+	if len(e.Args) == 2 {
+		if object, ok := e.Args[1].Data.(*js_ast.EObject); ok {
+			for _, property := range object.Properties {
+				if key, ok := property.Key.Data.(*js_ast.EString); ok && property.Kind == js_ast.PropertyNormal &&
+					!property.Flags.Has(js_ast.PropertyIsComputed) && helpers.UTF16EqualsString(key.Value, "type") {
+					if value, ok := property.ValueOrNil.Data.(*js_ast.EString); ok && helpers.UTF16EqualsString(value.Value, "module") {
+						flags |= ast.IsModuleWorker
+					}
+				}
+			}
+		}
+	}
+
+	importRecordIndex := p.addImportRecord(ast.ImportWorker, p.source.RangeOfString(url.Args[0].Loc), text, nil, flags)
+	p.importRecordsForCurrentPart = append(p.importRecordsForCurrentPart, importRecordIndex)
+	url.Args[0].Data = &js_ast.EImportPathString{ImportRecordIndex: importRecordIndex}
+	return p.maybeReplaceImportMetaURLBase(url)
+}
+
+// The path of the asset or worker is relative to the output file, so the base
+// URL must be the URL of the output file. That's "import.meta.url" in ESM but
+// it's empty in other output formats, which makes "new URL()" throw. Use the
+// URL of the currently-running script instead.
+func (p *parser) maybeReplaceImportMetaURLBase(url *js_ast.ENew) *js_ast.ENew {
+	if !p.options.unsupportedJSFeatures.Has(compat.ImportMeta) && p.options.outputFormat.KeepESMImportExportSyntax() {
+		return nil
+	}
+
+	// Don't override a user-specified value for "import.meta.url"
+	if defines, ok := p.options.defines.DotDefines["url"]; ok {
+		for _, define := range defines {
+			if p.isDotOrIndexDefineMatch(url.Args[1], define.Parts) {
+				return nil
+			}
+		}
+	}
+
+	url.Args[1] = js_ast.Expr{Loc: url.Args[1].Loc, Data: js_ast.EUndefinedShared}
+	return url
+}
+
 func (p *parser) maybeMarkKnownGlobalConstructorAsPure(e *js_ast.ENew) {
 	if id, ok := e.Target.Data.(*js_ast.EIdentifier); ok {
 		if symbol := p.symbols[id.Ref.InnerIndex]; symbol.Kind == ast.SymbolUnbound {
@@ -16178,6 +16863,62 @@ func (p *parser) maybeMarkKnownGlobalConstructorAsPure(e *js_ast.ENew) {
 	}
 }
 
+func primitiveToConstValue(expr js_ast.Expr) (js_ast.ConstValue, bool) {
+	switch e := expr.Data.(type) {
+	case *js_ast.ENull, *js_ast.EUndefined, *js_ast.EBoolean:
+		return js_ast.ExprToConstValue(expr), true
+
+	case *js_ast.ENumber:
+		return js_ast.ConstValue{Kind: js_ast.ConstValueNumber, Number: e.Value}, true
+
+	case *js_ast.EString:
+		return js_ast.ConstValue{Kind: js_ast.ConstValueString, String: e.Value}, true
+	}
+	return js_ast.ConstValue{}, false
+}
+
+// Returns the value of "const x = value" if it's a primitive, or if it's a
+// call to "Object.freeze()" on an object literal that only contains primitives
+func (p *parser) exprToFoldableConst(expr js_ast.Expr) (js_ast.FoldableConst, bool) {
+	if value, ok := primitiveToConstValue(expr); ok {
+		return js_ast.FoldableConst{Value: value}, true
+	}
+
+	call, ok := expr.Data.(*js_ast.ECall)
+	if !ok || len(call.Args) != 1 || call.OptionalChain != js_ast.OptionalChainNone {
+		return js_ast.FoldableConst{}, false
+	}
+	dot, ok := call.Target.Data.(*js_ast.EDot)
+	if !ok || dot.Name != "freeze" || dot.OptionalChain != js_ast.OptionalChainNone {
+		return js_ast.FoldableConst{}, false
+	}
+	if id, ok := dot.Target.Data.(*js_ast.EIdentifier); !ok || p.symbols[id.Ref.InnerIndex].Kind != ast.SymbolUnbound ||
+		p.symbols[id.Ref.InnerIndex].OriginalName != "Object" {
+		return js_ast.FoldableConst{}, false
+	}
+	obj, ok := call.Args[0].Data.(*js_ast.EObject)
+	if !ok {
+		return js_ast.FoldableConst{}, false
+	}
+
+	properties := make(map[string]js_ast.ConstValue, len(obj.Properties))
+	for _, prop := range obj.Properties {
+		if prop.Kind != js_ast.PropertyNormal || prop.Flags.Has(js_ast.PropertyIsComputed) || prop.Flags.Has(js_ast.PropertyIsMethod) {
+			return js_ast.FoldableConst{}, false
+		}
+		key, ok := prop.Key.Data.(*js_ast.EString)
+		if !ok || helpers.UTF16EqualsString(key.Value, "__proto__") {
+			return js_ast.FoldableConst{}, false
+		}
+		value, ok := primitiveToConstValue(prop.ValueOrNil)
+		if !ok {
+			return js_ast.FoldableConst{}, false
+		}
+		properties[helpers.UTF16ToString(key.Value)] = value
+	}
+	return js_ast.FoldableConst{Properties: properties}, true
+}
+
 type identifierOpts struct {
 	assignTarget            js_ast.AssignTarget
 	isCallTarget            bool
@@ -16202,9 +16943,14 @@ func (p *parser) handleIdentifier(loc logger.Loc, e *js_ast.EIdentifier, opts id
 	if p.fnOnlyDataVisit.argumentsRef != nil && ref == *p.fnOnlyDataVisit.argumentsRef {
 		isInsideUnsupportedArrow := p.fnOrArrowDataVisit.isArrow && p.options.unsupportedJSFeatures.Has(compat.Arrow)
 		isInsideUnsupportedAsyncArrow := p.fnOnlyDataVisit.isInsideAsyncArrowFn && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait)
-		if isInsideUnsupportedArrow || isInsideUnsupportedAsyncArrow {
+		if isInsideUnsupportedArrow || isInsideUnsupportedAsyncArrow || p.fnOnlyDataVisit.isInsideLoweredGeneratorFn {
 			return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: p.captureArguments()}}
 		}
+
+		// Track uses of "arguments" in case the enclosing loop body must be moved
+		// into a function when lowering "let" and "const"
+		p.fnOnlyDataVisit.argumentsUseCount++
+		p.fnOnlyDataVisit.argumentsUseLoc = loc
 	}
 
 	// Create an error for assigning to an import namespace
@@ -16341,12 +17087,13 @@ func (p *parser) visitFn(fn *js_ast.Fn, scopeLoc logger.Loc, opts visitFnOpts) {
 		isAsync:                        fn.IsAsync,
 		isGenerator:                    fn.IsGenerator,
 		isDerivedClassCtor:             opts.isDerivedClassCtor,
-		shouldLowerSuperPropertyAccess: fn.IsAsync && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait),
+		shouldLowerSuperPropertyAccess: (fn.IsAsync && p.options.unsupportedJSFeatures.Has(compat.AsyncAwait)) || p.isGeneratorLowered(fn.IsAsync, fn.IsGenerator),
 	}
 	p.fnOnlyDataVisit = fnOnlyDataVisit{
-		isThisNested:       true,
-		isNewTargetAllowed: true,
-		argumentsRef:       &fn.ArgumentsRef,
+		isThisNested:               true,
+		isNewTargetAllowed:         true,
+		argumentsRef:               &fn.ArgumentsRef,
+		isInsideLoweredGeneratorFn: p.isGeneratorLowered(fn.IsAsync, fn.IsGenerator),
 	}
 
 	if opts.isClassMethod {
@@ -16358,6 +17105,11 @@ func (p *parser) visitFn(fn *js_ast.Fn, scopeLoc logger.Loc, opts visitFnOpts) {
 		}
 	}
 
+	if opts.isDerivedClassCtor && p.derivedClassThisRef != ast.InvalidRef {
+		ref := p.derivedClassThisRef
+		p.fnOnlyDataVisit.derivedClassThisRef = &ref
+	}
+
 	if fn.Name != nil {
 		p.recordDeclaredSymbol(fn.Name.Ref)
 	}
@@ -16831,6 +17583,12 @@ func (p *parser) appendPart(parts []js_ast.Part, stmts []js_ast.Stmt) []js_ast.P
 		panic("Internal error: Scope stack imbalance")
 	}
 
+	// Block-scoped symbols become function-scoped if "let" and "const" are
+	// lowered, so they must be renamed to avoid collisions with each other
+	if p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) {
+		p.hoistBlockScopedSymbolsForRenaming()
+	}
+
 	// Insert any relocated variable statements now
 	if len(p.relocatedTopLevelVars) > 0 {
 		alreadyDeclared := make(map[ast.Ref]bool)
@@ -16897,6 +17655,9 @@ func newParser(log logger.Log, source logger.Source, lexer js_lexer.Lexer, optio
 		importMetaRef:      ast.InvalidRef,
 		superCtorRef:       ast.InvalidRef,
 
+		// For lowering classes
+		derivedClassThisRef: ast.InvalidRef,
+
 		// For lowering private methods
 		weakMapRef:     ast.InvalidRef,
 		weakSetRef:     ast.InvalidRef,
@@ -16908,6 +17669,8 @@ func newParser(log logger.Log, source logger.Source, lexer js_lexer.Lexer, optio
 		emittedNamespaceVars:       make(map[ast.Ref]bool),
 		isExportedInsideNamespace:  make(map[ast.Ref]ast.Ref),
 		localTypeNames:             make(map[string]bool),
+		tsMetadataObjectNames:      make(map[string]bool),
+		tsEnumMetadataTypes:        make(map[ast.Ref]string),
 
 		// These are for handling ES6 imports and exports
 		importItemsForNamespace: make(map[ast.Ref]namespaceImportItems),
@@ -16922,6 +17685,10 @@ func newParser(log logger.Log, source logger.Source, lexer js_lexer.Lexer, optio
 		suppressWarningsAboutWeirdCode: helpers.IsInsideNodeModules(source.KeyPath.Text),
 	}
 
+	if options.tsDeclaration && options.ts.Parse {
+		p.tsDeclaration = newTSDeclarationData()
+	}
+
 	if len(options.dropLabels) > 0 {
 		p.dropLabelsMap = make(map[string]struct{})
 		for _, name := range options.dropLabels {
@@ -16987,6 +17754,12 @@ func Parse(log logger.Log, source logger.Source, options Options) (result js_ast
 		isModuleScope:          true,
 		allowDirectivePrologue: true,
 	})
+
+	// Declaration files must be generated before the visit pass transforms the AST
+	var tsDeclaration string
+	if p.tsDeclaration != nil {
+		tsDeclaration = p.generateTSDeclaration()
+	}
 	p.prepareForVisitPass()
 
 	// Insert a "use strict" directive if "alwaysStrict" is active
@@ -17231,9 +18004,22 @@ func Parse(log logger.Log, source logger.Source, options Options) (result js_ast
 				p.scopesInOrder = p.scopesInOrder[len(p.scopesInOrderForEnum[stmt.Loc]):]
 
 			default:
-				parts = p.appendPart(parts, []js_ast.Stmt{stmt})
+				if split := p.splitCommonJSObjectExport(stmt); split != nil {
+					// Put each property of "module.exports = { ... }" in its own part
+					group := commonJSObjectExport{partsStart: len(parts)}
+					for _, stmt := range split {
+						parts = p.appendPart(parts, []js_ast.Stmt{stmt})
+					}
+					group.partsEnd = len(parts)
+					p.commonJSObjectExports = append(p.commonJSObjectExports, group)
+				} else {
+					parts = p.appendPart(parts, []js_ast.Stmt{stmt})
+				}
 			}
 		}
+
+		// Try to convert CommonJS exports into ESM exports for tree shaking
+		parts = p.convertCommonJSExportsToESM(parts)
 	}
 
 	// Insert a variable for "import.meta" at the top of the file if it was used.
@@ -17261,6 +18047,7 @@ func Parse(log logger.Log, source logger.Source, options Options) (result js_ast
 
 	result = p.toAST(before, parts, after, hashbang, directives)
 	result.SourceMapComment = p.lexer.SourceMappingURL
+	result.TSDeclaration = tsDeclaration
 	return
 }
 
@@ -17900,7 +18687,7 @@ func (p *parser) toAST(before, parts, after []js_ast.Part, hashbang string, dire
 	usesExportsRef := p.symbols[p.exportsRef.InnerIndex].UseCountEstimate > 0
 	usesModuleRef := p.symbols[p.moduleRef.InnerIndex].UseCountEstimate > 0
 
-	if p.esmExportKeyword.Len > 0 || p.esmImportMeta.Len > 0 || p.topLevelAwaitKeyword.Len > 0 {
+	if p.esmExportKeyword.Len > 0 || p.esmImportMeta.Len > 0 || p.topLevelAwaitKeyword.Len > 0 || p.commonJSExportsWereConvertedToESM {
 		exportsKind = js_ast.ExportsESM
 	} else if usesExportsRef || usesModuleRef || p.hasTopLevelReturn {
 		exportsKind = js_ast.ExportsCommonJS
@@ -17942,6 +18729,7 @@ func (p *parser) toAST(before, parts, after []js_ast.Part, hashbang string, dire
 		NamedExports:                    p.namedExports,
 		TSEnums:                         p.tsEnums,
 		ConstValues:                     p.constValues,
+		FoldableConsts:                  p.foldableConsts,
 		ExprComments:                    p.exprComments,
 		NestedScopeSlotCounts:           nestedScopeSlotCounts,
 		TopLevelSymbolToPartsFromParser: p.topLevelSymbolToParts,
@@ -17953,9 +18741,10 @@ func (p *parser) toAST(before, parts, after []js_ast.Part, hashbang string, dire
 		ManifestForYarnPnP:              p.manifestForYarnPnP,
 
 		// CommonJS features
-		UsesExportsRef: usesExportsRef,
-		UsesModuleRef:  usesModuleRef,
-		ExportsKind:    exportsKind,
+		UsesExportsRef:        usesExportsRef,
+		UsesModuleRef:         usesModuleRef,
+		ExportsKind:           exportsKind,
+		ConvertedFromCommonJS: p.commonJSExportsWereConvertedToESM,
 
 		// ES6 features
 		ExportKeyword:            p.esmExportKeyword,
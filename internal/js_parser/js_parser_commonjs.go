@@ -0,0 +1,586 @@
+package js_parser
+
+// This file implements the "treeShakeCommonJS" option, which converts
+// CommonJS modules with statically-analyzable exports into ECMAScript modules
+// so that the linker can tree-shake their exports individually. CommonJS
+// modules are otherwise wrapped in a closure and included in their entirety.
+//
+// A module is only converted if every use of "exports" and "module" is one of
+// the following top-level statements:
+//
+//   exports.foo = value;
+//   module.exports.foo = value;
+//   module.exports = { foo: value, bar };
+//   exports.foo = exports.bar = void 0;
+//   exports.__esModule = true;
+//   Object.defineProperty(exports, "__esModule", { value: true });
+//
+// Anything else (e.g. reading "exports.foo", passing "exports" to a function,
+// top-level "this", or top-level "return") causes the module to be left alone.
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/config"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// These are the parts that "module.exports = { ... }" was split into
+type commonJSObjectExport struct {
+	partsStart int
+	partsEnd   int
+}
+
+type commonJSExportKind uint8
+
+const (
+	// "exports.foo = value" or "module.exports = { foo: value }"
+	commonJSExportValue commonJSExportKind = iota
+
+	// "exports.foo = void 0" before the actual assignment (emitted by TypeScript)
+	commonJSExportPreDeclaration
+
+	// "exports.__esModule = true" and friends
+	commonJSExportESModuleMarker
+)
+
+type commonJSExport struct {
+	value     js_ast.Expr
+	alias     string
+	aliasLoc  logger.Loc
+	partIndex int
+	kind      commonJSExportKind
+}
+
+// This is called on each top-level statement before it's visited. Each
+// property of "module.exports = { ... }" is moved into a separate part so
+// that it can later become a separate export. These parts are merged back
+// together again by "convertCommonJSExportsToESM" if the conversion fails.
+func (p *parser) splitCommonJSObjectExport(stmt js_ast.Stmt) []js_ast.Stmt {
+	if !p.options.treeShakeCommonJS || p.options.mode != config.ModeBundle || p.isFileConsideredESM || p.options.mangleProps != nil {
+		return nil
+	}
+
+	// Don't do this if a define could substitute "module" or "module.exports"
+	if _, ok := p.options.defines.IdentifierDefines["module"]; ok {
+		return nil
+	}
+	if _, ok := p.options.defines.DotDefines["exports"]; ok {
+		return nil
+	}
+
+	s, ok := stmt.Data.(*js_ast.SExpr)
+	if !ok {
+		return nil
+	}
+	assign, ok := s.Value.Data.(*js_ast.EBinary)
+	if !ok || assign.Op != js_ast.BinOpAssign {
+		return nil
+	}
+	dot, ok := assign.Left.Data.(*js_ast.EDot)
+	if !ok || dot.Name != "exports" || dot.OptionalChain != js_ast.OptionalChainNone {
+		return nil
+	}
+	id, ok := dot.Target.Data.(*js_ast.EIdentifier)
+	if !ok || p.loadNameFromRef(id.Ref) != "module" {
+		return nil
+	}
+	obj, ok := assign.Right.Data.(*js_ast.EObject)
+	if !ok || len(obj.Properties) == 0 {
+		return nil
+	}
+	for _, prop := range obj.Properties {
+		if prop.Kind != js_ast.PropertyNormal || prop.Flags.Has(js_ast.PropertyIsComputed) || prop.Flags.Has(js_ast.PropertyIsMethod) {
+			return nil
+		}
+		if key, ok := prop.Key.Data.(*js_ast.EString); !ok || helpers.UTF16EqualsString(key.Value, "__proto__") {
+			return nil
+		}
+	}
+
+	stmts := make([]js_ast.Stmt, 0, len(obj.Properties))
+	for _, prop := range obj.Properties {
+		clone := *obj
+		clone.Properties = []js_ast.Property{prop}
+		stmts = append(stmts, js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: s.Value.Loc, Data: &js_ast.EBinary{
+			Op: js_ast.BinOpAssign,
+			Left: js_ast.Expr{Loc: assign.Left.Loc, Data: &js_ast.EDot{
+				Target:  js_ast.Expr{Loc: dot.Target.Loc, Data: &js_ast.EIdentifier{Ref: id.Ref}},
+				Name:    dot.Name,
+				NameLoc: dot.NameLoc,
+			}},
+			Right: js_ast.Expr{Loc: assign.Right.Loc, Data: &clone},
+		}}}})
+	}
+	return stmts
+}
+
+// Returns the export alias if this is "exports.foo", "exports['foo']", or
+// "module.exports.foo". The number of "exports" and "module" references that
+// were used is also returned.
+func (p *parser) commonJSExportTarget(expr js_ast.Expr) (alias string, aliasLoc logger.Loc, exportsUses int, moduleUses int, ok bool) {
+	var target js_ast.Expr
+	switch e := expr.Data.(type) {
+	case *js_ast.EDot:
+		if e.OptionalChain != js_ast.OptionalChainNone {
+			return
+		}
+		target, alias, aliasLoc = e.Target, e.Name, e.NameLoc
+
+	case *js_ast.EIndex:
+		str, isString := e.Index.Data.(*js_ast.EString)
+		if !isString || e.OptionalChain != js_ast.OptionalChainNone {
+			return
+		}
+		target, alias, aliasLoc = e.Target, helpers.UTF16ToString(str.Value), e.Index.Loc
+
+	default:
+		return
+	}
+
+	if p.isCommonJSExportsObject(target) {
+		exportsUses = 1
+	} else if p.isCommonJSModuleExports(target) {
+		moduleUses = 1
+	} else {
+		return
+	}
+	ok = alias != "__proto__"
+	return
+}
+
+func (p *parser) isCommonJSExportsObject(expr js_ast.Expr) bool {
+	id, ok := expr.Data.(*js_ast.EIdentifier)
+	return ok && id.Ref == p.exportsRef
+}
+
+func (p *parser) isCommonJSModuleExports(expr js_ast.Expr) bool {
+	if dot, ok := expr.Data.(*js_ast.EDot); ok && dot.Name == "exports" && dot.OptionalChain == js_ast.OptionalChainNone {
+		id, ok := dot.Target.Data.(*js_ast.EIdentifier)
+		return ok && id.Ref == p.moduleRef
+	}
+	return false
+}
+
+func isTrueLiteral(expr js_ast.Expr) bool {
+	b, ok := expr.Data.(*js_ast.EBoolean)
+	return ok && b.Value
+}
+
+// Matches "Object.defineProperty(exports, '__esModule', { value: true })"
+func (p *parser) isCommonJSDefinePropertyESModuleMarker(expr js_ast.Expr) bool {
+	call, ok := expr.Data.(*js_ast.ECall)
+	if !ok || len(call.Args) != 3 || call.OptionalChain != js_ast.OptionalChainNone {
+		return false
+	}
+	dot, ok := call.Target.Data.(*js_ast.EDot)
+	if !ok || dot.Name != "defineProperty" || dot.OptionalChain != js_ast.OptionalChainNone {
+		return false
+	}
+	if id, ok := dot.Target.Data.(*js_ast.EIdentifier); !ok || p.symbols[id.Ref.InnerIndex].OriginalName != "Object" ||
+		p.symbols[id.Ref.InnerIndex].Kind != ast.SymbolUnbound {
+		return false
+	}
+	if !p.isCommonJSExportsObject(call.Args[0]) {
+		return false
+	}
+	if str, ok := call.Args[1].Data.(*js_ast.EString); !ok || !helpers.UTF16EqualsString(str.Value, "__esModule") {
+		return false
+	}
+	obj, ok := call.Args[2].Data.(*js_ast.EObject)
+	if !ok || len(obj.Properties) != 1 {
+		return false
+	}
+	prop := obj.Properties[0]
+	key, ok := prop.Key.Data.(*js_ast.EString)
+	return ok && prop.Kind == js_ast.PropertyNormal && !prop.Flags.Has(js_ast.PropertyIsComputed) &&
+		helpers.UTF16EqualsString(key.Value, "value") && isTrueLiteral(prop.ValueOrNil)
+}
+
+// This must be called after all top-level statements have been visited. It
+// either converts the CommonJS exports in "parts" into ESM exports, or merges
+// back together any parts that were split by "splitCommonJSObjectExport".
+func (p *parser) convertCommonJSExportsToESM(parts []js_ast.Part) []js_ast.Part {
+	if !p.options.treeShakeCommonJS || p.options.mode != config.ModeBundle || !p.options.treeShaking ||
+		p.willWrapModuleInTryCatchForUsing || p.isFileConsideredESM || p.hasTopLevelReturn ||
+		p.moduleScope.ContainsDirectEval || p.options.moduleTypeData.Type.IsESM() {
+		return p.mergeCommonJSObjectExports(parts)
+	}
+
+	exports, ok := p.findCommonJSExports(parts)
+	if !ok || len(exports) == 0 {
+		return p.mergeCommonJSObjectExports(parts)
+	}
+
+	// Count how many top-level declarations each symbol has
+	topLevelDeclCounts := make(map[ast.Ref]int)
+	for _, part := range parts {
+		for _, declared := range part.DeclaredSymbols {
+			if declared.IsTopLevel {
+				topLevelDeclCounts[declared.Ref]++
+			}
+		}
+	}
+
+	// Node's "exports" and "module" must not have been redeclared
+	if topLevelDeclCounts[p.exportsRef] > 0 || topLevelDeclCounts[p.moduleRef] > 0 {
+		return p.mergeCommonJSObjectExports(parts)
+	}
+
+	hasESModuleMarker := false
+	for _, export := range exports {
+		if export.kind == commonJSExportESModuleMarker {
+			hasESModuleMarker = true
+		}
+	}
+
+	// Each export must be assigned exactly once. Without the "__esModule"
+	// marker, a default import is the whole "exports" object, so we can't
+	// represent an export named "default" in that case.
+	seen := make(map[string]bool)
+	preDeclared := make(map[string]bool)
+	for _, export := range exports {
+		switch export.kind {
+		case commonJSExportValue:
+			if seen[export.alias] || (export.alias == "default" && !hasESModuleMarker) {
+				return p.mergeCommonJSObjectExports(parts)
+			}
+			seen[export.alias] = true
+
+		case commonJSExportPreDeclaration:
+			preDeclared[export.alias] = true
+		}
+	}
+	for alias := range preDeclared {
+		if !seen[alias] {
+			return p.mergeCommonJSObjectExports(parts)
+		}
+	}
+
+	// Everything checks out, so convert each export into a variable
+	var defaultProperties []js_ast.Property
+	for _, export := range exports {
+		part := &parts[export.partIndex]
+
+		// All uses of "exports" and "module" in this part are going away
+		for ref, use := range part.SymbolUses {
+			if ref == p.exportsRef || ref == p.moduleRef {
+				p.symbols[ref.InnerIndex].UseCountEstimate -= use.CountEstimate
+				delete(part.SymbolUses, ref)
+			}
+		}
+
+		if export.kind != commonJSExportValue {
+			p.removeCommonJSExportPart(part)
+			continue
+		}
+
+		// Export top-level functions, classes, and constants directly if we can.
+		// Otherwise make a new variable to hold the value.
+		ref, ok := p.commonJSExportDirectRef(export.value, topLevelDeclCounts)
+		if ok {
+			p.removeCommonJSExportPart(part)
+		} else {
+			name := js_ast.EnsureValidIdentifier(export.alias)
+			if export.alias == "default" {
+				name = p.source.IdentifierName + "_default"
+			}
+			ref = p.newSymbol(ast.SymbolHoisted, name)
+			p.moduleScope.Generated = append(p.moduleScope.Generated, ref)
+			part.Stmts = []js_ast.Stmt{{Loc: part.Stmts[0].Loc, Data: &js_ast.SLocal{
+				Kind: js_ast.LocalVar,
+				Decls: []js_ast.Decl{{
+					Binding:    js_ast.Binding{Loc: export.aliasLoc, Data: &js_ast.BIdentifier{Ref: ref}},
+					ValueOrNil: export.value,
+				}},
+			}}}
+			part.DeclaredSymbols = append(part.DeclaredSymbols, js_ast.DeclaredSymbol{Ref: ref, IsTopLevel: true})
+			part.CanBeRemovedIfUnused = p.astHelpers.StmtsCanBeRemovedIfUnused(part.Stmts, 0)
+		}
+
+		p.recordExport(export.aliasLoc, export.alias, ref)
+		defaultProperties = append(defaultProperties, js_ast.Property{
+			Key:        js_ast.Expr{Loc: export.aliasLoc, Data: &js_ast.EString{Value: helpers.StringToUTF16(export.alias)}},
+			ValueOrNil: js_ast.Expr{Loc: export.aliasLoc, Data: &js_ast.EIdentifier{Ref: ref}},
+			Flags:      js_ast.PropertyWasShorthand,
+		})
+	}
+
+	// Without the "__esModule" marker, importing the default export of a
+	// CommonJS module gives you the "exports" object itself. Emulate that
+	// with an object literal that's only included if it's actually imported.
+	if !hasESModuleMarker {
+		ref := p.newSymbol(ast.SymbolHoisted, p.source.IdentifierName+"_default")
+		p.moduleScope.Generated = append(p.moduleScope.Generated, ref)
+		symbolUses := make(map[ast.Ref]js_ast.SymbolUse)
+		for _, prop := range defaultProperties {
+			propRef := prop.ValueOrNil.Data.(*js_ast.EIdentifier).Ref
+			use := symbolUses[propRef]
+			use.CountEstimate++
+			symbolUses[propRef] = use
+			p.symbols[propRef.InnerIndex].UseCountEstimate++
+		}
+		parts = append(parts, js_ast.Part{
+			Stmts: []js_ast.Stmt{{Data: &js_ast.SLocal{
+				Kind: js_ast.LocalVar,
+				Decls: []js_ast.Decl{{
+					Binding:    js_ast.Binding{Data: &js_ast.BIdentifier{Ref: ref}},
+					ValueOrNil: js_ast.Expr{Data: &js_ast.EObject{Properties: defaultProperties}},
+				}},
+			}}},
+			SymbolUses:           symbolUses,
+			DeclaredSymbols:      []js_ast.DeclaredSymbol{{Ref: ref, IsTopLevel: true}},
+			CanBeRemovedIfUnused: true,
+		})
+		p.recordExport(logger.Loc{}, "default", ref)
+	}
+
+	p.commonJSExportsWereConvertedToESM = true
+	return parts
+}
+
+// Returns all export statements, or false if there are uses of "exports" or
+// "module" that aren't part of an export statement we can convert
+func (p *parser) findCommonJSExports(parts []js_ast.Part) (exports []commonJSExport, ok bool) {
+	exportsUses := 0
+	moduleUses := 0
+	objectExportCount := 0
+	otherExportCount := 0
+
+	// Parts from "module.exports = { ... }" have already been split up
+	objectExportParts := make(map[int]bool)
+	for _, group := range p.commonJSObjectExports {
+		for partIndex := group.partsStart; partIndex < group.partsEnd; partIndex++ {
+			objectExportParts[partIndex] = true
+		}
+	}
+
+	for partIndex, part := range parts {
+		if len(part.Stmts) != 1 {
+			continue
+		}
+		s, isExpr := part.Stmts[0].Data.(*js_ast.SExpr)
+		if !isExpr {
+			continue
+		}
+
+		// "Object.defineProperty(exports, '__esModule', { value: true })"
+		if p.isCommonJSDefinePropertyESModuleMarker(s.Value) {
+			exports = append(exports, commonJSExport{partIndex: partIndex, kind: commonJSExportESModuleMarker})
+			exportsUses++
+			otherExportCount++
+			continue
+		}
+
+		assign, isAssign := s.Value.Data.(*js_ast.EBinary)
+		if !isAssign || assign.Op != js_ast.BinOpAssign {
+			continue
+		}
+
+		// "module.exports = { foo: value }"
+		if objectExportParts[partIndex] {
+			obj, isObject := assign.Right.Data.(*js_ast.EObject)
+			if !p.isCommonJSModuleExports(assign.Left) || !isObject || len(obj.Properties) != 1 {
+				return nil, false
+			}
+			prop := obj.Properties[0]
+			key, isString := prop.Key.Data.(*js_ast.EString)
+			if !isString {
+				return nil, false
+			}
+			export := commonJSExport{
+				value:     prop.ValueOrNil,
+				alias:     helpers.UTF16ToString(key.Value),
+				aliasLoc:  prop.Key.Loc,
+				partIndex: partIndex,
+			}
+			if export.alias == "__esModule" && isTrueLiteral(export.value) {
+				export.kind = commonJSExportESModuleMarker
+			}
+			exports = append(exports, export)
+			moduleUses++
+			continue
+		}
+
+		// "exports.foo = value"
+		alias, aliasLoc, exportsCount, moduleCount, isExport := p.commonJSExportTarget(assign.Left)
+		if !isExport {
+			continue
+		}
+		exportsUses += exportsCount
+		moduleUses += moduleCount
+		otherExportCount++
+
+		// "exports.foo = exports.bar = void 0"
+		value := assign.Right
+		var preDeclared []commonJSExport
+		for {
+			chain, isChain := value.Data.(*js_ast.EBinary)
+			if !isChain || chain.Op != js_ast.BinOpAssign {
+				break
+			}
+			chainAlias, chainAliasLoc, exportsCount, moduleCount, isExport := p.commonJSExportTarget(chain.Left)
+			if !isExport {
+				break
+			}
+			exportsUses += exportsCount
+			moduleUses += moduleCount
+			preDeclared = append(preDeclared, commonJSExport{alias: chainAlias, aliasLoc: chainAliasLoc, partIndex: partIndex, kind: commonJSExportPreDeclaration})
+			value = chain.Right
+		}
+		if len(preDeclared) > 0 {
+			if _, isUndefined := value.Data.(*js_ast.EUndefined); !isUndefined {
+				return nil, false
+			}
+			exports = append(exports, commonJSExport{alias: alias, aliasLoc: aliasLoc, partIndex: partIndex, kind: commonJSExportPreDeclaration})
+			exports = append(exports, preDeclared...)
+			continue
+		}
+
+		export := commonJSExport{value: value, alias: alias, aliasLoc: aliasLoc, partIndex: partIndex}
+		if alias == "__esModule" && isTrueLiteral(value) {
+			export.kind = commonJSExportESModuleMarker
+		}
+		exports = append(exports, export)
+	}
+
+	// Every use of "exports" and "module" must have been accounted for
+	if uint32(exportsUses) != p.symbols[p.exportsRef.InnerIndex].UseCountEstimate ||
+		uint32(moduleUses) != p.symbols[p.moduleRef.InnerIndex].UseCountEstimate {
+		return nil, false
+	}
+
+	// Assigning to "module.exports" replaces the exports object, so it can't
+	// be combined with other exports. It also can't happen more than once.
+	for _, group := range p.commonJSObjectExports {
+		if group.partsEnd > group.partsStart {
+			objectExportCount++
+		}
+	}
+	if objectExportCount > 1 || (objectExportCount == 1 && otherExportCount > 0) {
+		return nil, false
+	}
+
+	ok = true
+	return
+}
+
+// Returns the symbol to export if the value is a reference to a top-level
+// declaration that will always have the same value as the export
+func (p *parser) commonJSExportDirectRef(value js_ast.Expr, topLevelDeclCounts map[ast.Ref]int) (ast.Ref, bool) {
+	id, ok := value.Data.(*js_ast.EIdentifier)
+	if !ok || topLevelDeclCounts[id.Ref] != 1 {
+		return ast.InvalidRef, false
+	}
+	symbol := &p.symbols[id.Ref.InnerIndex]
+	if symbol.Link != ast.InvalidRef || symbol.Flags.Has(ast.CouldPotentiallyBeMutated) {
+		return ast.InvalidRef, false
+	}
+	switch symbol.Kind {
+	case ast.SymbolHoistedFunction, ast.SymbolGeneratorOrAsyncFunction, ast.SymbolClass, ast.SymbolConst:
+		return id.Ref, true
+	}
+	return ast.InvalidRef, false
+}
+
+func (p *parser) removeCommonJSExportPart(part *js_ast.Part) {
+	for ref, use := range part.SymbolUses {
+		p.symbols[ref.InnerIndex].UseCountEstimate -= use.CountEstimate
+	}
+	part.Stmts = nil
+	part.SymbolUses = make(map[ast.Ref]js_ast.SymbolUse)
+	part.SymbolCallUses = nil
+	part.CanBeRemovedIfUnused = true
+}
+
+// This undoes "splitCommonJSObjectExport" by merging each group of parts
+// back into a single "module.exports = { ... }" statement
+func (p *parser) mergeCommonJSObjectExports(parts []js_ast.Part) []js_ast.Part {
+	for _, group := range p.commonJSObjectExports {
+		if group.partsEnd-group.partsStart < 2 {
+			continue
+		}
+		merged := &parts[group.partsStart]
+		var stmts []js_ast.Stmt
+		var assign *js_ast.EBinary
+		var obj *js_ast.EObject
+
+		for partIndex := group.partsStart; partIndex < group.partsEnd; partIndex++ {
+			part := &parts[partIndex]
+
+			// Keep any other statements such as temporary variable declarations
+			for _, stmt := range part.Stmts {
+				if s, ok := stmt.Data.(*js_ast.SExpr); ok {
+					if e, ok := s.Value.Data.(*js_ast.EBinary); ok && e.Op == js_ast.BinOpAssign {
+						if right, ok := e.Right.Data.(*js_ast.EObject); ok {
+							if assign == nil {
+								assign = e
+								obj = right
+								stmts = append(stmts, stmt)
+							} else {
+								obj.Properties = append(obj.Properties, right.Properties...)
+
+								// Each split part had its own reference to "module"
+								if dot, ok := e.Left.Data.(*js_ast.EDot); ok {
+									if id, ok := dot.Target.Data.(*js_ast.EIdentifier); ok {
+										p.symbols[id.Ref.InnerIndex].UseCountEstimate--
+										use := part.SymbolUses[id.Ref]
+										use.CountEstimate--
+										part.SymbolUses[id.Ref] = use
+									}
+								}
+							}
+							continue
+						}
+					}
+				}
+				stmts = append(stmts, stmt)
+			}
+
+			if partIndex == group.partsStart {
+				continue
+			}
+			for ref, use := range part.SymbolUses {
+				if use.CountEstimate == 0 {
+					continue
+				}
+				mergedUse := merged.SymbolUses[ref]
+				mergedUse.CountEstimate += use.CountEstimate
+				merged.SymbolUses[ref] = mergedUse
+			}
+			for ref, use := range part.SymbolCallUses {
+				if merged.SymbolCallUses == nil {
+					merged.SymbolCallUses = make(map[ast.Ref]js_ast.SymbolCallUse)
+				}
+				mergedUse := merged.SymbolCallUses[ref]
+				mergedUse.CallCountEstimate += use.CallCountEstimate
+				mergedUse.SingleArgNonSpreadCallCountEstimate += use.SingleArgNonSpreadCallCountEstimate
+				merged.SymbolCallUses[ref] = mergedUse
+			}
+			for ref, uses := range part.ImportSymbolPropertyUses {
+				if merged.ImportSymbolPropertyUses == nil {
+					merged.ImportSymbolPropertyUses = make(map[ast.Ref]map[string]js_ast.SymbolUse)
+				}
+				mergedUses := merged.ImportSymbolPropertyUses[ref]
+				if mergedUses == nil {
+					mergedUses = make(map[string]js_ast.SymbolUse)
+					merged.ImportSymbolPropertyUses[ref] = mergedUses
+				}
+				for name, use := range uses {
+					mergedUse := mergedUses[name]
+					mergedUse.CountEstimate += use.CountEstimate
+					mergedUses[name] = mergedUse
+				}
+			}
+			merged.ImportRecordIndices = append(merged.ImportRecordIndices, part.ImportRecordIndices...)
+			merged.DeclaredSymbols = append(merged.DeclaredSymbols, part.DeclaredSymbols...)
+			merged.Scopes = append(merged.Scopes, part.Scopes...)
+			merged.CanBeRemovedIfUnused = merged.CanBeRemovedIfUnused && part.CanBeRemovedIfUnused
+			*part = js_ast.Part{SymbolUses: make(map[ast.Ref]js_ast.SymbolUse)}
+		}
+
+		merged.Stmts = stmts
+	}
+	return parts
+}
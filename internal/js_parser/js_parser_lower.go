@@ -78,9 +78,6 @@ func (p *parser) markSyntaxFeature(feature compat.JSFeature, r logger.Range) (di
 	case compat.NestedRestBinding:
 		name = "non-identifier array rest patterns"
 
-	case compat.Decorators:
-		name = "JavaScript decorators"
-
 	case compat.ImportAttributes:
 		p.log.AddError(&p.tracker, r, fmt.Sprintf(
 			"Using an arbitrary value as the second argument to \"import()\" is not possible in %s", where))
@@ -229,22 +226,6 @@ func (p *parser) whyStrictMode(scope *js_ast.Scope) (where string, notes []logge
 	return
 }
 
-func (p *parser) markAsyncFn(asyncRange logger.Range, isGenerator bool) (didGenerateError bool) {
-	// Lowered async functions are implemented in terms of generators. So if
-	// generators aren't supported, async functions aren't supported either.
-	// But if generators are supported, then async functions are unconditionally
-	// supported because we can use generators to implement them.
-	if !p.options.unsupportedJSFeatures.Has(compat.Generator) {
-		return false
-	}
-
-	feature := compat.AsyncAwait
-	if isGenerator {
-		feature = compat.AsyncGenerator
-	}
-	return p.markSyntaxFeature(feature, asyncRange)
-}
-
 func (p *parser) captureThis() ast.Ref {
 	if p.fnOnlyDataVisit.thisCaptureRef == nil {
 		ref := p.newSymbol(ast.SymbolHoisted, "_this")
@@ -277,8 +258,9 @@ func (p *parser) lowerFunction(
 	hasRestArg *bool,
 	isArrow bool,
 ) {
-	// Lower object rest binding patterns in function arguments
-	if p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
+	// Lower object rest binding patterns in function arguments. This isn't
+	// needed if all binding patterns are being lowered, which happens later.
+	if p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) && !p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
 		var prefixStmts []js_ast.Stmt
 
 		// Lower each argument individually instead of lowering all arguments
@@ -318,7 +300,7 @@ func (p *parser) lowerFunction(
 				init := js_ast.Expr{Loc: arg.Binding.Loc, Data: &js_ast.EIdentifier{Ref: ref}}
 				p.recordUsage(ref)
 
-				if decls, ok := p.lowerObjectRestToDecls(target, init, nil); ok {
+				if decls, ok := p.lowerDestructuringToDecls(target, init, nil); ok {
 					// Replace the binding but leave the default value intact
 					(*args)[i].Binding.Data = &js_ast.BIdentifier{Ref: ref}
 
@@ -474,6 +456,16 @@ func (p *parser) lowerFunction(
 			name = "__async"
 		}
 		*isAsync = false
+
+		// The generator function may also need to be lowered
+		if p.options.unsupportedJSFeatures.Has(compat.Generator) {
+			p.lowerGeneratorBody(bodyLoc, &fn.Body.Block)
+			fn.IsGenerator = false
+		}
+
+		// The arguments may have been moved to the generator function
+		p.lowerFunctionArgs(&fn.Args, &fn.HasRestArg, &fn.Body.Block, false /* isArrow */)
+
 		callAsync := p.callRuntime(bodyLoc, name, []js_ast.Expr{
 			thisValue,
 			forwardedArgs,
@@ -481,12 +473,25 @@ func (p *parser) lowerFunction(
 		})
 		bodyBlock.Stmts = []js_ast.Stmt{{Loc: bodyLoc, Data: &js_ast.SReturn{ValueOrNil: callAsync}}}
 	}
+
+	// Lower generator functions
+	if isGenerator != nil && *isGenerator && p.options.unsupportedJSFeatures.Has(compat.Generator) {
+		// "function* foo(a, b) { stmts }" => "function foo(a, b) { return __stateMachine(this, function (_) { stmts }) }"
+		p.lowerGeneratorBody(bodyLoc, bodyBlock)
+		*isGenerator = false
+	}
+
+	// Lower default arguments, rest arguments, and binding patterns in function
+	// arguments. This is done last so that the resulting code comes before any
+	// generated state machine and refers to the correct "arguments" object.
+	p.lowerFunctionArgs(args, hasRestArg, bodyBlock, isArrow)
 }
 
 func (p *parser) lowerOptionalChain(expr js_ast.Expr, in exprIn, childOut exprOut) (js_ast.Expr, exprOut) {
 	valueWhenUndefined := js_ast.Expr{Loc: expr.Loc, Data: js_ast.EUndefinedShared}
 	endsWithPropertyAccess := false
 	containsPrivateName := false
+	containsCallSpread := false
 	startsWithCall := false
 	originalExpr := expr
 	chain := []js_ast.Expr{}
@@ -528,6 +533,13 @@ flatten:
 
 		case *js_ast.ECall:
 			expr = e.Target
+
+			// Spread arguments that need to be lowered can't be lowered inside an
+			// optional chain either, so the entire chain must be lowered too
+			if p.shouldLowerCallSpread(e.Args) {
+				containsCallSpread = true
+			}
+
 			if e.OptionalChain == js_ast.OptionalChainStart {
 				startsWithCall = true
 				break flatten
@@ -567,7 +579,7 @@ flatten:
 	// Don't lower this if we don't need to. This check must be done here instead
 	// of earlier so we can do the dead code elimination above when the target is
 	// null or undefined.
-	if !p.options.unsupportedJSFeatures.Has(compat.OptionalChain) && !containsPrivateName {
+	if !p.options.unsupportedJSFeatures.Has(compat.OptionalChain) && !containsPrivateName && !containsCallSpread {
 		return originalExpr, exprOut{}
 	}
 
@@ -705,7 +717,7 @@ flatten:
 			// a property access, invoke the function using ".call(this, ...args)" to
 			// explicitly provide the value for "this".
 			if i == len(chain)-1 && thisArg.Data != nil {
-				result = js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+				result = p.maybeLowerCallSpread(loc, &js_ast.ECall{
 					Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
 						Target:  result,
 						Name:    "call",
@@ -715,7 +727,7 @@ flatten:
 					CanBeUnwrappedIfUnused: e.CanBeUnwrappedIfUnused,
 					IsMultiLine:            e.IsMultiLine,
 					Kind:                   js_ast.TargetWasOriginallyPropertyAccess,
-				}}
+				})
 				break
 			}
 
@@ -724,7 +736,7 @@ flatten:
 			// the property access target that was stashed away earlier as the value
 			// for "this" for the call. Example for this case: "foo.#bar?.()"
 			if privateThisFunc != nil {
-				result = privateThisWrapFunc(js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+				result = privateThisWrapFunc(p.maybeLowerCallSpread(loc, &js_ast.ECall{
 					Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
 						Target:  result,
 						Name:    "call",
@@ -734,18 +746,18 @@ flatten:
 					CanBeUnwrappedIfUnused: e.CanBeUnwrappedIfUnused,
 					IsMultiLine:            e.IsMultiLine,
 					Kind:                   js_ast.TargetWasOriginallyPropertyAccess,
-				}})
+				}))
 				privateThisFunc = nil
 				break
 			}
 
-			result = js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+			result = p.maybeLowerCallSpread(loc, &js_ast.ECall{
 				Target:                 result,
 				Args:                   e.Args,
 				CanBeUnwrappedIfUnused: e.CanBeUnwrappedIfUnused,
 				IsMultiLine:            e.IsMultiLine,
 				Kind:                   e.Kind,
-			}}
+			})
 
 		case *js_ast.EUnary:
 			result = js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{
@@ -792,7 +804,7 @@ flatten:
 }
 
 func (p *parser) lowerParenthesizedOptionalChain(loc logger.Loc, e *js_ast.ECall, childOut exprOut) js_ast.Expr {
-	return childOut.thisArgWrapFunc(js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+	return childOut.thisArgWrapFunc(p.maybeLowerCallSpread(loc, &js_ast.ECall{
 		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
 			Target:  e.Target,
 			Name:    "call",
@@ -801,7 +813,7 @@ func (p *parser) lowerParenthesizedOptionalChain(loc logger.Loc, e *js_ast.ECall
 		Args:        append(append(make([]js_ast.Expr, 0, len(e.Args)+1), childOut.thisArgFunc()), e.Args...),
 		IsMultiLine: e.IsMultiLine,
 		Kind:        js_ast.TargetWasOriginallyPropertyAccess,
-	}})
+	}))
 }
 
 func (p *parser) lowerAssignmentOperator(value js_ast.Expr, callback func(js_ast.Expr, js_ast.Expr) js_ast.Expr) js_ast.Expr {
@@ -991,7 +1003,7 @@ func (p *parser) lowerObjectSpread(loc logger.Loc, e *js_ast.EObject) js_ast.Exp
 	}
 
 	if !needsLowering {
-		return js_ast.Expr{Loc: loc, Data: e}
+		return p.lowerObjectExtensions(loc, e)
 	}
 
 	var result js_ast.Expr
@@ -1006,17 +1018,17 @@ func (p *parser) lowerObjectSpread(loc logger.Loc, e *js_ast.EObject) js_ast.Exp
 		if len(properties) > 0 || result.Data == nil {
 			if result.Data == nil {
 				// "{a, ...b}" => "__spreadValues({a}, b)"
-				result = js_ast.Expr{Loc: loc, Data: &js_ast.EObject{
+				result = p.lowerObjectExtensions(loc, &js_ast.EObject{
 					Properties:   properties,
 					IsSingleLine: e.IsSingleLine,
-				}}
+				})
 			} else {
 				// "{...a, b, ...c}" => "__spreadValues(__spreadProps(__spreadValues({}, a), {b}), c)"
 				result = p.callRuntime(loc, "__spreadProps",
-					[]js_ast.Expr{result, {Loc: loc, Data: &js_ast.EObject{
+					[]js_ast.Expr{result, p.lowerObjectExtensions(loc, &js_ast.EObject{
 						Properties:   properties,
 						IsSingleLine: e.IsSingleLine,
-					}}})
+					})})
 			}
 			properties = []js_ast.Property{}
 		}
@@ -1027,16 +1039,270 @@ func (p *parser) lowerObjectSpread(loc logger.Loc, e *js_ast.EObject) js_ast.Exp
 
 	if len(properties) > 0 {
 		// "{...a, b}" => "__spreadProps(__spreadValues({}, a), {b})"
-		result = p.callRuntime(loc, "__spreadProps", []js_ast.Expr{result, {Loc: loc, Data: &js_ast.EObject{
+		result = p.callRuntime(loc, "__spreadProps", []js_ast.Expr{result, p.lowerObjectExtensions(loc, &js_ast.EObject{
 			Properties:    properties,
 			IsSingleLine:  e.IsSingleLine,
 			CloseBraceLoc: e.CloseBraceLoc,
-		}}})
+		})})
+	}
+
+	return result
+}
+
+// This lowers object literal syntax that was added in ES6. Shorthand
+// properties are expanded by the printer. Methods are converted into normal
+// properties with function expressions:
+//
+//	"{ foo() {} }" => "{ foo: function() {} }"
+//
+// Computed properties, and all properties after them (to preserve property
+// order), are assigned to the object one at a time after it's created:
+//
+//	"{ a: 1, [b]: 2, c: 3 }" => "(_a = { a: 1 }, __defNormalProp(_a, b, 2), __defNormalProp(_a, "c", 3), _a)"
+//	"{ get [a]() {} }" => "(_a = {}, __defProp(_a, a, { get: function() {}, enumerable: true, configurable: true }), _a)"
+//
+// Own properties named "__proto__" that weren't written as "__proto__: x"
+// are handled the same way, since printing them that way would instead set
+// the prototype of the object.
+func (p *parser) lowerObjectExtensions(loc logger.Loc, e *js_ast.EObject) js_ast.Expr {
+	if !p.options.unsupportedJSFeatures.Has(compat.ObjectExtensions) {
+		return js_ast.Expr{Loc: loc, Data: e}
+	}
+
+	firstAssignedProperty := -1
+	for i := range e.Properties {
+		property := &e.Properties[i]
+		if firstAssignedProperty == -1 && (property.Flags.Has(js_ast.PropertyIsComputed) ||
+			(property.Kind == js_ast.PropertyNormal && isProtoKey(property.Key) &&
+				(property.Flags.Has(js_ast.PropertyWasShorthand) || property.Flags.Has(js_ast.PropertyIsMethod)))) {
+			firstAssignedProperty = i
+		}
+		if property.Kind == js_ast.PropertyNormal {
+			property.Flags &= ^js_ast.PropertyIsMethod
+		}
+	}
+
+	if firstAssignedProperty == -1 {
+		return js_ast.Expr{Loc: loc, Data: e}
+	}
+
+	tempRef := p.generateTempRef(tempRefNeedsDeclare, "")
+	temp := func() js_ast.Expr {
+		p.recordUsage(tempRef)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: tempRef}}
+	}
+	result := js_ast.Assign(temp(), js_ast.Expr{Loc: loc, Data: &js_ast.EObject{
+		Properties:   e.Properties[:firstAssignedProperty],
+		IsSingleLine: e.IsSingleLine,
+	}})
+
+	for _, property := range e.Properties[firstAssignedProperty:] {
+		var value js_ast.Expr
+		key := property.Key
+
+		switch property.Kind {
+		case js_ast.PropertyGet, js_ast.PropertySet:
+			// "get [a]() {}" => "__defProp(_a, a, { get: function() {}, enumerable: true, configurable: true })"
+			kind := "get"
+			if property.Kind == js_ast.PropertySet {
+				kind = "set"
+			}
+			value = p.callRuntime(property.Loc, "__defProp", []js_ast.Expr{temp(), key, {Loc: property.Loc, Data: &js_ast.EObject{
+				Properties: []js_ast.Property{
+					{Key: js_ast.Expr{Loc: property.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(kind)}}, ValueOrNil: property.ValueOrNil},
+					{Key: js_ast.Expr{Loc: property.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("enumerable")}}, ValueOrNil: js_ast.Expr{Loc: property.Loc, Data: &js_ast.EBoolean{Value: true}}},
+					{Key: js_ast.Expr{Loc: property.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("configurable")}}, ValueOrNil: js_ast.Expr{Loc: property.Loc, Data: &js_ast.EBoolean{Value: true}}},
+				},
+				IsSingleLine: true,
+			}}})
+
+		default:
+			if !property.Flags.Has(js_ast.PropertyIsComputed) && !property.Flags.Has(js_ast.PropertyWasShorthand) && isProtoKey(key) {
+				// "__proto__: a" => "_a.__proto__ = a"
+				value = js_ast.Assign(js_ast.Expr{Loc: property.Loc, Data: &js_ast.EDot{
+					Target:  temp(),
+					NameLoc: key.Loc,
+					Name:    "__proto__",
+				}}, property.ValueOrNil)
+			} else {
+				// "[a]: b" => "__defNormalProp(_a, a, b)"
+				value = p.callRuntime(property.Loc, "__defNormalProp", []js_ast.Expr{temp(), key, property.ValueOrNil})
+			}
+		}
+
+		result = js_ast.JoinWithComma(result, value)
+	}
+
+	return js_ast.JoinWithComma(result, temp())
+}
+
+func isProtoKey(key js_ast.Expr) bool {
+	str, ok := key.Data.(*js_ast.EString)
+	return ok && helpers.UTF16EqualsString(str.Value, "__proto__")
+}
+
+// Spread arguments must be passed as an array if either array spread or rest
+// arguments are unsupported. Historically both of these features have been
+// used to guard spread arguments.
+func (p *parser) shouldLowerCallSpread(args []js_ast.Expr) bool {
+	if !p.options.unsupportedJSFeatures.Has(compat.ArraySpread) && !p.options.unsupportedJSFeatures.Has(compat.RestArgument) {
+		return false
+	}
+	for _, arg := range args {
+		if _, ok := arg.Data.(*js_ast.ESpread); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Array spread is lowered using "concat". Spread values are converted to
+// arrays first using the "__toArray" helper, which handles both iterables and
+// array-like objects:
+//
+//	"[...a]" => "__toArray(a)"
+//	"[a, ...b, c]" => "[a].concat(__toArray(b), [c])"
+func (p *parser) lowerArraySpread(loc logger.Loc, items []js_ast.Expr, isSingleLine bool) js_ast.Expr {
+	var parts []js_ast.Expr
+	start := 0
+
+	for i, item := range items {
+		if spread, ok := item.Data.(*js_ast.ESpread); ok {
+			if start < i {
+				parts = append(parts, js_ast.Expr{Loc: items[start].Loc, Data: &js_ast.EArray{
+					Items:        items[start:i],
+					IsSingleLine: isSingleLine,
+				}})
+			}
+			parts = append(parts, p.callRuntime(item.Loc, "__toArray", []js_ast.Expr{spread.Value}))
+			start = i + 1
+		}
+	}
+
+	if start < len(items) {
+		parts = append(parts, js_ast.Expr{Loc: items[start].Loc, Data: &js_ast.EArray{
+			Items:        items[start:],
+			IsSingleLine: isSingleLine,
+		}})
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+
+	return js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+			Target:  parts[0],
+			NameLoc: loc,
+			Name:    "concat",
+		}},
+		Args: parts[1:],
+		Kind: js_ast.TargetWasOriginallyPropertyAccess,
+	}}
+}
+
+func (p *parser) maybeLowerCallSpread(loc logger.Loc, e *js_ast.ECall) js_ast.Expr {
+	if p.shouldLowerCallSpread(e.Args) {
+		return p.lowerCallSpread(loc, e)
+	}
+	return js_ast.Expr{Loc: loc, Data: e}
+}
+
+// Spread arguments in calls are lowered using "apply":
+//
+//	"f(a, ...b)" => "f.apply(void 0, [a].concat(__toArray(b)))"
+//	"a.f(...b)" => "a.f.apply(a, __toArray(b))"
+//	"a().f(...b)" => "(_a = a()).f.apply(_a, __toArray(b))"
+func (p *parser) lowerCallSpread(loc logger.Loc, e *js_ast.ECall) js_ast.Expr {
+	// Calls to the super constructor are handled when the class is lowered
+	switch t := e.Target.Data.(type) {
+	case *js_ast.ESuper:
+		return js_ast.Expr{Loc: loc, Data: e}
+	case *js_ast.EIdentifier:
+		if t.Ref == p.superCtorRef {
+			return js_ast.Expr{Loc: loc, Data: e}
+		}
+	}
+
+	target := e.Target
+	thisArg := js_ast.Expr{Loc: loc, Data: js_ast.EUndefinedShared}
+	var wrapFunc func(js_ast.Expr) js_ast.Expr
+
+	switch t := e.Target.Data.(type) {
+	case *js_ast.EDot:
+		if _, ok := t.Target.Data.(*js_ast.ESuper); ok {
+			// "super.foo(...a)" => "__superGet(Class, this, 'foo').apply(this, __toArray(a))"
+			if p.shouldLowerSuperPropertyAccess(t.Target) {
+				target = p.lowerSuperPropertyGet(e.Target.Loc, js_ast.Expr{Loc: t.NameLoc, Data: &js_ast.EString{Value: helpers.StringToUTF16(t.Name)}})
+			}
+			thisArg = p.thisForLoweredSuperProperty(loc)
+		} else {
+			var targetFunc func() js_ast.Expr
+			targetFunc, wrapFunc = p.captureValueWithPossibleSideEffects(loc, 2, t.Target, valueDefinitelyNotMutated)
+			clone := *t
+			clone.Target = targetFunc()
+			target = js_ast.Expr{Loc: e.Target.Loc, Data: &clone}
+			thisArg = targetFunc()
+		}
+
+	case *js_ast.EIndex:
+		if _, ok := t.Target.Data.(*js_ast.ESuper); ok {
+			if p.shouldLowerSuperPropertyAccess(t.Target) {
+				target = p.lowerSuperPropertyGet(e.Target.Loc, t.Index)
+			}
+			thisArg = p.thisForLoweredSuperProperty(loc)
+		} else {
+			var targetFunc func() js_ast.Expr
+			targetFunc, wrapFunc = p.captureValueWithPossibleSideEffects(loc, 2, t.Target, valueDefinitelyNotMutated)
+			clone := *t
+			clone.Target = targetFunc()
+			target = js_ast.Expr{Loc: e.Target.Loc, Data: &clone}
+			thisArg = targetFunc()
+		}
 	}
 
+	result := js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+			Target:  target,
+			NameLoc: loc,
+			Name:    "apply",
+		}},
+		Args:                   []js_ast.Expr{thisArg, p.lowerArraySpread(loc, e.Args, !e.IsMultiLine)},
+		CanBeUnwrappedIfUnused: e.CanBeUnwrappedIfUnused,
+		IsMultiLine:            e.IsMultiLine,
+		Kind:                   js_ast.TargetWasOriginallyPropertyAccess,
+	}}
+	if wrapFunc != nil {
+		result = wrapFunc(result)
+	}
 	return result
 }
 
+// Spread arguments in "new" expressions are lowered using "bind":
+//
+//	"new F(a, ...b)" => "new (F.bind.apply(F, [null, a].concat(__toArray(b))))()"
+func (p *parser) lowerNewSpread(loc logger.Loc, e *js_ast.ENew) js_ast.Expr {
+	targetFunc, wrapFunc := p.captureValueWithPossibleSideEffects(loc, 2, e.Target, valueDefinitelyNotMutated)
+	args := append([]js_ast.Expr{{Loc: loc, Data: js_ast.ENullShared}}, e.Args...)
+	return wrapFunc(js_ast.Expr{Loc: loc, Data: &js_ast.ENew{
+		Target: js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+			Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+				Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+					Target:  targetFunc(),
+					NameLoc: loc,
+					Name:    "bind",
+				}},
+				NameLoc: loc,
+				Name:    "apply",
+			}},
+			Args: []js_ast.Expr{targetFunc(), p.lowerArraySpread(loc, args, !e.IsMultiLine)},
+			Kind: js_ast.TargetWasOriginallyPropertyAccess,
+		}},
+		CloseParenLoc: e.CloseParenLoc,
+		IsMultiLine:   e.IsMultiLine,
+	}})
+}
+
 func (p *parser) maybeLowerAwait(loc logger.Loc, e *js_ast.EAwait) js_ast.Expr {
 	// "await x" turns into "yield __await(x)" when lowering async generator functions
 	if p.fnOrArrowDataVisit.isGenerator && (p.options.unsupportedJSFeatures.Has(compat.AsyncAwait) || p.options.unsupportedJSFeatures.Has(compat.AsyncGenerator)) {
@@ -1082,30 +1348,78 @@ func (p *parser) lowerForAwaitLoop(loc logger.Loc, loop *js_ast.SForOf, stmts []
 	//
 	// except that "yield" is used instead of "await" if await is unsupported.
 	// This mostly follows TypeScript's implementation of the syntax transform.
+	iter := p.callRuntime(loc, "__forAwait", []js_ast.Expr{loop.Value})
+	return append(stmts, p.lowerForOfLoopUsingIterator(loc, loop, iter, true /* isAwait */))
+}
 
-	iterRef := p.generateTempRef(tempRefNoDeclare, "iter")
-	moreRef := p.generateTempRef(tempRefNoDeclare, "more")
-	tempRef := p.generateTempRef(tempRefNoDeclare, "temp")
-	errorRef := p.generateTempRef(tempRefNoDeclare, "error")
+func (p *parser) lowerForOfLoop(loc logger.Loc, loop *js_ast.SForOf, stmts []js_ast.Stmt) []js_ast.Stmt {
+	// If the "loose" option is enabled, assume the value is an array:
+	//
+	//   for (let x of y) z()
+	//
+	// is transformed into the following code:
+	//
+	//   for (var i = 0, arr = y; i < arr.length; i++) {
+	//     let x = arr[i];
+	//     z();
+	//   }
+	//
+	if p.options.looseForOf {
+		indexRef := p.generateTempRef(tempRefNoDeclare, "i")
+		arrayRef := p.generateTempRef(tempRefNoDeclare, "arr")
+		p.recordDeclaredTempRef(indexRef)
+		p.recordDeclaredTempRef(arrayRef)
+		body, closeBraceLoc := forOfLoopBody(loc, loop, js_ast.Expr{Loc: loc, Data: &js_ast.EIndex{
+			Target: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: arrayRef}},
+			Index:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: indexRef}},
+		}})
+		return append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SFor{
+			InitOrNil: js_ast.Stmt{Loc: loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: []js_ast.Decl{
+				{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: indexRef}},
+					ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.ENumber{Value: 0}}},
+				{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: arrayRef}},
+					ValueOrNil: loop.Value},
+			}}},
+			TestOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+				Op:   js_ast.BinOpLt,
+				Left: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: indexRef}},
+				Right: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+					Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: arrayRef}},
+					NameLoc: loc,
+					Name:    "length",
+				}},
+			}},
+			UpdateOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{
+				Op:    js_ast.UnOpPostInc,
+				Value: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: indexRef}},
+			}},
+			Body: js_ast.Stmt{Loc: loop.Body.Loc, Data: &js_ast.SBlock{
+				Stmts:         body,
+				CloseBraceLoc: closeBraceLoc,
+			}},
+		}})
+	}
+
+	// Otherwise, this is the same as "lowerForAwaitLoop" above except without
+	// "await". The "__forOf" helper iterates over arrays by index and falls
+	// back to array-like objects if "Symbol.iterator" doesn't exist.
+	iter := p.callRuntime(loc, "__forOf", []js_ast.Expr{loop.Value})
+	return append(stmts, p.lowerForOfLoopUsingIterator(loc, loop, iter, false /* isAwait */))
+}
 
+// This moves the initializer of a "for-of" loop into the loop body and assigns
+// "value" to it. It returns the statements for the new loop body.
+func forOfLoopBody(loc logger.Loc, loop *js_ast.SForOf, value js_ast.Expr) ([]js_ast.Stmt, logger.Loc) {
 	switch init := loop.Init.Data.(type) {
 	case *js_ast.SLocal:
 		if len(init.Decls) == 1 {
-			init.Decls[0].ValueOrNil = js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
-				Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: tempRef}},
-				NameLoc: loc,
-				Name:    "value",
-			}}
+			init.Decls[0].ValueOrNil = value
 		}
 	case *js_ast.SExpr:
 		init.Value.Data = &js_ast.EBinary{
-			Op:   js_ast.BinOpAssign,
-			Left: init.Value,
-			Right: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
-				Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: tempRef}},
-				NameLoc: loc,
-				Name:    "value",
-			}},
+			Op:    js_ast.BinOpAssign,
+			Left:  init.Value,
+			Right: value,
 		}
 	}
 
@@ -1120,6 +1434,28 @@ func (p *parser) lowerForAwaitLoop(loc logger.Loc, loop *js_ast.SForOf, stmts []
 		body = append(body, loop.Body)
 	}
 
+	return body, closeBraceLoc
+}
+
+// This is the same as "lowerForAwaitLoop" above except that "await" is only
+// used if "isAwait" is true. The "iter" expression must evaluate to the
+// iterator object.
+func (p *parser) lowerForOfLoopUsingIterator(loc logger.Loc, loop *js_ast.SForOf, iter js_ast.Expr, isAwait bool) js_ast.Stmt {
+	iterRef := p.generateTempRef(tempRefNoDeclare, "iter")
+	moreRef := p.generateTempRef(tempRefNoDeclare, "more")
+	tempRef := p.generateTempRef(tempRefNoDeclare, "temp")
+	errorRef := p.generateTempRef(tempRefNoDeclare, "error")
+	p.recordDeclaredTempRef(iterRef)
+	p.recordDeclaredTempRef(moreRef)
+	p.recordDeclaredTempRef(tempRef)
+	p.recordDeclaredTempRef(errorRef)
+
+	body, closeBraceLoc := forOfLoopBody(loc, loop, js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+		Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: tempRef}},
+		NameLoc: loc,
+		Name:    "value",
+	}})
+
 	awaitIterNext := js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
 		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
 			Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: iterRef}},
@@ -1139,16 +1475,18 @@ func (p *parser) lowerForAwaitLoop(loc logger.Loc, loop *js_ast.SForOf, stmts []
 	}}
 
 	// "await" expressions turn into "yield" expressions when lowering
-	awaitIterNext = p.maybeLowerAwait(awaitIterNext.Loc, &js_ast.EAwait{Value: awaitIterNext})
-	awaitTempCallIter = p.maybeLowerAwait(awaitTempCallIter.Loc, &js_ast.EAwait{Value: awaitTempCallIter})
+	if isAwait {
+		awaitIterNext = p.maybeLowerAwait(awaitIterNext.Loc, &js_ast.EAwait{Value: awaitIterNext})
+		awaitTempCallIter = p.maybeLowerAwait(awaitTempCallIter.Loc, &js_ast.EAwait{Value: awaitTempCallIter})
+	}
 
-	return append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.STry{
+	return js_ast.Stmt{Loc: loc, Data: &js_ast.STry{
 		BlockLoc: loc,
 		Block: js_ast.SBlock{
 			Stmts: []js_ast.Stmt{{Loc: loc, Data: &js_ast.SFor{
 				InitOrNil: js_ast.Stmt{Loc: loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: []js_ast.Decl{
 					{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: iterRef}},
-						ValueOrNil: p.callRuntime(loc, "__forAwait", []js_ast.Expr{loop.Value})},
+						ValueOrNil: iter},
 					{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: moreRef}}},
 					{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: tempRef}}},
 					{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: errorRef}}},
@@ -1236,7 +1574,7 @@ func (p *parser) lowerForAwaitLoop(loc logger.Loc, loop *js_ast.SForOf, stmts []
 				}}},
 			},
 		},
-	}})
+	}}
 }
 
 func bindingHasObjectRest(binding js_ast.Binding) bool {
@@ -1279,96 +1617,6 @@ func exprHasObjectRest(expr js_ast.Expr) bool {
 	return false
 }
 
-func (p *parser) lowerObjectRestInDecls(decls []js_ast.Decl) []js_ast.Decl {
-	if !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
-		return decls
-	}
-
-	// Don't do any allocations if there are no object rest patterns. We want as
-	// little overhead as possible in the common case.
-	for i, decl := range decls {
-		if decl.ValueOrNil.Data != nil && bindingHasObjectRest(decl.Binding) {
-			clone := append([]js_ast.Decl{}, decls[:i]...)
-			for _, decl := range decls[i:] {
-				if decl.ValueOrNil.Data != nil {
-					target := js_ast.ConvertBindingToExpr(decl.Binding, nil)
-					if result, ok := p.lowerObjectRestToDecls(target, decl.ValueOrNil, clone); ok {
-						clone = result
-						continue
-					}
-				}
-				clone = append(clone, decl)
-			}
-
-			return clone
-		}
-	}
-
-	return decls
-}
-
-func (p *parser) lowerObjectRestInForLoopInit(init js_ast.Stmt, body *js_ast.Stmt) {
-	if !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
-		return
-	}
-
-	var bodyPrefixStmt js_ast.Stmt
-
-	switch s := init.Data.(type) {
-	case *js_ast.SExpr:
-		// "for ({...x} in y) {}"
-		// "for ({...x} of y) {}"
-		if exprHasObjectRest(s.Value) {
-			ref := p.generateTempRef(tempRefNeedsDeclare, "")
-			if expr, ok := p.lowerAssign(s.Value, js_ast.Expr{Loc: init.Loc, Data: &js_ast.EIdentifier{Ref: ref}}, objRestReturnValueIsUnused); ok {
-				p.recordUsage(ref)
-				s.Value.Data = &js_ast.EIdentifier{Ref: ref}
-				bodyPrefixStmt = js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}}
-			}
-		}
-
-	case *js_ast.SLocal:
-		// "for (let {...x} in y) {}"
-		// "for (let {...x} of y) {}"
-		if len(s.Decls) == 1 && bindingHasObjectRest(s.Decls[0].Binding) {
-			ref := p.generateTempRef(tempRefNoDeclare, "")
-			decl := js_ast.Decl{Binding: s.Decls[0].Binding, ValueOrNil: js_ast.Expr{Loc: init.Loc, Data: &js_ast.EIdentifier{Ref: ref}}}
-			p.recordUsage(ref)
-			decls := p.lowerObjectRestInDecls([]js_ast.Decl{decl})
-			s.Decls[0].Binding.Data = &js_ast.BIdentifier{Ref: ref}
-			bodyPrefixStmt = js_ast.Stmt{Loc: init.Loc, Data: &js_ast.SLocal{Kind: s.Kind, Decls: decls}}
-		}
-	}
-
-	if bodyPrefixStmt.Data != nil {
-		if block, ok := body.Data.(*js_ast.SBlock); ok {
-			// If there's already a block, insert at the front
-			stmts := make([]js_ast.Stmt, 0, 1+len(block.Stmts))
-			block.Stmts = append(append(stmts, bodyPrefixStmt), block.Stmts...)
-		} else {
-			// Otherwise, make a block and insert at the front
-			body.Data = &js_ast.SBlock{Stmts: []js_ast.Stmt{bodyPrefixStmt, *body}}
-		}
-	}
-}
-
-func (p *parser) lowerObjectRestInCatchBinding(catch *js_ast.Catch) {
-	if !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
-		return
-	}
-
-	if catch.BindingOrNil.Data != nil && bindingHasObjectRest(catch.BindingOrNil) {
-		ref := p.generateTempRef(tempRefNoDeclare, "")
-		decl := js_ast.Decl{Binding: catch.BindingOrNil, ValueOrNil: js_ast.Expr{Loc: catch.BindingOrNil.Loc, Data: &js_ast.EIdentifier{Ref: ref}}}
-		p.recordUsage(ref)
-		decls := p.lowerObjectRestInDecls([]js_ast.Decl{decl})
-		catch.BindingOrNil.Data = &js_ast.BIdentifier{Ref: ref}
-		stmts := make([]js_ast.Stmt, 0, 1+len(catch.Block.Stmts))
-		stmts = append(stmts, js_ast.Stmt{Loc: catch.BindingOrNil.Loc, Data: &js_ast.SLocal{Kind: js_ast.LocalLet, Decls: decls}})
-		catch.Block.Stmts = append(stmts, catch.Block.Stmts...)
-	}
-}
-
 type objRestMode uint8
 
 const (
@@ -1384,7 +1632,12 @@ func (p *parser) lowerAssign(rootExpr js_ast.Expr, rootInit js_ast.Expr, mode ob
 		expr = js_ast.JoinWithComma(expr, js_ast.Assign(left, right))
 	}
 
-	if initWrapFunc, ok := p.lowerObjectRestHelper(rootExpr, rootInit, assign, tempRefNeedsDeclare, mode); ok {
+	lower := p.lowerObjectRestHelper
+	if p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
+		lower = p.lowerDestructuringHelper
+	}
+
+	if initWrapFunc, ok := lower(rootExpr, rootInit, assign, tempRefNeedsDeclare, mode); ok {
 		if initWrapFunc != nil {
 			expr = initWrapFunc(expr)
 		}
@@ -1398,22 +1651,6 @@ func (p *parser) lowerAssign(rootExpr js_ast.Expr, rootInit js_ast.Expr, mode ob
 	return js_ast.Expr{}, false
 }
 
-func (p *parser) lowerObjectRestToDecls(rootExpr js_ast.Expr, rootInit js_ast.Expr, decls []js_ast.Decl) ([]js_ast.Decl, bool) {
-	assign := func(left js_ast.Expr, right js_ast.Expr) {
-		binding, invalidLog := p.convertExprToBinding(left, invalidLog{})
-		if len(invalidLog.invalidTokens) > 0 {
-			panic("Internal error")
-		}
-		decls = append(decls, js_ast.Decl{Binding: binding, ValueOrNil: right})
-	}
-
-	if _, ok := p.lowerObjectRestHelper(rootExpr, rootInit, assign, tempRefNoDeclare, objRestReturnValueIsUnused); ok {
-		return decls, true
-	}
-
-	return nil, false
-}
-
 func (p *parser) lowerObjectRestHelper(
 	rootExpr js_ast.Expr,
 	rootInit js_ast.Expr,
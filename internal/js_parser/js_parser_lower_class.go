@@ -6,6 +6,7 @@ import (
 	"github.com/evanw/esbuild/internal/config"
 	"github.com/evanw/esbuild/internal/helpers"
 	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_lexer"
 	"github.com/evanw/esbuild/internal/logger"
 )
 
@@ -254,11 +255,26 @@ func (p *parser) shouldLowerSuperPropertyAccess(expr js_ast.Expr) bool {
 	return false
 }
 
+// Returns the value of "this" to use for a lowered "super" property access.
+// This handles "this" in derived class constructors that are lowered to ES5
+// and in arrow functions that are lowered to ES5.
+func (p *parser) thisForLoweredSuperProperty(loc logger.Loc) js_ast.Expr {
+	if ref := p.fnOnlyDataVisit.derivedClassThisRef; ref != nil {
+		p.recordUsage(*ref)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: *ref}}
+	}
+	if p.fnOrArrowDataVisit.isArrow && p.options.unsupportedJSFeatures.Has(compat.Arrow) && p.fnOnlyDataVisit.isThisNested {
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: p.captureThis()}}
+	}
+	p.fnOnlyDataVisit.thisUseCount++
+	return js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+}
+
 func (p *parser) callSuperPropertyWrapper(loc logger.Loc, key js_ast.Expr) js_ast.Expr {
 	ref := *p.fnOnlyDataVisit.innerClassNameRef
 	p.recordUsage(ref)
 	class := js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
-	this := js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+	this := p.thisForLoweredSuperProperty(loc)
 
 	// Handle "this" in lowered static class field initializers
 	if p.fnOnlyDataVisit.shouldReplaceThisWithInnerClassNameRef {
@@ -283,7 +299,7 @@ func (p *parser) lowerSuperPropertyGet(loc logger.Loc, key js_ast.Expr) js_ast.E
 	ref := *p.fnOnlyDataVisit.innerClassNameRef
 	p.recordUsage(ref)
 	class := js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
-	this := js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+	this := p.thisForLoweredSuperProperty(loc)
 
 	// Handle "this" in lowered static class field initializers
 	if p.fnOnlyDataVisit.shouldReplaceThisWithInnerClassNameRef {
@@ -310,7 +326,7 @@ func (p *parser) lowerSuperPropertySet(loc logger.Loc, key js_ast.Expr, value js
 	ref := *p.fnOnlyDataVisit.innerClassNameRef
 	p.recordUsage(ref)
 	class := js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
-	this := js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+	this := p.thisForLoweredSuperProperty(loc)
 
 	// Handle "this" in lowered static class field initializers
 	if p.fnOnlyDataVisit.shouldReplaceThisWithInnerClassNameRef {
@@ -372,17 +388,26 @@ func (p *parser) maybeLowerSuperPropertyGetInsideCall(call *js_ast.ECall) {
 		NameLoc: key.Loc,
 		Name:    "call",
 	}
-	thisExpr := js_ast.Expr{Loc: call.Target.Loc, Data: js_ast.EThisShared}
+	thisExpr := p.thisForLoweredSuperProperty(call.Target.Loc)
 	call.Args = append([]js_ast.Expr{thisExpr}, call.Args...)
 }
 
 type classLoweringInfo struct {
-	lowerAllInstanceFields bool
-	lowerAllStaticFields   bool
-	shimSuperCtorCalls     bool
+	lowerAllInstanceFields  bool
+	lowerAllStaticFields    bool
+	shimSuperCtorCalls      bool
+	lowerStandardDecorators bool
 }
 
 func (p *parser) computeClassLoweringInfo(class *js_ast.Class) (result classLoweringInfo) {
+	// Classes that are lowered to ES5 constructor functions can't have any
+	// fields, so all fields must be moved out of the class body. This also
+	// means "super()" calls in derived class constructors will be shimmed.
+	if p.options.unsupportedJSFeatures.Has(compat.Class) {
+		result.lowerAllInstanceFields = true
+		result.lowerAllStaticFields = true
+	}
+
 	// Name keeping for classes is implemented with a static block. So we need to
 	// lower all static fields if static blocks are unsupported so that the name
 	// keeping comes first before other static initializers.
@@ -409,6 +434,19 @@ func (p *parser) computeClassLoweringInfo(class *js_ast.Class) (result classLowe
 		result.lowerAllStaticFields = true
 	}
 
+	// JavaScript decorators are lowered by applying them after the class has
+	// been created. Field decorators need to wrap the field initializer and
+	// method decorators can add initializers that must run before any fields
+	// are initialized, so all instance fields must be moved into the
+	// constructor. Static fields must be initialized after the class decorators
+	// have been applied, so all static fields must be moved after the class.
+	if p.options.unsupportedJSFeatures.Has(compat.Decorators) &&
+		(!p.options.ts.Parse || p.options.ts.Config.ExperimentalDecorators != config.True) && classHasDecorators(class) {
+		result.lowerStandardDecorators = true
+		result.lowerAllInstanceFields = true
+		result.lowerAllStaticFields = true
+	}
+
 	// Conservatively lower fields of a given type (instance or static) when any
 	// member of that type needs to be lowered. This must be done to preserve
 	// evaluation order. For example:
@@ -656,6 +694,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	}
 
 	var ctor *js_ast.EFunction
+	var ctorDecoratorMetadata *js_ast.TSDecoratorMetadata
 	var parameterFields []js_ast.Stmt
 	var instanceMembers []js_ast.Stmt
 	var instancePrivateMethods []js_ast.Stmt
@@ -668,11 +707,34 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	var instanceDecorators []js_ast.Expr
 	var staticDecorators []js_ast.Expr
 
+	// This holds the state for JavaScript decorators (see "__decoratorStart")
+	decoratorStateRef := ast.InvalidRef
+	decoratorState := func(loc logger.Loc) js_ast.Expr {
+		p.recordUsage(decoratorStateRef)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: decoratorStateRef}}
+	}
+
 	// These are only for class expressions that need to be captured
 	var nameFunc func() js_ast.Expr
 	var wrapFunc func(js_ast.Expr) js_ast.Expr
 	didCaptureClassExpr := false
 
+	// If classes aren't supported, the class is converted into a function at
+	// the very end once everything else has been done. Until then, this is a
+	// placeholder for the function in the generated code.
+	lowerToES5 := p.options.unsupportedJSFeatures.Has(compat.Class)
+	var es5Class *js_ast.ECall
+	es5SuperRef := ast.InvalidRef
+
+	// Derived classes that are lowered to ES5 replace "this" in the constructor
+	thisForCtor := func(loc logger.Loc) js_ast.Expr {
+		if result.derivedClassThisRef != ast.InvalidRef {
+			p.recordUsage(result.derivedClassThisRef)
+			return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: result.derivedClassThisRef}}
+		}
+		return js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+	}
+
 	// Class statements can be missing a name if they are in an
 	// "export default" statement:
 	//
@@ -685,9 +747,16 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			// If this is a class expression, capture and store it. We have to
 			// do this even if it has a name since the name isn't exposed
 			// outside the class body.
-			classExpr := &js_ast.EClass{Class: *class}
-			class = &classExpr.Class
-			nameFunc, wrapFunc = p.captureValueWithPossibleSideEffects(classLoc, 2, js_ast.Expr{Loc: classLoc, Data: classExpr}, valueDefinitelyNotMutated)
+			var value js_ast.E
+			if lowerToES5 {
+				es5Class = &js_ast.ECall{}
+				value = es5Class
+			} else {
+				classExpr := &js_ast.EClass{Class: *class}
+				class = &classExpr.Class
+				value = classExpr
+			}
+			nameFunc, wrapFunc = p.captureValueWithPossibleSideEffects(classLoc, 2, js_ast.Expr{Loc: classLoc, Data: value}, valueDefinitelyNotMutated)
 			expr = nameFunc()
 			didCaptureClassExpr = true
 			name := nameFunc()
@@ -743,7 +812,11 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	//
 	// If this returns true, the return property should be added to the class
 	// body. Otherwise the property should be omitted from the class body.
-	lowerField := func(prop js_ast.Property, private *js_ast.EPrivateIdentifier, shouldOmitFieldInitializer bool, staticFieldToBlockAssign bool) (js_ast.Property, bool) {
+	//
+	// If the field has JavaScript decorators, "decoratorSlot" is the index of
+	// its initializers in the decorator state array (and its extra initializers
+	// come right after). Otherwise it's zero.
+	lowerField := func(prop js_ast.Property, private *js_ast.EPrivateIdentifier, shouldOmitFieldInitializer bool, staticFieldToBlockAssign bool, decoratorSlot int) (js_ast.Property, bool) {
 		mustLowerPrivate := private != nil && p.privateSymbolNeedsToBeLowered(private)
 
 		// The TypeScript compiler doesn't follow the JavaScript spec for
@@ -754,7 +827,9 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 
 			// Determine where to store the field
 			var target js_ast.Expr
-			if prop.Flags.Has(js_ast.PropertyIsStatic) && !staticFieldToBlockAssign {
+			if !prop.Flags.Has(js_ast.PropertyIsStatic) {
+				target = thisForCtor(loc)
+			} else if !staticFieldToBlockAssign {
 				target = nameFunc()
 			} else {
 				target = js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
@@ -768,6 +843,29 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 				init = js_ast.Expr{Loc: loc, Data: js_ast.EUndefinedShared}
 			}
 
+			// Pass the initial value through the initializers returned by the
+			// decorators, and then run the extra initializers that the decorators
+			// added after the field has been defined
+			var extraInitializers js_ast.Expr
+			if decoratorSlot != 0 {
+				self := func() js_ast.Expr {
+					if prop.Flags.Has(js_ast.PropertyIsStatic) {
+						return nameFunc()
+					}
+					return thisForCtor(loc)
+				}
+				args := []js_ast.Expr{decoratorState(loc), {Loc: loc, Data: &js_ast.ENumber{Value: float64(decoratorSlot << 1)}}, self()}
+				if prop.InitializerOrNil.Data != nil {
+					args = append(args, init)
+				}
+				init = p.callRuntime(loc, "__runInitializers", args)
+				extraInitializers = p.callRuntime(loc, "__runInitializers", []js_ast.Expr{
+					decoratorState(loc),
+					{Loc: loc, Data: &js_ast.ENumber{Value: float64(((decoratorSlot + 1) << 1) | 1)}},
+					self(),
+				})
+			}
+
 			// Generate the assignment target
 			var memberExpr js_ast.Expr
 			if mustLowerPrivate {
@@ -820,6 +918,9 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 
 				memberExpr = js_ast.Assign(target, init)
 			}
+			if extraInitializers.Data != nil {
+				memberExpr = js_ast.JoinWithComma(memberExpr, extraInitializers)
+			}
 
 			if prop.Flags.Has(js_ast.PropertyIsStatic) {
 				// Move this property to an assignment after the class ends
@@ -883,7 +984,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 				if prop.Flags.Has(js_ast.PropertyIsStatic) {
 					target = nameFunc()
 				} else {
-					target = js_ast.Expr{Loc: loc, Data: js_ast.EThisShared}
+					target = thisForCtor(loc)
 				}
 
 				// Add every newly-constructed instance into this map
@@ -929,6 +1030,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			if fn, ok := prop.ValueOrNil.Data.(*js_ast.EFunction); ok {
 				// Remember where the constructor is for later
 				ctor = fn
+				ctorDecoratorMetadata = prop.TSDecoratorMetadata
 
 				// Initialize TypeScript constructor parameter fields
 				if p.options.ts.Parse {
@@ -937,7 +1039,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 							if id, ok := arg.Binding.Data.(*js_ast.BIdentifier); ok {
 								parameterFields = append(parameterFields, js_ast.AssignStmt(
 									js_ast.Expr{Loc: arg.Binding.Loc, Data: p.dotOrMangledPropVisit(
-										js_ast.Expr{Loc: arg.Binding.Loc, Data: js_ast.EThisShared},
+										thisForCtor(arg.Binding.Loc),
 										p.symbols[id.Ref.InnerIndex].OriginalName,
 										arg.Binding.Loc,
 									)},
@@ -957,7 +1059,54 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	properties := make([]js_ast.Property, 0, len(class.Properties))
 	autoAccessorCount := 0
 
-	for _, prop := range class.Properties {
+	// JavaScript decorators are evaluated in order with the computed keys, but
+	// they can be evaluated before the class as long as no computed key or
+	// superclass expression with side effects comes first. Decorated elements
+	// are decorated in the order in which the groups below are listed.
+	var decoratorsBeforeClass js_ast.Expr
+	var classNameForDecorators string
+	classDecoratorsRef := ast.InvalidRef
+	var decorations [decoratorGroupCount][]js_ast.Expr
+	var decoratorSlots []int
+	hasStaticExtraInitializers := false
+	hasInstanceExtraInitializers := false
+	if classLoweringInfo.lowerStandardDecorators {
+		decoratorStateRef = p.generateTempRef(tempRefNeedsDeclareMayBeCapturedInsideLoop, "_init")
+		if len(class.Decorators) > 0 {
+			// Class decorators see the class name, which is "default" for an
+			// anonymous class in an "export default" statement
+			if result.decoratedClassRef != ast.InvalidRef {
+				classNameForDecorators = p.symbols[result.decoratedClassRef.InnerIndex].OriginalName
+			} else if kind == classKindExportDefaultStmt {
+				classNameForDecorators = "default"
+			}
+			tempName := "_class_decorators"
+			if classNameForDecorators != "" {
+				tempName = "_" + classNameForDecorators + "_decorators"
+			}
+			classDecoratorsRef, decoratorsBeforeClass = p.evaluateDecorators(class.Decorators, tempName)
+			class.Decorators = nil
+		}
+
+		// Decorated fields and accessors each get a pair of slots in the decorator
+		// state array for their initializers and their extra initializers. These
+		// are allocated at run-time in the order that decorators are applied.
+		decoratorSlots = make([]int, len(class.Properties))
+		nextSlot := 4
+		for group := decoratorGroup(0); group < decoratorGroupCount; group++ {
+			for i, prop := range class.Properties {
+				if len(prop.Decorators) > 0 && prop.Kind != js_ast.PropertyDeclareOrAbstract && decoratorGroupForProperty(prop) == group {
+					if kind := decoratorKindForProperty(prop); kind == decoratorKindAccessor || kind == decoratorKindField {
+						decoratorSlots[i] = nextSlot
+						nextSlot += 2
+					}
+				}
+			}
+		}
+	}
+	canEvaluateDecoratorsBeforeClass := class.ExtendsOrNil.Data == nil || p.astHelpers.ExprCanBeRemovedIfUnused(class.ExtendsOrNil)
+
+	for propIndex, prop := range class.Properties {
 		if prop.Kind == js_ast.PropertyClassStaticBlock {
 			// Drop empty class blocks when minifying
 			if p.options.minifySyntax && len(prop.ClassStaticBlock.Block.Stmts) == 0 {
@@ -1033,6 +1182,46 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			}
 		}
 
+		var decoratorSlot int
+		if decoratorSlots != nil {
+			decoratorSlot = decoratorSlots[propIndex]
+		}
+
+		// Evaluate the JavaScript decorators for this property. This must happen
+		// before the computed key (if any) is evaluated.
+		var propDecorators []js_ast.Decorator
+		var propDecoratorsRef ast.Ref
+		if classLoweringInfo.lowerStandardDecorators && len(prop.Decorators) > 0 && prop.Kind != js_ast.PropertyDeclareOrAbstract {
+			var name string
+			switch k := prop.Key.Data.(type) {
+			case *js_ast.EString:
+				if !prop.Flags.Has(js_ast.PropertyIsComputed) {
+					name = js_ast.ForceValidIdentifier("_", helpers.UTF16ToString(k.Value)) + "_dec"
+				}
+			case *js_ast.EPrivateIdentifier:
+				name = "_" + p.symbols[k.Ref.InnerIndex].OriginalName[1:] + "_dec"
+			}
+			if name == "" {
+				name = "_dec"
+			}
+			var value js_ast.Expr
+			propDecorators = prop.Decorators
+			propDecoratorsRef, value = p.evaluateDecorators(propDecorators, name)
+			prop.Decorators = nil
+			if canEvaluateDecoratorsBeforeClass {
+				decoratorsBeforeClass = js_ast.JoinWithComma(decoratorsBeforeClass, value)
+			} else {
+				computedPropertyCache = js_ast.JoinWithComma(computedPropertyCache, value)
+			}
+		}
+		if prop.Flags.Has(js_ast.PropertyIsComputed) {
+			switch prop.Key.Data.(type) {
+			case *js_ast.EString, *js_ast.ENameOfSymbol, *js_ast.ENumber:
+			default:
+				canEvaluateDecoratorsBeforeClass = false
+			}
+		}
+
 		// The TypeScript class field transform requires removing fields without
 		// initializers. If the field is removed, then we only need the key for
 		// its side effects and we don't need a temporary reference for the key.
@@ -1041,7 +1230,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		private, _ := prop.Key.Data.(*js_ast.EPrivateIdentifier)
 		mustLowerPrivate := private != nil && p.privateSymbolNeedsToBeLowered(private)
 		shouldOmitFieldInitializer := p.options.ts.Parse && !prop.Flags.Has(js_ast.PropertyIsMethod) && prop.InitializerOrNil.Data == nil &&
-			!class.UseDefineForClassFields && !mustLowerPrivate
+			!class.UseDefineForClassFields && !mustLowerPrivate && len(propDecorators) == 0
 
 		// Class fields must be lowered if the environment doesn't support them
 		mustLowerField := false
@@ -1086,9 +1275,11 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		// Make sure the order of computed property keys doesn't change. These
 		// expressions have side effects and must be evaluated in order.
 		keyExprNoSideEffects := prop.Key
-		if prop.Flags.Has(js_ast.PropertyIsComputed) && (len(propExperimentalDecorators) > 0 || mustLowerField || staticFieldToBlockAssign || computedPropertyCache.Data != nil || rewriteAutoAccessorToGetSet) {
+		if prop.Flags.Has(js_ast.PropertyIsComputed) && (len(propExperimentalDecorators) > 0 || len(propDecorators) > 0 || mustLowerField ||
+			staticFieldToBlockAssign || computedPropertyCache.Data != nil || rewriteAutoAccessorToGetSet) {
 			needsKey := true
-			if len(propExperimentalDecorators) == 0 && !rewriteAutoAccessorToGetSet && (prop.Flags.Has(js_ast.PropertyIsMethod) || shouldOmitFieldInitializer || (!mustLowerField && !staticFieldToBlockAssign)) {
+			if len(propExperimentalDecorators) == 0 && len(propDecorators) == 0 && !rewriteAutoAccessorToGetSet &&
+				(prop.Flags.Has(js_ast.PropertyIsMethod) || shouldOmitFieldInitializer || (!mustLowerField && !staticFieldToBlockAssign)) {
 				needsKey = false
 			}
 
@@ -1144,6 +1335,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 				for i, decorator := range propExperimentalDecorators {
 					values[i] = decorator.Value
 				}
+				values = append(values, p.tsDecoratorMetadataForProperty(loc, &prop)...)
 				prop.Decorators = nil
 				decorator := p.callRuntime(loc, "__decorateClass", []js_ast.Expr{
 					{Loc: loc, Data: &js_ast.EArray{Items: values}},
@@ -1161,6 +1353,99 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			}
 		}
 
+		// Generate a call to "__decorateElement()" for this property
+		if len(propDecorators) > 0 {
+			loc := prop.Key.Loc
+			kind := decoratorKindForProperty(prop)
+			flags := int(kind)
+			if prop.Flags.Has(js_ast.PropertyIsStatic) {
+				flags |= decoratorFlagStatic
+				if kind < decoratorKindAccessor {
+					hasStaticExtraInitializers = true
+				}
+			} else if kind < decoratorKindAccessor {
+				hasInstanceExtraInitializers = true
+			}
+			if private != nil {
+				flags |= decoratorFlagPrivate
+			}
+			args := []js_ast.Expr{
+				decoratorState(loc),
+				{Loc: loc, Data: &js_ast.ENumber{Value: float64(flags)}},
+				{},
+				{Loc: loc, Data: &js_ast.EIdentifier{Ref: propDecoratorsRef}},
+			}
+			p.recordUsage(propDecoratorsRef)
+			var decoration js_ast.Expr
+
+			if private == nil {
+				// "@dec foo() {}" => "__decorateElement(_init, 1, 'foo', _foo_dec, Foo)"
+				args[2] = cloneKeyForLowerClass(keyExprNoSideEffects)
+				decoration = p.callRuntime(loc, "__decorateElement", append(args, nameFunc()))
+			} else {
+				// Private elements are passed the lowered private name instead of the
+				// class, along with the function that implements the element (if any)
+				args[2] = js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(p.symbols[private.Ref.InnerIndex].OriginalName)}}
+				args = append(args, js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: private.Ref}})
+				p.recordUsage(private.Ref)
+				switch kind {
+				case decoratorKindField:
+					// "@dec #foo" => "__decorateElement(_init, 21, '#foo', _foo_dec, _foo)"
+					decoration = p.callRuntime(loc, "__decorateElement", args)
+
+				case decoratorKindAccessor:
+					// "@dec accessor #foo" => "_a = __decorateElement(_init, 20, '#foo', _foo_dec, _foo, { get: foo_get, set: foo_set }), foo_get = _a.get, foo_set = _a.set"
+					getRef := p.privateGetters[private.Ref]
+					setRef := p.privateSetters[private.Ref]
+					descRef := p.generateTempRef(tempRefNeedsDeclare, "")
+					desc := js_ast.Expr{Loc: loc, Data: &js_ast.EObject{
+						Properties: []js_ast.Property{
+							{Key: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("get")}}, ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: getRef}}},
+							{Key: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("set")}}, ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: setRef}}},
+						},
+						IsSingleLine: true,
+					}}
+					decoration = js_ast.JoinWithComma(js_ast.JoinWithComma(
+						js_ast.Assign(
+							js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: descRef}},
+							p.callRuntime(loc, "__decorateElement", append(args, desc)),
+						),
+						js_ast.Assign(
+							js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: getRef}},
+							js_ast.Expr{Loc: loc, Data: &js_ast.EDot{Target: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: descRef}}, Name: "get", NameLoc: loc}},
+						)),
+						js_ast.Assign(
+							js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: setRef}},
+							js_ast.Expr{Loc: loc, Data: &js_ast.EDot{Target: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: descRef}}, Name: "set", NameLoc: loc}},
+						),
+					)
+					p.recordUsage(getRef)
+					p.recordUsage(getRef)
+					p.recordUsage(setRef)
+					p.recordUsage(setRef)
+					p.recordUsage(descRef)
+					p.recordUsage(descRef)
+					p.recordUsage(descRef)
+
+				default:
+					// "@dec #foo() {}" => "foo_fn = __decorateElement(_init, 17, '#foo', _foo_dec, _foo, foo_fn)"
+					fnRef := p.privateGetters[private.Ref]
+					if kind == decoratorKindSetter {
+						fnRef = p.privateSetters[private.Ref]
+					}
+					p.recordUsage(fnRef)
+					p.recordUsage(fnRef)
+					decoration = js_ast.Assign(
+						js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: fnRef}},
+						p.callRuntime(loc, "__decorateElement", append(args, js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: fnRef}})),
+					)
+				}
+			}
+
+			group := decoratorGroupForProperty(prop)
+			decorations[group] = append(decorations[group], decoration)
+		}
+
 		// Generate get/set methods for auto-accessors
 		if rewriteAutoAccessorToGetSet {
 			var storageKind ast.SymbolKind
@@ -1191,6 +1476,9 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			// Replace this accessor with other properties
 			loc := keyExprNoSideEffects.Loc
 			storagePrivate := &js_ast.EPrivateIdentifier{Ref: storageRef}
+			if classLoweringInfo.lowerStandardDecorators {
+				p.symbols[storageRef.InnerIndex].Flags |= ast.PrivateSymbolMustBeLowered
+			}
 			storageNeedsToBeLowered := p.privateSymbolNeedsToBeLowered(storagePrivate)
 			storageProp := js_ast.Property{
 				Loc:              prop.Loc,
@@ -1201,7 +1489,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			}
 			if !mustLowerField {
 				properties = append(properties, storageProp)
-			} else if prop, ok := lowerField(storageProp, storagePrivate, false, false); ok {
+			} else if prop, ok := lowerField(storageProp, storagePrivate, false, false, decoratorSlot); ok {
 				properties = append(properties, prop)
 			}
 
@@ -1284,7 +1572,7 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		// Lower fields
 		if (!prop.Flags.Has(js_ast.PropertyIsMethod) && mustLowerField) || staticFieldToBlockAssign {
 			var keep bool
-			prop, keep = lowerField(prop, private, shouldOmitFieldInitializer, staticFieldToBlockAssign)
+			prop, keep = lowerField(prop, private, shouldOmitFieldInitializer, staticFieldToBlockAssign, decoratorSlot)
 			if !keep {
 				continue
 			}
@@ -1302,6 +1590,77 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	// Finish the filtering operation
 	class.Properties = properties
 
+	// Generate the code that applies JavaScript decorators. This runs after the
+	// class body has been evaluated but before any static fields are defined.
+	var decorateExprs []js_ast.Expr
+	var classDecoration js_ast.Expr
+	if classLoweringInfo.lowerStandardDecorators {
+		// The metadata object inherits from the metadata object of the base class
+		base := js_ast.Expr{Loc: classLoc, Data: js_ast.ENullShared}
+		if extends := class.ExtendsOrNil; extends.Data != nil {
+			if id, ok := extends.Data.(*js_ast.EIdentifier); ok {
+				p.recordUsage(id.Ref)
+				base = js_ast.Expr{Loc: extends.Loc, Data: &js_ast.EIdentifier{Ref: id.Ref}}
+			} else {
+				ref := p.generateTempRef(tempRefNeedsDeclare, "")
+				class.ExtendsOrNil = js_ast.Assign(js_ast.Expr{Loc: extends.Loc, Data: &js_ast.EIdentifier{Ref: ref}}, extends)
+				base = js_ast.Expr{Loc: extends.Loc, Data: &js_ast.EIdentifier{Ref: ref}}
+				p.recordUsage(ref)
+				p.recordUsage(ref)
+			}
+		}
+		decorateExprs = append(decorateExprs, js_ast.Assign(decoratorState(classLoc), p.callRuntime(classLoc, "__decoratorStart", []js_ast.Expr{base})))
+		for _, group := range decorations {
+			decorateExprs = append(decorateExprs, group...)
+		}
+
+		// Class decorators also define the metadata, so only do that here if
+		// there are no class decorators
+		if classDecoratorsRef == ast.InvalidRef {
+			decorateExprs = append(decorateExprs, p.callRuntime(classLoc, "__decoratorMetadata", []js_ast.Expr{decoratorState(classLoc), nameFunc()}))
+		} else {
+			name := js_ast.Expr{Loc: classLoc, Data: js_ast.EUndefinedShared}
+			if classNameForDecorators != "" {
+				name.Data = &js_ast.EString{Value: helpers.StringToUTF16(classNameForDecorators)}
+			}
+			p.recordUsage(classDecoratorsRef)
+			classDecoration = p.callRuntime(classLoc, "__decorateElement", []js_ast.Expr{
+				decoratorState(classLoc),
+				{Loc: classLoc, Data: &js_ast.ENumber{Value: float64(decoratorKindClass)}},
+				name,
+				{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: classDecoratorsRef}},
+				nameFunc(),
+			})
+		}
+
+		// Extra initializers for methods run before any fields are initialized
+		if hasInstanceExtraInitializers {
+			instancePrivateMethods = append(instancePrivateMethods, js_ast.Stmt{Loc: classLoc, Data: &js_ast.SExpr{
+				Value: p.callRuntime(classLoc, "__runInitializers", []js_ast.Expr{
+					decoratorState(classLoc),
+					{Loc: classLoc, Data: &js_ast.ENumber{Value: 2<<1 | 1}},
+					thisForCtor(classLoc),
+				}),
+			}})
+		}
+		if hasStaticExtraInitializers {
+			staticMembers = append([]js_ast.Expr{p.callRuntime(classLoc, "__runInitializers", []js_ast.Expr{
+				decoratorState(classLoc),
+				{Loc: classLoc, Data: &js_ast.ENumber{Value: 1<<1 | 1}},
+				nameFunc(),
+			})}, staticMembers...)
+		}
+	}
+
+	// Class extra initializers run last, once the decorated class is complete
+	runClassExtraInitializers := func(target js_ast.Expr) js_ast.Expr {
+		return p.callRuntime(classLoc, "__runInitializers", []js_ast.Expr{
+			decoratorState(classLoc),
+			{Loc: classLoc, Data: &js_ast.ENumber{Value: 0<<1 | 1}},
+			target,
+		})
+	}
+
 	// If there are expressions with side effects left over and static blocks are
 	// supported, insert a static block at the start of the class body. This is
 	// necessary because computed static fields need to reference variables that
@@ -1340,8 +1699,10 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		computedPropertyCache = js_ast.Expr{}
 	}
 
-	// Insert instance field initializers into the constructor
-	if len(parameterFields) > 0 || len(instancePrivateMethods) > 0 || len(instanceMembers) > 0 || (ctor != nil && result.superCtorRef != ast.InvalidRef) {
+	// Insert instance field initializers into the constructor. Derived classes
+	// that are lowered to ES5 always need a constructor to call the base class.
+	if len(parameterFields) > 0 || len(instancePrivateMethods) > 0 || len(instanceMembers) > 0 ||
+		(ctor != nil && result.superCtorRef != ast.InvalidRef) || result.derivedClassThisRef != ast.InvalidRef {
 		// Create a constructor if one doesn't already exist
 		if ctor == nil {
 			ctor = &js_ast.EFunction{Fn: js_ast.Fn{Body: js_ast.FnBody{Loc: classLoc}}}
@@ -1372,11 +1733,28 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 
 		// Make sure the instance field initializers come after "super()" since
 		// they need "this" to ba available
-		generatedStmts := make([]js_ast.Stmt, 0, len(parameterFields)+len(instancePrivateMethods)+len(instanceMembers))
+		generatedStmts := make([]js_ast.Stmt, 0, len(parameterFields)+len(instancePrivateMethods)+len(instanceMembers)+1)
+
+		// Arrow functions in instance field initializers that were lowered to ES5
+		// captured "this", so declare that capture here: "var _this = this;"
+		if ref := result.instanceFieldThisCaptureRef; ref != nil {
+			p.currentScope.Generated = append(p.currentScope.Generated, *ref)
+			p.recordDeclaredSymbol(*ref)
+			generatedStmts = append(generatedStmts, js_ast.Stmt{Loc: classLoc, Data: &js_ast.SLocal{Decls: []js_ast.Decl{{
+				Binding:    js_ast.Binding{Loc: classLoc, Data: &js_ast.BIdentifier{Ref: *ref}},
+				ValueOrNil: js_ast.Expr{Loc: classLoc, Data: js_ast.EThisShared},
+			}}}})
+		}
 		generatedStmts = append(generatedStmts, parameterFields...)
 		generatedStmts = append(generatedStmts, instancePrivateMethods...)
 		generatedStmts = append(generatedStmts, instanceMembers...)
-		p.insertStmtsAfterSuperCall(&ctor.Fn.Body, generatedStmts, result.superCtorRef)
+		if result.derivedClassThisRef != ast.InvalidRef {
+			es5SuperRef = p.newSymbol(ast.SymbolOther, "_super")
+			p.currentScope.Generated = append(p.currentScope.Generated, es5SuperRef)
+			p.insertStmtsAfterSuperCallForES5(&ctor.Fn.Body, generatedStmts, result.superCtorRef, result.derivedClassThisRef, es5SuperRef)
+		} else {
+			p.insertStmtsAfterSuperCall(&ctor.Fn.Body, generatedStmts, result.superCtorRef)
+		}
 
 		// Sort the constructor first to match the TypeScript compiler's output
 		for i := 0; i < len(class.Properties); i++ {
@@ -1398,10 +1776,19 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		// before joining "expr" with any other expressions
 		var nameToJoin js_ast.Expr
 		if didCaptureClassExpr || computedPropertyCache.Data != nil ||
-			len(privateMembers) > 0 || len(staticPrivateMethods) > 0 || len(staticMembers) > 0 {
+			len(privateMembers) > 0 || len(staticPrivateMethods) > 0 || len(staticMembers) > 0 || len(decorateExprs) > 0 {
 			nameToJoin = nameFunc()
 		}
 
+		// Convert the class into a function if classes aren't supported
+		if lowerToES5 {
+			if es5Class == nil {
+				es5Class = &js_ast.ECall{}
+				expr.Data = es5Class
+			}
+			*es5Class = p.lowerClassToES5(classLoc, class, ctor, ast.InvalidRef, es5SuperRef, result.canBeRemovedIfUnused)
+		}
+
 		// Then join "expr" with any other expressions that apply
 		if computedPropertyCache.Data != nil {
 			expr = js_ast.JoinWithComma(expr, computedPropertyCache)
@@ -1409,17 +1796,40 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		for _, value := range privateMembers {
 			expr = js_ast.JoinWithComma(expr, value)
 		}
+		for _, value := range decorateExprs {
+			expr = js_ast.JoinWithComma(expr, value)
+		}
+
+		// "@dec class Foo {}" => "(_a = class {}, ..., _Foo = __decorateElement(_init, 0, 'Foo', _Foo_decorators, _a), ..., _Foo)"
+		decoratedClassRef := ast.InvalidRef
+		if classDecoration.Data != nil {
+			decoratedClassRef = p.generateTempRef(tempRefNeedsDeclareMayBeCapturedInsideLoop, "")
+			if result.decoratedClassRef != ast.InvalidRef {
+				p.mergeSymbols(result.decoratedClassRef, decoratedClassRef)
+			}
+			p.recordUsage(decoratedClassRef)
+			expr = js_ast.JoinWithComma(expr, js_ast.Assign(js_ast.Expr{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: decoratedClassRef}}, classDecoration))
+		}
 		for _, value := range staticPrivateMethods {
 			expr = js_ast.JoinWithComma(expr, value)
 		}
 		for _, value := range staticMembers {
 			expr = js_ast.JoinWithComma(expr, value)
 		}
+		if decoratedClassRef != ast.InvalidRef {
+			p.recordUsage(decoratedClassRef)
+			p.recordUsage(decoratedClassRef)
+			expr = js_ast.JoinWithComma(expr, runClassExtraInitializers(js_ast.Expr{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: decoratedClassRef}}))
+			nameToJoin = js_ast.Expr{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: decoratedClassRef}}
+		}
 
 		// Finally join "expr" with the variable that holds the class object
 		if nameToJoin.Data != nil {
 			expr = js_ast.JoinWithComma(expr, nameToJoin)
 		}
+		if decoratorsBeforeClass.Data != nil {
+			expr = js_ast.JoinWithComma(decoratorsBeforeClass, expr)
+		}
 		if wrapFunc != nil {
 			expr = wrapFunc(expr)
 		}
@@ -1489,15 +1899,19 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			len(staticMembers) > 0 ||
 			len(instanceDecorators) > 0 ||
 			len(staticDecorators) > 0 ||
-			len(classExperimentalDecorators) > 0)
+			len(classExperimentalDecorators) > 0 ||
+			len(decorateExprs) > 0)
 
 	// Pack the class back into a statement, with potentially some extra
 	// statements afterwards
 	var stmts []js_ast.Stmt
 	var outerClassNameDecl js_ast.Stmt
+	if decoratorsBeforeClass.Data != nil {
+		stmts = append(stmts, js_ast.Stmt{Loc: decoratorsBeforeClass.Loc, Data: &js_ast.SExpr{Value: decoratorsBeforeClass}})
+	}
 	var nameForClassDecorators ast.LocRef
 	didGenerateLocalStmt := false
-	if len(classExperimentalDecorators) > 0 || hasPotentialInnerClassNameEscape || mustConvertStmtToExpr {
+	if len(classExperimentalDecorators) > 0 || hasPotentialInnerClassNameEscape || mustConvertStmtToExpr || lowerToES5 {
 		didGenerateLocalStmt = true
 
 		// Determine the name to use for decorators
@@ -1517,16 +1931,30 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 			p.recordUsage(nameForClassDecorators.Ref)
 		}
 
-		classExpr := js_ast.EClass{Class: *class}
-		class = &classExpr.Class
-		init := js_ast.Expr{Loc: classLoc, Data: &classExpr}
+		var init js_ast.Expr
+		if lowerToES5 {
+			es5Class = &js_ast.ECall{}
+			init = js_ast.Expr{Loc: classLoc, Data: es5Class}
+		} else {
+			classExpr := js_ast.EClass{Class: *class}
+			class = &classExpr.Class
+			init = js_ast.Expr{Loc: classLoc, Data: &classExpr}
+		}
 
 		// If the inner class name was referenced, then set the name of the class
 		// that we will end up printing to the inner class name. Otherwise if the
 		// inner class name was unused, we can just leave it blank.
 		if result.innerClassNameRef != ast.InvalidRef {
-			// "class Foo { x = Foo }" => "const Foo = class _Foo { x = _Foo }"
-			class.Name.Ref = result.innerClassNameRef
+			if lowerToES5 && len(classExperimentalDecorators) == 0 && !hasPotentialInnerClassNameEscape {
+				// "class Foo { x() { Foo } }" => "var Foo = function() { function Foo() {} ... }()"
+				// The constructor function shadows the outer class name inside the
+				// function expression, so the inner class name can use the same symbol
+				p.mergeSymbols(result.innerClassNameRef, nameForClassDecorators.Ref)
+				class.Name = nil
+			} else {
+				// "class Foo { x = Foo }" => "const Foo = class _Foo { x = _Foo }"
+				class.Name.Ref = result.innerClassNameRef
+			}
 		} else {
 			// "class Foo {}" => "const Foo = class {}"
 			class.Name = nil
@@ -1568,13 +1996,21 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 				}},
 			}})
 			p.recordUsage(nameForClassDecorators.Ref)
+			outerClassValue := js_ast.Expr{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: captureRef}}
 			p.recordUsage(captureRef)
+			if classDecoration.Data != nil {
+				// "@dec class Foo {}" => "const _Foo = class {}; ...; let Foo = __decorateElement(_init, 0, 'Foo', _Foo_decorators, _Foo)"
+				outerClassValue = classDecoration
+				if result.decoratedClassRef != ast.InvalidRef {
+					p.mergeSymbols(result.decoratedClassRef, nameForClassDecorators.Ref)
+				}
+			}
 			outerClassNameDecl = js_ast.Stmt{Loc: classLoc, Data: &js_ast.SLocal{
 				Kind:     p.selectLocalKind(js_ast.LocalLet),
 				IsExport: kind == classKindExportStmt,
 				Decls: []js_ast.Decl{{
 					Binding:    js_ast.Binding{Loc: nameForClassDecorators.Loc, Data: &js_ast.BIdentifier{Ref: nameForClassDecorators.Ref}},
-					ValueOrNil: js_ast.Expr{Loc: classLoc, Data: &js_ast.EIdentifier{Ref: captureRef}},
+					ValueOrNil: outerClassValue,
 				}},
 			}}
 		} else {
@@ -1628,6 +2064,14 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 	for _, expr := range privateMembers {
 		stmts = append(stmts, js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}})
 	}
+	for _, expr := range decorateExprs {
+		stmts = append(stmts, js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}})
+	}
+	if classDecoration.Data != nil {
+		// Class decorators must be applied before static fields are initialized
+		stmts = append(stmts, outerClassNameDecl)
+		outerClassNameDecl = js_ast.Stmt{}
+	}
 	for _, expr := range staticPrivateMethods {
 		stmts = append(stmts, js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}})
 	}
@@ -1649,6 +2093,10 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		for i, decorator := range classExperimentalDecorators {
 			values[i] = decorator.Value
 		}
+		if ctorDecoratorMetadata != nil {
+			values = append(values, p.tsDecoratorMetadataCall(classLoc, "design:paramtypes",
+				p.tsDecoratorMetadataParamTypes(classLoc, ctorDecoratorMetadata)))
+		}
 		class.Decorators = nil
 		stmts = append(stmts, js_ast.AssignStmt(
 			js_ast.Expr{Loc: nameForClassDecorators.Loc, Data: &js_ast.EIdentifier{Ref: nameForClassDecorators.Ref}},
@@ -1660,12 +2108,22 @@ func (p *parser) lowerClass(stmt js_ast.Stmt, expr js_ast.Expr, result visitClas
 		p.recordUsage(nameForClassDecorators.Ref)
 		p.recordUsage(nameForClassDecorators.Ref)
 	}
+	if classDecoration.Data != nil {
+		p.recordUsage(nameForClassDecorators.Ref)
+		stmts = append(stmts, js_ast.Stmt{Loc: classLoc, Data: &js_ast.SExpr{Value: runClassExtraInitializers(
+			js_ast.Expr{Loc: nameForClassDecorators.Loc, Data: &js_ast.EIdentifier{Ref: nameForClassDecorators.Ref}})}})
+	}
 	if didGenerateLocalStmt && kind == classKindExportDefaultStmt {
 		// "export default class x {}" => "class x {} export {x as default}"
 		stmts = append(stmts, js_ast.Stmt{Loc: classLoc, Data: &js_ast.SExportClause{
 			Items: []js_ast.ClauseItem{{Alias: "default", Name: defaultName}},
 		}})
 	}
+
+	// Convert the class into a function if classes aren't supported
+	if lowerToES5 {
+		*es5Class = p.lowerClassToES5(classLoc, class, ctor, nameForClassDecorators.Ref, es5SuperRef, result.canBeRemovedIfUnused)
+	}
 	return stmts, js_ast.Expr{}
 }
 
@@ -1692,13 +2150,245 @@ func cloneKeyForLowerClass(key js_ast.Expr) js_ast.Expr {
 	return key
 }
 
+// These values are passed to "__decorateElement" in the runtime
+type decoratorKind uint8
+
+const (
+	decoratorKindClass decoratorKind = iota
+	decoratorKindMethod
+	decoratorKindGetter
+	decoratorKindSetter
+	decoratorKindAccessor
+	decoratorKindField
+)
+
+const (
+	decoratorFlagStatic  = 8
+	decoratorFlagPrivate = 16
+)
+
+func decoratorKindForProperty(prop js_ast.Property) decoratorKind {
+	switch {
+	case prop.Kind == js_ast.PropertyGet:
+		return decoratorKindGetter
+	case prop.Kind == js_ast.PropertySet:
+		return decoratorKindSetter
+	case prop.Kind == js_ast.PropertyAutoAccessor:
+		return decoratorKindAccessor
+	case prop.Flags.Has(js_ast.PropertyIsMethod):
+		return decoratorKindMethod
+	default:
+		return decoratorKindField
+	}
+}
+
+// Decorators for class elements are applied in this order. Static elements
+// come before instance elements, and methods and accessors come before fields.
+type decoratorGroup uint8
+
+const (
+	decoratorGroupStaticMethod decoratorGroup = iota
+	decoratorGroupInstanceMethod
+	decoratorGroupStaticField
+	decoratorGroupInstanceField
+	decoratorGroupCount
+)
+
+func decoratorGroupForProperty(prop js_ast.Property) decoratorGroup {
+	group := decoratorGroupStaticMethod
+	if decoratorKindForProperty(prop) == decoratorKindField {
+		group = decoratorGroupStaticField
+	}
+	if !prop.Flags.Has(js_ast.PropertyIsStatic) {
+		group++
+	}
+	return group
+}
+
+func classHasDecorators(class *js_ast.Class) bool {
+	if len(class.Decorators) > 0 {
+		return true
+	}
+	for _, prop := range class.Properties {
+		if len(prop.Decorators) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// JavaScript decorators are evaluated once into an array that is stored in a
+// temporary variable. This returns that variable and the assignment to it.
+func (p *parser) evaluateDecorators(decorators []js_ast.Decorator, name string) (ast.Ref, js_ast.Expr) {
+	loc := decorators[0].AtLoc
+	values := make([]js_ast.Expr, len(decorators))
+	for i, decorator := range decorators {
+		values[i] = decorator.Value
+	}
+	ref := p.generateTempRef(tempRefNeedsDeclare, name)
+	p.recordUsage(ref)
+	return ref, js_ast.Assign(
+		js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}},
+		js_ast.Expr{Loc: loc, Data: &js_ast.EArray{Items: values, IsSingleLine: true}},
+	)
+}
+
+// TypeScript's "emitDecoratorMetadata" setting appends "__metadata()" calls
+// to the decorators for each decorated class element. They describe the type
+// annotations of that element.
+func (p *parser) tsDecoratorMetadataForProperty(loc logger.Loc, prop *js_ast.Property) []js_ast.Expr {
+	metadata := prop.TSDecoratorMetadata
+	if metadata == nil {
+		return nil
+	}
+	designType := p.tsDecoratorMetadataCall(loc, "design:type", p.valueForTSMetadataType(loc, metadata.Type))
+
+	switch {
+	case prop.Kind == js_ast.PropertyGet:
+		return []js_ast.Expr{designType}
+
+	case prop.Kind == js_ast.PropertySet:
+		return []js_ast.Expr{
+			designType,
+			p.tsDecoratorMetadataCall(loc, "design:paramtypes", p.tsDecoratorMetadataParamTypes(loc, metadata)),
+		}
+
+	case prop.Flags.Has(js_ast.PropertyIsMethod):
+		return []js_ast.Expr{
+			p.tsDecoratorMetadataCall(loc, "design:type", p.valueForTSMetadataType(loc, js_ast.TSMetadataType{Name: "Function"})),
+			p.tsDecoratorMetadataCall(loc, "design:paramtypes", p.tsDecoratorMetadataParamTypes(loc, metadata)),
+			p.tsDecoratorMetadataCall(loc, "design:returntype", p.valueForTSMetadataType(loc, metadata.Type)),
+		}
+
+	default:
+		return []js_ast.Expr{designType}
+	}
+}
+
+func (p *parser) tsDecoratorMetadataCall(loc logger.Loc, key string, value js_ast.Expr) js_ast.Expr {
+	return p.callRuntime(loc, "__metadata", []js_ast.Expr{
+		{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(key)}},
+		value,
+	})
+}
+
+func (p *parser) tsDecoratorMetadataParamTypes(loc logger.Loc, metadata *js_ast.TSDecoratorMetadata) js_ast.Expr {
+	items := make([]js_ast.Expr, len(metadata.ParamTypes))
+	for i, t := range metadata.ParamTypes {
+		items[i] = p.valueForTSMetadataType(loc, t)
+	}
+	return js_ast.Expr{Loc: loc, Data: &js_ast.EArray{Items: items, IsSingleLine: true}}
+}
+
+// Note: The returned expression has already been visited. This is called
+// during lowering in the scope that contains the class.
+func (p *parser) valueForTSMetadataType(loc logger.Loc, t js_ast.TSMetadataType) js_ast.Expr {
+	if t.EntityOrNil.Data == nil {
+		if t.Name == "" {
+			return js_ast.Expr{Loc: loc, Data: js_ast.EUndefinedShared}
+		}
+		return p.visitExpr(js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: p.storeNameInRef(js_lexer.MaybeSubstring{String: t.Name})}})
+	}
+
+	// Classes declared in this file can be referenced directly, enums declared
+	// in this file are replaced by the type of their values, and interfaces,
+	// type aliases, and type-only imports are always "Object"
+	entity := t.EntityOrNil
+	root := entity
+	for {
+		if dot, ok := root.Data.(*js_ast.EDot); ok {
+			root = dot.Target
+			continue
+		}
+		break
+	}
+	if id, ok := root.Data.(*js_ast.EIdentifier); ok {
+		name := p.loadNameFromRef(id.Ref)
+		found := false
+		for s := p.currentScope; s != nil; s = s.Parent {
+			if member, ok := s.Members[name]; ok {
+				found = true
+				switch p.symbols[member.Ref.InnerIndex].Kind {
+				case ast.SymbolClass:
+					if root.Data == entity.Data {
+						return p.visitExpr(cloneTSMetadataEntity(entity))
+					}
+
+				case ast.SymbolTSEnum:
+					// A member of an enum with mixed values could be either type
+					if metadataType, ok := p.tsEnumMetadataTypes[member.Ref]; ok {
+						if dot, ok := entity.Data.(*js_ast.EDot); root.Data == entity.Data || (ok && dot.Target.Data == root.Data && metadataType != "Object") {
+							return p.valueForTSMetadataType(loc, js_ast.TSMetadataType{Name: metadataType})
+						}
+					}
+				}
+				break
+			}
+		}
+		if !found && p.tsMetadataObjectNames[name] {
+			return p.valueForTSMetadataType(loc, js_ast.TSMetadataType{Name: "Object"})
+		}
+	}
+
+	// Anything else may only be a type, so check for it at run-time instead:
+	//
+	//   typeof Foo === "function" ? Foo : Object
+	//   typeof Foo !== "undefined" && typeof Foo.Bar === "function" ? Foo.Bar : Object
+	//
+	loc = entity.Loc
+	_, isIdentifier := entity.Data.(*js_ast.EIdentifier)
+	test := js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+		Op: js_ast.BinOpStrictEq,
+		Left: js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{
+			Op:                            js_ast.UnOpTypeof,
+			Value:                         cloneTSMetadataEntity(entity),
+			WasOriginallyTypeofIdentifier: isIdentifier,
+		}},
+		Right: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("function")}},
+	}}
+	if root.Data != entity.Data {
+		test = js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+			Op: js_ast.BinOpLogicalAnd,
+			Left: js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+				Op: js_ast.BinOpStrictNe,
+				Left: js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{
+					Op:                            js_ast.UnOpTypeof,
+					Value:                         cloneTSMetadataEntity(root),
+					WasOriginallyTypeofIdentifier: true,
+				}},
+				Right: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("undefined")}},
+			}},
+			Right: test,
+		}}
+	}
+	return p.visitExpr(js_ast.Expr{Loc: loc, Data: &js_ast.EIf{
+		Test: test,
+		Yes:  cloneTSMetadataEntity(entity),
+		No:   js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: p.storeNameInRef(js_lexer.MaybeSubstring{String: "Object"})}},
+	}})
+}
+
+// Type references are stored unvisited and each copy must be visited separately
+func cloneTSMetadataEntity(expr js_ast.Expr) js_ast.Expr {
+	switch e := expr.Data.(type) {
+	case *js_ast.EIdentifier:
+		return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EIdentifier{Ref: e.Ref}}
+	case *js_ast.EDot:
+		return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EDot{Target: cloneTSMetadataEntity(e.Target), Name: e.Name, NameLoc: e.NameLoc}}
+	}
+	return expr
+}
+
 // Replace "super()" calls with our shim so that we can guarantee
 // that instance field initialization doesn't happen before "super()"
 // is called, since at that point "this" isn't available.
 func (p *parser) insertStmtsAfterSuperCall(body *js_ast.FnBody, stmtsToInsert []js_ast.Stmt, superCtorRef ast.Ref) {
 	// If this class has no base class, then there's no "super()" call to handle
 	if superCtorRef == ast.InvalidRef || p.symbols[superCtorRef.InnerIndex].UseCountEstimate == 0 {
-		body.Block.Stmts = append(stmtsToInsert, body.Block.Stmts...)
+		// Lowered function arguments must still be initialized first
+		n := p.loweredArgsPrologueLen[&body.Block]
+		stmts := append(append([]js_ast.Stmt{}, body.Block.Stmts[:n]...), stmtsToInsert...)
+		body.Block.Stmts = append(stmts, body.Block.Stmts[n:]...)
 		return
 	}
 
@@ -1706,87 +2396,26 @@ func (p *parser) insertStmtsAfterSuperCall(body *js_ast.FnBody, stmtsToInsert []
 	// top-level expression in the constructor function body. If so, we
 	// can generate tighter code for this common case.
 	if p.symbols[superCtorRef.InnerIndex].UseCountEstimate == 1 {
-		for i, stmt := range body.Block.Stmts {
-			var before js_ast.Expr
-			var callLoc logger.Loc
-			var callData *js_ast.ECall
-			var after js_ast.Stmt
-
-			switch s := stmt.Data.(type) {
-			case *js_ast.SExpr:
-				if b, loc, c, a := findFirstTopLevelSuperCall(s.Value, superCtorRef); c != nil {
-					before, callLoc, callData = b, loc, c
-					if a.Data != nil {
-						s.Value = a
-						after = js_ast.Stmt{Loc: a.Loc, Data: s}
-					}
-				}
-
-			case *js_ast.SReturn:
-				if s.ValueOrNil.Data != nil {
-					if b, loc, c, a := findFirstTopLevelSuperCall(s.ValueOrNil, superCtorRef); c != nil && a.Data != nil {
-						before, callLoc, callData = b, loc, c
-						s.ValueOrNil = a
-						after = js_ast.Stmt{Loc: a.Loc, Data: s}
-					}
-				}
-
-			case *js_ast.SThrow:
-				if b, loc, c, a := findFirstTopLevelSuperCall(s.Value, superCtorRef); c != nil && a.Data != nil {
-					before, callLoc, callData = b, loc, c
-					s.Value = a
-					after = js_ast.Stmt{Loc: a.Loc, Data: s}
-				}
-
-			case *js_ast.SIf:
-				if b, loc, c, a := findFirstTopLevelSuperCall(s.Test, superCtorRef); c != nil && a.Data != nil {
-					before, callLoc, callData = b, loc, c
-					s.Test = a
-					after = js_ast.Stmt{Loc: a.Loc, Data: s}
-				}
-
-			case *js_ast.SSwitch:
-				if b, loc, c, a := findFirstTopLevelSuperCall(s.Test, superCtorRef); c != nil && a.Data != nil {
-					before, callLoc, callData = b, loc, c
-					s.Test = a
-					after = js_ast.Stmt{Loc: a.Loc, Data: s}
-				}
-
-			case *js_ast.SFor:
-				if expr, ok := s.InitOrNil.Data.(*js_ast.SExpr); ok {
-					if b, loc, c, a := findFirstTopLevelSuperCall(expr.Value, superCtorRef); c != nil {
-						before, callLoc, callData = b, loc, c
-						if a.Data != nil {
-							expr.Value = a
-						} else {
-							s.InitOrNil.Data = nil
-						}
-						after = js_ast.Stmt{Loc: a.Loc, Data: s}
-					}
-				}
+		if i, before, callLoc, callData, after := findTopLevelSuperCallStmt(body.Block.Stmts, superCtorRef); callData != nil {
+			// Revert "__super()" back to "super()"
+			callData.Target.Data = js_ast.ESuperShared
+			p.ignoreUsage(superCtorRef)
+
+			// Inject "stmtsToInsert" after "super()"
+			stmtsBefore := body.Block.Stmts[:i]
+			stmtsAfter := body.Block.Stmts[i+1:]
+			stmts := append([]js_ast.Stmt{}, stmtsBefore...)
+			if before.Data != nil {
+				stmts = append(stmts, js_ast.Stmt{Loc: before.Loc, Data: &js_ast.SExpr{Value: before}})
 			}
-
-			if callData != nil {
-				// Revert "__super()" back to "super()"
-				callData.Target.Data = js_ast.ESuperShared
-				p.ignoreUsage(superCtorRef)
-
-				// Inject "stmtsToInsert" after "super()"
-				stmtsBefore := body.Block.Stmts[:i]
-				stmtsAfter := body.Block.Stmts[i+1:]
-				stmts := append([]js_ast.Stmt{}, stmtsBefore...)
-				if before.Data != nil {
-					stmts = append(stmts, js_ast.Stmt{Loc: before.Loc, Data: &js_ast.SExpr{Value: before}})
-				}
-				stmts = append(stmts, js_ast.Stmt{Loc: callLoc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: callLoc, Data: callData}}})
-				stmts = append(stmts, stmtsToInsert...)
-				if after.Data != nil {
-					stmts = append(stmts, after)
-				}
-				stmts = append(stmts, stmtsAfter...)
-				body.Block.Stmts = stmts
-				return
+			stmts = append(stmts, js_ast.Stmt{Loc: callLoc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: callLoc, Data: callData}}})
+			stmts = append(stmts, stmtsToInsert...)
+			if after.Data != nil {
+				stmts = append(stmts, after)
 			}
+			stmts = append(stmts, stmtsAfter...)
+			body.Block.Stmts = stmts
+			return
 		}
 	}
 
@@ -1824,10 +2453,82 @@ func (p *parser) insertStmtsAfterSuperCall(body *js_ast.FnBody, stmtsToInsert []
 	}}}}}, body.Block.Stmts...)
 }
 
+// Finds the top-level statement in a constructor body that contains the
+// only "super()" call, if there is one. The statement is split into the
+// expressions before and after the call so that code can be inserted in
+// between them.
+func findTopLevelSuperCallStmt(stmts []js_ast.Stmt, superCtorRef ast.Ref) (int, js_ast.Expr, logger.Loc, *js_ast.ECall, js_ast.Stmt) {
+	for i, stmt := range stmts {
+		var before js_ast.Expr
+		var callLoc logger.Loc
+		var callData *js_ast.ECall
+		var after js_ast.Stmt
+
+		switch s := stmt.Data.(type) {
+		case *js_ast.SExpr:
+			if b, loc, c, a := findFirstTopLevelSuperCall(s.Value, superCtorRef); c != nil {
+				before, callLoc, callData = b, loc, c
+				if a.Data != nil {
+					s.Value = a
+					after = js_ast.Stmt{Loc: a.Loc, Data: s}
+				}
+			}
+
+		case *js_ast.SReturn:
+			if s.ValueOrNil.Data != nil {
+				if b, loc, c, a := findFirstTopLevelSuperCall(s.ValueOrNil, superCtorRef); c != nil && a.Data != nil {
+					before, callLoc, callData = b, loc, c
+					s.ValueOrNil = a
+					after = js_ast.Stmt{Loc: a.Loc, Data: s}
+				}
+			}
+
+		case *js_ast.SThrow:
+			if b, loc, c, a := findFirstTopLevelSuperCall(s.Value, superCtorRef); c != nil && a.Data != nil {
+				before, callLoc, callData = b, loc, c
+				s.Value = a
+				after = js_ast.Stmt{Loc: a.Loc, Data: s}
+			}
+
+		case *js_ast.SIf:
+			if b, loc, c, a := findFirstTopLevelSuperCall(s.Test, superCtorRef); c != nil && a.Data != nil {
+				before, callLoc, callData = b, loc, c
+				s.Test = a
+				after = js_ast.Stmt{Loc: a.Loc, Data: s}
+			}
+
+		case *js_ast.SSwitch:
+			if b, loc, c, a := findFirstTopLevelSuperCall(s.Test, superCtorRef); c != nil && a.Data != nil {
+				before, callLoc, callData = b, loc, c
+				s.Test = a
+				after = js_ast.Stmt{Loc: a.Loc, Data: s}
+			}
+
+		case *js_ast.SFor:
+			if expr, ok := s.InitOrNil.Data.(*js_ast.SExpr); ok {
+				if b, loc, c, a := findFirstTopLevelSuperCall(expr.Value, superCtorRef); c != nil {
+					before, callLoc, callData = b, loc, c
+					if a.Data != nil {
+						expr.Value = a
+					} else {
+						s.InitOrNil.Data = nil
+					}
+					after = js_ast.Stmt{Loc: a.Loc, Data: s}
+				}
+			}
+		}
+
+		if callData != nil {
+			return i, before, callLoc, callData, after
+		}
+	}
+
+	return 0, js_ast.Expr{}, logger.Loc{}, nil, js_ast.Stmt{}
+}
+
 func findFirstTopLevelSuperCall(expr js_ast.Expr, superCtorRef ast.Ref) (js_ast.Expr, logger.Loc, *js_ast.ECall, js_ast.Expr) {
 	if call, ok := expr.Data.(*js_ast.ECall); ok {
 		if target, ok := call.Target.Data.(*js_ast.EIdentifier); ok && target.Ref == superCtorRef {
-			call.Target.Data = js_ast.ESuperShared
 			return js_ast.Expr{}, expr.Loc, call, js_ast.Expr{}
 		}
 	}
@@ -1845,3 +2546,246 @@ func findFirstTopLevelSuperCall(expr js_ast.Expr, superCtorRef ast.Ref) (js_ast.
 
 	return js_ast.Expr{}, logger.Loc{}, nil, js_ast.Expr{}
 }
+
+// This is like "insertStmtsAfterSuperCall" but for derived classes that are
+// lowered to ES5. There is no "super()" in ES5, so the base class constructor
+// is called using a helper function instead. The object returned from that
+// call is stored in "thisRef", which replaces "this" in the constructor body,
+// and is returned at the end of the constructor:
+//
+//	function Foo(x) {
+//	  var _this = __callSuper(this, _super, [x]);
+//	  ...stmtsToInsert...
+//	  return _this;
+//	}
+func (p *parser) insertStmtsAfterSuperCallForES5(body *js_ast.FnBody, stmtsToInsert []js_ast.Stmt, superCtorRef ast.Ref, thisRef ast.Ref, superRef ast.Ref) {
+	loc := body.Loc
+	thisFunc := func() js_ast.Expr {
+		p.recordUsage(thisRef)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: thisRef}}
+	}
+
+	// "super(a, b)" => "__callSuper(_this, _super, [a, b])"
+	// "super(...arguments)" => "__callSuper(_this, _super, arguments)"
+	callSuper := func(loc logger.Loc, self js_ast.Expr, args []js_ast.Expr) js_ast.Expr {
+		p.recordUsage(superRef)
+		helperArgs := []js_ast.Expr{self, {Loc: loc, Data: &js_ast.EIdentifier{Ref: superRef}}}
+		if len(args) == 1 {
+			if spread, ok := args[0].Data.(*js_ast.ESpread); ok {
+				args = nil
+				helperArgs = append(helperArgs, spread.Value)
+			}
+		}
+		if p.shouldLowerCallSpread(args) {
+			helperArgs = append(helperArgs, p.lowerArraySpread(loc, args, true))
+		} else if len(args) > 0 {
+			helperArgs = append(helperArgs, js_ast.Expr{Loc: loc, Data: &js_ast.EArray{Items: args, IsSingleLine: true}})
+		}
+		return p.callRuntime(loc, "__callSuper", helperArgs)
+	}
+
+	var stmts []js_ast.Stmt
+	declareThis := true
+
+	if p.symbols[superCtorRef.InnerIndex].UseCountEstimate == 0 {
+		// There is no "super()" call
+		stmts = append(stmtsToInsert, body.Block.Stmts...)
+	} else if i, before, callLoc, callData, after := findTopLevelSuperCallStmt(body.Block.Stmts, superCtorRef); callData != nil &&
+		p.symbols[superCtorRef.InnerIndex].UseCountEstimate == 1 {
+		p.ignoreUsage(superCtorRef)
+
+		// Inject "stmtsToInsert" after "super()"
+		stmts = append(stmts, body.Block.Stmts[:i]...)
+		if before.Data != nil {
+			stmts = append(stmts, js_ast.Stmt{Loc: before.Loc, Data: &js_ast.SExpr{Value: before}})
+		}
+		if len(stmts) == 0 {
+			// Initialize "_this" with the result if "super()" comes first
+			declareThis = false
+			stmts = append(stmts, js_ast.Stmt{Loc: callLoc, Data: &js_ast.SLocal{Decls: []js_ast.Decl{{
+				Binding:    js_ast.Binding{Loc: callLoc, Data: &js_ast.BIdentifier{Ref: thisRef}},
+				ValueOrNil: callSuper(callLoc, js_ast.Expr{Loc: callLoc, Data: js_ast.EThisShared}, callData.Args),
+			}}}})
+		} else {
+			stmts = append(stmts, js_ast.AssignStmt(thisFunc(), callSuper(callLoc, thisFunc(), callData.Args)))
+		}
+		stmts = append(stmts, stmtsToInsert...)
+		if after.Data != nil {
+			stmts = append(stmts, after)
+		}
+		stmts = append(stmts, body.Block.Stmts[i+1:]...)
+	} else {
+		// Otherwise, inject a generated "__super" helper function at the top of
+		// the constructor that looks like this:
+		//
+		//   var __super = function() {
+		//     _this = __callSuper(_this, _super, arguments);
+		//     ...stmtsToInsert...
+		//     return _this;
+		//   };
+		//
+		argumentsRef := p.newSymbol(ast.SymbolUnbound, "arguments")
+		p.currentScope.Generated = append(p.currentScope.Generated, argumentsRef)
+		superStmts := make([]js_ast.Stmt, 0, len(stmtsToInsert)+2)
+		superStmts = append(superStmts, js_ast.AssignStmt(thisFunc(), callSuper(loc, thisFunc(), []js_ast.Expr{{Loc: loc, Data: &js_ast.ESpread{
+			Value: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: argumentsRef}}}}})))
+		superStmts = append(superStmts, stmtsToInsert...)
+		superStmts = append(superStmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: thisFunc()}})
+		if p.options.minifySyntax {
+			superStmts = p.mangleStmts(superStmts, stmtsFnBody)
+		}
+		stmts = append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SLocal{Decls: []js_ast.Decl{{
+			Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: superCtorRef}},
+			ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EFunction{Fn: js_ast.Fn{
+				Body:         js_ast.FnBody{Loc: loc, Block: js_ast.SBlock{Stmts: superStmts}},
+				ArgumentsRef: ast.InvalidRef,
+			}}},
+		}}}})
+		stmts = append(stmts, body.Block.Stmts...)
+	}
+
+	// "this" may be used before "super()" is called (e.g. in an arrow function)
+	if declareThis {
+		stmts = append([]js_ast.Stmt{{Loc: loc, Data: &js_ast.SLocal{Decls: []js_ast.Decl{{
+			Binding:    js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: thisRef}},
+			ValueOrNil: js_ast.Expr{Loc: loc, Data: js_ast.EThisShared},
+		}}}}}, stmts...)
+	}
+
+	// Return the object that was returned from the base class constructor
+	if len(stmts) == 0 || !isJumpStatement(stmts[len(stmts)-1].Data) {
+		stmts = append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: thisFunc()}})
+	}
+	body.Block.Stmts = stmts
+}
+
+// This converts a class that has already had all of its fields and private
+// members lowered into an ES5 constructor function. The constructor and the
+// methods are wrapped in an immediately-invoked function expression:
+//
+//	var Foo = /* @__PURE__ */ function(_super) {
+//	  __inherits(Foo, _super);
+//	  function Foo() {
+//	    var _this = __callSuper(this, _super, arguments);
+//	    return _this;
+//	  }
+//	  __method(Foo.prototype, "foo", function() {
+//	  });
+//	  return Foo;
+//	}(Bar);
+//
+// Computed property keys are passed as arguments to the function expression
+// so they are evaluated in the same scope and in the same order as before.
+func (p *parser) lowerClassToES5(loc logger.Loc, class *js_ast.Class, ctor *js_ast.EFunction, nameRef ast.Ref, superRef ast.Ref, canBeRemovedIfUnused bool) js_ast.ECall {
+	var name ast.LocRef
+	if class.Name != nil {
+		name = *class.Name
+	} else if nameRef != ast.InvalidRef {
+		name = ast.LocRef{Loc: loc, Ref: nameRef}
+	} else {
+		name = ast.LocRef{Loc: loc, Ref: p.newSymbol(ast.SymbolOther, "_class")}
+		p.currentScope.Generated = append(p.currentScope.Generated, name.Ref)
+	}
+	nameFunc := func(loc logger.Loc) js_ast.Expr {
+		p.recordUsage(name.Ref)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: name.Ref}}
+	}
+
+	var params []js_ast.Arg
+	var args []js_ast.Expr
+	var stmts []js_ast.Stmt
+
+	// Set up the prototype chain
+	if class.ExtendsOrNil.Data != nil {
+		if superRef == ast.InvalidRef {
+			superRef = p.newSymbol(ast.SymbolOther, "_super")
+			p.currentScope.Generated = append(p.currentScope.Generated, superRef)
+		}
+		p.recordUsage(superRef)
+		params = append(params, js_ast.Arg{Binding: js_ast.Binding{Loc: class.ExtendsOrNil.Loc, Data: &js_ast.BIdentifier{Ref: superRef}}})
+		args = append(args, class.ExtendsOrNil)
+		stmts = append(stmts, js_ast.Stmt{Loc: class.ExtendsOrNil.Loc, Data: &js_ast.SExpr{Value: p.callRuntime(class.ExtendsOrNil.Loc, "__inherits", []js_ast.Expr{
+			nameFunc(loc),
+			{Loc: class.ExtendsOrNil.Loc, Data: &js_ast.EIdentifier{Ref: superRef}},
+		})}})
+	}
+
+	// Generate the constructor function
+	ctorLoc := class.BodyLoc
+	fn := js_ast.Fn{Body: js_ast.FnBody{Loc: class.BodyLoc}, ArgumentsRef: ast.InvalidRef}
+	if ctor != nil {
+		fn = ctor.Fn
+	}
+	fn.Name = &name
+	p.recordDeclaredSymbol(name.Ref)
+
+	// Classes must be called with "new": "__classCallCheck(this, Foo);"
+	callCheck := js_ast.Stmt{Loc: ctorLoc, Data: &js_ast.SExpr{Value: p.callRuntime(ctorLoc, "__classCallCheck", []js_ast.Expr{
+		{Loc: ctorLoc, Data: js_ast.EThisShared},
+		nameFunc(ctorLoc),
+	})}}
+	bodyStmts := fn.Body.Block.Stmts
+	directiveCount := 0
+	for directiveCount < len(bodyStmts) {
+		if _, ok := bodyStmts[directiveCount].Data.(*js_ast.SDirective); !ok {
+			break
+		}
+		directiveCount++
+	}
+	fn.Body.Block.Stmts = make([]js_ast.Stmt, 0, len(bodyStmts)+1)
+	fn.Body.Block.Stmts = append(fn.Body.Block.Stmts, bodyStmts[:directiveCount]...)
+	fn.Body.Block.Stmts = append(fn.Body.Block.Stmts, callCheck)
+	fn.Body.Block.Stmts = append(fn.Body.Block.Stmts, bodyStmts[directiveCount:]...)
+	stmts = append(stmts, js_ast.Stmt{Loc: ctorLoc, Data: &js_ast.SFunction{Fn: fn}})
+
+	// Add the methods to the constructor function or to its prototype
+	for _, prop := range class.Properties {
+		if ctor != nil && prop.ValueOrNil.Data == ctor {
+			continue
+		}
+		if !prop.Flags.Has(js_ast.PropertyIsMethod) {
+			panic("Internal error")
+		}
+
+		var target js_ast.Expr
+		if prop.Flags.Has(js_ast.PropertyIsStatic) {
+			target = nameFunc(prop.Loc)
+		} else {
+			target = js_ast.Expr{Loc: prop.Loc, Data: &js_ast.EDot{Target: nameFunc(prop.Loc), Name: "prototype", NameLoc: prop.Loc}}
+		}
+
+		// Evaluate computed keys outside of the function expression
+		key := prop.Key
+		switch key.Data.(type) {
+		case *js_ast.EString, *js_ast.ENumber, *js_ast.ENameOfSymbol:
+		default:
+			ref := p.generateTempRef(tempRefNoDeclare, "")
+			p.recordUsage(ref)
+			params = append(params, js_ast.Arg{Binding: js_ast.Binding{Loc: key.Loc, Data: &js_ast.BIdentifier{Ref: ref}}})
+			args = append(args, key)
+			key = js_ast.Expr{Loc: key.Loc, Data: &js_ast.EIdentifier{Ref: ref}}
+		}
+
+		var helper string
+		switch prop.Kind {
+		case js_ast.PropertyGet:
+			helper = "__getter"
+		case js_ast.PropertySet:
+			helper = "__setter"
+		default:
+			helper = "__method"
+		}
+		stmts = append(stmts, js_ast.Stmt{Loc: prop.Loc, Data: &js_ast.SExpr{Value: p.callRuntime(prop.Loc, helper, []js_ast.Expr{target, key, prop.ValueOrNil})}})
+	}
+
+	stmts = append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: nameFunc(loc)}})
+	return js_ast.ECall{
+		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EFunction{Fn: js_ast.Fn{
+			Args:         params,
+			Body:         js_ast.FnBody{Loc: class.BodyLoc, Block: js_ast.SBlock{Stmts: stmts}},
+			ArgumentsRef: ast.InvalidRef,
+		}}},
+		Args:                   args,
+		CanBeUnwrappedIfUnused: canBeRemovedIfUnused,
+	}
+}
@@ -0,0 +1,444 @@
+package js_parser
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/compat"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+)
+
+// This file implements lowering destructuring binding patterns as well as
+// default and rest function arguments for environments that don't support
+// them (e.g. ES5). Binding patterns are flattened into a sequence of simple
+// assignments that read from temporary variables:
+//
+//   var {a, b: [c, d = 1]} = foo()
+//
+// is transformed into the following code:
+//
+//   var _a = foo(), a = _a.a, _b = __toArray(_a.b, 2), c = _b[0], _c = _b[1], d = _c === void 0 ? 1 : _c
+//
+// Default and rest arguments are moved into the function body:
+//
+//   function foo(a = 1, ...b) {}
+//
+// is transformed into the following code:
+//
+//   function foo(a) {
+//     if (a === void 0) a = 1;
+//     var b = __restArgs(arguments, 1);
+//   }
+//
+// This mostly follows the transform that TypeScript uses. Array patterns go
+// through the "__toArray" runtime helper so that they work with any iterable
+// instead of only with arrays.
+
+func bindingIsPattern(binding js_ast.Binding) bool {
+	switch binding.Data.(type) {
+	case *js_ast.BArray, *js_ast.BObject:
+		return true
+	}
+	return false
+}
+
+// Returns true if this binding contains a pattern that must be lowered
+func (p *parser) bindingNeedsLowering(binding js_ast.Binding) bool {
+	if p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
+		return bindingIsPattern(binding)
+	}
+	return p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) && bindingHasObjectRest(binding)
+}
+
+// Returns true if this assignment target contains a pattern that must be lowered
+func (p *parser) exprNeedsLowering(expr js_ast.Expr) bool {
+	if p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
+		switch expr.Data.(type) {
+		case *js_ast.EArray, *js_ast.EObject:
+			return true
+		}
+		return false
+	}
+	return p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) && exprHasObjectRest(expr)
+}
+
+func (p *parser) lowerDestructuringHelper(
+	rootExpr js_ast.Expr,
+	rootInit js_ast.Expr,
+	assign func(js_ast.Expr, js_ast.Expr),
+	declare generateTempRefArg,
+	mode objRestMode,
+) (wrapFunc func(js_ast.Expr) js_ast.Expr, ok bool) {
+	if !p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
+		return nil, false
+	}
+
+	// Check if this is actually a destructuring pattern
+	switch rootExpr.Data.(type) {
+	case *js_ast.EArray, *js_ast.EObject:
+	default:
+		return nil, false
+	}
+
+	// Store the value in a temporary so it can be referenced multiple times.
+	// This returns a function that generates a new reference each time.
+	capture := func(value js_ast.Expr) func() js_ast.Expr {
+		loc := value.Loc
+		ref := p.generateTempRef(declare, "")
+		p.recordUsage(ref)
+		assign(js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}, value)
+		return func() js_ast.Expr {
+			p.recordUsage(ref)
+			return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
+		}
+	}
+
+	// This takes an expression representing a binding pattern as input and
+	// appends a simple assignment for each identifier or property access in
+	// that pattern. The "init" function returns the value being destructured,
+	// and "initIsCaptured" indicates that it can be called more than once.
+	//
+	// This transform preserves the evaluation order of default values and
+	// computed property keys. However, the targets of assignment expressions
+	// (e.g. "a.b" in "[a.b] = c") are evaluated after reading the value
+	// instead of before like in the specification. This is what the
+	// TypeScript compiler does too.
+	var visit func(js_ast.Expr, func() js_ast.Expr, bool)
+	visit = func(expr js_ast.Expr, init func() js_ast.Expr, initIsCaptured bool) {
+		switch e := expr.Data.(type) {
+		case *js_ast.EBinary:
+			if e.Op == js_ast.BinOpAssign {
+				// "[a = b] = c" => "_a = c[0], a = _a === void 0 ? b : _a"
+				if !initIsCaptured {
+					init = capture(init())
+				}
+				visit(e.Left, func() js_ast.Expr {
+					return js_ast.Expr{Loc: e.Right.Loc, Data: &js_ast.EIf{
+						Test: js_ast.Expr{Loc: e.Right.Loc, Data: &js_ast.EBinary{
+							Op:    js_ast.BinOpStrictEq,
+							Left:  init(),
+							Right: js_ast.Expr{Loc: e.Right.Loc, Data: js_ast.EUndefinedShared},
+						}},
+						Yes: e.Right,
+						No:  init(),
+					}}
+				}, false)
+				return
+			}
+
+		case *js_ast.EArray:
+			// "[a, b] = c" => "_a = __toArray(c, 2), a = _a[0], b = _a[1]"
+			// "[a, ...b] = c" => "_a = __toArray(c), a = _a[0], b = _a.slice(1)"
+			args := []js_ast.Expr{init()}
+			last := len(e.Items) - 1
+			if last < 0 {
+				args = append(args, js_ast.Expr{Loc: expr.Loc, Data: &js_ast.ENumber{}})
+			} else if _, ok := e.Items[last].Data.(*js_ast.ESpread); !ok {
+				args = append(args, js_ast.Expr{Loc: expr.Loc, Data: &js_ast.ENumber{Value: float64(len(e.Items))}})
+			}
+			array := capture(p.callRuntime(expr.Loc, "__toArray", args))
+			for i, item := range e.Items {
+				index := js_ast.Expr{Loc: item.Loc, Data: &js_ast.ENumber{Value: float64(i)}}
+				switch item := item.Data.(type) {
+				case *js_ast.EMissing:
+					continue
+
+				case *js_ast.ESpread:
+					visit(item.Value, func() js_ast.Expr {
+						return js_ast.Expr{Loc: index.Loc, Data: &js_ast.ECall{
+							Target: js_ast.Expr{Loc: index.Loc, Data: &js_ast.EDot{Target: array(), Name: "slice", NameLoc: index.Loc}},
+							Args:   []js_ast.Expr{index},
+							Kind:   js_ast.TargetWasOriginallyPropertyAccess,
+						}}
+					}, false)
+					continue
+				}
+				visit(item, func() js_ast.Expr {
+					return js_ast.Expr{Loc: index.Loc, Data: &js_ast.EIndex{Target: array(), Index: index}}
+				}, false)
+			}
+			return
+
+		case *js_ast.EObject:
+			// "{a, b} = c" => "_a = c, a = _a.a, b = _a.b"
+			// "{a, ...b} = c" => "_a = c, a = _a.a, b = __objRest(_a, ['a'])"
+			last := len(e.Properties) - 1
+			endsWithRestBinding := last >= 0 && e.Properties[last].Kind == js_ast.PropertySpread
+
+			// "{} = c" => "_a = c"
+			if last < 0 {
+				if !initIsCaptured {
+					capture(init())
+				}
+				return
+			}
+
+			// There's no need for a temporary if there's only one property
+			if !initIsCaptured && (len(e.Properties) != 1 || endsWithRestBinding) {
+				init = capture(init())
+			}
+
+			var capturedKeys []func() js_ast.Expr
+			for _, property := range e.Properties {
+				// "let {a, ...b} = c"
+				if property.Kind == js_ast.PropertySpread {
+					visit(property.ValueOrNil, func() js_ast.Expr {
+						keysToExclude := make([]js_ast.Expr, len(capturedKeys))
+						for i, capturedKey := range capturedKeys {
+							keysToExclude[i] = capturedKey()
+						}
+						return p.callRuntime(property.ValueOrNil.Loc, "__objRest", []js_ast.Expr{init(),
+							{Loc: property.ValueOrNil.Loc, Data: &js_ast.EArray{Items: keysToExclude, IsSingleLine: e.IsSingleLine}}})
+					}, false)
+					continue
+				}
+
+				// Save a copy of this key so the rest binding can exclude it
+				key := property.Key
+				if endsWithRestBinding {
+					var capturedKey func() js_ast.Expr
+					key, capturedKey = p.captureKeyForObjectRest(key)
+					capturedKeys = append(capturedKeys, capturedKey)
+				}
+
+				// Default values for shorthand properties are stored separately
+				target := property.ValueOrNil
+				if property.InitializerOrNil.Data != nil {
+					target = js_ast.Assign(target, property.InitializerOrNil)
+				}
+
+				visit(target, func() js_ast.Expr {
+					if str, ok := key.Data.(*js_ast.EString); ok && js_ast.IsIdentifierUTF16(str.Value) {
+						return js_ast.Expr{Loc: key.Loc, Data: &js_ast.EDot{Target: init(), Name: helpers.UTF16ToString(str.Value), NameLoc: key.Loc}}
+					}
+					return js_ast.Expr{Loc: key.Loc, Data: &js_ast.EIndex{Target: init(), Index: key}}
+				}, false)
+			}
+			return
+		}
+
+		assign(expr, init())
+	}
+
+	// Capture and return the value of the initializer if this is an assignment
+	// expression and the return value is used
+	if mode == objRestMustReturnInitExpr {
+		initFunc, initWrapFunc := p.captureValueWithPossibleSideEffects(rootInit.Loc, 2, rootInit, valueCouldBeMutated)
+		rootInit = initFunc()
+		wrapFunc = func(expr js_ast.Expr) js_ast.Expr {
+			return initWrapFunc(js_ast.JoinWithComma(expr, initFunc()))
+		}
+	}
+
+	visit(rootExpr, func() js_ast.Expr { return rootInit }, false)
+	return wrapFunc, true
+}
+
+// This lowers default arguments, rest arguments, and binding patterns in
+// function arguments into statements at the start of the function body.
+// Arguments with default values are kept in the argument list so that the
+// "length" property of the function is only different when there is a
+// default value before an argument without one. Rest arguments are removed
+// and read from "arguments" instead, which is only possible if this is not
+// an arrow function (or if the arrow function will be converted into a
+// normal function).
+func (p *parser) lowerFunctionArgs(args *[]js_ast.Arg, hasRestArg *bool, bodyBlock *js_ast.SBlock, isArrow bool) {
+	lowerPatterns := p.options.unsupportedJSFeatures.Has(compat.Destructuring)
+	lowerDefaults := p.options.unsupportedJSFeatures.Has(compat.DefaultArgument)
+	lowerRest := *hasRestArg && p.options.unsupportedJSFeatures.Has(compat.RestArgument) &&
+		(!isArrow || p.options.unsupportedJSFeatures.Has(compat.Arrow))
+
+	// Don't do any allocations if there's nothing to lower. We want as little
+	// overhead as possible in the common case.
+	needsLowering := lowerRest
+	if !needsLowering {
+		for _, arg := range *args {
+			if (lowerPatterns && bindingIsPattern(arg.Binding)) || (lowerDefaults && arg.DefaultOrNil.Data != nil) {
+				needsLowering = true
+				break
+			}
+		}
+		if !needsLowering {
+			return
+		}
+	}
+
+	var stmts []js_ast.Stmt
+	var decls []js_ast.Decl
+	flushDecls := func() {
+		if len(decls) > 0 {
+			stmts = append(stmts, js_ast.Stmt{Loc: decls[0].Binding.Loc, Data: &js_ast.SLocal{
+				Kind:  js_ast.LocalVar,
+				Decls: p.lowerDestructuringInDecls(decls),
+			}})
+			decls = nil
+		}
+	}
+
+	for i, arg := range *args {
+		loc := arg.Binding.Loc
+
+		// "function foo(...a) {}" => "function foo() { var a = __restArgs(arguments, 0) }"
+		if lowerRest && i+1 == len(*args) {
+			argumentsRef := p.newSymbol(ast.SymbolUnbound, "arguments")
+			p.currentScope.Generated = append(p.currentScope.Generated, argumentsRef)
+			decls = append(decls, js_ast.Decl{Binding: arg.Binding, ValueOrNil: p.callRuntime(loc, "__restArgs", []js_ast.Expr{
+				{Loc: loc, Data: &js_ast.EIdentifier{Ref: argumentsRef}},
+				{Loc: loc, Data: &js_ast.ENumber{Value: float64(i)}},
+			})})
+			*args = (*args)[:i]
+			*hasRestArg = false
+			break
+		}
+
+		// "function foo({a}) {}" => "function foo(_a) { var a = _a.a }"
+		binding := arg.Binding
+		if bindingIsPattern(binding) && (lowerPatterns || (lowerDefaults && arg.DefaultOrNil.Data != nil)) {
+			ref := p.generateTempRef(tempRefNoDeclare, "")
+			(*args)[i].Binding.Data = &js_ast.BIdentifier{Ref: ref}
+		}
+
+		// "function foo(a = b) {}" => "function foo(a) { if (a === void 0) a = b }"
+		if lowerDefaults && arg.DefaultOrNil.Data != nil {
+			ref := (*args)[i].Binding.Data.(*js_ast.BIdentifier).Ref
+			p.recordUsage(ref)
+			p.recordUsage(ref)
+			flushDecls()
+			stmts = append(stmts, js_ast.Stmt{Loc: arg.DefaultOrNil.Loc, Data: &js_ast.SIf{
+				Test: js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+					Op:    js_ast.BinOpStrictEq,
+					Left:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}},
+					Right: js_ast.Expr{Loc: loc, Data: js_ast.EUndefinedShared},
+				}},
+				Yes: js_ast.AssignStmt(js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}, arg.DefaultOrNil),
+			}})
+			(*args)[i].DefaultOrNil = js_ast.Expr{}
+		}
+
+		// Destructure the temporary after the default value has been applied
+		if binding.Data != (*args)[i].Binding.Data {
+			ref := (*args)[i].Binding.Data.(*js_ast.BIdentifier).Ref
+			p.recordUsage(ref)
+			decls = append(decls, js_ast.Decl{Binding: binding, ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}})
+		}
+	}
+
+	flushDecls()
+
+	if p.loweredArgsPrologueLen == nil {
+		p.loweredArgsPrologueLen = make(map[*js_ast.SBlock]int)
+	}
+	p.loweredArgsPrologueLen[bodyBlock] = len(stmts)
+
+	bodyBlock.Stmts = append(stmts, bodyBlock.Stmts...)
+}
+
+func (p *parser) lowerDestructuringInDecls(decls []js_ast.Decl) []js_ast.Decl {
+	if !p.options.unsupportedJSFeatures.Has(compat.Destructuring) && !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
+		return decls
+	}
+
+	// Don't do any allocations if there are no binding patterns. We want as
+	// little overhead as possible in the common case.
+	for i, decl := range decls {
+		if decl.ValueOrNil.Data != nil && p.bindingNeedsLowering(decl.Binding) {
+			clone := append([]js_ast.Decl{}, decls[:i]...)
+			for _, decl := range decls[i:] {
+				if decl.ValueOrNil.Data != nil {
+					target := js_ast.ConvertBindingToExpr(decl.Binding, nil)
+					if result, ok := p.lowerDestructuringToDecls(target, decl.ValueOrNil, clone); ok {
+						clone = result
+						continue
+					}
+				}
+				clone = append(clone, decl)
+			}
+
+			return clone
+		}
+	}
+
+	return decls
+}
+
+func (p *parser) lowerDestructuringInForLoopInit(init js_ast.Stmt, body *js_ast.Stmt) {
+	if !p.options.unsupportedJSFeatures.Has(compat.Destructuring) && !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
+		return
+	}
+
+	var bodyPrefixStmt js_ast.Stmt
+
+	switch s := init.Data.(type) {
+	case *js_ast.SExpr:
+		// "for ({...x} in y) {}"
+		// "for ([x] of y) {}"
+		if p.exprNeedsLowering(s.Value) {
+			ref := p.generateTempRef(tempRefNeedsDeclare, "")
+			if expr, ok := p.lowerAssign(s.Value, js_ast.Expr{Loc: init.Loc, Data: &js_ast.EIdentifier{Ref: ref}}, objRestReturnValueIsUnused); ok {
+				p.recordUsage(ref)
+				s.Value.Data = &js_ast.EIdentifier{Ref: ref}
+				bodyPrefixStmt = js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}}
+			}
+		}
+
+	case *js_ast.SLocal:
+		// "for (let {...x} in y) {}"
+		// "for (let [x] of y) {}"
+		if len(s.Decls) == 1 && p.bindingNeedsLowering(s.Decls[0].Binding) {
+			ref := p.generateTempRef(tempRefNoDeclare, "")
+			decl := js_ast.Decl{Binding: s.Decls[0].Binding, ValueOrNil: js_ast.Expr{Loc: init.Loc, Data: &js_ast.EIdentifier{Ref: ref}}}
+			p.recordUsage(ref)
+			decls := p.lowerDestructuringInDecls([]js_ast.Decl{decl})
+			s.Decls[0].Binding.Data = &js_ast.BIdentifier{Ref: ref}
+			bodyPrefixStmt = js_ast.Stmt{Loc: init.Loc, Data: &js_ast.SLocal{Kind: s.Kind, Decls: decls}}
+		}
+	}
+
+	if bodyPrefixStmt.Data != nil {
+		if block, ok := body.Data.(*js_ast.SBlock); ok {
+			// If there's already a block, insert at the front
+			stmts := make([]js_ast.Stmt, 0, 1+len(block.Stmts))
+			block.Stmts = append(append(stmts, bodyPrefixStmt), block.Stmts...)
+		} else {
+			// Otherwise, make a block and insert at the front
+			body.Data = &js_ast.SBlock{Stmts: []js_ast.Stmt{bodyPrefixStmt, *body}}
+		}
+	}
+}
+
+func (p *parser) lowerDestructuringInCatchBinding(catch *js_ast.Catch) {
+	if !p.options.unsupportedJSFeatures.Has(compat.Destructuring) && !p.options.unsupportedJSFeatures.Has(compat.ObjectRestSpread) {
+		return
+	}
+
+	if catch.BindingOrNil.Data != nil && p.bindingNeedsLowering(catch.BindingOrNil) {
+		ref := p.generateTempRef(tempRefNoDeclare, "")
+		decl := js_ast.Decl{Binding: catch.BindingOrNil, ValueOrNil: js_ast.Expr{Loc: catch.BindingOrNil.Loc, Data: &js_ast.EIdentifier{Ref: ref}}}
+		p.recordUsage(ref)
+		decls := p.lowerDestructuringInDecls([]js_ast.Decl{decl})
+		catch.BindingOrNil.Data = &js_ast.BIdentifier{Ref: ref}
+		stmts := make([]js_ast.Stmt, 0, 1+len(catch.Block.Stmts))
+		stmts = append(stmts, js_ast.Stmt{Loc: catch.BindingOrNil.Loc, Data: &js_ast.SLocal{Kind: p.selectLocalKind(js_ast.LocalLet), Decls: decls}})
+		catch.Block.Stmts = append(stmts, catch.Block.Stmts...)
+	}
+}
+
+func (p *parser) lowerDestructuringToDecls(rootExpr js_ast.Expr, rootInit js_ast.Expr, decls []js_ast.Decl) ([]js_ast.Decl, bool) {
+	assign := func(left js_ast.Expr, right js_ast.Expr) {
+		binding, invalidLog := p.convertExprToBinding(left, invalidLog{})
+		if len(invalidLog.invalidTokens) > 0 {
+			panic("Internal error")
+		}
+		decls = append(decls, js_ast.Decl{Binding: binding, ValueOrNil: right})
+	}
+
+	lower := p.lowerObjectRestHelper
+	if p.options.unsupportedJSFeatures.Has(compat.Destructuring) {
+		lower = p.lowerDestructuringHelper
+	}
+
+	if _, ok := lower(rootExpr, rootInit, assign, tempRefNoDeclare, objRestReturnValueIsUnused); ok {
+		return decls, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,1537 @@
+package js_parser
+
+import (
+	"fmt"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/compat"
+	"github.com/evanw/esbuild/internal/config"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// This file implements lowering generator functions for environments that
+// don't support them (e.g. ES5). The body of the generator function is split
+// into a series of "cases" at each "yield" expression. The cases live inside
+// a "switch" statement in a nested function that the "__stateMachine" runtime
+// helper calls repeatedly to step through the generator. This code:
+//
+//   function* foo(x) {
+//     let y = yield x
+//     return y + 1
+//   }
+//
+// is transformed into the following code:
+//
+//   function foo(x) {
+//     var y;
+//     return __stateMachine(this, function (_) {
+//       switch (_.label) {
+//         case 0:
+//           return [4, x];
+//         case 1:
+//           y = _.sent();
+//           return [2, y + 1];
+//       }
+//     });
+//   }
+//
+// This mostly follows the transform that TypeScript uses, which is also what
+// the "__stateMachine" helper is modeled after. Statements that don't contain
+// "yield" are kept mostly as-is. Only statements and expressions that contain
+// "yield" need to be split across multiple cases.
+
+// These are the instructions that the state machine body returns to the
+// runtime. They must be kept in sync with the "__stateMachine" helper.
+const (
+	generatorOpReturn     = 2
+	generatorOpJump       = 3
+	generatorOpYield      = 4
+	generatorOpYieldStar  = 5
+	generatorOpEndFinally = 7
+)
+
+type generatorLabelUse struct {
+	number *js_ast.ENumber
+	label  int
+}
+
+type generatorJumpTarget struct {
+	labels        []ast.Ref
+	breakLabel    int
+	continueLabel int // This is -1 if this isn't a loop
+	isBreakable   bool
+}
+
+// Statements inside a statement that doesn't contain "yield" are kept as-is.
+// This tracks which "break" and "continue" statements inside that statement
+// stay local to it and which ones must jump somewhere in the state machine.
+type generatorRewriteContext struct {
+	labels         []ast.Ref
+	loopDepth      int
+	breakableDepth int
+}
+
+type generatorLowering struct {
+	p         *parser
+	stateRef  ast.Ref
+	symbolRef ast.Ref
+
+	// Each case is a list of statements. The case currently being generated
+	// is "current" and its case number is "len(cases)".
+	cases   [][]js_ast.Stmt
+	current []js_ast.Stmt
+
+	// Labels are allocated before they are placed. The label numbers in the
+	// generated code are patched in once all labels have been placed.
+	labelCases []int
+	labelUses  []generatorLabelUse
+	targets    []generatorJumpTarget
+	loopDepth  int
+
+	// Variables and functions declared in the body are moved to the outer
+	// function so that they persist across calls to the state machine body
+	hoistedRefs []ast.Ref
+	isHoisted   map[ast.Ref]bool
+	hoistedFns  []js_ast.Stmt
+	outerDecls  []js_ast.Decl
+}
+
+// Only functions that are visited as generators will be transformed into a
+// state machine. This includes async functions that are first lowered into
+// generators when async functions aren't supported.
+func (p *parser) isGeneratorLowered(isAsync bool, isGenerator bool) bool {
+	if !p.options.unsupportedJSFeatures.Has(compat.Generator) {
+		return false
+	}
+	if isAsync {
+		return p.options.unsupportedJSFeatures.Has(compat.AsyncAwait) ||
+			(isGenerator && p.options.unsupportedJSFeatures.Has(compat.AsyncGenerator))
+	}
+	return isGenerator
+}
+
+func (p *parser) lowerGeneratorBody(bodyLoc logger.Loc, bodyBlock *js_ast.SBlock) {
+	g := &generatorLowering{
+		p:         p,
+		stateRef:  p.newSymbol(ast.SymbolOther, "_"),
+		symbolRef: ast.InvalidRef,
+		isHoisted: make(map[ast.Ref]bool),
+	}
+	p.currentScope.Generated = append(p.currentScope.Generated, g.stateRef)
+
+	// Directives must stay at the top of the outer function
+	stmts := bodyBlock.Stmts
+	var directives []js_ast.Stmt
+	for len(stmts) > 0 {
+		if _, ok := stmts[0].Data.(*js_ast.SDirective); !ok {
+			break
+		}
+		directives = append(directives, stmts[0])
+		stmts = stmts[1:]
+	}
+
+	g.visitStmts(stmts)
+	g.finishCase()
+
+	// Now that all labels have been placed, fill in the label numbers
+	for _, use := range g.labelUses {
+		use.number.Value = float64(g.labelCases[use.label])
+	}
+
+	// Only generate a "switch" statement if there's more than one case
+	var body []js_ast.Stmt
+	if len(g.cases) == 1 {
+		body = g.cases[0]
+	} else {
+		cases := make([]js_ast.Case, 0, len(g.cases))
+		for i, stmts := range g.cases {
+			cases = append(cases, js_ast.Case{
+				Loc:        bodyLoc,
+				ValueOrNil: js_ast.Expr{Loc: bodyLoc, Data: &js_ast.ENumber{Value: float64(i)}},
+				Body:       stmts,
+			})
+		}
+		body = []js_ast.Stmt{{Loc: bodyLoc, Data: &js_ast.SSwitch{
+			Test:          g.stateDot(bodyLoc, "label"),
+			Cases:         cases,
+			BodyLoc:       bodyLoc,
+			CloseBraceLoc: bodyBlock.CloseBraceLoc,
+		}}}
+	}
+
+	outer := directives
+	if len(g.outerDecls) > 0 {
+		outer = append(outer, js_ast.Stmt{Loc: bodyLoc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: g.outerDecls}})
+	}
+	if len(g.hoistedRefs) > 0 {
+		decls := make([]js_ast.Decl, 0, len(g.hoistedRefs))
+		for _, ref := range g.hoistedRefs {
+			decls = append(decls, js_ast.Decl{Binding: js_ast.Binding{Loc: bodyLoc, Data: &js_ast.BIdentifier{Ref: ref}}})
+		}
+		outer = append(outer, js_ast.Stmt{Loc: bodyLoc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: decls}})
+	}
+	outer = append(outer, g.hoistedFns...)
+
+	// "return __stateMachine(this, function (_) { ... })"
+	outer = append(outer, js_ast.Stmt{Loc: bodyLoc, Data: &js_ast.SReturn{ValueOrNil: p.callRuntime(bodyLoc, "__stateMachine", []js_ast.Expr{
+		{Loc: bodyLoc, Data: js_ast.EThisShared},
+		{Loc: bodyLoc, Data: &js_ast.EFunction{Fn: js_ast.Fn{
+			Args: []js_ast.Arg{{Binding: js_ast.Binding{Loc: bodyLoc, Data: &js_ast.BIdentifier{Ref: g.stateRef}}}},
+			Body: js_ast.FnBody{Loc: bodyLoc, Block: js_ast.SBlock{Stmts: body, CloseBraceLoc: bodyBlock.CloseBraceLoc}},
+		}}},
+	})}})
+	bodyBlock.Stmts = outer
+}
+
+func (g *generatorLowering) markUnsupported(loc logger.Loc, what string) {
+	p := g.p
+	where := config.PrettyPrintTargetEnvironment(p.options.originalTargetEnv, p.options.unsupportedJSFeatureOverridesMask)
+	p.log.AddError(&p.tracker, logger.Range{Loc: loc}, fmt.Sprintf(
+		"Transforming %s to %s is not supported yet", what, where))
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Cases and labels
+
+func (g *generatorLowering) newLabel() int {
+	g.labelCases = append(g.labelCases, -1)
+	return len(g.labelCases) - 1
+}
+
+func (g *generatorLowering) markLabel(label int) {
+	if len(g.current) > 0 {
+		// Fall through into the next case. The runtime must know which case
+		// we're in so that it can route exceptions to the right place.
+		next := len(g.cases) + 1
+		if !g.isCaseTerminated() {
+			g.emit(js_ast.AssignStmt(
+				g.stateDot(logger.Loc{}, "label"),
+				js_ast.Expr{Data: &js_ast.ENumber{Value: float64(next)}},
+			))
+		}
+		g.cases = append(g.cases, g.current)
+		g.current = nil
+	}
+	g.labelCases[label] = len(g.cases)
+}
+
+func (g *generatorLowering) finishCase() {
+	if len(g.current) == 0 || !g.isCaseTerminated() {
+		g.emit(js_ast.Stmt{Data: &js_ast.SReturn{ValueOrNil: g.op(logger.Loc{}, generatorOpReturn, js_ast.Expr{})}})
+	}
+	g.cases = append(g.cases, g.current)
+	g.current = nil
+}
+
+func (g *generatorLowering) isCaseTerminated() bool {
+	if len(g.current) == 0 {
+		return false
+	}
+	switch g.current[len(g.current)-1].Data.(type) {
+	case *js_ast.SReturn, *js_ast.SThrow:
+		return true
+	}
+	return false
+}
+
+func (g *generatorLowering) emit(stmt js_ast.Stmt) {
+	g.current = append(g.current, stmt)
+}
+
+func (g *generatorLowering) labelExpr(loc logger.Loc, label int) js_ast.Expr {
+	number := &js_ast.ENumber{}
+	g.labelUses = append(g.labelUses, generatorLabelUse{number: number, label: label})
+	return js_ast.Expr{Loc: loc, Data: number}
+}
+
+// This generates "[op, value]" or "[op]" if there is no value
+func (g *generatorLowering) op(loc logger.Loc, op int, valueOrNil js_ast.Expr) js_ast.Expr {
+	items := []js_ast.Expr{{Loc: loc, Data: &js_ast.ENumber{Value: float64(op)}}}
+	if valueOrNil.Data != nil {
+		items = append(items, valueOrNil)
+	}
+	return js_ast.Expr{Loc: loc, Data: &js_ast.EArray{Items: items, IsSingleLine: true}}
+}
+
+func (g *generatorLowering) jumpStmt(loc logger.Loc, label int) js_ast.Stmt {
+	return js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: g.op(loc, generatorOpJump, g.labelExpr(loc, label))}}
+}
+
+func (g *generatorLowering) jump(loc logger.Loc, label int) {
+	g.emit(g.jumpStmt(loc, label))
+}
+
+func (g *generatorLowering) jumpIf(loc logger.Loc, test js_ast.Expr, label int) {
+	g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SIf{Test: test, Yes: g.jumpStmt(loc, label)}})
+}
+
+func (g *generatorLowering) stateDot(loc logger.Loc, name string) js_ast.Expr {
+	g.p.recordUsage(g.stateRef)
+	return js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+		Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: g.stateRef}},
+		Name:    name,
+		NameLoc: loc,
+	}}
+}
+
+// This generates "_.sent()", which evaluates to the value passed to "next()"
+// or throws the value passed to "throw()"
+func (g *generatorLowering) sent(loc logger.Loc) js_ast.Expr {
+	return js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+		Target: g.stateDot(loc, "sent"),
+		Kind:   js_ast.TargetWasOriginallyPropertyAccess,
+	}}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Variables
+
+func (g *generatorLowering) hoist(ref ast.Ref) {
+	if !g.isHoisted[ref] {
+		g.isHoisted[ref] = true
+		g.hoistedRefs = append(g.hoistedRefs, ref)
+
+		// Block-scoped variables are turned into function-scoped variables. Make
+		// sure they are renamed as if they were declared in the function scope so
+		// that they don't collide with other variables of the same name.
+		g.p.currentScope.Generated = append(g.p.currentScope.Generated, ref)
+	}
+}
+
+func (g *generatorLowering) hoistBinding(binding js_ast.Binding) {
+	js_ast.ForEachIdentifierBinding(binding, func(loc logger.Loc, b *js_ast.BIdentifier) {
+		g.hoist(b.Ref)
+	})
+}
+
+func (g *generatorLowering) tempRef() ast.Ref {
+	ref := g.p.generateTempRef(tempRefNoDeclare, "")
+	g.isHoisted[ref] = true
+	g.hoistedRefs = append(g.hoistedRefs, ref)
+	return ref
+}
+
+func (g *generatorLowering) ident(loc logger.Loc, ref ast.Ref) js_ast.Expr {
+	g.p.recordUsage(ref)
+	return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
+}
+
+// This evaluates the expression now and returns an expression that will
+// evaluate to the same value later on, after other code has run
+func (g *generatorLowering) spill(expr js_ast.Expr) js_ast.Expr {
+	switch expr.Data.(type) {
+	case *js_ast.EThis, *js_ast.ESuper, *js_ast.EFunction, *js_ast.EArrow:
+		return expr
+	}
+	if js_ast.IsPrimitiveLiteral(expr.Data) {
+		return expr
+	}
+	ref := g.tempRef()
+	g.emit(js_ast.AssignStmt(g.ident(expr.Loc, ref), expr))
+	return g.ident(expr.Loc, ref)
+}
+
+// Declarations are converted into assignments to hoisted variables
+func (g *generatorLowering) visitLocal(loc logger.Loc, s *js_ast.SLocal) {
+	if s.Kind.IsUsing() {
+		g.markUnsupported(loc, "\"using\" declarations inside generator functions")
+		return
+	}
+
+	var pending []js_ast.Expr
+	flush := func() {
+		if len(pending) > 0 {
+			g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: js_ast.JoinAllWithComma(pending)}})
+			pending = nil
+		}
+	}
+
+	for _, decl := range s.Decls {
+		// The captured "arguments" variable must refer to the outer function
+		if id, ok := decl.Binding.Data.(*js_ast.BIdentifier); ok && decl.ValueOrNil.Data != nil {
+			if ref := g.p.fnOnlyDataVisit.argumentsCaptureRef; ref != nil && id.Ref == *ref {
+				g.outerDecls = append(g.outerDecls, decl)
+				continue
+			}
+		}
+
+		g.hoistBinding(decl.Binding)
+		value := decl.ValueOrNil
+		if value.Data == nil {
+			// Block-scoped variables inside loops start over as undefined
+			if s.Kind == js_ast.LocalVar || g.loopDepth == 0 {
+				continue
+			}
+			value = js_ast.Expr{Loc: decl.Binding.Loc, Data: js_ast.EUndefinedShared}
+		}
+		target := js_ast.ConvertBindingToExpr(decl.Binding, nil)
+		if exprContainsYield(target) {
+			g.markUnsupported(target.Loc, "\"yield\" inside destructuring patterns")
+			continue
+		}
+		if exprContainsYield(value) {
+			flush()
+			value = g.visitExpr(value)
+		}
+		pending = append(pending, js_ast.Assign(target, value))
+	}
+
+	flush()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Statements
+
+func (g *generatorLowering) visitStmts(stmts []js_ast.Stmt) {
+	for _, stmt := range stmts {
+		g.visitStmt(stmt)
+	}
+}
+
+func (g *generatorLowering) visitStmt(stmt js_ast.Stmt) {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SFunction:
+		g.hoistedFns = append(g.hoistedFns, stmt)
+		return
+
+	case *js_ast.SLocal:
+		g.visitLocal(stmt.Loc, s)
+		return
+
+	case *js_ast.SClass:
+		if classContainsYield(&s.Class) {
+			g.markUnsupported(stmt.Loc, "\"yield\" inside classes")
+			return
+		}
+
+		// "class Foo {}" => "Foo = class Foo {}"
+		g.hoist(s.Class.Name.Ref)
+		g.emit(js_ast.AssignStmt(g.ident(s.Class.Name.Loc, s.Class.Name.Ref), js_ast.Expr{Loc: stmt.Loc, Data: &js_ast.EClass{Class: s.Class}}))
+		return
+	}
+
+	if !stmtContainsYield(stmt) {
+		g.emit(g.rewriteStmt(stmt, generatorRewriteContext{}))
+		return
+	}
+
+	switch s := stmt.Data.(type) {
+	case *js_ast.SExpr:
+		g.visitExprAndDiscard(s.Value)
+
+	case *js_ast.SBlock:
+		g.visitStmts(s.Stmts)
+
+	case *js_ast.SIf:
+		if !stmtContainsYield(s.Yes) && (s.NoOrNil.Data == nil || !stmtContainsYield(s.NoOrNil)) {
+			g.emit(g.rewriteStmt(js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SIf{
+				Test:    g.visitExpr(s.Test),
+				Yes:     s.Yes,
+				NoOrNil: s.NoOrNil,
+			}}, generatorRewriteContext{}))
+			return
+		}
+		elseLabel := g.newLabel()
+		endLabel := elseLabel
+		g.jumpIf(stmt.Loc, js_ast.Not(g.visitExpr(s.Test)), elseLabel)
+		g.visitStmt(s.Yes)
+		if s.NoOrNil.Data != nil {
+			endLabel = g.newLabel()
+			g.jump(stmt.Loc, endLabel)
+			g.markLabel(elseLabel)
+			g.visitStmt(s.NoOrNil)
+		}
+		g.markLabel(endLabel)
+
+	case *js_ast.SLabel:
+		labels := []ast.Ref{s.Name.Ref}
+		inner := s.Stmt
+		for {
+			if label, ok := inner.Data.(*js_ast.SLabel); ok {
+				labels = append(labels, label.Name.Ref)
+				inner = label.Stmt
+				continue
+			}
+			break
+		}
+		if !g.visitBreakable(inner, labels) {
+			endLabel := g.newLabel()
+			g.targets = append(g.targets, generatorJumpTarget{labels: labels, breakLabel: endLabel, continueLabel: -1})
+			g.visitStmt(inner)
+			g.targets = g.targets[:len(g.targets)-1]
+			g.markLabel(endLabel)
+		}
+
+	case *js_ast.STry:
+		g.visitTry(stmt.Loc, s)
+
+	case *js_ast.SReturn:
+		g.emit(js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SReturn{ValueOrNil: g.op(stmt.Loc, generatorOpReturn, g.visitExpr(s.ValueOrNil))}})
+
+	case *js_ast.SThrow:
+		g.emit(js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SThrow{Value: g.visitExpr(s.Value)}})
+
+	case *js_ast.SWith:
+		g.markUnsupported(stmt.Loc, "\"yield\" inside \"with\" statements")
+
+	default:
+		if !g.visitBreakable(stmt, nil) {
+			g.emit(stmt)
+		}
+	}
+}
+
+// This handles loops and "switch" statements, which are the statements that
+// unlabeled "break" statements can target
+func (g *generatorLowering) visitBreakable(stmt js_ast.Stmt, labels []ast.Ref) bool {
+	loc := stmt.Loc
+
+	switch s := stmt.Data.(type) {
+	case *js_ast.SWhile:
+		loopLabel := g.newLabel()
+		endLabel := g.newLabel()
+		g.markLabel(loopLabel)
+		g.jumpIf(loc, js_ast.Not(g.visitExpr(s.Test)), endLabel)
+		g.visitLoopBody(s.Body, labels, endLabel, loopLabel)
+		g.jump(loc, loopLabel)
+		g.markLabel(endLabel)
+
+	case *js_ast.SDoWhile:
+		loopLabel := g.newLabel()
+		continueLabel := g.newLabel()
+		endLabel := g.newLabel()
+		g.markLabel(loopLabel)
+		g.visitLoopBody(s.Body, labels, endLabel, continueLabel)
+		g.markLabel(continueLabel)
+		g.jumpIf(loc, g.visitExpr(s.Test), loopLabel)
+		g.markLabel(endLabel)
+
+	case *js_ast.SFor:
+		switch init := s.InitOrNil.Data.(type) {
+		case *js_ast.SLocal:
+			g.visitLocal(s.InitOrNil.Loc, init)
+		case *js_ast.SExpr:
+			g.visitExprAndDiscard(init.Value)
+		}
+		loopLabel := g.newLabel()
+		continueLabel := g.newLabel()
+		endLabel := g.newLabel()
+		g.markLabel(loopLabel)
+		if s.TestOrNil.Data != nil {
+			g.jumpIf(loc, js_ast.Not(g.visitExpr(s.TestOrNil)), endLabel)
+		}
+		g.visitLoopBody(s.Body, labels, endLabel, continueLabel)
+		g.markLabel(continueLabel)
+		if s.UpdateOrNil.Data != nil {
+			g.visitExprAndDiscard(s.UpdateOrNil)
+		}
+		g.jump(loc, loopLabel)
+		g.markLabel(endLabel)
+
+	case *js_ast.SForIn:
+		// The keys are collected up front since a "for-in" loop can't be
+		// suspended. Keys that are deleted during iteration are skipped:
+		//
+		//   keys = [];
+		//   for (key in obj) keys.push(key);
+		//   i = 0;
+		//   loop:
+		//     if (!(i < keys.length)) jump end;
+		//     key = keys[i];
+		//     if (!(key in obj)) jump continue;
+		//     ...
+		//   continue:
+		//     i++;
+		//     jump loop;
+		//   end:
+		//
+		obj := g.spill(g.visitExpr(s.Value))
+		keysRef := g.tempRef()
+		keyRef := g.tempRef()
+		indexRef := g.tempRef()
+		g.emit(js_ast.AssignStmt(g.ident(loc, keysRef), js_ast.Expr{Loc: loc, Data: &js_ast.EArray{IsSingleLine: true}}))
+		g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SForIn{
+			Init:  js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: g.ident(loc, keyRef)}},
+			Value: obj,
+			Body: js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+				Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{Target: g.ident(loc, keysRef), Name: "push", NameLoc: loc}},
+				Args:   []js_ast.Expr{g.ident(loc, keyRef)},
+				Kind:   js_ast.TargetWasOriginallyPropertyAccess,
+			}}}},
+		}})
+		g.emit(js_ast.AssignStmt(g.ident(loc, indexRef), js_ast.Expr{Loc: loc, Data: &js_ast.ENumber{Value: 0}}))
+		loopLabel := g.newLabel()
+		continueLabel := g.newLabel()
+		endLabel := g.newLabel()
+		g.markLabel(loopLabel)
+		g.jumpIf(loc, js_ast.Not(js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+			Op:    js_ast.BinOpLt,
+			Left:  g.ident(loc, indexRef),
+			Right: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{Target: g.ident(loc, keysRef), Name: "length", NameLoc: loc}},
+		}}), endLabel)
+		g.emit(js_ast.AssignStmt(g.ident(loc, keyRef), js_ast.Expr{Loc: loc, Data: &js_ast.EIndex{
+			Target: g.ident(loc, keysRef),
+			Index:  g.ident(loc, indexRef),
+		}}))
+		g.jumpIf(loc, js_ast.Not(js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+			Op:    js_ast.BinOpIn,
+			Left:  g.ident(loc, keyRef),
+			Right: obj,
+		}}), continueLabel)
+		g.visitForInit(s.Init, g.ident(loc, keyRef))
+		g.visitLoopBody(s.Body, labels, endLabel, continueLabel)
+		g.markLabel(continueLabel)
+		g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{
+			Op:    js_ast.UnOpPostInc,
+			Value: g.ident(loc, indexRef),
+		}}}})
+		g.jump(loc, loopLabel)
+		g.markLabel(endLabel)
+
+	case *js_ast.SForOf:
+		if s.Await.Len > 0 {
+			g.markUnsupported(s.Await.Loc, "for-await loops inside generator functions")
+			break
+		}
+
+		// Convert the loop into a "for" loop that uses the iterator directly
+		value := g.visitExpr(s.Value)
+		if g.symbolRef == ast.InvalidRef {
+			g.symbolRef = g.p.newSymbol(ast.SymbolUnbound, "Symbol")
+			g.p.moduleScope.Generated = append(g.p.moduleScope.Generated, g.symbolRef)
+		}
+		iter := js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+			Target: js_ast.Expr{Loc: loc, Data: &js_ast.EIndex{
+				Target: value,
+				Index: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+					Target:  g.ident(loc, g.symbolRef),
+					Name:    "iterator",
+					NameLoc: loc,
+				}},
+			}},
+			Kind: js_ast.TargetWasOriginallyPropertyAccess,
+		}}
+		tryStmt := g.p.lowerForOfLoopUsingIterator(loc, s, iter, false /* isAwait */)
+
+		// Move the labels onto the generated loop
+		try := tryStmt.Data.(*js_ast.STry)
+		for i := len(labels) - 1; i >= 0; i-- {
+			try.Block.Stmts[0] = js_ast.Stmt{Loc: loc, Data: &js_ast.SLabel{
+				Name: ast.LocRef{Loc: loc, Ref: labels[i]},
+				Stmt: try.Block.Stmts[0],
+			}}
+		}
+		g.visitStmt(tryStmt)
+
+	case *js_ast.SSwitch:
+		test := g.spill(g.visitExpr(s.Test))
+		caseLabels := make([]int, len(s.Cases))
+		defaultLabel := -1
+		for i, c := range s.Cases {
+			caseLabels[i] = g.newLabel()
+			if c.ValueOrNil.Data == nil {
+				defaultLabel = caseLabels[i]
+				continue
+			}
+			g.jumpIf(c.Loc, js_ast.Expr{Loc: c.Loc, Data: &js_ast.EBinary{
+				Op:    js_ast.BinOpStrictEq,
+				Left:  test,
+				Right: g.visitExpr(c.ValueOrNil),
+			}}, caseLabels[i])
+		}
+		endLabel := g.newLabel()
+		if defaultLabel != -1 {
+			g.jump(loc, defaultLabel)
+		} else {
+			g.jump(loc, endLabel)
+		}
+		g.targets = append(g.targets, generatorJumpTarget{labels: labels, breakLabel: endLabel, continueLabel: -1, isBreakable: true})
+		for i, c := range s.Cases {
+			g.markLabel(caseLabels[i])
+			g.visitStmts(c.Body)
+		}
+		g.targets = g.targets[:len(g.targets)-1]
+		g.markLabel(endLabel)
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (g *generatorLowering) visitLoopBody(body js_ast.Stmt, labels []ast.Ref, breakLabel int, continueLabel int) {
+	g.targets = append(g.targets, generatorJumpTarget{labels: labels, breakLabel: breakLabel, continueLabel: continueLabel, isBreakable: true})
+	g.loopDepth++
+	g.visitStmt(body)
+	g.loopDepth--
+	g.targets = g.targets[:len(g.targets)-1]
+}
+
+// This assigns the value for the current iteration to the loop variable
+func (g *generatorLowering) visitForInit(init js_ast.Stmt, value js_ast.Expr) {
+	switch s := init.Data.(type) {
+	case *js_ast.SLocal:
+		if len(s.Decls) == 1 {
+			g.visitLocal(init.Loc, &js_ast.SLocal{Kind: s.Kind, Decls: []js_ast.Decl{{Binding: s.Decls[0].Binding, ValueOrNil: value}}})
+		}
+	case *js_ast.SExpr:
+		g.visitExprAndDiscard(js_ast.Assign(s.Value, value))
+	}
+}
+
+func (g *generatorLowering) visitTry(loc logger.Loc, s *js_ast.STry) {
+	tryLabel := g.newLabel()
+	catchLabel := -1
+	finallyLabel := -1
+	if s.Catch != nil {
+		catchLabel = g.newLabel()
+	}
+	if s.Finally != nil {
+		finallyLabel = g.newLabel()
+	}
+	endLabel := g.newLabel()
+
+	// "_.trys.push([try, catch, finally, end])"
+	g.markLabel(tryLabel)
+	entry := []js_ast.Expr{g.labelExpr(loc, tryLabel)}
+	for _, label := range []int{catchLabel, finallyLabel} {
+		if label == -1 {
+			entry = append(entry, js_ast.Expr{Loc: loc, Data: js_ast.EMissingShared})
+		} else {
+			entry = append(entry, g.labelExpr(loc, label))
+		}
+	}
+	entry = append(entry, g.labelExpr(loc, endLabel))
+	g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: js_ast.Expr{Loc: loc, Data: &js_ast.ECall{
+		Target: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{Target: g.stateDot(loc, "trys"), Name: "push", NameLoc: loc}},
+		Args:   []js_ast.Expr{{Loc: loc, Data: &js_ast.EArray{Items: entry, IsSingleLine: true}}},
+		Kind:   js_ast.TargetWasOriginallyPropertyAccess,
+	}}}})
+
+	g.visitStmts(s.Block.Stmts)
+	g.jump(loc, endLabel)
+
+	if s.Catch != nil {
+		g.markLabel(catchLabel)
+		if s.Catch.BindingOrNil.Data != nil {
+			g.visitLocal(s.Catch.Loc, &js_ast.SLocal{Kind: js_ast.LocalLet, Decls: []js_ast.Decl{{
+				Binding:    s.Catch.BindingOrNil,
+				ValueOrNil: g.sent(s.Catch.Loc),
+			}}})
+		}
+		g.visitStmts(s.Catch.Block.Stmts)
+		g.jump(loc, endLabel)
+	}
+
+	if s.Finally != nil {
+		g.markLabel(finallyLabel)
+		g.visitStmts(s.Finally.Block.Stmts)
+		g.emit(js_ast.Stmt{Loc: s.Finally.Loc, Data: &js_ast.SReturn{ValueOrNil: g.op(s.Finally.Loc, generatorOpEndFinally, js_ast.Expr{})}})
+	}
+
+	g.markLabel(endLabel)
+}
+
+func (g *generatorLowering) findJumpTarget(label *ast.LocRef, isContinue bool) (int, bool) {
+	for i := len(g.targets) - 1; i >= 0; i-- {
+		target := g.targets[i]
+		if isContinue && target.continueLabel == -1 {
+			continue
+		}
+		if label == nil {
+			if target.isBreakable {
+				if isContinue {
+					return target.continueLabel, true
+				}
+				return target.breakLabel, true
+			}
+			continue
+		}
+		for _, ref := range target.labels {
+			if ref == label.Ref {
+				if isContinue {
+					return target.continueLabel, true
+				}
+				return target.breakLabel, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Statements that don't contain "yield" are kept as-is except for statements
+// that would leave the state machine body, which must be turned into
+// instructions for the runtime instead
+func (g *generatorLowering) rewriteStmt(stmt js_ast.Stmt, ctx generatorRewriteContext) js_ast.Stmt {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SReturn:
+		return js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SReturn{ValueOrNil: g.op(stmt.Loc, generatorOpReturn, s.ValueOrNil)}}
+
+	case *js_ast.SBreak:
+		if s.Label == nil && ctx.breakableDepth > 0 || s.Label != nil && ctx.hasLabel(s.Label.Ref) {
+			return stmt
+		}
+		if label, ok := g.findJumpTarget(s.Label, false); ok {
+			return g.jumpStmt(stmt.Loc, label)
+		}
+
+	case *js_ast.SContinue:
+		if s.Label == nil && ctx.loopDepth > 0 || s.Label != nil && ctx.hasLabel(s.Label.Ref) {
+			return stmt
+		}
+		if label, ok := g.findJumpTarget(s.Label, true); ok {
+			return g.jumpStmt(stmt.Loc, label)
+		}
+
+	case *js_ast.SLocal:
+		if s.Kind == js_ast.LocalVar {
+			return g.rewriteVar(stmt.Loc, s)
+		}
+
+	case *js_ast.SBlock:
+		for i, child := range s.Stmts {
+			s.Stmts[i] = g.rewriteStmt(child, ctx)
+		}
+
+	case *js_ast.SIf:
+		s.Yes = g.rewriteStmt(s.Yes, ctx)
+		if s.NoOrNil.Data != nil {
+			s.NoOrNil = g.rewriteStmt(s.NoOrNil, ctx)
+		}
+
+	case *js_ast.SLabel:
+		ctx.labels = append(ctx.labels, s.Name.Ref)
+		s.Stmt = g.rewriteStmt(s.Stmt, ctx)
+
+	case *js_ast.SFor:
+		if local, ok := s.InitOrNil.Data.(*js_ast.SLocal); ok && local.Kind == js_ast.LocalVar {
+			s.InitOrNil = g.rewriteVar(s.InitOrNil.Loc, local)
+			if _, ok := s.InitOrNil.Data.(*js_ast.SEmpty); ok {
+				s.InitOrNil = js_ast.Stmt{}
+			}
+		}
+		s.Body = g.rewriteStmt(s.Body, ctx.enterLoop())
+
+	case *js_ast.SForIn:
+		s.Init = g.rewriteForInOfInit(s.Init)
+		s.Body = g.rewriteStmt(s.Body, ctx.enterLoop())
+
+	case *js_ast.SForOf:
+		s.Init = g.rewriteForInOfInit(s.Init)
+		s.Body = g.rewriteStmt(s.Body, ctx.enterLoop())
+
+	case *js_ast.SWhile:
+		s.Body = g.rewriteStmt(s.Body, ctx.enterLoop())
+
+	case *js_ast.SDoWhile:
+		s.Body = g.rewriteStmt(s.Body, ctx.enterLoop())
+
+	case *js_ast.SSwitch:
+		ctx.breakableDepth++
+		for _, c := range s.Cases {
+			for i, child := range c.Body {
+				c.Body[i] = g.rewriteStmt(child, ctx)
+			}
+		}
+
+	case *js_ast.STry:
+		for i, child := range s.Block.Stmts {
+			s.Block.Stmts[i] = g.rewriteStmt(child, ctx)
+		}
+		if s.Catch != nil {
+			for i, child := range s.Catch.Block.Stmts {
+				s.Catch.Block.Stmts[i] = g.rewriteStmt(child, ctx)
+			}
+		}
+		if s.Finally != nil {
+			for i, child := range s.Finally.Block.Stmts {
+				s.Finally.Block.Stmts[i] = g.rewriteStmt(child, ctx)
+			}
+		}
+
+	case *js_ast.SWith:
+		s.Body = g.rewriteStmt(s.Body, ctx)
+	}
+
+	return stmt
+}
+
+func (ctx generatorRewriteContext) enterLoop() generatorRewriteContext {
+	ctx.loopDepth++
+	ctx.breakableDepth++
+	return ctx
+}
+
+func (ctx generatorRewriteContext) hasLabel(ref ast.Ref) bool {
+	for _, label := range ctx.labels {
+		if label == ref {
+			return true
+		}
+	}
+	return false
+}
+
+// "var" declarations are hoisted into the outer function: "var x = 1" => "x = 1"
+func (g *generatorLowering) rewriteVar(loc logger.Loc, s *js_ast.SLocal) js_ast.Stmt {
+	var assigns []js_ast.Expr
+	for _, decl := range s.Decls {
+		g.hoistBinding(decl.Binding)
+		if decl.ValueOrNil.Data != nil {
+			assigns = append(assigns, js_ast.Assign(js_ast.ConvertBindingToExpr(decl.Binding, nil), decl.ValueOrNil))
+		}
+	}
+	if len(assigns) == 0 {
+		return js_ast.Stmt{Loc: loc, Data: js_ast.SEmptyShared}
+	}
+	return js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: js_ast.JoinAllWithComma(assigns)}}
+}
+
+func (g *generatorLowering) rewriteForInOfInit(init js_ast.Stmt) js_ast.Stmt {
+	if local, ok := init.Data.(*js_ast.SLocal); ok && local.Kind == js_ast.LocalVar && len(local.Decls) == 1 {
+		g.hoistBinding(local.Decls[0].Binding)
+		return js_ast.Stmt{Loc: init.Loc, Data: &js_ast.SExpr{Value: js_ast.ConvertBindingToExpr(local.Decls[0].Binding, nil)}}
+	}
+	return init
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Expressions
+
+func (g *generatorLowering) visitExprAndDiscard(expr js_ast.Expr) {
+	if !exprContainsYield(expr) {
+		g.emit(js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: expr}})
+		return
+	}
+
+	switch e := expr.Data.(type) {
+	case *js_ast.EBinary:
+		switch e.Op {
+		case js_ast.BinOpComma:
+			g.visitExprAndDiscard(e.Left)
+			g.visitExprAndDiscard(e.Right)
+			return
+
+		case js_ast.BinOpLogicalAnd, js_ast.BinOpLogicalOr, js_ast.BinOpNullishCoalescing:
+			if exprContainsYield(e.Right) {
+				endLabel := g.newLabel()
+				g.jumpIf(expr.Loc, g.shortCircuitTest(e.Op, g.visitExpr(e.Left)), endLabel)
+				g.visitExprAndDiscard(e.Right)
+				g.markLabel(endLabel)
+				return
+			}
+		}
+
+	case *js_ast.EIf:
+		if exprContainsYield(e.Yes) || exprContainsYield(e.No) {
+			elseLabel := g.newLabel()
+			endLabel := g.newLabel()
+			g.jumpIf(expr.Loc, js_ast.Not(g.visitExpr(e.Test)), elseLabel)
+			g.visitExprAndDiscard(e.Yes)
+			g.jump(expr.Loc, endLabel)
+			g.markLabel(elseLabel)
+			g.visitExprAndDiscard(e.No)
+			g.markLabel(endLabel)
+			return
+		}
+	}
+
+	// Don't bother emitting an unused reference to a temporary variable
+	value := g.visitExpr(expr)
+	if id, ok := value.Data.(*js_ast.EIdentifier); ok && g.isHoisted[id.Ref] {
+		return
+	}
+	g.emit(js_ast.Stmt{Loc: expr.Loc, Data: &js_ast.SExpr{Value: value}})
+}
+
+// This returns an expression that is true if the right operand of a logical
+// operator should be skipped
+func (g *generatorLowering) shortCircuitTest(op js_ast.OpCode, left js_ast.Expr) js_ast.Expr {
+	switch op {
+	case js_ast.BinOpLogicalAnd, js_ast.BinOpLogicalAndAssign:
+		return js_ast.Not(left)
+	case js_ast.BinOpLogicalOr, js_ast.BinOpLogicalOrAssign:
+		return left
+	default:
+		return js_ast.Expr{Loc: left.Loc, Data: &js_ast.EBinary{
+			Op:    js_ast.BinOpLooseNe,
+			Left:  left,
+			Right: js_ast.Expr{Loc: left.Loc, Data: js_ast.ENullShared},
+		}}
+	}
+}
+
+// The statements for any "yield" expressions are emitted first, then an
+// expression is returned that evaluates to the value of the original
+// expression when it's placed after those statements
+func (g *generatorLowering) visitExpr(expr js_ast.Expr) js_ast.Expr {
+	if !exprContainsYield(expr) {
+		return expr
+	}
+	loc := expr.Loc
+
+	switch e := expr.Data.(type) {
+	case *js_ast.EYield:
+		op := generatorOpYield
+		if e.IsStar {
+			op = generatorOpYieldStar
+		}
+		var value js_ast.Expr
+		if e.ValueOrNil.Data != nil {
+			value = g.visitExpr(e.ValueOrNil)
+		}
+		g.emit(js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: g.op(loc, op, value)}})
+		g.markLabel(g.newLabel())
+		return g.sent(loc)
+
+	case *js_ast.EBinary:
+		switch e.Op {
+		case js_ast.BinOpComma:
+			g.visitExprAndDiscard(e.Left)
+			return g.visitExpr(e.Right)
+
+		case js_ast.BinOpLogicalAnd, js_ast.BinOpLogicalOr, js_ast.BinOpNullishCoalescing:
+			if !exprContainsYield(e.Right) {
+				e.Left = g.visitExpr(e.Left)
+				return expr
+			}
+			tempRef := g.tempRef()
+			endLabel := g.newLabel()
+			g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), g.visitExpr(e.Left)))
+			g.jumpIf(loc, g.shortCircuitTest(e.Op, g.ident(loc, tempRef)), endLabel)
+			g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), g.visitExpr(e.Right)))
+			g.markLabel(endLabel)
+			return g.ident(loc, tempRef)
+		}
+
+		if e.Op.BinaryAssignTarget() != js_ast.AssignTargetNone {
+			return g.visitAssign(loc, e)
+		}
+
+		g.visitExprsInOrder([]*js_ast.Expr{&e.Left, &e.Right})
+		return expr
+
+	case *js_ast.EIf:
+		if !exprContainsYield(e.Yes) && !exprContainsYield(e.No) {
+			e.Test = g.visitExpr(e.Test)
+			return expr
+		}
+		tempRef := g.tempRef()
+		elseLabel := g.newLabel()
+		endLabel := g.newLabel()
+		g.jumpIf(loc, js_ast.Not(g.visitExpr(e.Test)), elseLabel)
+		g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), g.visitExpr(e.Yes)))
+		g.jump(loc, endLabel)
+		g.markLabel(elseLabel)
+		g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), g.visitExpr(e.No)))
+		g.markLabel(endLabel)
+		return g.ident(loc, tempRef)
+
+	case *js_ast.EUnary:
+		if e.Op.UnaryAssignTarget() != js_ast.AssignTargetNone {
+			e.Value = g.visitAssignTarget(e.Value, false)
+		} else {
+			e.Value = g.visitExpr(e.Value)
+		}
+		return expr
+
+	case *js_ast.ECall:
+		if e.OptionalChain != js_ast.OptionalChainNone {
+			g.markUnsupported(loc, "\"yield\" inside optional chains")
+			return expr
+		}
+		return g.visitCall(loc, e)
+
+	case *js_ast.ENew:
+		exprs := []*js_ast.Expr{&e.Target}
+		for i := range e.Args {
+			exprs = append(exprs, argPtr(e.Args, i))
+		}
+		g.visitExprsInOrder(exprs)
+		return expr
+
+	case *js_ast.EDot:
+		if e.OptionalChain != js_ast.OptionalChainNone {
+			g.markUnsupported(loc, "\"yield\" inside optional chains")
+			return expr
+		}
+		e.Target = g.visitExpr(e.Target)
+		return expr
+
+	case *js_ast.EIndex:
+		if e.OptionalChain != js_ast.OptionalChainNone {
+			g.markUnsupported(loc, "\"yield\" inside optional chains")
+			return expr
+		}
+		g.visitExprsInOrder([]*js_ast.Expr{&e.Target, &e.Index})
+		return expr
+
+	case *js_ast.EArray:
+		exprs := make([]*js_ast.Expr, 0, len(e.Items))
+		for i := range e.Items {
+			exprs = append(exprs, argPtr(e.Items, i))
+		}
+		g.visitExprsInOrder(exprs)
+		return expr
+
+	case *js_ast.EObject:
+		var exprs []*js_ast.Expr
+		for i := range e.Properties {
+			property := &e.Properties[i]
+			if property.Flags.Has(js_ast.PropertyIsComputed) {
+				exprs = append(exprs, &property.Key)
+			}
+			if property.ValueOrNil.Data != nil {
+				exprs = append(exprs, &property.ValueOrNil)
+			}
+		}
+		g.visitExprsInOrder(exprs)
+		return expr
+
+	case *js_ast.ETemplate:
+		if e.TagOrNil.Data != nil {
+			g.markUnsupported(loc, "\"yield\" inside tagged template literals")
+			return expr
+		}
+		exprs := make([]*js_ast.Expr, 0, len(e.Parts))
+		for i := range e.Parts {
+			exprs = append(exprs, &e.Parts[i].Value)
+		}
+		g.visitExprsInOrder(exprs)
+		return expr
+
+	case *js_ast.EImportCall:
+		g.visitExprsInOrder([]*js_ast.Expr{&e.Expr, &e.OptionsOrNil})
+		return expr
+
+	case *js_ast.ESpread:
+		e.Value = g.visitExpr(e.Value)
+		return expr
+
+	case *js_ast.EAwait:
+		e.Value = g.visitExpr(e.Value)
+		return expr
+
+	case *js_ast.EInlinedEnum:
+		e.Value = g.visitExpr(e.Value)
+		return expr
+
+	case *js_ast.EAnnotation:
+		e.Value = g.visitExpr(e.Value)
+		return expr
+
+	case *js_ast.EClass:
+		g.markUnsupported(loc, "\"yield\" inside classes")
+		return expr
+
+	case *js_ast.EJSXElement:
+		g.markUnsupported(loc, "\"yield\" inside JSX elements")
+		return expr
+	}
+
+	return expr
+}
+
+func argPtr(exprs []js_ast.Expr, i int) *js_ast.Expr {
+	return &exprs[i]
+}
+
+// JavaScript evaluates operands from left to right. When a later operand
+// contains "yield", all earlier operands must be evaluated and stored before
+// the state machine is suspended.
+func (g *generatorLowering) visitExprsInOrder(exprs []*js_ast.Expr) {
+	last := -1
+	for i, expr := range exprs {
+		if expr.Data != nil && exprContainsYield(*expr) {
+			last = i
+		}
+	}
+	for i := 0; i < last; i++ {
+		if expr := exprs[i]; expr.Data != nil {
+			if spread, ok := expr.Data.(*js_ast.ESpread); ok {
+				spread.Value = g.spill(g.visitExpr(spread.Value))
+			} else {
+				*expr = g.spill(g.visitExpr(*expr))
+			}
+		}
+	}
+	if last != -1 {
+		*exprs[last] = g.visitExpr(*exprs[last])
+	}
+}
+
+func (g *generatorLowering) visitCall(loc logger.Loc, e *js_ast.ECall) js_ast.Expr {
+	argsContainYield := false
+	for _, arg := range e.Args {
+		if exprContainsYield(arg) {
+			argsContainYield = true
+			break
+		}
+	}
+
+	// Calls where only the target contains "yield" don't need to be split
+	if !argsContainYield {
+		switch target := e.Target.Data.(type) {
+		case *js_ast.EDot:
+			target.Target = g.visitExpr(target.Target)
+		case *js_ast.EIndex:
+			g.visitExprsInOrder([]*js_ast.Expr{&target.Target, &target.Index})
+		default:
+			e.Target = g.visitExpr(e.Target)
+		}
+		return js_ast.Expr{Loc: loc, Data: e}
+	}
+
+	// Property accesses must preserve the value of "this" for the call:
+	//
+	//   "a.b(yield)" => "_a = a, _b = _a.b, ... _b.call(_a, _.sent())"
+	//
+	var thisArg js_ast.Expr
+	switch target := e.Target.Data.(type) {
+	case *js_ast.EDot:
+		if target.OptionalChain == js_ast.OptionalChainNone {
+			thisArg = g.spill(g.visitExpr(target.Target))
+			target.Target = thisArg
+			e.Target = g.spill(e.Target)
+		}
+	case *js_ast.EIndex:
+		if target.OptionalChain == js_ast.OptionalChainNone {
+			g.visitExprsInOrder([]*js_ast.Expr{&target.Target, &target.Index})
+			thisArg = g.spill(target.Target)
+			target.Target = thisArg
+			target.Index = g.spill(target.Index)
+			e.Target = g.spill(e.Target)
+		}
+	}
+	if thisArg.Data == nil && e.Kind != js_ast.DirectEval {
+		e.Target = g.spill(g.visitExpr(e.Target))
+	}
+
+	exprs := make([]*js_ast.Expr, 0, len(e.Args))
+	for i := range e.Args {
+		exprs = append(exprs, argPtr(e.Args, i))
+	}
+	g.visitExprsInOrder(exprs)
+
+	if thisArg.Data != nil {
+		// "_b.call(_a, ...args)"
+		e.Target = js_ast.Expr{Loc: e.Target.Loc, Data: &js_ast.EDot{Target: e.Target, Name: "call", NameLoc: e.Target.Loc}}
+		e.Args = append([]js_ast.Expr{thisArg}, e.Args...)
+		e.Kind = js_ast.TargetWasOriginallyPropertyAccess
+	}
+	return js_ast.Expr{Loc: loc, Data: e}
+}
+
+// Property accesses in assignment targets are evaluated before the value
+func (g *generatorLowering) visitAssignTarget(target js_ast.Expr, mustSpill bool) js_ast.Expr {
+	switch t := target.Data.(type) {
+	case *js_ast.EDot:
+		if t.OptionalChain == js_ast.OptionalChainNone {
+			t.Target = g.visitExpr(t.Target)
+			if mustSpill {
+				t.Target = g.spill(t.Target)
+			}
+			return target
+		}
+
+	case *js_ast.EIndex:
+		if t.OptionalChain == js_ast.OptionalChainNone {
+			g.visitExprsInOrder([]*js_ast.Expr{&t.Target, &t.Index})
+			if mustSpill {
+				t.Target = g.spill(t.Target)
+				t.Index = g.spill(t.Index)
+			}
+			return target
+		}
+
+	case *js_ast.EIdentifier:
+		return target
+	}
+
+	if exprContainsYield(target) {
+		g.markUnsupported(target.Loc, "\"yield\" inside destructuring patterns")
+	}
+	return target
+}
+
+func (g *generatorLowering) visitAssign(loc logger.Loc, e *js_ast.EBinary) js_ast.Expr {
+	rightContainsYield := exprContainsYield(e.Right)
+
+	switch e.Op {
+	case js_ast.BinOpAssign:
+		e.Left = g.visitAssignTarget(e.Left, rightContainsYield)
+		e.Right = g.visitExpr(e.Right)
+		return js_ast.Expr{Loc: loc, Data: e}
+
+	case js_ast.BinOpLogicalAndAssign, js_ast.BinOpLogicalOrAssign, js_ast.BinOpNullishCoalescingAssign:
+		e.Left = g.visitAssignTarget(e.Left, rightContainsYield)
+		if !rightContainsYield {
+			return js_ast.Expr{Loc: loc, Data: e}
+		}
+
+		// "a.b ||= yield" => "_a = a, _b = _a.b; if (_b) jump end; _b = _a.b = _.sent(); end:"
+		tempRef := g.tempRef()
+		endLabel := g.newLabel()
+		g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), e.Left))
+		g.jumpIf(loc, g.shortCircuitTest(e.Op, g.ident(loc, tempRef)), endLabel)
+		g.emit(js_ast.AssignStmt(g.ident(loc, tempRef), js_ast.Assign(e.Left, g.visitExpr(e.Right))))
+		g.markLabel(endLabel)
+		return g.ident(loc, tempRef)
+	}
+
+	e.Left = g.visitAssignTarget(e.Left, rightContainsYield)
+	if !rightContainsYield {
+		return js_ast.Expr{Loc: loc, Data: e}
+	}
+
+	// The current value is read before the right operand is evaluated:
+	//
+	//   "a.b += yield" => "_a = a, _b = _a.b; ... _a.b = _b + _.sent()"
+	//
+	current := g.spill(e.Left)
+	return js_ast.Assign(e.Left, js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+		Op:    generatorCompoundAssignOps[e.Op],
+		Left:  current,
+		Right: g.visitExpr(e.Right),
+	}})
+}
+
+var generatorCompoundAssignOps = map[js_ast.OpCode]js_ast.OpCode{
+	js_ast.BinOpAddAssign:        js_ast.BinOpAdd,
+	js_ast.BinOpSubAssign:        js_ast.BinOpSub,
+	js_ast.BinOpMulAssign:        js_ast.BinOpMul,
+	js_ast.BinOpDivAssign:        js_ast.BinOpDiv,
+	js_ast.BinOpRemAssign:        js_ast.BinOpRem,
+	js_ast.BinOpPowAssign:        js_ast.BinOpPow,
+	js_ast.BinOpShlAssign:        js_ast.BinOpShl,
+	js_ast.BinOpShrAssign:        js_ast.BinOpShr,
+	js_ast.BinOpUShrAssign:       js_ast.BinOpUShr,
+	js_ast.BinOpBitwiseOrAssign:  js_ast.BinOpBitwiseOr,
+	js_ast.BinOpBitwiseAndAssign: js_ast.BinOpBitwiseAnd,
+	js_ast.BinOpBitwiseXorAssign: js_ast.BinOpBitwiseXor,
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Searching for "yield"
+
+// Note that these don't descend into nested functions since "yield" in a
+// nested function belongs to that function instead
+func stmtsContainYield(stmts []js_ast.Stmt) bool {
+	for _, stmt := range stmts {
+		if stmtContainsYield(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+func stmtContainsYield(stmt js_ast.Stmt) bool {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SBlock:
+		return stmtsContainYield(s.Stmts)
+
+	case *js_ast.SExpr:
+		return exprContainsYield(s.Value)
+
+	case *js_ast.SLocal:
+		for _, decl := range s.Decls {
+			if bindingContainsYield(decl.Binding) || exprContainsYield(decl.ValueOrNil) {
+				return true
+			}
+		}
+
+	case *js_ast.SIf:
+		return exprContainsYield(s.Test) || stmtContainsYield(s.Yes) || stmtContainsYield(s.NoOrNil)
+
+	case *js_ast.SFor:
+		return stmtContainsYield(s.InitOrNil) || exprContainsYield(s.TestOrNil) ||
+			exprContainsYield(s.UpdateOrNil) || stmtContainsYield(s.Body)
+
+	case *js_ast.SForIn:
+		return stmtContainsYield(s.Init) || exprContainsYield(s.Value) || stmtContainsYield(s.Body)
+
+	case *js_ast.SForOf:
+		return stmtContainsYield(s.Init) || exprContainsYield(s.Value) || stmtContainsYield(s.Body)
+
+	case *js_ast.SDoWhile:
+		return stmtContainsYield(s.Body) || exprContainsYield(s.Test)
+
+	case *js_ast.SWhile:
+		return exprContainsYield(s.Test) || stmtContainsYield(s.Body)
+
+	case *js_ast.SWith:
+		return exprContainsYield(s.Value) || stmtContainsYield(s.Body)
+
+	case *js_ast.SLabel:
+		return stmtContainsYield(s.Stmt)
+
+	case *js_ast.STry:
+		if stmtsContainYield(s.Block.Stmts) {
+			return true
+		}
+		if s.Catch != nil && (bindingContainsYield(s.Catch.BindingOrNil) || stmtsContainYield(s.Catch.Block.Stmts)) {
+			return true
+		}
+		if s.Finally != nil && stmtsContainYield(s.Finally.Block.Stmts) {
+			return true
+		}
+
+	case *js_ast.SSwitch:
+		if exprContainsYield(s.Test) {
+			return true
+		}
+		for _, c := range s.Cases {
+			if exprContainsYield(c.ValueOrNil) || stmtsContainYield(c.Body) {
+				return true
+			}
+		}
+
+	case *js_ast.SReturn:
+		return exprContainsYield(s.ValueOrNil)
+
+	case *js_ast.SThrow:
+		return exprContainsYield(s.Value)
+
+	case *js_ast.SClass:
+		return classContainsYield(&s.Class)
+	}
+
+	return false
+}
+
+func bindingContainsYield(binding js_ast.Binding) bool {
+	switch b := binding.Data.(type) {
+	case *js_ast.BArray:
+		for _, item := range b.Items {
+			if bindingContainsYield(item.Binding) || exprContainsYield(item.DefaultValueOrNil) {
+				return true
+			}
+		}
+
+	case *js_ast.BObject:
+		for _, property := range b.Properties {
+			if exprContainsYield(property.Key) || bindingContainsYield(property.Value) || exprContainsYield(property.DefaultValueOrNil) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func classContainsYield(class *js_ast.Class) bool {
+	if exprContainsYield(class.ExtendsOrNil) {
+		return true
+	}
+	for _, decorator := range class.Decorators {
+		if exprContainsYield(decorator.Value) {
+			return true
+		}
+	}
+	for _, property := range class.Properties {
+		if property.ClassStaticBlock == nil && propertyContainsYield(&property) {
+			return true
+		}
+	}
+	return false
+}
+
+func propertyContainsYield(property *js_ast.Property) bool {
+	if exprContainsYield(property.Key) || exprContainsYield(property.ValueOrNil) || exprContainsYield(property.InitializerOrNil) {
+		return true
+	}
+	for _, decorator := range property.Decorators {
+		if exprContainsYield(decorator.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+func exprsContainYield(exprs []js_ast.Expr) bool {
+	for _, expr := range exprs {
+		if exprContainsYield(expr) {
+			return true
+		}
+	}
+	return false
+}
+
+func exprContainsYield(expr js_ast.Expr) bool {
+	switch e := expr.Data.(type) {
+	case *js_ast.EYield:
+		return true
+
+	case *js_ast.EArray:
+		return exprsContainYield(e.Items)
+
+	case *js_ast.EUnary:
+		return exprContainsYield(e.Value)
+
+	case *js_ast.EBinary:
+		return exprContainsYield(e.Left) || exprContainsYield(e.Right)
+
+	case *js_ast.EIf:
+		return exprContainsYield(e.Test) || exprContainsYield(e.Yes) || exprContainsYield(e.No)
+
+	case *js_ast.ENew:
+		return exprContainsYield(e.Target) || exprsContainYield(e.Args)
+
+	case *js_ast.ECall:
+		return exprContainsYield(e.Target) || exprsContainYield(e.Args)
+
+	case *js_ast.EDot:
+		return exprContainsYield(e.Target)
+
+	case *js_ast.EIndex:
+		return exprContainsYield(e.Target) || exprContainsYield(e.Index)
+
+	case *js_ast.EObject:
+		for i := range e.Properties {
+			if propertyContainsYield(&e.Properties[i]) {
+				return true
+			}
+		}
+
+	case *js_ast.ESpread:
+		return exprContainsYield(e.Value)
+
+	case *js_ast.ETemplate:
+		if exprContainsYield(e.TagOrNil) {
+			return true
+		}
+		for _, part := range e.Parts {
+			if exprContainsYield(part.Value) {
+				return true
+			}
+		}
+
+	case *js_ast.EClass:
+		return classContainsYield(&e.Class)
+
+	case *js_ast.EJSXElement:
+		if exprContainsYield(e.TagOrNil) || exprsContainYield(e.NullableChildren) {
+			return true
+		}
+		for i := range e.Properties {
+			if propertyContainsYield(&e.Properties[i]) {
+				return true
+			}
+		}
+
+	case *js_ast.EImportCall:
+		return exprContainsYield(e.Expr) || exprContainsYield(e.OptionsOrNil)
+
+	case *js_ast.EAwait:
+		return exprContainsYield(e.Value)
+
+	case *js_ast.EInlinedEnum:
+		return exprContainsYield(e.Value)
+
+	case *js_ast.EAnnotation:
+		return exprContainsYield(e.Value)
+	}
+
+	return false
+}
@@ -0,0 +1,665 @@
+package js_parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/compat"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_lexer"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// These are the symbols that become function-scoped when "let" and "const"
+// are lowered to "var"
+func isBlockScopedSymbolKind(kind ast.SymbolKind) bool {
+	switch kind {
+	case ast.SymbolOther, ast.SymbolConst, ast.SymbolClass, ast.SymbolTSEnum, ast.SymbolTSNamespace:
+		return true
+	}
+	return false
+}
+
+// Lowering "let" and "const" to "var" moves block-scoped symbols into the
+// nearest enclosing function. Two symbols with the same name in sibling or
+// nested blocks would then collide, so each block-scoped symbol is also added
+// to the enclosing function scope. The renamer assigns names to a scope's
+// generated symbols after its members and before its child scopes, which
+// gives each of these symbols a name that's unique within the function.
+func (p *parser) hoistBlockScopedSymbolsForRenaming() {
+	var sortedMembers scopeMemberArray
+
+	for _, scope := range p.scopesForCurrentPart {
+		if scope.Kind != js_ast.ScopeBlock && scope.Kind != js_ast.ScopeCatchBinding {
+			continue
+		}
+
+		// Sort the members for determinism since the order affects naming
+		sortedMembers = sortedMembers[:0]
+		for _, member := range scope.Members {
+			if kind := p.symbols[member.Ref.InnerIndex].Kind; isBlockScopedSymbolKind(kind) {
+				sortedMembers = append(sortedMembers, member)
+			}
+		}
+		if len(sortedMembers) == 0 {
+			continue
+		}
+		sort.Sort(sortedMembers)
+
+		hoistingScope := scope.Parent
+		for !hoistingScope.Kind.StopsHoisting() {
+			hoistingScope = hoistingScope.Parent
+		}
+		for _, member := range sortedMembers {
+			hoistingScope.Generated = append(hoistingScope.Generated, member.Ref)
+
+			// Top-level symbols are renamed across the whole bundle instead
+			if hoistingScope == p.moduleScope {
+				p.declaredSymbols = append(p.declaredSymbols, js_ast.DeclaredSymbol{Ref: member.Ref, IsTopLevel: true})
+			}
+		}
+	}
+}
+
+// This tracks the information needed to lower a loop containing block-scoped
+// variables that are captured by a closure. Each iteration of such a loop
+// needs its own copy of these variables, which isn't possible with "var". So
+// the loop body is moved into a function that's called once per iteration:
+//
+//	// Original code
+//	for (let i = 0; i < 3; i++) fns.push(() => i)
+//
+//	// Lowered code
+//	var _loop = function(i) {
+//	  fns.push(function() { return i })
+//	};
+//	for (var i = 0; i < 3; i++) _loop(i);
+type letLoop struct {
+	outerScope *js_ast.Scope
+
+	// This is the index into "scopesForCurrentPart" of the first scope that
+	// belongs to the loop
+	firstScope int
+
+	// Labels that refer to this loop. These become plain breaks and continues.
+	ownLabelRefs []ast.Ref
+
+	// Variables declared in the header of a "for" loop that are assigned to
+	// inside the loop body. If they are passed to the loop function, their
+	// values must be copied back out at the end of each iteration so that the
+	// loop update expression sees them.
+	headRefs            []ast.Ref
+	headRefsWereMutated []bool
+	headRefsMutated     map[ast.Ref]bool
+	copyBackHeadRefs    bool
+
+	// These are used to detect code in the loop body that can't be moved into
+	// a function
+	oldThisUseCount      uint32
+	oldArgumentsUseCount uint32
+	oldAwaitOrYieldCount uint32
+	usesThis             bool
+	usesArguments        bool
+	usesAwaitOrYield     bool
+}
+
+func (p *parser) beginLetLoop(copyBackHeadRefs bool) *letLoop {
+	if !p.options.unsupportedJSFeatures.Has(compat.ConstAndLet) {
+		return nil
+	}
+
+	loop := &letLoop{
+		outerScope:       p.currentScope,
+		firstScope:       len(p.scopesForCurrentPart),
+		copyBackHeadRefs: copyBackHeadRefs,
+	}
+
+	// A labeled loop is visited inside the scope for its label
+	for scope := p.currentScope; scope.Kind == js_ast.ScopeLabel; scope = scope.Parent {
+		loop.ownLabelRefs = append(loop.ownLabelRefs, scope.Label.Ref)
+	}
+	return loop
+}
+
+func (p *parser) beforeLetLoopBody(loop *letLoop) {
+	// Temporarily clear the mutation flags of variables in the loop header so
+	// that we can tell if the loop body assigns to them
+	if loop.copyBackHeadRefs && p.currentScope != loop.outerScope {
+		for _, member := range p.currentScope.Members {
+			if symbol := &p.symbols[member.Ref.InnerIndex]; isBlockScopedSymbolKind(symbol.Kind) {
+				loop.headRefs = append(loop.headRefs, member.Ref)
+				loop.headRefsWereMutated = append(loop.headRefsWereMutated, symbol.Flags.Has(ast.CouldPotentiallyBeMutated))
+				symbol.Flags &= ^ast.CouldPotentiallyBeMutated
+			}
+		}
+	}
+
+	loop.oldThisUseCount = p.fnOnlyDataVisit.thisUseCount
+	loop.oldArgumentsUseCount = p.fnOnlyDataVisit.argumentsUseCount
+	loop.oldAwaitOrYieldCount = p.fnOrArrowDataVisit.awaitOrYieldCount
+}
+
+func (p *parser) afterLetLoopBody(loop *letLoop) {
+	for i, ref := range loop.headRefs {
+		symbol := &p.symbols[ref.InnerIndex]
+		if symbol.Flags.Has(ast.CouldPotentiallyBeMutated) {
+			if loop.headRefsMutated == nil {
+				loop.headRefsMutated = make(map[ast.Ref]bool)
+			}
+			loop.headRefsMutated[ref] = true
+		}
+		if loop.headRefsWereMutated[i] {
+			symbol.Flags |= ast.CouldPotentiallyBeMutated
+		}
+	}
+
+	loop.usesThis = p.fnOnlyDataVisit.thisUseCount != loop.oldThisUseCount
+	loop.usesArguments = p.fnOnlyDataVisit.argumentsUseCount != loop.oldArgumentsUseCount
+	loop.usesAwaitOrYield = p.fnOrArrowDataVisit.awaitOrYieldCount != loop.oldAwaitOrYieldCount
+}
+
+// This must be called after the loop has been visited and after any other
+// lowering of the loop header has been done. If the loop body needs to be
+// moved into a function, the declaration of that function is appended to
+// "stmts" and the loop body is replaced with a call to that function. Note
+// that "initOrNil" must be the loop header from before any variables in it
+// were relocated to the top level when bundling.
+func (p *parser) lowerLetLoop(loc logger.Loc, loop *letLoop, initOrNil js_ast.Stmt, body *js_ast.Stmt, stmts []js_ast.Stmt) []js_ast.Stmt {
+	if loop == nil {
+		return stmts
+	}
+
+	// Find the block-scoped variables declared inside the loop that are
+	// captured by a closure. Variables declared inside nested functions aren't
+	// relevant, and variables declared inside nested loops were already handled
+	// when those loops were lowered.
+	var captured []js_ast.ScopeMember
+	for _, scope := range p.scopesForCurrentPart[loop.firstScope:] {
+		if scope.Kind != js_ast.ScopeBlock || len(scope.Members) == 0 {
+			continue
+		}
+		isInsideFunction := false
+		for s := scope; s != loop.outerScope; s = s.Parent {
+			if s.Kind.StopsHoisting() || s.Kind == js_ast.ScopeClassBody {
+				isInsideFunction = true
+				break
+			}
+		}
+		if isInsideFunction {
+			continue
+		}
+		for _, member := range scope.Members {
+			if p.letsCapturedByClosures[member.Ref] {
+				captured = append(captured, member)
+			}
+		}
+	}
+	if len(captured) == 0 {
+		return stmts
+	}
+	sort.Sort(scopeMemberArray(captured))
+	isCaptured := make(map[ast.Ref]bool, len(captured))
+	for _, member := range captured {
+		isCaptured[member.Ref] = true
+		delete(p.letsCapturedByClosures, member.Ref)
+	}
+
+	// Some things can't be moved into a function
+	var r logger.Range
+	var text string
+	if loop.usesAwaitOrYield {
+		r = p.fnOrArrowDataVisit.awaitOrYieldRange
+		text = p.source.TextForRange(r)
+	} else if loop.usesArguments && p.options.unsupportedJSFeatures.Has(compat.Arrow) {
+		r = js_lexer.RangeOfIdentifier(p.source, p.fnOnlyDataVisit.argumentsUseLoc)
+		text = "arguments"
+	}
+	if text != "" {
+		member := captured[0]
+		name := p.symbols[member.Ref.InnerIndex].OriginalName
+		p.log.AddErrorWithNotes(&p.tracker, r, fmt.Sprintf(
+			"Transforming %q inside this loop to the configured target environment is not supported yet", text),
+			[]logger.MsgData{p.tracker.MsgData(js_lexer.RangeOfIdentifier(p.source, member.Loc), fmt.Sprintf(
+				"The loop body must be moved into a function because the variable %q is captured by a closure:", name))})
+		return stmts
+	}
+
+	// Only variables declared in the loop header need to be passed to the loop
+	// function. Everything else is declared inside the loop function itself.
+	var params []ast.Ref
+	if local, ok := initOrNil.Data.(*js_ast.SLocal); ok {
+		for _, decl := range local.Decls {
+			if id, ok := decl.Binding.Data.(*js_ast.BIdentifier); ok && isCaptured[id.Ref] {
+				params = append(params, id.Ref)
+			}
+		}
+	}
+
+	// Variables in the header of a "for" loop that are assigned to inside the
+	// loop body must have their values copied back out of the loop function
+	var copies []letLoopCopy
+	for _, ref := range params {
+		if loop.headRefsMutated[ref] {
+			copies = append(copies, letLoopCopy{
+				ref:     ref,
+				tempRef: p.generateTempRef(tempRefNoDeclare, "_"+p.symbols[ref.InnerIndex].OriginalName),
+			})
+		}
+	}
+
+	// When bundling, block-scoped variables declared in the loop body may have
+	// been relocated to the top level. Each iteration needs its own copy of the
+	// captured ones, so declare them inside the loop function instead.
+	var bodyLocals []js_ast.Decl
+	if len(p.relocatedTopLevelVars) > 0 {
+		isParam := make(map[ast.Ref]bool, len(params))
+		for _, ref := range params {
+			isParam[ref] = true
+		}
+		end := 0
+		for _, local := range p.relocatedTopLevelVars {
+			if isCaptured[local.Ref] && !isParam[local.Ref] {
+				bodyLocals = append(bodyLocals, js_ast.Decl{Binding: js_ast.Binding{Loc: local.Loc, Data: &js_ast.BIdentifier{Ref: local.Ref}}})
+				continue
+			}
+			p.relocatedTopLevelVars[end] = local
+			end++
+		}
+		p.relocatedTopLevelVars = p.relocatedTopLevelVars[:end]
+	}
+
+	// Rewrite the loop body
+	v := letLoopBodyVisitor{p: p, loop: loop, copies: copies}
+	var bodyStmts []js_ast.Stmt
+	if block, ok := body.Data.(*js_ast.SBlock); ok {
+		bodyStmts = block.Stmts
+	} else {
+		bodyStmts = []js_ast.Stmt{*body}
+	}
+	bodyStmts = append(v.visitStmts(bodyStmts), v.copyOutStmts(body.Loc)...)
+	if len(bodyLocals) > 0 {
+		bodyStmts = append([]js_ast.Stmt{{Loc: body.Loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: bodyLocals}}}, bodyStmts...)
+	}
+
+	// Generate the loop function
+	loopRef := p.generateTempRef(tempRefNoDeclare, "_loop")
+	args := make([]js_ast.Arg, 0, len(params))
+	for _, ref := range params {
+		args = append(args, js_ast.Arg{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: ref}}})
+	}
+	fnBody := js_ast.FnBody{Loc: body.Loc, Block: js_ast.SBlock{Stmts: bodyStmts}}
+	var fn js_ast.Expr
+	if p.options.unsupportedJSFeatures.Has(compat.Arrow) {
+		fn = js_ast.Expr{Loc: loc, Data: &js_ast.EFunction{Fn: js_ast.Fn{Args: args, Body: fnBody}}}
+	} else {
+		fn = js_ast.Expr{Loc: loc, Data: &js_ast.EArrow{Args: args, Body: fnBody}}
+	}
+	decls := []js_ast.Decl{{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: loopRef}}, ValueOrNil: fn}}
+	for _, c := range copies {
+		decls = append(decls, js_ast.Decl{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: c.tempRef}}})
+	}
+	for _, ref := range v.hoistedVars {
+		decls = append(decls, js_ast.Decl{Binding: js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: ref}}})
+	}
+	stmts = append(stmts, js_ast.Stmt{Loc: loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: decls}})
+
+	// Generate the call to the loop function
+	p.recordUsage(loopRef)
+	var target js_ast.Expr
+	callArgs := make([]js_ast.Expr, 0, len(params)+1)
+	if loop.usesThis && p.options.unsupportedJSFeatures.Has(compat.Arrow) {
+		p.recordUsage(loopRef)
+		target = js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+			Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: loopRef}},
+			Name:    "call",
+			NameLoc: loc,
+		}}
+		callArgs = append(callArgs, js_ast.Expr{Loc: loc, Data: js_ast.EThisShared})
+	} else {
+		target = js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: loopRef}}
+	}
+	for _, ref := range params {
+		p.recordUsage(ref)
+		callArgs = append(callArgs, js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}})
+	}
+	call := js_ast.Expr{Loc: loc, Data: &js_ast.ECall{Target: target, Args: callArgs, Kind: js_ast.TargetWasOriginallyPropertyAccess}}
+
+	// Generate code to handle control flow that leaves the loop function
+	var newBody []js_ast.Stmt
+	if len(v.exits) == 0 && !v.hasReturn {
+		newBody = append(newBody, js_ast.Stmt{Loc: loc, Data: &js_ast.SExpr{Value: call}})
+		newBody = append(newBody, v.copyInStmts(loc)...)
+	} else {
+		retRef := p.generateTempRef(tempRefNoDeclare, "_ret")
+		p.recordDeclaredTempRef(retRef)
+		newBody = append(newBody, js_ast.Stmt{Loc: loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: []js_ast.Decl{{
+			Binding:    js_ast.Binding{Loc: loc, Data: &js_ast.BIdentifier{Ref: retRef}},
+			ValueOrNil: call,
+		}}}})
+		newBody = append(newBody, v.copyInStmts(loc)...)
+		for _, exit := range v.exits {
+			p.recordUsage(retRef)
+			var jump js_ast.Stmt
+			if exit.labelRef == ast.InvalidRef {
+				jump = js_ast.Stmt{Loc: loc, Data: &js_ast.SBreak{}}
+			} else if exit.isContinue {
+				p.recordUsage(exit.labelRef)
+				jump = js_ast.Stmt{Loc: loc, Data: &js_ast.SContinue{Label: &ast.LocRef{Loc: loc, Ref: exit.labelRef}}}
+			} else {
+				p.recordUsage(exit.labelRef)
+				jump = js_ast.Stmt{Loc: loc, Data: &js_ast.SBreak{Label: &ast.LocRef{Loc: loc, Ref: exit.labelRef}}}
+			}
+			newBody = append(newBody, js_ast.Stmt{Loc: loc, Data: &js_ast.SIf{
+				Test: js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+					Op:    js_ast.BinOpStrictEq,
+					Left:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: retRef}},
+					Right: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(exit.value)}},
+				}},
+				Yes: jump,
+			}})
+		}
+		if v.hasReturn {
+			p.recordUsage(retRef)
+			p.recordUsage(retRef)
+			newBody = append(newBody, js_ast.Stmt{Loc: loc, Data: &js_ast.SIf{
+				Test: js_ast.Expr{Loc: loc, Data: &js_ast.EBinary{
+					Op:    js_ast.BinOpStrictEq,
+					Left:  js_ast.Expr{Loc: loc, Data: &js_ast.EUnary{Op: js_ast.UnOpTypeof, Value: js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: retRef}}}},
+					Right: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("object")}},
+				}},
+				Yes: js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EDot{
+					Target:  js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: retRef}},
+					Name:    "v",
+					NameLoc: loc,
+				}}}},
+			}})
+		}
+	}
+	*body = js_ast.Stmt{Loc: body.Loc, Data: &js_ast.SBlock{Stmts: newBody}}
+
+	p.recordDeclaredTempRef(loopRef)
+	for _, c := range copies {
+		p.recordDeclaredTempRef(c.tempRef)
+	}
+	return stmts
+}
+
+type letLoopCopy struct {
+	ref     ast.Ref
+	tempRef ast.Ref
+}
+
+type letLoopExit struct {
+	value      string
+	labelRef   ast.Ref
+	isContinue bool
+}
+
+// This rewrites the statements in a loop body that is being moved into a
+// function. It doesn't traverse into nested functions or classes since
+// control flow can't cross those boundaries.
+type letLoopBodyVisitor struct {
+	p           *parser
+	loop        *letLoop
+	copies      []letLoopCopy
+	exits       []letLoopExit
+	hoistedVars []ast.Ref
+	innerLabels map[ast.Ref]bool
+	loopDepth   int
+	switchDepth int
+	hasReturn   bool
+}
+
+// "_i = i" at the end of each iteration inside the loop function
+func (v *letLoopBodyVisitor) copyOutStmts(loc logger.Loc) []js_ast.Stmt {
+	var stmts []js_ast.Stmt
+	for _, c := range v.copies {
+		v.p.recordUsage(c.tempRef)
+		v.p.recordUsage(c.ref)
+		stmts = append(stmts, js_ast.AssignStmt(
+			js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: c.tempRef}},
+			js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: c.ref}},
+		))
+	}
+	return stmts
+}
+
+// "i = _i" after each call to the loop function
+func (v *letLoopBodyVisitor) copyInStmts(loc logger.Loc) []js_ast.Stmt {
+	var stmts []js_ast.Stmt
+	for _, c := range v.copies {
+		v.p.recordUsage(c.ref)
+		v.p.recordUsage(c.tempRef)
+		stmts = append(stmts, js_ast.AssignStmt(
+			js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: c.ref}},
+			js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: c.tempRef}},
+		))
+	}
+	return stmts
+}
+
+func (v *letLoopBodyVisitor) exit(loc logger.Loc, value string, labelRef ast.Ref, isContinue bool) js_ast.Stmt {
+	found := false
+	for _, exit := range v.exits {
+		if exit.value == value {
+			found = true
+			break
+		}
+	}
+	if !found {
+		v.exits = append(v.exits, letLoopExit{value: value, labelRef: labelRef, isContinue: isContinue})
+	}
+	return js_ast.Stmt{Loc: loc, Data: &js_ast.SReturn{ValueOrNil: js_ast.Expr{Loc: loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(value)}}}}
+}
+
+func (v *letLoopBodyVisitor) isOwnLabel(ref ast.Ref) bool {
+	for _, labelRef := range v.loop.ownLabelRefs {
+		if labelRef == ref {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *letLoopBodyVisitor) visitStmts(stmts []js_ast.Stmt) []js_ast.Stmt {
+	result := make([]js_ast.Stmt, 0, len(stmts))
+	for _, stmt := range stmts {
+		result = v.visitStmt(result, stmt)
+	}
+	return result
+}
+
+func (v *letLoopBodyVisitor) visitSingleStmt(stmt js_ast.Stmt) js_ast.Stmt {
+	stmts := v.visitStmt(nil, stmt)
+	if len(stmts) == 1 {
+		return stmts[0]
+	}
+	return stmtsToSingleStmt(stmt.Loc, stmts, logger.Loc{})
+}
+
+func (v *letLoopBodyVisitor) visitLoopBody(stmt js_ast.Stmt) js_ast.Stmt {
+	v.loopDepth++
+	stmt = v.visitSingleStmt(stmt)
+	v.loopDepth--
+	return stmt
+}
+
+// Returns true if this is a "var" declaration from the original code. Those
+// must stay in the enclosing function instead of moving into the loop
+// function. Variables that were originally "let" or "const" are different.
+func (v *letLoopBodyVisitor) isHoistedDecl(decl js_ast.Decl) (isHoisted bool) {
+	js_ast.ForEachIdentifierBinding(decl.Binding, func(loc logger.Loc, b *js_ast.BIdentifier) {
+		if v.p.symbols[b.Ref.InnerIndex].Kind == ast.SymbolHoisted {
+			isHoisted = true
+		}
+	})
+	return
+}
+
+// "var a = 1" => "a = 1"
+func (v *letLoopBodyVisitor) hoistDecl(decl js_ast.Decl) js_ast.Expr {
+	return js_ast.ConvertBindingToExpr(decl.Binding, func(loc logger.Loc, ref ast.Ref) js_ast.Expr {
+		v.hoistedVars = append(v.hoistedVars, ref)
+		v.p.recordUsage(ref)
+		return js_ast.Expr{Loc: loc, Data: &js_ast.EIdentifier{Ref: ref}}
+	})
+}
+
+func (v *letLoopBodyVisitor) hoistForLoopInit(init js_ast.Stmt, isInOrOf bool) js_ast.Stmt {
+	local, ok := init.Data.(*js_ast.SLocal)
+	if !ok || local.Kind != js_ast.LocalVar || len(local.Decls) == 0 || !v.isHoistedDecl(local.Decls[0]) {
+		return init
+	}
+	var value js_ast.Expr
+	for _, decl := range local.Decls {
+		target := v.hoistDecl(decl)
+		if !isInOrOf {
+			if decl.ValueOrNil.Data == nil {
+				continue
+			}
+			target = js_ast.Assign(target, decl.ValueOrNil)
+		}
+		value = js_ast.JoinWithComma(value, target)
+	}
+	if value.Data == nil {
+		return js_ast.Stmt{}
+	}
+	return js_ast.Stmt{Loc: init.Loc, Data: &js_ast.SExpr{Value: value}}
+}
+
+func (v *letLoopBodyVisitor) visitStmt(stmts []js_ast.Stmt, stmt js_ast.Stmt) []js_ast.Stmt {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SBlock:
+		s.Stmts = v.visitStmts(s.Stmts)
+
+	case *js_ast.SIf:
+		s.Yes = v.visitSingleStmt(s.Yes)
+		if s.NoOrNil.Data != nil {
+			s.NoOrNil = v.visitSingleStmt(s.NoOrNil)
+		}
+
+	case *js_ast.SWith:
+		s.Body = v.visitSingleStmt(s.Body)
+
+	case *js_ast.SLabel:
+		if v.innerLabels == nil {
+			v.innerLabels = make(map[ast.Ref]bool)
+		}
+		v.innerLabels[s.Name.Ref] = true
+		s.Stmt = v.visitSingleStmt(s.Stmt)
+		delete(v.innerLabels, s.Name.Ref)
+
+	case *js_ast.STry:
+		s.Block.Stmts = v.visitStmts(s.Block.Stmts)
+		if s.Catch != nil {
+			s.Catch.Block.Stmts = v.visitStmts(s.Catch.Block.Stmts)
+		}
+		if s.Finally != nil {
+			s.Finally.Block.Stmts = v.visitStmts(s.Finally.Block.Stmts)
+		}
+
+	case *js_ast.SSwitch:
+		v.switchDepth++
+		for i := range s.Cases {
+			c := &s.Cases[i]
+			c.Body = v.visitStmts(c.Body)
+		}
+		v.switchDepth--
+
+	case *js_ast.SWhile:
+		s.Body = v.visitLoopBody(s.Body)
+
+	case *js_ast.SDoWhile:
+		s.Body = v.visitLoopBody(s.Body)
+
+	case *js_ast.SFor:
+		if s.InitOrNil.Data != nil {
+			s.InitOrNil = v.hoistForLoopInit(s.InitOrNil, false)
+		}
+		s.Body = v.visitLoopBody(s.Body)
+
+	case *js_ast.SForIn:
+		s.Init = v.hoistForLoopInit(s.Init, true)
+		s.Body = v.visitLoopBody(s.Body)
+
+	case *js_ast.SForOf:
+		s.Init = v.hoistForLoopInit(s.Init, true)
+		s.Body = v.visitLoopBody(s.Body)
+
+	case *js_ast.SLocal:
+		if s.Kind != js_ast.LocalVar {
+			break
+		}
+
+		// Split the declarations into ones that stay in the loop function and
+		// ones that are turned into assignments to a variable that's declared
+		// outside of the loop function, while preserving evaluation order
+		var decls []js_ast.Decl
+		var value js_ast.Expr
+		flush := func() {
+			if len(decls) > 0 {
+				stmts = append(stmts, js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SLocal{Kind: js_ast.LocalVar, Decls: decls}})
+				decls = nil
+			}
+			if value.Data != nil {
+				stmts = append(stmts, js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SExpr{Value: value}})
+				value = js_ast.Expr{}
+			}
+		}
+		for _, decl := range s.Decls {
+			if !v.isHoistedDecl(decl) {
+				if value.Data != nil {
+					flush()
+				}
+				decls = append(decls, decl)
+			} else if target := v.hoistDecl(decl); decl.ValueOrNil.Data != nil {
+				if len(decls) > 0 {
+					flush()
+				}
+				value = js_ast.JoinWithComma(value, js_ast.Assign(target, decl.ValueOrNil))
+			}
+		}
+		flush()
+		return stmts
+
+	case *js_ast.SBreak:
+		if s.Label == nil {
+			if v.loopDepth == 0 && v.switchDepth == 0 {
+				return append(stmts, v.exit(stmt.Loc, "break", ast.InvalidRef, false))
+			}
+		} else if v.isOwnLabel(s.Label.Ref) {
+			return append(stmts, v.exit(stmt.Loc, "break", ast.InvalidRef, false))
+		} else if !v.innerLabels[s.Label.Ref] {
+			name := v.p.symbols[s.Label.Ref.InnerIndex].OriginalName
+			return append(stmts, v.exit(stmt.Loc, "break-"+name, s.Label.Ref, false))
+		}
+
+	case *js_ast.SContinue:
+		if (s.Label == nil && v.loopDepth == 0) || (s.Label != nil && v.isOwnLabel(s.Label.Ref)) {
+			ret := js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SReturn{}}
+			if len(v.copies) == 0 {
+				return append(stmts, ret)
+			}
+			return append(stmts, js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SBlock{Stmts: append(v.copyOutStmts(stmt.Loc), ret)}})
+		} else if s.Label != nil && !v.innerLabels[s.Label.Ref] {
+			name := v.p.symbols[s.Label.Ref.InnerIndex].OriginalName
+			return append(stmts, v.exit(stmt.Loc, "continue-"+name, s.Label.Ref, true))
+		}
+
+	case *js_ast.SReturn:
+		// "return x" => "return { v: x }"
+		value := s.ValueOrNil
+		if value.Data == nil {
+			value = js_ast.Expr{Loc: stmt.Loc, Data: js_ast.EUndefinedShared}
+		}
+		s.ValueOrNil = js_ast.Expr{Loc: stmt.Loc, Data: &js_ast.EObject{Properties: []js_ast.Property{{
+			Key:        js_ast.Expr{Loc: stmt.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16("v")}},
+			ValueOrNil: value,
+		}}}}
+		v.hasReturn = true
+	}
+
+	return append(stmts, stmt)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/evanw/esbuild/internal/compat"
+	"github.com/evanw/esbuild/internal/config"
 )
 
 func TestLowerFunctionArgumentScope(t *testing.T) {
@@ -734,17 +735,15 @@ func TestAsyncGeneratorFns(t *testing.T) {
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncAwait, "(async function () {});", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncAwait, "({ async foo() {} });", err)
 
-	err = "<stdin>: ERROR: Transforming generator functions to the configured target environment is not supported yet\n"
+	// These can be lowered to a state machine
 	expectParseErrorWithUnsupportedFeatures(t, compat.Generator, "function* gen() {}", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.Generator, "(function* () {});", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.Generator, "({ *foo() {} });", err)
-
-	err = "<stdin>: ERROR: Transforming async functions to the configured target environment is not supported yet\n"
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncAwait|compat.Generator, "async function gen() {}", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncAwait|compat.Generator, "(async function () {});", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncAwait|compat.Generator, "({ async foo() {} });", err)
+	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncGenerator|compat.Generator, "async function* gen() {}", err)
 
-	err = ""
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncGenerator, "async function* gen() {}", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncGenerator, "(async function* () {});", err)
 	expectParseErrorWithUnsupportedFeatures(t, compat.AsyncGenerator, "({ async *foo() {} });", err)
@@ -761,10 +760,8 @@ func TestForAwait(t *testing.T) {
 	// This is ok because for-await can be lowered to yield
 	expectParseErrorWithUnsupportedFeatures(t, compat.ForAwait|compat.AsyncAwait, "async function gen() { for await (x of y) ; }", err)
 
-	// This is not ok because for-await can't be lowered
-	err =
-		"<stdin>: ERROR: Transforming async functions to the configured target environment is not supported yet\n" +
-			"<stdin>: ERROR: Transforming for-await loops to the configured target environment is not supported yet\n"
+	// This is ok because for-await can be lowered to yield, which can then be
+	// lowered to a state machine
 	expectParseErrorWithUnsupportedFeatures(t, compat.ForAwait|compat.AsyncAwait|compat.Generator, "async function gen() { for await (x of y) ; }", err)
 
 	// Can't use for-await at the top-level without top-level await
@@ -777,6 +774,511 @@ func TestForAwait(t *testing.T) {
 			"<stdin>: NOTE: This file is considered to be an ECMAScript module because of the top-level \"await\" keyword here:\n")
 }
 
+func TestLowerGenerator(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { return 1 }",
+		"function foo() {\n  return __stateMachine(this, function(_) {\n    return [2, 1];\n  });\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo(x) { let y = yield x; return y + 1 }",
+		`function foo(x) {
+  var y;
+  return __stateMachine(this, function(_) {
+    switch (_.label) {
+      case 0:
+        return [4, x];
+      case 1:
+        y = _.sent();
+        return [2, y + 1];
+    }
+  });
+}
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { while (a) { yield b; if (c) break; if (d) continue } }",
+		`function foo() {
+  return __stateMachine(this, function(_) {
+    switch (_.label) {
+      case 0:
+        if (!a)
+          return [3, 2];
+        return [4, b];
+      case 1:
+        _.sent();
+        if (c)
+          return [3, 2];
+        if (d)
+          return [3, 0];
+        return [3, 0];
+      case 2:
+        return [2];
+    }
+  });
+}
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { try { yield 1 } catch (e) { yield e } finally { bar() } }",
+		`function foo() {
+  var e;
+  return __stateMachine(this, function(_) {
+    switch (_.label) {
+      case 0:
+        _.trys.push([0, 2, 4, 5]);
+        return [4, 1];
+      case 1:
+        _.sent();
+        return [3, 5];
+      case 2:
+        e = _.sent();
+        return [4, e];
+      case 3:
+        _.sent();
+        return [3, 5];
+      case 4:
+        bar();
+        return [7];
+      case 5:
+        return [2];
+    }
+  });
+}
+`)
+
+	// Operands that are evaluated before a "yield" must be saved
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { a.b(c, yield d) }",
+		`function foo() {
+  var _a, _b, _c;
+  return __stateMachine(this, function(_) {
+    switch (_.label) {
+      case 0:
+        _a = a;
+        _b = _a.b;
+        _c = c;
+        return [4, d];
+      case 1:
+        _b.call(_a, _c, _.sent());
+        return [2];
+    }
+  });
+}
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { x = a || (yield b) }",
+		`function foo() {
+  var _a;
+  return __stateMachine(this, function(_) {
+    switch (_.label) {
+      case 0:
+        _a = a;
+        if (_a)
+          return [3, 2];
+        return [4, b];
+      case 1:
+        _a = _.sent();
+        _.label = 2;
+      case 2:
+        x = _a;
+        return [2];
+    }
+  });
+}
+`)
+
+	// The state machine body is a nested function, so "arguments" must be captured
+	expectPrintedWithUnsupportedFeatures(t, compat.Generator, "function* foo() { return arguments }",
+		"function foo() {\n  var _arguments = arguments;\n  return __stateMachine(this, function(_) {\n    return [2, _arguments];\n  });\n}\n")
+
+	// Async functions are lowered to generators first
+	expectPrintedWithUnsupportedFeatures(t, compat.AsyncAwait|compat.Generator, "async function foo() { return await a }",
+		`function foo() {
+  return __async(this, null, function() {
+    return __stateMachine(this, function(_) {
+      switch (_.label) {
+        case 0:
+          return [4, a];
+        case 1:
+          return [2, _.sent()];
+      }
+    });
+  });
+}
+`)
+
+	expectParseErrorWithUnsupportedFeatures(t, compat.Generator, "function* foo() { a?.b(yield) }",
+		"<stdin>: ERROR: Transforming \"yield\" inside optional chains to the configured target environment is not supported yet\n")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Generator, "function* foo() { with (a) yield b }",
+		"<stdin>: ERROR: Transforming \"yield\" inside \"with\" statements to the configured target environment is not supported yet\n")
+}
+
+func TestLowerClassES5(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo {}",
+		"let Foo = /* @__PURE__ */ function() {\n  function Foo() {\n    __classCallCheck(this, Foo);\n  }\n  return Foo;\n}();\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "(class {});",
+		"/* @__PURE__ */ (function() {\n  function _class() {\n    __classCallCheck(this, _class);\n  }\n  return _class;\n})();\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo { constructor(x) { this.x = x } foo() {} get bar() { return 1 } set bar(x) {} static baz() {} }",
+		`let Foo = /* @__PURE__ */ function() {
+  function Foo(x) {
+    __classCallCheck(this, Foo);
+    this.x = x;
+  }
+  __method(Foo.prototype, "foo", function() {
+  });
+  __getter(Foo.prototype, "bar", function() {
+    return 1;
+  });
+  __setter(Foo.prototype, "bar", function(x) {
+  });
+  __method(Foo, "baz", function() {
+  });
+  return Foo;
+}();
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo { [a]() {} static ['b']() {} }",
+		`let Foo = function(_a) {
+  function Foo() {
+    __classCallCheck(this, Foo);
+  }
+  __method(Foo.prototype, _a, function() {
+  });
+  __method(Foo, "b", function() {
+  });
+  return Foo;
+}(a);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo { x = 1; static y = 2 }",
+		`let Foo = /* @__PURE__ */ function() {
+  function Foo() {
+    __classCallCheck(this, Foo);
+    __publicField(this, "x", 1);
+  }
+  return Foo;
+}();
+__publicField(Foo, "y", 2);
+`)
+
+	// Derived class constructors replace "this" with the result of "super()"
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar {}",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = __callSuper(this, _super, arguments);
+    return _this;
+  }
+  return Foo;
+}(Bar);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar { constructor() { super(1); this.x = 2 } }",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = __callSuper(this, _super, [1]);
+    _this.x = 2;
+    return _this;
+  }
+  return Foo;
+}(Bar);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar { constructor() { foo(); super(); if (x) return; this.x = 2 } }",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = this;
+    foo();
+    _this = __callSuper(_this, _super);
+    if (x)
+      return _this;
+    _this.x = 2;
+    return _this;
+  }
+  return Foo;
+}(Bar);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar { constructor() { if (x) super(1); else super(2) } }",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = this;
+    var __super = function() {
+      _this = __callSuper(_this, _super, arguments);
+      return _this;
+    };
+    if (x)
+      __super(1);
+    else
+      __super(2);
+    return _this;
+  }
+  return Foo;
+}(Bar);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar { x = this.y }",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = __callSuper(this, _super, arguments);
+    __publicField(_this, "x", _this.y);
+    return _this;
+  }
+  return Foo;
+}(Bar);
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class, "class Foo extends Bar { foo() { return super.foo() } static bar() { super.bar = 1 } }",
+		`let Foo = function(_super) {
+  __inherits(Foo, _super);
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = __callSuper(this, _super, arguments);
+    return _this;
+  }
+  __method(Foo.prototype, "foo", function() {
+    return __superGet(Foo.prototype, this, "foo").call(this);
+  });
+  __method(Foo, "bar", function() {
+    __superSet(Foo, this, "bar", 1);
+  });
+  return Foo;
+}(Bar);
+`)
+
+	// Arrow functions in instance field initializers capture "this" in the constructor
+	expectPrintedWithUnsupportedFeatures(t, compat.Class|compat.Arrow, "class Foo { x = 1; f = () => this.x }",
+		`let Foo = /* @__PURE__ */ function() {
+  function Foo() {
+    __classCallCheck(this, Foo);
+    var _this = this;
+    __publicField(this, "x", 1);
+    __publicField(this, "f", function() {
+      return _this.x;
+    });
+  }
+  return Foo;
+}();
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.Class|compat.Arrow, "function foo() { class Foo { f = () => this } return () => this }",
+		`function foo() {
+  var _this = this;
+  let Foo = /* @__PURE__ */ function() {
+    function Foo() {
+      __classCallCheck(this, Foo);
+      var _this = this;
+      __publicField(this, "f", function() {
+        return _this;
+      });
+    }
+    return Foo;
+  }();
+  return function() {
+    return _this;
+  };
+}
+`)
+}
+
+func TestLowerDestructuring(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "var {a, b: [c, d = 1]} = foo()",
+		"var _a = foo(), a = _a.a, _b = __toArray(_a.b, 2), c = _b[0], _c = _b[1], d = _c === void 0 ? 1 : _c;\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "var [a, , ...b] = c",
+		"var _a = __toArray(c), a = _a[0], b = _a.slice(2);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "var {a = 1, [b]: c, 'd-e': f, ...g} = h",
+		"var _a = h, _b = _a.a, a = _b === void 0 ? 1 : _b, c = _a[b], f = _a[\"d-e\"], g = __objRest(_a, [\"a\", __restKey(b), \"d-e\"]);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "[a, b] = [b, a]",
+		"var _a;\n_a = __toArray([b, a], 2), a = _a[0], b = _a[1];\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "x = {a: y.z, b = 2} = foo()",
+		"var _a, _b, _c;\nx = (_b = _a = foo(), y.z = _b.a, _c = _b.b, b = _c === void 0 ? 2 : _c, _a);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "try {} catch ({message}) {}",
+		"try {\n} catch (_a) {\n  let message = _a.message;\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring, "for (var [k, v] in o) ;",
+		"for (var _a in o) {\n  var _b = __toArray(_a, 2), k = _b[0], v = _b[1];\n  ;\n}\n")
+
+	// Default and rest arguments
+	expectPrintedWithUnsupportedFeatures(t, compat.DefaultArgument, "function f(a, b = a) {}",
+		"function f(a, b) {\n  if (b === void 0)\n    b = a;\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.DefaultArgument, "function f({a} = {}) {}",
+		"function f(_a) {\n  if (_a === void 0)\n    _a = {};\n  var { a } = _a;\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.RestArgument, "function f(a, ...b) {}",
+		"function f(a) {\n  var b = __restArgs(arguments, 1);\n}\n")
+	expectParseErrorWithUnsupportedFeatures(t, compat.RestArgument, "(a, ...b) => {}",
+		"<stdin>: ERROR: Transforming rest arguments to the configured target environment is not supported yet\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.RestArgument|compat.Arrow, "(a, ...b) => {}",
+		"(function(a) {\n  var b = __restArgs(arguments, 1);\n});\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Destructuring|compat.DefaultArgument|compat.RestArgument,
+		"function f(a = 1, {b, c}, [d] = [], ...e) { return a }",
+		`function f(a, _a, _b) {
+  if (a === void 0)
+    a = 1;
+  var _c = _a, b = _c.b, c = _c.c;
+  if (_b === void 0)
+    _b = [];
+  var _d = __toArray(_b, 1), d = _d[0], e = __restArgs(arguments, 3);
+  return a;
+}
+`)
+
+	// Arguments must be initialized before "super()" is called
+	expectPrintedWithUnsupportedFeatures(t, compat.DefaultArgument|compat.ClassField,
+		"class A extends B { x = 1; constructor(y = 2) { super() } }",
+		`class A extends B {
+  constructor(y) {
+    if (y === void 0)
+      y = 2;
+    super();
+    __publicField(this, "x", 1);
+  }
+}
+`)
+}
+
+func TestLowerLet(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "const a = 1; let b; { let a = 2 }", "var a = 1;\nvar b;\n{\n  var a = 2;\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (;;) { let x; x ||= 1 }", "for (; ; ) {\n  var x = void 0;\n  x ||= 1;\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (let i = 0; i < 3; i++) fns.push(i)",
+		"for (var i = 0; i < 3; i++)\n  fns.push(i);\n")
+
+	// Loops with captured variables are moved into a function
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (let i = 0; i < 3; i++) fns.push(() => i)",
+		"var _loop = (i) => {\n  fns.push(() => i);\n};\nfor (var i = 0; i < 3; i++) {\n  _loop(i);\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet|compat.Arrow, "for (let i = 0; i < 3; i++) fns.push(() => i)",
+		"var _loop = function(i) {\n  fns.push(function() {\n    return i;\n  });\n};\nfor (var i = 0; i < 3; i++) {\n  _loop(i);\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (let i = 0; i < 3; i++) { let j = i; fns.push(() => j) }",
+		"var _loop = () => {\n  var j = i;\n  fns.push(() => j);\n};\nfor (var i = 0; i < 3; i++) {\n  _loop();\n}\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet|compat.Arrow, "while (x) { const y = this.z; fns.push(function () { return y }) }",
+		"var _loop = function() {\n  var y = this.z;\n  fns.push(function() {\n    return y;\n  });\n};\nwhile (x) {\n  _loop.call(this);\n}\n")
+
+	// Assignments to loop variables must be copied back out
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (let i = 0; i < 3; i++) { fns.push(() => i); i++; if (i > 1) continue; i++ }",
+		`var _loop = (i) => {
+  fns.push(() => i);
+  i++;
+  if (i > 1) {
+    _i = i;
+    return;
+  }
+  i++;
+  _i = i;
+}, _i;
+for (var i = 0; i < 3; i++) {
+  _loop(i);
+  i = _i;
+}
+`)
+
+	// Control flow that leaves the loop function
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "for (const k in o) { var v = o[k]; if (!v) break; if (v < 0) return k; fns.push(() => k) }",
+		`var _loop = (k) => {
+  v = o[k];
+  if (!v)
+    return "break";
+  if (v < 0)
+    return {
+      v: k
+    };
+  fns.push(() => k);
+}, v;
+for (var k in o) {
+  var _ret = _loop(k);
+  if (_ret === "break")
+    break;
+  if (typeof _ret === "object")
+    return _ret.v;
+}
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "outer: for (let i of a) for (let j of b) { fns.push(() => j); if (j) continue outer; if (i) break outer }",
+		`outer:
+  for (var i of a) {
+    var _loop = (j) => {
+      fns.push(() => j);
+      if (j)
+        return "continue-outer";
+      if (i)
+        return "break-outer";
+    };
+    for (var j of b) {
+      var _ret = _loop(j);
+      if (_ret === "continue-outer")
+        continue outer;
+      if (_ret === "break-outer")
+        break outer;
+    }
+  }
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "do { let y = x(); switch (y) { case 0: break; default: continue } fns.push(() => y) } while (z)",
+		`var _loop = () => {
+  var y = x();
+  switch (y) {
+    case 0:
+      break;
+    default:
+      return;
+  }
+  fns.push(() => y);
+};
+do {
+  _loop();
+} while (z);
+`)
+
+	// Some loop bodies can't be moved into a function
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet, "async function f() { for (let i of a) { await 0; fns.push(() => i) } }",
+		`<stdin>: ERROR: Transforming "await" inside this loop to the configured target environment is not supported yet
+<stdin>: NOTE: The loop body must be moved into a function because the variable "i" is captured by a closure:
+`)
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet|compat.Arrow, "function f() { for (let i of a) { fns.push(function() { return i }); g(arguments) } }",
+		`<stdin>: ERROR: Transforming "arguments" inside this loop to the configured target environment is not supported yet
+<stdin>: NOTE: The loop body must be moved into a function because the variable "i" is captured by a closure:
+`)
+	expectPrintedWithUnsupportedFeatures(t, compat.ConstAndLet, "function f() { for (let i of a) { fns.push(() => i); g(arguments) } }",
+		"function f() {\n  var _loop = (i) => {\n    fns.push(() => i);\n    g(arguments);\n  };\n  for (var i of a) {\n    _loop(i);\n  }\n}\n")
+
+	// Assigning to a constant would no longer throw after it becomes a "var"
+	constErrorText := `<stdin>: ERROR: Cannot assign to "x" because it is a constant
+<stdin>: NOTE: The symbol "x" was declared a constant here:
+`
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet, "const x = 1; x = 2", constErrorText)
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet, "const x = 1; x++", constErrorText)
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet, "for (const x of y) x += 1", constErrorText)
+	expectParseErrorWithUnsupportedFeatures(t, compat.ConstAndLet, "let x = 1; x = 2", "")
+	expectParseError(t, "const x = 1; x = 2", `<stdin>: WARNING: This assignment will throw because "x" is a constant
+<stdin>: NOTE: The symbol "x" was declared a constant here:
+`)
+}
+
+func TestLowerForOf(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.ForOf, "for (const x of y) f(x)",
+		"try {\n  for (var iter = __forOf(y), more, temp, error; more = !(temp = iter.next()).done; more = false) {\n    const x = temp.value;\n    f(x);\n  }\n} catch (temp) {\n  error = [temp];\n} finally {\n  try {\n    more && (temp = iter.return) && temp.call(iter);\n  } finally {\n    if (error)\n      throw error[0];\n  }\n}\n")
+
+	// Labels are moved onto the inner loop so that "continue" still works
+	expectPrintedWithUnsupportedFeatures(t, compat.ForOf, "a: for (const x of y) { if (x) continue a; f(x) }",
+		"try {\n  a:\n    for (var iter = __forOf(y), more, temp, error; more = !(temp = iter.next()).done; more = false) {\n      const x = temp.value;\n      if (x)\n        continue a;\n      f(x);\n    }\n} catch (temp) {\n  error = [temp];\n} finally {\n  try {\n    more && (temp = iter.return) && temp.call(iter);\n  } finally {\n    if (error)\n      throw error[0];\n  }\n}\n")
+
+	// Loose mode assumes the iterable is an array
+	expectPrintedCommon(t, "for (const x of y) f(x)", "for (var i = 0, arr = y; i < arr.length; i++) {\n  const x = arr[i];\n  f(x);\n}\n", config.Options{
+		UnsupportedJSFeatures: compat.ForOf,
+		LooseForOf:            true,
+	})
+}
+
+func TestLowerSpread(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "[a, ...b, c]", "[a].concat(__toArray(b), [c]);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "[...a]", "__toArray(a);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "f(...a)", "f.apply(void 0, __toArray(a));\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "a.b.c(x, ...y)", "var _a;\n(_a = a.b).c.apply(_a, [x].concat(__toArray(y)));\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "a.b[c](...d)", "var _a;\n(_a = a.b)[c].apply(_a, __toArray(d));\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "new F(...a, b)", "new (F.bind.apply(F, [null].concat(__toArray(a), [b])))();\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "a?.b(...c)", "a == null ? void 0 : a.b.apply(a, __toArray(c));\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ArraySpread, "[a, ...b] = c", "[a, ...b] = c;\n")
+}
+
+func TestLowerObjectExtensions(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { a() {}, b }", "x = { a: function() {\n}, b: b };\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { a, [b]: c, d }",
+		"var _a;\nx = (_a = { a: a }, __defNormalProp(_a, b, c), __defNormalProp(_a, \"d\", d), _a);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { [a]: 1, get b() { return 1 }, set b(v) {} }",
+		"var _b;\nx = (_b = {}, __defNormalProp(_b, a, 1), __defProp(_b, \"b\", { get: function() {\n  return 1;\n}, enumerable: true, configurable: true }), __defProp(_b, \"b\", { set: function(v) {\n}, enumerable: true, configurable: true }), _b);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { __proto__: a, [b]: c }", "var _a;\nx = (_a = { __proto__: a }, __defNormalProp(_a, b, c), _a);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { [a]: b, __proto__: c }", "var _a;\nx = (_a = {}, __defNormalProp(_a, a, b), _a.__proto__ = c, _a);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.ObjectExtensions, "x = { foo() { return super.foo() } }",
+		"var _a;\nx = _a = { foo: function() {\n  return __superGet(_a, this, \"foo\").call(this);\n} };\n")
+}
+
 func TestLowerAutoAccessors(t *testing.T) {
 	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { accessor x }",
 		"class Foo {\n  #x;\n  get x() {\n    return this.#x;\n  }\n  set x(_) {\n    this.#x = _;\n  }\n}\n")
@@ -796,3 +1298,28 @@ func TestLowerAutoAccessors(t *testing.T) {
 	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { static accessor [x] = null }",
 		"var _a;\nclass Foo {\n  static #a = null;\n  static get [_a = x]() {\n    return this.#a;\n  }\n  static set [_a](_) {\n    this.#a = _;\n  }\n}\n")
 }
+
+func TestLowerDecorators(t *testing.T) {
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "@dec class Foo {}",
+		"var _init, _Foo_decorators;\n_Foo_decorators = [dec];\nconst _Foo = class _Foo {\n};\n_init = __decoratorStart(null);\nlet Foo = __decorateElement(_init, 0, \"Foo\", _Foo_decorators, _Foo);\n__runInitializers(_init, 1, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "@dec export class Foo {}",
+		"var _init, _Foo_decorators;\n_Foo_decorators = [dec];\nconst _Foo = class _Foo {\n};\n_init = __decoratorStart(null);\nexport let Foo = __decorateElement(_init, 0, \"Foo\", _Foo_decorators, _Foo);\n__runInitializers(_init, 1, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "let Foo = @dec class {}",
+		"var _init, _class_decorators, _a, _b;\nlet Foo = (_class_decorators = [dec], _a = class {\n}, _init = __decoratorStart(null), _b = __decorateElement(_init, 0, void 0, _class_decorators, _a), __runInitializers(_init, 1, _b), _b);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "let Foo = @dec class Bar { static x = Bar }",
+		"var _init, _Bar_decorators, _a, _b;\nlet Foo = (_Bar_decorators = [dec], _a = class {\n}, _init = __decoratorStart(null), _b = __decorateElement(_init, 0, \"Bar\", _Bar_decorators, _a), __publicField(_a, \"x\", _b), __runInitializers(_init, 1, _b), _b);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec x = 1; @dec static y }",
+		"var _init, _x_dec, _y_dec;\n_x_dec = [dec], _y_dec = [dec];\nclass Foo {\n  constructor() {\n    __publicField(this, \"x\", __runInitializers(_init, 12, this, 1)), __runInitializers(_init, 15, this);\n  }\n}\n_init = __decoratorStart(null);\n__decorateElement(_init, 13, \"y\", _y_dec, Foo);\n__decorateElement(_init, 5, \"x\", _x_dec, Foo);\n__decoratorMetadata(_init, Foo);\n__publicField(Foo, \"y\", __runInitializers(_init, 8, Foo)), __runInitializers(_init, 11, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec m() {} @dec static n() {} }",
+		"var _init, _m_dec, _n_dec;\n_m_dec = [dec], _n_dec = [dec];\nclass Foo {\n  constructor() {\n    __runInitializers(_init, 5, this);\n  }\n  m() {\n  }\n  static n() {\n  }\n}\n_init = __decoratorStart(null);\n__decorateElement(_init, 9, \"n\", _n_dec, Foo);\n__decorateElement(_init, 1, \"m\", _m_dec, Foo);\n__decoratorMetadata(_init, Foo);\n__runInitializers(_init, 3, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec get x() {} @dec set x(y) {} }",
+		"var _init, _x_dec, _x_dec;\n_x_dec = [dec], _x_dec = [dec];\nclass Foo {\n  constructor() {\n    __runInitializers(_init, 5, this);\n  }\n  get x() {\n  }\n  set x(y) {\n  }\n}\n_init = __decoratorStart(null);\n__decorateElement(_init, 2, \"x\", _x_dec, Foo);\n__decorateElement(_init, 3, \"x\", _x_dec, Foo);\n__decoratorMetadata(_init, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec accessor x = 1 }",
+		"var _init, _x_dec, _x;\n_x_dec = [dec];\nclass Foo {\n  constructor() {\n    __privateAdd(this, _x, __runInitializers(_init, 8, this, 1)), __runInitializers(_init, 11, this);\n  }\n  get x() {\n    return __privateGet(this, _x);\n  }\n  set x(_) {\n    __privateSet(this, _x, _);\n  }\n}\n_x = new WeakMap();\n_init = __decoratorStart(null);\n__decorateElement(_init, 4, \"x\", _x_dec, Foo);\n__decoratorMetadata(_init, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec #x = 1; @dec #m() {} }",
+		"var _init, _x_dec, _x, _m_dec, _m, m_fn;\n_x_dec = [dec], _m_dec = [dec];\nclass Foo {\n  constructor() {\n    __privateAdd(this, _m);\n    __runInitializers(_init, 5, this);\n    __privateAdd(this, _x, __runInitializers(_init, 8, this, 1)), __runInitializers(_init, 11, this);\n  }\n}\n_x = new WeakMap();\n_m = new WeakSet();\nm_fn = function() {\n};\n_init = __decoratorStart(null);\nm_fn = __decorateElement(_init, 17, \"#m\", _m_dec, _m, m_fn);\n__decorateElement(_init, 21, \"#x\", _x_dec, _x);\n__decoratorMetadata(_init, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec accessor #x }",
+		"var _init, _x_dec, _a, __x, _x, x_get, x_set;\n_x_dec = [dec];\nclass Foo {\n  constructor() {\n    __privateAdd(this, _x);\n    __privateAdd(this, __x, __runInitializers(_init, 8, this)), __runInitializers(_init, 11, this);\n  }\n}\n__x = new WeakMap();\n_x = new WeakSet();\nx_get = function() {\n  return __privateGet(this, __x);\n};\nx_set = function(_) {\n  __privateSet(this, __x, _);\n};\n_init = __decoratorStart(null);\n_a = __decorateElement(_init, 20, \"#x\", _x_dec, _x, { get: x_get, set: x_set }), x_get = _a.get, x_set = _a.set;\n__decoratorMetadata(_init, Foo);\n")
+	expectPrintedWithUnsupportedFeatures(t, compat.Decorators, "class Foo extends Bar { @dec [x()] = 1; @dec2 y }",
+		"var _init, _dec, _a, _y_dec;\nclass Foo extends Bar {\n  constructor() {\n    super(...arguments);\n    __publicField(this, _a, __runInitializers(_init, 8, this, 1)), __runInitializers(_init, 11, this);\n    __publicField(this, \"y\", __runInitializers(_init, 12, this)), __runInitializers(_init, 15, this);\n  }\n  static {\n    _dec = [dec], _a = x(), _y_dec = [dec2];\n  }\n}\n_init = __decoratorStart(Bar);\n__decorateElement(_init, 5, _a, _dec, Foo);\n__decorateElement(_init, 5, \"y\", _y_dec, Foo);\n__decoratorMetadata(_init, Foo);\n")
+}
@@ -2,8 +2,10 @@ package js_parser
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/evanw/esbuild/internal/ast"
 	"github.com/evanw/esbuild/internal/compat"
@@ -2042,14 +2044,14 @@ func TestDecorators(t *testing.T) {
 		"<stdin>: ERROR: JavaScript decorator syntax does not allow \".\" after a call expression\n"+
 			"<stdin>: NOTE: Wrap this decorator in parentheses to allow arbitrary expressions:\n")
 
-	errorText := "<stdin>: ERROR: Transforming JavaScript decorators to the configured target environment is not supported yet\n"
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "@dec class Foo {}", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec x }", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec x() {} }", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec accessor x }", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static x }", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static x() {} }", errorText)
-	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static accessor x }", errorText)
+	// JavaScript decorators are lowered when they are unsupported
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "@dec class Foo {}", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec x }", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec x() {} }", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec accessor x }", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static x }", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static x() {} }", "")
+	expectParseErrorWithUnsupportedFeatures(t, compat.Decorators, "class Foo { @dec static accessor x }", "")
 
 	// Check ASI for "abstract"
 	expectParseError(t, "@x abstract class Foo {}", "<stdin>: ERROR: Expected \";\" but found \"class\"\n")
@@ -6137,82 +6139,45 @@ func TestES5(t *testing.T) {
 	expectPrintedTarget(t, 2015, "if (1) function f() {}", "if (1) {\n  let f = function() {\n  };\n  var f = f;\n}\n")
 	expectPrintedTarget(t, 5, "if (1) function f() {}", "if (1) {\n  var f = function() {\n  };\n  var f = f;\n}\n")
 
-	expectParseErrorTarget(t, 5, "function foo(x = 0) {}",
-		"<stdin>: ERROR: Transforming default arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function(x = 0) {})",
-		"<stdin>: ERROR: Transforming default arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(x = 0) => {}",
-		"<stdin>: ERROR: Transforming default arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function foo(...x) {}",
-		"<stdin>: ERROR: Transforming rest arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function(...x) {})",
-		"<stdin>: ERROR: Transforming rest arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(...x) => {}",
-		"<stdin>: ERROR: Transforming rest arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "foo(...x)",
-		"<stdin>: ERROR: Transforming rest arguments to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "[...x]",
-		"<stdin>: ERROR: Transforming array spread to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "for (var x of y) ;",
-		"<stdin>: ERROR: Transforming for-of loops to the configured target environment is not supported yet\n")
+	expectPrintedTarget(t, 5, "function foo(x = 0) {}", "function foo(x) {\n  if (x === void 0)\n    x = 0;\n}\n")
+	expectPrintedTarget(t, 5, "(function(x = 0) {})", "(function(x) {\n  if (x === void 0)\n    x = 0;\n});\n")
+	expectPrintedTarget(t, 5, "(x = 0) => {}", "(function(x) {\n  if (x === void 0)\n    x = 0;\n});\n")
+	expectPrintedTarget(t, 5, "function foo(...x) {}", "function foo() {\n  var x = __restArgs(arguments, 0);\n}\n")
+	expectPrintedTarget(t, 5, "(function(...x) {})", "(function() {\n  var x = __restArgs(arguments, 0);\n});\n")
+	expectPrintedTarget(t, 5, "(...x) => {}", "(function() {\n  var x = __restArgs(arguments, 0);\n});\n")
+	expectPrintedTarget(t, 5, "foo(...x)", "foo.apply(void 0, __toArray(x));\n")
+	expectPrintedTarget(t, 5, "[...x]", "__toArray(x);\n")
+	expectPrintedTarget(t, 5, "for (var x of y) ;", "try {\n  for (var iter = __forOf(y), more, temp, error; more = !(temp = iter.next()).done; more = false) {\n    var x = temp.value;\n    ;\n  }\n"+
+		"} catch (temp) {\n  error = [temp];\n} finally {\n  try {\n    more && (temp = iter.return) && temp.call(iter);\n  } finally {\n    if (error)\n      throw error[0];\n  }\n}\n")
 	expectPrintedTarget(t, 5, "({ x })", "({ x: x });\n")
-	expectParseErrorTarget(t, 5, "({ [x]: y })",
-		"<stdin>: ERROR: Transforming object literal extensions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "({ x() {} });",
-		"<stdin>: ERROR: Transforming object literal extensions to the configured target environment is not supported yet\n")
+	expectPrintedTarget(t, 5, "({ [x]: y })", "var _a;\n_a = {}, __defNormalProp(_a, x, y), _a;\n")
+	expectPrintedTarget(t, 5, "({ x() {} });", "({ x: function() {\n} });\n")
 	expectParseErrorTarget(t, 5, "({ get x() {} });", "")
 	expectParseErrorTarget(t, 5, "({ set x(x) {} });", "")
-	expectParseErrorTarget(t, 5, "({ get [x]() {} });",
-		"<stdin>: ERROR: Transforming object literal extensions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "({ set [x](x) {} });",
-		"<stdin>: ERROR: Transforming object literal extensions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function foo([]) {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function foo({}) {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function([]) {})",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function({}) {})",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "([]) => {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "({}) => {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "var [] = [];",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "var {} = {};",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "([] = []);",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "({} = {});",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "for ([] in []);",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "for ({} in []);",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function foo([...x]) {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function([...x]) {})",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "([...x]) => {}",
-		"<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function foo([...[x]]) {}",
-		`<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming non-identifier array rest patterns to the configured target environment is not supported yet
-`)
-	expectParseErrorTarget(t, 5, "(function([...[x]]) {})",
-		`<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming non-identifier array rest patterns to the configured target environment is not supported yet
-`)
-	expectParseErrorTarget(t, 5, "([...[x]]) => {}",
-		`<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet
-<stdin>: ERROR: Transforming non-identifier array rest patterns to the configured target environment is not supported yet
-`)
-	expectParseErrorTarget(t, 5, "([...[x]])",
-		"<stdin>: ERROR: Transforming array spread to the configured target environment is not supported yet\n")
+	expectPrintedTarget(t, 5, "({ get [x]() {} });", "var _b;\n_b = {}, __defProp(_b, x, { get: function() {\n}, enumerable: true, configurable: true }), _b;\n")
+	expectPrintedTarget(t, 5, "({ set [x](x) {} });", "var _b;\n_b = {}, __defProp(_b, x, { set: function(x) {\n}, enumerable: true, configurable: true }), _b;\n")
+	expectPrintedTarget(t, 5, "function foo([]) {}", "function foo(_a) {\n  var _b = __toArray(_a, 0);\n}\n")
+	expectPrintedTarget(t, 5, "function foo({}) {}", "function foo(_a) {\n  var _b = _a;\n}\n")
+	expectPrintedTarget(t, 5, "(function([]) {})", "(function(_a) {\n  var _b = __toArray(_a, 0);\n});\n")
+	expectPrintedTarget(t, 5, "(function({}) {})", "(function(_a) {\n  var _b = _a;\n});\n")
+	expectPrintedTarget(t, 5, "([]) => {}", "(function(_a) {\n  var _b = __toArray(_a, 0);\n});\n")
+	expectPrintedTarget(t, 5, "({}) => {}", "(function(_a) {\n  var _b = _a;\n});\n")
+	expectPrintedTarget(t, 5, "var [] = [];", "var _a = __toArray([], 0);\n")
+	expectPrintedTarget(t, 5, "var {} = {};", "var _a = {};\n")
+	expectPrintedTarget(t, 5, "([] = []);", "var _a;\n_a = __toArray([], 0);\n")
+	expectPrintedTarget(t, 5, "({} = {});", "var _a;\n_a = {};\n")
+	expectPrintedTarget(t, 5, "for ([] in []);", "var _a, _b;\nfor (_a in []) {\n  _b = __toArray(_a, 0);\n  ;\n}\n")
+	expectPrintedTarget(t, 5, "for ({} in []);", "var _a, _b;\nfor (_a in []) {\n  _b = _a;\n  ;\n}\n")
+	expectPrintedTarget(t, 5, "function foo([...x]) {}", "function foo(_a) {\n  var _b = __toArray(_a), x = _b.slice(0);\n}\n")
+	expectPrintedTarget(t, 5, "(function([...x]) {})", "(function(_a) {\n  var _b = __toArray(_a), x = _b.slice(0);\n});\n")
+	expectPrintedTarget(t, 5, "([...x]) => {}", "(function(_a) {\n  var _b = __toArray(_a), x = _b.slice(0);\n});\n")
+	expectPrintedTarget(t, 5, "function foo([...[x]]) {}",
+		"function foo(_a) {\n  var _b = __toArray(_a), _c = __toArray(_b.slice(0), 1), x = _c[0];\n}\n")
+	expectPrintedTarget(t, 5, "(function([...[x]]) {})",
+		"(function(_a) {\n  var _b = __toArray(_a), _c = __toArray(_b.slice(0), 1), x = _c[0];\n});\n")
+	expectPrintedTarget(t, 5, "([...[x]]) => {}",
+		"(function(_a) {\n  var _b = __toArray(_a), _c = __toArray(_b.slice(0), 1), x = _c[0];\n});\n")
+	expectPrintedTarget(t, 5, "([...[x]])", "__toArray([x]);\n")
 	expectPrintedTarget(t, 5, "`abc`;", "\"abc\";\n")
 	expectPrintedTarget(t, 5, "`a${b}`;", "\"a\".concat(b);\n")
 	expectPrintedTarget(t, 5, "`${a}b`;", "\"\".concat(a, \"b\");\n")
@@ -6231,27 +6196,17 @@ func TestES5(t *testing.T) {
 	expectPrintedTarget(t, 5, "tag`a${b}\\u`;", "var _a;\ntag(_a || (_a = __template([\"a\", void 0], [\"a\", \"\\\\u\"])), b);\n")
 	expectPrintedTarget(t, 5, "tag`\\u${b}c`;", "var _a;\ntag(_a || (_a = __template([void 0, \"c\"], [\"\\\\u\", \"c\"])), b);\n")
 	expectParseErrorTarget(t, 5, "class Foo { constructor() { new.target } }",
-		"<stdin>: ERROR: Transforming class syntax to the configured target environment is not supported yet\n"+
-			"<stdin>: ERROR: Transforming object literal extensions to the configured target environment is not supported yet\n"+
-			"<stdin>: ERROR: Transforming new.target to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "const x = 1;",
-		"<stdin>: ERROR: Transforming const to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "let x = 2;",
-		"<stdin>: ERROR: Transforming let to the configured target environment is not supported yet\n")
+		"<stdin>: ERROR: Transforming new.target to the configured target environment is not supported yet\n")
+	expectPrintedTarget(t, 5, "const x = 1;", "var x = 1;\n")
+	expectPrintedTarget(t, 5, "let x = 2;", "var x = 2;\n")
 	expectPrintedTarget(t, 5, "async => foo;", "(function(async) {\n  return foo;\n});\n")
 	expectPrintedTarget(t, 5, "x => x;", "(function(x) {\n  return x;\n});\n")
-	expectParseErrorTarget(t, 5, "async () => foo;",
-		"<stdin>: ERROR: Transforming async functions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "class Foo {}",
-		"<stdin>: ERROR: Transforming class syntax to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(class {});",
-		"<stdin>: ERROR: Transforming class syntax to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "function* gen() {}",
-		"<stdin>: ERROR: Transforming generator functions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "(function* () {});",
-		"<stdin>: ERROR: Transforming generator functions to the configured target environment is not supported yet\n")
-	expectParseErrorTarget(t, 5, "({ *foo() {} });",
-		"<stdin>: ERROR: Transforming generator functions to the configured target environment is not supported yet\n")
+	expectPrintedTarget(t, 5, "async () => foo;", "(function() {\n  return __async(this, null, function() {\n    return __stateMachine(this, function(_) {\n      return [2, foo];\n    });\n  });\n});\n")
+	expectPrintedTarget(t, 5, "class Foo {}", "var Foo = /* @__PURE__ */ function() {\n  function Foo() {\n    __classCallCheck(this, Foo);\n  }\n  return Foo;\n}();\n")
+	expectPrintedTarget(t, 5, "(class {});", "/* @__PURE__ */ (function() {\n  function _class() {\n    __classCallCheck(this, _class);\n  }\n  return _class;\n})();\n")
+	expectPrintedTarget(t, 5, "function* gen() {}", "function gen() {\n  return __stateMachine(this, function(_) {\n    return [2];\n  });\n}\n")
+	expectPrintedTarget(t, 5, "(function* () {});", "(function() {\n  return __stateMachine(this, function(_) {\n    return [2];\n  });\n});\n")
+	expectPrintedTarget(t, 5, "({ *foo() {} });", "({ foo: function() {\n  return __stateMachine(this, function(_) {\n    return [2];\n  });\n} });\n")
 }
 
 func TestASCIIOnly(t *testing.T) {
@@ -6603,3 +6558,139 @@ func TestUsing(t *testing.T) {
 	expectPrintedMangle(t, "using x = null, y = z", "using x = null, y = z;\n")
 	expectPrintedMangle(t, "using x = z, y = undefined", "using x = z, y = void 0;\n")
 }
+
+// The persistent cache reuses an AST from an earlier build if the cache key is
+// the same, so every parser option must be part of the key. This sets each
+// field of the options (recursively) to a non-zero value and checks that the
+// key changes. It also checks that the key is deterministic so that it can be
+// shared between processes. New fields will make this test fail until they
+// are added to "PersistentCacheKey".
+func TestPersistentCacheKeyCoversAllOptions(t *testing.T) {
+	// Fields in this list don't affect the AST that the parser generates
+	ignored := map[string]string{
+		// These sources are only used for log messages, which don't use the index
+		"moduleTypeData.Source.Index": "log messages only",
+		"tsAlwaysStrict.Source.Index": "log messages only",
+	}
+
+	type step func(v reflect.Value) reflect.Value
+	settable := func(v reflect.Value) reflect.Value {
+		// Unexported fields can't normally be set using reflection
+		return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+	isOwnType := func(typ reflect.Type) bool {
+		return strings.HasPrefix(typ.PkgPath(), "github.com/evanw/esbuild/")
+	}
+	exprType := reflect.TypeOf((*js_ast.E)(nil)).Elem()
+
+	checked := 0
+	check := func(name string, path []step, mutate func(v reflect.Value)) {
+		t.Helper()
+		checked++
+		makeOptions := func(withMutation bool) *Options {
+			options := &Options{}
+			v := reflect.ValueOf(options).Elem()
+			for _, s := range path {
+				v = s(v)
+			}
+			if withMutation {
+				mutate(v)
+			}
+			return options
+		}
+		before, beforeOK := makeOptions(false).PersistentCacheKey()
+		after, afterOK := makeOptions(true).PersistentCacheKey()
+		again, _ := makeOptions(true).PersistentCacheKey()
+		if !afterOK {
+			return // The persistent cache won't be used with these options
+		}
+		if after != again {
+			t.Errorf("The persistent cache key for %q is not deterministic:\n  %s\n  %s", name, after, again)
+		} else if beforeOK && before == after {
+			t.Errorf("The option %q is not part of the persistent cache key", name)
+		}
+	}
+
+	var walk func(name string, typ reflect.Type, path []step)
+	walk = func(name string, typ reflect.Type, path []step) {
+		if _, ok := ignored[name]; ok {
+			return
+		}
+		extend := func(s step) []step {
+			return append(path[:len(path):len(path)], s)
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			if !isOwnType(typ) {
+				t.Fatalf("Unexpected type %s for %q", typ, name)
+			}
+			for i := 0; i < typ.NumField(); i++ {
+				i := i
+				field := typ.Field(i)
+				fieldName := field.Name
+				if name != "" && !field.Anonymous {
+					fieldName = name + "." + field.Name
+				} else if field.Anonymous {
+					fieldName = name
+				}
+				walk(fieldName, field.Type, extend(func(v reflect.Value) reflect.Value { return settable(v.Field(i)) }))
+			}
+
+		case reflect.Ptr:
+			check(name, path, func(v reflect.Value) { v.Set(reflect.New(typ.Elem())) })
+			if typ.Elem().Kind() == reflect.Struct && isOwnType(typ.Elem()) {
+				walk(name, typ.Elem(), extend(func(v reflect.Value) reflect.Value {
+					if v.IsNil() {
+						v.Set(reflect.New(typ.Elem()))
+					}
+					return v.Elem()
+				}))
+			}
+
+		case reflect.Slice:
+			check(name, path, func(v reflect.Value) { v.Set(reflect.MakeSlice(typ, 1, 1)) })
+			if typ.Elem().Kind() == reflect.Struct || typ.Elem().Kind() == reflect.String {
+				walk(name+"[0]", typ.Elem(), extend(func(v reflect.Value) reflect.Value {
+					if v.Len() == 0 {
+						v.Set(reflect.MakeSlice(typ, 1, 1))
+					}
+					return v.Index(0)
+				}))
+			}
+
+		case reflect.Map:
+			check(name, path, func(v reflect.Value) {
+				m := reflect.MakeMap(typ)
+				m.SetMapIndex(reflect.Zero(typ.Key()), reflect.Zero(typ.Elem()))
+				v.Set(m)
+			})
+
+		case reflect.Interface:
+			if typ != exprType {
+				t.Fatalf("Unexpected type %s for %q", typ, name)
+			}
+			check(name, path, func(v reflect.Value) { v.Set(reflect.ValueOf(&js_ast.ENumber{Value: 1})) })
+
+		case reflect.Bool:
+			check(name, path, func(v reflect.Value) { v.SetBool(true) })
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			check(name, path, func(v reflect.Value) { v.SetInt(1) })
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			check(name, path, func(v reflect.Value) { v.SetUint(1) })
+
+		case reflect.String:
+			check(name, path, func(v reflect.Value) { v.SetString("x") })
+
+		default:
+			t.Fatalf("Unexpected type %s for %q", typ, name)
+		}
+	}
+
+	walk("", reflect.TypeOf(Options{}), nil)
+	if checked < 50 {
+		t.Fatalf("Only %d options were checked", checked)
+	}
+}
@@ -0,0 +1,1337 @@
+package js_parser
+
+// This file generates TypeScript declaration files (i.e. ".d.ts" files). This
+// is done without a type checker, which means it works like TypeScript's
+// "isolatedDeclarations" setting: types are copied from the type annotations
+// in the original source code, and declarations that would need type
+// inference are errors unless the type is obvious from the syntax (e.g. the
+// declaration is initialized to a literal).
+//
+// Most type annotations are skipped over by the parser and don't end up in
+// the AST. So the parser records the source ranges of the interesting parts
+// of the syntax while parsing, and the declaration file is then generated
+// from the AST and those ranges by copying text from the original source.
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_lexer"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+type tsDeclarationData struct {
+	fns     map[logger.Loc]tsDeclFn       // Keyed by "Fn.OpenParenLoc"
+	arrows  map[logger.Loc]tsDeclFn       // Keyed by the location of the "EArrow"
+	props   map[logger.Loc]tsDeclProp     // Keyed by "Property.Loc"
+	classes map[logger.Loc][]tsDeclMember // Keyed by "Class.BodyLoc"
+	decls   map[logger.Loc]tsDeclDecl     // Keyed by "Decl.Binding.Loc"
+
+	// TypeScript-only statements are not present in the AST, so statements at
+	// the top level and inside namespaces are recorded separately
+	moduleStmts    *tsDeclStmtList
+	namespaceStmts []*tsDeclStmtList
+
+	// Type parameters are parsed before the function or arrow function that
+	// they belong to, so remember where the most recent ones were
+	lastTypeParameters logger.Range
+}
+
+type tsDeclStmtList struct {
+	stmts []tsDeclStmt
+	loc   logger.Loc // The first token
+	start int32      // The end of the token before the first token
+}
+
+type tsDeclStmt struct {
+	stmt  js_ast.Stmt
+	start int32
+	end   int32
+}
+
+type tsDeclFn struct {
+	args           []tsDeclArg
+	typeParameters logger.Range
+	thisArg        logger.Range
+	returnType     logger.Range
+}
+
+type tsDeclArg struct {
+	defaultOrNil js_ast.Expr
+	binding      logger.Range
+	typeRange    logger.Range
+	start        int32 // This includes any "..." and parameter property modifiers
+	isOptional   bool
+	isRest       bool
+}
+
+type tsDeclProp struct {
+	key            logger.Range
+	typeRange      logger.Range
+	initializerEnd int32
+	isOptional     bool
+	isPrivate      bool
+	isProtected    bool
+	isReadonly     bool
+	isStatic       bool
+	isOverload     bool
+}
+
+type tsDeclMember struct {
+	propertyLoc   logger.Loc // This is after any decorators
+	start         int32
+	end           int32
+	propertyIndex int32 // This is -1 for TypeScript-only class members
+}
+
+type tsDeclDecl struct {
+	binding   logger.Range
+	typeRange logger.Range
+	valueEnd  int32
+}
+
+func newTSDeclarationData() *tsDeclarationData {
+	return &tsDeclarationData{
+		fns:     make(map[logger.Loc]tsDeclFn),
+		arrows:  make(map[logger.Loc]tsDeclFn),
+		props:   make(map[logger.Loc]tsDeclProp),
+		classes: make(map[logger.Loc][]tsDeclMember),
+		decls:   make(map[logger.Loc]tsDeclDecl),
+	}
+}
+
+func (p *parser) newTSDeclStmtList(isModuleScope bool) *[]tsDeclStmt {
+	list := &tsDeclStmtList{loc: p.lexer.Loc(), start: p.lexer.PrevTokenEnd()}
+	if isModuleScope && p.tsDeclaration.moduleStmts == nil {
+		p.tsDeclaration.moduleStmts = list
+	} else {
+		p.tsDeclaration.namespaceStmts = append(p.tsDeclaration.namespaceStmts, list)
+	}
+	return &list.stmts
+}
+
+func (p *parser) rangeToPrevTokenEnd(loc logger.Loc) logger.Range {
+	return logger.Range{Loc: loc, Len: p.lexer.PrevTokenEnd() - loc.Start}
+}
+
+type tsDeclScope uint8
+
+const (
+	tsDeclModuleScope tsDeclScope = iota
+	tsDeclNamespaceScope
+)
+
+type tsDeclKind uint8
+
+const (
+	// This statement is not present in the declaration file
+	tsDeclOmitted tsDeclKind = iota
+
+	// This statement declares something without exporting it. It's only
+	// present in the declaration file if it's referenced by something else.
+	tsDeclLocal
+
+	// This is an import statement. It's only present in the declaration file
+	// if it's referenced by something else.
+	tsDeclImport
+
+	// These statements are always present in the declaration file
+	tsDeclExported
+	tsDeclExportClause // "export {}", "export * from", or "export ="
+	tsDeclGlobal       // "declare global {}" or "declare module 'foo' {}"
+)
+
+type tsDeclCandidate struct {
+	text        string
+	names       []string
+	kind        tsDeclKind
+	isIncluded  bool
+	addsDeclare bool // For function overloads
+}
+
+type tsDeclError struct {
+	text string
+	r    logger.Range
+}
+
+type tsDeclGenerator struct {
+	p      *parser
+	source string
+	errors []tsDeclError
+}
+
+func (p *parser) generateTSDeclaration() string {
+	g := tsDeclGenerator{p: p, source: p.source.Contents}
+	var text string
+	if list := p.tsDeclaration.moduleStmts; list != nil {
+		text = g.printStmts(list, tsDeclModuleScope)
+	}
+	if text == "" {
+		// Always generate a module so that the file can be imported
+		text = "export {};\n"
+	}
+
+	// Errors are reported in source order
+	sort.SliceStable(g.errors, func(i int, j int) bool {
+		return g.errors[i].r.Loc.Start < g.errors[j].r.Loc.Start
+	})
+	for _, err := range g.errors {
+		p.log.AddError(&p.tracker, err.r, err.text)
+	}
+	return text
+}
+
+// This removes the indentation of the line that some text started on from the
+// lines after the first one, so the text can be indented again at its depth
+// in the declaration file
+func tsDeclDedent(text string, indent string) string {
+	if indent == "" || !strings.Contains(text, "\n") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i := 1; i < len(lines); i++ {
+		lines[i] = strings.TrimPrefix(lines[i], indent)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Code that's copied from the original source may use any indentation. This
+// dedents it like "tsDeclDedent" and then changes each level of indentation
+// inside it to two spaces. The smallest indentation is assumed to be one level.
+func tsDeclReindent(text string, indent string) string {
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+	lines := strings.Split(tsDeclDedent(text, indent), "\n")
+	unit := 0
+	for _, line := range lines[1:] {
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); n > 0 && n < len(line) && (unit == 0 || n < unit) {
+			unit = n
+		}
+	}
+	if unit == 0 {
+		return strings.Join(lines, "\n")
+	}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t")
+		n := len(lines[i]) - len(line)
+		if line != "" {
+			lines[i] = strings.Repeat("  ", n/unit) + strings.Repeat(" ", n%unit) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// This indents text by one level, which is always two spaces
+func tsDeclIndent(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (g *tsDeclGenerator) addError(r logger.Range, text string) {
+	g.errors = append(g.errors, tsDeclError{r: r, text: text + " with --isolatedDeclarations"})
+}
+
+func (g *tsDeclGenerator) text(r logger.Range) string {
+	return strings.TrimSpace(g.source[r.Loc.Start:r.End()])
+}
+
+func (g *tsDeclGenerator) nameAt(loc logger.Loc) string {
+	return g.text(js_lexer.RangeOfIdentifier(g.p.source, loc))
+}
+
+// Statements are printed in their original order, but statements that only
+// declare something locally are only printed if something that's printed
+// references them. References are found by looking at the identifiers in the
+// generated text, which is conservative but doesn't need a type checker.
+func (g *tsDeclGenerator) printStmts(list *tsDeclStmtList, scope tsDeclScope) string {
+	candidates := make([]tsDeclCandidate, len(list.stmts))
+	isModule := scope == tsDeclNamespaceScope
+	lastOverloadName := ""
+
+	for i, s := range list.stmts {
+		c := &candidates[i]
+		g.classifyStmt(s, c)
+		if c.kind != tsDeclOmitted && c.kind != tsDeclLocal && c.kind != tsDeclGlobal {
+			isModule = true
+		}
+
+		// Omit the implementation of an overloaded function
+		if len(c.names) == 1 {
+			switch s := s.stmt.Data.(type) {
+			case *js_ast.STypeScript:
+				if c.addsDeclare || c.kind == tsDeclExported {
+					lastOverloadName = c.names[0]
+					continue
+				}
+			case *js_ast.SFunction:
+				if c.names[0] == lastOverloadName {
+					c.kind = tsDeclOmitted
+				}
+			case *js_ast.SExportDefault:
+				if _, ok := s.Value.Data.(*js_ast.SFunction); ok && c.names[0] == lastOverloadName {
+					c.kind = tsDeclOmitted
+				}
+			}
+		}
+		lastOverloadName = ""
+	}
+
+	// Figure out which statements to include
+	refs := make(map[string]bool)
+	include := func(i int) {
+		c := &candidates[i]
+		c.isIncluded = true
+		c.text = g.stmtText(list.stmts[i], scope, c.addsDeclare)
+		tsDeclForEachIdentifier(c.text, func(name string) {
+			refs[name] = true
+		})
+	}
+	for i, c := range candidates {
+		if c.kind == tsDeclExported || c.kind == tsDeclExportClause || c.kind == tsDeclGlobal || (!isModule && c.kind == tsDeclLocal) {
+			include(i)
+		}
+	}
+	for {
+		changed := false
+		for i, c := range candidates {
+			if !c.isIncluded && (c.kind == tsDeclLocal || c.kind == tsDeclImport) {
+				for _, name := range c.names {
+					if refs[name] {
+						include(i)
+						changed = true
+						break
+					}
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// Print the included statements
+	sb := strings.Builder{}
+	prevEnd := list.start
+	needsScopeFix := false
+	hasScopeMarker := false
+	hasModuleIndicator := false
+	for i, c := range candidates {
+		s := list.stmts[i]
+		if c.isIncluded && c.text != "" {
+			sb.WriteString(g.leadingComment(prevEnd, s.start))
+			sb.WriteString(c.text)
+			sb.WriteByte('\n')
+			switch c.kind {
+			case tsDeclLocal:
+				needsScopeFix = true
+			case tsDeclExportClause:
+				hasScopeMarker = true
+				hasModuleIndicator = true
+			case tsDeclImport, tsDeclExported:
+				hasModuleIndicator = true
+			}
+		}
+		prevEnd = s.end
+	}
+
+	// Without an export clause, everything in a module in a declaration file is
+	// exported. TypeScript adds an empty export clause to avoid this.
+	if isModule && ((scope == tsDeclModuleScope && !hasModuleIndicator) || (needsScopeFix && !hasScopeMarker)) {
+		sb.WriteString("export {};\n")
+	}
+	return sb.String()
+}
+
+func (g *tsDeclGenerator) classifyStmt(s tsDeclStmt, c *tsDeclCandidate) {
+	switch d := s.stmt.Data.(type) {
+	case *js_ast.STypeScript:
+		tokens := tsDeclTokens(g.source[s.start:s.end], 4)
+		if len(tokens) == 0 {
+			return
+		}
+		switch tokens[0] {
+		case "export":
+			c.kind = tsDeclExported
+			if tsDeclIsClause(tokens) {
+				c.kind = tsDeclExportClause
+			} else if len(tokens) > 2 && tokens[1] == "function" {
+				// "export function foo(): void;"
+				c.names = []string{tokens[2]}
+				c.addsDeclare = true
+			} else if len(tokens) > 3 && tokens[1] == "default" && tokens[2] == "function" {
+				// "export default function foo(): void;"
+				c.names = []string{tokens[3]}
+			}
+
+		case "import":
+			c.kind = tsDeclImport
+			c.names = tsDeclImportedNames(g.source[s.start:s.end])
+
+		case "declare":
+			if len(tokens) > 1 && (tokens[1] == "global" || (tokens[1] == "module" && len(tokens) > 2 && tsDeclIsString(tokens[2]))) {
+				c.kind = tsDeclGlobal
+				return
+			}
+			c.kind = tsDeclLocal
+			c.names = tsDeclNameAfterKeywords(tokens[1:])
+
+		default:
+			c.kind = tsDeclLocal
+			c.names = tsDeclNameAfterKeywords(tokens)
+
+			// "function foo(): void;"
+			if tokens[0] == "function" {
+				c.addsDeclare = true
+			}
+		}
+
+	case *js_ast.SFunction:
+		c.kind = tsDeclLocalOrExported(d.IsExport)
+		c.names = []string{g.nameAt(d.Fn.Name.Loc)}
+
+	case *js_ast.SClass:
+		c.kind = tsDeclLocalOrExported(d.IsExport)
+		c.names = []string{g.nameAt(d.Class.Name.Loc)}
+
+	case *js_ast.SEnum:
+		c.kind = tsDeclLocalOrExported(d.IsExport)
+		c.names = []string{g.nameAt(d.Name.Loc)}
+
+	case *js_ast.SNamespace:
+		c.kind = tsDeclLocalOrExported(d.IsExport)
+		c.names = []string{g.nameAt(d.Name.Loc)}
+
+	case *js_ast.SLocal:
+		if d.WasTSImportEquals && !d.IsExport {
+			c.kind = tsDeclImport
+		} else {
+			c.kind = tsDeclLocalOrExported(d.IsExport)
+		}
+		js_ast.ForEachIdentifierBindingInDecls(d.Decls, func(loc logger.Loc, b *js_ast.BIdentifier) {
+			c.names = append(c.names, g.nameAt(loc))
+		})
+
+	case *js_ast.SImport:
+		c.kind = tsDeclImport
+		c.names = tsDeclImportedNames(g.source[s.start:s.end])
+
+	case *js_ast.SExportClause, *js_ast.SExportFrom, *js_ast.SExportStar, *js_ast.SExportEquals:
+		c.kind = tsDeclExportClause
+
+	case *js_ast.SExportDefault:
+		c.kind = tsDeclExported
+		switch v := d.Value.Data.(type) {
+		case *js_ast.SFunction:
+			if v.Fn.Name != nil {
+				c.names = []string{g.nameAt(v.Fn.Name.Loc)}
+			}
+		case *js_ast.SClass:
+			if v.Class.Name != nil {
+				c.names = []string{g.nameAt(v.Class.Name.Loc)}
+			}
+		}
+	}
+}
+
+// This is conservative and may include names that aren't imported (e.g. the
+// name of the export that's being renamed in "import { a as b } from 'c'")
+func tsDeclImportedNames(text string) (names []string) {
+	tsDeclForEachIdentifier(text, func(name string) {
+		switch name {
+		case "import", "type", "typeof", "from", "as", "require", "export":
+		default:
+			names = append(names, name)
+		}
+	})
+	return
+}
+
+func tsDeclLocalOrExported(isExport bool) tsDeclKind {
+	if isExport {
+		return tsDeclExported
+	}
+	return tsDeclLocal
+}
+
+// "interface Foo {}"
+// "declare const enum Foo {}"
+// "abstract class Foo {}"
+func tsDeclNameAfterKeywords(tokens []string) []string {
+	for i, token := range tokens {
+		switch token {
+		case "abstract", "async", "declare", "const", "let", "var", "enum", "class", "function",
+			"interface", "type", "namespace", "module":
+			continue
+		}
+		if i > 0 && js_ast.IsIdentifier(token) {
+			return []string{token}
+		}
+		break
+	}
+	return nil
+}
+
+func (g *tsDeclGenerator) stmtText(s tsDeclStmt, scope tsDeclScope, addsDeclare bool) string {
+	indent := g.lineIndentation(s.start)
+	switch d := s.stmt.Data.(type) {
+	case *js_ast.STypeScript:
+		text := strings.TrimSpace(g.source[s.start:s.end])
+		if tsDeclIsClause(tsDeclTokens(text, 3)) {
+			text = tsDeclWithClauseSemicolon(text)
+		} else {
+			text = tsDeclWithSemicolon(text)
+		}
+		if addsDeclare {
+			text = g.withDeclare(text, strings.HasPrefix(text, "export"), scope)
+		}
+		return tsDeclReindent(text, indent)
+
+	case *js_ast.SFunction:
+		return tsDeclReindent(g.prefix(d.IsExport, scope)+g.fnText(&d.Fn), indent)
+
+	case *js_ast.SClass:
+		return g.classText(&d.Class, g.prefix(d.IsExport, scope))
+
+	case *js_ast.SLocal:
+		if d.WasTSImportEquals {
+			return tsDeclReindent(tsDeclWithSemicolon(strings.TrimSpace(g.source[s.start:s.end])), indent)
+		}
+		return tsDeclReindent(g.localText(d, g.prefix(d.IsExport, scope)), indent)
+
+	case *js_ast.SEnum:
+		return tsDeclReindent(g.withDeclare(strings.TrimSpace(g.source[s.start:s.end]), d.IsExport, scope), indent)
+
+	case *js_ast.SNamespace:
+		return g.namespaceText(s, d.IsExport, scope)
+
+	case *js_ast.SImport, *js_ast.SExportClause, *js_ast.SExportFrom, *js_ast.SExportStar:
+		return tsDeclReindent(tsDeclWithClauseSemicolon(strings.TrimSpace(g.source[s.start:s.end])), indent)
+
+	case *js_ast.SExportEquals:
+		if _, ok := d.Value.Data.(*js_ast.EIdentifier); !ok {
+			g.addError(logger.Range{Loc: d.Value.Loc}, "Default exports can't be inferred")
+			return ""
+		}
+		return tsDeclWithSemicolon(strings.TrimSpace(g.source[s.start:s.end]))
+
+	case *js_ast.SExportDefault:
+		switch v := d.Value.Data.(type) {
+		case *js_ast.SFunction:
+			return tsDeclReindent("export default "+g.fnText(&v.Fn), indent)
+		case *js_ast.SClass:
+			return g.classText(&v.Class, "export default ")
+		case *js_ast.SExpr:
+			if _, ok := v.Value.Data.(*js_ast.EIdentifier); ok {
+				return tsDeclWithSemicolon(strings.TrimSpace(g.source[s.start:s.end]))
+			}
+			g.addError(logger.Range{Loc: v.Value.Loc}, "Default exports can't be inferred")
+		}
+	}
+	return ""
+}
+
+// Everything outside of a namespace must be "declare" in a declaration file
+func (g *tsDeclGenerator) prefix(isExport bool, scope tsDeclScope) string {
+	switch {
+	case scope == tsDeclNamespaceScope && isExport:
+		return "export "
+	case scope == tsDeclNamespaceScope:
+		return ""
+	case isExport:
+		return "export declare "
+	default:
+		return "declare "
+	}
+}
+
+func (g *tsDeclGenerator) withDeclare(text string, isExport bool, scope tsDeclScope) string {
+	if scope == tsDeclNamespaceScope {
+		return text
+	}
+	if isExport {
+		return "export declare " + strings.TrimLeft(strings.TrimPrefix(text, "export"), " \t\r\n")
+	}
+	return "declare " + text
+}
+
+func (g *tsDeclGenerator) namespaceText(s tsDeclStmt, isExport bool, scope tsDeclScope) string {
+	// The statement list for the namespace body is the outermost one inside the namespace
+	var body *tsDeclStmtList
+	for _, list := range g.p.tsDeclaration.namespaceStmts {
+		if list.loc.Start > s.start && list.loc.Start < s.end && (body == nil || list.loc.Start < body.loc.Start) {
+			body = list
+		}
+	}
+	if body == nil {
+		return ""
+	}
+
+	header := g.withDeclare(strings.TrimSpace(g.source[s.start:body.start]), isExport, scope)
+	return tsDeclReindent(header, g.lineIndentation(s.start)) + "\n" + tsDeclIndent(g.printStmts(body, tsDeclNamespaceScope)) + "}"
+}
+
+func (g *tsDeclGenerator) fnText(fn *js_ast.Fn) string {
+	name := ""
+	r := logger.Range{Loc: fn.OpenParenLoc, Len: 1}
+	if fn.Name != nil {
+		r = js_lexer.RangeOfIdentifier(g.p.source, fn.Name.Loc)
+		name = " " + g.text(r)
+	}
+	decl := g.p.tsDeclaration.fns[fn.OpenParenLoc]
+	if decl.returnType.Len == 0 {
+		g.addError(r, "Function must have an explicit return type annotation")
+	}
+	return "function" + name + g.signature(decl, false) + ";"
+}
+
+// This generates "<T>(a: T): T" or, for function types, "<T>(a: T) => T"
+func (g *tsDeclGenerator) signature(decl tsDeclFn, isFunctionType bool) string {
+	sb := strings.Builder{}
+	if decl.typeParameters.Len > 0 {
+		sb.WriteString(g.text(decl.typeParameters))
+	}
+	sb.WriteByte('(')
+	if decl.thisArg.Len > 0 {
+		sb.WriteString(g.text(decl.thisArg))
+		if len(decl.args) > 0 {
+			sb.WriteString(", ")
+		}
+	}
+	for i := range decl.args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(g.argText(decl.args, i))
+	}
+	sb.WriteByte(')')
+	if decl.returnType.Len > 0 {
+		if isFunctionType {
+			sb.WriteString(" => ")
+		} else {
+			sb.WriteString(": ")
+		}
+		sb.WriteString(g.text(decl.returnType))
+	}
+	return sb.String()
+}
+
+func (g *tsDeclGenerator) argText(args []tsDeclArg, i int) string {
+	arg := args[i]
+	binding := g.text(arg.binding)
+	isOptional := arg.isOptional
+
+	// Arrow function arguments are parsed as expressions, which includes the "?"
+	if strings.HasSuffix(binding, "?") {
+		binding = strings.TrimSpace(binding[:len(binding)-1])
+		isOptional = true
+	}
+
+	typeText := ""
+	if arg.typeRange.Len > 0 {
+		typeText = g.text(arg.typeRange)
+	}
+
+	if arg.defaultOrNil.Data != nil {
+		// Arguments with default values are optional unless a required argument follows
+		isOptional = true
+		for _, after := range args[i+1:] {
+			if !after.isOptional && !after.isRest && after.defaultOrNil.Data == nil {
+				isOptional = false
+				break
+			}
+		}
+
+		if typeText == "" {
+			if typeText = tsDeclPrimitiveType(arg.defaultOrNil); typeText == "" {
+				g.addError(arg.binding, "Parameter must have an explicit type annotation")
+			}
+		}
+	}
+
+	sb := strings.Builder{}
+	if arg.isRest {
+		sb.WriteString("...")
+	}
+	sb.WriteString(binding)
+	if isOptional {
+		sb.WriteByte('?')
+	}
+	if typeText != "" {
+		sb.WriteString(": ")
+		sb.WriteString(typeText)
+	}
+	return sb.String()
+}
+
+func (g *tsDeclGenerator) localText(local *js_ast.SLocal, prefix string) string {
+	keyword := "const"
+	switch local.Kind {
+	case js_ast.LocalVar:
+		keyword = "var"
+	case js_ast.LocalLet:
+		keyword = "let"
+	}
+
+	var decls []string
+	for _, d := range local.Decls {
+		decl := g.p.tsDeclaration.decls[d.Binding.Loc]
+		if _, ok := d.Binding.Data.(*js_ast.BIdentifier); !ok {
+			g.addError(decl.binding, "Binding elements can't be exported directly")
+			continue
+		}
+		text := g.text(decl.binding)
+
+		if decl.typeRange.Len > 0 {
+			text += ": " + g.text(decl.typeRange)
+		} else if d.ValueOrNil.Data != nil {
+			if constType, ok := g.asConstType(d.ValueOrNil, decl.valueEnd); ok {
+				text += ": " + constType
+			} else if literal := g.literalText(d.ValueOrNil, decl.valueEnd); literal != "" && keyword == "const" {
+				text += " = " + literal
+			} else if primitive := tsDeclPrimitiveType(d.ValueOrNil); primitive != "" {
+				text += ": " + primitive
+			} else if fnType, ok := g.fnTypeText(d.ValueOrNil); ok {
+				if fnType != "" {
+					text += ": " + fnType
+				}
+			} else {
+				g.addError(decl.binding, "Variable must have an explicit type annotation")
+			}
+		}
+
+		decls = append(decls, text)
+	}
+
+	if len(decls) == 0 {
+		return ""
+	}
+	return prefix + keyword + " " + strings.Join(decls, ", ") + ";"
+}
+
+// A function expression or an arrow function has an obvious type if all of
+// its argument types and its return type are present. This returns false if
+// the value isn't a function, and an empty string if an error was reported.
+func (g *tsDeclGenerator) fnTypeText(value js_ast.Expr) (string, bool) {
+	var decl tsDeclFn
+	var ok bool
+	switch e := value.Data.(type) {
+	case *js_ast.EArrow:
+		decl, ok = g.p.tsDeclaration.arrows[value.Loc]
+	case *js_ast.EFunction:
+		decl, ok = g.p.tsDeclaration.fns[e.Fn.OpenParenLoc]
+	}
+	if !ok {
+		return "", false
+	}
+	if decl.returnType.Len == 0 {
+		g.addError(logger.Range{Loc: value.Loc}, "Function must have an explicit return type annotation")
+		return "", true
+	}
+	return g.signature(decl, true), true
+}
+
+// This is the text of a literal value that can be used as the initializer of
+// a "const" variable or a "readonly" property in a declaration file
+func (g *tsDeclGenerator) literalText(value js_ast.Expr, end int32) string {
+	switch e := value.Data.(type) {
+	case *js_ast.EString:
+		return string(helpers.QuoteForJSON(helpers.UTF16ToString(e.Value), false))
+
+	case *js_ast.ENumber, *js_ast.EBigInt, *js_ast.EBoolean:
+	case *js_ast.EUnary:
+		if e.Op != js_ast.UnOpNeg {
+			return ""
+		}
+		switch e.Value.Data.(type) {
+		case *js_ast.ENumber, *js_ast.EBigInt:
+		default:
+			return ""
+		}
+
+	default:
+		return ""
+	}
+
+	// Don't use the original text if it's complicated (e.g. has parentheses)
+	text := strings.TrimSpace(g.source[value.Loc.Start:end])
+	if strings.ContainsAny(text, "()/\r\n") {
+		return ""
+	}
+	return text
+}
+
+// This is the type of a value whose type is obvious from its syntax
+// An initializer ending in "as const" has the type of its literal value, with
+// arrays and objects becoming readonly (e.g. "[1, 'a'] as const" has the type
+// "readonly [1, \"a\"]"). This returns false if the initializer doesn't end
+// in "as const" or if its value isn't made of literals.
+func (g *tsDeclGenerator) asConstType(value js_ast.Expr, end int32) (string, bool) {
+	text := strings.TrimRight(strings.TrimSuffix(strings.TrimSpace(g.source[value.Loc.Start:end]), "const"), " \t\r\n")
+	if len(text) < 3 || !strings.HasSuffix(text, "as") || js_ast.IsIdentifierContinue(rune(text[len(text)-3])) {
+		return "", false
+	}
+	return tsDeclConstType(value)
+}
+
+func tsDeclConstType(value js_ast.Expr) (string, bool) {
+	switch e := value.Data.(type) {
+	case *js_ast.EString:
+		return string(helpers.QuoteForJSON(helpers.UTF16ToString(e.Value), false)), true
+
+	case *js_ast.ETemplate:
+		if e.TagOrNil.Data == nil && len(e.Parts) == 0 {
+			return string(helpers.QuoteForJSON(helpers.UTF16ToString(e.HeadCooked), false)), true
+		}
+
+	case *js_ast.ENumber:
+		return js_ast.TryToStringOnNumberSafely(e.Value, 10)
+
+	case *js_ast.EBigInt:
+		return e.Value + "n", true
+
+	case *js_ast.EBoolean, *js_ast.ENull:
+		return js_ast.ToStringWithoutSideEffects(e)
+
+	case *js_ast.EUnary:
+		if e.Op == js_ast.UnOpNeg {
+			switch e.Value.Data.(type) {
+			case *js_ast.ENumber, *js_ast.EBigInt:
+				if text, ok := tsDeclConstType(e.Value); ok {
+					return "-" + text, true
+				}
+			}
+		}
+
+	case *js_ast.EArray:
+		items := make([]string, 0, len(e.Items))
+		for _, item := range e.Items {
+			text, ok := tsDeclConstType(item)
+			if !ok {
+				return "", false
+			}
+			items = append(items, text)
+		}
+		return "readonly [" + strings.Join(items, ", ") + "]", true
+
+	case *js_ast.EObject:
+		props := make([]string, 0, len(e.Properties))
+		for _, property := range e.Properties {
+			if property.Kind != js_ast.PropertyNormal || property.Flags.Has(js_ast.PropertyIsComputed) || property.Flags.Has(js_ast.PropertyIsMethod) || property.ValueOrNil.Data == nil {
+				return "", false
+			}
+			var key string
+			switch k := property.Key.Data.(type) {
+			case *js_ast.EString:
+				key = helpers.UTF16ToString(k.Value)
+				if !js_ast.IsIdentifier(key) {
+					key = string(helpers.QuoteForJSON(key, false))
+				}
+			case *js_ast.ENumber:
+				text, ok := js_ast.TryToStringOnNumberSafely(k.Value, 10)
+				if !ok {
+					return "", false
+				}
+				key = text
+			default:
+				return "", false
+			}
+			text, ok := tsDeclConstType(property.ValueOrNil)
+			if !ok {
+				return "", false
+			}
+			props = append(props, "readonly "+key+": "+text)
+		}
+		if len(props) == 0 {
+			return "{}", true
+		}
+		return "{ " + strings.Join(props, "; ") + " }", true
+	}
+	return "", false
+}
+
+func tsDeclPrimitiveType(value js_ast.Expr) string {
+	switch e := value.Data.(type) {
+	case *js_ast.EString, *js_ast.ETemplate:
+		if t, ok := e.(*js_ast.ETemplate); ok && t.TagOrNil.Data != nil {
+			return ""
+		}
+		return "string"
+	case *js_ast.ENumber:
+		return "number"
+	case *js_ast.EBigInt:
+		return "bigint"
+	case *js_ast.EBoolean:
+		return "boolean"
+	case *js_ast.EUnary:
+		if e.Op == js_ast.UnOpNeg || e.Op == js_ast.UnOpPos {
+			switch e.Value.Data.(type) {
+			case *js_ast.ENumber:
+				return "number"
+			case *js_ast.EBigInt:
+				if e.Op == js_ast.UnOpNeg {
+					return "bigint"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (g *tsDeclGenerator) classText(class *js_ast.Class, prefix string) string {
+	sb := strings.Builder{}
+	sb.WriteString(prefix)
+
+	// Decorators are omitted, so the "abstract" keyword has to be found again
+	before := strings.TrimRight(g.source[:class.ClassKeyword.Loc.Start], " \t\r\n")
+	if strings.HasSuffix(before, "abstract") && (len(before) == 8 || !js_ast.IsIdentifierContinue(rune(before[len(before)-9]))) {
+		sb.WriteString("abstract ")
+	}
+
+	sb.WriteString("class")
+	sb.WriteString(tsDeclReindent(strings.TrimRight(g.source[class.ClassKeyword.End():class.BodyLoc.Start], " \t\r\n"),
+		g.lineIndentation(class.ClassKeyword.Loc.Start)))
+	sb.WriteString(" {\n")
+
+	// Base classes are only allowed to be references to other declarations
+	if class.ExtendsOrNil.Data != nil && !tsDeclIsEntityName(class.ExtendsOrNil) {
+		g.addError(logger.Range{Loc: class.ExtendsOrNil.Loc}, "Extends clause can't contain an expression")
+	}
+
+	prevEnd := class.BodyLoc.Start + 1
+	overloadKey := ""
+	hasPrivateName := false
+	for _, member := range g.p.tsDeclaration.classes[class.BodyLoc] {
+		leading := g.leadingComment(prevEnd, member.start)
+		indent := g.lineIndentation(member.start)
+		prevEnd = member.end
+		decl, hasDecl := g.p.tsDeclaration.props[member.propertyLoc]
+		var property *js_ast.Property
+		if member.propertyIndex != -1 {
+			property = &class.Properties[member.propertyIndex]
+		}
+
+		// Copy TypeScript-only class members over, but omit the implementation
+		// of overloaded methods
+		if property == nil || property.Kind == js_ast.PropertyDeclareOrAbstract {
+			overloadKey = ""
+			if hasDecl && decl.isOverload {
+				overloadKey = g.memberKey(decl)
+			}
+			if text := tsDeclWithoutDeclareModifier(strings.TrimSpace(g.source[member.propertyLoc.Start:member.end])); text != "" {
+				sb.WriteString(tsDeclIndent(leading + tsDeclReindent(tsDeclWithSemicolon(text), indent)))
+				sb.WriteByte('\n')
+			}
+			continue
+		}
+		isOverloadImplementation := hasDecl && overloadKey != "" && g.memberKey(decl) == overloadKey
+		overloadKey = ""
+		if isOverloadImplementation {
+			continue
+		}
+
+		var text string
+		switch {
+		case property.Kind == js_ast.PropertyClassStaticBlock:
+
+		case !hasDecl:
+
+		case tsDeclIsPrivateName(property.Key):
+			// All private names are replaced with a single "#private" member
+			if !hasPrivateName {
+				hasPrivateName = true
+				text = "#private;"
+			}
+
+		default:
+			text = g.memberText(class, property, decl)
+		}
+
+		if text != "" {
+			sb.WriteString(tsDeclIndent(leading + tsDeclReindent(text, indent)))
+			sb.WriteByte('\n')
+		}
+	}
+
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+func (g *tsDeclGenerator) memberKey(decl tsDeclProp) string {
+	if decl.isStatic {
+		return "static " + g.text(decl.key)
+	}
+	return g.text(decl.key)
+}
+
+func (g *tsDeclGenerator) memberText(class *js_ast.Class, property *js_ast.Property, decl tsDeclProp) string {
+	sb := strings.Builder{}
+	if decl.isPrivate {
+		sb.WriteString("private ")
+	} else if decl.isProtected {
+		sb.WriteString("protected ")
+	}
+	if decl.isStatic {
+		sb.WriteString("static ")
+	}
+	key := g.text(decl.key)
+	optional := ""
+	if decl.isOptional {
+		optional = "?"
+	}
+
+	fn, isMethod := property.ValueOrNil.Data.(*js_ast.EFunction)
+	if !isMethod {
+		// Class fields
+		if decl.isReadonly {
+			sb.WriteString("readonly ")
+		}
+		if property.Kind == js_ast.PropertyAutoAccessor {
+			sb.WriteString("accessor ")
+		}
+		sb.WriteString(key)
+		sb.WriteString(optional)
+		if !decl.isPrivate {
+			if decl.typeRange.Len > 0 {
+				sb.WriteString(": ")
+				sb.WriteString(g.text(decl.typeRange))
+			} else if init := property.InitializerOrNil; init.Data != nil {
+				if constType, ok := g.asConstType(init, decl.initializerEnd); ok {
+					sb.WriteString(": ")
+					sb.WriteString(constType)
+				} else if literal := g.literalText(init, decl.initializerEnd); literal != "" && decl.isReadonly && !decl.isOptional {
+					sb.WriteString(" = ")
+					sb.WriteString(literal)
+				} else if primitive := tsDeclPrimitiveType(init); primitive != "" {
+					sb.WriteString(": ")
+					sb.WriteString(primitive)
+				} else if fnType, ok := g.fnTypeText(init); ok {
+					if fnType != "" {
+						sb.WriteString(": ")
+						sb.WriteString(fnType)
+					}
+				} else {
+					g.addError(decl.key, "Property must have an explicit type annotation")
+				}
+			}
+		}
+		sb.WriteByte(';')
+		return sb.String()
+	}
+
+	// Methods
+	isConstructor := false
+	if str, ok := property.Key.Data.(*js_ast.EString); ok && !decl.isStatic &&
+		!property.Flags.Has(js_ast.PropertyIsComputed) && helpers.UTF16EqualsString(str.Value, "constructor") {
+		isConstructor = true
+	}
+	if decl.isPrivate && !isConstructor {
+		sb.WriteString(key)
+		sb.WriteString(optional)
+		sb.WriteByte(';')
+		return sb.String()
+	}
+	fnDecl := g.p.tsDeclaration.fns[fn.Fn.OpenParenLoc]
+	switch property.Kind {
+	case js_ast.PropertyGet:
+		sb.WriteString("get ")
+		if fnDecl.returnType.Len == 0 {
+			// A getter without a return type can use the type of the setter
+			if setterType := g.accessorType(class, decl, js_ast.PropertySet); setterType.Len > 0 {
+				fnDecl.returnType = setterType
+			} else {
+				g.addError(decl.key, "At least one accessor must have an explicit type annotation")
+			}
+		}
+	case js_ast.PropertySet:
+		sb.WriteString("set ")
+		if len(fnDecl.args) > 0 && fnDecl.args[0].typeRange.Len == 0 {
+			// A setter without an argument type can use the type of the getter
+			if getterType := g.accessorType(class, decl, js_ast.PropertyGet); getterType.Len > 0 {
+				fnDecl.args = append([]tsDeclArg{}, fnDecl.args...)
+				fnDecl.args[0].typeRange = getterType
+			}
+		}
+	default:
+		if fnDecl.returnType.Len == 0 && !isConstructor {
+			g.addError(decl.key, "Method must have an explicit return type annotation")
+		}
+	}
+	sb.WriteString(key)
+	sb.WriteString(optional)
+	sb.WriteString(g.signature(fnDecl, false))
+	sb.WriteByte(';')
+
+	// Parameter properties become class fields
+	if isConstructor {
+		var fields []string
+		for i, arg := range fn.Fn.Args {
+			if arg.IsTypeScriptCtorField && i < len(fnDecl.args) {
+				fields = append(fields, g.parameterPropertyText(fnDecl.args[i]))
+			}
+		}
+		if len(fields) > 0 {
+			return strings.Join(fields, "\n") + "\n" + sb.String()
+		}
+	}
+	return sb.String()
+}
+
+// This returns the type annotation of the getter or setter with the same name
+// as the given accessor, which is the getter's return type or the setter's
+// argument type
+func (g *tsDeclGenerator) accessorType(class *js_ast.Class, accessorDecl tsDeclProp, kind js_ast.PropertyKind) logger.Range {
+	key := g.memberKey(accessorDecl)
+	for i := range class.Properties {
+		property := &class.Properties[i]
+		if property.Kind != kind {
+			continue
+		}
+		if decl, ok := g.p.tsDeclaration.props[property.Loc]; ok && g.memberKey(decl) == key {
+			if fn, ok := property.ValueOrNil.Data.(*js_ast.EFunction); ok {
+				fnDecl := g.p.tsDeclaration.fns[fn.Fn.OpenParenLoc]
+				if kind == js_ast.PropertyGet {
+					return fnDecl.returnType
+				}
+				if len(fnDecl.args) > 0 {
+					return fnDecl.args[0].typeRange
+				}
+			}
+		}
+	}
+	return logger.Range{}
+}
+
+// "constructor(private readonly x: number) {}" => "private readonly x;"
+func (g *tsDeclGenerator) parameterPropertyText(arg tsDeclArg) string {
+	sb := strings.Builder{}
+	isPrivate := false
+	for _, modifier := range tsDeclTokens(g.source[arg.start:arg.binding.Loc.Start], -1) {
+		switch modifier {
+		case "private":
+			isPrivate = true
+			fallthrough
+		case "protected", "readonly":
+			sb.WriteString(modifier)
+			sb.WriteByte(' ')
+		}
+	}
+	sb.WriteString(g.text(arg.binding))
+	if arg.isOptional {
+		sb.WriteByte('?')
+	}
+	if !isPrivate {
+		if arg.typeRange.Len > 0 {
+			sb.WriteString(": ")
+			sb.WriteString(g.text(arg.typeRange))
+		} else if arg.defaultOrNil.Data != nil {
+			if primitive := tsDeclPrimitiveType(arg.defaultOrNil); primitive != "" {
+				sb.WriteString(": ")
+				sb.WriteString(primitive)
+			}
+		}
+	}
+	sb.WriteByte(';')
+	return sb.String()
+}
+
+// This returns any documentation comment immediately before a statement or
+// class member, without the indentation from the original source
+func (g *tsDeclGenerator) leadingComment(prevEnd int32, start int32) string {
+	gap := g.source[prevEnd:start]
+	i := len(gap)
+	for i > 0 && (gap[i-1] == ' ' || gap[i-1] == '\t') {
+		i--
+	}
+	if i == 0 || gap[i-1] != '\n' {
+		return ""
+	}
+
+	before := strings.TrimRight(gap[:i], " \t\r\n")
+	if strings.HasSuffix(before, "*/") {
+		if j := strings.LastIndex(before, "/**"); j != -1 && len(before)-j >= 5 {
+			k := j
+			for k > 0 && (before[k-1] == ' ' || before[k-1] == '\t') {
+				k--
+			}
+			if k == 0 || before[k-1] == '\n' {
+				return tsDeclDedent(before[j:], before[k:j]) + "\n"
+			}
+		}
+	}
+	return ""
+}
+
+// This returns the indentation of the line containing the given position
+func (g *tsDeclGenerator) lineIndentation(pos int32) string {
+	start := pos
+	for start > 0 && g.source[start-1] != '\n' && g.source[start-1] != '\r' {
+		start--
+	}
+	end := start
+	for end < pos && (g.source[end] == ' ' || g.source[end] == '\t') {
+		end++
+	}
+	return g.source[start:end]
+}
+
+func tsDeclIsEntityName(expr js_ast.Expr) bool {
+	switch e := expr.Data.(type) {
+	case *js_ast.EIdentifier:
+		return true
+	case *js_ast.EDot:
+		return tsDeclIsEntityName(e.Target)
+	}
+	return false
+}
+
+func tsDeclIsPrivateName(key js_ast.Expr) bool {
+	_, ok := key.Data.(*js_ast.EPrivateIdentifier)
+	return ok
+}
+
+func tsDeclIsString(token string) bool {
+	return len(token) > 0 && (token[0] == '"' || token[0] == '\'')
+}
+
+func tsDeclWithSemicolon(text string) string {
+	if strings.HasSuffix(text, ";") || strings.HasSuffix(text, "}") {
+		return text
+	}
+	return text + ";"
+}
+
+// "import ..."
+// "export { ... }"
+// "export * from ..."
+// "export = ..."
+// "export type { ... }"
+func tsDeclIsClause(tokens []string) bool {
+	if len(tokens) > 0 && tokens[0] == "import" {
+		return true
+	}
+	if len(tokens) > 1 && tokens[0] == "export" {
+		switch tokens[1] {
+		case "{", "*", "=":
+			return true
+		case "type":
+			return len(tokens) > 2 && (tokens[2] == "{" || tokens[2] == "*")
+		}
+	}
+	return false
+}
+
+// Import and export clauses end with a semicolon even if they end with a brace
+func tsDeclWithClauseSemicolon(text string) string {
+	if strings.HasSuffix(text, ";") {
+		return text
+	}
+	return text + ";"
+}
+
+// A "declare" modifier on a class member isn't allowed in a declaration file
+func tsDeclWithoutDeclareModifier(text string) string {
+	rest := text
+	for {
+		tokens := tsDeclTokens(rest, 2)
+		if len(tokens) < 2 || !js_ast.IsIdentifier(tokens[1]) {
+			return text
+		}
+		switch tokens[0] {
+		case "declare":
+			index := strings.Index(rest, "declare")
+			return text[:len(text)-len(rest)] + strings.TrimLeft(rest[index+len("declare"):], " \t\r\n")
+		case "private", "protected", "public", "static", "readonly", "override":
+			index := strings.Index(rest, tokens[0])
+			rest = rest[index+len(tokens[0]):]
+		default:
+			return text
+		}
+	}
+}
+
+// This splits the text into identifiers, strings, and single punctuation
+// characters while ignoring whitespace and comments. A negative limit means
+// there is no limit.
+func tsDeclTokens(text string, limit int) (tokens []string) {
+	tsDeclScan(text, func(token string) bool {
+		tokens = append(tokens, token)
+		return limit < 0 || len(tokens) < limit
+	})
+	return
+}
+
+func tsDeclForEachIdentifier(text string, callback func(string)) {
+	tsDeclScan(text, func(token string) bool {
+		if js_ast.IsIdentifier(token) {
+			callback(token)
+		}
+		return true
+	})
+}
+
+func tsDeclScan(text string, callback func(string) bool) {
+	i := 0
+	for i < len(text) {
+		c := text[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+
+		case c == '/' && i+1 < len(text) && text[i+1] == '/':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			continue
+
+		case c == '/' && i+1 < len(text) && text[i+1] == '*':
+			if end := strings.Index(text[i+2:], "*/"); end != -1 {
+				i += end + 4
+			} else {
+				i = len(text)
+			}
+			continue
+
+		case c == '"' || c == '\'' || c == '`':
+			i++
+			for i < len(text) && text[i] != c {
+				if text[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i < len(text) {
+				i++
+			}
+
+		case c >= '0' && c <= '9':
+			for i < len(text) && (text[i] == '.' || js_ast.IsIdentifierContinue(rune(text[i]))) {
+				i++
+			}
+
+		case c == '$' || c == '_' || c == '#' || c >= 0x80 || (c|0x20 >= 'a' && c|0x20 <= 'z'):
+			i++
+			for i < len(text) && (text[i] >= 0x80 || js_ast.IsIdentifierContinue(rune(text[i]))) {
+				i++
+			}
+
+		default:
+			i++
+		}
+		if i > len(text) {
+			i = len(text)
+		}
+		if !callback(text[start:i]) {
+			return
+		}
+	}
+}
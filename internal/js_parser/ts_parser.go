@@ -545,6 +545,326 @@ loop:
 	}
 }
 
+// This skips over a type annotation like "skipTypeScriptType" does but also
+// serializes it for TypeScript's "emitDecoratorMetadata" setting. Only syntax
+// is considered, which approximates what the TypeScript compiler does when
+// "isolatedModules" is enabled. Rest arguments use the array's element type.
+func (p *parser) skipTypeScriptTypeForDecoratorMetadata(flags skipTypeFlags, isRestArg bool) js_ast.TSMetadataType {
+	oldLexer := p.lexer
+	p.skipTypeScriptTypeWithFlags(js_ast.LLowest, flags)
+	newLexer := p.lexer
+
+	// Scan over the type a second time now that we know it's valid. Anything
+	// that isn't understood is serialized as "Object" like TypeScript does.
+	result := js_ast.TSMetadataType{Name: "Object"}
+	p.lexer = oldLexer
+	p.lexer.IsLogDisabled = true
+	func() {
+		defer func() {
+			r := recover()
+			if _, isLexerPanic := r.(js_lexer.LexerPanic); !isLexerPanic && r != nil {
+				panic(r)
+			}
+		}()
+		t, stop := p.serializeTypeScriptUnionType()
+		if stop || p.lexer.Loc() == newLexer.Loc() {
+			if !isRestArg {
+				result = t.TSMetadataType
+			} else if t.arrayElementOrNil != nil {
+				result = *t.arrayElementOrNil
+			}
+		}
+	}()
+	p.lexer = newLexer
+	return result
+}
+
+type tsSerializedType struct {
+	js_ast.TSMetadataType
+
+	// This is set for "T[]" so that rest arguments can use "T"
+	arrayElementOrNil *js_ast.TSMetadataType
+
+	// TypeScript leaves "null", "undefined", and "never" out of unions
+	isOmittedFromUnion bool
+}
+
+// These return "stop" as true if the rest of the type doesn't affect the
+// result. Otherwise the caller checks that all of the type was consumed.
+func (p *parser) serializeTypeScriptUnionType() (result tsSerializedType, stop bool) {
+	object := tsSerializedType{TSMetadataType: js_ast.TSMetadataType{Name: "Object"}}
+	count := 0
+
+	// "| A | B"
+	// "& A & B"
+	if p.lexer.Token == js_lexer.TBar || p.lexer.Token == js_lexer.TAmpersand {
+		p.lexer.Next()
+	}
+
+	for {
+		t, stop := p.serializeTypeScriptPostfixType()
+		if stop {
+			return t, true
+		}
+
+		// Unions of different types are serialized as "Object"
+		if !t.isOmittedFromUnion {
+			if t.EntityOrNil.Data == nil && t.Name == "Object" {
+				return object, true
+			}
+			if count == 0 {
+				result = t
+			} else if result.EntityOrNil.Data != nil || t.EntityOrNil.Data != nil || result.Name == "" || result.Name != t.Name {
+				return object, true
+			} else {
+				result.arrayElementOrNil = nil
+			}
+			count++
+		} else if count == 0 && !result.isOmittedFromUnion {
+			result = t
+		}
+
+		if p.lexer.Token != js_lexer.TBar && p.lexer.Token != js_lexer.TAmpersand {
+			break
+		}
+		p.lexer.Next()
+	}
+
+	if count > 0 {
+		result.isOmittedFromUnion = false
+	}
+	return
+}
+
+func (p *parser) serializeTypeScriptPostfixType() (tsSerializedType, bool) {
+	t, stop := p.serializeTypeScriptPrimaryType()
+	if stop {
+		return t, true
+	}
+
+	// "string[]"
+	for p.lexer.Token == js_lexer.TOpenBracket && !p.lexer.HasNewlineBefore {
+		p.lexer.Next()
+		if p.lexer.Token != js_lexer.TCloseBracket {
+			// "Foo['bar']"
+			return tsSerializedType{TSMetadataType: js_ast.TSMetadataType{Name: "Object"}}, true
+		}
+		p.lexer.Next()
+		element := t.TSMetadataType
+		t = tsSerializedType{TSMetadataType: js_ast.TSMetadataType{Name: "Array"}, arrayElementOrNil: &element}
+	}
+
+	// "A extends B ? C : D"
+	if p.lexer.Token == js_lexer.TExtends {
+		return tsSerializedType{TSMetadataType: js_ast.TSMetadataType{Name: "Object"}}, true
+	}
+
+	return t, false
+}
+
+func (p *parser) serializeTypeScriptPrimaryType() (tsSerializedType, bool) {
+	named := func(name string) tsSerializedType {
+		return tsSerializedType{TSMetadataType: js_ast.TSMetadataType{Name: name}}
+	}
+
+	switch p.lexer.Token {
+	case js_lexer.TStringLiteral, js_lexer.TNoSubstitutionTemplateLiteral:
+		p.lexer.Next()
+		return named("String"), false
+
+	case js_lexer.TTemplateHead:
+		// "`${'a' | 'b'}-${'c' | 'd'}`"
+		return named("String"), true
+
+	case js_lexer.TNumericLiteral:
+		p.lexer.Next()
+		return named("Number"), false
+
+	case js_lexer.TBigIntegerLiteral:
+		p.lexer.Next()
+		return named("BigInt"), false
+
+	case js_lexer.TMinus:
+		// "-123"
+		// "-123n"
+		p.lexer.Next()
+		if p.lexer.Token == js_lexer.TBigIntegerLiteral {
+			p.lexer.Next()
+			return named("BigInt"), false
+		}
+		p.lexer.Next()
+		return named("Number"), false
+
+	case js_lexer.TTrue, js_lexer.TFalse:
+		p.lexer.Next()
+		return named("Boolean"), false
+
+	case js_lexer.TNull:
+		p.lexer.Next()
+		return tsSerializedType{isOmittedFromUnion: true}, false
+
+	case js_lexer.TVoid:
+		p.lexer.Next()
+		return tsSerializedType{}, false
+
+	case js_lexer.TOpenBracket:
+		// "[number, string]"
+		p.skipTypeScriptBalancedTokens()
+		return named("Array"), false
+
+	case js_lexer.TOpenParen:
+		// "(a: number) => void"
+		oldLexer := p.lexer
+		p.skipTypeScriptBalancedTokens()
+		if p.lexer.Token == js_lexer.TEqualsGreaterThan {
+			return named("Function"), true
+		}
+
+		// "(number | string)"
+		p.lexer = oldLexer
+		p.lexer.Next()
+		t, stop := p.serializeTypeScriptUnionType()
+		if stop {
+			return t, true
+		}
+		if p.lexer.Token != js_lexer.TCloseParen {
+			return named("Object"), true
+		}
+		p.lexer.Next()
+		return t, false
+
+	case js_lexer.TNew, js_lexer.TLessThan:
+		// "new () => Foo"
+		// "<T>() => Foo<T>"
+		return named("Function"), true
+
+	case js_lexer.TThis:
+		// "function check(): this is boolean"
+		p.lexer.Next()
+		if p.lexer.IsContextualKeyword("is") && !p.lexer.HasNewlineBefore {
+			return named("Boolean"), true
+		}
+
+	case js_lexer.TIdentifier:
+		switch p.lexer.Identifier.String {
+		case "string":
+			p.lexer.Next()
+			return named("String"), false
+
+		case "number":
+			p.lexer.Next()
+			return named("Number"), false
+
+		case "boolean":
+			p.lexer.Next()
+			return named("Boolean"), false
+
+		case "bigint":
+			p.lexer.Next()
+			return named("BigInt"), false
+
+		case "symbol":
+			p.lexer.Next()
+			return named("Symbol"), false
+
+		case "undefined", "never":
+			p.lexer.Next()
+			return tsSerializedType{isOmittedFromUnion: true}, false
+
+		case "unique":
+			// "unique symbol"
+			p.lexer.Next()
+			if p.lexer.IsContextualKeyword("symbol") {
+				p.lexer.Next()
+				return named("Symbol"), false
+			}
+			return named("Object"), true
+
+		case "readonly":
+			// "readonly string[]"
+			p.lexer.Next()
+			return p.serializeTypeScriptPostfixType()
+
+		case "asserts":
+			// "function assert(x: boolean): asserts x"
+			return named("Boolean"), true
+
+		case "any", "unknown", "object", "keyof", "infer", "abstract":
+			return named("Object"), true
+		}
+
+		// "Foo"
+		entity := js_ast.Expr{Loc: p.lexer.Loc(), Data: &js_ast.EIdentifier{Ref: p.storeNameInRef(p.lexer.Identifier)}}
+		p.lexer.Next()
+
+		// "function assert(x: any): x is boolean"
+		if p.lexer.IsContextualKeyword("is") && !p.lexer.HasNewlineBefore {
+			return named("Boolean"), true
+		}
+
+		// "Foo.Bar"
+		for p.lexer.Token == js_lexer.TDot {
+			p.lexer.Next()
+			if !p.lexer.IsIdentifierOrKeyword() {
+				return named("Object"), true
+			}
+			name := p.lexer.Raw()
+			if p.lexer.Token == js_lexer.TIdentifier {
+				name = p.lexer.Identifier.String
+			}
+			entity = js_ast.Expr{Loc: entity.Loc, Data: &js_ast.EDot{Target: entity, Name: name, NameLoc: p.lexer.Loc()}}
+			p.lexer.Next()
+		}
+
+		// "Foo<T>"
+		if p.lexer.Token == js_lexer.TLessThan && !p.lexer.HasNewlineBefore {
+			depth := 0
+			for {
+				switch p.lexer.Token {
+				case js_lexer.TLessThan:
+					depth++
+					p.lexer.Next()
+				case js_lexer.TGreaterThan, js_lexer.TGreaterThanEquals, js_lexer.TGreaterThanGreaterThan,
+					js_lexer.TGreaterThanGreaterThanEquals, js_lexer.TGreaterThanGreaterThanGreaterThan,
+					js_lexer.TGreaterThanGreaterThanGreaterThanEquals:
+					depth--
+					p.lexer.ExpectGreaterThan(false)
+				case js_lexer.TEndOfFile:
+					p.lexer.Unexpected()
+				default:
+					p.lexer.Next()
+				}
+				if depth == 0 {
+					break
+				}
+			}
+		}
+
+		return tsSerializedType{TSMetadataType: js_ast.TSMetadataType{EntityOrNil: entity}}, false
+	}
+
+	return named("Object"), true
+}
+
+// This assumes the current token is "(", "[", or "{"
+func (p *parser) skipTypeScriptBalancedTokens() {
+	depth := 0
+	for {
+		switch p.lexer.Token {
+		case js_lexer.TOpenParen, js_lexer.TOpenBracket, js_lexer.TOpenBrace:
+			depth++
+		case js_lexer.TCloseParen, js_lexer.TCloseBracket, js_lexer.TCloseBrace:
+			depth--
+		case js_lexer.TEndOfFile:
+			p.lexer.Unexpected()
+		}
+		p.lexer.Next()
+		if depth == 0 {
+			break
+		}
+	}
+}
+
 func (p *parser) skipTypeScriptObjectType() {
 	p.lexer.Expect(js_lexer.TOpenBrace)
 
@@ -671,6 +991,14 @@ func (p *parser) skipTypeScriptTypeParameters(flags typeParameterFlags) skipType
 		return didNotSkipAnything
 	}
 
+	// Remember where these were for TypeScript declaration files
+	if p.tsDeclaration != nil {
+		start := p.lexer.Loc()
+		defer func() {
+			p.tsDeclaration.lastTypeParameters = logger.Range{Loc: start, Len: p.lexer.PrevTokenEnd() - start.Start}
+		}()
+	}
+
 	p.lexer.Next()
 	result := couldBeTypeCast
 
@@ -1187,6 +1515,7 @@ func (p *parser) skipTypeScriptInterfaceStmt(opts parseStmtOpts) {
 
 	if opts.isModuleScope {
 		p.localTypeNames[name] = true
+		p.tsMetadataObjectNames[name] = true
 	}
 
 	p.skipTypeScriptTypeParameters(allowInOutVarianceAnnotations | allowEmptyTypeParameters)
@@ -1262,6 +1591,7 @@ func (p *parser) skipTypeScriptTypeStmt(opts parseStmtOpts) {
 
 	if opts.isModuleScope {
 		p.localTypeNames[name] = true
+		p.tsMetadataObjectNames[name] = true
 	}
 
 	p.skipTypeScriptTypeParameters(allowInOutVarianceAnnotations | allowEmptyTypeParameters)
@@ -1379,6 +1709,36 @@ func (p *parser) parseTypeScriptEnumStmt(loc logger.Loc, opts parseStmtOpts) js_
 	p.fnOrArrowDataParse = oldFnOrArrowData
 
 	if !opts.isTypeScriptDeclare {
+		// Remember the type of this enum's values for "emitDecoratorMetadata".
+		// Uninitialized and computed enum members are numbers, so only string
+		// initializers are different. Merged enums are combined.
+		hasNumber := false
+		hasString := false
+		for _, value := range values {
+			switch e := value.ValueOrNil.Data.(type) {
+			case *js_ast.EString:
+				hasString = true
+				continue
+			case *js_ast.ETemplate:
+				if e.TagOrNil.Data == nil {
+					hasString = true
+					continue
+				}
+			}
+			hasNumber = true
+		}
+		metadataType := "Number"
+		if hasString {
+			metadataType = "String"
+			if hasNumber {
+				metadataType = "Object"
+			}
+		}
+		if old, ok := p.tsEnumMetadataTypes[name.Ref]; ok && old != metadataType {
+			metadataType = "Object"
+		}
+		p.tsEnumMetadataTypes[name.Ref] = metadataType
+
 		// Avoid a collision with the enum closure argument variable if the
 		// enum exports a symbol with the same name as the enum itself:
 		//
@@ -5,6 +5,8 @@ import (
 
 	"github.com/evanw/esbuild/internal/compat"
 	"github.com/evanw/esbuild/internal/config"
+	"github.com/evanw/esbuild/internal/logger"
+	"github.com/evanw/esbuild/internal/test"
 )
 
 func expectParseErrorTS(t *testing.T, contents string, expected string) {
@@ -98,6 +100,19 @@ func expectPrintedExperimentalDecoratorTS(t *testing.T, contents string, expecte
 	})
 }
 
+func expectPrintedDecoratorMetadataTS(t *testing.T, contents string, expected string) {
+	t.Helper()
+	expectPrintedCommon(t, contents, expected, config.Options{
+		TS: config.TSOptions{
+			Parse: true,
+			Config: config.TSConfig{
+				EmitDecoratorMetadata:  config.True,
+				ExperimentalDecorators: config.True,
+			},
+		},
+	})
+}
+
 func expectPrintedMangleTS(t *testing.T, contents string, expected string) {
 	t.Helper()
 	expectPrintedCommon(t, contents, expected, config.Options{
@@ -192,6 +207,41 @@ func expectPrintedTSX(t *testing.T, contents string, expected string) {
 	})
 }
 
+func expectPrintedDeclarationTS(t *testing.T, contents string, expected string) {
+	t.Helper()
+	t.Run(contents, func(t *testing.T) {
+		t.Helper()
+		log := logger.NewDeferLog(logger.DeferLogNoVerboseOrDebug, nil)
+		options := config.Options{
+			TS: config.TSOptions{
+				Parse: true,
+			},
+			TSDeclaration: true,
+		}
+		tree, ok := Parse(log, test.SourceForTest(contents), OptionsFromConfig(&options))
+		msgs := log.Done()
+		text := ""
+		for _, msg := range msgs {
+			text += msg.String(logger.OutputOptions{}, logger.TerminalInfo{})
+		}
+		test.AssertEqualWithDiff(t, text, "")
+		if !ok {
+			t.Fatal("Parse error")
+		}
+		test.AssertEqualWithDiff(t, tree.TSDeclaration, expected)
+	})
+}
+
+func expectParseErrorDeclarationTS(t *testing.T, contents string, expected string) {
+	t.Helper()
+	expectParseErrorCommon(t, contents, expected, config.Options{
+		TS: config.TSOptions{
+			Parse: true,
+		},
+		TSDeclaration: true,
+	})
+}
+
 func TestTSTypes(t *testing.T) {
 	expectPrintedTS(t, "let x: T extends number\n ? T\n : number", "let x;\n")
 	expectPrintedTS(t, "let x: {y: T extends number ? T : number}", "let x;\n")
@@ -2062,6 +2112,103 @@ func TestTSExperimentalDecorator(t *testing.T) {
 		"class Foo {\n  bar;\n}\n__decorateClass([\n  () => {\n  }\n], Foo.prototype, \"foo\", 2);\n__decorateClass([\n  () => {\n  }\n], Foo.prototype, \"bar\", 2);\n")
 }
 
+func TestTSDecoratorMetadata(t *testing.T) {
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: string }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: number | null | undefined }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Number)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: string | number }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: 'a' | 'b' }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: -1n }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", BigInt)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: boolean[] }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Array)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: [number, string] }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Array)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: (a: number) => void }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Function)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: new () => Foo }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Function)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: { y: number } }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: unique symbol }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Symbol)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: any }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: void }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", void 0)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: never }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", void 0)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: Bar }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", typeof Bar === \"function\" ? Bar : Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: Bar.Baz<T> }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", typeof Bar !== \"undefined\" && typeof Bar.Baz === \"function\" ? Bar.Baz : Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: Foo }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Foo)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: (string) }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: keyof Foo }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Bar {} class Foo { @dec x: Bar }",
+		"class Bar {\n}\nclass Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Bar)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec foo(a: string, b, ...c: number[]): boolean {} }",
+		"class Foo {\n  foo(a, b, ...c) {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Function),\n  __metadata(\"design:paramtypes\", [String, Object, Number]),\n  __metadata(\"design:returntype\", Boolean)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { foo(@dec a: string): void {} }",
+		"class Foo {\n  foo(a) {\n  }\n}\n__decorateClass([\n  __decorateParam(0, dec),\n  __metadata(\"design:type\", Function),\n  __metadata(\"design:paramtypes\", [String]),\n  __metadata(\"design:returntype\", void 0)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec async foo() {} }",
+		"class Foo {\n  async foo() {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Function),\n  __metadata(\"design:paramtypes\", []),\n  __metadata(\"design:returntype\", Promise)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec foo(): x is string {} }",
+		"class Foo {\n  foo() {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Function),\n  __metadata(\"design:paramtypes\", []),\n  __metadata(\"design:returntype\", Boolean)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec get foo(): number {} }",
+		"class Foo {\n  get foo() {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Number)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec get foo() {} }",
+		"class Foo {\n  get foo() {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec set foo(x: string) {} }",
+		"class Foo {\n  set foo(x) {\n  }\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String),\n  __metadata(\"design:paramtypes\", [String])\n], Foo.prototype, \"foo\", 1);\n")
+	expectPrintedDecoratorMetadataTS(t, "@dec class Foo { constructor(a: string, b: Bar) {} }",
+		"let Foo = class {\n  constructor(a, b) {\n  }\n};\nFoo = __decorateClass([\n  dec,\n  __metadata(\"design:paramtypes\", [String, typeof Bar === \"function\" ? Bar : Object])\n], Foo);\n")
+	expectPrintedDecoratorMetadataTS(t, "@dec class Foo {}",
+		"let Foo = class {\n};\nFoo = __decorateClass([\n  dec\n], Foo);\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { constructor(@dec a: string, b: number) {} }",
+		"let Foo = class {\n  constructor(a, b) {\n  }\n};\nFoo = __decorateClass([\n  __decorateParam(0, dec),\n  __metadata(\"design:paramtypes\", [String, Number])\n], Foo);\n")
+	expectPrintedDecoratorMetadataTS(t, "import { Bar } from \"bar\"; class Foo { @dec x: Bar }",
+		"import { Bar } from \"bar\";\nclass Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", typeof Bar === \"function\" ? Bar : Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "import { Bar } from \"bar\"; class Foo { x: Bar }",
+		"class Foo {\n  x;\n}\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: string; y: Bar }",
+		"class Foo {\n  x;\n  y;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String)\n], Foo.prototype, \"x\", 2);\n")
+
+	// Enums declared in this file are replaced by the type of their values
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: E } enum E { A, B = 2 }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Number)\n], Foo.prototype, \"x\", 2);\nvar E = /* @__PURE__ */ ((E) => {\n  E[E[\"A\"] = 0] = \"A\";\n  E[E[\"B\"] = 2] = \"B\";\n  return E;\n})(E || {});\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: E } enum E { A = 'a', B = `b` }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", String)\n], Foo.prototype, \"x\", 2);\nvar E = /* @__PURE__ */ ((E) => {\n  E[\"A\"] = \"a\";\n  E[\"B\"] = `b`;\n  return E;\n})(E || {});\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: E } enum E { A = 1, B = 'b' }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\nvar E = /* @__PURE__ */ ((E) => {\n  E[E[\"A\"] = 1] = \"A\";\n  E[\"B\"] = \"b\";\n  return E;\n})(E || {});\n")
+	expectPrintedDecoratorMetadataTS(t, "class Foo { @dec x: E.B } enum E { A, B }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Number)\n], Foo.prototype, \"x\", 2);\nvar E = /* @__PURE__ */ ((E) => {\n  E[E[\"A\"] = 0] = \"A\";\n  E[E[\"B\"] = 1] = \"B\";\n  return E;\n})(E || {});\n")
+
+	// Interfaces, type aliases, and type-only imports are always "Object"
+	expectPrintedDecoratorMetadataTS(t, "interface Bar {} class Foo { @dec x: Bar }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "type Bar = string; class Foo { @dec x: Bar }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "import type { Bar } from \"bar\"; class Foo { @dec x: Bar }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "import type Bar from \"bar\"; class Foo { @dec x: Bar }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "import type * as Bar from \"bar\"; class Foo { @dec x: Bar.Baz }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "import { type Bar as Baz } from \"bar\"; class Foo { @dec x: Baz }",
+		"class Foo {\n  x;\n}\n__decorateClass([\n  dec,\n  __metadata(\"design:type\", Object)\n], Foo.prototype, \"x\", 2);\n")
+	expectPrintedDecoratorMetadataTS(t, "interface Bar {} function f(Bar) { class Foo { @dec x: Bar } }",
+		"function f(Bar) {\n  class Foo {\n    x;\n  }\n  __decorateClass([\n    dec,\n    __metadata(\"design:type\", typeof Bar === \"function\" ? Bar : Object)\n  ], Foo.prototype, \"x\", 2);\n}\n")
+}
+
 func TestTSDecorators(t *testing.T) {
 	expectPrintedTS(t, "@x @y class Foo {}", "@x @y class Foo {\n}\n")
 	expectPrintedTS(t, "@x @y export class Foo {}", "@x @y export class Foo {\n}\n")
@@ -2113,14 +2260,14 @@ func TestTSDecorators(t *testing.T) {
 		"<stdin>: ERROR: JavaScript decorator syntax does not allow \".\" after a call expression\n"+
 			"<stdin>: NOTE: Wrap this decorator in parentheses to allow arbitrary expressions:\n")
 
-	errorText := "<stdin>: ERROR: Transforming JavaScript decorators to the configured target environment is not supported yet\n"
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "@dec class Foo {}", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec x }", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec x() {} }", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec accessor x }", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static x }", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static x() {} }", errorText)
-	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static accessor x }", errorText)
+	// JavaScript decorators are lowered when they are unsupported
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "@dec class Foo {}", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec x }", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec x() {} }", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec accessor x }", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static x }", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static x() {} }", "")
+	expectParseErrorWithUnsupportedFeaturesTS(t, compat.Decorators, "class Foo { @dec static accessor x }", "")
 
 	// Check ASI for "abstract"
 	expectPrintedTS(t, "@x abstract class Foo {}", "@x class Foo {\n}\n")
@@ -2242,8 +2389,8 @@ func TestTSArrow(t *testing.T) {
 	expectPrintedTS(t, "function x(): ({y: z}) {}", "function x() {\n}\n")
 
 	expectParseErrorTargetTS(t, 5, "return check ? (hover = 2, bar) : baz()", "")
-	expectParseErrorTargetTS(t, 5, "return check ? (hover = 2, bar) => 0 : baz()",
-		"<stdin>: ERROR: Transforming default arguments to the configured target environment is not supported yet\n")
+	expectPrintedTargetTS(t, 5, "return check ? (hover = 2, bar) => 0 : baz()",
+		"return check ? function(hover, bar) {\n  if (hover === void 0)\n    hover = 2;\n  return 0;\n} : baz();\n")
 }
 
 func TestTSSuperCall(t *testing.T) {
@@ -3140,8 +3287,8 @@ func TestTSES5(t *testing.T) {
 	expectPrintedTargetTS(t, 5, "0 ? ({}) : 0", "0 ? {} : 0;\n")
 	expectPrintedTargetTS(t, 2015, "0 ? ([]): 0 => 0 : 0", "0 ? ([]) => 0 : 0;\n")
 	expectPrintedTargetTS(t, 2015, "0 ? ({}): 0 => 0 : 0", "0 ? ({}) => 0 : 0;\n")
-	expectParseErrorTargetTS(t, 5, "0 ? ([]): 0 => 0 : 0", "<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
-	expectParseErrorTargetTS(t, 5, "0 ? ({}): 0 => 0 : 0", "<stdin>: ERROR: Transforming destructuring to the configured target environment is not supported yet\n")
+	expectPrintedTargetTS(t, 5, "0 ? ([]): 0 => 0 : 0", "0 ? function(_a) {\n  var _b = __toArray(_a, 0);\n  return 0;\n} : 0;\n")
+	expectPrintedTargetTS(t, 5, "0 ? ({}): 0 => 0 : 0", "0 ? function(_a) {\n  var _b = _a;\n  return 0;\n} : 0;\n")
 }
 
 func TestTSUsing(t *testing.T) {
@@ -3151,3 +3298,106 @@ func TestTSUsing(t *testing.T) {
 	expectParseErrorTS(t, "export using x: any = y", "<stdin>: ERROR: Unexpected \"using\"\n")
 	expectParseErrorTS(t, "namespace ns { export using x: any = y }", "<stdin>: ERROR: Unexpected \"using\"\n")
 }
+
+func TestTSDeclaration(t *testing.T) {
+	// Scripts keep everything, modules only keep what's exported or referenced
+	expectPrintedDeclarationTS(t, "let x = 1; function f(): void {}", "declare let x: number;\ndeclare function f(): void;\n")
+	expectPrintedDeclarationTS(t, "let x = 1; export let y = 2", "export declare let y: number;\n")
+	expectPrintedDeclarationTS(t, "type T = number; let x = 1; export let y: T", "type T = number;\nexport declare let y: T;\nexport {};\n")
+	expectPrintedDeclarationTS(t, "const x = 1; export { x }", "declare const x = 1;\nexport { x };\n")
+	expectPrintedDeclarationTS(t, "import { A, B } from 'ab'; export let x: A", "import { A, B } from 'ab';\nexport declare let x: A;\n")
+	expectPrintedDeclarationTS(t, "import { A } from 'a'; export let x = 1", "export declare let x: number;\n")
+	expectPrintedDeclarationTS(t, "import 'a'", "export {};\n")
+	expectPrintedDeclarationTS(t, "", "export {};\n")
+
+	// Documentation comments are kept
+	expectPrintedDeclarationTS(t, "/** Docs */\nexport let x = 1", "/** Docs */\nexport declare let x: number;\n")
+	expectPrintedDeclarationTS(t, "/* Not docs */\nexport let x = 1", "export declare let x: number;\n")
+
+	// Variables
+	expectPrintedDeclarationTS(t, "export const a = 1, b = 'b', c = -2n, d = true", "export declare const a = 1, b = \"b\", c = -2n, d = true;\n")
+	expectPrintedDeclarationTS(t, "export let a = 1, b = 'b', c = `c${d}`, e: E = f()", "export declare let a: number, b: string, c: string, e: E;\n")
+	expectPrintedDeclarationTS(t, "export const a = (x: number, y = ''): void => {}", "export declare const a: (x: number, y?: string) => void;\n")
+	expectPrintedDeclarationTS(t, "export const a = <T,>(x?: T): T => x!", "export declare const a: <T,>(x?: T) => T;\n")
+	expectPrintedDeclarationTS(t, "export const a = async function (x: number): Promise<void> {}", "export declare const a: (x: number) => Promise<void>;\n")
+	expectPrintedDeclarationTS(t, "export const a = 'a' as const; export let b = -1 as const", "export declare const a: \"a\";\nexport declare let b: -1;\n")
+	expectPrintedDeclarationTS(t, "export const a = [1, 'b', true, null, [2n]] as const",
+		"export declare const a: readonly [1, \"b\", true, null, readonly [2n]];\n")
+	expectPrintedDeclarationTS(t, "export const a = { b: 1, 'c-d': `e`, f: { g: [] } } as const",
+		"export declare const a: { readonly b: 1; readonly \"c-d\": \"e\"; readonly f: { readonly g: readonly [] } };\n")
+
+	// Functions
+	expectPrintedDeclarationTS(t, "export function f<T>(this: T, a: T, b = 1, ...c: T[]): T { return a }",
+		"export declare function f<T>(this: T, a: T, b?: number, ...c: T[]): T;\n")
+	expectPrintedDeclarationTS(t, "export function f(a = 1, b: number): void {}", "export declare function f(a: number, b: number): void;\n")
+	expectPrintedDeclarationTS(t, "export async function* f(): AsyncGenerator<number> {}", "export declare function f(): AsyncGenerator<number>;\n")
+	expectPrintedDeclarationTS(t, "export function f(a: string): string; export function f(a: number): number; export function f(a: any): any { return a }",
+		"export declare function f(a: string): string;\nexport declare function f(a: number): number;\n")
+	expectPrintedDeclarationTS(t, "export default function (a: number): void {}", "export default function(a: number): void;\n")
+
+	// Classes
+	expectPrintedDeclarationTS(t, "export class Foo<T> extends Bar<T> implements Baz {\n  a = 1\n  readonly b = 'b'\n  c?: number\n  private d = {}\n  #e = 1\n  #f() {}\n  static { g() }\n}",
+		"export declare class Foo<T> extends Bar<T> implements Baz {\n  a: number;\n  readonly b = \"b\";\n  c?: number;\n  private d;\n  #private;\n}\n")
+	expectPrintedDeclarationTS(t, "export abstract class Foo {\n  abstract a(): void\n  declare b: number\n  protected static c(): void {}\n  private d() {}\n}",
+		"export declare abstract class Foo {\n  abstract a(): void;\n  b: number;\n  protected static c(): void;\n  private d;\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo {\n  constructor(private a: number, readonly b = 1) {}\n}",
+		"export declare class Foo {\n  private a;\n  readonly b: number;\n  constructor(a: number, b?: number);\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo {\n  get a(): number { return 1 }\n  set a(v) {}\n  get b() { return 1 }\n  set b(v: string) {}\n}",
+		"export declare class Foo {\n  get a(): number;\n  set a(v: number);\n  get b(): string;\n  set b(v: string);\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo {\n  m(a: string): void\n  m(a: number): void\n  m(a: any) {}\n}",
+		"export declare class Foo {\n  m(a: string): void;\n  m(a: number): void;\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo {\n  @dec a: number = 1\n  accessor b = ''\n}",
+		"export declare class Foo {\n  a: number;\n  accessor b: string;\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo { a = 1 }", "export declare class Foo {\n  a: number;\n}\n")
+	expectPrintedDeclarationTS(t, "export class Foo { readonly a = ['b'] as const }", "export declare class Foo {\n  readonly a: readonly [\"b\"];\n}\n")
+
+	// TypeScript-only statements
+	expectPrintedDeclarationTS(t, "export interface I { a: number }\nexport type T = I", "export interface I { a: number }\nexport type T = I;\n")
+	expectPrintedDeclarationTS(t, "export enum E { A, B = 2 }\nexport const enum C { D }", "export declare enum E { A, B = 2 }\nexport declare const enum C { D }\n")
+	expectPrintedDeclarationTS(t, "export declare const x: number", "export declare const x: number;\n")
+	expectPrintedDeclarationTS(t, "declare global { interface Window { a: number } }", "declare global { interface Window { a: number } }\n")
+	expectPrintedDeclarationTS(t, "import fs = require('fs'); export let x: fs.Stats", "import fs = require('fs');\nexport declare let x: fs.Stats;\n")
+	expectPrintedDeclarationTS(t, "export type { T } from 'a'; export * from 'b'", "export type { T } from 'a';\nexport * from 'b';\n")
+
+	// Namespaces
+	expectPrintedDeclarationTS(t, "export namespace NS {\n  export const a = 1\n  export function f(): void {}\n}",
+		"export declare namespace NS {\n  export const a = 1;\n  export function f(): void;\n}\n")
+	expectPrintedDeclarationTS(t, "export namespace NS {\n  const a = 1\n  export let b: typeof a\n}",
+		"export declare namespace NS {\n  const a = 1;\n  export let b: typeof a;\n  export {};\n}\n")
+	expectPrintedDeclarationTS(t, "export namespace A.B {\n  export let c = 1\n}", "export declare namespace A.B {\n  export let c: number;\n}\n")
+
+	// Nested code is indented with two spaces regardless of the original indentation
+	expectPrintedDeclarationTS(t, "export namespace A {\n  export namespace B { export let c = 1 }\n  export class C { d = 1 }\n}",
+		"export declare namespace A {\n  export namespace B {\n    export let c: number;\n  }\n  export class C {\n    d: number;\n  }\n}\n")
+	expectPrintedDeclarationTS(t, "export namespace A {\n    /**\n     * Docs\n     */\n    export let b = 1\n    export interface C {\n        d: number\n    }\n}",
+		"export declare namespace A {\n  /**\n   * Docs\n   */\n  export let b: number;\n  export interface C {\n    d: number\n  }\n}\n")
+	expectPrintedDeclarationTS(t, "\t\texport class A {\n\t\t\tb = 1\n\t\t}", "export declare class A {\n  b: number;\n}\n")
+
+	// Declarations that need type inference are errors
+	expectParseErrorDeclarationTS(t, "export function f() {}",
+		"<stdin>: ERROR: Function must have an explicit return type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export function f(a = g()): void {}",
+		"<stdin>: ERROR: Parameter must have an explicit type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export let x = f()",
+		"<stdin>: ERROR: Variable must have an explicit type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export const x = [f()] as const",
+		"<stdin>: ERROR: Variable must have an explicit type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export let x = () => {}",
+		"<stdin>: ERROR: Function must have an explicit return type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export let { x } = y",
+		"<stdin>: ERROR: Binding elements can't be exported directly with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export class Foo { a = f() }",
+		"<stdin>: ERROR: Property must have an explicit type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export class Foo { m() {} }",
+		"<stdin>: ERROR: Method must have an explicit return type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export class Foo { get a() { return 1 } }",
+		"<stdin>: ERROR: At least one accessor must have an explicit type annotation with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export class Foo extends mixin(Bar) {}",
+		"<stdin>: ERROR: Extends clause can't contain an expression with --isolatedDeclarations\n")
+	expectParseErrorDeclarationTS(t, "export default 1 + 2",
+		"<stdin>: ERROR: Default exports can't be inferred with --isolatedDeclarations\n")
+
+	// Declarations that aren't in the declaration file don't need types
+	expectParseErrorDeclarationTS(t, "let x = f(); export let y = 1", "")
+	expectParseErrorDeclarationTS(t, "export class Foo { private m() {} #n() {} }", "")
+}
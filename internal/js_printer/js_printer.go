@@ -15,6 +15,7 @@ import (
 	"github.com/evanw/esbuild/internal/js_ast"
 	"github.com/evanw/esbuild/internal/logger"
 	"github.com/evanw/esbuild/internal/renamer"
+	"github.com/evanw/esbuild/internal/runtime"
 	"github.com/evanw/esbuild/internal/sourcemap"
 )
 
@@ -255,6 +256,10 @@ func (p *printer) printJSXTag(tagOrNil js_ast.Expr) {
 		p.print(helpers.UTF16ToString(e.Value))
 
 	case *js_ast.EIdentifier:
+		if alias := p.crossChunkAlias(e.Ref); alias != nil {
+			p.printCrossChunkAlias(tagOrNil.Loc, e.Ref, alias, false)
+			break
+		}
 		name := p.renamer.NameForSymbol(e.Ref)
 		p.addSourceMappingForName(tagOrNil.Loc, name, e.Ref)
 		p.print(name)
@@ -285,6 +290,7 @@ type printer struct {
 	js                     []byte
 	jsonMetadataImports    []string
 	binaryExprStack        []binaryExprVisitor
+	systemJSExportExpr     js_ast.E
 	options                Options
 	builder                sourcemap.ChunkBuilder
 
@@ -364,6 +370,51 @@ func (p *printer) mangledPropName(ref ast.Ref) string {
 	return p.renamer.NameForSymbol(ref)
 }
 
+// Output formats other than ESM have no syntax for importing a binding from
+// another chunk. Symbols that were declared in another chunk are instead read
+// off of the exports object of that chunk, which keeps them live.
+func (p *printer) crossChunkAlias(ref ast.Ref) *ast.NamespaceAlias {
+	if p.options.CrossChunkAliases != nil {
+		if alias, ok := p.options.CrossChunkAliases[ast.FollowSymbols(p.symbols, ref)]; ok {
+			return &alias
+		}
+	}
+	return nil
+}
+
+func (p *printer) printCrossChunkAlias(loc logger.Loc, ref ast.Ref, alias *ast.NamespaceAlias, isCallTarget bool) {
+	// "foo()" must not become "import_chunk.foo()" since that changes "this".
+	// Runtime helpers don't use "this" so they are left alone.
+	if isCallTarget && ast.FollowSymbols(p.symbols, ref).SourceIndex == runtime.SourceIndex {
+		isCallTarget = false
+	}
+	if isCallTarget {
+		p.print("(0,")
+		p.printSpace()
+	}
+	p.printSpaceBeforeIdentifier()
+	p.addSourceMapping(loc)
+	p.printIdentifier(p.renamer.NameForSymbol(alias.NamespaceRef))
+	p.print(".")
+	p.addSourceMappingForName(loc, alias.Alias, ref)
+	p.printIdentifier(alias.Alias)
+	if isCallTarget {
+		p.print(")")
+	}
+}
+
+// This is for references to symbols that aren't in the AST such as runtime
+// helpers, which may also have been declared in another chunk
+func (p *printer) printSymbol(ref ast.Ref) {
+	if alias := p.crossChunkAlias(ref); alias != nil {
+		p.printIdentifier(p.renamer.NameForSymbol(alias.NamespaceRef))
+		p.print(".")
+		p.printIdentifier(alias.Alias)
+		return
+	}
+	p.printIdentifier(p.renamer.NameForSymbol(ref))
+}
+
 func (p *printer) tryToGetImportedEnumValue(target js_ast.Expr, name string) (js_ast.TSEnumValue, bool) {
 	if id, ok := target.Data.(*js_ast.EImportIdentifier); ok {
 		ref := ast.FollowSymbols(p.symbols, id.Ref)
@@ -924,7 +975,13 @@ func (p *printer) printDecorators(decorators []js_ast.Decorator, defaultMode pri
 
 			switch e := expr.Data.(type) {
 			case *js_ast.EIdentifier:
+				if isCallTarget && p.crossChunkAlias(e.Ref) != nil {
+					// "@((0, import_chunk.fn)())"
+					break
+				}
+
 				// "@foo"
+				// "@import_chunk.foo"
 				break outer
 
 			case *js_ast.ECall:
@@ -967,6 +1024,11 @@ func (p *printer) printDecorators(decorators []js_ast.Decorator, defaultMode pri
 					break
 				}
 
+				if isCallTarget && p.crossChunkAlias(ref) != nil {
+					// "@((0, import_chunk.fn)())"
+					break
+				}
+
 				if value := p.options.ConstValues[ref]; value.Kind != js_ast.ConstValueNone {
 					// "@(<inlined constant>)"
 					break
@@ -1204,7 +1266,7 @@ func (p *printer) printProperty(property js_ast.Property) {
 			if !p.options.UnsupportedFeatures.Has(compat.ObjectExtensions) && property.ValueOrNil.Data != nil && !p.willPrintExprCommentsAtLoc(property.ValueOrNil.Loc) {
 				switch e := property.ValueOrNil.Data.(type) {
 				case *js_ast.EIdentifier:
-					if name == p.renamer.NameForSymbol(e.Ref) {
+					if name == p.renamer.NameForSymbol(e.Ref) && p.crossChunkAlias(e.Ref) == nil {
 						if property.InitializerOrNil.Data != nil {
 							p.printSpace()
 							p.print("=")
@@ -1218,7 +1280,7 @@ func (p *printer) printProperty(property js_ast.Property) {
 					// Make sure we're not using a property access instead of an identifier
 					ref := ast.FollowSymbols(p.symbols, e.Ref)
 					if symbol := p.symbols.Get(ref); symbol.NamespaceAlias == nil && name == p.renamer.NameForSymbol(ref) &&
-						p.options.ConstValues[ref].Kind == js_ast.ConstValueNone {
+						p.options.ConstValues[ref].Kind == js_ast.ConstValueNone && p.crossChunkAlias(ref) == nil {
 						if property.InitializerOrNil.Data != nil {
 							p.printSpace()
 							p.print("=")
@@ -1242,7 +1304,7 @@ func (p *printer) printProperty(property js_ast.Property) {
 			if !p.options.UnsupportedFeatures.Has(compat.ObjectExtensions) && property.ValueOrNil.Data != nil && !p.willPrintExprCommentsAtLoc(property.ValueOrNil.Loc) {
 				switch e := property.ValueOrNil.Data.(type) {
 				case *js_ast.EIdentifier:
-					if canUseShorthandProperty(key.Value, p.renamer.NameForSymbol(e.Ref), property.Flags) {
+					if canUseShorthandProperty(key.Value, p.renamer.NameForSymbol(e.Ref), property.Flags) && p.crossChunkAlias(e.Ref) == nil {
 						if p.options.AddSourceMappings {
 							p.addSourceMappingForName(property.Key.Loc, helpers.UTF16ToString(key.Value), e.Ref)
 						}
@@ -1260,7 +1322,7 @@ func (p *printer) printProperty(property js_ast.Property) {
 					// Make sure we're not using a property access instead of an identifier
 					ref := ast.FollowSymbols(p.symbols, e.Ref)
 					if symbol := p.symbols.Get(ref); symbol.NamespaceAlias == nil && canUseShorthandProperty(key.Value, p.renamer.NameForSymbol(ref), property.Flags) &&
-						p.options.ConstValues[ref].Kind == js_ast.ConstValueNone {
+						p.options.ConstValues[ref].Kind == js_ast.ConstValueNone && p.crossChunkAlias(ref) == nil {
 						if p.options.AddSourceMappings {
 							p.addSourceMappingForName(property.Key.Loc, helpers.UTF16ToString(key.Value), ref)
 						}
@@ -1389,6 +1451,128 @@ func (p *printer) printQuotedUTF16(data []uint16, flags printQuotedFlags) {
 	p.print(c)
 }
 
+// SystemJS modules export live bindings by passing every new value of an
+// exported variable to the "exports" function. This returns false if the
+// expression doesn't assign to an exported variable.
+//
+//	"x = y"   => "exports('x', x = y)"
+//	"++x"     => "exports('x', ++x)"
+//	"x++"     => "(exports('x', x + 1), x++)"
+//	"[x] = y" => "[x] = y, exports('x', x)"
+func (p *printer) printSystemJSExportUpdate(expr js_ast.Expr, level js_ast.L, flags printExprFlags) bool {
+	isUnused := (flags & exprResultIsUnused) != 0
+	var value js_ast.Expr
+
+	switch e := expr.Data.(type) {
+	case *js_ast.EBinary:
+		if e.Op.BinaryAssignTarget() == js_ast.AssignTargetNone {
+			return false
+		}
+		if id, ok := e.Left.Data.(*js_ast.EIdentifier); ok {
+			value = p.wrapWithSystemJSExports(id.Ref, expr)
+		} else if e.Op == js_ast.BinOpAssign && isUnused {
+			// Destructuring assignments export each variable afterward. This is
+			// only done when the result is unused since the result must be the
+			// right-hand side of the assignment.
+			for _, ref := range p.systemJSExportsInAssignTarget(e.Left, nil) {
+				if call := p.wrapWithSystemJSExports(ref, js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EIdentifier{Ref: ref}}); call.Data != nil {
+					value = js_ast.JoinWithComma(value, call)
+				}
+			}
+			if value.Data != nil {
+				value = js_ast.JoinWithComma(expr, value)
+			}
+		}
+
+	case *js_ast.EUnary:
+		if e.Op.UnaryAssignTarget() == js_ast.AssignTargetNone {
+			return false
+		}
+		id, ok := e.Value.Data.(*js_ast.EIdentifier)
+		if !ok {
+			return false
+		}
+		if e.Op.IsPrefix() {
+			value = p.wrapWithSystemJSExports(id.Ref, expr)
+		} else if isUnused {
+			// "x++" => "exports('x', ++x)"
+			op := js_ast.UnOpPreInc
+			if e.Op == js_ast.UnOpPostDec {
+				op = js_ast.UnOpPreDec
+			}
+			expr = js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EUnary{Op: op, Value: e.Value}}
+			value = p.wrapWithSystemJSExports(id.Ref, expr)
+		} else {
+			// "x++" => "(exports('x', x + 1), x++)"
+			op := js_ast.BinOpAdd
+			if e.Op == js_ast.UnOpPostDec {
+				op = js_ast.BinOpSub
+			}
+			if call := p.wrapWithSystemJSExports(id.Ref, js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EBinary{
+				Op:    op,
+				Left:  e.Value,
+				Right: js_ast.Expr{Loc: expr.Loc, Data: &js_ast.ENumber{Value: 1}},
+			}}); call.Data != nil {
+				value = js_ast.JoinWithComma(call, expr)
+			}
+		}
+	}
+
+	if value.Data == nil {
+		return false
+	}
+
+	// Don't wrap the original expression again when it's printed inside the call
+	old := p.systemJSExportExpr
+	p.systemJSExportExpr = expr.Data
+	p.printExpr(value, level, flags)
+	p.systemJSExportExpr = old
+	return true
+}
+
+func (p *printer) wrapWithSystemJSExports(ref ast.Ref, value js_ast.Expr) js_ast.Expr {
+	aliases := p.options.SystemJSExports[ast.FollowSymbols(p.symbols, ref)]
+	if len(aliases) == 0 {
+		return js_ast.Expr{}
+	}
+	for _, alias := range aliases {
+		value = js_ast.Expr{Loc: value.Loc, Data: &js_ast.ECall{
+			Target: js_ast.Expr{Loc: value.Loc, Data: &js_ast.EIdentifier{Ref: p.options.SystemJSExportsRef}},
+			Args: []js_ast.Expr{
+				{Loc: value.Loc, Data: &js_ast.EString{Value: helpers.StringToUTF16(alias)}},
+				value,
+			},
+		}}
+	}
+	return value
+}
+
+func (p *printer) systemJSExportsInAssignTarget(target js_ast.Expr, refs []ast.Ref) []ast.Ref {
+	switch e := target.Data.(type) {
+	case *js_ast.EIdentifier:
+		refs = append(refs, e.Ref)
+
+	case *js_ast.EArray:
+		for _, item := range e.Items {
+			refs = p.systemJSExportsInAssignTarget(item, refs)
+		}
+
+	case *js_ast.EObject:
+		for _, property := range e.Properties {
+			refs = p.systemJSExportsInAssignTarget(property.ValueOrNil, refs)
+		}
+
+	case *js_ast.ESpread:
+		refs = p.systemJSExportsInAssignTarget(e.Value, refs)
+
+	case *js_ast.EBinary:
+		if e.Op == js_ast.BinOpAssign {
+			refs = p.systemJSExportsInAssignTarget(e.Left, refs)
+		}
+	}
+	return refs
+}
+
 func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_ast.L, flags printExprFlags, closeParenLoc logger.Loc) {
 	record := &p.importRecords[importRecordIndex]
 
@@ -1399,20 +1583,26 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 	}
 
 	if !record.SourceIndex.IsValid() {
+		// "import()" of another chunk when code splitting with the IIFE format
+		if record.Kind == ast.ImportDynamic && p.options.ChunkLoaderPaths != nil && record.Flags.Has(ast.ContainsUniqueKey) {
+			p.printChunkLoad(importRecordIndex)
+			return
+		}
+
 		// External "require()"
 		if record.Kind != ast.ImportDynamic {
 			// Wrap this with a call to "__toESM()" if this is a CommonJS file
 			wrapWithToESM := record.Flags.Has(ast.WrapWithToESM)
 			if wrapWithToESM {
 				p.printSpaceBeforeIdentifier()
-				p.printIdentifier(p.renamer.NameForSymbol(p.options.ToESMRef))
+				p.printSymbol(p.options.ToESMRef)
 				p.print("(")
 			}
 
 			// Potentially substitute our own "__require" stub for "require"
 			p.printSpaceBeforeIdentifier()
 			if record.Flags.Has(ast.CallRuntimeRequire) {
-				p.printIdentifier(p.renamer.NameForSymbol(p.options.RuntimeRequireRef))
+				p.printSymbol(p.options.RuntimeRequireRef)
 			} else {
 				p.print("require")
 			}
@@ -1449,9 +1639,17 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 			return
 		}
 
-		// External "import()"
+		// External "import()". Other chunks are always loaded with "require()"
+		// when code splitting with the CommonJS format. Otherwise node would
+		// load them as CommonJS modules and fail to detect most of the exports.
 		kind := ast.ImportDynamic
-		if !p.options.UnsupportedFeatures.Has(compat.DynamicImport) {
+		isChunk := record.Flags.Has(ast.ContainsUniqueKey) && p.options.OutputFormat == config.FormatCommonJS
+		if p.options.OutputFormat == config.FormatSystemJS {
+			// SystemJS modules always load other modules using "module.import()"
+			p.printSpaceBeforeIdentifier()
+			p.printSymbol(p.options.SystemJSModuleRef)
+			p.print(".import(")
+		} else if !p.options.UnsupportedFeatures.Has(compat.DynamicImport) && !isChunk {
 			p.printSpaceBeforeIdentifier()
 			p.print("import(")
 		} else {
@@ -1464,10 +1662,10 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 			// Wrap this with a call to "__toESM()" if this is a CommonJS file
 			if record.Flags.Has(ast.WrapWithToESM) {
 				p.printSpaceBeforeIdentifier()
-				p.printIdentifier(p.renamer.NameForSymbol(p.options.ToESMRef))
+				p.printSymbol(p.options.ToESMRef)
 				p.print("(")
 				defer func() {
-					if p.moduleType.IsESM() {
+					if p.moduleType.IsESM() && !isChunk {
 						p.print(",")
 						p.printSpace()
 						p.print("1")
@@ -1479,7 +1677,7 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 			// Potentially substitute our own "__require" stub for "require"
 			p.printSpaceBeforeIdentifier()
 			if record.Flags.Has(ast.CallRuntimeRequire) {
-				p.printIdentifier(p.renamer.NameForSymbol(p.options.RuntimeRequireRef))
+				p.printSymbol(p.options.RuntimeRequireRef)
 			} else {
 				p.print("require")
 			}
@@ -1526,12 +1724,12 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 	// Internal "import()" of async ESM
 	if record.Kind == ast.ImportDynamic && meta.IsWrapperAsync {
 		p.printSpaceBeforeIdentifier()
-		p.printIdentifier(p.renamer.NameForSymbol(meta.WrapperRef))
+		p.printSymbol(meta.WrapperRef)
 		p.print("()")
 		if meta.ExportsRef != ast.InvalidRef {
 			p.printDotThenPrefix()
 			p.printSpaceBeforeIdentifier()
-			p.printIdentifier(p.renamer.NameForSymbol(meta.ExportsRef))
+			p.printSymbol(meta.ExportsRef)
 			p.printDotThenSuffix()
 		}
 		return
@@ -1555,13 +1753,13 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 	wrapWithToESM := record.Flags.Has(ast.WrapWithToESM)
 	if wrapWithToESM {
 		p.printSpaceBeforeIdentifier()
-		p.printIdentifier(p.renamer.NameForSymbol(p.options.ToESMRef))
+		p.printSymbol(p.options.ToESMRef)
 		p.print("(")
 	}
 
 	// Call the wrapper
 	p.printSpaceBeforeIdentifier()
-	p.printIdentifier(p.renamer.NameForSymbol(meta.WrapperRef))
+	p.printSymbol(meta.WrapperRef)
 	p.print("()")
 
 	// Return the namespace object if this is an ESM file
@@ -1572,10 +1770,10 @@ func (p *printer) printRequireOrImportExpr(importRecordIndex uint32, level js_as
 		// Wrap this with a call to "__toCommonJS()" if this is an ESM file
 		wrapWithTpCJS := record.Flags.Has(ast.WrapWithToCJS)
 		if wrapWithTpCJS {
-			p.printIdentifier(p.renamer.NameForSymbol(p.options.ToCommonJSRef))
+			p.printSymbol(p.options.ToCommonJSRef)
 			p.print("(")
 		}
-		p.printIdentifier(p.renamer.NameForSymbol(meta.ExportsRef))
+		p.printSymbol(meta.ExportsRef)
 		if wrapWithTpCJS {
 			p.print(")")
 		}
@@ -1986,6 +2184,10 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 
 	p.printExprCommentsAtLoc(expr.Loc)
 
+	if p.options.SystemJSExports != nil && expr.Data != p.systemJSExportExpr && p.printSystemJSExportUpdate(expr, level, flags) {
+		return
+	}
+
 	switch e := expr.Data.(type) {
 	case *js_ast.EMissing:
 		p.addSourceMapping(expr.Loc)
@@ -2024,7 +2226,12 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 	case *js_ast.EImportMeta:
 		p.printSpaceBeforeIdentifier()
 		p.addSourceMapping(expr.Loc)
-		p.print("import.meta")
+		if p.options.OutputFormat == config.FormatSystemJS {
+			p.printSymbol(p.options.SystemJSModuleRef)
+			p.print(".meta")
+		} else {
+			p.print("import.meta")
+		}
 
 	case *js_ast.ENameOfSymbol:
 		name := p.mangledPropName(e.Ref)
@@ -2449,6 +2656,9 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 		p.addSourceMapping(expr.Loc)
 		p.printRequireOrImportExpr(e.ImportRecordIndex, level, flags, e.CloseParenLoc)
 
+	case *js_ast.EImportPathString:
+		p.printPath(e.ImportRecordIndex, p.importRecords[e.ImportRecordIndex].Kind)
+
 	case *js_ast.EImportCall:
 		// Only print the second argument if either import assertions or import attributes are supported
 		printImportAssertOrWith := e.OptionsOrNil.Data != nil && (!p.options.UnsupportedFeatures.Has(compat.ImportAssertions) || !p.options.UnsupportedFeatures.Has(compat.ImportAttributes))
@@ -2462,6 +2672,10 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 		}
 		p.printSpaceBeforeIdentifier()
 		p.addSourceMapping(expr.Loc)
+		if p.options.OutputFormat == config.FormatSystemJS {
+			p.printSymbol(p.options.SystemJSModuleRef)
+			p.print(".")
+		}
 		p.print("import(")
 		if isMultiLine {
 			p.printNewline()
@@ -3030,6 +3244,11 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 		p.printNumber(e.Value, level)
 
 	case *js_ast.EIdentifier:
+		if alias := p.crossChunkAlias(e.Ref); alias != nil {
+			p.printCrossChunkAlias(expr.Loc, e.Ref, alias, p.callTarget == e)
+			break
+		}
+
 		name := p.renamer.NameForSymbol(e.Ref)
 		wrap := len(p.js) == p.forOfInitStart && (name == "let" ||
 			((flags&isFollowedByOf) != 0 && (flags&isInsideForAwait) == 0 && name == "async"))
@@ -3061,7 +3280,7 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 			}
 			p.printSpaceBeforeIdentifier()
 			p.addSourceMapping(expr.Loc)
-			p.printIdentifier(p.renamer.NameForSymbol(symbol.NamespaceAlias.NamespaceRef))
+			p.printSymbol(symbol.NamespaceAlias.NamespaceRef)
 			alias := symbol.NamespaceAlias.Alias
 			if !e.PreferQuotedKey && p.canPrintIdentifier(alias) {
 				p.print(".")
@@ -3079,6 +3298,8 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 		} else if value := p.options.ConstValues[ref]; value.Kind != js_ast.ConstValueNone {
 			// Handle inlined constants
 			p.printExpr(js_ast.ConstValueToExpr(expr.Loc, value), level, flags)
+		} else if alias := p.crossChunkAlias(ref); alias != nil {
+			p.printCrossChunkAlias(expr.Loc, ref, alias, p.callTarget == e)
 		} else {
 			p.printSpaceBeforeIdentifier()
 			name := p.renamer.NameForSymbol(ref)
@@ -3200,8 +3421,9 @@ func (p *printer) printExpr(expr js_ast.Expr, level js_ast.L, flags printExprFla
 			left := v.e.Left
 			leftBinary, ok := left.Data.(*js_ast.EBinary)
 
-			// Stop iterating if iteration doesn't apply to the left node
-			if !ok {
+			// Stop iterating if iteration doesn't apply to the left node. This
+			// includes assignments that may need to update SystemJS exports.
+			if !ok || (p.options.SystemJSExports != nil && leftBinary.Op.BinaryAssignTarget() != js_ast.AssignTargetNone) {
 				p.printExpr(left, v.leftLevel, v.leftFlags)
 				v.visitRightAndFinish(p)
 				break
@@ -3816,6 +4038,28 @@ func (p *printer) printIndentedComment(text string) {
 	}
 }
 
+// This prints something like "__chunk.load(['./chunk.js', './lazy.js'])",
+// which loads the chunk after loading all chunks that it imports
+func (p *printer) printChunkLoad(importRecordIndex uint32) {
+	record := &p.importRecords[importRecordIndex]
+	p.printSpaceBeforeIdentifier()
+	p.printSymbol(p.options.ChunkLoaderRef)
+	p.print(".load([")
+	for _, path := range p.options.ChunkLoaderPaths[record.Path.Text] {
+		p.printQuotedUTF8(path, printQuotedNoWrap)
+		p.print(",")
+		p.printSpace()
+	}
+	p.printPath(importRecordIndex, ast.ImportDynamic)
+	p.print("]")
+	if record.Flags.Has(ast.WrapWithToESM) {
+		p.print(",")
+		p.printSpace()
+		p.printSymbol(p.options.ToESMRef)
+	}
+	p.print(")")
+}
+
 func (p *printer) printPath(importRecordIndex uint32, importKind ast.ImportKind) {
 	record := p.importRecords[importRecordIndex]
 	p.addSourceMapping(record.Range.Loc)
@@ -4807,6 +5051,26 @@ type Options struct {
 	// us do binary search on to figure out what line a given AST node came from
 	LineOffsetTables []sourcemap.LineOffsetTable
 
+	// This is only used for code splitting with formats other than ESM. It maps
+	// each symbol declared in another chunk to the namespace object for that
+	// chunk and the property that the chunk exports the symbol as.
+	CrossChunkAliases map[ast.Ref]ast.NamespaceAlias
+
+	// This is only used for code splitting with the IIFE format. Other chunks
+	// are loaded at run-time using the chunk loader, which first needs to load
+	// the chunks that they depend on. This maps the unique key of each chunk to
+	// the unique keys of its dependencies in the order that they must be loaded.
+	ChunkLoaderPaths map[string][]string
+	ChunkLoaderRef   ast.Ref
+
+	// This is only used with the SystemJS format. It maps each exported symbol
+	// to its export aliases. Assignments to these symbols are wrapped in calls to
+	// the "exports" function so that importers see the new value. The "module"
+	// object replaces "import()" and "import.meta".
+	SystemJSExports    map[ast.Ref][]string
+	SystemJSExportsRef ast.Ref
+	SystemJSModuleRef  ast.Ref
+
 	ToCommonJSRef       ast.Ref
 	ToESMRef            ast.Ref
 	RuntimeRequireRef   ast.Ref
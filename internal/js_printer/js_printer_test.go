@@ -513,8 +513,10 @@ func TestObject(t *testing.T) {
 	expectPrinted(t, "import { __proto__ } from 'foo'; let foo = () => ({ '__proto__': __proto__ })", "import { __proto__ } from \"foo\";\nlet foo = () => ({ \"__proto__\": __proto__ });\n")
 	expectPrinted(t, "import { __proto__ } from 'foo'; let foo = () => ({ ['__proto__']: __proto__ })", "import { __proto__ } from \"foo\";\nlet foo = () => ({ [\"__proto__\"]: __proto__ });\n")
 
-	// Don't use ES6+ features (such as a shorthand or computed property name) in ES5
-	expectPrintedTarget(t, 5, "function foo(__proto__) { return { __proto__ } }", "function foo(__proto__) {\n  return { __proto__: __proto__ };\n}\n")
+	// Don't use ES6+ features (such as a shorthand or computed property name) in ES5.
+	// A shorthand "__proto__" property must not become a prototype assignment.
+	expectPrintedTarget(t, 5, "function foo(__proto__) { return { __proto__ } }",
+		"import { __defNormalProp } from \"<runtime>\";\nfunction foo(__proto__) {\n  var _a;\n  return _a = {}, __defNormalProp(_a, \"__proto__\", __proto__), _a;\n}\n")
 }
 
 func TestFor(t *testing.T) {
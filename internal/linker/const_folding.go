@@ -0,0 +1,1307 @@
+package linker
+
+// This file implements cross-module constant folding. Conditions that test
+// constants imported from other files (e.g. "if (FEATURE_X) { ... }" where
+// "FEATURE_X" is "export const FEATURE_X = false" in another file) are folded
+// before tree shaking so that the dead branch, along with any imports that are
+// only used by it, doesn't end up in the bundle. The parser records the values
+// of these constants in "FoldableConsts" since it can't see across files.
+//
+// The AST is shared with the cache for incremental builds, so everything here
+// is copy-on-write. Only the part-level maps are cloned by the linker.
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/graph"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+func (c *linkerContext) foldImportedConstants(sourceIndex uint32) {
+	repr := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr)
+	if len(repr.Meta.ImportsToBind) == 0 {
+		return
+	}
+
+	for partIndex := range repr.AST.Parts {
+		part := &repr.AST.Parts[partIndex]
+
+		// Fast path: most parts don't use any imported constants
+		if !c.partUsesFoldableConst(sourceIndex, repr, part) {
+			continue
+		}
+
+		f := constFolder{c: c, sourceIndex: sourceIndex, repr: repr}
+		stmts := f.foldStmts(part.Stmts)
+		if !f.didFold {
+			continue
+		}
+		part.Stmts = stmts
+		if len(stmts) == 0 {
+			part.CanBeRemovedIfUnused = true
+		}
+
+		// Remove symbol uses and import records that only appeared in the code
+		// that was removed. This is what lets tree shaking drop the imports used
+		// by the dead branches. Skip this if we can't be sure that something
+		// isn't still used.
+		live := refCollector{}
+		live.stmts(stmts)
+		if live.incomplete {
+			continue
+		}
+		var clonedCallUses, clonedPropertyUses bool
+		for ref := range f.dead.refs {
+			if live.refs[ref] {
+				continue
+			}
+			delete(part.SymbolUses, ref)
+			if _, ok := part.SymbolCallUses[ref]; ok {
+				if !clonedCallUses {
+					clonedCallUses = true
+					clone := make(map[ast.Ref]js_ast.SymbolCallUse, len(part.SymbolCallUses))
+					for k, v := range part.SymbolCallUses {
+						clone[k] = v
+					}
+					part.SymbolCallUses = clone
+				}
+				delete(part.SymbolCallUses, ref)
+			}
+			if _, ok := part.ImportSymbolPropertyUses[ref]; ok {
+				if !clonedPropertyUses {
+					clonedPropertyUses = true
+					clone := make(map[ast.Ref]map[string]js_ast.SymbolUse, len(part.ImportSymbolPropertyUses))
+					for k, v := range part.ImportSymbolPropertyUses {
+						clone[k] = v
+					}
+					part.ImportSymbolPropertyUses = clone
+				}
+				delete(part.ImportSymbolPropertyUses, ref)
+			}
+		}
+		if len(f.dead.importRecords) > 0 {
+			importRecordIndices := make([]uint32, 0, len(part.ImportRecordIndices))
+			for _, importRecordIndex := range part.ImportRecordIndices {
+				if !f.dead.importRecords[importRecordIndex] || live.importRecords[importRecordIndex] {
+					importRecordIndices = append(importRecordIndices, importRecordIndex)
+				}
+			}
+			part.ImportRecordIndices = importRecordIndices
+
+			// The import records themselves were cloned by the linker. Marking them
+			// as unused keeps code splitting from following them.
+			for importRecordIndex := range f.dead.importRecords {
+				if !live.importRecords[importRecordIndex] {
+					repr.AST.ImportRecords[importRecordIndex].Flags |= ast.IsUnused
+				}
+			}
+		}
+	}
+}
+
+// Files in the same import cycle (i.e. strongly-connected component of the
+// graph of import statements and "require()" calls) end up in the same
+// component. Files that aren't in a cycle each have their own component.
+func (c *linkerContext) computeImportCycleComponents() {
+	components := make([]uint32, len(c.graph.Files))
+	indices := make([]uint32, len(c.graph.Files))
+	lowLinks := make([]uint32, len(c.graph.Files))
+	onStack := make([]bool, len(c.graph.Files))
+	var stack []uint32
+	nextIndex := uint32(1)
+	nextComponent := uint32(0)
+
+	// This is Tarjan's strongly-connected components algorithm
+	var visit func(sourceIndex uint32)
+	visit = func(sourceIndex uint32) {
+		indices[sourceIndex] = nextIndex
+		lowLinks[sourceIndex] = nextIndex
+		nextIndex++
+		stack = append(stack, sourceIndex)
+		onStack[sourceIndex] = true
+
+		if repr, ok := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr); ok {
+			for _, record := range repr.AST.ImportRecords {
+				if !record.SourceIndex.IsValid() || (record.Kind != ast.ImportStmt && record.Kind != ast.ImportRequire) {
+					continue
+				}
+				other := record.SourceIndex.GetIndex()
+				if indices[other] == 0 {
+					visit(other)
+					if lowLinks[other] < lowLinks[sourceIndex] {
+						lowLinks[sourceIndex] = lowLinks[other]
+					}
+				} else if onStack[other] && indices[other] < lowLinks[sourceIndex] {
+					lowLinks[sourceIndex] = indices[other]
+				}
+			}
+		}
+
+		if lowLinks[sourceIndex] == indices[sourceIndex] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				components[top] = nextComponent
+				if top == sourceIndex {
+					break
+				}
+			}
+			nextComponent++
+		}
+	}
+
+	for _, sourceIndex := range c.graph.ReachableFiles {
+		if indices[sourceIndex] == 0 {
+			visit(sourceIndex)
+		}
+	}
+	c.importCycleComponents = components
+}
+
+func (c *linkerContext) partUsesFoldableConst(sourceIndex uint32, repr *graph.JSRepr, part *js_ast.Part) bool {
+	for ref := range part.SymbolUses {
+		if c.foldableConstForImport(sourceIndex, repr, ref) != nil {
+			return true
+		}
+	}
+	for ref := range part.ImportSymbolPropertyUses {
+		if c.foldableConstForImport(sourceIndex, repr, ref) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *linkerContext) foldableConstForImport(sourceIndex uint32, repr *graph.JSRepr, ref ast.Ref) *js_ast.FoldableConst {
+	importData, ok := repr.Meta.ImportsToBind[ref]
+	if !ok {
+		return nil
+	}
+
+	// The constant must already have been initialized when the importing file
+	// is evaluated. Otherwise the importing file observes the constant's TDZ
+	// (or "undefined" in the bundle) instead of its value. This is guaranteed
+	// when the exporting file is evaluated first, which isn't the case if the
+	// exporting file is lazily-initialized or if the two files are in the
+	// same import cycle.
+	if otherRepr := c.graph.Files[importData.SourceIndex].InputFile.Repr.(*graph.JSRepr); otherRepr.Meta.Wrap != graph.WrapNone ||
+		c.importCycleComponents[importData.SourceIndex] == c.importCycleComponents[sourceIndex] {
+		return nil
+	}
+	value, ok := c.graph.FoldableConsts[importData.Ref]
+	if !ok || c.graph.Symbols.Get(importData.Ref).Flags.Has(ast.CouldPotentiallyBeMutated) {
+		return nil
+	}
+	return &value
+}
+
+type constFolder struct {
+	c           *linkerContext
+	repr        *graph.JSRepr
+	sourceIndex uint32
+
+	// This holds everything referenced by code that was removed
+	dead refCollector
+
+	didFold bool
+
+	// Conditions are only folded if they involve an imported constant. Other
+	// constant conditions are left for the parser's minifier to deal with.
+	sawImportedConst bool
+}
+
+// This returns a primitive literal if the expression is known to evaluate to
+// one without any side effects
+func (f *constFolder) evalConst(expr js_ast.Expr) (js_ast.Expr, bool) {
+	switch e := expr.Data.(type) {
+	case *js_ast.ENull, *js_ast.EUndefined, *js_ast.EBoolean, *js_ast.ENumber, *js_ast.EString:
+		return expr, true
+
+	case *js_ast.EInlinedEnum:
+		return f.evalConst(e.Value)
+
+	case *js_ast.EImportIdentifier:
+		if value := f.c.foldableConstForImport(f.sourceIndex, f.repr, e.Ref); value != nil && value.Properties == nil {
+			f.sawImportedConst = true
+			return js_ast.ConstValueToExpr(expr.Loc, value.Value), true
+		}
+
+	case *js_ast.EDot:
+		if e.OptionalChain == js_ast.OptionalChainNone {
+			return f.evalFrozenProperty(expr.Loc, e.Target, e.Name)
+		}
+
+	case *js_ast.EIndex:
+		if str, ok := e.Index.Data.(*js_ast.EString); ok && e.OptionalChain == js_ast.OptionalChainNone {
+			return f.evalFrozenProperty(expr.Loc, e.Target, helpers.UTF16ToString(str.Value))
+		}
+
+	case *js_ast.EUnary:
+		if e.Op == js_ast.UnOpNot {
+			if value, ok := f.evalConst(e.Value); ok {
+				if boolean, sideEffects, ok := js_ast.ToBooleanWithSideEffects(value.Data); ok && sideEffects == js_ast.NoSideEffects {
+					return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EBoolean{Value: !boolean}}, true
+				}
+			}
+		}
+
+	case *js_ast.EBinary:
+		switch e.Op {
+		case js_ast.BinOpStrictEq, js_ast.BinOpStrictNe, js_ast.BinOpLooseEq, js_ast.BinOpLooseNe:
+			left, ok := f.evalConst(e.Left)
+			if !ok {
+				break
+			}
+			right, ok := f.evalConst(e.Right)
+			if !ok {
+				break
+			}
+			kind := js_ast.StrictEquality
+			if e.Op == js_ast.BinOpLooseEq || e.Op == js_ast.BinOpLooseNe {
+				kind = js_ast.LooseEquality
+			}
+			if equal, ok := js_ast.CheckEqualityIfNoSideEffects(left.Data, right.Data, kind); ok {
+				if e.Op == js_ast.BinOpStrictNe || e.Op == js_ast.BinOpLooseNe {
+					equal = !equal
+				}
+				return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EBoolean{Value: equal}}, true
+			}
+
+		case js_ast.BinOpLogicalAnd, js_ast.BinOpLogicalOr, js_ast.BinOpNullishCoalescing:
+			left, ok := f.evalConst(e.Left)
+			if !ok {
+				break
+			}
+			if useLeft, ok := shortCircuitsOnLeft(e.Op, left); ok {
+				if useLeft {
+					return left, true
+				}
+				return f.evalConst(e.Right)
+			}
+		}
+	}
+
+	return js_ast.Expr{}, false
+}
+
+func (f *constFolder) evalFrozenProperty(loc logger.Loc, target js_ast.Expr, name string) (js_ast.Expr, bool) {
+	if id, ok := target.Data.(*js_ast.EImportIdentifier); ok {
+		if value := f.c.foldableConstForImport(f.sourceIndex, f.repr, id.Ref); value != nil && value.Properties != nil {
+			// Missing properties come from the prototype, so they aren't foldable
+			if property, ok := value.Properties[name]; ok {
+				f.sawImportedConst = true
+				return js_ast.ConstValueToExpr(loc, property), true
+			}
+		}
+	}
+	return js_ast.Expr{}, false
+}
+
+// Returns true if the result of this logical operator is the left operand
+func shortCircuitsOnLeft(op js_ast.OpCode, left js_ast.Expr) (useLeft bool, ok bool) {
+	if op == js_ast.BinOpNullishCoalescing {
+		isNullOrUndefined, sideEffects, ok := js_ast.ToNullOrUndefinedWithSideEffects(left.Data)
+		return !isNullOrUndefined, ok && sideEffects == js_ast.NoSideEffects
+	}
+	boolean, sideEffects, ok := js_ast.ToBooleanWithSideEffects(left.Data)
+	if !ok || sideEffects != js_ast.NoSideEffects {
+		return false, false
+	}
+	if op == js_ast.BinOpLogicalAnd {
+		return !boolean, true
+	}
+	return boolean, true
+}
+
+// Returns the value of a condition if it can be folded
+func (f *constFolder) evalCondition(test js_ast.Expr) (boolean bool, ok bool) {
+	f.sawImportedConst = false
+	value, ok := f.evalConst(test)
+	if !ok || !f.sawImportedConst {
+		return false, false
+	}
+	boolean, sideEffects, ok := js_ast.ToBooleanWithSideEffects(value.Data)
+	return boolean, ok && sideEffects == js_ast.NoSideEffects
+}
+
+func (f *constFolder) foldStmts(stmts []js_ast.Stmt) []js_ast.Stmt {
+	var result []js_ast.Stmt // This stays nil until something changes
+	for i, stmt := range stmts {
+		replacement, changed := f.foldStmt(stmt)
+		if changed && result == nil {
+			result = append(make([]js_ast.Stmt, 0, len(stmts)), stmts[:i]...)
+		}
+		if changed {
+			result = append(result, replacement...)
+		} else if result != nil {
+			result = append(result, stmt)
+		}
+	}
+	if result == nil {
+		return stmts
+	}
+	return result
+}
+
+// This is for contexts that expect exactly one statement such as loop bodies
+func (f *constFolder) foldSingleStmt(stmt js_ast.Stmt) js_ast.Stmt {
+	replacement, changed := f.foldStmt(stmt)
+	if !changed {
+		return stmt
+	}
+	switch len(replacement) {
+	case 0:
+		return js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SEmpty{}}
+	case 1:
+		return replacement[0]
+	}
+	return js_ast.Stmt{Loc: stmt.Loc, Data: &js_ast.SBlock{Stmts: replacement}}
+}
+
+func (f *constFolder) foldBlock(block js_ast.SBlock) (js_ast.SBlock, bool) {
+	stmts := f.foldStmts(block.Stmts)
+	if len(stmts) == len(block.Stmts) && (len(stmts) == 0 || &stmts[0] == &block.Stmts[0]) {
+		return block, false
+	}
+	block.Stmts = stmts
+	return block, true
+}
+
+func (f *constFolder) foldStmt(stmt js_ast.Stmt) ([]js_ast.Stmt, bool) {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SIf:
+		test := f.foldExpr(s.Test)
+		yes := f.foldSingleStmt(s.Yes)
+		no := s.NoOrNil
+		if no.Data != nil {
+			no = f.foldSingleStmt(no)
+		}
+
+		if boolean, ok := f.evalCondition(test); ok {
+			live, dead := yes, no
+			if !boolean {
+				live, dead = no, yes
+			}
+
+			// Removing the dead branch would remove hoisted declarations that may
+			// still be referenced elsewhere, so leave those alone
+			if dead.Data == nil || !declaresHoistedSymbols(dead) {
+				f.didFold = true
+				f.dead.expr(test)
+				if dead.Data != nil {
+					f.dead.stmt(dead)
+				}
+				if live.Data == nil {
+					return nil, true
+				}
+				stmts := []js_ast.Stmt{live}
+				if block, ok := live.Data.(*js_ast.SBlock); ok {
+					stmts = block.Stmts
+				}
+				if containsLexicalDeclaration(stmts) {
+					return []js_ast.Stmt{{Loc: live.Loc, Data: &js_ast.SBlock{Stmts: stmts}}}, true
+				}
+				return stmts, true
+			}
+		}
+
+		if test.Data != s.Test.Data || yes.Data != s.Yes.Data || no.Data != s.NoOrNil.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &js_ast.SIf{Test: test, Yes: yes, NoOrNil: no}}}, true
+		}
+
+	case *js_ast.SBlock:
+		if block, ok := f.foldBlock(*s); ok {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &block}}, true
+		}
+
+	case *js_ast.SExpr:
+		if value := f.foldExpr(s.Value); value.Data != s.Value.Data {
+			// The minifier turns "if (a) b()" into "a && b()", which may have been
+			// folded into a literal that can now be removed
+			if js_ast.IsPrimitiveLiteral(value.Data) {
+				return nil, true
+			}
+			clone := *s
+			clone.Value = value
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SReturn:
+		if s.ValueOrNil.Data != nil {
+			if value := f.foldExpr(s.ValueOrNil); value.Data != s.ValueOrNil.Data {
+				return []js_ast.Stmt{{Loc: stmt.Loc, Data: &js_ast.SReturn{ValueOrNil: value}}}, true
+			}
+		}
+
+	case *js_ast.SThrow:
+		if value := f.foldExpr(s.Value); value.Data != s.Value.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &js_ast.SThrow{Value: value}}}, true
+		}
+
+	case *js_ast.SLocal:
+		var decls []js_ast.Decl
+		for i, decl := range s.Decls {
+			if decl.ValueOrNil.Data == nil {
+				continue
+			}
+			if value := f.foldExpr(decl.ValueOrNil); value.Data != decl.ValueOrNil.Data {
+				if decls == nil {
+					decls = append([]js_ast.Decl{}, s.Decls...)
+				}
+				decls[i].ValueOrNil = value
+			}
+		}
+		if decls != nil {
+			clone := *s
+			clone.Decls = decls
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SFor:
+		clone := *s
+		if s.InitOrNil.Data != nil {
+			clone.InitOrNil = f.foldSingleStmt(s.InitOrNil)
+		}
+		if s.TestOrNil.Data != nil {
+			clone.TestOrNil = f.foldExpr(s.TestOrNil)
+		}
+		if s.UpdateOrNil.Data != nil {
+			clone.UpdateOrNil = f.foldExpr(s.UpdateOrNil)
+		}
+		clone.Body = f.foldSingleStmt(s.Body)
+		if clone.InitOrNil.Data != s.InitOrNil.Data || clone.TestOrNil.Data != s.TestOrNil.Data ||
+			clone.UpdateOrNil.Data != s.UpdateOrNil.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SForIn:
+		clone := *s
+		clone.Init = f.foldSingleStmt(s.Init)
+		clone.Value = f.foldExpr(s.Value)
+		clone.Body = f.foldSingleStmt(s.Body)
+		if clone.Init.Data != s.Init.Data || clone.Value.Data != s.Value.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SForOf:
+		clone := *s
+		clone.Init = f.foldSingleStmt(s.Init)
+		clone.Value = f.foldExpr(s.Value)
+		clone.Body = f.foldSingleStmt(s.Body)
+		if clone.Init.Data != s.Init.Data || clone.Value.Data != s.Value.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SWhile:
+		clone := *s
+		clone.Test = f.foldExpr(s.Test)
+		clone.Body = f.foldSingleStmt(s.Body)
+		if clone.Test.Data != s.Test.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SDoWhile:
+		clone := *s
+		clone.Body = f.foldSingleStmt(s.Body)
+		clone.Test = f.foldExpr(s.Test)
+		if clone.Test.Data != s.Test.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SLabel:
+		if body := f.foldSingleStmt(s.Stmt); body.Data != s.Stmt.Data {
+			clone := *s
+			clone.Stmt = body
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SWith:
+		clone := *s
+		clone.Value = f.foldExpr(s.Value)
+		clone.Body = f.foldSingleStmt(s.Body)
+		if clone.Value.Data != s.Value.Data || clone.Body.Data != s.Body.Data {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.STry:
+		clone := *s
+		changed := false
+		if block, ok := f.foldBlock(s.Block); ok {
+			clone.Block = block
+			changed = true
+		}
+		if s.Catch != nil {
+			if block, ok := f.foldBlock(s.Catch.Block); ok {
+				catch := *s.Catch
+				catch.Block = block
+				clone.Catch = &catch
+				changed = true
+			}
+		}
+		if s.Finally != nil {
+			if block, ok := f.foldBlock(s.Finally.Block); ok {
+				finally := *s.Finally
+				finally.Block = block
+				clone.Finally = &finally
+				changed = true
+			}
+		}
+		if changed {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SSwitch:
+		clone := *s
+		clone.Test = f.foldExpr(s.Test)
+		changed := clone.Test.Data != s.Test.Data
+		var cases []js_ast.Case
+		for i, c := range s.Cases {
+			value := c.ValueOrNil
+			if value.Data != nil {
+				value = f.foldExpr(value)
+			}
+			body := f.foldStmts(c.Body)
+			if value.Data != c.ValueOrNil.Data || len(body) != len(c.Body) || (len(body) > 0 && &body[0] != &c.Body[0]) {
+				if cases == nil {
+					cases = append([]js_ast.Case{}, s.Cases...)
+				}
+				cases[i].ValueOrNil = value
+				cases[i].Body = body
+			}
+		}
+		if cases != nil {
+			clone.Cases = cases
+			changed = true
+		}
+		if changed {
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SFunction:
+		if fn, ok := f.foldFn(s.Fn); ok {
+			clone := *s
+			clone.Fn = fn
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SClass:
+		if class, ok := f.foldClass(s.Class); ok {
+			clone := *s
+			clone.Class = class
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+
+	case *js_ast.SExportDefault:
+		if value := f.foldSingleStmt(s.Value); value.Data != s.Value.Data {
+			clone := *s
+			clone.Value = value
+			return []js_ast.Stmt{{Loc: stmt.Loc, Data: &clone}}, true
+		}
+	}
+
+	return nil, false
+}
+
+func (f *constFolder) foldExprs(exprs []js_ast.Expr) []js_ast.Expr {
+	var result []js_ast.Expr
+	for i, expr := range exprs {
+		if value := f.foldExpr(expr); value.Data != expr.Data {
+			if result == nil {
+				result = append([]js_ast.Expr{}, exprs...)
+			}
+			result[i] = value
+		}
+	}
+	if result == nil {
+		return exprs
+	}
+	return result
+}
+
+func (f *constFolder) foldArgs(args []js_ast.Arg) []js_ast.Arg {
+	var result []js_ast.Arg
+	for i, arg := range args {
+		if arg.DefaultOrNil.Data == nil {
+			continue
+		}
+		if value := f.foldExpr(arg.DefaultOrNil); value.Data != arg.DefaultOrNil.Data {
+			if result == nil {
+				result = append([]js_ast.Arg{}, args...)
+			}
+			result[i].DefaultOrNil = value
+		}
+	}
+	if result == nil {
+		return args
+	}
+	return result
+}
+
+func (f *constFolder) foldFn(fn js_ast.Fn) (js_ast.Fn, bool) {
+	args := f.foldArgs(fn.Args)
+	block, changed := f.foldBlock(fn.Body.Block)
+	if !changed && (len(args) == 0 || &args[0] == &fn.Args[0]) {
+		return fn, false
+	}
+	fn.Args = args
+	fn.Body.Block = block
+	return fn, true
+}
+
+func (f *constFolder) foldProperties(properties []js_ast.Property) []js_ast.Property {
+	var result []js_ast.Property
+	for i, property := range properties {
+		clone := property
+		if property.Flags.Has(js_ast.PropertyIsComputed) {
+			clone.Key = f.foldExpr(property.Key)
+		}
+		if property.ValueOrNil.Data != nil {
+			clone.ValueOrNil = f.foldExpr(property.ValueOrNil)
+		}
+		if property.InitializerOrNil.Data != nil {
+			clone.InitializerOrNil = f.foldExpr(property.InitializerOrNil)
+		}
+		if property.ClassStaticBlock != nil {
+			if block, ok := f.foldBlock(property.ClassStaticBlock.Block); ok {
+				staticBlock := *property.ClassStaticBlock
+				staticBlock.Block = block
+				clone.ClassStaticBlock = &staticBlock
+			}
+		}
+		if clone.Key.Data != property.Key.Data || clone.ValueOrNil.Data != property.ValueOrNil.Data ||
+			clone.InitializerOrNil.Data != property.InitializerOrNil.Data || clone.ClassStaticBlock != property.ClassStaticBlock {
+			if result == nil {
+				result = append([]js_ast.Property{}, properties...)
+			}
+			result[i] = clone
+		}
+	}
+	if result == nil {
+		return properties
+	}
+	return result
+}
+
+func (f *constFolder) foldClass(class js_ast.Class) (js_ast.Class, bool) {
+	extends := class.ExtendsOrNil
+	if extends.Data != nil {
+		extends = f.foldExpr(extends)
+	}
+	properties := f.foldProperties(class.Properties)
+	if extends.Data == class.ExtendsOrNil.Data && (len(properties) == 0 || &properties[0] == &class.Properties[0]) {
+		return class, false
+	}
+	class.ExtendsOrNil = extends
+	class.Properties = properties
+	return class, true
+}
+
+func (f *constFolder) foldExpr(expr js_ast.Expr) js_ast.Expr {
+	switch e := expr.Data.(type) {
+	case *js_ast.EIf:
+		test := f.foldExpr(e.Test)
+		yes := f.foldExpr(e.Yes)
+		no := f.foldExpr(e.No)
+		if boolean, ok := f.evalCondition(test); ok {
+			f.didFold = true
+			f.dead.expr(test)
+			if boolean {
+				f.dead.expr(no)
+				return yes
+			}
+			f.dead.expr(yes)
+			return no
+		}
+		if test.Data != e.Test.Data || yes.Data != e.Yes.Data || no.Data != e.No.Data {
+			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EIf{Test: test, Yes: yes, No: no}}
+		}
+
+	case *js_ast.EBinary:
+		left := f.foldExpr(e.Left)
+		right := f.foldExpr(e.Right)
+		switch e.Op {
+		case js_ast.BinOpLogicalAnd, js_ast.BinOpLogicalOr, js_ast.BinOpNullishCoalescing:
+			f.sawImportedConst = false
+			if value, ok := f.evalConst(left); ok && f.sawImportedConst {
+				if useLeft, ok := shortCircuitsOnLeft(e.Op, value); ok {
+					f.didFold = true
+					f.dead.expr(left)
+					if useLeft {
+						f.dead.expr(right)
+						return value
+					}
+					return right
+				}
+			}
+		}
+		if left.Data != e.Left.Data || right.Data != e.Right.Data {
+			clone := *e
+			clone.Left = left
+			clone.Right = right
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EUnary:
+		if value := f.foldExpr(e.Value); value.Data != e.Value.Data {
+			clone := *e
+			clone.Value = value
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.ECall:
+		target := f.foldExpr(e.Target)
+		args := f.foldExprs(e.Args)
+		if target.Data != e.Target.Data || (len(args) > 0 && &args[0] != &e.Args[0]) {
+			clone := *e
+			clone.Target = target
+			clone.Args = args
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.ENew:
+		target := f.foldExpr(e.Target)
+		args := f.foldExprs(e.Args)
+		if target.Data != e.Target.Data || (len(args) > 0 && &args[0] != &e.Args[0]) {
+			clone := *e
+			clone.Target = target
+			clone.Args = args
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EDot:
+		if target := f.foldExpr(e.Target); target.Data != e.Target.Data {
+			clone := *e
+			clone.Target = target
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EIndex:
+		target := f.foldExpr(e.Target)
+		index := f.foldExpr(e.Index)
+		if target.Data != e.Target.Data || index.Data != e.Index.Data {
+			clone := *e
+			clone.Target = target
+			clone.Index = index
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EArrow:
+		args := f.foldArgs(e.Args)
+		block, changed := f.foldBlock(e.Body.Block)
+		if changed || (len(args) > 0 && &args[0] != &e.Args[0]) {
+			clone := *e
+			clone.Args = args
+			clone.Body.Block = block
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EFunction:
+		if fn, ok := f.foldFn(e.Fn); ok {
+			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EFunction{Fn: fn}}
+		}
+
+	case *js_ast.EClass:
+		if class, ok := f.foldClass(e.Class); ok {
+			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EClass{Class: class}}
+		}
+
+	case *js_ast.EArray:
+		if items := f.foldExprs(e.Items); len(items) > 0 && &items[0] != &e.Items[0] {
+			clone := *e
+			clone.Items = items
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EObject:
+		if properties := f.foldProperties(e.Properties); len(properties) > 0 && &properties[0] != &e.Properties[0] {
+			clone := *e
+			clone.Properties = properties
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.ESpread:
+		if value := f.foldExpr(e.Value); value.Data != e.Value.Data {
+			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.ESpread{Value: value}}
+		}
+
+	case *js_ast.EAwait:
+		if value := f.foldExpr(e.Value); value.Data != e.Value.Data {
+			return js_ast.Expr{Loc: expr.Loc, Data: &js_ast.EAwait{Value: value}}
+		}
+
+	case *js_ast.EYield:
+		if e.ValueOrNil.Data != nil {
+			if value := f.foldExpr(e.ValueOrNil); value.Data != e.ValueOrNil.Data {
+				clone := *e
+				clone.ValueOrNil = value
+				return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+			}
+		}
+
+	case *js_ast.EAnnotation:
+		if value := f.foldExpr(e.Value); value.Data != e.Value.Data {
+			clone := *e
+			clone.Value = value
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.ETemplate:
+		var parts []js_ast.TemplatePart
+		for i, part := range e.Parts {
+			if value := f.foldExpr(part.Value); value.Data != part.Value.Data {
+				if parts == nil {
+					parts = append([]js_ast.TemplatePart{}, e.Parts...)
+				}
+				parts[i].Value = value
+			}
+		}
+		if parts != nil {
+			clone := *e
+			clone.Parts = parts
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+
+	case *js_ast.EImportCall:
+		value := f.foldExpr(e.Expr)
+		options := e.OptionsOrNil
+		if options.Data != nil {
+			options = f.foldExpr(options)
+		}
+		if value.Data != e.Expr.Data || options.Data != e.OptionsOrNil.Data {
+			clone := *e
+			clone.Expr = value
+			clone.OptionsOrNil = options
+			return js_ast.Expr{Loc: expr.Loc, Data: &clone}
+		}
+	}
+
+	return expr
+}
+
+// Returns true if removing this statement would remove a "var" or function
+// declaration that's hoisted out of it
+func declaresHoistedSymbols(stmt js_ast.Stmt) bool {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SLocal:
+		return s.Kind == js_ast.LocalVar
+	case *js_ast.SFunction, *js_ast.SEnum, *js_ast.SNamespace:
+		return true
+	case *js_ast.SBlock:
+		return stmtsDeclareHoistedSymbols(s.Stmts)
+	case *js_ast.SIf:
+		return declaresHoistedSymbols(s.Yes) || (s.NoOrNil.Data != nil && declaresHoistedSymbols(s.NoOrNil))
+	case *js_ast.SFor:
+		return (s.InitOrNil.Data != nil && declaresHoistedSymbols(s.InitOrNil)) || declaresHoistedSymbols(s.Body)
+	case *js_ast.SForIn:
+		return declaresHoistedSymbols(s.Init) || declaresHoistedSymbols(s.Body)
+	case *js_ast.SForOf:
+		return declaresHoistedSymbols(s.Init) || declaresHoistedSymbols(s.Body)
+	case *js_ast.SWhile:
+		return declaresHoistedSymbols(s.Body)
+	case *js_ast.SDoWhile:
+		return declaresHoistedSymbols(s.Body)
+	case *js_ast.SWith:
+		return declaresHoistedSymbols(s.Body)
+	case *js_ast.SLabel:
+		return declaresHoistedSymbols(s.Stmt)
+	case *js_ast.STry:
+		return stmtsDeclareHoistedSymbols(s.Block.Stmts) ||
+			(s.Catch != nil && stmtsDeclareHoistedSymbols(s.Catch.Block.Stmts)) ||
+			(s.Finally != nil && stmtsDeclareHoistedSymbols(s.Finally.Block.Stmts))
+	case *js_ast.SSwitch:
+		for _, c := range s.Cases {
+			if stmtsDeclareHoistedSymbols(c.Body) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func stmtsDeclareHoistedSymbols(stmts []js_ast.Stmt) bool {
+	for _, stmt := range stmts {
+		if declaresHoistedSymbols(stmt) {
+			return true
+		}
+	}
+	return false
+}
+
+// Statements from the live branch can't be moved into the parent scope if
+// they declare something that's scoped to the branch
+func containsLexicalDeclaration(stmts []js_ast.Stmt) bool {
+	for _, stmt := range stmts {
+		switch s := stmt.Data.(type) {
+		case *js_ast.SLocal:
+			if s.Kind != js_ast.LocalVar {
+				return true
+			}
+		case *js_ast.SFunction, *js_ast.SClass:
+			return true
+		}
+	}
+	return false
+}
+
+// This collects every symbol and import record referenced by some code. It
+// sets "incomplete" if it encounters code it doesn't know how to traverse.
+type refCollector struct {
+	refs          map[ast.Ref]bool
+	importRecords map[uint32]bool
+	incomplete    bool
+}
+
+func (rc *refCollector) ref(ref ast.Ref) {
+	if rc.refs == nil {
+		rc.refs = make(map[ast.Ref]bool)
+	}
+	rc.refs[ref] = true
+}
+
+func (rc *refCollector) importRecord(importRecordIndex uint32) {
+	if rc.importRecords == nil {
+		rc.importRecords = make(map[uint32]bool)
+	}
+	rc.importRecords[importRecordIndex] = true
+}
+
+func (rc *refCollector) locRef(locRef *ast.LocRef) {
+	if locRef != nil {
+		rc.ref(locRef.Ref)
+	}
+}
+
+func (rc *refCollector) stmts(stmts []js_ast.Stmt) {
+	for _, stmt := range stmts {
+		rc.stmt(stmt)
+	}
+}
+
+func (rc *refCollector) stmt(stmt js_ast.Stmt) {
+	switch s := stmt.Data.(type) {
+	case *js_ast.SEmpty, *js_ast.STypeScript, *js_ast.SComment, *js_ast.SDebugger, *js_ast.SDirective:
+
+	case *js_ast.SBlock:
+		rc.stmts(s.Stmts)
+
+	case *js_ast.SExpr:
+		rc.expr(s.Value)
+
+	case *js_ast.SLazyExport:
+		rc.expr(s.Value)
+
+	case *js_ast.SExportEquals:
+		rc.expr(s.Value)
+
+	case *js_ast.SReturn:
+		rc.expr(s.ValueOrNil)
+
+	case *js_ast.SThrow:
+		rc.expr(s.Value)
+
+	case *js_ast.SLocal:
+		for _, decl := range s.Decls {
+			rc.binding(decl.Binding)
+			rc.expr(decl.ValueOrNil)
+		}
+
+	case *js_ast.SIf:
+		rc.expr(s.Test)
+		rc.stmt(s.Yes)
+		rc.stmt(s.NoOrNil)
+
+	case *js_ast.SFor:
+		rc.stmt(s.InitOrNil)
+		rc.expr(s.TestOrNil)
+		rc.expr(s.UpdateOrNil)
+		rc.stmt(s.Body)
+
+	case *js_ast.SForIn:
+		rc.stmt(s.Init)
+		rc.expr(s.Value)
+		rc.stmt(s.Body)
+
+	case *js_ast.SForOf:
+		rc.stmt(s.Init)
+		rc.expr(s.Value)
+		rc.stmt(s.Body)
+
+	case *js_ast.SWhile:
+		rc.expr(s.Test)
+		rc.stmt(s.Body)
+
+	case *js_ast.SDoWhile:
+		rc.stmt(s.Body)
+		rc.expr(s.Test)
+
+	case *js_ast.SWith:
+		rc.expr(s.Value)
+		rc.stmt(s.Body)
+
+	case *js_ast.SLabel:
+		rc.ref(s.Name.Ref)
+		rc.stmt(s.Stmt)
+
+	case *js_ast.SBreak:
+		rc.locRef(s.Label)
+
+	case *js_ast.SContinue:
+		rc.locRef(s.Label)
+
+	case *js_ast.STry:
+		rc.stmts(s.Block.Stmts)
+		if s.Catch != nil {
+			rc.binding(s.Catch.BindingOrNil)
+			rc.stmts(s.Catch.Block.Stmts)
+		}
+		if s.Finally != nil {
+			rc.stmts(s.Finally.Block.Stmts)
+		}
+
+	case *js_ast.SSwitch:
+		rc.expr(s.Test)
+		for _, c := range s.Cases {
+			rc.expr(c.ValueOrNil)
+			rc.stmts(c.Body)
+		}
+
+	case *js_ast.SFunction:
+		rc.fn(&s.Fn)
+
+	case *js_ast.SClass:
+		rc.class(&s.Class)
+
+	case *js_ast.SExportDefault:
+		rc.ref(s.DefaultName.Ref)
+		rc.stmt(s.Value)
+
+	case *js_ast.SEnum:
+		rc.ref(s.Name.Ref)
+		rc.ref(s.Arg)
+		for _, value := range s.Values {
+			rc.ref(value.Ref)
+			rc.expr(value.ValueOrNil)
+		}
+
+	case *js_ast.SNamespace:
+		rc.ref(s.Name.Ref)
+		rc.ref(s.Arg)
+		rc.stmts(s.Stmts)
+
+	case *js_ast.SImport:
+		rc.importRecord(s.ImportRecordIndex)
+		rc.ref(s.NamespaceRef)
+		rc.locRef(s.DefaultName)
+		if s.Items != nil {
+			for _, item := range *s.Items {
+				rc.ref(item.Name.Ref)
+			}
+		}
+
+	case *js_ast.SExportClause:
+		for _, item := range s.Items {
+			rc.ref(item.Name.Ref)
+		}
+
+	case *js_ast.SExportFrom:
+		rc.importRecord(s.ImportRecordIndex)
+		rc.ref(s.NamespaceRef)
+		for _, item := range s.Items {
+			rc.ref(item.Name.Ref)
+		}
+
+	case *js_ast.SExportStar:
+		rc.importRecord(s.ImportRecordIndex)
+		rc.ref(s.NamespaceRef)
+
+	default:
+		if stmt.Data != nil {
+			rc.incomplete = true
+		}
+	}
+}
+
+func (rc *refCollector) binding(binding js_ast.Binding) {
+	switch b := binding.Data.(type) {
+	case *js_ast.BMissing:
+
+	case *js_ast.BIdentifier:
+		rc.ref(b.Ref)
+
+	case *js_ast.BArray:
+		for _, item := range b.Items {
+			rc.binding(item.Binding)
+			rc.expr(item.DefaultValueOrNil)
+		}
+
+	case *js_ast.BObject:
+		for _, property := range b.Properties {
+			rc.expr(property.Key)
+			rc.binding(property.Value)
+			rc.expr(property.DefaultValueOrNil)
+		}
+
+	default:
+		if binding.Data != nil {
+			rc.incomplete = true
+		}
+	}
+}
+
+func (rc *refCollector) fn(fn *js_ast.Fn) {
+	rc.locRef(fn.Name)
+	rc.ref(fn.ArgumentsRef)
+	rc.args(fn.Args)
+	rc.stmts(fn.Body.Block.Stmts)
+}
+
+func (rc *refCollector) args(args []js_ast.Arg) {
+	for _, arg := range args {
+		rc.decorators(arg.Decorators)
+		rc.binding(arg.Binding)
+		rc.expr(arg.DefaultOrNil)
+	}
+}
+
+func (rc *refCollector) decorators(decorators []js_ast.Decorator) {
+	for _, decorator := range decorators {
+		rc.expr(decorator.Value)
+	}
+}
+
+func (rc *refCollector) class(class *js_ast.Class) {
+	rc.decorators(class.Decorators)
+	rc.locRef(class.Name)
+	rc.expr(class.ExtendsOrNil)
+	rc.properties(class.Properties)
+}
+
+func (rc *refCollector) properties(properties []js_ast.Property) {
+	for _, property := range properties {
+		rc.decorators(property.Decorators)
+		rc.expr(property.Key)
+		rc.expr(property.ValueOrNil)
+		rc.expr(property.InitializerOrNil)
+		if property.ClassStaticBlock != nil {
+			rc.stmts(property.ClassStaticBlock.Block.Stmts)
+		}
+	}
+}
+
+func (rc *refCollector) exprs(exprs []js_ast.Expr) {
+	for _, expr := range exprs {
+		rc.expr(expr)
+	}
+}
+
+func (rc *refCollector) expr(expr js_ast.Expr) {
+	switch e := expr.Data.(type) {
+	case *js_ast.EMissing, *js_ast.ESuper, *js_ast.ENull, *js_ast.EUndefined, *js_ast.EThis,
+		*js_ast.ENewTarget, *js_ast.EImportMeta, *js_ast.EBoolean, *js_ast.ENumber, *js_ast.EBigInt,
+		*js_ast.EString, *js_ast.ERegExp, *js_ast.EJSXText:
+
+	case *js_ast.EIdentifier:
+		rc.ref(e.Ref)
+
+	case *js_ast.EImportIdentifier:
+		rc.ref(e.Ref)
+
+	case *js_ast.EPrivateIdentifier:
+		rc.ref(e.Ref)
+
+	case *js_ast.ENameOfSymbol:
+		rc.ref(e.Ref)
+
+	case *js_ast.ERequireString:
+		rc.importRecord(e.ImportRecordIndex)
+
+	case *js_ast.ERequireResolveString:
+		rc.importRecord(e.ImportRecordIndex)
+
+	case *js_ast.EImportString:
+		rc.importRecord(e.ImportRecordIndex)
+
+	case *js_ast.EImportPathString:
+		rc.importRecord(e.ImportRecordIndex)
+
+	case *js_ast.EImportCall:
+		rc.expr(e.Expr)
+		rc.expr(e.OptionsOrNil)
+
+	case *js_ast.EArray:
+		rc.exprs(e.Items)
+
+	case *js_ast.EUnary:
+		rc.expr(e.Value)
+
+	case *js_ast.EBinary:
+		rc.expr(e.Left)
+		rc.expr(e.Right)
+
+	case *js_ast.EIf:
+		rc.expr(e.Test)
+		rc.expr(e.Yes)
+		rc.expr(e.No)
+
+	case *js_ast.ENew:
+		rc.expr(e.Target)
+		rc.exprs(e.Args)
+
+	case *js_ast.ECall:
+		rc.expr(e.Target)
+		rc.exprs(e.Args)
+
+	case *js_ast.EDot:
+		rc.expr(e.Target)
+
+	case *js_ast.EIndex:
+		rc.expr(e.Target)
+		rc.expr(e.Index)
+
+	case *js_ast.EArrow:
+		rc.args(e.Args)
+		rc.stmts(e.Body.Block.Stmts)
+
+	case *js_ast.EFunction:
+		rc.fn(&e.Fn)
+
+	case *js_ast.EClass:
+		rc.class(&e.Class)
+
+	case *js_ast.EObject:
+		rc.properties(e.Properties)
+
+	case *js_ast.EJSXElement:
+		rc.expr(e.TagOrNil)
+		rc.properties(e.Properties)
+		rc.exprs(e.NullableChildren)
+
+	case *js_ast.ESpread:
+		rc.expr(e.Value)
+
+	case *js_ast.ETemplate:
+		rc.expr(e.TagOrNil)
+		for _, part := range e.Parts {
+			rc.expr(part.Value)
+		}
+
+	case *js_ast.EInlinedEnum:
+		rc.expr(e.Value)
+
+	case *js_ast.EAnnotation:
+		rc.expr(e.Value)
+
+	case *js_ast.EAwait:
+		rc.expr(e.Value)
+
+	case *js_ast.EYield:
+		rc.expr(e.ValueOrNil)
+
+	default:
+		if expr.Data != nil {
+			rc.incomplete = true
+		}
+	}
+}
@@ -65,12 +65,29 @@ type linkerContext struct {
 	// Property mangling results go here
 	mangledProps map[ast.Ref]string
 
-	// We may need to refer to the CommonJS "module" symbol for exports
-	unboundModuleRef ast.Ref
+	// This is the pre-formatted "why" metadata for each live file
+	metafileWhy map[uint32]string
+
+	// We may need to refer to the CommonJS "module" symbol for exports. The
+	// SystemJS format uses "module" for "import()" and "import.meta" and uses
+	// "exports" to update the values of exports.
+	unboundModuleRef  ast.Ref
+	unboundExportsRef ast.Ref
+
+	// Code splitting with the IIFE format declares a chunk loader at the top of
+	// every chunk. This symbol is shared between all chunks. The code for the
+	// chunk loader is the "__chunkLoader" runtime function, which lives in the
+	// chunk containing the runtime code. Every other chunk imports that chunk.
+	chunkLoaderRef        ast.Ref
+	chunkLoaderRuntimeRef ast.Ref
 
 	// We may need to refer to the "__esm" and/or "__commonJS" runtime symbols
 	cjsRuntimeRef ast.Ref
 	esmRuntimeRef ast.Ref
+
+	// This is used to avoid folding constants imported from within an import
+	// cycle, since they may not be initialized yet
+	importCycleComponents []uint32
 }
 
 type partRange struct {
@@ -120,11 +137,20 @@ type chunkInfo struct {
 	// omitted.
 	intermediateOutput intermediateOutput
 
+	// For hot module replacement, this maps the path of each module in this
+	// chunk to a hash of its generated code. This is compared between builds
+	// to determine which modules have changed.
+	hmrModuleHashes map[string]string
+
 	// This information is only useful if "isEntryPoint" is true
 	entryPointBit uint   // An index into "c.graph.EntryPoints"
 	sourceIndex   uint32 // An index into "c.sources"
 	isEntryPoint  bool
 
+	// This is non-empty if this chunk was created from a manual chunk. It's
+	// used instead of "chunk" for the "[name]" placeholder.
+	manualChunkName string
+
 	isExecutable bool
 }
 
@@ -139,6 +165,7 @@ const (
 	outputPieceNone outputPieceIndexKind = iota
 	outputPieceAssetIndex
 	outputPieceChunkIndex
+	outputPieceWorkerIndex
 )
 
 // This is a chunk of source code followed by a reference to another chunk. For
@@ -183,6 +210,20 @@ type chunkReprJS struct {
 	crossChunkPrefixStmts  []js_ast.Stmt
 	crossChunkSuffixStmts  []js_ast.Stmt
 
+	// For code splitting with formats other than ESM. Symbols imported from
+	// other chunks become property accesses off of a namespace object for that
+	// chunk. Entry point chunks in the IIFE format register their exports in
+	// the entry point tail, so the getters for their cross-chunk exports are
+	// kept here until then.
+	crossChunkAliases       map[ast.Ref]ast.NamespaceAlias
+	crossChunkNamespaceRefs []ast.Ref
+	crossChunkExportGetters []js_ast.Property
+	chunkLoaderPaths        map[string][]string
+
+	// For the SystemJS format. This maps each exported symbol to its export
+	// aliases so that assignments to it can update the exported values.
+	systemJSExports map[ast.Ref][]string
+
 	cssChunkIndex uint32
 	hasCSSChunk   bool
 }
@@ -259,9 +300,13 @@ func Link(
 	}
 	timer.End("Clone linker graph")
 
-	// Use a smaller version of these functions if we don't need profiler names
+	// Use a smaller version of these functions if we don't need profiler names.
+	// Hot module replacement registers CommonJS closures with a registry instead.
 	runtimeRepr := c.graph.Files[runtime.SourceIndex].InputFile.Repr.(*graph.JSRepr)
-	if c.options.ProfilerNames {
+	if c.options.HotModuleReplacement {
+		c.cjsRuntimeRef = runtimeRepr.AST.NamedExports["__hmrModule"].Ref
+		c.esmRuntimeRef = runtimeRepr.AST.NamedExports["__esm"].Ref
+	} else if c.options.ProfilerNames {
 		c.cjsRuntimeRef = runtimeRepr.AST.NamedExports["__commonJS"].Ref
 		c.esmRuntimeRef = runtimeRepr.AST.NamedExports["__esm"].Ref
 	} else {
@@ -284,10 +329,14 @@ func Link(
 
 			// Entry points with ES6 exports must generate an exports object when
 			// targeting non-ES6 formats. Note that the IIFE format only needs this
-			// when the global name is present, since that's the only way the exports
-			// can actually be observed externally.
-			if repr.AST.ExportKeyword.Len > 0 && (options.OutputFormat == config.FormatCommonJS ||
-				(options.OutputFormat == config.FormatIIFE && len(options.GlobalName) > 0)) {
+			// when the global name is present or when code splitting is active,
+			// since those are the only ways the exports can actually be observed
+			// externally. The AMD and UMD formats always return the exports. This
+			// isn't needed with hot module replacement since then every module
+			// already has an exports object.
+			if (repr.AST.ExportKeyword.Len > 0 || repr.AST.ConvertedFromCommonJS) && !options.HotModuleReplacement && (options.OutputFormat == config.FormatCommonJS ||
+				options.OutputFormat == config.FormatAMD || options.OutputFormat == config.FormatUMD ||
+				(options.OutputFormat == config.FormatIIFE && (len(options.GlobalName) > 0 || options.CodeSplitting))) {
 				repr.AST.UsesExportsRef = true
 				repr.Meta.ForceIncludeExportsForEntryPoint = true
 			}
@@ -301,12 +350,30 @@ func Link(
 		}
 	}
 
+	// Code splitting with formats other than ESM hands the exports of entry
+	// points that are only imported dynamically to other chunks using an
+	// exports object, so those entry points need one too
+	if options.CodeSplitting && !options.HotModuleReplacement &&
+		(options.OutputFormat == config.FormatCommonJS || options.OutputFormat == config.FormatIIFE) {
+		for _, entryPoint := range c.graph.EntryPoints() {
+			if repr, ok := c.graph.Files[entryPoint.SourceIndex].InputFile.Repr.(*graph.JSRepr); ok && (repr.AST.ExportKeyword.Len > 0 || repr.AST.ConvertedFromCommonJS) {
+				repr.AST.UsesExportsRef = true
+				repr.Meta.ForceIncludeExportsForEntryPoint = true
+			}
+		}
+	}
+
 	// Allocate a new unbound symbol called "module" in case we need it later
-	if c.options.OutputFormat == config.FormatCommonJS {
+	if c.options.OutputFormat == config.FormatCommonJS || c.options.OutputFormat == config.FormatSystemJS {
 		c.unboundModuleRef = c.graph.GenerateNewSymbol(runtime.SourceIndex, ast.SymbolUnbound, "module")
 	} else {
 		c.unboundModuleRef = ast.InvalidRef
 	}
+	if c.options.OutputFormat == config.FormatSystemJS {
+		c.unboundExportsRef = c.graph.GenerateNewSymbol(runtime.SourceIndex, ast.SymbolUnbound, "exports")
+	} else {
+		c.unboundExportsRef = ast.InvalidRef
+	}
 
 	c.scanImportsAndExports()
 
@@ -318,8 +385,18 @@ func Link(
 		return []graph.OutputFile{}
 	}
 
+	// Folding constants may have removed all "import()" expressions for some
+	// dynamic entry points, in which case they shouldn't generate a chunk
+	if c.options.CodeSplitting {
+		c.graph.RemoveUnusedDynamicImportEntryPoints()
+	}
+
 	c.treeShakingAndCodeSplitting()
 
+	if c.options.NeedsMetafileWhy {
+		c.computeMetafileWhy()
+	}
+
 	if c.options.Mode == config.ModePassThrough {
 		for _, entryPoint := range c.graph.EntryPoints() {
 			c.preventExportsFromBeingRenamed(entryPoint.SourceIndex)
@@ -709,6 +786,19 @@ func (c *linkerContext) generateChunksInParallel(additionalFiles []graph.OutputF
 				})
 			}
 
+			// Generate the optional TypeScript declaration file for this chunk
+			if c.options.TSDeclaration && chunk.isEntryPoint {
+				if repr, ok := c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr); ok && repr.AST.TSDeclaration != "" {
+					finalRelPathForDeclaration := tsDeclarationPath(chunk.finalRelPath)
+					outputFiles = append(outputFiles, graph.OutputFile{
+						AbsPath:  c.fs.Join(c.options.AbsOutputDir, finalRelPathForDeclaration),
+						Contents: []byte(repr.AST.TSDeclaration),
+						JSONMetadataChunk: fmt.Sprintf(
+							"{\n      \"imports\": [],\n      \"exports\": [],\n      \"inputs\": {},\n      \"bytes\": %d\n    }", len(repr.AST.TSDeclaration)),
+					})
+				}
+			}
+
 			// Generate the optional source map for this chunk
 			if c.options.SourceMap != config.SourceMapNone && chunk.outputSourceMap.HasContent() {
 				outputSourceMap := chunk.outputSourceMap.Finalize(outputSourceMapShifts)
@@ -761,11 +851,19 @@ func (c *linkerContext) generateChunksInParallel(additionalFiles []graph.OutputF
 			}
 
 			// Generate the output file for this chunk
+			var entryPointSourceIndex ast.Index32
+			if chunk.isEntryPoint {
+				entryPointSourceIndex = ast.MakeIndex32(chunk.sourceIndex)
+			}
+			_, isCSS := chunk.chunkRepr.(*chunkReprCSS)
 			outputFiles = append(outputFiles, graph.OutputFile{
-				AbsPath:           c.fs.Join(c.options.AbsOutputDir, chunk.finalRelPath),
-				Contents:          outputContents,
-				JSONMetadataChunk: jsonMetadataChunk,
-				IsExecutable:      chunk.isExecutable,
+				AbsPath:               c.fs.Join(c.options.AbsOutputDir, chunk.finalRelPath),
+				Contents:              outputContents,
+				JSONMetadataChunk:     jsonMetadataChunk,
+				HMRModuleHashes:       chunk.hmrModuleHashes,
+				EntryPointSourceIndex: entryPointSourceIndex,
+				IsCSS:                 isCSS,
+				IsExecutable:          chunk.isExecutable,
 			})
 
 			results[chunkIndex] = outputFiles
@@ -837,6 +935,13 @@ func (c *linkerContext) substituteFinalPaths(
 			shift.Before.AdvanceString(chunk.uniqueKey)
 			shift.After.AdvanceString(importPath)
 			shifts = append(shifts, shift)
+
+		case outputPieceWorkerIndex:
+			importPath := modifyPath(c.options.WorkerOutputPaths[piece.index])
+			j.AddString(importPath)
+			shift.Before.AdvanceString(fmt.Sprintf("%sW%08d", c.uniqueKeyPrefix, piece.index))
+			shift.After.AdvanceString(importPath)
+			shifts = append(shifts, shift)
 		}
 	}
 
@@ -868,6 +973,10 @@ func (c *linkerContext) accurateFinalByteCount(output intermediateOutput, chunkF
 			chunk := c.chunks[piece.index]
 			importPath := c.pathBetweenChunks(chunkFinalRelDir, chunk.finalRelPath)
 			count += len(importPath)
+
+		case outputPieceWorkerIndex:
+			importPath := c.pathBetweenChunks(chunkFinalRelDir, c.options.WorkerOutputPaths[piece.index])
+			count += len(importPath)
 		}
 	}
 
@@ -877,7 +986,7 @@ func (c *linkerContext) accurateFinalByteCount(output intermediateOutput, chunkF
 func (c *linkerContext) pathBetweenChunks(fromRelDir string, toRelPath string) string {
 	// Join with the public path if it has been configured
 	if c.options.PublicPath != "" {
-		return joinWithPublicPath(c.options.PublicPath, toRelPath)
+		return helpers.JoinWithPublicPath(c.options.PublicPath, toRelPath)
 	}
 
 	// Otherwise, return a relative path
@@ -900,6 +1009,17 @@ func (c *linkerContext) pathBetweenChunks(fromRelDir string, toRelPath string) s
 	return relPath
 }
 
+// The declaration file for "foo.js" is "foo.d.ts", for "foo.mjs" is
+// "foo.d.mts", and for "foo.cjs" is "foo.d.cts"
+func tsDeclarationPath(relPath string) string {
+	for _, ext := range []string{".js", ".mjs", ".cjs"} {
+		if strings.HasSuffix(relPath, ext) {
+			return relPath[:len(relPath)-len(ext)] + ".d." + ext[1:len(ext)-2] + "ts"
+		}
+	}
+	return relPath + ".d.ts"
+}
+
 func (c *linkerContext) computeCrossChunkDependencies() {
 	c.timer.Begin("Compute cross-chunk dependencies")
 	defer c.timer.End("Compute cross-chunk dependencies")
@@ -909,6 +1029,20 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 		return
 	}
 
+	runtimeRepr := c.graph.Files[runtime.SourceIndex].InputFile.Repr.(*graph.JSRepr)
+	toCommonJSRef := ast.FollowSymbols(c.graph.Symbols, runtimeRepr.AST.NamedExports["__toCommonJS"].Ref)
+
+	// Formats other than ESM generate some additional code to link chunks
+	// together, which refers to these symbols
+	var objectRef ast.Ref
+	switch c.options.OutputFormat {
+	case config.FormatCommonJS:
+		objectRef = c.graph.GenerateNewSymbol(runtime.SourceIndex, ast.SymbolUnbound, "Object")
+	case config.FormatIIFE:
+		c.chunkLoaderRef = c.graph.GenerateNewSymbol(runtime.SourceIndex, ast.SymbolOther, "__chunk")
+		c.chunkLoaderRuntimeRef = ast.FollowSymbols(c.graph.Symbols, runtimeRepr.AST.NamedExports["__chunkLoader"].Ref)
+	}
+
 	type chunkMeta struct {
 		imports        map[ast.Ref]bool
 		exports        map[ast.Ref]bool
@@ -1045,6 +1179,12 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 					// Ensure "exports" is included if the current output format needs it
 					if repr.Meta.ForceIncludeExportsForEntryPoint {
 						imports[repr.AST.ExportsRef] = true
+
+						// The entry point tail passes "exports" to "__toCommonJS", which
+						// lives in whatever chunk the runtime code ended up in
+						if repr.Meta.Wrap != graph.WrapCJS {
+							imports[toCommonJSRef] = true
+						}
 					}
 
 					// Include the wrapper if present
@@ -1059,6 +1199,14 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 	}
 	waitGroup.Wait()
 
+	// Every chunk needs the chunk loader, which is only declared in the chunk
+	// containing the runtime code. Other chunks get it from the global registry
+	// instead of importing it, but that chunk must still be loaded first.
+	chunkLoaderChunkIndex := ast.Index32{}
+	if c.options.OutputFormat == config.FormatIIFE {
+		chunkLoaderChunkIndex = c.graph.Symbols.Get(c.chunkLoaderRuntimeRef).ChunkIndex
+	}
+
 	// Mark imported symbols as exported in the chunk from which they are declared
 	for chunkIndex := range c.chunks {
 		chunk := &c.chunks[chunkIndex]
@@ -1070,6 +1218,12 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 
 		// Find all uses in this chunk of symbols from other chunks
 		chunkRepr.importsFromOtherChunks = make(map[uint32]crossChunkImportItemArray)
+		if chunkLoaderChunkIndex.IsValid() {
+			delete(chunkMeta.imports, c.chunkLoaderRuntimeRef)
+			if otherChunkIndex := chunkLoaderChunkIndex.GetIndex(); otherChunkIndex != uint32(chunkIndex) {
+				chunkRepr.importsFromOtherChunks[otherChunkIndex] = nil
+			}
+		}
 		for importRef := range chunkMeta.imports {
 			// Ignore uses that aren't top-level symbols
 			if otherChunkIndex := c.graph.Symbols.Get(importRef).ChunkIndex; otherChunkIndex.IsValid() {
@@ -1087,10 +1241,38 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 		if chunk.isEntryPoint {
 			for otherChunkIndex, otherChunk := range c.chunks {
 				if _, ok := otherChunk.chunkRepr.(*chunkReprJS); ok && chunkIndex != otherChunkIndex && otherChunk.entryBits.HasBit(chunk.entryPointBit) {
+					// When modules are preserved, the runtime code ends up in a chunk that
+					// belongs to every module. It has no side effects, so it's only imported
+					// by the modules that actually use something from it.
+					if c.options.PreserveModules && len(otherChunk.filesWithPartsInChunk) == 1 && otherChunk.filesWithPartsInChunk[runtime.SourceIndex] {
+						continue
+					}
 					imports := chunkRepr.importsFromOtherChunks[uint32(otherChunkIndex)]
 					chunkRepr.importsFromOtherChunks[uint32(otherChunkIndex)] = imports
 				}
 			}
+
+			// When modules are preserved, other modules aren't part of this entry
+			// point's chunks. Import the modules that this module imports instead
+			// so that they are still evaluated for their side effects.
+			if c.options.PreserveModules {
+				if repr, ok := c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr); ok {
+					for _, record := range repr.AST.ImportRecords {
+						if !record.SourceIndex.IsValid() || record.Flags.Has(ast.IsUnused) ||
+							(record.Kind != ast.ImportStmt && record.Kind != ast.ImportRequire) {
+							continue
+						}
+						if otherFile := &c.graph.Files[record.SourceIndex.GetIndex()]; otherFile.IsEntryPoint() {
+							if otherChunkIndex := otherFile.EntryPointChunkIndex; otherChunkIndex != uint32(chunkIndex) {
+								if _, ok := c.chunks[otherChunkIndex].chunkRepr.(*chunkReprJS); ok {
+									imports := chunkRepr.importsFromOtherChunks[otherChunkIndex]
+									chunkRepr.importsFromOtherChunks[otherChunkIndex] = imports
+								}
+							}
+						}
+					}
+				}
+			}
 		}
 
 		// Make sure we also track dynamic cross-chunk imports. These need to be
@@ -1121,35 +1303,118 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 			continue
 		}
 
-		chunkRepr.exportsToOtherChunks = make(map[ast.Ref]string)
-		switch c.options.OutputFormat {
-		case config.FormatESModule:
-			r := renamer.ExportRenamer{}
-			var items []js_ast.ClauseItem
-			for _, export := range c.sortedCrossChunkExportItems(chunkMetas[chunkIndex].exports) {
-				var alias string
-				if c.options.MinifyIdentifiers {
-					alias = r.NextMinifiedName()
-				} else {
-					alias = r.NextRenamedName(c.graph.Symbols.Get(export.Ref).OriginalName)
+		// Cross-chunk exports end up next to the exports of the entry point, so
+		// they must not reuse any of the entry point's export names
+		r := renamer.ExportRenamer{}
+		var entryPointExportAliases map[ast.Ref]string
+		if chunk.isEntryPoint {
+			if repr, ok := c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr); ok && repr.Meta.Wrap != graph.WrapCJS {
+				for _, alias := range repr.Meta.SortedAndFilteredExportAliases {
+					r.Reserve(alias)
 				}
-				items = append(items, js_ast.ClauseItem{Name: ast.LocRef{Ref: export.Ref}, Alias: alias})
+
+				// When modules are preserved, other modules can just use the module's
+				// own exports instead of duplicating them under different names
+				if c.options.PreserveModules && c.options.OutputFormat != config.FormatIIFE {
+					entryPointExportAliases = make(map[ast.Ref]string)
+					for _, alias := range repr.Meta.SortedAndFilteredExportAliases {
+						export := repr.Meta.ResolvedExports[alias]
+						if export.SourceIndex != chunk.sourceIndex || c.graph.Symbols.Get(export.Ref).NamespaceAlias != nil {
+							continue
+						}
+						if _, ok := repr.Meta.ImportsToBind[export.Ref]; ok {
+							continue
+						}
+						ref := ast.FollowSymbols(c.graph.Symbols, export.Ref)
+						if _, ok := entryPointExportAliases[ref]; !ok {
+							entryPointExportAliases[ref] = alias
+						}
+					}
+				}
+			}
+		}
+
+		chunkRepr.exportsToOtherChunks = make(map[ast.Ref]string)
+		var items []js_ast.ClauseItem
+		var getters []js_ast.Property
+		for _, export := range c.sortedCrossChunkExportItems(chunkMetas[chunkIndex].exports) {
+			if alias, ok := entryPointExportAliases[ast.FollowSymbols(c.graph.Symbols, export.Ref)]; ok {
 				chunkRepr.exportsToOtherChunks[export.Ref] = alias
+				continue
+			}
+			var alias string
+			if c.options.MinifyIdentifiers {
+				alias = r.NextMinifiedName()
+			} else {
+				alias = r.NextRenamedName(c.graph.Symbols.Get(export.Ref).OriginalName)
+			}
+			chunkRepr.exportsToOtherChunks[export.Ref] = alias
+
+			switch c.options.OutputFormat {
+			case config.FormatESModule, config.FormatSystemJS:
+				items = append(items, js_ast.ClauseItem{Name: ast.LocRef{Ref: export.Ref}, Alias: alias})
+
+			case config.FormatCommonJS, config.FormatIIFE:
+				getters = append(getters, js_ast.Property{
+					Key:        js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16(alias)}},
+					ValueOrNil: c.generateGetter(js_ast.Expr{Data: &js_ast.EIdentifier{Ref: export.Ref}}),
+				})
+
+			default:
+				panic("Internal error")
 			}
+		}
+
+		switch c.options.OutputFormat {
+		case config.FormatESModule, config.FormatSystemJS:
 			if len(items) > 0 {
 				chunkRepr.crossChunkSuffixStmts = []js_ast.Stmt{{Data: &js_ast.SExportClause{
 					Items: items,
 				}}}
 			}
 
-		default:
-			panic("Internal error")
+		case config.FormatCommonJS:
+			if len(getters) > 0 {
+				// "Object.defineProperties(module.exports, { a: { get: () => a, enumerable: true } });"
+				for i, getter := range getters {
+					getters[i].ValueOrNil = js_ast.Expr{Data: &js_ast.EObject{Properties: []js_ast.Property{
+						{Key: js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16("get")}}, ValueOrNil: getter.ValueOrNil},
+						{Key: js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16("enumerable")}}, ValueOrNil: js_ast.Expr{Data: &js_ast.EBoolean{Value: true}}},
+					}}}
+				}
+				chunkRepr.crossChunkSuffixStmts = []js_ast.Stmt{{Data: &js_ast.SExpr{Value: js_ast.Expr{Data: &js_ast.ECall{
+					Kind: js_ast.TargetWasOriginallyPropertyAccess,
+					Target: js_ast.Expr{Data: &js_ast.EDot{
+						Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: objectRef}},
+						Name:   "defineProperties",
+					}},
+					Args: []js_ast.Expr{
+						{Data: &js_ast.EDot{
+							Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: c.unboundModuleRef}},
+							Name:   "exports",
+						}},
+						{Data: &js_ast.EObject{Properties: getters, IsSingleLine: len(getters) == 1}},
+					},
+				}}}}}
+			}
+
+		case config.FormatIIFE:
+			// Entry points register their exports in the entry point tail instead
+			if chunk.isEntryPoint {
+				chunkRepr.crossChunkExportGetters = getters
+			} else if len(getters) > 0 {
+				// "__chunk.define({}, { a: () => a });"
+				chunkRepr.crossChunkSuffixStmts = []js_ast.Stmt{{Data: &js_ast.SExpr{
+					Value: c.generateChunkDefine(js_ast.Expr{Data: &js_ast.EObject{}}, getters),
+				}}}
+			}
 		}
 	}
 
 	// Generate cross-chunk imports. These must be computed after cross-chunk
 	// exports because the export aliases must already be finalized so they can
 	// be embedded in the generated import statements.
+	namespaceRefs := make(map[uint32]ast.Ref)
 	for chunkIndex := range c.chunks {
 		chunk := &c.chunks[chunkIndex]
 		chunkRepr, ok := chunk.chunkRepr.(*chunkReprJS)
@@ -1161,7 +1426,38 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 
 		for _, crossChunkImport := range c.sortedCrossChunkImports(chunkRepr.importsFromOtherChunks) {
 			switch c.options.OutputFormat {
-			case config.FormatESModule:
+			case config.FormatCommonJS, config.FormatIIFE:
+				importRecordIndex := uint32(len(chunk.crossChunkImports))
+				chunk.crossChunkImports = append(chunk.crossChunkImports, chunkImport{
+					importKind: ast.ImportRequire,
+					chunkIndex: crossChunkImport.chunkIndex,
+				})
+				value := js_ast.Expr{Data: &js_ast.ERequireString{ImportRecordIndex: importRecordIndex}}
+				if len(crossChunkImport.sortedImportItems) > 0 {
+					// "var import_chunk = require('./chunk.js');"
+					namespaceRef, ok := namespaceRefs[crossChunkImport.chunkIndex]
+					if !ok {
+						namespaceRef = c.graph.GenerateNewSymbol(runtime.SourceIndex, ast.SymbolOther,
+							"import_"+c.chunks[crossChunkImport.chunkIndex].nameForNamespace(c))
+						namespaceRefs[crossChunkImport.chunkIndex] = namespaceRef
+					}
+					if chunkRepr.crossChunkAliases == nil {
+						chunkRepr.crossChunkAliases = make(map[ast.Ref]ast.NamespaceAlias)
+					}
+					for _, item := range crossChunkImport.sortedImportItems {
+						chunkRepr.crossChunkAliases[item.ref] = ast.NamespaceAlias{NamespaceRef: namespaceRef, Alias: item.exportAlias}
+					}
+					chunkRepr.crossChunkNamespaceRefs = append(chunkRepr.crossChunkNamespaceRefs, namespaceRef)
+					crossChunkPrefixStmts = append(crossChunkPrefixStmts, js_ast.Stmt{Data: &js_ast.SLocal{Decls: []js_ast.Decl{{
+						Binding:    js_ast.Binding{Data: &js_ast.BIdentifier{Ref: namespaceRef}},
+						ValueOrNil: value,
+					}}}})
+				} else {
+					// "require('./chunk.js');"
+					crossChunkPrefixStmts = append(crossChunkPrefixStmts, js_ast.Stmt{Data: &js_ast.SExpr{Value: value}})
+				}
+
+			case config.FormatESModule, config.FormatSystemJS:
 				var items []js_ast.ClauseItem
 				for _, item := range crossChunkImport.sortedImportItems {
 					items = append(items, js_ast.ClauseItem{Name: ast.LocRef{Ref: item.ref}, Alias: item.exportAlias})
@@ -1191,6 +1487,129 @@ func (c *linkerContext) computeCrossChunkDependencies() {
 
 		chunkRepr.crossChunkPrefixStmts = crossChunkPrefixStmts
 	}
+
+	// Chunks loaded at run-time by the chunk loader need the chunks that they
+	// import to be loaded first, so remember the order to load them in
+	if c.options.OutputFormat == config.FormatIIFE {
+		for chunkIndex := range c.chunks {
+			chunk := &c.chunks[chunkIndex]
+			chunkRepr, ok := chunk.chunkRepr.(*chunkReprJS)
+			if !ok {
+				continue
+			}
+			for _, chunkImport := range chunk.crossChunkImports {
+				if chunkImport.importKind == ast.ImportDynamic {
+					if chunkRepr.chunkLoaderPaths == nil {
+						chunkRepr.chunkLoaderPaths = make(map[string][]string)
+					}
+					visited := make(map[uint32]bool)
+					visited[chunkImport.chunkIndex] = true
+					chunkRepr.chunkLoaderPaths[c.chunks[chunkImport.chunkIndex].uniqueKey] = c.staticChunkDependencies(chunkImport.chunkIndex, visited, nil)
+				}
+			}
+		}
+	}
+}
+
+// Code splitting with the IIFE format uses a global registry of loaded chunks
+// that is shared by all chunks on the page. Each chunk creates a loader that
+// resolves paths relative to its own URL. The loader itself returns the exports
+// of an already-loaded chunk, "define" registers the exports of the current
+// chunk, and "load" loads chunks that haven't been loaded yet. The code for
+// this is the "__chunkLoader" runtime function, which the chunk containing the
+// runtime code calls directly. Other chunks get it from the registry. In a web
+// worker, the runtime chunk may not have been loaded yet, so it's loaded first.
+const chunkLoaderStubJS = `var __chunk = function(global, chunks) {
+  chunks = global.__esbuild_chunks || (global.__esbuild_chunks = { exports: {}, promises: {} });
+  if (!chunks.loader && typeof importScripts === "function") {
+    importScripts(chunks.current = new URL("__path", global.location.href).href);
+    chunks.current = void 0;
+  }
+  return chunks.loader();
+}(typeof self !== "undefined" ? self : globalThis);
+`
+
+const chunkLoaderStubMinifiedJS = `var __chunk=function(g,c){return c=g.__esbuild_chunks||(g.__esbuild_chunks={exports:{},promises:{}}),!c.loader&&typeof importScripts=="function"&&(importScripts(c.current=new URL("__path",g.location.href).href),c.current=void 0),c.loader()}(typeof self<"u"?self:globalThis);`
+
+func (c *linkerContext) generateChunkLoader(chunkIndex int, r renamer.Renamer) string {
+	name := r.NameForSymbol(c.chunkLoaderRef)
+	var text string
+	if otherChunkIndex := c.graph.Symbols.Get(c.chunkLoaderRuntimeRef).ChunkIndex.GetIndex(); otherChunkIndex == uint32(chunkIndex) {
+		text = fmt.Sprintf("var %s = %s();\n", name, r.NameForSymbol(c.chunkLoaderRuntimeRef))
+		if c.options.MinifyWhitespace {
+			text = fmt.Sprintf("var %s=%s();", name, r.NameForSymbol(c.chunkLoaderRuntimeRef))
+		}
+	} else {
+		// The path is relative to the current chunk, and is substituted later
+		text = chunkLoaderStubJS
+		if c.options.MinifyWhitespace {
+			text = chunkLoaderStubMinifiedJS
+		}
+		text = strings.Replace(text, "__chunk", name, 1)
+		text = strings.Replace(text, "__path", c.chunks[otherChunkIndex].uniqueKey, 1)
+	}
+	if c.options.MinifyWhitespace {
+		return text
+	}
+	sb := strings.Builder{}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line != "" {
+			sb.WriteString("  ")
+			sb.WriteString(line)
+		}
+	}
+	return sb.String()
+}
+
+// This returns the unique keys of the chunks that are statically imported by
+// the given chunk, transitively, with each chunk after the chunks it imports
+func (c *linkerContext) staticChunkDependencies(chunkIndex uint32, visited map[uint32]bool, keys []string) []string {
+	for _, chunkImport := range c.chunks[chunkIndex].crossChunkImports {
+		if chunkImport.importKind != ast.ImportDynamic && !visited[chunkImport.chunkIndex] {
+			visited[chunkImport.chunkIndex] = true
+			keys = c.staticChunkDependencies(chunkImport.chunkIndex, visited, keys)
+			keys = append(keys, c.chunks[chunkImport.chunkIndex].uniqueKey)
+		}
+	}
+	return keys
+}
+
+// Entry point chunks are named after the entry point and manual chunks are
+// named after the manual chunk. This only matters for readability.
+func (chunk *chunkInfo) nameForNamespace(c *linkerContext) string {
+	if chunk.isEntryPoint {
+		return js_ast.GenerateNonUniqueNameFromPath(c.graph.Files[chunk.sourceIndex].InputFile.Source.KeyPath.Text)
+	}
+	if chunk.manualChunkName != "" {
+		return js_ast.EnsureValidIdentifier(chunk.manualChunkName)
+	}
+	return "chunk"
+}
+
+// This generates "() => value" or "function() { return value }"
+func (c *linkerContext) generateGetter(value js_ast.Expr) js_ast.Expr {
+	body := js_ast.FnBody{Block: js_ast.SBlock{Stmts: []js_ast.Stmt{{Loc: value.Loc, Data: &js_ast.SReturn{ValueOrNil: value}}}}}
+	if c.options.UnsupportedJSFeatures.Has(compat.Arrow) {
+		return js_ast.Expr{Data: &js_ast.EFunction{Fn: js_ast.Fn{Body: body}}}
+	}
+	return js_ast.Expr{Data: &js_ast.EArrow{PreferExpr: true, Body: body}}
+}
+
+// This generates "__chunk.define(value, { a: () => a })", which registers the
+// exports of the current chunk with the chunk loader
+func (c *linkerContext) generateChunkDefine(value js_ast.Expr, getters []js_ast.Property) js_ast.Expr {
+	args := []js_ast.Expr{value}
+	if len(getters) > 0 {
+		args = append(args, js_ast.Expr{Data: &js_ast.EObject{Properties: getters, IsSingleLine: len(getters) == 1}})
+	}
+	return js_ast.Expr{Data: &js_ast.ECall{
+		Kind: js_ast.TargetWasOriginallyPropertyAccess,
+		Target: js_ast.Expr{Data: &js_ast.EDot{
+			Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: c.chunkLoaderRef}},
+			Name:   "define",
+		}},
+		Args: args,
+	}}
 }
 
 type crossChunkImport struct {
@@ -1287,6 +1706,26 @@ func (c *linkerContext) scanImportsAndExports() {
 
 	// Step 1: Figure out what modules must be CommonJS
 	c.timer.Begin("Step 1")
+
+	// Hot module replacement needs every module to be individually wrapped so
+	// that it can be evaluated again later. Turning every module into CommonJS
+	// does this, and also means that imports from other modules are property
+	// accesses instead of bindings, so they see the new exports after an update.
+	if c.options.HotModuleReplacement {
+		for _, sourceIndex := range c.graph.ReachableFiles {
+			if sourceIndex == runtime.SourceIndex {
+				continue
+			}
+			if repr, ok := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr); ok {
+				if !repr.AST.HasLazyExport && (repr.AST.ExportsKind == js_ast.ExportsESM || repr.AST.ExportsKind == js_ast.ExportsESMWithDynamicFallback) {
+					repr.Meta.IsHMRModuleFromESM = true
+				}
+				repr.AST.ExportsKind = js_ast.ExportsCommonJS
+				repr.Meta.Wrap = graph.WrapCJS
+			}
+		}
+	}
+
 	for _, sourceIndex := range c.graph.ReachableFiles {
 		file := &c.graph.Files[sourceIndex]
 		additionalFiles := file.InputFile.AdditionalFiles
@@ -1384,9 +1823,34 @@ func (c *linkerContext) scanImportsAndExports() {
 					continue
 				}
 
+				// Workers are linked separately. Reference the output file for the
+				// worker using a unique key that's replaced with the final path later.
+				if record.Kind == ast.ImportWorker {
+					record.Path.Text = fmt.Sprintf("%sW%08d", c.uniqueKeyPrefix, record.SourceIndex.GetIndex())
+					record.Path.Namespace = ""
+					record.SourceIndex = ast.Index32{}
+					record.Flags |= ast.ShouldNotBeExternalInMetafile | ast.ContainsUniqueKey
+					continue
+				}
+
 				otherFile := &c.graph.Files[record.SourceIndex.GetIndex()]
 				otherRepr := otherFile.InputFile.Repr.(*graph.JSRepr)
 
+				// Inline URLs for files referenced using "new URL()" into the JS file
+				if record.Kind == ast.ImportNewURL {
+					record.Path.Text = otherRepr.AST.URLForCSS
+					record.Path.Namespace = ""
+					record.SourceIndex = ast.Index32{}
+					record.Flags |= ast.ShouldNotBeExternalInMetafile
+					if strings.Contains(otherRepr.AST.URLForCSS, c.uniqueKeyPrefix) {
+						record.Flags |= ast.ContainsUniqueKey
+					}
+
+					// Copy the additional files to the output directory
+					additionalFiles = append(additionalFiles, otherFile.InputFile.AdditionalFiles...)
+					continue
+				}
+
 				switch record.Kind {
 				case ast.ImportStmt:
 					// Importing using ES6 syntax from a file without any ES6 syntax
@@ -1444,7 +1908,7 @@ func (c *linkerContext) scanImportsAndExports() {
 			// resulting wrapper won't be invoked by other files. An exception is made
 			// for entry point files in CommonJS format (or when in pass-through mode).
 			if repr.AST.ExportsKind == js_ast.ExportsCommonJS && (!file.IsEntryPoint() ||
-				c.options.OutputFormat == config.FormatIIFE || c.options.OutputFormat == config.FormatESModule) {
+				c.options.OutputFormat.IsIIFELike() || c.options.OutputFormat.IsESModuleLike()) {
 				repr.Meta.Wrap = graph.WrapCJS
 			}
 		}
@@ -1560,6 +2024,12 @@ func (c *linkerContext) scanImportsAndExports() {
 	// for CommonJS files, and is also necessary for other files if they are
 	// imported using an import star statement.
 	c.timer.Begin("Step 5")
+
+	// Import cycles must be known before constants can be folded in parallel
+	if c.graph.FoldableConsts != nil && c.options.TreeShaking && !c.options.HotModuleReplacement {
+		c.computeImportCycleComponents()
+	}
+
 	waitGroup := sync.WaitGroup{}
 	for _, sourceIndex := range c.graph.ReachableFiles {
 		repr, ok := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr)
@@ -1632,6 +2102,12 @@ func (c *linkerContext) scanImportsAndExports() {
 			// come second after we fill in that array
 			c.createExportsForFile(uint32(sourceIndex))
 
+			// Remove branches that test constants imported from other files. This
+			// must come before cross-part dependencies are determined below.
+			if c.importCycleComponents != nil {
+				c.foldImportedConstants(sourceIndex)
+			}
+
 			// Each part tracks the other parts it depends on within this file
 			localDependencies := make(map[uint32]uint32)
 			parts := repr.AST.Parts
@@ -1755,7 +2231,7 @@ func (c *linkerContext) scanImportsAndExports() {
 		// Pre-generate symbols for re-exports CommonJS symbols in case they
 		// are necessary later. This is done now because the symbols map cannot be
 		// mutated later due to parallelism.
-		if file.IsEntryPoint() && c.options.OutputFormat == config.FormatESModule {
+		if file.IsEntryPoint() && c.options.OutputFormat.IsESModuleLike() {
 			copies := make([]ast.Ref, len(repr.Meta.SortedAndFilteredExportAliases))
 			for i, alias := range repr.Meta.SortedAndFilteredExportAliases {
 				copies[i] = c.graph.GenerateNewSymbol(sourceIndex, ast.SymbolOther, "export_"+alias)
@@ -1788,6 +2264,11 @@ func (c *linkerContext) scanImportsAndExports() {
 			c.graph.GenerateSymbolImportAndUse(sourceIndex, js_ast.NSExportPartIndex, exportRef, 1, runtime.SourceIndex)
 		}
 
+		// Likewise for the "__markAsModule" symbol used by hot module replacement
+		if repr.Meta.IsHMRModuleFromESM {
+			c.graph.GenerateRuntimeSymbolImportAndUse(sourceIndex, js_ast.NSExportPartIndex, "__markAsModule", 1)
+		}
+
 		for importRef, importData := range repr.Meta.ImportsToBind {
 			resolvedRepr := c.graph.Files[importData.SourceIndex].InputFile.Repr.(*graph.JSRepr)
 			partsDeclaringSymbol := resolvedRepr.TopLevelSymbolToParts(importData.Ref)
@@ -1866,6 +2347,11 @@ func (c *linkerContext) scanImportsAndExports() {
 			if repr.Meta.ForceIncludeExportsForEntryPoint {
 				c.graph.GenerateRuntimeSymbolImportAndUse(sourceIndex, entryPointPartIndex, "__toCommonJS", 1)
 			}
+
+			// Pull in the chunk loader if we need it due to code splitting
+			if c.options.CodeSplitting && c.options.OutputFormat == config.FormatIIFE {
+				c.graph.GenerateRuntimeSymbolImportAndUse(sourceIndex, entryPointPartIndex, "__chunkLoader", 1)
+			}
 		}
 
 		// Encode import-specific constraints in the dependency graph
@@ -1878,14 +2364,23 @@ func (c *linkerContext) scanImportsAndExports() {
 			for _, importRecordIndex := range part.ImportRecordIndices {
 				record := &repr.AST.ImportRecords[importRecordIndex]
 
+				// Workers and "new URL()" assets are printed as paths to other output
+				// files, not as imports. Their source index was cleared above but they
+				// aren't external.
+				if record.Kind == ast.ImportWorker || record.Kind == ast.ImportNewURL {
+					continue
+				}
+
 				// Don't follow external imports (this includes import() expressions)
 				if !record.SourceIndex.IsValid() || c.isExternalDynamicImport(record, sourceIndex) {
 					// This is an external import. Check if it will be a "require()" call.
 					if record.Kind == ast.ImportRequire || !c.options.OutputFormat.KeepESMImportExportSyntax() ||
 						(record.Kind == ast.ImportDynamic && c.options.UnsupportedJSFeatures.Has(compat.DynamicImport)) {
 						// We should use "__require" instead of "require" if we're not
-						// generating a CommonJS output file, since it won't exist otherwise
-						if config.ShouldCallRuntimeRequire(c.options.Mode, c.options.OutputFormat) {
+						// generating a CommonJS output file, since it won't exist otherwise.
+						// Other chunks are loaded by the chunk loader in the IIFE format.
+						if config.ShouldCallRuntimeRequire(c.options.Mode, c.options.OutputFormat) &&
+							!(record.SourceIndex.IsValid() && c.options.OutputFormat == config.FormatIIFE) {
 							record.Flags |= ast.CallRuntimeRequire
 							runtimeRequireUses++
 						}
@@ -2280,16 +2775,9 @@ func (c *linkerContext) createExportsForFile(sourceIndex uint32) {
 		}
 
 		// Add a getter property
-		var getter js_ast.Expr
-		body := js_ast.FnBody{Block: js_ast.SBlock{Stmts: []js_ast.Stmt{{Loc: value.Loc, Data: &js_ast.SReturn{ValueOrNil: value}}}}}
-		if c.options.UnsupportedJSFeatures.Has(compat.Arrow) {
-			getter = js_ast.Expr{Data: &js_ast.EFunction{Fn: js_ast.Fn{Body: body}}}
-		} else {
-			getter = js_ast.Expr{Data: &js_ast.EArrow{PreferExpr: true, Body: body}}
-		}
 		properties = append(properties, js_ast.Property{
 			Key:        js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16(alias)}},
-			ValueOrNil: getter,
+			ValueOrNil: c.generateGetter(value),
 		})
 		nsExportSymbolUses[export.Ref] = js_ast.SymbolUse{CountEstimate: 1}
 
@@ -2351,8 +2839,11 @@ func (c *linkerContext) createExportsForFile(sourceIndex uint32) {
 	// instead of by mutating the exports object because other modules in the
 	// bundle (including the entry point module) may do "import * as" to get
 	// access to the exports object and should NOT see the "__esModule" flag.
+	//
+	// With code splitting this is done in the entry point tail instead, since
+	// this part may end up in a different chunk than the entry point.
 	if repr.Meta.ForceIncludeExportsForEntryPoint &&
-		c.options.OutputFormat == config.FormatCommonJS {
+		c.options.OutputFormat == config.FormatCommonJS && !c.options.CodeSplitting {
 
 		runtimeRepr := c.graph.Files[runtime.SourceIndex].InputFile.Repr.(*graph.JSRepr)
 		toCommonJSRef := runtimeRepr.AST.NamedExports["__toCommonJS"].Ref
@@ -2371,6 +2862,21 @@ func (c *linkerContext) createExportsForFile(sourceIndex uint32) {
 		))
 	}
 
+	// Modules converted to CommonJS for hot module replacement are marked with
+	// "__esModule" too. This mutates the exports object instead of wrapping it
+	// because "export * from" statements add to the exports object later on.
+	if repr.Meta.IsHMRModuleFromESM {
+		runtimeRepr := c.graph.Files[runtime.SourceIndex].InputFile.Repr.(*graph.JSRepr)
+		markAsModuleRef := runtimeRepr.AST.NamedExports["__markAsModule"].Ref
+
+		// "__markAsModule(exports);"
+		nsExportStmts = append(nsExportStmts, js_ast.Stmt{Data: &js_ast.SExpr{Value: js_ast.Expr{Data: &js_ast.ECall{
+			Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: markAsModuleRef}},
+			Args:   []js_ast.Expr{{Data: &js_ast.EIdentifier{Ref: repr.AST.ExportsRef}}},
+		}}}})
+		repr.AST.UsesExportsRef = true
+	}
+
 	// No need to generate a part if it'll be empty
 	if len(nsExportStmts) > 0 {
 		// Initialize the part that was allocated for us earlier. The information
@@ -2381,8 +2887,10 @@ func (c *linkerContext) createExportsForFile(sourceIndex uint32) {
 			Dependencies:    nsExportDependencies,
 			DeclaredSymbols: declaredSymbols,
 
-			// This can be removed if nothing uses it
-			CanBeRemovedIfUnused: true,
+			// This can be removed if nothing uses it. Modules that may be replaced
+			// at run time must always have exports since they can't know which
+			// exports their future importers will use.
+			CanBeRemovedIfUnused: !c.options.HotModuleReplacement || sourceIndex == runtime.SourceIndex,
 
 			// Make sure this is trimmed if unused even if tree shaking is disabled
 			ForceTreeShaking: true,
@@ -2696,8 +3204,11 @@ loop:
 			namedImport := trackerFile.InputFile.Repr.(*graph.JSRepr).AST.NamedImports[tracker.importRef]
 			r := js_lexer.RangeOfIdentifier(trackerFile.InputFile.Source, namedImport.AliasLoc)
 
-			// Report mismatched imports and exports
-			if symbol.ImportItemStatus == ast.ImportItemGenerated {
+			// Report mismatched imports and exports. Files that were converted from
+			// CommonJS are treated like CommonJS here, where a missing export is
+			// just undefined at run-time.
+			nextRepr := c.graph.Files[nextTracker.sourceIndex].InputFile.Repr.(*graph.JSRepr)
+			if symbol.ImportItemStatus == ast.ImportItemGenerated || nextRepr.AST.ConvertedFromCommonJS {
 				// This is not an error because although it appears to be a named
 				// import, it's actually an automatically-generated named import
 				// that was originally a property access on an import star
@@ -3064,7 +3575,7 @@ func (c *linkerContext) advanceImportTracker(tracker importTracker) (importTrack
 	otherRepr := c.graph.Files[otherSourceIndex].InputFile.Repr.(*graph.JSRepr)
 	if !namedImport.AliasIsStar && !otherRepr.AST.HasLazyExport &&
 		// CommonJS exports
-		otherRepr.AST.ExportKeyword.Len == 0 && namedImport.Alias != "default" &&
+		otherRepr.AST.ExportKeyword.Len == 0 && !otherRepr.AST.ConvertedFromCommonJS && namedImport.Alias != "default" &&
 		// ESM exports
 		!otherRepr.AST.UsesExportsRef && !otherRepr.AST.UsesModuleRef {
 		// Just warn about it and replace the import with "undefined"
@@ -3142,6 +3653,14 @@ func (c *linkerContext) markFileReachableForCodeSplitting(sourceIndex uint32, en
 	}
 	distanceFromEntryPoint++
 
+	// When modules are preserved, every module is its own entry point. Stop
+	// at other entry points so that each module ends up in its own chunk.
+	if c.options.PreserveModules && distanceFromEntryPoint > 1 && file.IsEntryPoint() {
+		if _, ok := file.InputFile.Repr.(*graph.JSRepr); ok {
+			return
+		}
+	}
+
 	// Don't mark this file more than once
 	if file.EntryBits.HasBit(entryPointBit) && !traverseAgain {
 		return
@@ -3157,7 +3676,7 @@ func (c *linkerContext) markFileReachableForCodeSplitting(sourceIndex uint32, en
 
 		// Traverse into all imported files
 		for _, record := range repr.AST.ImportRecords {
-			if record.SourceIndex.IsValid() && !c.isExternalDynamicImport(&record, sourceIndex) {
+			if record.SourceIndex.IsValid() && !record.Flags.Has(ast.IsUnused) && !c.isExternalDynamicImport(&record, sourceIndex) {
 				c.markFileReachableForCodeSplitting(record.SourceIndex.GetIndex(), entryPointBit, distanceFromEntryPoint)
 			}
 		}
@@ -3909,9 +4428,21 @@ func (c *linkerContext) computeChunks() {
 	}
 
 	// Figure out which JS files are in which chunk
+	var manualChunkNames []string
+	manualChunkFiles := make(map[string][]uint32)
 	for _, sourceIndex := range c.graph.ReachableFiles {
 		if file := &c.graph.Files[sourceIndex]; file.IsLive {
 			if _, ok := file.InputFile.Repr.(*graph.JSRepr); ok {
+				// Files in a manual chunk are grouped by name instead of by which entry
+				// points can reach them
+				if name := c.manualChunkNameForFile(sourceIndex); name != "" {
+					if _, ok := manualChunkFiles[name]; !ok {
+						manualChunkNames = append(manualChunkNames, name)
+					}
+					manualChunkFiles[name] = append(manualChunkFiles[name], sourceIndex)
+					continue
+				}
+
 				key := file.EntryBits.String()
 				chunk, ok := jsChunks[key]
 				if !ok {
@@ -3924,11 +4455,19 @@ func (c *linkerContext) computeChunks() {
 			}
 		}
 	}
+	manualChunks := make(map[string]chunkInfo)
+	for _, name := range manualChunkNames {
+		c.splitManualChunk(manualChunks, name, manualChunkFiles[name])
+	}
+
+	if c.options.MinChunkSize > 0 {
+		c.mergeSmallSharedChunks(jsChunks, manualChunks)
+	}
 
 	// Sort the chunks for determinism. This matters because we use chunk indices
 	// as sorting keys in a few places.
-	sortedChunks := make([]chunkInfo, 0, len(jsChunks)+len(cssChunks))
-	sortedKeys := make([]string, 0, len(jsChunks)+len(cssChunks))
+	sortedChunks := make([]chunkInfo, 0, len(jsChunks)+len(manualChunks)+len(cssChunks))
+	sortedKeys := make([]string, 0, len(jsChunks)+len(manualChunks)+len(cssChunks))
 	for key := range jsChunks {
 		sortedKeys = append(sortedKeys, key)
 	}
@@ -3942,6 +4481,14 @@ func (c *linkerContext) computeChunks() {
 		sortedChunks = append(sortedChunks, chunk)
 	}
 	sortedKeys = sortedKeys[:0]
+	for name := range manualChunks {
+		sortedKeys = append(sortedKeys, name)
+	}
+	sort.Strings(sortedKeys)
+	for _, name := range sortedKeys {
+		sortedChunks = append(sortedChunks, manualChunks[name])
+	}
+	sortedKeys = sortedKeys[:0]
 	for key := range cssChunks {
 		sortedKeys = append(sortedKeys, key)
 	}
@@ -4040,6 +4587,9 @@ func (c *linkerContext) computeChunks() {
 		} else {
 			dir = "/"
 			base = "chunk"
+			if chunk.manualChunkName != "" {
+				base = chunk.manualChunkName
+			}
 			ext = stdExt
 			template = c.options.ChunkPathTemplate
 		}
@@ -4057,42 +4607,320 @@ func (c *linkerContext) computeChunks() {
 	c.chunks = sortedChunks
 }
 
-type chunkOrder struct {
-	sourceIndex uint32
-	distance    uint32
-	tieBreaker  uint32
-}
-
-// This type is just so we can use Go's native sort function
-type chunkOrderArray []chunkOrder
+func (c *linkerContext) manualChunkNameForFile(sourceIndex uint32) string {
+	// Manual chunks are only relevant when code splitting. Entry points and the
+	// runtime always stay where they are.
+	if !c.options.CodeSplitting || sourceIndex == runtime.SourceIndex {
+		return ""
+	}
+	file := &c.graph.Files[sourceIndex]
+	if file.IsEntryPoint() {
+		return ""
+	}
+	prettyPath := file.InputFile.Source.PrettyPath
 
-func (a chunkOrderArray) Len() int          { return len(a) }
-func (a chunkOrderArray) Swap(i int, j int) { a[i], a[j] = a[j], a[i] }
+	if c.options.ManualChunkCallback != nil {
+		if name := c.options.ManualChunkCallback(file.InputFile.Source.KeyPath); name != "" {
+			if !config.IsValidManualChunkName(name) {
+				c.log.AddError(nil, logger.Range{}, fmt.Sprintf("Invalid manual chunk name %q for %q", name, prettyPath))
+				return ""
+			}
+			return name
+		}
+	}
 
-func (a chunkOrderArray) Less(i int, j int) bool {
-	ai := a[i]
-	aj := a[j]
-	return ai.distance < aj.distance || (ai.distance == aj.distance && ai.tieBreaker < aj.tieBreaker)
+	for _, manualChunk := range c.options.ManualChunks {
+		if manualChunk.Matches(prettyPath) {
+			return manualChunk.Name
+		}
+	}
+	return ""
 }
 
-func appendOrExtendPartRange(ranges []partRange, sourceIndex uint32, partIndex uint32) []partRange {
-	if i := len(ranges) - 1; i >= 0 {
-		if r := &ranges[i]; r.sourceIndex == sourceIndex && r.partIndexEnd == partIndex {
-			r.partIndexEnd = partIndex + 1
-			return ranges
+// A manual chunk is loaded by all entry points that can reach any of the files
+// in it, so code in it may run for entry points that don't use it. That's fine
+// for code without side effects but not for code with side effects, since it
+// would then run in places where it didn't run before. Files with side effects
+// that are reachable from a different set of entry points than the whole chunk
+// are split off into a separate chunk for each set of entry points, which has
+// the same name with a numeric suffix.
+func (c *linkerContext) splitManualChunk(manualChunks map[string]chunkInfo, name string, files []uint32) {
+	entryBits := helpers.NewBitSet(uint(len(c.graph.EntryPoints())))
+	for _, sourceIndex := range files {
+		entryBits = entryBits.Union(c.graph.Files[sourceIndex].EntryBits)
+	}
+	key := entryBits.String()
+
+	var splitKeys []string
+	splitChunks := make(map[string]chunkInfo)
+	for _, sourceIndex := range files {
+		fileBits := c.graph.Files[sourceIndex].EntryBits
+		chunkKey := key
+		if fileKey := fileBits.String(); fileKey != key && c.jsFileHasSideEffects(sourceIndex) {
+			chunkKey = fileKey
+		}
+		chunk, ok := splitChunks[chunkKey]
+		if !ok {
+			chunk.entryBits = helpers.NewBitSet(uint(len(c.graph.EntryPoints())))
+			chunk.filesWithPartsInChunk = make(map[uint32]bool)
+			chunk.chunkRepr = &chunkReprJS{}
+			chunk.manualChunkName = name
+			if chunkKey != key {
+				splitKeys = append(splitKeys, chunkKey)
+			}
 		}
+		chunk.entryBits = chunk.entryBits.Union(fileBits)
+		chunk.filesWithPartsInChunk[sourceIndex] = true
+		splitChunks[chunkKey] = chunk
 	}
 
-	return append(ranges, partRange{
-		sourceIndex:    sourceIndex,
-		partIndexBegin: partIndex,
-		partIndexEnd:   partIndex + 1,
-	})
+	if chunk, ok := splitChunks[key]; ok {
+		manualChunks[name] = chunk
+	}
+	sort.Strings(splitKeys)
+	for i, chunkKey := range splitKeys {
+		chunk := splitChunks[chunkKey]
+		chunk.manualChunkName = fmt.Sprintf("%s-%d", name, i+1)
+		manualChunks[fmt.Sprintf("%s\x00%d", name, i+1)] = chunk
+	}
 }
 
-func (c *linkerContext) shouldIncludePart(repr *graph.JSRepr, part js_ast.Part) bool {
-	// As an optimization, ignore parts containing a single import statement to
-	// an internal non-wrapped file. These will be ignored anyway and it's a
+// Code splitting can generate many small shared chunks, one for each unique
+// set of entry points. This merges shared chunks smaller than the minimum size
+// into other shared chunks to reduce the number of requests. A merged chunk is
+// loaded by the union of the entry points that loaded either chunk, so code
+// may run for entry points that didn't need it. To avoid changing behavior,
+// chunks are only merged when the extra code loaded for any entry point has
+// no side effects and when merging doesn't create an import cycle between
+// chunks. Among the valid choices, the merge that loads the fewest additional
+// bytes wins.
+func (c *linkerContext) mergeSmallSharedChunks(jsChunks map[string]chunkInfo, manualChunks map[string]chunkInfo) {
+	type chunkNode struct {
+		key            string
+		deps           map[int]bool
+		size           int
+		mergedInto     int
+		isShared       bool
+		hasSideEffects bool
+	}
+
+	// Number every chunk in a deterministic order. Entry point chunks and
+	// manual chunks are never merged but they still matter for cycle detection.
+	var nodes []chunkNode
+	sortedKeys := make([]string, 0, len(jsChunks))
+	for key := range jsChunks {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		nodes = append(nodes, chunkNode{key: key, isShared: !jsChunks[key].isEntryPoint})
+	}
+	manualStart := len(nodes)
+	sortedKeys = sortedKeys[:0]
+	for name := range manualChunks {
+		sortedKeys = append(sortedKeys, name)
+	}
+	sort.Strings(sortedKeys)
+	for _, name := range sortedKeys {
+		nodes = append(nodes, chunkNode{key: name})
+	}
+	owners := make(map[uint32]int)
+	for i := range nodes {
+		node := &nodes[i]
+		node.mergedInto = i
+		node.deps = make(map[int]bool)
+		chunks := jsChunks
+		if i >= manualStart {
+			chunks = manualChunks
+		}
+		for sourceIndex := range chunks[node.key].filesWithPartsInChunk {
+			owners[sourceIndex] = i
+			node.size += len(c.graph.Files[sourceIndex].InputFile.Source.Contents)
+			if c.jsFileHasSideEffects(sourceIndex) {
+				node.hasSideEffects = true
+			}
+		}
+	}
+
+	// Approximate the chunk import graph using file-level dependencies
+	for sourceIndex, owner := range owners {
+		repr := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr)
+		for _, record := range repr.AST.ImportRecords {
+			if record.SourceIndex.IsValid() && !c.isExternalDynamicImport(&record, sourceIndex) {
+				if other, ok := owners[record.SourceIndex.GetIndex()]; ok && other != owner {
+					nodes[owner].deps[other] = true
+				}
+			}
+		}
+		for _, part := range repr.AST.Parts {
+			if part.IsLive {
+				for _, dependency := range part.Dependencies {
+					if other, ok := owners[dependency.SourceIndex]; ok && other != owner {
+						nodes[owner].deps[other] = true
+					}
+				}
+			}
+		}
+	}
+
+	find := func(i int) int {
+		for nodes[i].mergedInto != i {
+			i = nodes[i].mergedInto
+		}
+		return i
+	}
+
+	// Returns true if "to" can be reached from "from" without going directly
+	// from "from" to "to" (i.e. if merging them would create a cycle)
+	hasIndirectPath := func(from int, to int) bool {
+		visited := make(map[int]bool)
+		var visit func(int) bool
+		visit = func(i int) bool {
+			if i == to {
+				return true
+			}
+			if visited[i] || i == from {
+				return false
+			}
+			visited[i] = true
+			for dep := range nodes[i].deps {
+				if visit(find(dep)) {
+					return true
+				}
+			}
+			return false
+		}
+		for dep := range nodes[from].deps {
+			if dep := find(dep); dep != to && visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+
+	type candidate struct {
+		into int
+		cost int
+	}
+	cannotMerge := make(map[int]bool)
+
+	for {
+		// Find the smallest shared chunk that's still too small
+		from := -1
+		for i := range nodes {
+			if node := &nodes[i]; node.mergedInto == i && node.isShared && !cannotMerge[i] &&
+				node.size < c.options.MinChunkSize && (from == -1 || node.size < nodes[from].size) {
+				from = i
+			}
+		}
+		if from == -1 {
+			break
+		}
+		small := &nodes[from]
+		fromBits := jsChunks[small.key].entryBits
+
+		// Order the other shared chunks by how many extra bytes would be loaded
+		var candidates []candidate
+		for i := range nodes {
+			other := &nodes[i]
+			if i == from || other.mergedInto != i || !other.isShared {
+				continue
+			}
+			intoBits := jsChunks[other.key].entryBits
+			extraForSmall := intoBits.CountBitsNotIn(fromBits)
+			extraForOther := fromBits.CountBitsNotIn(intoBits)
+			if (extraForSmall > 0 && small.hasSideEffects) || (extraForOther > 0 && other.hasSideEffects) {
+				continue
+			}
+			candidates = append(candidates, candidate{into: i, cost: small.size*extraForSmall + other.size*extraForOther})
+		}
+		sort.SliceStable(candidates, func(i int, j int) bool {
+			return candidates[i].cost < candidates[j].cost
+		})
+
+		// Merge into the cheapest chunk that doesn't create a cycle
+		merged := false
+		for _, candidate := range candidates {
+			if hasIndirectPath(from, candidate.into) || hasIndirectPath(candidate.into, from) {
+				continue
+			}
+			into := &nodes[candidate.into]
+			fromChunk := jsChunks[small.key]
+			intoChunk := jsChunks[into.key]
+			for sourceIndex := range fromChunk.filesWithPartsInChunk {
+				intoChunk.filesWithPartsInChunk[sourceIndex] = true
+			}
+			intoChunk.entryBits = intoChunk.entryBits.Union(fromChunk.entryBits)
+			jsChunks[into.key] = intoChunk
+			delete(jsChunks, small.key)
+			for dep := range small.deps {
+				into.deps[dep] = true
+			}
+			delete(into.deps, from)
+			delete(into.deps, candidate.into)
+			into.size += small.size
+			into.hasSideEffects = into.hasSideEffects || small.hasSideEffects
+			small.mergedInto = candidate.into
+			merged = true
+			break
+		}
+		if !merged {
+			cannotMerge[from] = true
+		}
+	}
+}
+
+// Wrapped files are evaluated lazily, so they never have side effects when
+// the chunk containing them is loaded
+func (c *linkerContext) jsFileHasSideEffects(sourceIndex uint32) bool {
+	repr := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr)
+	if repr.Meta.Wrap != graph.WrapNone {
+		return false
+	}
+	for partIndex, part := range repr.AST.Parts {
+		if part.IsLive && !part.CanBeRemovedIfUnused && uint32(partIndex) != js_ast.NSExportPartIndex {
+			return true
+		}
+	}
+	return false
+}
+
+type chunkOrder struct {
+	sourceIndex uint32
+	distance    uint32
+	tieBreaker  uint32
+}
+
+// This type is just so we can use Go's native sort function
+type chunkOrderArray []chunkOrder
+
+func (a chunkOrderArray) Len() int          { return len(a) }
+func (a chunkOrderArray) Swap(i int, j int) { a[i], a[j] = a[j], a[i] }
+
+func (a chunkOrderArray) Less(i int, j int) bool {
+	ai := a[i]
+	aj := a[j]
+	return ai.distance < aj.distance || (ai.distance == aj.distance && ai.tieBreaker < aj.tieBreaker)
+}
+
+func appendOrExtendPartRange(ranges []partRange, sourceIndex uint32, partIndex uint32) []partRange {
+	if i := len(ranges) - 1; i >= 0 {
+		if r := &ranges[i]; r.sourceIndex == sourceIndex && r.partIndexEnd == partIndex {
+			r.partIndexEnd = partIndex + 1
+			return ranges
+		}
+	}
+
+	return append(ranges, partRange{
+		sourceIndex:    sourceIndex,
+		partIndexBegin: partIndex,
+		partIndexEnd:   partIndex + 1,
+	})
+}
+
+func (c *linkerContext) shouldIncludePart(repr *graph.JSRepr, part js_ast.Part) bool {
+	// As an optimization, ignore parts containing a single import statement to
+	// an internal non-wrapped file. These will be ignored anyway and it's a
 	// performance hit to spin up a goroutine only to discover this later.
 	if len(part.Stmts) == 1 {
 		if s, ok := part.Stmts[0].Data.(*js_ast.SImport); ok {
@@ -4138,7 +4966,7 @@ func (c *linkerContext) findImportedPartsInJSOrder(chunk *chunkInfo) (js []uint3
 		file := &c.graph.Files[sourceIndex]
 
 		if repr, ok := file.InputFile.Repr.(*graph.JSRepr); ok {
-			isFileInThisChunk := chunk.entryBits.Equals(file.EntryBits)
+			isFileInThisChunk := chunk.filesWithPartsInChunk[sourceIndex]
 
 			// Wrapped files can't be split because they are all inside the wrapper
 			canFileBeSplit := repr.Meta.Wrap == graph.WrapNone
@@ -4576,6 +5404,10 @@ type stmtList struct {
 type compileResultJS struct {
 	js_printer.PrintResult
 
+	// For the SystemJS format. Top-level import statements are removed from the
+	// code and become dependencies of the "System.register" call instead.
+	systemJSImports []systemJSImport
+
 	sourceIndex uint32
 
 	// This is the line and column offset since the previous JavaScript string
@@ -4598,6 +5430,7 @@ func (c *linkerContext) requireOrImportMetaForSource(sourceIndex uint32) (meta j
 func (c *linkerContext) generateCodeForFileInChunkJS(
 	r renamer.Renamer,
 	waitGroup *sync.WaitGroup,
+	chunkRepr *chunkReprJS,
 	partRange partRange,
 	toCommonJSRef ast.Ref,
 	toESMRef ast.Ref,
@@ -4759,7 +5592,7 @@ func (c *linkerContext) generateCodeForFileInChunkJS(
 			}
 
 			var cjsArgs []js_ast.Expr
-			if c.options.ProfilerNames {
+			if c.options.ProfilerNames || c.options.HotModuleReplacement {
 				// "__commonJS({ 'file.js'(exports, module) { ... } })"
 				var flags js_ast.PropertyFlags
 				if !c.options.UnsupportedJSFeatures.Has(compat.ObjectExtensions) {
@@ -4882,11 +5715,8 @@ func (c *linkerContext) generateCodeForFileInChunkJS(
 		lineOffsetTables = dataForSourceMaps[partRange.sourceIndex].LineOffsetTables
 	}
 
-	// Indent the file if everything is wrapped in an IIFE
-	indent := 0
-	if c.options.OutputFormat == config.FormatIIFE {
-		indent++
-	}
+	// Indent the file if everything is wrapped in a function
+	indent := c.indentForOutputFormat()
 
 	// Convert the AST to JavaScript code
 	printOptions := js_printer.Options{
@@ -4911,13 +5741,24 @@ func (c *linkerContext) generateCodeForFileInChunkJS(
 		RequireOrImportMetaForSource: c.requireOrImportMetaForSource,
 		MangledProps:                 c.mangledProps,
 		NeedsMetafile:                c.options.NeedsMetafile,
+		CrossChunkAliases:            chunkRepr.crossChunkAliases,
+		ChunkLoaderPaths:             chunkRepr.chunkLoaderPaths,
+		ChunkLoaderRef:               c.chunkLoaderRef,
+		SystemJSExports:              chunkRepr.systemJSExports,
+		SystemJSExportsRef:           c.unboundExportsRef,
+		SystemJSModuleRef:            c.unboundModuleRef,
+	}
+	var systemJSImports []systemJSImport
+	if c.options.OutputFormat == config.FormatSystemJS {
+		stmts = c.convertStmtsForSystemJS(stmts, repr.AST.ImportRecords, &systemJSImports)
 	}
 	tree := repr.AST
 	tree.Directives = nil // This is handled elsewhere
 	tree.Parts = []js_ast.Part{{Stmts: stmts}}
 	*result = compileResultJS{
-		PrintResult: js_printer.Print(tree, c.graph.Symbols, r, printOptions),
-		sourceIndex: partRange.sourceIndex,
+		PrintResult:     js_printer.Print(tree, c.graph.Symbols, r, printOptions),
+		systemJSImports: systemJSImports,
+		sourceIndex:     partRange.sourceIndex,
 	}
 
 	if file.InputFile.Loader == config.LoaderFile {
@@ -4930,6 +5771,7 @@ func (c *linkerContext) generateCodeForFileInChunkJS(
 
 func (c *linkerContext) generateEntryPointTailJS(
 	r renamer.Renamer,
+	chunkRepr *chunkReprJS,
 	toCommonJSRef ast.Ref,
 	toESMRef ast.Ref,
 	sourceIndex uint32,
@@ -4948,9 +5790,48 @@ func (c *linkerContext) generateEntryPointTailJS(
 			}}}})
 		}
 
-	case config.FormatIIFE:
-		if repr.Meta.Wrap == graph.WrapCJS {
-			if len(c.options.GlobalName) > 0 {
+	case config.FormatIIFE, config.FormatAMD, config.FormatUMD:
+		if c.options.CodeSplitting {
+			// With code splitting, the exports of the entry point are registered
+			// with the chunk loader so that other chunks can import them
+			var value js_ast.Expr
+			if repr.Meta.Wrap == graph.WrapCJS {
+				// "require_foo()"
+				value = js_ast.Expr{Data: &js_ast.ECall{
+					Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: repr.AST.WrapperRef}},
+				}}
+			} else {
+				if repr.Meta.Wrap == graph.WrapESM {
+					// "init_foo();"
+					stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SExpr{Value: js_ast.Expr{Data: &js_ast.ECall{
+						Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: repr.AST.WrapperRef}},
+					}}}})
+				}
+
+				if repr.Meta.ForceIncludeExportsForEntryPoint {
+					// "__toCommonJS(exports)"
+					value = js_ast.Expr{Data: &js_ast.ECall{
+						Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: toCommonJSRef}},
+						Args:   []js_ast.Expr{{Data: &js_ast.EIdentifier{Ref: repr.AST.ExportsRef}}},
+					}}
+				}
+			}
+
+			// "__chunk.define(__toCommonJS(exports), { a: () => a })"
+			if getters := chunkRepr.crossChunkExportGetters; value.Data != nil || len(getters) > 0 {
+				if value.Data == nil {
+					value = js_ast.Expr{Data: &js_ast.EObject{}}
+				}
+				value = c.generateChunkDefine(value, getters)
+				if len(c.options.GlobalName) > 0 && file.IsUserSpecifiedEntryPoint() {
+					stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SReturn{ValueOrNil: value}})
+				} else {
+					stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SExpr{Value: value}})
+				}
+			}
+		} else if repr.Meta.Wrap == graph.WrapCJS {
+			// The AMD and UMD formats always return the exports from the factory
+			if len(c.options.GlobalName) > 0 || c.options.OutputFormat != config.FormatIIFE {
 				// "return require_foo();"
 				stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SReturn{ValueOrNil: js_ast.Expr{Data: &js_ast.ECall{
 					Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: repr.AST.WrapperRef}},
@@ -4999,6 +5880,20 @@ func (c *linkerContext) generateEntryPointTailJS(
 					Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: repr.AST.WrapperRef}},
 				}}}})
 			}
+
+			if c.options.CodeSplitting && repr.Meta.ForceIncludeExportsForEntryPoint {
+				// "module.exports = __toCommonJS(exports);"
+				stmts = append(stmts, js_ast.AssignStmt(
+					js_ast.Expr{Data: &js_ast.EDot{
+						Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: c.unboundModuleRef}},
+						Name:   "exports",
+					}},
+					js_ast.Expr{Data: &js_ast.ECall{
+						Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: toCommonJSRef}},
+						Args:   []js_ast.Expr{{Data: &js_ast.EIdentifier{Ref: repr.AST.ExportsRef}}},
+					}},
+				))
+			}
 		}
 
 		// If we are generating CommonJS for node, encode the known export names in
@@ -5086,8 +5981,13 @@ func (c *linkerContext) generateEntryPointTailJS(
 			}
 		}
 
-	case config.FormatESModule:
-		if repr.Meta.Wrap == graph.WrapCJS {
+	case config.FormatESModule, config.FormatSystemJS:
+		if repr.Meta.IsHMRModuleFromESM && len(repr.Meta.SortedAndFilteredExportAliases) == 0 {
+			// "require_foo();"
+			stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SExpr{Value: js_ast.Expr{Data: &js_ast.ECall{
+				Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: repr.AST.WrapperRef}},
+			}}}})
+		} else if repr.Meta.Wrap == graph.WrapCJS {
 			// "export default require_foo();"
 			stmts = append(stmts, js_ast.Stmt{
 				Data: &js_ast.SExportDefault{Value: js_ast.Stmt{
@@ -5215,6 +6115,10 @@ func (c *linkerContext) generateEntryPointTailJS(
 				stmts = append(stmts, js_ast.Stmt{Data: &js_ast.SExportClause{Items: items}})
 			}
 		}
+
+		if c.options.OutputFormat == config.FormatSystemJS {
+			stmts = c.convertStmtsForSystemJS(stmts, nil, nil)
+		}
 	}
 
 	if len(stmts) == 0 {
@@ -5225,11 +6129,8 @@ func (c *linkerContext) generateEntryPointTailJS(
 	tree.Directives = nil
 	tree.Parts = []js_ast.Part{{Stmts: stmts}}
 
-	// Indent the file if everything is wrapped in an IIFE
-	indent := 0
-	if c.options.OutputFormat == config.FormatIIFE {
-		indent++
-	}
+	// Indent the file if everything is wrapped in a function
+	indent := c.indentForOutputFormat()
 
 	// Convert the AST to JavaScript code
 	printOptions := js_printer.Options{
@@ -5246,6 +6147,7 @@ func (c *linkerContext) generateEntryPointTailJS(
 		UnsupportedFeatures:          c.options.UnsupportedJSFeatures,
 		RequireOrImportMetaForSource: c.requireOrImportMetaForSource,
 		MangledProps:                 c.mangledProps,
+		CrossChunkAliases:            chunkRepr.crossChunkAliases,
 	}
 	result.PrintResult = js_printer.Print(tree, c.graph.Symbols, r, printOptions)
 	return
@@ -5286,15 +6188,54 @@ func (c *linkerContext) renameSymbolsInChunk(chunk *chunkInfo, filesInOrder []ui
 		reservedNames["require"] = 1
 		reservedNames["Promise"] = 1
 	}
+
+	// These are used by the code that SystemJS modules are wrapped in. Nested
+	// names must also avoid "exports" because assignments to exported variables
+	// anywhere in the module are rewritten to call it.
+	if c.options.OutputFormat == config.FormatSystemJS {
+		reservedNames["Object"] = 1
+		reservedNames["System"] = 1
+		reservedNames["exports"] = 1
+		reservedNames["module"] = 1
+	}
+
+	// These are used to link chunks together when code splitting
+	if c.options.CodeSplitting {
+		switch c.options.OutputFormat {
+		case config.FormatCommonJS:
+			reservedNames["Object"] = 1
+		case config.FormatIIFE:
+			for _, name := range []string{"URL", "globalThis", "importScripts", "self"} {
+				reservedNames[name] = 1
+			}
+		}
+	}
 	timer.End("Compute reserved names")
 
-	// Make sure imports get a chance to be renamed too
+	// Make sure imports get a chance to be renamed too. Formats other than ESM
+	// access imports through a namespace object, so that's what gets renamed.
 	var sortedImportsFromOtherChunks stableRefArray
-	for _, imports := range chunk.chunkRepr.(*chunkReprJS).importsFromOtherChunks {
-		for _, item := range imports {
+	chunkRepr := chunk.chunkRepr.(*chunkReprJS)
+	if c.options.OutputFormat.IsESModuleLike() {
+		for _, imports := range chunkRepr.importsFromOtherChunks {
+			for _, item := range imports {
+				sortedImportsFromOtherChunks = append(sortedImportsFromOtherChunks, stableRef{
+					StableSourceIndex: c.graph.StableSourceIndices[item.ref.SourceIndex],
+					Ref:               item.ref,
+				})
+			}
+		}
+	} else {
+		for _, ref := range chunkRepr.crossChunkNamespaceRefs {
+			sortedImportsFromOtherChunks = append(sortedImportsFromOtherChunks, stableRef{
+				StableSourceIndex: c.graph.StableSourceIndices[ref.SourceIndex],
+				Ref:               ref,
+			})
+		}
+		if c.options.CodeSplitting && c.options.OutputFormat == config.FormatIIFE {
 			sortedImportsFromOtherChunks = append(sortedImportsFromOtherChunks, stableRef{
-				StableSourceIndex: c.graph.StableSourceIndices[item.ref.SourceIndex],
-				Ref:               item.ref,
+				StableSourceIndex: c.graph.StableSourceIndices[c.chunkLoaderRef.SourceIndex],
+				Ref:               c.chunkLoaderRef,
 			})
 		}
 	}
@@ -5530,6 +6471,11 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 	// never change the "../" count.
 	chunkAbsDir := c.fs.Dir(c.fs.Join(c.options.AbsOutputDir, config.TemplateToString(chunk.finalTemplate)))
 
+	// Assignments to exported symbols update the exported values in SystemJS
+	if c.options.OutputFormat == config.FormatSystemJS {
+		chunkRepr.systemJSExports = c.systemJSExportsForChunk(chunk, chunkRepr)
+	}
+
 	// Generate JavaScript for each file in parallel
 	timer.Begin("Print JavaScript files")
 	waitGroup := sync.WaitGroup{}
@@ -5546,6 +6492,7 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 		go c.generateCodeForFileInChunkJS(
 			r,
 			&waitGroup,
+			chunkRepr,
 			partRange,
 			toCommonJSRef,
 			toESMRef,
@@ -5558,13 +6505,11 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 	// Also generate the cross-chunk binding code
 	var crossChunkPrefix []byte
 	var crossChunkSuffix []byte
+	var crossChunkSystemJSImports []systemJSImport
 	var jsonMetadataImports []string
 	{
-		// Indent the file if everything is wrapped in an IIFE
-		indent := 0
-		if c.options.OutputFormat == config.FormatIIFE {
-			indent++
-		}
+		// Indent the file if everything is wrapped in a function
+		indent := c.indentForOutputFormat()
 		printOptions := js_printer.Options{
 			Indent:            indent,
 			OutputFormat:      c.options.OutputFormat,
@@ -5573,23 +6518,36 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 			MinifySyntax:      c.options.MinifySyntax,
 			LineLimit:         c.options.LineLimit,
 			NeedsMetafile:     c.options.NeedsMetafile,
+			CrossChunkAliases: chunkRepr.crossChunkAliases,
+		}
+		flags := ast.ShouldNotBeExternalInMetafile | ast.ContainsUniqueKey
+		if c.options.OutputFormat == config.FormatIIFE {
+			// Other chunks are imported by calling the chunk loader instead of "require"
+			printOptions.RuntimeRequireRef = c.chunkLoaderRef
+			flags |= ast.CallRuntimeRequire
 		}
 		crossChunkImportRecords := make([]ast.ImportRecord, len(chunk.crossChunkImports))
 		for i, chunkImport := range chunk.crossChunkImports {
 			crossChunkImportRecords[i] = ast.ImportRecord{
 				Kind:  chunkImport.importKind,
 				Path:  logger.Path{Text: c.chunks[chunkImport.chunkIndex].uniqueKey},
-				Flags: ast.ShouldNotBeExternalInMetafile | ast.ContainsUniqueKey,
+				Flags: flags,
 			}
 		}
+		prefixStmts := chunkRepr.crossChunkPrefixStmts
+		suffixStmts := chunkRepr.crossChunkSuffixStmts
+		if c.options.OutputFormat == config.FormatSystemJS {
+			prefixStmts = c.convertStmtsForSystemJS(prefixStmts, crossChunkImportRecords, &crossChunkSystemJSImports)
+			suffixStmts = c.convertStmtsForSystemJS(suffixStmts, nil, nil)
+		}
 		crossChunkResult := js_printer.Print(js_ast.AST{
 			ImportRecords: crossChunkImportRecords,
-			Parts:         []js_ast.Part{{Stmts: chunkRepr.crossChunkPrefixStmts}},
+			Parts:         []js_ast.Part{{Stmts: prefixStmts}},
 		}, c.graph.Symbols, r, printOptions)
 		crossChunkPrefix = crossChunkResult.JS
 		jsonMetadataImports = crossChunkResult.JSONMetadataImports
 		crossChunkSuffix = js_printer.Print(js_ast.AST{
-			Parts: []js_ast.Part{{Stmts: chunkRepr.crossChunkSuffixStmts}},
+			Parts: []js_ast.Part{{Stmts: suffixStmts}},
 		}, c.graph.Symbols, r, printOptions).JS
 	}
 
@@ -5598,6 +6556,7 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 	if chunk.isEntryPoint {
 		entryPointTail = c.generateEntryPointTailJS(
 			r,
+			chunkRepr,
 			toCommonJSRef,
 			toESMRef,
 			chunk.sourceIndex,
@@ -5650,7 +6609,7 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 	if chunk.isEntryPoint {
 		repr := c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr)
 		for _, directive := range repr.AST.Directives {
-			if directive != "use strict" || c.options.OutputFormat != config.FormatESModule {
+			if directive != "use strict" || !c.options.OutputFormat.IsESModuleLike() {
 				quoted := string(helpers.QuoteForJSON(directive, c.options.ASCIIOnly)) + ";" + newline
 				prevOffset.AdvanceString(quoted)
 				j.AddString(quoted)
@@ -5659,11 +6618,14 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 		}
 	}
 
-	// Optionally wrap with an IIFE
-	if c.options.OutputFormat == config.FormatIIFE {
+	// Optionally wrap with an IIFE or with the code for another module format
+	switch c.options.OutputFormat {
+	case config.FormatIIFE:
 		var text string
 		indent = "  "
-		if len(c.options.GlobalName) > 0 {
+		if len(c.options.GlobalName) > 0 && (!c.options.CodeSplitting ||
+			(chunk.isEntryPoint && c.graph.Files[chunk.sourceIndex].IsUserSpecifiedEntryPoint())) {
+			// Other chunks are accessed through the chunk loader when code splitting
 			text = c.generateGlobalNamePrefix()
 		}
 		if c.options.UnsupportedJSFeatures.Has(compat.Arrow) {
@@ -5674,6 +6636,33 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 		prevOffset.AdvanceString(text)
 		j.AddString(text)
 		newlineBeforeComment = false
+
+		// Every chunk starts off by creating the chunk loader when code splitting
+		if c.options.CodeSplitting {
+			text := c.generateChunkLoader(chunkIndex, r)
+			prevOffset.AdvanceString(text)
+			j.AddString(text)
+			newlineBeforeComment = true
+		}
+
+	case config.FormatAMD, config.FormatUMD:
+		indent = "  "
+		text := c.generateFactoryPrefix(c.externalDependenciesForChunk(chunkRepr))
+		prevOffset.AdvanceString(text)
+		j.AddString(text)
+		newlineBeforeComment = false
+
+	case config.FormatSystemJS:
+		indent = "      "
+		imports := crossChunkSystemJSImports
+		for _, compileResult := range compileResults {
+			imports = append(imports, compileResult.systemJSImports...)
+		}
+		text, jsonImports := c.generateSystemJSPrefix(r, chunk, chunkRepr, imports)
+		jsonMetadataImports = append(jsonImports, jsonMetadataImports...)
+		prevOffset.AdvanceString(text)
+		j.AddString(text)
+		newlineBeforeComment = false
 	}
 
 	// Put the cross-chunk prefix inside the IIFE
@@ -5839,6 +6828,19 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 		if len(compileResult.JS) > 0 {
 			newlineBeforeComment = true
 		}
+
+		// Remember what each module looks like for hot module replacement. The
+		// random unique key prefix is removed first so that the hash is stable
+		// across builds.
+		if c.options.HotModuleReplacement && !c.graph.Files[compileResult.sourceIndex].InputFile.OmitFromSourceMapsAndMetafile {
+			if chunk.hmrModuleHashes == nil {
+				chunk.hmrModuleHashes = make(map[string]string)
+			}
+			hash := xxhash.New()
+			hash.Write(bytes.ReplaceAll(compileResult.JS, []byte(c.uniqueKeyPrefix), nil))
+			path := c.graph.Files[compileResult.sourceIndex].InputFile.Source.PrettyPath
+			chunk.hmrModuleHashes[path] = strconv.FormatUint(hash.Sum64(), 16)
+		}
 	}
 
 	// Stick the entry point tail at the end of the file. Deliberately don't
@@ -5854,9 +6856,20 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 		j.AddBytes(crossChunkSuffix)
 	}
 
-	// Optionally wrap with an IIFE
-	if c.options.OutputFormat == config.FormatIIFE {
+	// Optionally wrap with an IIFE or with the code for another module format
+	switch c.options.OutputFormat {
+	case config.FormatIIFE:
 		j.AddString("})();" + newline)
+
+	case config.FormatAMD, config.FormatUMD:
+		j.AddString("});" + newline)
+
+	case config.FormatSystemJS:
+		if c.options.MinifyWhitespace {
+			j.AddString("}}});")
+		} else {
+			j.AddString("    }\n  };\n});\n")
+		}
 	}
 
 	// Make sure the file ends with a newline
@@ -5905,9 +6918,9 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 				for _, output := range pieces[i] {
 					count += c.accurateFinalByteCount(output, finalRelDir)
 				}
-				jMeta.AddString(fmt.Sprintf("\n        %s: {\n          \"bytesInOutput\": %d\n        %s}",
+				jMeta.AddString(fmt.Sprintf("\n        %s: {\n          \"bytesInOutput\": %d%s\n        %s}",
 					helpers.QuoteForJSON(c.graph.Files[sourceIndex].InputFile.Source.PrettyPath, c.options.ASCIIOnly),
-					count, c.generateExtraDataForFileJS(sourceIndex)))
+					count, c.metafileWhy[sourceIndex], c.generateExtraDataForFileJS(sourceIndex)))
 			}
 			if len(metaOrder) > 0 {
 				jMeta.AddString("\n      ")
@@ -5922,6 +6935,18 @@ func (c *linkerContext) generateChunkJS(chunkIndex int, chunkWaitGroup *sync.Wai
 	chunkWaitGroup.Done()
 }
 
+// SystemJS code is nested inside the "execute" function, which is inside the
+// object returned by the function that's passed to "System.register"
+func (c *linkerContext) indentForOutputFormat() int {
+	if c.options.OutputFormat.IsIIFELike() {
+		return 1
+	}
+	if c.options.OutputFormat == config.FormatSystemJS {
+		return 3
+	}
+	return 0
+}
+
 func (c *linkerContext) generateGlobalNamePrefix() string {
 	var text string
 	globalName := c.options.GlobalName
@@ -5985,6 +7010,446 @@ func (c *linkerContext) generateGlobalNamePrefix() string {
 	return text
 }
 
+type externalDependency struct {
+	path        string
+	sourceIndex uint32
+	importRange logger.Range
+}
+
+// The AMD and UMD formats need to list external modules as dependencies so
+// that they are loaded before the factory function calls "require()" on them
+func (c *linkerContext) externalDependenciesForChunk(chunkRepr *chunkReprJS) []externalDependency {
+	var deps []externalDependency
+	visited := make(map[string]bool)
+	for _, partRange := range chunkRepr.partsInChunkInOrder {
+		repr := c.graph.Files[partRange.sourceIndex].InputFile.Repr.(*graph.JSRepr)
+		for partIndex := partRange.partIndexBegin; partIndex < partRange.partIndexEnd; partIndex++ {
+			part := &repr.AST.Parts[partIndex]
+			if !part.IsLive {
+				continue
+			}
+			for _, importRecordIndex := range part.ImportRecordIndices {
+				record := &repr.AST.ImportRecords[importRecordIndex]
+
+				// Optional dependencies inside "try" blocks are not listed because
+				// the module would then fail to load if they are missing
+				if record.SourceIndex.IsValid() || record.CopySourceIndex.IsValid() ||
+					record.Flags.Has(ast.IsUnused) || record.Flags.Has(ast.HandlesImportErrors) ||
+					(record.Kind != ast.ImportStmt && record.Kind != ast.ImportRequire) {
+					continue
+				}
+				if !visited[record.Path.Text] {
+					visited[record.Path.Text] = true
+					deps = append(deps, externalDependency{
+						path:        record.Path.Text,
+						sourceIndex: partRange.sourceIndex,
+						importRange: record.Range,
+					})
+				}
+			}
+		}
+	}
+	return deps
+}
+
+func (c *linkerContext) generateFactoryPrefix(deps []externalDependency) string {
+	space := " "
+	if c.options.MinifyWhitespace {
+		space = ""
+	}
+
+	// "["require", "foo"]"
+	depsArray := strings.Builder{}
+	depsArray.WriteString("[\"require\"")
+	for _, dep := range deps {
+		depsArray.WriteString(",")
+		depsArray.WriteString(space)
+		depsArray.Write(helpers.QuoteForJSON(dep.path, c.options.ASCIIOnly))
+	}
+	depsArray.WriteString("]")
+
+	if c.options.OutputFormat == config.FormatAMD {
+		if c.options.MinifyWhitespace {
+			return fmt.Sprintf("define(%s,function(require){", depsArray.String())
+		}
+		return fmt.Sprintf("define(%s, function(require) {\n", depsArray.String())
+	}
+
+	// Without a global name, the global fallback just runs the code
+	global := fmt.Sprintf("factory(%s)", c.generateUMDGlobalRequire(deps))
+	if len(c.options.GlobalName) > 0 {
+		global = fmt.Sprintf("%s%s=%s%s", c.generateUMDGlobalName(c.options.GlobalName), space, space, global)
+	}
+	if c.options.MinifyWhitespace {
+		return fmt.Sprintf("(function(root,factory){"+
+			"if(typeof define==\"function\"&&define.amd)define(%s,factory);"+
+			"else if(typeof module==\"object\"&&module.exports)module.exports=factory(require);"+
+			"else %s"+
+			"})(typeof self<\"u\"?self:this,function(require){", depsArray.String(), global)
+	}
+	return fmt.Sprintf("(function(root, factory) {\n"+
+		"  if (typeof define === \"function\" && define.amd) define(%s, factory);\n"+
+		"  else if (typeof module === \"object\" && module.exports) module.exports = factory(require);\n"+
+		"  else %s;\n"+
+		"})(typeof self !== \"undefined\" ? self : this, function(require) {\n", depsArray.String(), global)
+}
+
+// There's no module loader in the global fallback of the UMD format, so
+// external modules are read from global variables instead. This generates
+// "function(id) { return { "foo": root.Foo }[id]; }" or an empty string if
+// there are no external modules.
+func (c *linkerContext) generateUMDGlobalRequire(deps []externalDependency) string {
+	if len(deps) == 0 {
+		return ""
+	}
+	space := " "
+	if c.options.MinifyWhitespace {
+		space = ""
+	}
+	sb := strings.Builder{}
+	sb.WriteString("function(id)" + space + "{" + space + "return" + space + "{" + space)
+	for i, dep := range deps {
+		globalName := c.options.UMDGlobals[dep.path]
+		if globalName == nil {
+			file := &c.graph.Files[dep.sourceIndex].InputFile
+			tracker := logger.MakeLineColumnTracker(&file.Source)
+			c.log.AddErrorWithNotes(&tracker, dep.importRange,
+				fmt.Sprintf("No global variable is configured for the external module %q", dep.path),
+				[]logger.MsgData{{Text: fmt.Sprintf("The \"umd\" format reads external modules from global variables "+
+					"when there is no module loader. You can use \"--umd-global:%s=Name\" to specify the global variable to use.", dep.path)}})
+		}
+		if i > 0 {
+			sb.WriteString("," + space)
+		}
+		sb.Write(helpers.QuoteForJSON(dep.path, c.options.ASCIIOnly))
+		sb.WriteString(":" + space + "root")
+		for _, name := range globalName {
+			sb.WriteString(c.propertyAccessText(name))
+		}
+	}
+	if c.options.MinifyWhitespace {
+		sb.WriteString("}[id]}")
+	} else {
+		sb.WriteString(" }[id]; }")
+	}
+	return sb.String()
+}
+
+// This generates "(root.a = root.a || {}).b" for the global name "a.b"
+func (c *linkerContext) generateUMDGlobalName(globalName []string) string {
+	space := " "
+	if c.options.MinifyWhitespace {
+		space = ""
+	}
+	target := "root"
+	path := "root"
+	for _, name := range globalName[:len(globalName)-1] {
+		path += c.propertyAccessText(name)
+		target = fmt.Sprintf("(%s%s%s=%s%s%s||%s{})", target, c.propertyAccessText(name), space, space, path, space, space)
+	}
+	return target + c.propertyAccessText(globalName[len(globalName)-1])
+}
+
+// This generates ".name" or "["name"]" depending on whether "name" is a valid identifier
+func (c *linkerContext) propertyAccessText(name string) string {
+	if js_printer.CanEscapeIdentifier(name, c.options.UnsupportedJSFeatures, c.options.ASCIIOnly) {
+		if c.options.ASCIIOnly {
+			name = string(js_printer.QuoteIdentifier(nil, name, c.options.UnsupportedJSFeatures))
+		}
+		return "." + name
+	}
+	return fmt.Sprintf("[%s]", helpers.QuoteForJSON(name, c.options.ASCIIOnly))
+}
+
+// This is a top-level "import" or "export * from" statement that has been
+// removed from a SystemJS module. The imported values are assigned to the
+// local variables in the setter function for that dependency instead.
+type systemJSImport struct {
+	record       ast.ImportRecord
+	namespaceRef ast.Ref
+	items        []js_ast.ClauseItem
+	isExportStar bool
+}
+
+// SystemJS modules can't contain "import" or "export" statements, so the ESM
+// code generated by the linker is converted here. Top-level imports are moved
+// into "imports" and exports become calls to the "exports" function.
+func (c *linkerContext) convertStmtsForSystemJS(stmts []js_ast.Stmt, importRecords []ast.ImportRecord, imports *[]systemJSImport) []js_ast.Stmt {
+	result := make([]js_ast.Stmt, 0, len(stmts))
+
+	for _, stmt := range stmts {
+		switch s := stmt.Data.(type) {
+		case *js_ast.SImport:
+			imp := systemJSImport{record: importRecords[s.ImportRecordIndex], namespaceRef: ast.InvalidRef}
+			if s.StarNameLoc != nil {
+				imp.namespaceRef = s.NamespaceRef
+			}
+			if s.DefaultName != nil {
+				imp.items = append(imp.items, js_ast.ClauseItem{Alias: "default", Name: *s.DefaultName})
+			}
+			if s.Items != nil {
+				imp.items = append(imp.items, *s.Items...)
+			}
+			*imports = append(*imports, imp)
+			continue
+
+		case *js_ast.SExportStar:
+			// "export * from 'path'"
+			if s.Alias == nil {
+				*imports = append(*imports, systemJSImport{
+					record:       importRecords[s.ImportRecordIndex],
+					namespaceRef: ast.InvalidRef,
+					isExportStar: true,
+				})
+				continue
+			}
+
+		case *js_ast.SExportClause:
+			// "exports('a', a);"
+			// "exports({ a: a, b: b });"
+			if len(s.Items) == 1 {
+				item := s.Items[0]
+				stmt = c.systemJSExportsCall(
+					js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16(item.Alias)}},
+					js_ast.Expr{Data: &js_ast.EIdentifier{Ref: item.Name.Ref}},
+				)
+			} else if len(s.Items) > 1 {
+				properties := make([]js_ast.Property, len(s.Items))
+				for i, item := range s.Items {
+					properties[i] = js_ast.Property{
+						Key:        js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16(item.Alias)}},
+						ValueOrNil: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: item.Name.Ref}},
+					}
+				}
+				stmt = c.systemJSExportsCall(js_ast.Expr{Data: &js_ast.EObject{Properties: properties, IsSingleLine: s.IsSingleLine}})
+			} else {
+				continue
+			}
+
+		case *js_ast.SExportDefault:
+			// "exports('default', require_foo());"
+			if expr, ok := s.Value.Data.(*js_ast.SExpr); ok {
+				stmt = c.systemJSExportsCall(
+					js_ast.Expr{Data: &js_ast.EString{Value: helpers.StringToUTF16("default")}},
+					expr.Value,
+				)
+			}
+		}
+
+		result = append(result, stmt)
+	}
+
+	return result
+}
+
+func (c *linkerContext) systemJSExportsCall(args ...js_ast.Expr) js_ast.Stmt {
+	return js_ast.Stmt{Data: &js_ast.SExpr{Value: js_ast.Expr{Data: &js_ast.ECall{
+		Target: js_ast.Expr{Data: &js_ast.EIdentifier{Ref: c.unboundExportsRef}},
+		Args:   args,
+	}}}}
+}
+
+func (c *linkerContext) systemJSExportsForChunk(chunk *chunkInfo, chunkRepr *chunkReprJS) map[ast.Ref][]string {
+	exports := make(map[ast.Ref][]string)
+
+	// This mirrors how the entry point tail generates the export clause
+	if chunk.isEntryPoint {
+		repr := c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr)
+		if repr.Meta.Wrap != graph.WrapCJS {
+			for _, alias := range repr.Meta.SortedAndFilteredExportAliases {
+				export := repr.Meta.ResolvedExports[alias]
+				if importData, ok := c.graph.Files[export.SourceIndex].InputFile.Repr.(*graph.JSRepr).Meta.ImportsToBind[export.Ref]; ok {
+					export.Ref = importData.Ref
+				}
+
+				// Exports of CommonJS properties are copied and are not live
+				if c.graph.Symbols.Get(export.Ref).NamespaceAlias == nil {
+					ref := ast.FollowSymbols(c.graph.Symbols, export.Ref)
+					exports[ref] = append(exports[ref], alias)
+				}
+			}
+		}
+	}
+
+	for ref, alias := range chunkRepr.exportsToOtherChunks {
+		ref = ast.FollowSymbols(c.graph.Symbols, ref)
+		exports[ref] = append(exports[ref], alias)
+	}
+
+	if len(exports) == 0 {
+		return nil
+	}
+	for _, aliases := range exports {
+		sort.Strings(aliases) // Sort for determinism
+	}
+	return exports
+}
+
+// This generates everything in a SystemJS module that comes before the code:
+//
+//	System.register(["foo"], function(exports, module) {
+//	  "use strict";
+//	  var foo;
+//	  return {
+//	    setters: [function(module) {
+//	      foo = module.foo;
+//	    }],
+//	    execute: function() {
+//
+// It also returns the metafile entries for the dependencies.
+func (c *linkerContext) generateSystemJSPrefix(
+	r renamer.Renamer,
+	chunk *chunkInfo,
+	chunkRepr *chunkReprJS,
+	imports []systemJSImport,
+) (string, []string) {
+	space := " "
+	newline := "\n"
+	indent := func(depth int) string { return strings.Repeat("  ", depth) }
+	if c.options.MinifyWhitespace {
+		space = ""
+		newline = ""
+		indent = func(int) string { return "" }
+	}
+	nameForSymbol := func(ref ast.Ref) string {
+		name := r.NameForSymbol(ref)
+		if c.options.ASCIIOnly {
+			name = string(js_printer.QuoteIdentifier(nil, name, c.options.UnsupportedJSFeatures))
+		}
+		return name
+	}
+
+	// Merge imports of the same dependency together
+	type dependency struct {
+		record  ast.ImportRecord
+		imports []systemJSImport
+	}
+	var deps []dependency
+	depIndices := make(map[string]int)
+	for _, imp := range imports {
+		i, ok := depIndices[imp.record.Path.Text]
+		if !ok {
+			i = len(deps)
+			depIndices[imp.record.Path.Text] = i
+			deps = append(deps, dependency{record: imp.record})
+		}
+		deps[i].imports = append(deps[i].imports, imp)
+	}
+
+	// Names exported by this module take precedence over "export * from"
+	starExcludes := []string{"default"}
+	if chunk.isEntryPoint {
+		for _, alias := range c.graph.Files[chunk.sourceIndex].InputFile.Repr.(*graph.JSRepr).Meta.SortedAndFilteredExportAliases {
+			if alias != "default" {
+				starExcludes = append(starExcludes, alias)
+			}
+		}
+	}
+	for _, alias := range chunkRepr.exportsToOtherChunks {
+		starExcludes = append(starExcludes, alias)
+	}
+
+	// Generate the dependency array, the variables, and the setters
+	var jsonMetadataImports []string
+	var depsArray []string
+	var vars []string
+	var setters []string
+	varNames := make(map[string]bool)
+	hasSetter := false
+	for _, dep := range deps {
+		path := string(helpers.QuoteForJSON(dep.record.Path.Text, c.options.ASCIIOnly))
+		depsArray = append(depsArray, path)
+		if c.options.NeedsMetafile {
+			external := ""
+			if !dep.record.Flags.Has(ast.ShouldNotBeExternalInMetafile) {
+				external = ",\n          \"external\": true"
+			}
+			jsonMetadataImports = append(jsonMetadataImports, fmt.Sprintf("\n        {\n          \"path\": %s,\n          \"kind\": %s%s\n        }",
+				helpers.QuoteForJSON(dep.record.Path.Text, c.options.ASCIIOnly),
+				helpers.QuoteForJSON(ast.ImportStmt.StringForMetafile(), c.options.ASCIIOnly),
+				external))
+		}
+
+		var stmts []string
+		assign := func(ref ast.Ref, value string) {
+			name := nameForSymbol(ref)
+			if !varNames[name] {
+				varNames[name] = true
+				vars = append(vars, name)
+			}
+			stmts = append(stmts, fmt.Sprintf("%s%s=%s%s", name, space, space, value))
+
+			// Re-exported imports must pass along their new values too
+			for _, alias := range chunkRepr.systemJSExports[ast.FollowSymbols(c.graph.Symbols, ref)] {
+				stmts = append(stmts, fmt.Sprintf("exports(%s,%s%s)", helpers.QuoteForJSON(alias, c.options.ASCIIOnly), space, name))
+			}
+		}
+		isExportStar := false
+		for _, imp := range dep.imports {
+			if imp.namespaceRef != ast.InvalidRef {
+				assign(imp.namespaceRef, "module")
+			}
+			for _, item := range imp.items {
+				assign(item.Name.Ref, "module"+c.propertyAccessText(item.Alias))
+			}
+			if imp.isExportStar {
+				isExportStar = true
+			}
+		}
+		if isExportStar {
+			var conditions []string
+			for _, alias := range starExcludes {
+				conditions = append(conditions, fmt.Sprintf("k%s!==%s%s", space, space, helpers.QuoteForJSON(alias, c.options.ASCIIOnly)))
+			}
+			semicolon := ";"
+			if c.options.MinifyWhitespace {
+				semicolon = ""
+			}
+			stmts = append(stmts, fmt.Sprintf("Object.keys(module).forEach(function(k)%s{%s%sif%s(%s)%sexports(k,%smodule[k])%s%s%s})",
+				space, newline, indent(4), space, strings.Join(conditions, space+"&&"+space), space, space, semicolon, newline, indent(3)))
+		}
+
+		if len(stmts) == 0 {
+			setters = append(setters, "null")
+			continue
+		}
+		hasSetter = true
+		if c.options.MinifyWhitespace {
+			setters = append(setters, fmt.Sprintf("function(module){%s}", strings.Join(stmts, ";")))
+		} else {
+			setters = append(setters, fmt.Sprintf("function(module) {\n%s%s;\n%s}",
+				indent(3), strings.Join(stmts, ";\n"+indent(3)), indent(2)))
+		}
+	}
+
+	// The "execute" function must be async if the code uses top-level await
+	isAsync := false
+	for _, sourceIndex := range chunkRepr.filesInChunkInOrder {
+		if c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr).Meta.IsAsyncOrHasAsyncDependency {
+			isAsync = true
+			break
+		}
+	}
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("System.register([%s],%sfunction(exports,%smodule)%s{%s",
+		strings.Join(depsArray, ","+space), space, space, space, newline))
+	sb.WriteString(fmt.Sprintf("%s\"use strict\";%s", indent(1), newline))
+	if len(vars) > 0 {
+		sb.WriteString(fmt.Sprintf("%svar %s;%s", indent(1), strings.Join(vars, ","+space), newline))
+	}
+	sb.WriteString(fmt.Sprintf("%sreturn%s{%s", indent(1), space, newline))
+	if hasSetter {
+		sb.WriteString(fmt.Sprintf("%ssetters:%s[%s],%s", indent(2), space, strings.Join(setters, ","+space), newline))
+	}
+	async := ""
+	if isAsync {
+		async = "async "
+	}
+	sb.WriteString(fmt.Sprintf("%sexecute:%s%sfunction()%s{%s", indent(2), space, async, space, newline))
+	return sb.String(), jsonMetadataImports
+}
+
 type compileResultCSS struct {
 	css_printer.PrintResult
 
@@ -6349,9 +7814,9 @@ func (c *linkerContext) generateChunkCSS(chunkIndex int, chunkWaitGroup *sync.Wa
 				} else {
 					jMeta.AddString(",")
 				}
-				jMeta.AddString(fmt.Sprintf("\n        %s: {\n          \"bytesInOutput\": %d\n        }",
+				jMeta.AddString(fmt.Sprintf("\n        %s: {\n          \"bytesInOutput\": %d%s\n        }",
 					helpers.QuoteForJSON(c.graph.Files[compileResult.sourceIndex.GetIndex()].InputFile.Source.PrettyPath, c.options.ASCIIOnly),
-					c.accurateFinalByteCount(pieces[i], finalRelDir)))
+					c.accurateFinalByteCount(pieces[i], finalRelDir), c.metafileWhy[compileResult.sourceIndex.GetIndex()]))
 			}
 			if len(compileResults) > 0 {
 				jMeta.AddString("\n      ")
@@ -6579,9 +8044,12 @@ func (c *linkerContext) appendIsolatedHashesForImportedChunks(
 		c.appendIsolatedHashesForImportedChunks(hash, chunkImport.chunkIndex, visited, visitedKey)
 	}
 
-	// Mix in hashes for referenced asset paths (i.e. the "file" loader)
+	// Mix in hashes for referenced asset paths (i.e. the "file" loader) and
+	// for the output paths of workers, which already contain their own hashes
 	for _, piece := range chunk.intermediateOutput.pieces {
-		if piece.kind == outputPieceAssetIndex {
+		if piece.kind == outputPieceWorkerIndex {
+			hashWriteLengthPrefixed(hash, []byte(c.options.WorkerOutputPaths[piece.index]))
+		} else if piece.kind == outputPieceAssetIndex {
 			file := c.graph.Files[piece.index]
 			if len(file.InputFile.AdditionalFiles) != 1 {
 				panic("Internal error")
@@ -6629,6 +8097,8 @@ func (c *linkerContext) breakOutputIntoPieces(output []byte) intermediateOutput
 					kind = outputPieceAssetIndex
 				case 'C':
 					kind = outputPieceChunkIndex
+				case 'W':
+					kind = outputPieceWorkerIndex
 				}
 				for j := 1; j < 9; j++ {
 					c := output[start+j]
@@ -6653,6 +8123,11 @@ func (c *linkerContext) breakOutputIntoPieces(output []byte) intermediateOutput
 				boundary = -1
 			}
 
+		case outputPieceWorkerIndex:
+			if _, ok := c.options.WorkerOutputPaths[index]; !ok {
+				boundary = -1
+			}
+
 		default:
 			boundary = -1
 		}
@@ -7085,32 +8560,3 @@ func (c *linkerContext) recoverInternalError(waitGroup *sync.WaitGroup, sourceIn
 		waitGroup.Done()
 	}
 }
-
-func joinWithPublicPath(publicPath string, relPath string) string {
-	if strings.HasPrefix(relPath, "./") {
-		relPath = relPath[2:]
-
-		// Strip any amount of further no-op slashes (i.e. ".///././/x/y" => "x/y")
-		for {
-			if strings.HasPrefix(relPath, "/") {
-				relPath = relPath[1:]
-			} else if strings.HasPrefix(relPath, "./") {
-				relPath = relPath[2:]
-			} else {
-				break
-			}
-		}
-	}
-
-	// Use a relative path if there is no public path
-	if publicPath == "" {
-		publicPath = "."
-	}
-
-	// Join with a slash
-	slash := "/"
-	if strings.HasSuffix(publicPath, "/") {
-		slash = ""
-	}
-	return fmt.Sprintf("%s%s%s", publicPath, slash, relPath)
-}
@@ -0,0 +1,179 @@
+package linker
+
+// This file implements the "metafileWhy" option, which records why each input
+// file was included in the bundle. For each input file in each output file,
+// the metafile gets the shortest chain of imports from an entry point to that
+// file and the exports of that file that are used by the included code.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/graph"
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+)
+
+// This must be called after tree shaking since it depends on which files and
+// parts are live
+func (c *linkerContext) computeMetafileWhy() {
+	c.timer.Begin("Compute metafile why")
+	defer c.timer.End("Compute metafile why")
+
+	// Find the shortest import chain to each live file with a breadth-first
+	// search starting from all entry points
+	parents := make(map[uint32]uint32)
+	visited := make(map[uint32]bool)
+	var queue []uint32
+	for _, entryPoint := range c.graph.EntryPoints() {
+		if !visited[entryPoint.SourceIndex] {
+			visited[entryPoint.SourceIndex] = true
+			queue = append(queue, entryPoint.SourceIndex)
+		}
+	}
+	for len(queue) > 0 {
+		sourceIndex := queue[0]
+		queue = queue[1:]
+		visit := func(otherSourceIndex uint32, parent uint32, hasParent bool) {
+			if !visited[otherSourceIndex] && c.graph.Files[otherSourceIndex].IsLive {
+				visited[otherSourceIndex] = true
+				if hasParent {
+					parents[otherSourceIndex] = parent
+				}
+				queue = append(queue, otherSourceIndex)
+			}
+		}
+
+		switch repr := c.graph.Files[sourceIndex].InputFile.Repr.(type) {
+		case *graph.JSRepr:
+			// The JavaScript stub for a CSS file has the same path as the CSS file,
+			// so link the CSS file to whatever imported the stub instead
+			if repr.CSSSourceIndex.IsValid() {
+				parent, hasParent := parents[sourceIndex]
+				visit(repr.CSSSourceIndex.GetIndex(), parent, hasParent)
+			}
+			for _, record := range repr.AST.ImportRecords {
+				if record.SourceIndex.IsValid() && !record.Flags.Has(ast.IsUnused) {
+					visit(record.SourceIndex.GetIndex(), sourceIndex, true)
+				}
+			}
+
+		case *graph.CSSRepr:
+			for _, record := range repr.AST.ImportRecords {
+				if record.SourceIndex.IsValid() {
+					visit(record.SourceIndex.GetIndex(), sourceIndex, true)
+				}
+			}
+		}
+	}
+
+	// Find the exports of each file that are used by live code
+	usedExports := make(map[uint32]map[string]bool)
+	aliasesForFile := make(map[uint32]map[ast.Ref][]string)
+	markUsed := func(sourceIndex uint32, ref ast.Ref) {
+		aliasesForRef, ok := aliasesForFile[sourceIndex]
+		if !ok {
+			aliasesForRef = make(map[ast.Ref][]string)
+			if repr, ok := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr); ok {
+				for alias, export := range repr.Meta.ResolvedExports {
+					if export.SourceIndex == sourceIndex {
+						aliasesForRef[export.Ref] = append(aliasesForRef[export.Ref], alias)
+					}
+				}
+			}
+			aliasesForFile[sourceIndex] = aliasesForRef
+		}
+		for _, alias := range aliasesForRef[ref] {
+			used := usedExports[sourceIndex]
+			if used == nil {
+				used = make(map[string]bool)
+				usedExports[sourceIndex] = used
+			}
+			used[alias] = true
+		}
+	}
+	for _, sourceIndex := range c.graph.ReachableFiles {
+		file := &c.graph.Files[sourceIndex]
+		repr, ok := file.InputFile.Repr.(*graph.JSRepr)
+		if !ok || !file.IsLive {
+			continue
+		}
+
+		// Named imports that are used by live code
+		for _, part := range repr.AST.Parts {
+			if !part.IsLive {
+				continue
+			}
+			for ref := range part.SymbolUses {
+				if importData, ok := repr.Meta.ImportsToBind[ref]; ok {
+					markUsed(importData.SourceIndex, importData.Ref)
+				}
+			}
+		}
+
+		// Every export is used if the namespace object for this file is used,
+		// and the exports of entry points are used by whoever loads them
+		if file.IsEntryPoint() || (len(repr.AST.Parts) > int(js_ast.NSExportPartIndex) && repr.AST.Parts[js_ast.NSExportPartIndex].IsLive) {
+			for _, export := range repr.Meta.ResolvedExports {
+				otherRepr, ok := c.graph.Files[export.SourceIndex].InputFile.Repr.(*graph.JSRepr)
+				if !ok {
+					continue
+				}
+				for _, partIndex := range otherRepr.TopLevelSymbolToParts(export.Ref) {
+					if otherRepr.AST.Parts[partIndex].IsLive {
+						markUsed(export.SourceIndex, export.Ref)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	// Pre-format the JSON for each live file since it's the same in every chunk
+	c.metafileWhy = make(map[uint32]string)
+	for sourceIndex := range visited {
+		var chain []string
+		for current := sourceIndex; ; {
+			parent, ok := parents[current]
+			if !ok {
+				break
+			}
+			chain = append(chain, c.graph.Files[parent].InputFile.Source.PrettyPath)
+			current = parent
+		}
+		for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+			chain[i], chain[j] = chain[j], chain[i]
+		}
+
+		sb := strings.Builder{}
+		sb.WriteString(",\n          \"why\": {\n            \"importChain\": ")
+		c.writeMetafileWhyArray(&sb, chain)
+		if _, ok := c.graph.Files[sourceIndex].InputFile.Repr.(*graph.JSRepr); ok {
+			var exports []string
+			for alias := range usedExports[sourceIndex] {
+				exports = append(exports, alias)
+			}
+			sort.Strings(exports)
+			sb.WriteString(",\n            \"usedExports\": ")
+			c.writeMetafileWhyArray(&sb, exports)
+		}
+		sb.WriteString("\n          }")
+		c.metafileWhy[sourceIndex] = sb.String()
+	}
+}
+
+func (c *linkerContext) writeMetafileWhyArray(sb *strings.Builder, items []string) {
+	sb.WriteByte('[')
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(fmt.Sprintf("\n              %s", helpers.QuoteForJSON(item, c.options.ASCIIOnly)))
+	}
+	if len(items) > 0 {
+		sb.WriteString("\n            ")
+	}
+	sb.WriteByte(']')
+}
@@ -59,10 +59,12 @@ const (
 	// Bundler
 	MsgID_Bundler_AmbiguousReexport
 	MsgID_Bundler_DifferentPathCase
+	MsgID_Bundler_DuplicatePackage
 	MsgID_Bundler_EmptyGlob
 	MsgID_Bundler_IgnoredBareImport
 	MsgID_Bundler_IgnoredDynamicImport
 	MsgID_Bundler_ImportIsUndefined
+	MsgID_Bundler_InvalidImportMap
 	MsgID_Bundler_RequireResolveNotExternal
 
 	// Source maps
@@ -186,6 +188,8 @@ func StringToMsgIDs(str string, logLevel LogLevel, overrides map[MsgID]LogLevel)
 		overrides[MsgID_Bundler_AmbiguousReexport] = logLevel
 	case "different-path-case":
 		overrides[MsgID_Bundler_DifferentPathCase] = logLevel
+	case "duplicate-package":
+		overrides[MsgID_Bundler_DuplicatePackage] = logLevel
 	case "empty-glob":
 		overrides[MsgID_Bundler_EmptyGlob] = logLevel
 	case "ignored-bare-import":
@@ -194,6 +198,8 @@ func StringToMsgIDs(str string, logLevel LogLevel, overrides map[MsgID]LogLevel)
 		overrides[MsgID_Bundler_IgnoredDynamicImport] = logLevel
 	case "import-is-undefined":
 		overrides[MsgID_Bundler_ImportIsUndefined] = logLevel
+	case "invalid-import-map":
+		overrides[MsgID_Bundler_InvalidImportMap] = logLevel
 	case "require-resolve-not-external":
 		overrides[MsgID_Bundler_RequireResolveNotExternal] = logLevel
 
@@ -316,6 +322,8 @@ func MsgIDToString(id MsgID) string {
 		return "ambiguous-reexport"
 	case MsgID_Bundler_DifferentPathCase:
 		return "different-path-case"
+	case MsgID_Bundler_DuplicatePackage:
+		return "duplicate-package"
 	case MsgID_Bundler_EmptyGlob:
 		return "empty-glob"
 	case MsgID_Bundler_IgnoredBareImport:
@@ -324,6 +332,8 @@ func MsgIDToString(id MsgID) string {
 		return "ignored-dynamic-import"
 	case MsgID_Bundler_ImportIsUndefined:
 		return "import-is-undefined"
+	case MsgID_Bundler_InvalidImportMap:
+		return "invalid-import-map"
 	case MsgID_Bundler_RequireResolveNotExternal:
 		return "require-resolve-not-external"
 
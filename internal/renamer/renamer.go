@@ -655,8 +655,23 @@ func (r *ExportRenamer) NextRenamedName(name string) string {
 	return name
 }
 
+// This prevents the name from being generated. It's used to avoid names that
+// are already exported by some other means.
+func (r *ExportRenamer) Reserve(name string) {
+	if r.used == nil {
+		r.used = make(map[string]uint32)
+	}
+	if _, ok := r.used[name]; !ok {
+		r.used[name] = 1
+	}
+}
+
 func (r *ExportRenamer) NextMinifiedName() string {
-	name := ast.DefaultNameMinifierJS.NumberToMinifiedName(r.count)
-	r.count++
-	return name
+	for {
+		name := ast.DefaultNameMinifierJS.NumberToMinifiedName(r.count)
+		r.count++
+		if _, ok := r.used[name]; !ok {
+			return name
+		}
+	}
 }
@@ -0,0 +1,268 @@
+package resolver
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/cache"
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_parser"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+// This is an implementation of the "imports" and "scopes" fields from import
+// maps: https://html.spec.whatwg.org/multipage/webappapis.html#import-maps.
+// Browsers resolve everything as URLs, but we resolve relative URLs against
+// the directory containing the import map and then treat them as file system
+// paths instead. Addresses that are absolute URLs (other than "file:" URLs)
+// can't be bundled so they are marked as external.
+type importMap struct {
+	imports []importMapEntry
+	scopes  []importMapScope
+}
+
+type importMapScope struct {
+	prefix  importMapSpecifier
+	imports []importMapEntry
+}
+
+type importMapEntry struct {
+	key importMapSpecifier
+
+	// This is nil if the address is "null", which blocks the specifier
+	address *importMapSpecifier
+}
+
+type importMapSpecifier struct {
+	// This is either an absolute path, a URL, or a bare specifier
+	text string
+
+	// If true, "text" is an absolute path in the file system
+	isPath bool
+
+	// If true, this matches everything that starts with "text". This is
+	// represented separately instead of as a trailing slash because paths
+	// are cleaned (which removes the trailing slash) and may not even use
+	// "/" as a path separator.
+	isPrefix bool
+}
+
+func parseImportMap(log logger.Log, fs fs.FS, source logger.Source, jsonCache *cache.JSONCache) *importMap {
+	json, ok := jsonCache.Parse(log, source, js_parser.JSONOptions{})
+	if !ok {
+		return nil
+	}
+
+	tracker := logger.MakeLineColumnTracker(&source)
+	baseDir := fs.Dir(source.KeyPath.Text)
+	result := &importMap{}
+
+	if _, ok := json.Data.(*js_ast.EObject); !ok {
+		log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, &tracker, logger.Range{Loc: json.Loc},
+			"The import map must be a JSON object")
+		return nil
+	}
+
+	// Parse "imports"
+	if importsJSON, _, ok := getProperty(json, "imports"); ok {
+		result.imports = parseImportMapEntries(log, &tracker, source, fs, baseDir, importsJSON, "imports")
+	}
+
+	// Parse "scopes"
+	if scopesJSON, _, ok := getProperty(json, "scopes"); ok {
+		if scopes, ok := scopesJSON.Data.(*js_ast.EObject); ok {
+			for _, prop := range scopes.Properties {
+				key, ok := getString(prop.Key)
+				if !ok {
+					continue
+				}
+				prefix, ok := parseImportMapAddress(fs, baseDir, key)
+				if !ok {
+					log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, &tracker, source.RangeOfString(prop.Key.Loc),
+						fmt.Sprintf("Invalid scope %q (scopes must be URLs or start with \"/\", \"./\", or \"../\")", key))
+					continue
+				}
+				result.scopes = append(result.scopes, importMapScope{
+					prefix:  prefix,
+					imports: parseImportMapEntries(log, &tracker, source, fs, baseDir, prop.ValueOrNil, "scopes"),
+				})
+			}
+
+			// More specific scopes take precedence over less specific ones
+			sort.SliceStable(result.scopes, func(i int, j int) bool {
+				return result.scopes[i].prefix.text > result.scopes[j].prefix.text
+			})
+		} else {
+			log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, &tracker, logger.Range{Loc: scopesJSON.Loc},
+				"The value for \"scopes\" must be an object")
+		}
+	}
+
+	return result
+}
+
+func parseImportMapEntries(
+	log logger.Log,
+	tracker *logger.LineColumnTracker,
+	source logger.Source,
+	fs fs.FS,
+	baseDir string,
+	json js_ast.Expr,
+	field string,
+) (entries []importMapEntry) {
+	obj, ok := json.Data.(*js_ast.EObject)
+	if !ok {
+		log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, tracker, logger.Range{Loc: json.Loc},
+			fmt.Sprintf("The value for %q must be an object", field))
+		return
+	}
+
+	for _, prop := range obj.Properties {
+		key, ok := getString(prop.Key)
+		if !ok {
+			continue
+		}
+		if key == "" {
+			log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, tracker, source.RangeOfString(prop.Key.Loc),
+				"Invalid empty specifier in import map")
+			continue
+		}
+		entry := importMapEntry{key: parseImportMapSpecifier(fs, baseDir, key)}
+
+		// A value of "null" means the specifier is blocked
+		if _, ok := prop.ValueOrNil.Data.(*js_ast.ENull); !ok {
+			value, ok := getString(prop.ValueOrNil)
+			if !ok {
+				log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, tracker, logger.Range{Loc: prop.ValueOrNil.Loc},
+					fmt.Sprintf("The mapping for %q must be a string or null", key))
+				continue
+			}
+			address, ok := parseImportMapAddress(fs, baseDir, value)
+			if !ok {
+				log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, tracker, source.RangeOfString(prop.ValueOrNil.Loc),
+					fmt.Sprintf("Invalid address %q for %q (addresses must be URLs or start with \"/\", \"./\", or \"../\")", value, key))
+				continue
+			}
+			if entry.key.isPrefix && !address.isPrefix {
+				log.AddID(logger.MsgID_Bundler_InvalidImportMap, logger.Warning, tracker, source.RangeOfString(prop.ValueOrNil.Loc),
+					fmt.Sprintf("The address %q for %q must end in \"/\" because the specifier ends in \"/\"", value, key))
+				continue
+			}
+			entry.address = &address
+		}
+
+		entries = append(entries, entry)
+	}
+
+	// Sort in descending order so that longer keys are checked first
+	sort.SliceStable(entries, func(i int, j int) bool {
+		return entries[i].key.text > entries[j].key.text
+	})
+	return
+}
+
+// Specifiers are either paths, URLs, or bare specifiers
+func parseImportMapSpecifier(fs fs.FS, baseDir string, text string) importMapSpecifier {
+	if spec, ok := parseImportMapAddress(fs, baseDir, text); ok {
+		return spec
+	}
+	return importMapSpecifier{text: text, isPrefix: strings.HasSuffix(text, "/")}
+}
+
+// Addresses are either paths or URLs. Bare specifiers are not allowed.
+func parseImportMapAddress(fs fs.FS, baseDir string, text string) (importMapSpecifier, bool) {
+	isPrefix := strings.HasSuffix(text, "/")
+
+	if strings.HasPrefix(text, "./") || strings.HasPrefix(text, "../") {
+		return importMapSpecifier{text: fs.Join(baseDir, text), isPath: true, isPrefix: isPrefix}, true
+	}
+
+	if strings.HasPrefix(text, "/") && !strings.HasPrefix(text, "//") {
+		return importMapSpecifier{text: fs.Join(text), isPath: true, isPrefix: isPrefix}, true
+	}
+
+	if u, err := url.Parse(text); err == nil && u.Scheme != "" && len(u.Scheme) > 1 {
+		if u.Scheme == "file" {
+			if u.Path == "" {
+				return importMapSpecifier{}, false
+			}
+			return importMapSpecifier{text: fs.Join(u.Path), isPath: true, isPrefix: isPrefix}, true
+		}
+		return importMapSpecifier{text: text, isPrefix: isPrefix}, true
+	}
+
+	return importMapSpecifier{}, false
+}
+
+type importMapResult uint8
+
+const (
+	importMapNoMatch importMapResult = iota
+	importMapBlocked
+	importMapPath
+	importMapURL
+)
+
+// This returns the substituted path or URL for the import path, if any
+func (m *importMap) resolve(fs fs.FS, sourceDir string, importPath string) (string, importMapResult) {
+	spec := parseImportMapSpecifier(fs, sourceDir, importPath)
+
+	// Scopes are matched against the directory of the importer since we don't
+	// have the importer's file name. Only scopes ending in "/" can match.
+	for _, scope := range m.scopes {
+		if scope.prefix.isPath && scope.prefix.isPrefix && isSameOrNestedPath(fs, scope.prefix.text, sourceDir) {
+			if result, kind := resolveImportMapEntries(fs, scope.imports, spec); kind != importMapNoMatch {
+				return result, kind
+			}
+		}
+	}
+
+	return resolveImportMapEntries(fs, m.imports, spec)
+}
+
+func resolveImportMapEntries(fs fs.FS, entries []importMapEntry, spec importMapSpecifier) (string, importMapResult) {
+	for _, entry := range entries {
+		if entry.key.isPath != spec.isPath {
+			continue
+		}
+
+		// Check for an exact match or a prefix match
+		var rest string
+		if entry.key.text == spec.text && entry.key.isPrefix == spec.isPrefix {
+			// Exact match
+		} else if !entry.key.isPrefix {
+			continue
+		} else if !entry.key.isPath && strings.HasPrefix(spec.text, entry.key.text) {
+			rest = spec.text[len(entry.key.text):]
+		} else if rel, ok := fs.Rel(entry.key.text, spec.text); entry.key.isPath && ok && rel != "." && isSameOrNestedPath(fs, entry.key.text, spec.text) {
+			rest = strings.ReplaceAll(rel, "\\", "/")
+		} else {
+			continue
+		}
+
+		if entry.address == nil {
+			return "", importMapBlocked
+		}
+		if entry.address.isPath {
+			if rest == "" {
+				return entry.address.text, importMapPath
+			}
+			return fs.Join(entry.address.text, rest), importMapPath
+		}
+		address := entry.address.text
+		if rest != "" && !strings.HasSuffix(address, "/") {
+			address += "/"
+		}
+		return address + rest, importMapURL
+	}
+
+	return "", importMapNoMatch
+}
+
+func isSameOrNestedPath(fs fs.FS, dir string, path string) bool {
+	rel, ok := fs.Rel(dir, path)
+	return ok && rel != ".." && !strings.HasPrefix(rel, "../") && !strings.HasPrefix(rel, "..\\") && !fs.IsAbs(rel)
+}
@@ -18,6 +18,7 @@ import (
 
 type packageJSON struct {
 	name           string
+	version        string
 	mainFields     map[string]mainField
 	moduleTypeData js_ast.ModuleTypeData
 
@@ -289,6 +290,13 @@ func (r resolverQuery) parsePackageJSON(inputPath string) *packageJSON {
 		}
 	}
 
+	// Read the "version" field
+	if versionJSON, _, ok := getProperty(json, "version"); ok {
+		if versionValue, ok := getString(versionJSON); ok {
+			packageJSON.version = versionValue
+		}
+	}
+
 	// Read the "type" field
 	if typeJSON, typeKeyLoc, ok := getProperty(json, "type"); ok {
 		if typeValue, ok := getString(typeJSON); ok {
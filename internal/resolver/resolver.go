@@ -165,6 +165,7 @@ type Resolver struct {
 	caches *cache.CacheSet
 
 	tsConfigOverride *TSConfigJSON
+	importMap        *importMap
 
 	// These are sets that represent various conditions for the "exports" field
 	// in package.json.
@@ -353,6 +354,32 @@ func NewResolver(call config.APICall, fs fs.FS, log logger.Log, caches *cache.Ca
 		}
 	}
 
+	// Handle the import map when the resolver is created for the same reasons
+	if options.ImportMapPath != "" {
+		contents, err, _ := caches.FSCache.ReadFile(fs, options.ImportMapPath)
+		if err != nil {
+			prettyPath := PrettyPath(fs, logger.Path{Text: options.ImportMapPath, Namespace: "file"})
+			if err == syscall.ENOENT {
+				log.AddError(nil, logger.Range{}, fmt.Sprintf("Cannot find import map file %q", prettyPath))
+			} else {
+				log.AddError(nil, logger.Range{}, fmt.Sprintf("Cannot read file %q: %s", prettyPath, err.Error()))
+			}
+		} else {
+			keyPath := logger.Path{Text: options.ImportMapPath, Namespace: "file"}
+			res.importMap = parseImportMap(log, fs, logger.Source{
+				KeyPath:    keyPath,
+				PrettyPath: PrettyPath(fs, keyPath),
+				Contents:   contents,
+			}, &caches.JSONCache)
+		}
+	} else if options.ImportMapRaw != "" {
+		res.importMap = parseImportMap(log, fs, logger.Source{
+			KeyPath:    logger.Path{Text: fs.Join(fs.Cwd(), "<importmap.json>"), Namespace: "file"},
+			PrettyPath: "<importmap.json>",
+			Contents:   options.ImportMapRaw,
+		}, &caches.JSONCache)
+	}
+
 	// Mutate the provided options by settings from "tsconfig.json" if present
 	if res.tsConfigOverride != nil {
 		options.TS.Config = res.tsConfigOverride.Settings
@@ -376,7 +403,47 @@ func (res *Resolver) Resolve(sourceDir string, importPath string, kind ast.Impor
 			importPath, sourceDir, kind.StringForMetafile())}
 	}
 
-	// Apply package alias substitutions first
+	// Apply the import map first since browsers do this before anything else
+	if r.importMap != nil {
+		if r.debugLogs != nil {
+			r.debugLogs.addNote("Checking for import map matches")
+		}
+		switch result, kind := r.importMap.resolve(r.fs, sourceDir, importPath); kind {
+		case importMapBlocked:
+			debugMeta.notes = append(debugMeta.notes, logger.MsgData{
+				Text: fmt.Sprintf("The import %q is mapped to null in the import map.", importPath)})
+			if r.debugLogs != nil {
+				r.debugLogs.addNote(fmt.Sprintf("  The import %q was blocked by the import map", importPath))
+			}
+			r.flushDebugLogs(flushDueToFailure)
+			return nil, debugMeta
+
+		case importMapURL:
+			if r.debugLogs != nil {
+				r.debugLogs.addNote(fmt.Sprintf("  Mapped import path from %q to the URL %q", importPath, result))
+				r.debugLogs.addNote("  Marking this URL as external")
+			}
+			debugMeta.ModifiedImportPath = result
+			r.flushDebugLogs(flushDueToSuccess)
+			return &ResolveResult{
+				PathPair: PathPair{Primary: logger.Path{Text: result}, IsExternal: true},
+			}, debugMeta
+
+		case importMapPath:
+			if r.debugLogs != nil {
+				r.debugLogs.addNote(fmt.Sprintf("  Modified import path from %q to %q", importPath, result))
+			}
+			debugMeta.ModifiedImportPath = result
+			importPath = result
+
+		default:
+			if r.debugLogs != nil {
+				r.debugLogs.addNote("  Failed to find any import map matches")
+			}
+		}
+	}
+
+	// Apply package alias substitutions next
 	if r.options.PackageAliases != nil && IsPackagePath(importPath) {
 		if r.debugLogs != nil {
 			r.debugLogs.addNote("Checking for package alias matches")
@@ -779,6 +846,38 @@ func (res *Resolver) ProbeResolvePackageAsRelative(sourceDir string, importPath
 	return nil, debugMeta
 }
 
+type NodeModulesPackage struct {
+	AbsDir  string
+	Name    string
+	Version string
+}
+
+// This returns the package inside a "node_modules" directory that contains
+// the provided file, if there is one. Nested "package.json" files without
+// both a "name" and a "version" are skipped since they are typically only
+// used to configure a subdirectory of the package (e.g. to set "type").
+func (res *Resolver) NodeModulesPackageForPath(absPath string) (NodeModulesPackage, bool) {
+	r := resolverQuery{Resolver: res}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for dirInfo := r.dirInfoCached(r.fs.Dir(absPath)); dirInfo != nil; dirInfo = dirInfo.parent {
+		if packageJSON := dirInfo.packageJSON; packageJSON != nil && packageJSON.name != "" && packageJSON.version != "" {
+			if !helpers.IsInsideNodeModules(dirInfo.absPath) {
+				break
+			}
+			return NodeModulesPackage{
+				AbsDir:  dirInfo.absPath,
+				Name:    packageJSON.name,
+				Version: packageJSON.version,
+			}, true
+		}
+	}
+
+	return NodeModulesPackage{}, false
+}
+
 type debugLogs struct {
 	what   string
 	indent string
@@ -974,10 +1073,11 @@ func (r resolverQuery) resolveWithoutSymlinks(sourceDir string, sourceDirInfo *d
 		}
 	}
 
-	// Check both relative and package paths for CSS URL tokens, with relative
-	// paths taking precedence over package paths to match Webpack behavior.
+	// Check both relative and package paths for CSS URL tokens and HTML
+	// attributes, with relative paths taking precedence over package paths to
+	// match Webpack behavior.
 	isPackagePath := IsPackagePath(importPath)
-	checkRelative := !isPackagePath || r.kind.IsFromCSS()
+	checkRelative := !isPackagePath || r.kind.IsFromCSS() || r.kind == ast.ImportHTMLAttribute
 	checkPackage := isPackagePath
 
 	if checkRelative {
@@ -1040,7 +1140,6 @@ func (r resolverQuery) resolveWithoutSymlinks(sourceDir string, sourceDirInfo *d
 		if absolute, ok, diffCase, sideEffects := r.resolveWithoutRemapping(sourceDirInfo, importPath); ok {
 			result = ResolveResult{PathPair: absolute, DifferentCase: diffCase, PrimarySideEffectsData: sideEffects}
 		} else {
-			// Note: node's "self references" are not currently supported
 			return nil
 		}
 	}
@@ -2199,7 +2298,7 @@ func (r resolverQuery) loadPackageImports(importPath string, dirInfoPackageJSON
 	// The condition set is determined by the kind of import
 	conditions := r.esmConditionsDefault
 	switch r.kind {
-	case ast.ImportStmt, ast.ImportDynamic:
+	case ast.ImportStmt, ast.ImportDynamic, ast.ImportWorker:
 		conditions = r.esmConditionsImport
 	case ast.ImportRequire, ast.ImportRequireResolve:
 		conditions = r.esmConditionsRequire
@@ -2245,7 +2344,7 @@ func (r resolverQuery) esmResolveAlgorithm(esmPackageName string, esmPackageSubp
 	// The condition set is determined by the kind of import
 	conditions := r.esmConditionsDefault
 	switch r.kind {
-	case ast.ImportStmt, ast.ImportDynamic:
+	case ast.ImportStmt, ast.ImportDynamic, ast.ImportWorker:
 		conditions = r.esmConditionsImport
 	case ast.ImportRequire, ast.ImportRequireResolve:
 		conditions = r.esmConditionsRequire
@@ -2383,10 +2482,8 @@ func (r resolverQuery) loadNodeModules(importPath string, dirInfo *dirInfo, forb
 	}
 
 	// Check for self-references
-	if dirInfoPackageJSON != nil {
-		if packageJSON := dirInfoPackageJSON.packageJSON; packageJSON.name == esmPackageName && packageJSON.exportsMap != nil {
-			absolute, ok, diffCase := r.esmResolveAlgorithm(esmPackageName, esmPackageSubpath, packageJSON,
-				dirInfoPackageJSON.absPath, r.fs.Join(dirInfoPackageJSON.absPath, esmPackageSubpath))
+	if esmOK {
+		if absolute, ok, diffCase, found := r.loadPackageSelfReference(esmPackageName, esmPackageSubpath, dirInfoPackageJSON); found {
 			return absolute, ok, diffCase, nil
 		}
 	}
@@ -2457,6 +2554,30 @@ func (r resolverQuery) loadNodeModules(importPath string, dirInfo *dirInfo, forb
 	return PathPair{}, false, nil, nil
 }
 
+// This implements node's "PACKAGE_SELF_RESOLVE" algorithm, which lets code in
+// a package import that package by name through its own "exports" map. Like
+// node, only the nearest "package.json" file is checked. A "package.json" file
+// without a "name" field still stops the search.
+//
+// The last return value is true if a matching package was found, in which
+// case the result of resolving through its "exports" map is final.
+func (r resolverQuery) loadPackageSelfReference(esmPackageName string, esmPackageSubpath string, dirInfoPackageJSON *dirInfo) (PathPair, bool, *fs.DifferentCase, bool) {
+	if dirInfoPackageJSON == nil {
+		return PathPair{}, false, nil, false
+	}
+	packageJSON := dirInfoPackageJSON.packageJSON
+	if packageJSON.name != esmPackageName || packageJSON.exportsMap == nil {
+		return PathPair{}, false, nil, false
+	}
+	if r.debugLogs != nil {
+		r.debugLogs.addNote(fmt.Sprintf("Resolving %q as a self-reference using the file %q",
+			esmPackageName, r.fs.Join(dirInfoPackageJSON.absPath, "package.json")))
+	}
+	absolute, ok, diffCase := r.esmResolveAlgorithm(esmPackageName, esmPackageSubpath, packageJSON,
+		dirInfoPackageJSON.absPath, r.fs.Join(dirInfoPackageJSON.absPath, esmPackageSubpath))
+	return absolute, ok, diffCase, true
+}
+
 func (r resolverQuery) checkForBuiltInNodeModules(importPath string) (PathPair, bool, *SideEffectsData) {
 	// "import fs from 'fs'"
 	if r.options.Platform == config.PlatformNode && BuiltInNodeModules[importPath] {
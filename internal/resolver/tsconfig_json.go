@@ -192,6 +192,17 @@ func ParseTSConfigJSON(
 			}
 		}
 
+		// Parse "emitDecoratorMetadata"
+		if valueJSON, _, ok := getProperty(compilerOptionsJSON, "emitDecoratorMetadata"); ok {
+			if value, ok := getBool(valueJSON); ok {
+				if value {
+					result.Settings.EmitDecoratorMetadata = config.True
+				} else {
+					result.Settings.EmitDecoratorMetadata = config.False
+				}
+			}
+		}
+
 		// Parse "useDefineForClassFields"
 		if valueJSON, _, ok := getProperty(compilerOptionsJSON, "useDefineForClassFields"); ok {
 			if value, ok := getBool(valueJSON); ok {
@@ -363,6 +374,7 @@ func ParseTSConfigJSON(
 				switch key {
 				case "alwaysStrict",
 					"baseUrl",
+					"emitDecoratorMetadata",
 					"experimentalDecorators",
 					"importsNotUsedAsValues",
 					"jsx",
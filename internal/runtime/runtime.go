@@ -57,13 +57,10 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 	//   __spreadArray
 	//   __spreadArrays
 	//   __values
-	//
-	// Note: The "__objRest" function has a for-of loop which requires ES6, but
-	// transforming destructuring to ES5 isn't even supported so it's ok.
 	text := `
 		var __create = Object.create
 		var __freeze = Object.freeze
-		var __defProp = Object.defineProperty
+		export var __defProp = Object.defineProperty
 		var __defProps = Object.defineProperties
 		var __getOwnPropDesc = Object.getOwnPropertyDescriptor // Note: can return "undefined" due to a Safari bug
 		var __getOwnPropDescs = Object.getOwnPropertyDescriptors
@@ -81,7 +78,7 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 
 		export var __pow = Math.pow
 
-		var __defNormalProp = (obj, key, value) => key in obj
+		export var __defNormalProp = (obj, key, value) => key in obj
 			? __defProp(obj, key, {enumerable: true, configurable: true, writable: true, value})
 			: obj[key] = value
 
@@ -132,6 +129,79 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 				throw Error('Dynamic require of "' + x + '" is not supported')
 			})
 
+		// This is used as the base URL for "new URL('./file', import.meta.url)" in
+		// output formats without "import.meta". It has to be computed when the
+		// bundle starts running because "document.currentScript" is only available
+		// while the script is being evaluated. File paths are converted to URLs
+		// the same way as node's "pathToFileURL" function.
+		var __pathToFileURL = path => {
+			var encode = path => encodeURI(path).replace(/[?#]/g, encodeURIComponent)
+			return /^[a-z]:[\\/]/i.test(path) ? 'file:///' + encode(path.replace(/\\/g, '/')) :
+				/^\\\\/.test(path) ? 'file:' + encode(path.replace(/\\/g, '/')) :
+				'file://' + encode(path)
+		}
+		export var __scriptURL = /* @__PURE__ */ (() =>
+			typeof document !== 'undefined' && document.currentScript ? document.currentScript.src :
+			typeof __filename === 'string' ? __pathToFileURL(__filename) :
+			typeof self !== 'undefined' && self.location ? self.location.href : void 0
+		)()
+
+		// This implements code splitting with the IIFE format. Chunks register their
+		// exports in a global registry that's shared by all chunks, and paths to
+		// other chunks are resolved relative to the URL of the current chunk. This
+		// is a function declaration so that the chunk containing the runtime code
+		// can call it before the rest of the runtime code has been evaluated. Other
+		// chunks get it from the registry instead. Chunks are loaded using "<script>"
+		// tags in the browser and using "importScripts" in web workers, which don't
+		// have a DOM. Note that this relies on the "URL" and "Promise" globals.
+		export function __chunkLoader() {
+			var global = typeof self !== 'undefined' ? self : globalThis
+			var chunks = global.__esbuild_chunks || (global.__esbuild_chunks = { exports: {}, promises: {} })
+			var doc = typeof document !== 'undefined' && document
+			var src = doc && doc.currentScript ? doc.currentScript.src : chunks.current || global.location && global.location.href
+			var toURL = path => new URL(path, src).href
+			var importChunk = (url, current) => {
+				current = chunks.current
+				chunks.current = url
+				try {
+					importScripts(url)
+				} finally {
+					chunks.current = current
+				}
+			}
+			var get = path => {
+				var url = toURL(path)
+				if (!(url in chunks.exports) && !doc && typeof importScripts === 'function') importChunk(url)
+				if (!(url in chunks.exports)) throw new Error('Chunk "' + url + '" has not been loaded')
+				return chunks.exports[url]
+			}
+			get.define = (value, getters) => {
+				for (var name in getters) __defProp(value, name, { get: getters[name], enumerable: true })
+				return chunks.exports[src] = value
+			}
+			get.load = (paths, wrap) => paths.reduce((promise, path) => {
+				var url = toURL(path)
+				return promise.then(() => url in chunks.exports || chunks.promises[url] || (chunks.promises[url] = new Promise((resolve, reject) => {
+					if (doc) {
+						var script = doc.createElement('script')
+						script.onload = resolve
+						script.onerror = () => reject(new Error('Failed to load chunk "' + url + '"'))
+						script.src = url
+						doc.head.appendChild(script)
+					} else {
+						importChunk(url)
+						resolve()
+					}
+				})))
+			}, Promise.resolve()).then(() => {
+				var exports = get(paths[paths.length - 1])
+				return wrap ? wrap(exports) : exports
+			})
+			chunks.exports[src] = {}
+			chunks.loader = __chunkLoader
+			return get
+		}
+
 		// This is used for glob imports
 		export var __glob = map => path => {
 			var fn = map[path]
@@ -168,6 +238,38 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 			return target
 		}
 
+		// For lowering array destructuring patterns. This reads at most "count"
+		// values from the iterable (or all of them if "count" is omitted) and then
+		// closes the iterator. Array-like objects are also allowed in environments
+		// without "Symbol.iterator".
+		export var __toArray = (value, count) => {
+			var iterator = typeof Symbol === 'function' && Symbol.iterator, result = [], it, step
+			if (value == null) throw TypeError(value + ' is not iterable')
+			if (iterator && (it = value[iterator])) {
+				for (it = it.call(value); count == null || result.length < count; result.push(step.value))
+					if ((step = it.next()).done) return result
+				if (it.return) it.return()
+				return result
+			}
+			if (typeof value.length !== 'number') throw TypeError(value + ' is not iterable')
+			return [].slice.call(value, 0, count)
+		}
+
+		// For lowering "for-of" loops. Arrays are iterated over by index as a fast
+		// path. Array-like objects are also allowed in environments without
+		// "Symbol.iterator".
+		export var __forOf = (value, it, i) => {
+			var iterator = typeof Symbol === 'function' && Symbol.iterator
+			if (value == null) throw TypeError(value + ' is not iterable')
+			if (!Array.isArray(value) && iterator && (it = value[iterator])) return it.call(value)
+			if (typeof value.length !== 'number') throw TypeError(value + ' is not iterable')
+			i = 0
+			return { next: () => ({ done: i >= value.length, value: value[i++] }) }
+		}
+
+		// For lowering rest arguments
+		export var __restArgs = (args, start) => [].slice.call(args, start)
+
 		// This is for lazily-initialized ESM code. This has two implementations, a
 		// compact one for minified code and a verbose one that generates friendly
 		// names in V8's profiler and in stack traces.
@@ -184,6 +286,108 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 		}
 		export var __commonJSMin = (cb, mod) => () => (mod || cb((mod = {exports: {}}).exports, mod), mod.exports)
 
+		// These implement hot module replacement. Every module is wrapped like with
+		// "__commonJS" but the closures are stored in a registry that's shared by
+		// all copies of the output file that are loaded into the page. Loading a
+		// newer copy of the output file replaces the closures in the registry, and
+		// then the modules that changed are evaluated again.
+		var __hmrRegistry = registry => {
+			if (!(registry = globalThis.__esbuild_hmr__)) {
+				registry = globalThis.__esbuild_hmr__ = { factories: {}, modules: {}, data: {}, stack: [] }
+				if (typeof document !== 'undefined' && typeof EventSource !== 'undefined') {
+					registry.isClassic = !!document.currentScript
+					new EventSource('/esbuild').addEventListener('hmr', e => {
+						registry.queue = (registry.queue || Promise.resolve()).then(() => {
+							var updated = JSON.parse(e.data).updated, i = 0
+							var next = () => i < updated.length && __hmrApply(registry, updated[i].modules, updated[i++].url).then(next)
+							return next()
+						}).catch(err => {
+							console.error(err)
+							location.reload()
+						})
+					})
+				}
+			}
+			return registry
+		}
+		var __hmrRequire = (registry, id) => {
+			var stack = registry.stack, importer = stack[stack.length - 1], mod = registry.modules[id]
+			if (!mod) {
+				mod = registry.modules[id] = { id, exports: {}, importers: [], disposers: [] }
+				mod.hot = {
+					data: registry.data[id] || {},
+					accept: cb => {
+						if (typeof cb === 'function' || cb === void 0) mod.accept = cb || (() => {})
+						else console.warn('[esbuild] Only self-accepting modules are supported: ' + id)
+					},
+					dispose: cb => {
+						mod.disposers.push(cb)
+					},
+					invalidate: () => {
+						registry.queue = (registry.queue || Promise.resolve()).then(() => __hmrApply(registry, [id], null, id))
+					},
+				}
+				stack.push(id)
+				try {
+					registry.factories[id](mod.exports, mod)
+				} catch (err) {
+					delete registry.modules[id]
+					throw err
+				} finally {
+					stack.pop()
+				}
+			}
+			if (importer && mod.importers.indexOf(importer) < 0) mod.importers.push(importer)
+			return mod.exports
+		}
+		var __hmrApply = (registry, changed, url, invalidated) => {
+			// Walk up the importer chain from the changed modules until reaching a
+			// module that accepts the update. Reaching a module without importers
+			// means nothing can accept this update, so the page must be reloaded.
+			var stale = {}, boundaries = [], queue = changed.slice(), id, mod, i
+			while (queue.length) {
+				if (stale[id = queue.pop()] || !(mod = registry.modules[id])) continue
+				stale[id] = mod
+				if (mod.accept && id !== invalidated) boundaries.push(id)
+				else if (mod.importers.length) queue.push.apply(queue, mod.importers)
+				else return location.reload(), Promise.resolve()
+			}
+			if (!boundaries.length) return Promise.resolve()
+			return new Promise((resolve, reject) => {
+				if (!url) return resolve()
+				var script = document.createElement('script')
+				if (!registry.isClassic) script.type = 'module'
+				script.src = url + (url.indexOf('?') < 0 ? '?' : '&') + 'hmr=' + Date.now()
+				script.onload = resolve
+				script.onerror = reject
+				document.head.appendChild(script)
+			}).then(() => {
+				// Throw away the stale modules, keeping the data they want to preserve
+				for (id in stale) {
+					var data = {}
+					for (mod = stale[id], i = 0; i < mod.disposers.length; i++) mod.disposers[i](data)
+					registry.data[id] = data
+					delete registry.modules[id]
+				}
+				for (id in registry.modules)
+					registry.modules[id].importers = registry.modules[id].importers.filter(importer => !stale[importer])
+
+				// Evaluating the boundaries again also evaluates the changed modules
+				for (i = 0; i < boundaries.length; i++) {
+					var exports = __hmrRequire(registry, id = boundaries[i])
+					registry.modules[id].importers = stale[id].importers.filter(importer => !stale[importer])
+					stale[id].accept(exports)
+				}
+			})
+		}
+		export var __hmrModule = cb => {
+			var registry = __hmrRegistry(), id = __getOwnPropNames(cb)[0]
+			registry.factories[id] = cb[id]
+			return () => __hmrRequire(registry, id)
+		}
+		export var __hmrHot = id => __hmrRegistry().modules[id].hot
+		export var __markAsModule = target => __defProp(target, '__esModule', { value: true })
+
 		// Used to implement ESM exports both for "require()" and "import * as"
 		export var __export = (target, all) => {
 			for (var name in all)
@@ -262,6 +466,62 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 		}
 		export var __decorateParam = (index, decorator) => (target, key) => decorator(target, key, index)
 
+		// For TypeScript's "emitDecoratorMetadata" setting. This does nothing
+		// unless a polyfill such as "reflect-metadata" has been loaded.
+		export var __metadata = (key, value) =>
+			typeof Reflect === 'object' && typeof Reflect.metadata === 'function' && Reflect.metadata(key, value)
+
+		// For JavaScript decorators
+		// - kind === 0: class
+		// - kind === 1: method
+		// - kind === 2: getter
+		// - kind === 3: setter
+		// - kind === 4: accessor
+		// - kind === 5: field
+		// - flags & 8: static
+		// - flags & 16: private
+		var __decoratorStrings = ['class', 'method', 'getter', 'setter', 'accessor', 'field', 'value', 'get', 'set']
+		var __typeError = msg => {
+			throw TypeError(msg)
+		}
+		var __expectFn = fn => fn !== void 0 && typeof fn !== 'function' ? __typeError('Function expected') : fn
+		var __decoratorContext = (kind, name, done, metadata, fns) => ({
+			kind: __decoratorStrings[kind],
+			name,
+			metadata,
+			addInitializer: fn => done._ ? __typeError('Already initialized') : fns.push(__expectFn(fn || null)),
+		})
+		export var __decoratorStart = base => [, , , __create(base && base[__knownSymbol('metadata')] || null)]
+		export var __decoratorMetadata = (array, target) => __defNormalProp(target, __knownSymbol('metadata'), array[3])
+		export var __runInitializers = (array, flags, self, value) => {
+			for (var i = 0, fns = array[flags >> 1], n = fns && fns.length; i < n; i++)
+				flags & 1 ? fns[i].call(self) : value = fns[i].call(self, value)
+			return value
+		}
+		export var __decorateElement = (array, flags, name, decorators, target, extra) => {
+			var fn, it, done, ctx, access, kind = flags & 7, s = !!(flags & 8), p = !!(flags & 16)
+			var j = kind > 3 ? array.length + 1 : kind ? s ? 1 : 2 : 0, key = __decoratorStrings[kind + 5]
+			var initializers = kind > 3 && (array[j - 1] = []), extraInitializers = array[j] || (array[j] = [])
+			var obj = kind && !s && !p ? target.prototype : target
+			var desc = kind && kind < 5 && (p ? kind > 3 ? extra : {} : __getOwnPropDesc(obj, name))
+			if (p && kind < 4) desc[key] = extra
+			for (var i = decorators.length - 1; i >= 0; i--) {
+				ctx = __decoratorContext(kind, name, done = {}, array[3], extraInitializers)
+				if (kind) {
+					ctx.static = s, ctx.private = p, access = ctx.access = { has: p ? x => __privateIn(target, x) : x => name in x }
+					if (kind ^ 3) access.get = p ? x => kind ^ 1 ? __privateGet(x, target, desc && desc.get) : __privateMethod(x, target, desc.value) : x => x[name]
+					if (kind > 2) access.set = p ? (x, y) => __privateSet(x, target, y, desc && desc.set) : (x, y) => x[name] = y
+				}
+				it = (0, decorators[i])(kind ? kind < 4 ? desc[key] : kind > 4 ? void 0 : { get: desc.get, set: desc.set } : target, ctx), done._ = 1
+				if (kind ^ 4 || it === void 0) __expectFn(it) && (kind > 4 ? initializers.unshift(it) : kind ? desc[key] = it : target = it)
+				else if (typeof it !== 'object' || it === null) __typeError('Object expected')
+				else __expectFn(fn = it.get) && (desc.get = fn), __expectFn(fn = it.set) && (desc.set = fn), __expectFn(fn = it.init) && initializers.unshift(fn)
+			}
+			if (!kind) __decoratorMetadata(array, target)
+			else if (desc && !p) __defProp(obj, name, desc)
+			return p && kind < 4 ? desc[key] : p && kind > 3 ? desc : target
+		}
+
 		// For class members
 		export var __publicField = (obj, key, value) => {
 			__defNormalProp(obj, typeof key !== 'symbol' ? key + '' : key, value)
@@ -315,10 +575,29 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 		}
 
 		// For "super" property accesses
-		export var __superGet = (cls, obj, key) => __reflectGet(__getProtoOf(cls), key, obj)
-		export var __superSet = (cls, obj, key, val) => (__reflectSet(__getProtoOf(cls), key, val, obj), val)
 	`
 
+	// Avoid "Reflect" when not using ES6
+	if !unsupportedJSFeatures.Has(compat.Class) {
+		text += `
+			export var __superGet = (cls, obj, key) => __reflectGet(__getProtoOf(cls), key, obj)
+			export var __superSet = (cls, obj, key, val) => (__reflectSet(__getProtoOf(cls), key, val, obj), val)
+		`
+	} else {
+		text += `
+			var __superProp = (cls, key, desc) => {
+				for (var proto = __getProtoOf(cls); proto; proto = __getProtoOf(proto))
+					if (desc = __getOwnPropDesc(proto, key)) return desc
+			}
+			export var __superGet = (cls, obj, key, desc) => (desc = __superProp(cls, key))
+				? desc.get ? desc.get.call(obj) : desc.value
+				: void 0
+			export var __superSet = (cls, obj, key, val, desc) => ((desc = __superProp(cls, key)) && desc.set
+				? desc.set.call(obj, val)
+				: obj[key] = val, val)
+		`
+	}
+
 	if !unsupportedJSFeatures.Has(compat.ObjectAccessors) {
 		text += `
 			export var __superWrapper = (cls, obj, key) => ({
@@ -336,9 +615,142 @@ func Source(unsupportedJSFeatures compat.JSFeature) logger.Source {
 	}
 
 	text += `
+		// For lowering classes to ES5 constructor functions
+		var __setProtoOf = Object.setPrototypeOf
+		var __reflectConstruct = typeof Reflect == 'object' && Reflect.construct
+		export var __inherits = (child, parent) => {
+			if (typeof parent != 'function' && parent !== null)
+				throw TypeError('Class extends value ' + String(parent) + ' is not a constructor or null')
+			child.prototype = __create(parent && parent.prototype, { constructor: { value: child, writable: true, configurable: true } })
+			if (parent) {
+				if (__setProtoOf) __setProtoOf(child, parent)
+				else for (var key in parent) if (__hasOwnProp.call(parent, key)) child[key] = parent[key]
+			}
+		}
+		export var __callSuper = (self, parent, args) => {
+			// Use "Reflect.construct" if possible so that built-ins such as "Array"
+			// and "Error" (and classes that weren't lowered) can be extended
+			var result = __reflectConstruct
+				? __reflectConstruct(parent, args || [], __getProtoOf(self).constructor)
+				: parent.apply(self, args)
+			return result !== null && (typeof result == 'object' || typeof result == 'function') ? result : self
+		}
+		export var __classCallCheck = (self, cls) => {
+			if (!(self instanceof cls)) throw TypeError('Cannot call a class as a function')
+		}
+		export var __method = (obj, key, value) => __defProp(obj, key, { value, writable: true, configurable: true })
+		export var __getter = (obj, key, get) => __defProp(obj, key, { get, configurable: true })
+		export var __setter = (obj, key, set) => __defProp(obj, key, { set, configurable: true })
+
 		// For lowering tagged template literals
 		export var __template = (cooked, raw) => __freeze(__defProp(cooked, 'raw', { value: __freeze(raw || cooked.slice()) }))
 
+		// This helps for lowering generator functions to a state machine. The body
+		// is called with the state of the machine and returns an instruction:
+		//
+		//   [2, value]  return "value"
+		//   [3, label]  jump to "label"
+		//   [4, value]  yield "value" and resume at the next label
+		//   [5, value]  yield* "value" and resume at the next label
+		//   [7]         end of a "finally" block
+		//
+		// Opcodes 0, 1, and 6 are used internally for "next()", "throw()", and
+		// exceptions. Each entry in "trys" is "[try, catch, finally, end]" where
+		// each element is a label. Operations that are interrupted by a "finally"
+		// block are saved in "ops" and are resumed at the end of that block.
+		export var __stateMachine = (__this, body) => {
+			var running, started, done, delegate, sent, method, result, entry
+			var state = {
+				label: 0,
+				trys: [],
+				ops: [],
+				sent: () => {
+					if (sent[0] === 1) throw sent[1]
+					return sent[1]
+				},
+			}
+			var step = op => {
+				if (running) throw TypeError('Generator is already running')
+				if (!started && (started = 1, op[0])) done = 1
+				while (!done) {
+					running = 1
+					try {
+						if (delegate) {
+							if (method = delegate[op[0] === 2 ? 'return' : op[0] ? 'throw' : 'next']) {
+								if (!(result = method.call(delegate, op[1])).done) return result
+								op = [op[0] === 2 ? 2 : 0, result.value]
+							} else if (op[0] === 1) {
+								delegate.return && delegate.return()
+								op = [1, TypeError('Iterator does not have a "throw" method')]
+							}
+							delegate = 0
+						}
+						switch (op[0]) {
+							case 0:
+							case 1:
+								sent = op
+								break
+							case 4:
+								state.label++
+								return { value: op[1], done: false }
+							case 5:
+								state.label++
+								delegate = op[1]
+								if (method = typeof Symbol === 'function' && delegate[Symbol.iterator]) delegate = method.call(delegate)
+								else if (typeof delegate.next !== 'function') {
+									var array = delegate, i = 0
+									delegate = { next: () => ({ value: array[i], done: i++ >= array.length }) }
+								}
+								op = [0]
+								continue
+							case 7:
+								op = state.ops.pop()
+								state.trys.pop()
+								continue
+							default:
+								entry = state.trys[state.trys.length - 1]
+								if (op[0] === 3 && (!entry || op[1] > entry[0] && op[1] < entry[3])) {
+									state.label = op[1]
+									break
+								}
+								if (!entry) {
+									done = 1
+									continue
+								}
+								if (op[0] === 6 && state.label < entry[1]) {
+									state.label = entry[1]
+									sent = op
+									break
+								}
+								if (state.label < entry[2]) {
+									state.label = entry[2]
+									state.ops.push(op)
+									break
+								}
+								if (entry[2]) state.ops.pop()
+								state.trys.pop()
+								continue
+						}
+						op = body.call(__this, state)
+					} catch (e) {
+						op = [6, e]
+						delegate = 0
+					} finally {
+						running = 0
+					}
+				}
+				if (op[0] & 5) throw op[1]
+				return { value: op[0] ? op[1] : void 0, done: true }
+			}
+			var it = {
+				next: value => step([0, value]),
+				throw: value => step([1, value]),
+				return: value => step([2, value]),
+			}
+			if (typeof Symbol === 'function') it[Symbol.iterator] = () => it
+			return it
+		}
+
 		// This helps for lowering async functions
 		export var __async = (__this, __arguments, generator) => {
 			return new Promise((resolve, reject) => {
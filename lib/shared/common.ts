@@ -162,6 +162,7 @@ function pushCommonFlags(flags: string[], options: CommonOptions, keys: OptionKe
   let supported = getFlag(options, keys, 'supported', mustBeObject)
   let pure = getFlag(options, keys, 'pure', mustBeArray)
   let keepNames = getFlag(options, keys, 'keepNames', mustBeBoolean)
+  let looseForOf = getFlag(options, keys, 'looseForOf', mustBeBoolean)
   let platform = getFlag(options, keys, 'platform', mustBeString)
   let tsconfigRaw = getFlag(options, keys, 'tsconfigRaw', mustBeStringOrObject)
 
@@ -220,6 +221,7 @@ function pushCommonFlags(flags: string[], options: CommonOptions, keys: OptionKe
   }
   if (pure) for (let fn of pure) flags.push(`--pure:${validateStringValue(fn, 'pure')}`)
   if (keepNames) flags.push(`--keep-names`)
+  if (looseForOf) flags.push(`--loose-for-of`)
 }
 
 function flagsForBuildOptions(
@@ -249,12 +251,21 @@ function flagsForBuildOptions(
   let sourcemap = getFlag(options, keys, 'sourcemap', mustBeStringOrBoolean)
   let bundle = getFlag(options, keys, 'bundle', mustBeBoolean)
   let splitting = getFlag(options, keys, 'splitting', mustBeBoolean)
+  let preserveModules = getFlag(options, keys, 'preserveModules', mustBeBoolean)
+  let vendorDir = getFlag(options, keys, 'vendorDir', mustBeString)
+  let hmr = getFlag(options, keys, 'hmr', mustBeBoolean)
+  let declaration = getFlag(options, keys, 'declaration', mustBeBoolean)
   let preserveSymlinks = getFlag(options, keys, 'preserveSymlinks', mustBeBoolean)
+  let dedupe = getFlag(options, keys, 'dedupe', mustBeBoolean)
+  let treeShakeCommonJS = getFlag(options, keys, 'treeShakeCommonJS', mustBeBoolean)
   let metafile = getFlag(options, keys, 'metafile', mustBeBoolean)
+  let metafileWhy = getFlag(options, keys, 'metafileWhy', mustBeBoolean)
   let outfile = getFlag(options, keys, 'outfile', mustBeString)
   let outdir = getFlag(options, keys, 'outdir', mustBeString)
   let outbase = getFlag(options, keys, 'outbase', mustBeString)
+  let cacheDir = getFlag(options, keys, 'cacheDir', mustBeString)
   let tsconfig = getFlag(options, keys, 'tsconfig', mustBeString)
+  let importMap = getFlag(options, keys, 'importMap', mustBeStringOrObject)
   let resolveExtensions = getFlag(options, keys, 'resolveExtensions', mustBeArray)
   let nodePathsInput = getFlag(options, keys, 'nodePaths', mustBeArray)
   let mainFields = getFlag(options, keys, 'mainFields', mustBeArray)
@@ -262,12 +273,15 @@ function flagsForBuildOptions(
   let external = getFlag(options, keys, 'external', mustBeArray)
   let packages = getFlag(options, keys, 'packages', mustBeString)
   let alias = getFlag(options, keys, 'alias', mustBeObject)
+  let umdGlobals = getFlag(options, keys, 'umdGlobals', mustBeObject)
   let loader = getFlag(options, keys, 'loader', mustBeObject)
   let outExtension = getFlag(options, keys, 'outExtension', mustBeObject)
   let publicPath = getFlag(options, keys, 'publicPath', mustBeString)
   let entryNames = getFlag(options, keys, 'entryNames', mustBeString)
   let chunkNames = getFlag(options, keys, 'chunkNames', mustBeString)
   let assetNames = getFlag(options, keys, 'assetNames', mustBeString)
+  let manualChunks = getFlag(options, keys, 'manualChunks', mustBeObject)
+  let minChunkSize = getFlag(options, keys, 'minChunkSize', mustBeInteger)
   let inject = getFlag(options, keys, 'inject', mustBeArray)
   let banner = getFlag(options, keys, 'banner', mustBeObject)
   let footer = getFlag(options, keys, 'footer', mustBeObject)
@@ -284,12 +298,21 @@ function flagsForBuildOptions(
   if (bundle) flags.push('--bundle')
   if (allowOverwrite) flags.push('--allow-overwrite')
   if (splitting) flags.push('--splitting')
+  if (preserveModules) flags.push('--preserve-modules')
+  if (vendorDir) flags.push(`--vendor-dir=${vendorDir}`)
+  if (hmr) flags.push('--hmr')
+  if (declaration) flags.push('--declaration')
   if (preserveSymlinks) flags.push('--preserve-symlinks')
+  if (dedupe) flags.push('--dedupe')
+  if (treeShakeCommonJS) flags.push('--tree-shake-commonjs')
   if (metafile) flags.push(`--metafile`)
+  if (metafileWhy) flags.push(`--metafile-why`)
   if (outfile) flags.push(`--outfile=${outfile}`)
   if (outdir) flags.push(`--outdir=${outdir}`)
   if (outbase) flags.push(`--outbase=${outbase}`)
+  if (cacheDir) flags.push(`--cache-dir=${cacheDir}`)
   if (tsconfig) flags.push(`--tsconfig=${tsconfig}`)
+  if (importMap) flags.push(`--import-map=${typeof importMap === 'string' ? importMap : JSON.stringify(importMap)}`)
   if (packages) flags.push(`--packages=${packages}`)
   if (resolveExtensions) {
     let values: string[] = []
@@ -304,6 +327,15 @@ function flagsForBuildOptions(
   if (entryNames) flags.push(`--entry-names=${entryNames}`)
   if (chunkNames) flags.push(`--chunk-names=${chunkNames}`)
   if (assetNames) flags.push(`--asset-names=${assetNames}`)
+  if (manualChunks) {
+    for (let name in manualChunks) {
+      if (name.indexOf('=') >= 0) throw new Error(`Invalid manual chunk name: ${name}`)
+      let paths = manualChunks[name]
+      if (!Array.isArray(paths)) throw new Error(`Expected value for manual chunk ${quote(name)} to be an array`)
+      for (let path of paths) flags.push(`--manual-chunk:${name}=${validateStringValue(path, 'manual chunk', name)}`)
+    }
+  }
+  if (minChunkSize) flags.push(`--min-chunk-size=${minChunkSize}`)
   if (mainFields) {
     let values: string[] = []
     for (let value of mainFields) {
@@ -329,6 +361,12 @@ function flagsForBuildOptions(
       flags.push(`--alias:${old}=${validateStringValue(alias[old], 'alias', old)}`)
     }
   }
+  if (umdGlobals) {
+    for (let path in umdGlobals) {
+      if (path.indexOf('=') >= 0) throw new Error(`Invalid module path in umdGlobals: ${path}`)
+      flags.push(`--umd-global:${path}=${validateStringValue(umdGlobals[path], 'umdGlobals', path)}`)
+    }
+  }
   if (banner) {
     for (let type in banner) {
       if (type.indexOf('=') >= 0) throw new Error(`Invalid banner file type: ${type}`)
@@ -826,6 +864,7 @@ export function createChannel(streamIn: StreamIn): StreamOut {
     let keys: OptionKeys = {}
     let color = getFlag(options, keys, 'color', mustBeBoolean)
     let verbose = getFlag(options, keys, 'verbose', mustBeBoolean)
+    let why = getFlag(options, keys, 'why', mustBeString)
     checkForInvalidFlags(options, keys, `in ${callName}() call`)
     let request: protocol.AnalyzeMetafileRequest = {
       command: 'analyze-metafile',
@@ -833,6 +872,7 @@ export function createChannel(streamIn: StreamIn): StreamOut {
     }
     if (color !== void 0) request.color = color
     if (verbose !== void 0) request.verbose = verbose
+    if (why !== void 0) request.why = why
     sendRequest<protocol.AnalyzeMetafileRequest, protocol.AnalyzeMetafileResponse>(refs, request, (error, response) => {
       if (error) return callback(new Error(error), null)
       callback(null, response!.result)
@@ -1077,11 +1117,13 @@ function buildOrContextImpl(
         watch: (options = {}) => new Promise((resolve, reject) => {
           if (!streamIn.hasFS) throw new Error(`Cannot use the "watch" API in this environment`)
           const keys: OptionKeys = {}
+          const mode = getFlag(options, keys, 'mode', mustBeString)
           checkForInvalidFlags(options, keys, `in watch() call`)
           const request: protocol.WatchRequest = {
             command: 'watch',
             key: buildKey,
           }
+          if (mode !== void 0) request.mode = mode
           sendRequest<protocol.WatchRequest, null>(refs, request, error => {
             if (error) reject(new Error(error))
             else resolve(undefined)
@@ -97,6 +97,7 @@ export interface CancelRequest {
 export interface WatchRequest {
   command: 'watch'
   key: number
+  mode?: string
 }
 
 export interface OnServeRequest {
@@ -144,6 +145,7 @@ export interface AnalyzeMetafileRequest {
   metafile: string
   color?: boolean
   verbose?: boolean
+  why?: string
 }
 
 export interface AnalyzeMetafileResponse {
@@ -1,6 +1,6 @@
 export type Platform = 'browser' | 'node' | 'neutral'
-export type Format = 'iife' | 'cjs' | 'esm'
-export type Loader = 'base64' | 'binary' | 'copy' | 'css' | 'dataurl' | 'default' | 'empty' | 'file' | 'js' | 'json' | 'jsx' | 'local-css' | 'text' | 'ts' | 'tsx'
+export type Format = 'iife' | 'cjs' | 'esm' | 'amd' | 'umd' | 'system'
+export type Loader = 'base64' | 'binary' | 'copy' | 'css' | 'dataurl' | 'default' | 'empty' | 'file' | 'html' | 'js' | 'json' | 'jsx' | 'local-css' | 'text' | 'ts' | 'tsx'
 export type LogLevel = 'verbose' | 'debug' | 'info' | 'warning' | 'error' | 'silent'
 export type Charset = 'ascii' | 'utf8'
 export type Drop = 'console' | 'debugger'
@@ -74,6 +74,8 @@ interface CommonOptions {
   pure?: string[]
   /** Documentation: https://esbuild.github.io/api/#keep-names */
   keepNames?: boolean
+  /** Documentation: https://esbuild.github.io/api/#loose-for-of */
+  looseForOf?: boolean
 
   /** Documentation: https://esbuild.github.io/api/#color */
   color?: boolean
@@ -92,6 +94,7 @@ export interface TsconfigRaw {
   compilerOptions?: {
     alwaysStrict?: boolean
     baseUrl?: string
+    emitDecoratorMetadata?: boolean
     experimentalDecorators?: boolean
     importsNotUsedAsValues?: 'remove' | 'preserve' | 'error'
     jsx?: 'preserve' | 'react-native' | 'react' | 'react-jsx' | 'react-jsxdev'
@@ -107,27 +110,50 @@ export interface TsconfigRaw {
   }
 }
 
+export interface ImportMap {
+  imports?: Record<string, string | null>
+  scopes?: Record<string, Record<string, string | null>>
+}
+
 export interface BuildOptions extends CommonOptions {
   /** Documentation: https://esbuild.github.io/api/#bundle */
   bundle?: boolean
   /** Documentation: https://esbuild.github.io/api/#splitting */
   splitting?: boolean
+  /** Documentation: https://esbuild.github.io/api/#preserve-modules */
+  preserveModules?: boolean
+  /** Documentation: https://esbuild.github.io/api/#vendor-dir */
+  vendorDir?: string
+  /** Documentation: https://esbuild.github.io/api/#hmr */
+  hmr?: boolean
+  /** Documentation: https://esbuild.github.io/api/#declaration */
+  declaration?: boolean
   /** Documentation: https://esbuild.github.io/api/#preserve-symlinks */
   preserveSymlinks?: boolean
+  /** Documentation: https://esbuild.github.io/api/#dedupe */
+  dedupe?: boolean
+  /** Documentation: https://esbuild.github.io/api/#tree-shake-commonjs */
+  treeShakeCommonJS?: boolean
   /** Documentation: https://esbuild.github.io/api/#outfile */
   outfile?: string
   /** Documentation: https://esbuild.github.io/api/#metafile */
   metafile?: boolean
+  /** Documentation: https://esbuild.github.io/api/#metafile-why */
+  metafileWhy?: boolean
   /** Documentation: https://esbuild.github.io/api/#outdir */
   outdir?: string
   /** Documentation: https://esbuild.github.io/api/#outbase */
   outbase?: string
+  /** Documentation: https://esbuild.github.io/api/#cache-dir */
+  cacheDir?: string
   /** Documentation: https://esbuild.github.io/api/#external */
   external?: string[]
   /** Documentation: https://esbuild.github.io/api/#packages */
   packages?: 'external'
   /** Documentation: https://esbuild.github.io/api/#alias */
   alias?: Record<string, string>
+  /** Documentation: https://esbuild.github.io/api/#umd-globals */
+  umdGlobals?: Record<string, string>
   /** Documentation: https://esbuild.github.io/api/#loader */
   loader?: { [ext: string]: Loader }
   /** Documentation: https://esbuild.github.io/api/#resolve-extensions */
@@ -142,6 +168,8 @@ export interface BuildOptions extends CommonOptions {
   allowOverwrite?: boolean
   /** Documentation: https://esbuild.github.io/api/#tsconfig */
   tsconfig?: string
+  /** Documentation: https://esbuild.github.io/api/#import-map */
+  importMap?: string | ImportMap
   /** Documentation: https://esbuild.github.io/api/#out-extension */
   outExtension?: { [ext: string]: string }
   /** Documentation: https://esbuild.github.io/api/#public-path */
@@ -152,6 +180,10 @@ export interface BuildOptions extends CommonOptions {
   chunkNames?: string
   /** Documentation: https://esbuild.github.io/api/#asset-names */
   assetNames?: string
+  /** Documentation: https://esbuild.github.io/api/#manual-chunks */
+  manualChunks?: { [name: string]: string[] }
+  /** Documentation: https://esbuild.github.io/api/#min-chunk-size */
+  minChunkSize?: number
   /** Documentation: https://esbuild.github.io/api/#inject */
   inject?: string[]
   /** Documentation: https://esbuild.github.io/api/#banner */
@@ -389,12 +421,17 @@ export type ImportKind =
   | 'require-call'
   | 'dynamic-import'
   | 'require-resolve'
+  | 'new-worker'
+  | 'new-url'
 
   // CSS
   | 'import-rule'
   | 'composes-from'
   | 'url-token'
 
+  // HTML
+  | 'html-attribute'
+
 /** Documentation: https://esbuild.github.io/plugins/#on-resolve-results */
 export interface OnResolveResult {
   pluginName?: string
@@ -480,6 +517,11 @@ export interface Metafile {
       inputs: {
         [path: string]: {
           bytesInOutput: number
+          /** Only when "metafileWhy: true" */
+          why?: {
+            importChain: string[]
+            usedExports?: string[]
+          }
         }
       }
       imports: {
@@ -492,6 +534,12 @@ export interface Metafile {
       cssBundle?: string
     }
   }
+  /** Only when "dedupe" is enabled and some packages have multiple versions */
+  duplicatePackages?: {
+    [name: string]: {
+      [version: string]: string
+    }
+  }
 }
 
 export interface FormatMessagesOptions {
@@ -503,9 +551,13 @@ export interface FormatMessagesOptions {
 export interface AnalyzeMetafileOptions {
   color?: boolean
   verbose?: boolean
+  /** Explain why this input file is included instead of printing the summary */
+  why?: string
 }
 
 export interface WatchOptions {
+  /** Documentation: https://esbuild.github.io/api/#watch-mode */
+  mode?: 'native' | 'poll'
 }
 
 export interface BuildContext<ProvidedOptions extends BuildOptions = BuildOptions> {
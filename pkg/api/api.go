@@ -147,6 +147,7 @@ const (
 	LoaderEmpty
 	LoaderFile
 	LoaderGlobalCSS
+	LoaderHTML
 	LoaderJS
 	LoaderJSON
 	LoaderJSX
@@ -172,6 +173,9 @@ const (
 	FormatIIFE
 	FormatCommonJS
 	FormatESModule
+	FormatAMD
+	FormatUMD
+	FormatSystemJS
 )
 
 type Packages uint8
@@ -279,6 +283,8 @@ type BuildOptions struct {
 	Engines   []Engine        // Documentation: https://esbuild.github.io/api/#target
 	Supported map[string]bool // Documentation: https://esbuild.github.io/api/#supported
 
+	LooseForOf bool // Documentation: https://esbuild.github.io/api/#loose-for-of
+
 	MangleProps       string                 // Documentation: https://esbuild.github.io/api/#mangle-props
 	ReserveProps      string                 // Documentation: https://esbuild.github.io/api/#mangle-props
 	MangleQuoted      MangleQuoted           // Documentation: https://esbuild.github.io/api/#mangle-props
@@ -308,23 +314,33 @@ type BuildOptions struct {
 	GlobalName        string            // Documentation: https://esbuild.github.io/api/#global-name
 	Bundle            bool              // Documentation: https://esbuild.github.io/api/#bundle
 	PreserveSymlinks  bool              // Documentation: https://esbuild.github.io/api/#preserve-symlinks
+	Dedupe            bool              // Documentation: https://esbuild.github.io/api/#dedupe
+	TreeShakeCommonJS bool              // Documentation: https://esbuild.github.io/api/#tree-shake-commonjs
 	Splitting         bool              // Documentation: https://esbuild.github.io/api/#splitting
+	PreserveModules   bool              // Documentation: https://esbuild.github.io/api/#preserve-modules
+	VendorDir         string            // Documentation: https://esbuild.github.io/api/#vendor-dir
+	HMR               bool              // Documentation: https://esbuild.github.io/api/#hmr
+	Declaration       bool              // Documentation: https://esbuild.github.io/api/#declaration
 	Outfile           string            // Documentation: https://esbuild.github.io/api/#outfile
 	Metafile          bool              // Documentation: https://esbuild.github.io/api/#metafile
+	MetafileWhy       bool              // Documentation: https://esbuild.github.io/api/#metafile-why
 	Outdir            string            // Documentation: https://esbuild.github.io/api/#outdir
 	Outbase           string            // Documentation: https://esbuild.github.io/api/#outbase
 	AbsWorkingDir     string            // Documentation: https://esbuild.github.io/api/#working-directory
+	CacheDir          string            // Documentation: https://esbuild.github.io/api/#cache-dir
 	Platform          Platform          // Documentation: https://esbuild.github.io/api/#platform
 	Format            Format            // Documentation: https://esbuild.github.io/api/#format
 	External          []string          // Documentation: https://esbuild.github.io/api/#external
 	Packages          Packages          // Documentation: https://esbuild.github.io/api/#packages
 	Alias             map[string]string // Documentation: https://esbuild.github.io/api/#alias
+	UMDGlobals        map[string]string // Documentation: https://esbuild.github.io/api/#umd-globals
 	MainFields        []string          // Documentation: https://esbuild.github.io/api/#main-fields
 	Conditions        []string          // Documentation: https://esbuild.github.io/api/#conditions
 	Loader            map[string]Loader // Documentation: https://esbuild.github.io/api/#loader
 	ResolveExtensions []string          // Documentation: https://esbuild.github.io/api/#resolve-extensions
 	Tsconfig          string            // Documentation: https://esbuild.github.io/api/#tsconfig
 	TsconfigRaw       string            // Documentation: https://esbuild.github.io/api/#tsconfig-raw
+	ImportMap         string            // Documentation: https://esbuild.github.io/api/#import-map
 	OutExtension      map[string]string // Documentation: https://esbuild.github.io/api/#out-extension
 	PublicPath        string            // Documentation: https://esbuild.github.io/api/#public-path
 	Inject            []string          // Documentation: https://esbuild.github.io/api/#inject
@@ -336,6 +352,10 @@ type BuildOptions struct {
 	ChunkNames string // Documentation: https://esbuild.github.io/api/#chunk-names
 	AssetNames string // Documentation: https://esbuild.github.io/api/#asset-names
 
+	ManualChunks     map[string][]string               // Documentation: https://esbuild.github.io/api/#manual-chunks
+	ManualChunksFunc func(args ManualChunkArgs) string // Documentation: https://esbuild.github.io/api/#manual-chunks
+	MinChunkSize     int                               // Documentation: https://esbuild.github.io/api/#min-chunk-size
+
 	EntryPoints         []string     // Documentation: https://esbuild.github.io/api/#entry-points
 	EntryPointsAdvanced []EntryPoint // Documentation: https://esbuild.github.io/api/#entry-points
 
@@ -350,6 +370,13 @@ type EntryPoint struct {
 	OutputPath string
 }
 
+// Return the name of the chunk to put this file in, or an empty string to use
+// the "ManualChunks" patterns and then the default code splitting behavior
+type ManualChunkArgs struct {
+	Path      string
+	Namespace string
+}
+
 type StdinOptions struct {
 	Contents   string
 	ResolveDir string
@@ -410,6 +437,8 @@ type TransformOptions struct {
 	Engines   []Engine        // Documentation: https://esbuild.github.io/api/#target
 	Supported map[string]bool // Documentation: https://esbuild.github.io/api/#supported
 
+	LooseForOf bool // Documentation: https://esbuild.github.io/api/#loose-for-of
+
 	Platform   Platform // Documentation: https://esbuild.github.io/api/#platform
 	Format     Format   // Documentation: https://esbuild.github.io/api/#format
 	GlobalName string   // Documentation: https://esbuild.github.io/api/#global-name
@@ -493,8 +522,24 @@ type ServeResult struct {
 }
 
 type WatchOptions struct {
+	Mode WatchMode
 }
 
+type WatchMode uint8
+
+const (
+	// Use the operating system's file system events where available (currently
+	// only Linux) and fall back to polling otherwise
+	WatchModeDefault WatchMode = iota
+
+	// Always poll the file system for changes
+	WatchModePoll
+
+	// Always use the operating system's file system events, and fail to start
+	// watch mode if they aren't available
+	WatchModeNative
+)
+
 type BuildContext interface {
 	// Documentation: https://esbuild.github.io/api/#rebuild
 	Rebuild() BuildResult
@@ -677,6 +722,9 @@ const (
 	ResolveCSSImportRule
 	ResolveCSSComposesFrom
 	ResolveCSSURLToken
+	ResolveHTMLAttribute
+	ResolveJSNewWorker
+	ResolveJSNewURL
 )
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -705,9 +753,33 @@ func FormatMessages(msgs []Message, opts FormatMessagesOptions) []string {
 type AnalyzeMetafileOptions struct {
 	Color   bool
 	Verbose bool
+
+	// If this is set, explain why this input file is included in each output
+	// file instead of printing the size of every file. This uses the data from
+	// the "MetafileWhy" build option.
+	Why string
 }
 
 // Documentation: https://esbuild.github.io/api/#analyze
 func AnalyzeMetafile(metafile string, opts AnalyzeMetafileOptions) string {
 	return analyzeMetafileImpl(metafile, opts)
 }
+
+////////////////////////////////////////////////////////////////////////////////
+// CompareMetafiles API
+
+type CompareMetafilesOptions struct {
+	Color bool
+
+	// If true, the comparison is returned as JSON instead of as a human-readable
+	// report. This is intended for tools that post the comparison somewhere.
+	JSON bool
+}
+
+// This compares the metafiles from two builds (e.g. before and after a change)
+// and reports the change in size of each output file and input file, the
+// packages that were added or removed, and the input files that moved between
+// output files. The result is empty if either metafile can't be parsed.
+func CompareMetafiles(before string, after string, opts CompareMetafilesOptions) string {
+	return compareMetafilesImpl(before, after, opts)
+}
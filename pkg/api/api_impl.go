@@ -128,6 +128,12 @@ func validateFormat(value Format) config.Format {
 		return config.FormatCommonJS
 	case FormatESModule:
 		return config.FormatESModule
+	case FormatAMD:
+		return config.FormatAMD
+	case FormatUMD:
+		return config.FormatUMD
+	case FormatSystemJS:
+		return config.FormatSystemJS
 	default:
 		panic("Invalid format")
 	}
@@ -219,13 +225,14 @@ func validateASCIIOnly(value Charset) bool {
 func validateTreeShaking(value TreeShaking, bundle bool, format Format) bool {
 	switch value {
 	case TreeShakingDefault:
-		// If we're in an IIFE then there's no way to concatenate additional code
-		// to the end of our output so we assume tree shaking is safe. And when
+		// If we're in an IIFE (or in a similar wrapper such as AMD, UMD, or
+		// SystemJS) then there's no way to concatenate additional code to the
+		// end of our output so we assume tree shaking is safe. And when
 		// bundling we assume that tree shaking is safe because if you want to add
 		// code to the bundle, you should be doing that by including it in the
 		// bundle instead of concatenating it afterward, so we also assume tree
 		// shaking is safe then. Otherwise we assume tree shaking is not safe.
-		return bundle || format == FormatIIFE
+		return bundle || format == FormatIIFE || format == FormatAMD || format == FormatUMD || format == FormatSystemJS
 	case TreeShakingFalse:
 		return false
 	case TreeShakingTrue:
@@ -255,6 +262,8 @@ func validateLoader(value Loader) config.Loader {
 		return config.LoaderFile
 	case LoaderGlobalCSS:
 		return config.LoaderGlobalCSS
+	case LoaderHTML:
+		return config.LoaderHTML
 	case LoaderJS:
 		return config.LoaderJS
 	case LoaderJSON:
@@ -387,6 +396,23 @@ func validateGlobalName(log logger.Log, text string) []string {
 	return nil
 }
 
+func validateUMDGlobals(log logger.Log, globals map[string]string) map[string][]string {
+	if len(globals) == 0 {
+		return nil
+	}
+	valid := make(map[string][]string, len(globals))
+	for path, globalName := range globals {
+		if globalName == "" {
+			log.AddError(nil, logger.Range{}, fmt.Sprintf("Missing global variable for the external module %q", path))
+			continue
+		}
+		if result := validateGlobalName(log, globalName); result != nil {
+			valid[path] = result
+		}
+	}
+	return valid
+}
+
 func validateRegex(log logger.Log, what string, value string) *regexp.Regexp {
 	if value == "" {
 		return nil
@@ -435,6 +461,73 @@ func validateExternals(log logger.Log, fs fs.FS, paths []string) config.External
 	return result
 }
 
+func validateVendorDir(log logger.Log, dir string) string {
+	if dir == "" {
+		return "vendor"
+	}
+
+	// This must be a relative path that stays inside the output directory
+	dir = strings.TrimSuffix(strings.ReplaceAll(dir, "\\", "/"), "/")
+	for _, part := range strings.Split(dir, "/") {
+		if part == "" || part == "." || part == ".." || strings.ContainsRune(part, ':') {
+			log.AddError(nil, logger.Range{}, fmt.Sprintf("Invalid vendor directory: %q", dir))
+			return "vendor"
+		}
+	}
+	return dir
+}
+
+func validateManualChunks(log logger.Log, manualChunks map[string][]string) []config.ManualChunk {
+	if len(manualChunks) == 0 {
+		return nil
+	}
+
+	// Sort by name so the first match is deterministic
+	names := make([]string, 0, len(manualChunks))
+	for name := range manualChunks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := make([]config.ManualChunk, 0, len(names))
+
+	for _, name := range names {
+		if !config.IsValidManualChunkName(name) {
+			log.AddError(nil, logger.Range{}, fmt.Sprintf("Invalid manual chunk name: %q", name))
+			continue
+		}
+		chunk := config.ManualChunk{Name: name, Paths: make(map[string]bool)}
+
+		// Patterns are matched against paths relative to the working directory
+		// with forward slashes, which is how paths appear in the metafile
+		for _, path := range manualChunks[name] {
+			path = strings.TrimPrefix(strings.ReplaceAll(path, "\\", "/"), "./")
+			if index := strings.IndexByte(path, '*'); index != -1 {
+				if strings.ContainsRune(path[index+1:], '*') {
+					log.AddError(nil, logger.Range{}, fmt.Sprintf("Manual chunk path %q cannot have more than one \"*\" wildcard", path))
+				} else {
+					chunk.Patterns = append(chunk.Patterns, config.WildcardPattern{Prefix: path[:index], Suffix: path[index+1:]})
+				}
+			} else if path != "" {
+				// A path without a wildcard matches that file or anything in that directory
+				chunk.Paths[path] = true
+				chunk.Patterns = append(chunk.Patterns, config.WildcardPattern{Prefix: strings.TrimSuffix(path, "/") + "/"})
+			}
+		}
+		result = append(result, chunk)
+	}
+
+	return result
+}
+
+func validateManualChunksFunc(fn func(ManualChunkArgs) string) func(logger.Path) string {
+	if fn == nil {
+		return nil
+	}
+	return func(path logger.Path) string {
+		return fn(ManualChunkArgs{Path: path.Text, Namespace: path.Namespace})
+	}
+}
+
 func validateAlias(log logger.Log, fs fs.FS, alias map[string]string) map[string]string {
 	valid := make(map[string]string, len(alias))
 
@@ -878,6 +971,9 @@ func contextImpl(buildOpts BuildOptions) (*internalContext, []Message) {
 	onEndCallbacks, onDisposeCallbacks, finalizeBuildOptions := loadPlugins(&buildOpts, realFS, log, caches)
 	options, entryPoints := validateBuildOptions(buildOpts, log, realFS)
 	finalizeBuildOptions(&options)
+	if options.AbsCacheDir != "" {
+		caches.SetDiskCache(cache.MakeDiskCache(realFS, options.AbsCacheDir))
+	}
 	if buildOpts.AbsWorkingDir != absWorkingDir {
 		panic("Mutating \"AbsWorkingDir\" is not allowed")
 	}
@@ -974,7 +1070,7 @@ func (ctx *internalContext) rebuild() rebuildState {
 	var newHashes map[string]string
 	build.state, newHashes = rebuildImpl(args, oldHashes)
 	if handler != nil {
-		handler.broadcastBuildResult(build.state.result, newHashes)
+		handler.broadcastBuildResult(build.state.result, newHashes, build.state.hmrModuleHashes)
 	}
 	if watcher != nil {
 		watcher.setWatchData(build.state.watchData)
@@ -1050,9 +1146,20 @@ func (ctx *internalContext) Watch(options WatchOptions) error {
 		return errors.New("Watch mode has already been enabled")
 	}
 
+	// Use native file system events unless polling was requested. Fall back to
+	// polling if they aren't available unless native events were requested.
+	var native nativeWatcher
+	if options.Mode != WatchModePoll {
+		var err error
+		if native, err = newNativeWatcher(ctx.realFS); err != nil && options.Mode == WatchModeNative {
+			return fmt.Errorf("Cannot use native watch mode: %s", err.Error())
+		}
+	}
+
 	logLevel := ctx.args.logOptions.LogLevel
 	ctx.watcher = &watcher{
 		fs:        ctx.realFS,
+		native:    native,
 		shouldLog: logLevel == logger.LevelInfo || logLevel == logger.LevelDebug || logLevel == logger.LevelVerbose,
 		useColor:  ctx.args.logOptions.Color,
 		rebuild: func() fs.WatchData {
@@ -1249,15 +1356,24 @@ func validateBuildOptions(
 		IgnoreDCEAnnotations:  buildOpts.IgnoreAnnotations,
 		TreeShaking:           validateTreeShaking(buildOpts.TreeShaking, buildOpts.Bundle, buildOpts.Format),
 		GlobalName:            validateGlobalName(log, buildOpts.GlobalName),
-		CodeSplitting:         buildOpts.Splitting,
+		CodeSplitting:         buildOpts.Splitting || buildOpts.PreserveModules,
+		PreserveModules:       buildOpts.PreserveModules,
+		VendorDir:             validateVendorDir(log, buildOpts.VendorDir),
+		HotModuleReplacement:  buildOpts.HMR,
+		TSDeclaration:         buildOpts.Declaration,
 		OutputFormat:          validateFormat(buildOpts.Format),
 		AbsOutputFile:         validatePath(log, realFS, buildOpts.Outfile, "outfile path"),
 		AbsOutputDir:          validatePath(log, realFS, buildOpts.Outdir, "outdir path"),
 		AbsOutputBase:         validatePath(log, realFS, buildOpts.Outbase, "outbase path"),
+		AbsCacheDir:           validatePath(log, realFS, buildOpts.CacheDir, "cache directory"),
 		NeedsMetafile:         buildOpts.Metafile,
+		NeedsMetafileWhy:      buildOpts.Metafile && buildOpts.MetafileWhy,
 		EntryPathTemplate:     validatePathTemplate(buildOpts.EntryNames),
 		ChunkPathTemplate:     validatePathTemplate(buildOpts.ChunkNames),
 		AssetPathTemplate:     validatePathTemplate(buildOpts.AssetNames),
+		ManualChunks:          validateManualChunks(log, buildOpts.ManualChunks),
+		ManualChunkCallback:   validateManualChunksFunc(buildOpts.ManualChunksFunc),
+		MinChunkSize:          buildOpts.MinChunkSize,
 		OutputExtensionJS:     outJS,
 		OutputExtensionCSS:    outCSS,
 		ExtensionToLoader:     validateLoaders(log, buildOpts.Loader),
@@ -1265,11 +1381,13 @@ func validateBuildOptions(
 		ExternalSettings:      validateExternals(log, realFS, buildOpts.External),
 		ExternalPackages:      buildOpts.Packages == PackagesExternal,
 		PackageAliases:        validateAlias(log, realFS, buildOpts.Alias),
+		UMDGlobals:            validateUMDGlobals(log, buildOpts.UMDGlobals),
 		TSConfigPath:          validatePath(log, realFS, buildOpts.Tsconfig, "tsconfig path"),
 		TSConfigRaw:           buildOpts.TsconfigRaw,
 		MainFields:            buildOpts.MainFields,
 		PublicPath:            buildOpts.PublicPath,
 		KeepNames:             buildOpts.KeepNames,
+		LooseForOf:            buildOpts.LooseForOf,
 		InjectPaths:           append([]string{}, buildOpts.Inject...),
 		AbsNodePaths:          make([]string, len(buildOpts.NodePaths)),
 		JSBanner:              bannerJS,
@@ -1277,6 +1395,8 @@ func validateBuildOptions(
 		CSSBanner:             bannerCSS,
 		CSSFooter:             footerCSS,
 		PreserveSymlinks:      buildOpts.PreserveSymlinks,
+		DedupePackages:        buildOpts.Dedupe,
+		TreeShakeCommonJS:     buildOpts.TreeShakeCommonJS,
 	}
 	validateKeepNames(log, &options)
 	if buildOpts.Conditions != nil {
@@ -1316,6 +1436,9 @@ func validateBuildOptions(
 	if options.AbsOutputDir == "" && (entryPointCount > 1 || hasEntryPointWithWildcard) {
 		log.AddError(nil, logger.Range{},
 			"Must use \"outdir\" when there are multiple input files")
+	} else if options.AbsOutputDir == "" && options.PreserveModules {
+		log.AddError(nil, logger.Range{},
+			"Must use \"outdir\" when preserving modules")
 	} else if options.AbsOutputDir == "" && options.CodeSplitting {
 		log.AddError(nil, logger.Range{},
 			"Must use \"outdir\" when code splitting is enabled")
@@ -1358,6 +1481,9 @@ func validateBuildOptions(
 		if len(options.PackageAliases) > 0 {
 			log.AddError(nil, logger.Range{}, "Cannot use \"alias\" without \"bundle\"")
 		}
+		if options.PreserveModules {
+			log.AddError(nil, logger.Range{}, "Cannot use \"preserveModules\" without \"bundle\"")
+		}
 	} else if options.OutputFormat == config.FormatPreserve {
 		// If the format isn't specified, set the default format using the platform
 		switch options.Platform {
@@ -1382,9 +1508,39 @@ func validateBuildOptions(
 		options.Conditions = []string{"module"}
 	}
 
-	// Code splitting is experimental and currently only enabled for ES6 modules
-	if options.CodeSplitting && options.OutputFormat != config.FormatESModule {
-		log.AddError(nil, logger.Range{}, "Splitting currently only works with the \"esm\" format")
+	// Code splitting needs a format that can load other chunks
+	if options.CodeSplitting && (options.OutputFormat == config.FormatPreserve ||
+		options.OutputFormat == config.FormatAMD || options.OutputFormat == config.FormatUMD) {
+		what := "Splitting"
+		if options.PreserveModules {
+			what = "Preserving modules"
+		}
+		log.AddError(nil, logger.Range{}, what+" currently only works with the \"esm\", \"cjs\", \"iife\", and \"system\" formats")
+	}
+
+	if options.MinChunkSize < 0 {
+		log.AddError(nil, logger.Range{}, fmt.Sprintf("Invalid minimum chunk size: %d", options.MinChunkSize))
+	}
+
+	// Hot module replacement works by replacing modules in a single bundle
+	if options.HotModuleReplacement {
+		if options.Mode != config.ModeBundle {
+			log.AddError(nil, logger.Range{}, "Cannot use \"hmr\" without \"bundle\"")
+		} else if options.CodeSplitting {
+			log.AddError(nil, logger.Range{}, "Cannot use \"hmr\" with \"splitting\"")
+		}
+	}
+
+	// Declaration files are generated per file, so there's nothing to bundle them with
+	if options.TSDeclaration && options.Mode == config.ModeBundle {
+		log.AddError(nil, logger.Range{}, "Cannot use \"declaration\" with \"bundle\" (bundled declaration files are not supported yet)")
+	}
+
+	// The import map is either inline JSON or a path to a JSON file
+	if importMap := strings.TrimSpace(buildOpts.ImportMap); strings.HasPrefix(importMap, "{") {
+		options.ImportMapRaw = importMap
+	} else {
+		options.ImportMapPath = validatePath(log, realFS, buildOpts.ImportMap, "import map path")
 	}
 
 	// Code splitting is experimental and currently only enabled for ES6 modules
@@ -1423,6 +1579,10 @@ type rebuildState struct {
 	result    BuildResult
 	watchData fs.WatchData
 	options   config.Options
+
+	// For hot module replacement, this maps the path of each output file to
+	// the hashes of the modules in that output file
+	hmrModuleHashes map[string]map[string]string
 }
 
 func rebuildImpl(args rebuildArgs, oldHashes map[string]string) (rebuildState, map[string]string) {
@@ -1459,6 +1619,7 @@ func rebuildImpl(args rebuildArgs, oldHashes map[string]string) (rebuildState, m
 	// The new build summary remains the same as the old one when there are
 	// errors. A failed build shouldn't erase the previous successful build.
 	newHashes := oldHashes
+	var hmrModuleHashes map[string]map[string]string
 
 	// Stop now if there were errors
 	if !log.HasErrors() {
@@ -1493,6 +1654,12 @@ func rebuildImpl(args rebuildArgs, oldHashes map[string]string) (rebuildState, m
 					Hash:     hash,
 				}
 				newHashes[item.AbsPath] = hash
+				if item.HMRModuleHashes != nil {
+					if hmrModuleHashes == nil {
+						hmrModuleHashes = make(map[string]map[string]string)
+					}
+					hmrModuleHashes[item.AbsPath] = item.HMRModuleHashes
+				}
 			}
 
 			// Write output files before "OnEnd" callbacks run so they can expect
@@ -1632,9 +1799,10 @@ func rebuildImpl(args rebuildArgs, oldHashes map[string]string) (rebuildState, m
 	}
 
 	return rebuildState{
-		result:    result,
-		options:   args.options,
-		watchData: watchData,
+		result:          result,
+		options:         args.options,
+		watchData:       watchData,
+		hmrModuleHashes: hmrModuleHashes,
 	}, newHashes
 }
 
@@ -1707,6 +1875,7 @@ func transformImpl(input string, transformOpts TransformOptions) TransformResult
 		TreeShaking:           validateTreeShaking(transformOpts.TreeShaking, false /* bundle */, transformOpts.Format),
 		AbsOutputFile:         transformOpts.Sourcefile + "-out",
 		KeepNames:             transformOpts.KeepNames,
+		LooseForOf:            transformOpts.LooseForOf,
 		Stdin: &config.StdinInfo{
 			Loader:     validateLoader(transformOpts.Loader),
 			Contents:   input,
@@ -1849,6 +2018,12 @@ func importKindToResolveKind(kind ast.ImportKind) ResolveKind {
 		return ResolveCSSComposesFrom
 	case ast.ImportURL:
 		return ResolveCSSURLToken
+	case ast.ImportHTMLAttribute:
+		return ResolveHTMLAttribute
+	case ast.ImportWorker:
+		return ResolveJSNewWorker
+	case ast.ImportNewURL:
+		return ResolveJSNewURL
 	default:
 		panic("Internal error")
 	}
@@ -1872,6 +2047,12 @@ func resolveKindToImportKind(kind ResolveKind) ast.ImportKind {
 		return ast.ImportComposesFrom
 	case ResolveCSSURLToken:
 		return ast.ImportURL
+	case ResolveHTMLAttribute:
+		return ast.ImportHTMLAttribute
+	case ResolveJSNewWorker:
+		return ast.ImportWorker
+	case ResolveJSNewURL:
+		return ast.ImportNewURL
 	default:
 		panic("Internal error")
 	}
@@ -2192,6 +2373,10 @@ func analyzeMetafileImpl(metafile string, opts AnalyzeMetafileOptions) string {
 	source := logger.Source{Contents: metafile}
 
 	if result, ok := js_parser.ParseJSON(log, source, js_parser.JSONOptions{}); ok {
+		if opts.Why != "" {
+			return analyzeMetafileWhy(result, opts)
+		}
+
 		if outputs := getObjectPropertyObject(result, "outputs"); outputs != nil {
 			var entries metafileArray
 			var entryPoints []string
@@ -2446,6 +2631,137 @@ func analyzeMetafileImpl(metafile string, opts AnalyzeMetafileOptions) string {
 	return ""
 }
 
+// This explains why a single input file is included in each output file using
+// the data from the "MetafileWhy" build option
+func analyzeMetafileWhy(result js_ast.Expr, opts AnalyzeMetafileOptions) string {
+	type inclusion struct {
+		why           js_ast.Expr
+		output        string
+		bytesInOutput int
+	}
+
+	var colors logger.Colors
+	if opts.Color {
+		colors = logger.TerminalColors
+	}
+
+	// Prefer an exact match but also allow a path suffix to match
+	inclusionsForInput := make(map[string][]inclusion)
+	var exactInputs []string
+	var suffixInputs []string
+	if outputs := getObjectPropertyObject(result, "outputs"); outputs != nil {
+		for _, output := range outputs.Properties {
+			outputPath := helpers.UTF16ToString(output.Key.Data.(*js_ast.EString).Value)
+			if strings.HasSuffix(outputPath, ".map") {
+				continue
+			}
+			if inputs := getObjectPropertyObject(output.ValueOrNil, "inputs"); inputs != nil {
+				for _, input := range inputs.Properties {
+					inputPath := helpers.UTF16ToString(input.Key.Data.(*js_ast.EString).Value)
+					isExact := inputPath == opts.Why
+					if !isExact && !strings.HasSuffix(inputPath, "/"+strings.TrimPrefix(opts.Why, "./")) {
+						continue
+					}
+					if _, ok := inclusionsForInput[inputPath]; !ok {
+						if isExact {
+							exactInputs = append(exactInputs, inputPath)
+						} else {
+							suffixInputs = append(suffixInputs, inputPath)
+						}
+					}
+					bytesInOutput := 0
+					if bytes := getObjectPropertyNumber(input.ValueOrNil, "bytesInOutput"); bytes != nil {
+						bytesInOutput = int(bytes.Value)
+					}
+					inclusionsForInput[inputPath] = append(inclusionsForInput[inputPath], inclusion{
+						why:           getObjectProperty(input.ValueOrNil, "why"),
+						output:        outputPath,
+						bytesInOutput: bytesInOutput,
+					})
+				}
+			}
+		}
+	}
+
+	matches := exactInputs
+	if len(matches) == 0 {
+		matches = suffixInputs
+	}
+	sort.Strings(matches)
+
+	sb := strings.Builder{}
+	if len(matches) == 0 {
+		sb.WriteString(fmt.Sprintf("\n  %s%s%s is not included in any output file\n", colors.Bold, opts.Why, colors.Reset))
+		return sb.String()
+	}
+
+	for _, inputPath := range matches {
+		inclusions := inclusionsForInput[inputPath]
+		sort.SliceStable(inclusions, func(i int, j int) bool {
+			return inclusions[i].output < inclusions[j].output
+		})
+
+		count := "1 output file"
+		if len(inclusions) != 1 {
+			count = fmt.Sprintf("%d output files", len(inclusions))
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s%s%s is included in %s\n", colors.Bold, inputPath, colors.Reset, count))
+
+		for _, inclusion := range inclusions {
+			sb.WriteString(fmt.Sprintf("\n  %s%s%s %s(%s)%s\n", colors.Bold, inclusion.output, colors.Reset,
+				colors.Dim, strings.TrimSpace(prettyPrintByteCount(inclusion.bytesInOutput)), colors.Reset))
+
+			if inclusion.why.Data == nil {
+				sb.WriteString(fmt.Sprintf("   %sRebuild with the \"metafileWhy\" option to see why this file is included%s\n", colors.Dim, colors.Reset))
+				continue
+			}
+
+			// Print the import chain starting from the entry point
+			depth := 0
+			if chain := getObjectPropertyArray(inclusion.why, "importChain"); chain != nil {
+				for _, item := range chain.Items {
+					if str, ok := item.Data.(*js_ast.EString); ok {
+						sb.WriteString(fmt.Sprintf("   %s%s\n", whyIndent(depth), helpers.UTF16ToString(str.Value)))
+						depth++
+					}
+				}
+			}
+			if depth == 0 {
+				sb.WriteString(fmt.Sprintf("   %s %s(entry point)%s\n", inputPath, colors.Dim, colors.Reset))
+			} else {
+				sb.WriteString(fmt.Sprintf("   %s%s%s%s\n", whyIndent(depth), colors.Bold, inputPath, colors.Reset))
+			}
+
+			// Print the exports that are used, if this is a JavaScript file
+			if usedExports := getObjectPropertyArray(inclusion.why, "usedExports"); usedExports != nil {
+				var names []string
+				for _, item := range usedExports.Items {
+					if str, ok := item.Data.(*js_ast.EString); ok {
+						names = append(names, helpers.UTF16ToString(str.Value))
+					}
+				}
+				if len(names) == 0 {
+					if depth == 0 {
+						continue
+					}
+					sb.WriteString(fmt.Sprintf("   %sUsed exports: none%s\n", colors.Dim, colors.Reset))
+				} else {
+					sb.WriteString(fmt.Sprintf("   %sUsed exports:%s %s\n", colors.Dim, colors.Reset, strings.Join(names, ", ")))
+				}
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func whyIndent(depth int) string {
+	if depth == 0 {
+		return ""
+	}
+	return strings.Repeat(" ", 3*(depth-1)) + " └ "
+}
+
 func stripDirPrefix(path string, prefix string, allowedSlashes string) (string, bool) {
 	if strings.HasPrefix(path, prefix) {
 		pathLen := len(path)
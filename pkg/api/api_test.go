@@ -319,3 +319,155 @@ func TestFormatMessages(t *testing.T) {
 `,
 	)
 }
+
+func TestCompareMetafiles(t *testing.T) {
+	before := `{
+		"inputs": {},
+		"outputs": {
+			"out/entry.js": {
+				"entryPoint": "src/entry.js",
+				"inputs": {
+					"src/entry.js": { "bytesInOutput": 100 },
+					"node_modules/moment/index.js": { "bytesInOutput": 500 }
+				},
+				"bytes": 650
+			},
+			"out/entry.js.map": { "inputs": {}, "bytes": 1000 },
+			"out/chunk-AAAAAAAA.js": {
+				"inputs": {
+					"src/shared.js": { "bytesInOutput": 200 },
+					"src/util.js": { "bytesInOutput": 50 }
+				},
+				"bytes": 270
+			},
+			"out/old.js": {
+				"entryPoint": "src/old.js",
+				"inputs": {
+					"src/old.js": { "bytesInOutput": 30 }
+				},
+				"bytes": 40
+			}
+		}
+	}`
+	after := `{
+		"inputs": {},
+		"outputs": {
+			"out/entry.js": {
+				"entryPoint": "src/entry.js",
+				"inputs": {
+					"src/entry.js": { "bytesInOutput": 120 },
+					"node_modules/@scope/pkg/lib/index.js": { "bytesInOutput": 300 },
+					"src/util.js": { "bytesInOutput": 50 }
+				},
+				"bytes": 520
+			},
+			"out/chunk-BBBBBBBB.js": {
+				"inputs": {
+					"src/shared.js": { "bytesInOutput": 200 }
+				},
+				"bytes": 210
+			}
+		}
+	}`
+
+	test.AssertEqualWithDiff(t, api.CompareMetafiles(before, after, api.CompareMetafilesOptions{}), `
+  Output files                                   Before  After  Change
+  out/chunk-AAAAAAAA.js → out/chunk-BBBBBBBB.js   270b   210b    -60b   -22.2%
+  out/entry.js                                    650b   520b   -130b   -20.0%
+  out/old.js (removed)                             40b       -   -40b 
+  Total                                           960b   730b   -230b   -24.0%
+
+  Changed input files                           Before  After  Change
+  node_modules/moment/index.js (removed)         500b       -  -500b 
+  node_modules/@scope/pkg/lib/index.js (added)       -  300b   +300b 
+  src/old.js (removed)                            30b       -   -30b 
+  src/entry.js                                   100b   120b    +20b   +20.0%
+
+  Added packages
+   + @scope/pkg  300b
+
+  Removed packages
+   - moment  500b
+
+  Moved input files
+   src/util.js
+    └ out/chunk-AAAAAAAA.js → out/entry.js
+`)
+
+	test.AssertEqualWithDiff(t, api.CompareMetafiles(before, after, api.CompareMetafilesOptions{JSON: true}), `{
+  "totalBytes": {
+    "before": 960,
+    "after": 730
+  },
+  "outputs": [
+    {
+      "before": "out/chunk-AAAAAAAA.js",
+      "after": "out/chunk-BBBBBBBB.js",
+      "status": "changed",
+      "bytesBefore": 270,
+      "bytesAfter": 210
+    },
+    {
+      "before": "out/entry.js",
+      "after": "out/entry.js",
+      "status": "changed",
+      "bytesBefore": 650,
+      "bytesAfter": 520
+    },
+    {
+      "before": "out/old.js",
+      "status": "removed",
+      "bytesBefore": 40,
+      "bytesAfter": 0
+    }
+  ],
+  "inputs": [
+    {
+      "path": "node_modules/moment/index.js",
+      "status": "removed",
+      "bytesBefore": 500,
+      "bytesAfter": 0
+    },
+    {
+      "path": "node_modules/@scope/pkg/lib/index.js",
+      "status": "added",
+      "bytesBefore": 0,
+      "bytesAfter": 300
+    },
+    {
+      "path": "src/old.js",
+      "status": "removed",
+      "bytesBefore": 30,
+      "bytesAfter": 0
+    },
+    {
+      "path": "src/entry.js",
+      "status": "changed",
+      "bytesBefore": 100,
+      "bytesAfter": 120
+    }
+  ],
+  "addedPackages": [
+    {
+      "name": "@scope/pkg",
+      "bytes": 300
+    }
+  ],
+  "removedPackages": [
+    {
+      "name": "moment",
+      "bytes": 500
+    }
+  ],
+  "movedInputs": [
+    {
+      "path": "src/util.js",
+      "from": ["out/chunk-AAAAAAAA.js"],
+      "to": ["out/entry.js"]
+    }
+  ]
+}
+`)
+
+	test.AssertEqualWithDiff(t, api.CompareMetafiles("{", after, api.CompareMetafilesOptions{}), "")
+}
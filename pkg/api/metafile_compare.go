@@ -0,0 +1,559 @@
+package api
+
+// This file implements "CompareMetafiles", which reports how a bundle changed
+// between two builds. It's meant to be run on the metafiles from before and
+// after a change (e.g. in CI for every pull request) to catch size regressions.
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/evanw/esbuild/internal/helpers"
+	"github.com/evanw/esbuild/internal/js_ast"
+	"github.com/evanw/esbuild/internal/js_parser"
+	"github.com/evanw/esbuild/internal/logger"
+)
+
+type compareOutput struct {
+	inputs     map[string]int
+	path       string
+	entryPoint string
+	bytes      int
+}
+
+type compareOutputPair struct {
+	before *compareOutput
+	after  *compareOutput
+}
+
+type compareInput struct {
+	path        string
+	bytesBefore int
+	bytesAfter  int
+	hasBefore   bool
+	hasAfter    bool
+}
+
+type comparePackage struct {
+	name  string
+	bytes int
+}
+
+type compareMovedModule struct {
+	path string
+	from []string
+	to   []string
+}
+
+type metafileComparison struct {
+	outputs         []compareOutputPair
+	inputs          []compareInput
+	addedPackages   []comparePackage
+	removedPackages []comparePackage
+	movedModules    []compareMovedModule
+	totalBefore     int
+	totalAfter      int
+}
+
+func compareMetafilesImpl(before string, after string, opts CompareMetafilesOptions) string {
+	beforeOutputs, ok := parseMetafileOutputsForCompare(before)
+	if !ok {
+		return ""
+	}
+	afterOutputs, ok := parseMetafileOutputsForCompare(after)
+	if !ok {
+		return ""
+	}
+
+	comparison := compareMetafileOutputs(beforeOutputs, afterOutputs)
+	if opts.JSON {
+		return comparison.toJSON()
+	}
+	return comparison.toText(opts)
+}
+
+func parseMetafileOutputsForCompare(metafile string) ([]*compareOutput, bool) {
+	log := logger.NewDeferLog(logger.DeferLogNoVerboseOrDebug, nil)
+	source := logger.Source{Contents: metafile}
+	result, ok := js_parser.ParseJSON(log, source, js_parser.JSONOptions{})
+	if !ok {
+		return nil, false
+	}
+	outputs := getObjectPropertyObject(result, "outputs")
+	if outputs == nil {
+		return nil, false
+	}
+
+	var results []*compareOutput
+	for _, output := range outputs.Properties {
+		key := helpers.UTF16ToString(output.Key.Data.(*js_ast.EString).Value)
+		if strings.HasSuffix(key, ".map") {
+			continue
+		}
+		result := &compareOutput{path: key, inputs: make(map[string]int)}
+		if bytes := getObjectPropertyNumber(output.ValueOrNil, "bytes"); bytes != nil {
+			result.bytes = int(bytes.Value)
+		}
+		if entryPoint := getObjectPropertyString(output.ValueOrNil, "entryPoint"); entryPoint != nil {
+			result.entryPoint = helpers.UTF16ToString(entryPoint.Value)
+		}
+		if inputs := getObjectPropertyObject(output.ValueOrNil, "inputs"); inputs != nil {
+			for _, input := range inputs.Properties {
+				bytesInOutput := 0
+				if bytes := getObjectPropertyNumber(input.ValueOrNil, "bytesInOutput"); bytes != nil {
+					bytesInOutput = int(bytes.Value)
+				}
+				result.inputs[helpers.UTF16ToString(input.Key.Data.(*js_ast.EString).Value)] = bytesInOutput
+			}
+		}
+		results = append(results, result)
+	}
+	return results, true
+}
+
+func compareMetafileOutputs(beforeOutputs []*compareOutput, afterOutputs []*compareOutput) (comparison metafileComparison) {
+	// Output file names often contain content hashes, so they can't always be
+	// matched up by name. Match by name first, then by entry point, and then
+	// match the remaining output files by how many input bytes they share.
+	matchedBefore := make(map[*compareOutput]bool)
+	matchedAfter := make(map[*compareOutput]bool)
+	match := func(isMatch func(before *compareOutput, after *compareOutput) bool) {
+		for _, before := range beforeOutputs {
+			if matchedBefore[before] {
+				continue
+			}
+			for _, after := range afterOutputs {
+				if !matchedAfter[after] && isMatch(before, after) {
+					matchedBefore[before] = true
+					matchedAfter[after] = true
+					comparison.outputs = append(comparison.outputs, compareOutputPair{before: before, after: after})
+					break
+				}
+			}
+		}
+	}
+	match(func(before *compareOutput, after *compareOutput) bool {
+		return before.path == after.path
+	})
+	match(func(before *compareOutput, after *compareOutput) bool {
+		return before.entryPoint != "" && before.entryPoint == after.entryPoint && path.Ext(before.path) == path.Ext(after.path)
+	})
+	for _, before := range beforeOutputs {
+		if matchedBefore[before] {
+			continue
+		}
+		var best *compareOutput
+		bestShared := 0
+		for _, after := range afterOutputs {
+			if matchedAfter[after] || after.entryPoint != "" || before.entryPoint != "" || path.Ext(before.path) != path.Ext(after.path) {
+				continue
+			}
+			shared := 0
+			for input, bytesBefore := range before.inputs {
+				if bytesAfter, ok := after.inputs[input]; ok {
+					if bytesAfter < bytesBefore {
+						shared += bytesAfter
+					} else {
+						shared += bytesBefore
+					}
+				}
+			}
+			if shared > bestShared {
+				best = after
+				bestShared = shared
+			}
+		}
+		if best != nil {
+			matchedBefore[before] = true
+			matchedAfter[best] = true
+			comparison.outputs = append(comparison.outputs, compareOutputPair{before: before, after: best})
+		}
+	}
+	for _, before := range beforeOutputs {
+		if !matchedBefore[before] {
+			comparison.outputs = append(comparison.outputs, compareOutputPair{before: before})
+		}
+	}
+	for _, after := range afterOutputs {
+		if !matchedAfter[after] {
+			comparison.outputs = append(comparison.outputs, compareOutputPair{after: after})
+		}
+	}
+	sort.SliceStable(comparison.outputs, func(i int, j int) bool {
+		return comparison.outputs[i].name() < comparison.outputs[j].name()
+	})
+
+	// Add up the size of each input file and package over all output files
+	inputs := make(map[string]*compareInput)
+	beforePackages := make(map[string]int)
+	afterPackages := make(map[string]int)
+	beforeLocations := make(map[string][]int)
+	afterLocations := make(map[string][]int)
+	for i, pair := range comparison.outputs {
+		if pair.before != nil {
+			comparison.totalBefore += pair.before.bytes
+			for key, bytes := range pair.before.inputs {
+				input := inputs[key]
+				if input == nil {
+					input = &compareInput{path: key}
+					inputs[key] = input
+				}
+				input.bytesBefore += bytes
+				input.hasBefore = true
+				beforeLocations[key] = append(beforeLocations[key], i)
+				if name, ok := packageNameForInput(key); ok {
+					beforePackages[name] += bytes
+				}
+			}
+		}
+		if pair.after != nil {
+			comparison.totalAfter += pair.after.bytes
+			for key, bytes := range pair.after.inputs {
+				input := inputs[key]
+				if input == nil {
+					input = &compareInput{path: key}
+					inputs[key] = input
+				}
+				input.bytesAfter += bytes
+				input.hasAfter = true
+				afterLocations[key] = append(afterLocations[key], i)
+				if name, ok := packageNameForInput(key); ok {
+					afterPackages[name] += bytes
+				}
+			}
+		}
+	}
+
+	// Only report input files that changed
+	for _, input := range inputs {
+		if input.hasBefore != input.hasAfter || input.bytesBefore != input.bytesAfter {
+			comparison.inputs = append(comparison.inputs, *input)
+		}
+	}
+	sort.Slice(comparison.inputs, func(i int, j int) bool {
+		a, b := comparison.inputs[i], comparison.inputs[j]
+		deltaA, deltaB := absInt(a.bytesAfter-a.bytesBefore), absInt(b.bytesAfter-b.bytesBefore)
+		if deltaA != deltaB {
+			return deltaA > deltaB
+		}
+		return a.path < b.path
+	})
+
+	for name, bytes := range afterPackages {
+		if _, ok := beforePackages[name]; !ok {
+			comparison.addedPackages = append(comparison.addedPackages, comparePackage{name: name, bytes: bytes})
+		}
+	}
+	for name, bytes := range beforePackages {
+		if _, ok := afterPackages[name]; !ok {
+			comparison.removedPackages = append(comparison.removedPackages, comparePackage{name: name, bytes: bytes})
+		}
+	}
+	sortComparePackages(comparison.addedPackages)
+	sortComparePackages(comparison.removedPackages)
+
+	// An input file has moved if the set of matched output files that contain
+	// it is different. The output indices were appended in sorted order.
+	for key, before := range beforeLocations {
+		after, ok := afterLocations[key]
+		if !ok {
+			continue
+		}
+		isSame := len(before) == len(after)
+		for i := 0; isSame && i < len(before); i++ {
+			isSame = before[i] == after[i]
+		}
+		if !isSame {
+			moved := compareMovedModule{path: key}
+			for _, i := range before {
+				moved.from = append(moved.from, comparison.outputs[i].before.path)
+			}
+			for _, i := range after {
+				moved.to = append(moved.to, comparison.outputs[i].after.path)
+			}
+			comparison.movedModules = append(comparison.movedModules, moved)
+		}
+	}
+	sort.Slice(comparison.movedModules, func(i int, j int) bool {
+		return comparison.movedModules[i].path < comparison.movedModules[j].path
+	})
+	return
+}
+
+func (pair compareOutputPair) name() string {
+	if pair.after != nil {
+		return pair.after.path
+	}
+	return pair.before.path
+}
+
+// Returns the package name for input files inside a "node_modules" directory
+func packageNameForInput(inputPath string) (string, bool) {
+	i := strings.LastIndex(inputPath, "node_modules/")
+	if i == -1 || (i > 0 && inputPath[i-1] != '/') {
+		return "", false
+	}
+	rest := inputPath[i+len("node_modules/"):]
+	slash := strings.IndexByte(rest, '/')
+	if slash == -1 {
+		return "", false
+	}
+	if strings.HasPrefix(rest, "@") {
+		if next := strings.IndexByte(rest[slash+1:], '/'); next != -1 {
+			return rest[:slash+1+next], true
+		}
+		return "", false
+	}
+	return rest[:slash], true
+}
+
+func sortComparePackages(packages []comparePackage) {
+	sort.Slice(packages, func(i int, j int) bool {
+		if packages[i].bytes != packages[j].bytes {
+			return packages[i].bytes > packages[j].bytes
+		}
+		return packages[i].name < packages[j].name
+	})
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func (comparison *metafileComparison) toText(opts CompareMetafilesOptions) string {
+	var colors logger.Colors
+	if opts.Color {
+		colors = logger.TerminalColors
+	}
+
+	type tableEntry struct {
+		first    string
+		before   string
+		after    string
+		change   string
+		percent  string
+		firstLen int
+		color    string
+	}
+
+	sb := strings.Builder{}
+	writeTable := func(title string, table []tableEntry) {
+		maxFirstLen := utf8.RuneCountInString(title)
+		maxBeforeLen := len("Before")
+		maxAfterLen := len("After")
+		maxChangeLen := len("Change")
+		for _, entry := range table {
+			if maxFirstLen < entry.firstLen {
+				maxFirstLen = entry.firstLen
+			}
+			if maxBeforeLen < len(entry.before) {
+				maxBeforeLen = len(entry.before)
+			}
+			if maxAfterLen < len(entry.after) {
+				maxAfterLen = len(entry.after)
+			}
+			if maxChangeLen < len(entry.change) {
+				maxChangeLen = len(entry.change)
+			}
+		}
+
+		sb.WriteString(fmt.Sprintf("\n  %s%s%s  %*s  %*s  %*s%s\n",
+			colors.Bold, title, strings.Repeat(" ", maxFirstLen-utf8.RuneCountInString(title)),
+			maxBeforeLen, "Before", maxAfterLen, "After", maxChangeLen, "Change", colors.Reset))
+		for _, entry := range table {
+			sb.WriteString(fmt.Sprintf("  %s%s  %*s  %*s  %s%*s%s",
+				entry.first, strings.Repeat(" ", maxFirstLen-entry.firstLen),
+				maxBeforeLen, entry.before, maxAfterLen, entry.after,
+				entry.color, maxChangeLen, entry.change, colors.Reset))
+			if entry.percent != "" {
+				sb.WriteString(fmt.Sprintf("  %s%s%s", entry.color, entry.percent, colors.Reset))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	makeEntry := func(first string, before int, hasBefore bool, after int, hasAfter bool) tableEntry {
+		entry := tableEntry{first: first, firstLen: utf8.RuneCountInString(first), before: "-", after: "-"}
+		if hasBefore {
+			entry.before = prettyPrintByteCount(before)
+		}
+		if hasAfter {
+			entry.after = prettyPrintByteCount(after)
+		}
+		delta := after - before
+		entry.change = prettyPrintByteCount(absInt(delta))
+		if delta > 0 {
+			entry.change = "+" + entry.change
+			entry.color = colors.Red
+		} else if delta < 0 {
+			entry.change = "-" + entry.change
+			entry.color = colors.Green
+		}
+		if hasBefore && hasAfter && before > 0 && delta != 0 {
+			entry.percent = fmt.Sprintf("%+.1f%%", 100.0*float64(delta)/float64(before))
+		}
+		return entry
+	}
+
+	// Output files
+	var table []tableEntry
+	for _, pair := range comparison.outputs {
+		var entry tableEntry
+		switch {
+		case pair.before == nil:
+			entry = makeEntry(pair.after.path+" (added)", 0, false, pair.after.bytes, true)
+		case pair.after == nil:
+			entry = makeEntry(pair.before.path+" (removed)", pair.before.bytes, true, 0, false)
+		case pair.before.path != pair.after.path:
+			entry = makeEntry(pair.before.path+" → "+pair.after.path, pair.before.bytes, true, pair.after.bytes, true)
+		default:
+			entry = makeEntry(pair.after.path, pair.before.bytes, true, pair.after.bytes, true)
+		}
+		table = append(table, entry)
+	}
+	total := makeEntry("Total", comparison.totalBefore, true, comparison.totalAfter, true)
+	total.first = fmt.Sprintf("%s%s%s", colors.Bold, total.first, colors.Reset)
+	table = append(table, total)
+	writeTable("Output files", table)
+
+	// Input files
+	if len(comparison.inputs) > 0 {
+		table = table[:0]
+		for _, input := range comparison.inputs {
+			first := input.path
+			if !input.hasBefore {
+				first += " (added)"
+			} else if !input.hasAfter {
+				first += " (removed)"
+			}
+			table = append(table, makeEntry(first, input.bytesBefore, input.hasBefore, input.bytesAfter, input.hasAfter))
+		}
+		writeTable("Changed input files", table)
+	}
+
+	// Packages
+	writePackages := func(title string, sign string, color string, packages []comparePackage) {
+		if len(packages) == 0 {
+			return
+		}
+		maxNameLen := 0
+		for _, pkg := range packages {
+			if maxNameLen < len(pkg.name) {
+				maxNameLen = len(pkg.name)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s%s%s\n", colors.Bold, title, colors.Reset))
+		for _, pkg := range packages {
+			sb.WriteString(fmt.Sprintf("   %s%s%s %-*s  %s\n", color, sign, colors.Reset, maxNameLen, pkg.name,
+				strings.TrimRight(prettyPrintByteCount(pkg.bytes), " ")))
+		}
+	}
+	writePackages("Added packages", "+", colors.Red, comparison.addedPackages)
+	writePackages("Removed packages", "-", colors.Green, comparison.removedPackages)
+
+	// Moved modules
+	if len(comparison.movedModules) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  %sMoved input files%s\n", colors.Bold, colors.Reset))
+		for _, moved := range comparison.movedModules {
+			sb.WriteString(fmt.Sprintf("   %s\n    %s└ %s → %s%s\n", moved.path, colors.Dim,
+				strings.Join(moved.from, ", "), strings.Join(moved.to, ", "), colors.Reset))
+		}
+	}
+
+	return sb.String()
+}
+
+func (comparison *metafileComparison) toJSON() string {
+	sb := strings.Builder{}
+	quote := func(text string) string {
+		return string(helpers.QuoteForJSON(text, false))
+	}
+	writeArray := func(key string, count int, isLast bool, writeItem func(i int)) {
+		sb.WriteString(fmt.Sprintf("  %s: [", quote(key)))
+		for i := 0; i < count; i++ {
+			if i > 0 {
+				sb.WriteByte(',')
+			}
+			sb.WriteString("\n    {")
+			writeItem(i)
+			sb.WriteString("\n    }")
+		}
+		if count > 0 {
+			sb.WriteString("\n  ")
+		}
+		sb.WriteByte(']')
+		if !isLast {
+			sb.WriteByte(',')
+		}
+		sb.WriteByte('\n')
+	}
+	writeStatus := func(hasBefore bool, hasAfter bool) string {
+		if !hasBefore {
+			return "added"
+		}
+		if !hasAfter {
+			return "removed"
+		}
+		return "changed"
+	}
+
+	sb.WriteString(fmt.Sprintf("{\n  \"totalBytes\": {\n    \"before\": %d,\n    \"after\": %d\n  },\n",
+		comparison.totalBefore, comparison.totalAfter))
+
+	writeArray("outputs", len(comparison.outputs), false, func(i int) {
+		pair := comparison.outputs[i]
+		bytesBefore, bytesAfter := 0, 0
+		status := writeStatus(pair.before != nil, pair.after != nil)
+		if pair.before != nil {
+			sb.WriteString(fmt.Sprintf("\n      \"before\": %s,", quote(pair.before.path)))
+			bytesBefore = pair.before.bytes
+		}
+		if pair.after != nil {
+			sb.WriteString(fmt.Sprintf("\n      \"after\": %s,", quote(pair.after.path)))
+			bytesAfter = pair.after.bytes
+		}
+		if status == "changed" && bytesBefore == bytesAfter {
+			status = "unchanged"
+		}
+		sb.WriteString(fmt.Sprintf("\n      \"status\": %q,\n      \"bytesBefore\": %d,\n      \"bytesAfter\": %d",
+			status, bytesBefore, bytesAfter))
+	})
+
+	writeArray("inputs", len(comparison.inputs), false, func(i int) {
+		input := comparison.inputs[i]
+		sb.WriteString(fmt.Sprintf("\n      \"path\": %s,\n      \"status\": %q,\n      \"bytesBefore\": %d,\n      \"bytesAfter\": %d",
+			quote(input.path), writeStatus(input.hasBefore, input.hasAfter), input.bytesBefore, input.bytesAfter))
+	})
+
+	writeArray("addedPackages", len(comparison.addedPackages), false, func(i int) {
+		pkg := comparison.addedPackages[i]
+		sb.WriteString(fmt.Sprintf("\n      \"name\": %s,\n      \"bytes\": %d", quote(pkg.name), pkg.bytes))
+	})
+
+	writeArray("removedPackages", len(comparison.removedPackages), false, func(i int) {
+		pkg := comparison.removedPackages[i]
+		sb.WriteString(fmt.Sprintf("\n      \"name\": %s,\n      \"bytes\": %d", quote(pkg.name), pkg.bytes))
+	})
+
+	writeArray("movedInputs", len(comparison.movedModules), true, func(i int) {
+		moved := comparison.movedModules[i]
+		writePaths := func(paths []string) string {
+			quoted := make([]string, len(paths))
+			for j, path := range paths {
+				quoted[j] = quote(path)
+			}
+			return "[" + strings.Join(quoted, ", ") + "]"
+		}
+		sb.WriteString(fmt.Sprintf("\n      \"path\": %s,\n      \"from\": %s,\n      \"to\": %s",
+			quote(moved.path), writePaths(moved.from), writePaths(moved.to)))
+	})
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -51,6 +51,7 @@ type apiHandler struct {
 	serveWaitGroup   sync.WaitGroup
 	activeStreams    []chan serverSentEvent
 	currentHashes    map[string]string
+	currentModules   map[string]map[string]string
 	mutex            sync.Mutex
 }
 
@@ -59,6 +60,11 @@ type serverSentEvent struct {
 	data  string
 }
 
+type hmrUpdate struct {
+	url     string
+	modules []string
+}
+
 func escapeForHTML(text string) string {
 	text = strings.ReplaceAll(text, "&", "&amp;")
 	text = strings.ReplaceAll(text, "<", "&lt;")
@@ -421,12 +427,13 @@ func (h *apiHandler) serveEventStream(start time.Time, req *http.Request, res ht
 	res.Write([]byte("500 - Event stream error"))
 }
 
-func (h *apiHandler) broadcastBuildResult(result BuildResult, newHashes map[string]string) {
+func (h *apiHandler) broadcastBuildResult(result BuildResult, newHashes map[string]string, newModules map[string]map[string]string) {
 	h.mutex.Lock()
 
 	var added []string
 	var removed []string
 	var updated []string
+	var hmrUpdates []hmrUpdate
 
 	urlForPath := func(absPath string) (string, bool) {
 		if relPath, ok := stripDirPrefix(absPath, h.absOutputDir, "\\/"); ok {
@@ -446,7 +453,9 @@ func (h *apiHandler) broadcastBuildResult(result BuildResult, newHashes map[stri
 	// make it appear as if all files were removed when there is a build error.
 	if len(result.Errors) == 0 {
 		oldHashes := h.currentHashes
+		oldModuleHashes := h.currentModules
 		h.currentHashes = newHashes
+		h.currentModules = newModules
 
 		for absPath, newHash := range newHashes {
 			if oldHash, ok := oldHashes[absPath]; !ok {
@@ -456,6 +465,21 @@ func (h *apiHandler) broadcastBuildResult(result BuildResult, newHashes map[stri
 			} else if newHash != oldHash {
 				if url, ok := urlForPath(absPath); ok {
 					updated = append(updated, url)
+
+					// Hot module replacement needs to know which modules in this file
+					// have changed. Modules that didn't exist before can be ignored
+					// since they can only be reached through a module that changed.
+					var modules []string
+					oldModules := oldModuleHashes[absPath]
+					for module, newModuleHash := range newModules[absPath] {
+						if oldModuleHash, ok := oldModules[module]; ok && oldModuleHash != newModuleHash {
+							modules = append(modules, module)
+						}
+					}
+					if len(modules) > 0 {
+						sort.Strings(modules)
+						hmrUpdates = append(hmrUpdates, hmrUpdate{url: url, modules: modules})
+					}
 				}
 			}
 		}
@@ -509,6 +533,40 @@ func (h *apiHandler) broadcastBuildResult(result BuildResult, newHashes map[stri
 		}
 	}
 
+	// Hot module replacement uses a separate event that lists the modules that
+	// changed in each updated file. This is sent after the "change" event.
+	if len(hmrUpdates) > 0 {
+		sort.Slice(hmrUpdates, func(i int, j int) bool {
+			return hmrUpdates[i].url < hmrUpdates[j].url
+		})
+
+		// Assemble the list of updates
+		var sb strings.Builder
+		sb.WriteString("{\"updated\":[")
+		for i, update := range hmrUpdates {
+			if i > 0 {
+				sb.WriteRune(',')
+			}
+			sb.WriteString("{\"url\":")
+			sb.Write(helpers.QuoteForJSON(update.url, false))
+			sb.WriteString(",\"modules\":[")
+			for j, module := range update.modules {
+				if j > 0 {
+					sb.WriteRune(',')
+				}
+				sb.Write(helpers.QuoteForJSON(module, false))
+			}
+			sb.WriteString("]}")
+		}
+		sb.WriteString("]}")
+		json := sb.String()
+
+		// Broadcast the updates to all streams
+		for _, stream := range h.activeStreams {
+			stream <- serverSentEvent{event: "hmr", data: json}
+		}
+	}
+
 	h.mutex.Unlock()
 }
 
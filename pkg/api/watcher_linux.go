@@ -0,0 +1,178 @@
+//go:build linux
+// +build linux
+
+package api
+
+// This file implements native file system events on Linux using inotify. Only
+// directories are watched since a directory watch also reports changes to the
+// files inside it. That means each directory containing a watched file uses
+// one inotify watch regardless of how many files in it are watched, which
+// keeps the number of watches well below the system-wide limit for most
+// projects.
+
+import (
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/evanw/esbuild/internal/fs"
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_ONLYDIR | unix.IN_ATTRIB | unix.IN_CLOSE_WRITE | unix.IN_CREATE |
+	unix.IN_DELETE | unix.IN_DELETE_SELF | unix.IN_MODIFY | unix.IN_MOVE_SELF | unix.IN_MOVED_FROM |
+	unix.IN_MOVED_TO
+
+type inotifyWatcher struct {
+	fs     fs.FS
+	mutex  sync.Mutex
+	buffer []byte
+	fd     int
+
+	// Each directory is watched at most once. Note that multiple paths may map
+	// to the same watch descriptor (e.g. due to symlinks).
+	dirToWatch   map[string]int
+	watchToDirs  map[int][]string
+	notWatchable map[string]bool
+}
+
+func newNativeWatcher(fs fs.FS) (nativeWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	return &inotifyWatcher{
+		fs:           fs,
+		fd:           fd,
+		buffer:       make([]byte, 64*1024),
+		dirToWatch:   make(map[string]int),
+		watchToDirs:  make(map[int][]string),
+		notWatchable: make(map[string]bool),
+	}, nil
+}
+
+// This returns false if the path isn't a directory that can be watched
+func (w *inotifyWatcher) addDir(dir string, isUsed map[string]bool) bool {
+	if _, ok := w.dirToWatch[dir]; ok {
+		isUsed[dir] = true
+		return true
+	}
+
+	// Remember failures so that paths to files don't cause a system call each
+	// time. This is reset when an event mentions this path.
+	if w.notWatchable[dir] {
+		return false
+	}
+	wd, err := unix.InotifyAddWatch(w.fd, dir, inotifyMask)
+	if err != nil {
+		w.notWatchable[dir] = true
+		return false
+	}
+	w.dirToWatch[dir] = wd
+	w.watchToDirs[wd] = append(w.watchToDirs[wd], dir)
+	isUsed[dir] = true
+	return true
+}
+
+func (w *inotifyWatcher) removeDir(dir string) {
+	wd := w.dirToWatch[dir]
+	delete(w.dirToWatch, dir)
+	dirs := w.watchToDirs[wd]
+	for i, other := range dirs {
+		if other == dir {
+			dirs = append(dirs[:i], dirs[i+1:]...)
+			break
+		}
+	}
+	if len(dirs) > 0 {
+		w.watchToDirs[wd] = dirs
+	} else {
+		delete(w.watchToDirs, wd)
+		unix.InotifyRmWatch(w.fd, uint32(wd))
+	}
+}
+
+func (w *inotifyWatcher) setPaths(paths map[string]func() string) (pollPaths []string) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	isUsed := make(map[string]bool)
+
+	for path := range paths {
+		// A path is covered if it's a watched directory (which catches changes to
+		// the directory listing) or if it's inside a watched directory (which
+		// catches changes to the file itself). Watching both is necessary for
+		// directories since the path may be either a file or a directory.
+		isDir := w.addDir(path, isUsed)
+		if !w.addDir(w.fs.Dir(path), isUsed) && !isDir {
+			pollPaths = append(pollPaths, path)
+		}
+	}
+
+	// Stop watching directories that are no longer relevant
+	for dir := range w.dirToWatch {
+		if !isUsed[dir] {
+			w.removeDir(dir)
+		}
+	}
+	return
+}
+
+func (w *inotifyWatcher) wait(timeout time.Duration) (paths []string, overflow bool) {
+	fds := []unix.PollFd{{Fd: int32(w.fd), Events: unix.POLLIN}}
+	if n, err := unix.Poll(fds, int(timeout/time.Millisecond)); n <= 0 || err != nil {
+		return nil, false
+	}
+	n, err := unix.Read(w.fd, w.buffer)
+	if n <= 0 || err != nil {
+		return nil, false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+		// The kernel writes events using native byte order and alignment
+		event := (*unix.InotifyEvent)(unsafe.Pointer(&w.buffer[offset]))
+		wd := int(event.Wd)
+		mask := event.Mask
+		nameLen := int(event.Len)
+		offset += unix.SizeofInotifyEvent
+		if offset+nameLen > n {
+			break
+		}
+
+		// The name is padded with null bytes
+		name := w.buffer[offset : offset+nameLen]
+		for len(name) > 0 && name[len(name)-1] == 0 {
+			name = name[:len(name)-1]
+		}
+		offset += nameLen
+
+		if mask&unix.IN_Q_OVERFLOW != 0 {
+			overflow = true
+			continue
+		}
+
+		for _, dir := range w.watchToDirs[wd] {
+			path := dir
+			if len(name) > 0 {
+				path = w.fs.Join(dir, string(name))
+			}
+			delete(w.notWatchable, path)
+			paths = append(paths, path)
+		}
+
+		// The kernel automatically removes the watch when the directory is gone
+		if mask&unix.IN_IGNORED != 0 {
+			for _, dir := range w.watchToDirs[wd] {
+				delete(w.dirToWatch, dir)
+			}
+			delete(w.watchToDirs, wd)
+		}
+	}
+	return
+}
+
+func (w *inotifyWatcher) close() {
+	unix.Close(w.fd)
+}
@@ -0,0 +1,141 @@
+//go:build linux
+// +build linux
+
+package api
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/test"
+)
+
+func newInotifyWatcherForTest(t *testing.T) (*inotifyWatcher, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "esbuild-watcher-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	realFS, err := fs.RealFS(fs.RealFSOptions{AbsWorkingDir: dir, DoNotCache: true})
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	native, err := newNativeWatcher(realFS)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Skipf("inotify is not available: %s", err.Error())
+	}
+	return native.(*inotifyWatcher), dir, func() {
+		native.close()
+		os.RemoveAll(dir)
+	}
+}
+
+func waitForInotifyPath(t *testing.T, w *inotifyWatcher, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		paths, _ := w.wait(100 * time.Millisecond)
+		for _, path := range paths {
+			if path == expected {
+				return
+			}
+		}
+	}
+	t.Fatalf("Timed out waiting for an event for %q", expected)
+}
+
+func TestInotifyWatcherFileChange(t *testing.T) {
+	w, dir, cleanup := newInotifyWatcherForTest(t)
+	defer cleanup()
+	file := filepath.Join(dir, "entry.js")
+	if err := ioutil.WriteFile(file, []byte("1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Paths in directories that don't exist yet must be polled instead
+	missing := filepath.Join(dir, "missing", "other.js")
+	pollPaths := w.setPaths(map[string]func() string{
+		file:    func() string { return "" },
+		missing: func() string { return "" },
+	})
+	test.AssertEqualWithDiff(t, len(pollPaths), 1)
+	test.AssertEqualWithDiff(t, pollPaths[0], missing)
+
+	if err := ioutil.WriteFile(file, []byte("2"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitForInotifyPath(t, w, file)
+
+	// Creating the missing directory is reported as a change to its parent
+	if err := os.Mkdir(filepath.Dir(missing), 0755); err != nil {
+		t.Fatal(err)
+	}
+	waitForInotifyPath(t, w, filepath.Dir(missing))
+}
+
+func TestInotifyWatcherRemovesUnusedWatches(t *testing.T) {
+	w, dir, cleanup := newInotifyWatcherForTest(t)
+	defer cleanup()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory path watches both the directory and its parent
+	w.setPaths(map[string]func() string{sub: func() string { return "" }})
+	var dirs []string
+	for dir := range w.dirToWatch {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	test.AssertEqualWithDiff(t, len(dirs), 2)
+	test.AssertEqualWithDiff(t, dirs[0], dir)
+	test.AssertEqualWithDiff(t, dirs[1], sub)
+
+	w.setPaths(map[string]func() string{})
+	test.AssertEqualWithDiff(t, len(w.dirToWatch), 0)
+	test.AssertEqualWithDiff(t, len(w.watchToDirs), 0)
+}
+
+func TestWatcherEventPaths(t *testing.T) {
+	realFS, err := fs.RealFS(fs.RealFSOptions{DoNotCache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirty := ""
+	w := &watcher{fs: realFS}
+	w.setWatchData(fs.WatchData{Paths: map[string]func() string{
+		"/project":          func() string { return dirty },
+		"/project/entry.js": func() string { return "" },
+	}})
+
+	// An event for a new file in a watched directory marks the directory dirty
+	dirty = "/project/new.js"
+	test.AssertEqualWithDiff(t, w.tryToFindDirtyPath([]string{"/project/new.js"}, false), "/project/new.js")
+	test.AssertEqualWithDiff(t, len(w.recentItems), 1)
+	test.AssertEqualWithDiff(t, w.recentItems[0], "/project")
+
+	// Finding the same path again must not add a duplicate recent item
+	test.AssertEqualWithDiff(t, w.tryToFindDirtyPath([]string{"/project/new.js"}, false), "/project/new.js")
+	test.AssertEqualWithDiff(t, len(w.recentItems), 1)
+
+	dirty = ""
+	test.AssertEqualWithDiff(t, w.tryToFindDirtyPath(nil, true), "")
+}
+
+func TestWatcherMarkAsRecent(t *testing.T) {
+	w := &watcher{}
+	w.markAsRecent("a")
+	w.markAsRecent("b")
+	w.markAsRecent("c")
+	w.markAsRecent("a")
+	test.AssertEqualWithDiff(t, strings.Join(w.recentItems, ","), "b,c,a")
+}
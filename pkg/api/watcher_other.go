@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package api
+
+import (
+	"errors"
+
+	"github.com/evanw/esbuild/internal/fs"
+)
+
+func newNativeWatcher(fs fs.FS) (nativeWatcher, error) {
+	return nil, errors.New("Native file system events are not supported on this platform")
+}
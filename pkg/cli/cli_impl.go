@@ -51,6 +51,7 @@ const (
 
 type parseOptionsExtras struct {
 	watch       bool
+	watchMode   api.WatchMode
 	metafile    *string
 	mangleCache *string
 }
@@ -106,6 +107,20 @@ func parseOptionsImpl(
 				buildOpts.PreserveSymlinks = value
 			}
 
+		case isBoolFlag(arg, "--dedupe") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.Dedupe = value
+			}
+
+		case isBoolFlag(arg, "--tree-shake-commonjs") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.TreeShakeCommonJS = value
+			}
+
 		case isBoolFlag(arg, "--splitting") && buildOpts != nil:
 			if value, err := parseBoolFlag(arg, true); err != nil {
 				return parseOptionsExtras{}, err
@@ -113,6 +128,27 @@ func parseOptionsImpl(
 				buildOpts.Splitting = value
 			}
 
+		case isBoolFlag(arg, "--preserve-modules") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.PreserveModules = value
+			}
+
+		case isBoolFlag(arg, "--hmr") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.HMR = value
+			}
+
+		case isBoolFlag(arg, "--declaration") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.Declaration = value
+			}
+
 		case isBoolFlag(arg, "--allow-overwrite") && buildOpts != nil:
 			if value, err := parseBoolFlag(arg, true); err != nil {
 				return parseOptionsExtras{}, err
@@ -127,6 +163,20 @@ func parseOptionsImpl(
 				extras.watch = value
 			}
 
+		case strings.HasPrefix(arg, "--watch-mode=") && buildOpts != nil:
+			value := arg[len("--watch-mode="):]
+			switch value {
+			case "native":
+				extras.watchMode = api.WatchModeNative
+			case "poll":
+				extras.watchMode = api.WatchModePoll
+			default:
+				return parseOptionsExtras{}, cli_helpers.MakeErrorWithNote(
+					fmt.Sprintf("Invalid value %q in %q", value, arg),
+					"Valid values are \"native\" or \"poll\".",
+				)
+			}
+
 		case isBoolFlag(arg, "--minify"):
 			if value, err := parseBoolFlag(arg, true); err != nil {
 				return parseOptionsExtras{}, err
@@ -314,6 +364,15 @@ func parseOptionsImpl(
 				transformOpts.KeepNames = value
 			}
 
+		case isBoolFlag(arg, "--loose-for-of"):
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else if buildOpts != nil {
+				buildOpts.LooseForOf = value
+			} else {
+				transformOpts.LooseForOf = value
+			}
+
 		case arg == "--sourcemap":
 			if buildOpts != nil {
 				buildOpts.Sourcemap = api.SourceMapLinked
@@ -409,6 +468,13 @@ func parseOptionsImpl(
 			buildOpts.Metafile = true
 			extras.metafile = &value
 
+		case isBoolFlag(arg, "--metafile-why") && buildOpts != nil:
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				return parseOptionsExtras{}, err
+			} else {
+				buildOpts.MetafileWhy = value
+			}
+
 		case strings.HasPrefix(arg, "--outfile=") && buildOpts != nil:
 			buildOpts.Outfile = arg[len("--outfile="):]
 
@@ -418,9 +484,18 @@ func parseOptionsImpl(
 		case strings.HasPrefix(arg, "--outbase=") && buildOpts != nil:
 			buildOpts.Outbase = arg[len("--outbase="):]
 
+		case strings.HasPrefix(arg, "--vendor-dir=") && buildOpts != nil:
+			buildOpts.VendorDir = arg[len("--vendor-dir="):]
+
+		case strings.HasPrefix(arg, "--cache-dir=") && buildOpts != nil:
+			buildOpts.CacheDir = arg[len("--cache-dir="):]
+
 		case strings.HasPrefix(arg, "--tsconfig=") && buildOpts != nil:
 			buildOpts.Tsconfig = arg[len("--tsconfig="):]
 
+		case strings.HasPrefix(arg, "--import-map=") && buildOpts != nil:
+			buildOpts.ImportMap = arg[len("--import-map="):]
+
 		case strings.HasPrefix(arg, "--tsconfig-raw="):
 			if buildOpts != nil {
 				buildOpts.TsconfigRaw = arg[len("--tsconfig-raw="):]
@@ -437,6 +512,33 @@ func parseOptionsImpl(
 		case strings.HasPrefix(arg, "--asset-names=") && buildOpts != nil:
 			buildOpts.AssetNames = arg[len("--asset-names="):]
 
+		case strings.HasPrefix(arg, "--manual-chunk:") && buildOpts != nil:
+			value := arg[len("--manual-chunk:"):]
+			equals := strings.IndexByte(value, '=')
+			if equals == -1 {
+				return parseOptionsExtras{}, cli_helpers.MakeErrorWithNote(
+					fmt.Sprintf("Missing \"=\" in %q", arg),
+					"You need to use \"=\" to specify both the chunk name and the path pattern. "+
+						"For example, \"--manual-chunk:vendor=node_modules/*\" puts all files in \"node_modules\" in a chunk called \"vendor\".",
+				)
+			}
+			if buildOpts.ManualChunks == nil {
+				buildOpts.ManualChunks = make(map[string][]string)
+			}
+			name := value[:equals]
+			buildOpts.ManualChunks[name] = append(buildOpts.ManualChunks[name], value[equals+1:])
+
+		case strings.HasPrefix(arg, "--min-chunk-size=") && buildOpts != nil:
+			value := arg[len("--min-chunk-size="):]
+			size, err := strconv.Atoi(value)
+			if err != nil || size < 0 {
+				return parseOptionsExtras{}, cli_helpers.MakeErrorWithNote(
+					fmt.Sprintf("Invalid value %q in %q", value, arg),
+					"The minimum chunk size must be a non-negative integer.",
+				)
+			}
+			buildOpts.MinChunkSize = size
+
 		case strings.HasPrefix(arg, "--define:"):
 			value := arg[len("--define:"):]
 			equals := strings.IndexByte(value, '=')
@@ -598,10 +700,16 @@ func parseOptionsImpl(
 				format = api.FormatCommonJS
 			case "esm":
 				format = api.FormatESModule
+			case "amd":
+				format = api.FormatAMD
+			case "umd":
+				format = api.FormatUMD
+			case "system":
+				format = api.FormatSystemJS
 			default:
 				return parseOptionsExtras{}, cli_helpers.MakeErrorWithNote(
 					fmt.Sprintf("Invalid value %q in %q", value, arg),
-					"Valid values are \"iife\", \"cjs\", or \"esm\".",
+					"Valid values are \"iife\", \"cjs\", \"esm\", \"amd\", \"umd\", or \"system\".",
 				)
 			}
 			if buildOpts != nil {
@@ -644,6 +752,21 @@ func parseOptionsImpl(
 			}
 			buildOpts.Alias[value[:equals]] = value[equals+1:]
 
+		case strings.HasPrefix(arg, "--umd-global:") && buildOpts != nil:
+			value := arg[len("--umd-global:"):]
+			equals := strings.IndexByte(value, '=')
+			if equals == -1 {
+				return parseOptionsExtras{}, cli_helpers.MakeErrorWithNote(
+					fmt.Sprintf("Missing \"=\" in %q", arg),
+					"You need to use \"=\" to specify both the external module and the global variable. "+
+						"For example, \"--umd-global:react=React\" reads the external module \"react\" from the global variable \"React\".",
+				)
+			}
+			if buildOpts.UMDGlobals == nil {
+				buildOpts.UMDGlobals = make(map[string]string)
+			}
+			buildOpts.UMDGlobals[value[:equals]] = value[equals+1:]
+
 		case strings.HasPrefix(arg, "--jsx="):
 			value := arg[len("--jsx="):]
 			var mode api.JSX
@@ -816,20 +939,24 @@ func parseOptionsImpl(
 
 		default:
 			bare := map[string]bool{
-				"allow-overwrite":    true,
-				"bundle":             true,
-				"ignore-annotations": true,
-				"jsx-dev":            true,
-				"jsx-side-effects":   true,
-				"keep-names":         true,
-				"minify-identifiers": true,
-				"minify-syntax":      true,
-				"minify-whitespace":  true,
-				"minify":             true,
-				"preserve-symlinks":  true,
-				"sourcemap":          true,
-				"splitting":          true,
-				"watch":              true,
+				"allow-overwrite":     true,
+				"bundle":              true,
+				"declaration":         true,
+				"dedupe":              true,
+				"hmr":                 true,
+				"ignore-annotations":  true,
+				"jsx-dev":             true,
+				"jsx-side-effects":    true,
+				"keep-names":          true,
+				"minify-identifiers":  true,
+				"minify-syntax":       true,
+				"minify-whitespace":   true,
+				"minify":              true,
+				"preserve-symlinks":   true,
+				"sourcemap":           true,
+				"splitting":           true,
+				"tree-shake-commonjs": true,
+				"watch":               true,
 			}
 
 			equals := map[string]bool{
@@ -837,17 +964,22 @@ func parseOptionsImpl(
 				"asset-names":        true,
 				"banner":             true,
 				"bundle":             true,
+				"cache-dir":          true,
 				"certfile":           true,
 				"charset":            true,
 				"chunk-names":        true,
 				"color":              true,
 				"conditions":         true,
+				"declaration":        true,
+				"dedupe":             true,
 				"drop-labels":        true,
 				"entry-names":        true,
 				"footer":             true,
 				"format":             true,
 				"global-name":        true,
+				"hmr":                true,
 				"ignore-annotations": true,
+				"import-map":         true,
 				"jsx-factory":        true,
 				"jsx-fragment":       true,
 				"jsx-import-source":  true,
@@ -863,6 +995,7 @@ func parseOptionsImpl(
 				"mangle-props":       true,
 				"mangle-quoted":      true,
 				"metafile":           true,
+				"min-chunk-size":     true,
 				"minify-identifiers": true,
 				"minify-syntax":      true,
 				"minify-whitespace":  true,
@@ -889,6 +1022,7 @@ func parseOptionsImpl(
 				"tsconfig-raw":       true,
 				"tsconfig":           true,
 				"watch":              true,
+				"watch-mode":         true,
 			}
 
 			colon := map[string]bool{
@@ -901,9 +1035,11 @@ func parseOptionsImpl(
 				"inject":        true,
 				"loader":        true,
 				"log-override":  true,
+				"manual-chunk":  true,
 				"out-extension": true,
 				"pure":          true,
 				"supported":     true,
+				"umd-global":    true,
 			}
 
 			note := ""
@@ -1083,17 +1219,25 @@ const (
 	analyzeDisabled analyzeMode = iota
 	analyzeEnabled
 	analyzeVerbose
+	analyzeWhy
 )
 
-func filterAnalyzeFlags(osArgs []string) ([]string, analyzeMode) {
-	analyze := analyzeDisabled
+type analyzeOptions struct {
+	whyPath string // Only for "analyzeWhy"
+	mode    analyzeMode
+}
+
+func filterAnalyzeFlags(osArgs []string) ([]string, analyzeOptions) {
+	analyze := analyzeOptions{}
 	end := 0
 	for _, arg := range osArgs {
-		switch arg {
-		case "--analyze":
-			analyze = analyzeEnabled
-		case "--analyze=verbose":
-			analyze = analyzeVerbose
+		switch {
+		case arg == "--analyze":
+			analyze = analyzeOptions{mode: analyzeEnabled}
+		case arg == "--analyze=verbose":
+			analyze = analyzeOptions{mode: analyzeVerbose}
+		case strings.HasPrefix(arg, "--analyze=why:"):
+			analyze = analyzeOptions{mode: analyzeWhy, whyPath: arg[len("--analyze=why:"):]}
 		default:
 			osArgs[end] = arg
 			end++
@@ -1103,7 +1247,7 @@ func filterAnalyzeFlags(osArgs []string) ([]string, analyzeMode) {
 }
 
 // Print metafile analysis after the build if it's enabled
-func addAnalyzePlugin(buildOptions *api.BuildOptions, analyze analyzeMode, osArgs []string) {
+func addAnalyzePlugin(buildOptions *api.BuildOptions, analyze analyzeOptions, osArgs []string) {
 	buildOptions.Plugins = append(buildOptions.Plugins, api.Plugin{
 		Name: "PrintAnalysis",
 		Setup: func(build api.PluginBuild) {
@@ -1113,7 +1257,8 @@ func addAnalyzePlugin(buildOptions *api.BuildOptions, analyze analyzeMode, osArg
 					logger.PrintTextWithColor(os.Stderr, color, func(colors logger.Colors) string {
 						return api.AnalyzeMetafile(result.Metafile, api.AnalyzeMetafileOptions{
 							Color:   colors != logger.Colors{},
-							Verbose: analyze == analyzeVerbose,
+							Verbose: analyze.mode == analyzeVerbose,
+							Why:     analyze.whyPath,
 						})
 					})
 					os.Stderr.WriteString("\n")
@@ -1125,6 +1270,9 @@ func addAnalyzePlugin(buildOptions *api.BuildOptions, analyze analyzeMode, osArg
 
 	// Always generate a metafile if we're analyzing, even if it won't be written out
 	buildOptions.Metafile = true
+	if analyze.mode == analyzeWhy {
+		buildOptions.MetafileWhy = true
+	}
 }
 
 func runImpl(osArgs []string) int {
@@ -1139,9 +1287,16 @@ func runImpl(osArgs []string) int {
 		}
 	}
 
+	// Special-case comparing two metafiles
+	for _, arg := range osArgs {
+		if arg == "--compare-metafiles" {
+			return compareMetafilesImpl(osArgs)
+		}
+	}
+
 	osArgs, analyze := filterAnalyzeFlags(osArgs)
 	buildOptions, transformOptions, extras, err := parseOptionsForRun(osArgs)
-	if analyze != analyzeDisabled {
+	if analyze.mode != analyzeDisabled {
 		addAnalyzePlugin(buildOptions, analyze, osArgs)
 	}
 
@@ -1307,7 +1462,7 @@ func runImpl(osArgs []string) int {
 				return 1
 			}
 
-			ctx.Watch(api.WatchOptions{})
+			ctx.Watch(api.WatchOptions{Mode: extras.watchMode})
 
 			// Do not exit if we're in watch mode
 			<-make(chan struct{})
@@ -1347,6 +1502,72 @@ func runImpl(osArgs []string) int {
 	return 0
 }
 
+// This is "esbuild --compare-metafiles before.json after.json". It prints the
+// comparison to stdout so that it can be redirected to a file in CI.
+func compareMetafilesImpl(osArgs []string) int {
+	var paths []string
+	asJSON := false
+
+	for _, arg := range osArgs {
+		switch {
+		case arg == "--compare-metafiles":
+			// This flag is what got us here
+
+		case isBoolFlag(arg, "--json"):
+			if value, err := parseBoolFlag(arg, true); err != nil {
+				logger.PrintErrorWithNoteToStderr(osArgs, err.Text, err.Note)
+				return 1
+			} else {
+				asJSON = value
+			}
+
+		case strings.HasPrefix(arg, "--color=") || strings.HasPrefix(arg, "--log-level="):
+			// These are handled by "logger.OutputOptionsForArgs" below
+
+		case !strings.HasPrefix(arg, "-"):
+			paths = append(paths, arg)
+
+		default:
+			logger.PrintErrorWithNoteToStderr(osArgs, fmt.Sprintf("Invalid compare-metafiles flag: %q", arg),
+				"Only \"--json\" and \"--color\" can be used with \"--compare-metafiles\".")
+			return 1
+		}
+	}
+
+	if len(paths) != 2 {
+		logger.PrintErrorWithNoteToStderr(osArgs, fmt.Sprintf("Expected 2 metafile paths but got %d", len(paths)),
+			"Use \"esbuild --compare-metafiles before.json after.json\" to compare two metafiles.")
+		return 1
+	}
+
+	var metafiles [2]string
+	for i, path := range paths {
+		bytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			logger.PrintErrorToStderr(osArgs, fmt.Sprintf(
+				"Failed to read metafile %q: %s", path, err.Error()))
+			return 1
+		}
+		metafiles[i] = string(bytes)
+	}
+
+	var result string
+	color := logger.OutputOptionsForArgs(osArgs).Color
+	logger.PrintTextWithColor(os.Stdout, color, func(colors logger.Colors) string {
+		result = api.CompareMetafiles(metafiles[0], metafiles[1], api.CompareMetafilesOptions{
+			Color: colors != logger.Colors{} && !asJSON,
+			JSON:  asJSON,
+		})
+		return result
+	})
+	if result == "" {
+		logger.PrintErrorToStderr(osArgs, fmt.Sprintf(
+			"Failed to compare %q and %q (both files must be metafiles)", paths[0], paths[1]))
+		return 1
+	}
+	return 0
+}
+
 func parseServeOptionsImpl(osArgs []string) (api.ServeOptions, []string, error) {
 	host := ""
 	portText := "0"
@@ -1422,7 +1643,7 @@ func serveImpl(osArgs []string) {
 		logger.PrintErrorWithNoteToStderr(osArgs, errWithNote.Text, errWithNote.Note)
 		return
 	}
-	if analyze != analyzeDisabled {
+	if analyze.mode != analyzeDisabled {
 		addAnalyzePlugin(&options, analyze, osArgs)
 	}
 
@@ -1454,7 +1675,7 @@ func serveImpl(osArgs []string) {
 
 	// Also enable watch mode if it was requested
 	if extras.watch {
-		if err := ctx.Watch(api.WatchOptions{}); err != nil {
+		if err := ctx.Watch(api.WatchOptions{Mode: extras.watchMode}); err != nil {
 			logger.PrintErrorWithNoteToStderr(osArgs, err.Error(), "")
 			return
 		}
@@ -7327,6 +7327,27 @@ tests.push(
     `,
   }, { async: true }),
 
+  // Code splitting with the CommonJS format where the runtime code is in a shared chunk
+  test(['a.js', '--outdir=out', '--splitting', '--format=cjs', '--bundle'], {
+    'a.js': `
+      import * as ns1 from './b'
+      export default async function () {
+        const ns2 = await import('./b')
+        return [ns1.foo, -ns2.foo]
+      }
+    `,
+    'b.js': `
+      export let foo = 123
+    `,
+    'node.js': `
+      exports.async = async () => {
+        const {default: fn} = require('./out/a.js')
+        const [a, b] = await fn()
+        if (a !== 123 || b !== -123) throw 'fail'
+      }
+    `,
+  }, { async: true }),
+
   // Code splitting via CommonJS module double-imported with sync and async imports
   test(['a.js', '--outdir=out', '--splitting', '--format=esm', '--bundle'], {
     'a.js': `
@@ -3896,6 +3896,51 @@ let watchTests = {
     }
   },
 
+  async watchModeInvalid({ esbuild }) {
+    const context = await esbuild.context({})
+    try {
+      await context.watch({ mode: 'bogus' })
+      throw new Error('Expected an error to be thrown')
+    } catch (err) {
+      assert.strictEqual(err.message, 'Invalid watch mode: "bogus"')
+    } finally {
+      await context.dispose()
+    }
+  },
+
+  async watchModePoll({ esbuild, testDir }) {
+    const srcDir = path.join(testDir, 'src')
+    const outfile = path.join(testDir, 'out.js')
+    const input = path.join(srcDir, 'in.js')
+    await mkdirAsync(srcDir, { recursive: true })
+    await writeFileAsync(input, `throw 1`)
+
+    const { rebuildUntil, plugin } = makeRebuildUntilPlugin()
+    const context = await esbuild.context({
+      entryPoints: [input],
+      outfile,
+      format: 'esm',
+      logLevel: 'silent',
+      plugins: [plugin],
+    })
+
+    try {
+      await rebuildUntil(
+        () => context.watch({ mode: 'poll' }),
+        () => true,
+      )
+      assert.strictEqual(await readFileAsync(outfile, 'utf8'), 'throw 1;\n')
+
+      const result = await rebuildUntil(
+        () => writeFileAtomic(input, `throw 2`),
+        () => fs.readFileSync(outfile, 'utf8') === 'throw 2;\n',
+      )
+      assert.strictEqual(result.errors.length, 0)
+    } finally {
+      await context.dispose()
+    }
+  },
+
   async watchEditSession({ esbuild, testDir }) {
     const srcDir = path.join(testDir, 'src')
     const outfile = path.join(testDir, 'out.js')
@@ -6974,6 +7019,21 @@ let analyzeTests = {
    ├ lib.js ──── 50b ─── 50.0%
    │  └ entry.js
    └ entry.js ── 25b ─── 25.0%
+`)
+    assert.strictEqual(await esbuild.analyzeMetafile(metafile, { why: 'lib.js' }), `
+  lib.js is included in 1 output file
+
+  out.js (50b)
+   Rebuild with the "metafileWhy" option to see why this file is included
+`)
+    metafile.outputs['out.js'].inputs['lib.js'].why = { importChain: ['entry.js'], usedExports: ['fn'] }
+    assert.strictEqual(await esbuild.analyzeMetafile(metafile, { why: 'lib.js' }), `
+  lib.js is included in 1 output file
+
+  out.js (50b)
+   entry.js
+    └ lib.js
+   Used exports: fn
 `)
   },
 }